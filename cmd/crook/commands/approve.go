@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/andri/crook/pkg/cli"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/spf13/cobra"
+)
+
+// ApproveOptions holds options for the approve command
+type ApproveOptions struct {
+	// Reject rejects the request instead of approving it
+	Reject bool
+}
+
+// newApproveCmd creates the approve subcommand
+func newApproveCmd() *cobra.Command {
+	opts := &ApproveOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "approve [request-id]",
+		Short: "Approve a pending request to take a node down",
+		Long: `Approve, or with --reject reject, a request recorded by 'crook down <node> --request'.
+Without a request ID, list the recorded requests.
+
+This is the second half of the two-person rule: the 'crook down --request' that
+recorded the request waits until it is approved, then runs the down phase. The
+approver must be a different user than the requester; users are identified by
+the user name the Kubernetes API server authenticates their credentials as, not
+by their local user name. Requests are kept in crook-approval-<id> ConfigMaps in the
+Rook namespace, expire after 24 hours and can be used once.
+
+Set policy.require-approval-before-down to refuse every down phase without an
+approved request.`,
+		Example: `  # List the requests
+  crook approve
+
+  # Approve request 3f9a1c2e
+  crook approve 3f9a1c2e
+
+  # Reject it
+  crook approve 3f9a1c2e --reject`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return runListApprovals(cmd)
+			}
+			return runApprove(cmd, args[0], opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Reject, "reject", false,
+		"reject the request instead of approving it")
+
+	return cmd
+}
+
+// runApprove approves or rejects the request
+func runApprove(cmd *cobra.Command, id string, opts *ApproveOptions) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	request, err := maintenance.DecideApprovalRequest(ctx, client, cfg, id, opts.Reject)
+	if errors.Is(err, maintenance.ErrApprovalNotFound) || errors.Is(err, maintenance.ErrSelfApproval) {
+		return withExitCode(ExitCodeValidation, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	pw := cli.NewProgressWriter(cmd.OutOrStdout())
+	pw.SetQuiet(GlobalOptions.Quiet)
+	decision := "approved"
+	if request.Rejected {
+		decision = "rejected"
+	}
+	pw.PrintSuccess(fmt.Sprintf("Request %s to take down %s (requested by %s) %s", request.ID, request.Node, request.Requester, decision))
+	return nil
+}
+
+// runListApprovals prints the recorded requests, oldest first
+func runListApprovals(cmd *cobra.Command) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	requests, err := maintenance.ListApprovalRequests(ctx, client, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to list approval requests: %w", err)
+	}
+	if len(requests) == 0 {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No approval requests")
+		return nil
+	}
+
+	now := time.Now()
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tNODE\tREQUESTER\tREQUESTED\tSIGN-OFF\tSTATUS\tAPPROVER")
+	for _, request := range requests {
+		signOff := "-"
+		if request.SignOff != nil {
+			signOff = request.SignOff.String()
+		}
+		approver := "-"
+		if request.Approver != "" {
+			approver = request.Approver
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			request.ID, request.Node, request.Requester,
+			request.Requested.Local().Format("2006-01-02 15:04"),
+			signOff, request.Status(now), approver)
+	}
+	return tw.Flush()
+}
+
+// awaitApproval records a request to take the node down and waits up to
+// timeout for a second user to approve it. It returns the request ID.
+func awaitApproval(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	pw *cli.ProgressWriter,
+	nodeName string,
+	signOff maintenance.SignOff,
+	timeout time.Duration,
+) (string, error) {
+	request, err := maintenance.CreateApprovalRequest(ctx, client, cfg, nodeName, signOff)
+	if err != nil {
+		return "", fmt.Errorf("failed to record approval request: %w", err)
+	}
+	pw.PrintWarning(fmt.Sprintf("Approval request %s recorded; waiting for another user to run 'crook approve %s'", request.ID, request.ID))
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	approved, err := maintenance.WaitForApproval(ctx, client, cfg, request.ID, 0)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "", withExitCode(ExitCodeTimeout, err)
+	}
+	if err != nil {
+		return "", withExitCode(ExitCodeValidation, err)
+	}
+	pw.PrintSuccess(fmt.Sprintf("Request %s approved by %s", approved.ID, approved.Approver))
+	return approved.ID, nil
+}
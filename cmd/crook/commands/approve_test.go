@@ -0,0 +1,28 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestApproveCmdExists(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	approveCmd, _, err := cmd.Find([]string{"approve"})
+	if err != nil || approveCmd.Name() != "approve" {
+		t.Fatalf("expected 'approve' subcommand to exist: %v", err)
+	}
+	if approveCmd.Flags().Lookup("reject") == nil {
+		t.Error("expected approve flag \"reject\"")
+	}
+}
+
+func TestApproveCmdRejectsExtraArgs(t *testing.T) {
+	cmd := commands.NewRootCmd()
+	cmd.SetArgs([]string{"approve", "3f9a1c2e", "4b0d2e1f"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/andri/crook/internal/faults"
+	"github.com/spf13/cobra"
+)
+
+// ChaosTestOptions holds options for the chaos-test command
+type ChaosTestOptions struct {
+	// Faults are the fault rules injected while the command runs
+	Faults []string
+}
+
+// newChaosTestCmd creates the hidden chaos-test command used to check how
+// error and rollback states render
+func newChaosTestCmd() *cobra.Command {
+	opts := &ChaosTestOptions{}
+
+	cmd := &cobra.Command{
+		Use:    "chaos-test --fault RULE... -- <command> [args]",
+		Short:  "Run a crook command with injected Kubernetes and ceph faults",
+		Hidden: true,
+		Long: `Run a crook command while the Kubernetes API requests and ceph commands
+selected by --fault fail, to check that error, rollback and re-authentication
+states are handled and rendered correctly.
+
+A rule is <target>=<fault>[*<times>]: target is k8s:<verb>:<resource> or
+ceph:<command prefix>, fault is error, timeout, unauthorized or forbidden, and
+times limits how often the rule fires. The same rules can be set for any
+command with the CROOK_FAULTS environment variable.
+
+Only available in binaries built with -tags faults. Global flags go before --.`,
+		Example: `  # Fail the first scale of a deployment during the down phase
+  crook chaos-test --fault 'k8s:update:deployments/scale=error*1' -- down worker-1
+
+  # Let setting noout time out
+  crook chaos-test --fault 'ceph:osd set noout=timeout' -- down worker-1 --yes`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runChaosTest(cmd, args, opts)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&opts.Faults, "fault", nil,
+		"fault rule to inject (repeatable)")
+
+	return cmd
+}
+
+// runChaosTest activates the fault rules and runs the wrapped command
+func runChaosTest(cmd *cobra.Command, args []string, opts *ChaosTestOptions) error {
+	if len(opts.Faults) == 0 {
+		return withExitCode(ExitCodeValidation, errors.New("at least one --fault rule is required"))
+	}
+	if err := faults.Activate(strings.Join(opts.Faults, ",")); err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+
+	target, targetArgs, err := cmd.Root().Find(args)
+	if err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+	if target == cmd.Root() || target == cmd || target.RunE == nil {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("unknown command %q", strings.Join(args, " ")))
+	}
+
+	target.SetContext(cmd.Context())
+	if err := target.ParseFlags(targetArgs); err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+	targetArgs = target.Flags().Args()
+	if err := target.ValidateArgs(targetArgs); err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+	if target.PreRunE != nil {
+		if err := target.PreRunE(target, targetArgs); err != nil {
+			return err
+		}
+	}
+	return target.RunE(target, targetArgs)
+}
@@ -0,0 +1,53 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+	"github.com/andri/crook/internal/faults"
+)
+
+func TestChaosTestCmdIsHidden(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	chaosCmd, _, err := cmd.Find([]string{"chaos-test"})
+	if err != nil || chaosCmd.Name() != "chaos-test" {
+		t.Fatalf("expected 'chaos-test' subcommand to exist, err = %v", err)
+	}
+	if !chaosCmd.Hidden {
+		t.Error("expected chaos-test command to be hidden")
+	}
+	if chaosCmd.Flags().Lookup("fault") == nil {
+		t.Error("expected --fault flag")
+	}
+}
+
+func TestChaosTestCmdValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no command", args: []string{"chaos-test", "--fault", "k8s:get:nodes=error"}},
+		{name: "no fault", args: []string{"chaos-test", "--", "version"}},
+		{name: "invalid fault", args: []string{"chaos-test", "--fault", "k8s:get=error", "--", "version"}},
+	}
+	if !faults.Enabled {
+		tests = append(tests, struct {
+			name string
+			args []string
+		}{name: "built without faults", args: []string{"chaos-test", "--fault", "k8s:get:nodes=error", "--", "version"}})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := commands.NewRootCmd()
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			if err := cmd.Execute(); err == nil {
+				t.Errorf("expected an error for %v", tt.args)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/cli"
+	crookerrors "github.com/andri/crook/pkg/errors"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// CheckRBACOptions holds options for the check rbac command
+type CheckRBACOptions struct {
+	// Output specifies the output format: table, json
+	Output string
+}
+
+// CheckVolumesOptions holds options for the check volumes command
+type CheckVolumesOptions struct {
+	// Output specifies the output format: table, json
+	Output string
+}
+
+// newCheckCmd creates the check command grouping the standalone checks
+func newCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Verify the cluster is ready for crook before a maintenance window",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newCheckRBACCmd())
+	cmd.AddCommand(newCheckVolumesCmd())
+	return cmd
+}
+
+// newCheckRBACCmd creates the check rbac subcommand
+func newCheckRBACCmd() *cobra.Command {
+	opts := &CheckRBACOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "rbac",
+		Short: "Report every permission crook needs and whether it is granted",
+		Long: `Run a SelfSubjectAccessReview for every permission crook may need and print
+an allowed/denied matrix per verb, resource and namespace, so a role binding can
+be verified before a maintenance window.
+
+REQUIRED marks the permissions 'crook down' and 'crook up' need with the current
+configuration (e.g. the drain stage or StatefulSets add their own); the others
+serve optional commands such as 'crook logcat' and 'crook run-in-cluster'.
+The command exits with code 2 when a required permission is denied.`,
+		Example: `  # Permission matrix for the current kubeconfig context
+  crook check rbac
+
+  # JSON output for automation
+  crook check rbac -o json
+
+  # Check the service account's permissions from a pod
+  crook check rbac --in-cluster`,
+		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if _, err := output.ParseFormat(opts.Output); err != nil {
+				return withExitCode(ExitCodeValidation, err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runCheckRBAC(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "table",
+		"output format: table, json")
+
+	return cmd
+}
+
+// runCheckRBAC prints the permission report and fails when a required
+// permission is denied
+func runCheckRBAC(cmd *cobra.Command, opts *CheckRBACOptions) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+	format, err := output.ParseFormat(opts.Output)
+	if err != nil {
+		return err
+	}
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	report := maintenance.CheckPermissions(ctx, client, cfg)
+	if err := output.RenderPermissionReport(cmd.OutOrStdout(), report, format); err != nil {
+		return fmt.Errorf("failed to render output: %w", err)
+	}
+
+	missing := report.MissingRequired()
+	if len(missing) == 0 {
+		return nil
+	}
+	names := make([]string, len(missing))
+	for i, p := range missing {
+		names[i] = p.String()
+	}
+	return withExitCode(ExitCodeValidation, crookerrors.Wrap(crookerrors.KindRBACDenied,
+		fmt.Errorf("missing required permissions: %s", strings.Join(names, ", ")),
+		"ask a cluster admin to bind a role granting the permissions marked denied"))
+}
+
+// newCheckVolumesCmd creates the check volumes subcommand
+func newCheckVolumesCmd() *cobra.Command {
+	opts := &CheckVolumesOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "volumes <node>",
+		Short: "List the volumes attached or pinned to a node and flag stuck attachments",
+		Long: `List the VolumeAttachments to a node and the PersistentVolumes whose node
+affinity pins them to it, with the claim, storage class, CSI driver and whether
+a pod on the node uses each.
+
+An attachment looks stuck when its driver reported an attach or detach error,
+its detach never completed, or it is attached while no pod on the node uses it.
+Stale rbd mappings after a reboot show up this way: pods that need the volume
+on another node then fail to attach it. The command exits with code 2 when an
+attachment looks stuck.
+
+'crook down' runs the same check before the node is shut down and 'crook up'
+after it is back, and warn about what they find.`,
+		Example: `  # Volumes of node 'worker-1'
+  crook check volumes worker-1
+
+  # JSON output for automation
+  crook check volumes worker-1 -o json`,
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if _, err := output.ParseFormat(opts.Output); err != nil {
+				return withExitCode(ExitCodeValidation, err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCheckVolumes(cmd, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "table",
+		"output format: table, json")
+
+	return cmd
+}
+
+// runCheckVolumes prints the volumes tied to the node and fails when an
+// attachment looks stuck
+func runCheckVolumes(cmd *cobra.Command, nodeName string, opts *CheckVolumesOptions) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+	format, err := output.ParseFormat(opts.Output)
+	if err != nil {
+		return err
+	}
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	nodeName, err = resolveNodeName(ctx, cmd, client, nodeName, pickNodeNameIf(format == output.FormatTable))
+	if err != nil {
+		return err
+	}
+
+	volumes, err := maintenance.CheckNodeVolumes(ctx, client, nodeName, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list volumes of node %s: %w", nodeName, err)
+	}
+	if err := output.RenderNodeVolumes(cmd.OutOrStdout(), volumes, format); err != nil {
+		return fmt.Errorf("failed to render output: %w", err)
+	}
+
+	if stuck := volumes.Stuck(); len(stuck) > 0 {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("%d volume attachment(s) on %s look stuck", len(stuck), nodeName))
+	}
+	return nil
+}
+
+// printVolumeWarnings warns about the node's volumes at the end of a phase:
+// still attached before shutdown, stuck after recovery. The check is
+// best-effort and never fails the phase.
+func printVolumeWarnings(ctx context.Context, client *k8s.Client, pw *cli.ProgressWriter, nodeName, phase string) {
+	volumes, err := maintenance.CheckNodeVolumes(ctx, client, nodeName, maintenance.VolumeSettleTimeout)
+	if err != nil {
+		logger.Warn("failed to check the node's volumes", "node", nodeName, "error", err)
+		return
+	}
+	for _, warning := range volumes.Warnings(phase) {
+		pw.PrintWarning(warning)
+	}
+}
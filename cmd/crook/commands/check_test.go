@@ -0,0 +1,43 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestCheckRBACCmd(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	rbacCmd, _, err := cmd.Find([]string{"check", "rbac"})
+	if err != nil || rbacCmd.Name() != "rbac" {
+		t.Fatalf("expected 'check rbac' subcommand to exist: %v", err)
+	}
+	if rbacCmd.Flags().Lookup("output") == nil {
+		t.Error("expected check rbac flag \"output\"")
+	}
+
+	cmd = commands.NewRootCmd()
+	cmd.SetArgs([]string{"check", "rbac", "--output", "yaml"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an invalid output format to fail")
+	}
+}
+
+func TestCheckVolumesCmd(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	volumesCmd, _, err := cmd.Find([]string{"check", "volumes"})
+	if err != nil || volumesCmd.Name() != "volumes" {
+		t.Fatalf("expected 'check volumes' subcommand to exist: %v", err)
+	}
+	if volumesCmd.Flags().Lookup("output") == nil {
+		t.Error("expected check volumes flag \"output\"")
+	}
+
+	cmd = commands.NewRootCmd()
+	cmd.SetArgs([]string{"check", "volumes"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected a missing node to fail")
+	}
+}
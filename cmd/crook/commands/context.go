@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/tui/models"
+	"github.com/andri/crook/pkg/tui/styles"
+	"golang.org/x/term"
+)
+
+// runContextPicker shows the picker and returns the chosen context, "" when
+// the user quit
+var runContextPicker = func(cfg models.ContextPickerConfig) (string, error) {
+	model := models.NewContextPickerModel(cfg)
+	var opts []tea.ProgramOption
+	if profile, forced := styles.ColorProfile(); forced {
+		opts = append(opts, tea.WithColorProfile(profile))
+	}
+	if _, err := tea.NewProgram(model, opts...).Run(); err != nil {
+		return "", fmt.Errorf("context picker error: %w", err)
+	}
+	return model.Chosen(), nil
+}
+
+// interactiveTerminal reports whether stdin and stdout are a terminal
+var interactiveTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// pickKubeContext asks which kubeconfig context the TUI connects to when
+// --context is not set and the kubeconfig has several, preselecting the one
+// picked last time in the working directory. Launching against the wrong
+// cluster is the costliest mistake crook can make.
+func pickKubeContext() error {
+	if GlobalOptions.KubeContext != "" || !interactiveTerminal() ||
+		k8s.UsesInClusterConfig(k8s.ClientConfig{InCluster: GlobalOptions.InCluster}) {
+		return nil
+	}
+
+	contexts, current, err := k8s.KubeconfigContexts()
+	if err != nil || len(contexts) < 2 {
+		// A broken kubeconfig is reported when the client is created
+		return nil
+	}
+
+	dir, _ := os.Getwd()
+	last := k8s.LastContext(dir)
+	if !slices.Contains(contexts, last) {
+		last = ""
+	}
+
+	chosen, err := runContextPicker(models.ContextPickerConfig{Contexts: contexts, Current: current, Last: last})
+	if err != nil {
+		return err
+	}
+	if chosen == "" {
+		return withExitCode(ExitCodeDeclined, nil)
+	}
+
+	if dir != "" {
+		if err := k8s.RememberContext(dir, chosen); err != nil {
+			logger.Warn("failed to remember kubeconfig context", "error", err)
+		}
+	}
+	GlobalOptions.KubeContext = chosen
+	return nil
+}
+
+// kubeContextName returns the kubeconfig context the client connects to, or
+// "" with in-cluster credentials or an unreadable kubeconfig
+func kubeContextName() string {
+	if GlobalOptions.KubeContext != "" {
+		return GlobalOptions.KubeContext
+	}
+	if k8s.UsesInClusterConfig(k8s.ClientConfig{InCluster: GlobalOptions.InCluster}) {
+		return ""
+	}
+	_, current, err := k8s.KubeconfigContexts()
+	if err != nil {
+		return ""
+	}
+	return current
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/andri/crook/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// DeploymentsOptions holds options for the deployments command
+type DeploymentsOptions struct {
+	// Node is the node whose pinned deployments are listed
+	Node string
+
+	// Output specifies the output format: table, json
+	Output string
+}
+
+// newDeploymentsCmd creates the deployments subcommand
+func newDeploymentsCmd() *cobra.Command {
+	opts := &DeploymentsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "deployments --node <node>",
+		Short: "List the Rook deployments pinned to a node",
+		Long: `List the Rook deployments pinned to a node: the same set, in the same order,
+that 'crook down' would scale down.
+
+Discovery matches the kubernetes.io/hostname key of a deployment's
+nodeSelector or, failing that, its required nodeAffinity. The PINNED BY
+column shows which one matched, and ACTION shows whether the down phase would
+scale the deployment down, skip it because it is already at 0 replicas, or
+leave it running because discovery.exclude matches it.
+
+Use this to sanity-check discovery on clusters with unusual placement before
+running maintenance. Nothing is modified.`,
+		Example: `  # List the deployments crook down would act on
+  crook deployments --node worker-1
+
+  # JSON output for automation
+  crook deployments --node worker-1 -o json`,
+		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateDeploymentsOptions(opts)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDeployments(cmd, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Node, "node", "",
+		"node whose pinned deployments are listed (required)")
+	flags.StringVarP(&opts.Output, "output", "o", "table",
+		"output format: table, json")
+
+	return cmd
+}
+
+// validateDeploymentsOptions validates the deployments command options
+func validateDeploymentsOptions(opts *DeploymentsOptions) error {
+	if opts.Node == "" {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("--node is required"))
+	}
+	if _, err := output.ParseFormat(opts.Output); err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+	return nil
+}
+
+// runDeployments prints the deployments pinned to the node in down phase order
+func runDeployments(cmd *cobra.Command, opts *DeploymentsOptions) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+	format, err := output.ParseFormat(opts.Output)
+	if err != nil {
+		return err
+	}
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	opts.Node, err = resolveNodeName(ctx, cmd, client, opts.Node, nodeNameList)
+	if err != nil {
+		return err
+	}
+
+	list, err := output.FetchPinnedDeployments(ctx, client, cfg, opts.Node)
+	if err != nil {
+		return err
+	}
+
+	if renderErr := output.RenderPinnedDeployments(cmd.OutOrStdout(), list, format); renderErr != nil {
+		return fmt.Errorf("failed to render output: %w", renderErr)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestDeploymentsCmdExists(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	deploymentsCmd, _, err := cmd.Find([]string{"deployments"})
+	if err != nil || deploymentsCmd.Name() != "deployments" {
+		t.Fatalf("expected 'deployments' subcommand to exist: %v", err)
+	}
+	for _, flag := range []string{"node", "output"} {
+		if deploymentsCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected deployments flag %q", flag)
+		}
+	}
+}
+
+func TestDeploymentsCmdValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing node", args: []string{"deployments"}},
+		{name: "unexpected argument", args: []string{"deployments", "--node", "worker-1", "extra"}},
+		{name: "invalid output", args: []string{"deployments", "--node", "worker-1", "--output", "yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := commands.NewRootCmd()
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
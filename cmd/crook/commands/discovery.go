@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/spf13/pflag"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// addPrefixFlag registers the repeatable --prefix flag
+func addPrefixFlag(flags *pflag.FlagSet, prefixes *[]string, usage string) {
+	flags.StringArrayVar(prefixes, "prefix", nil, usage+" (repeatable)")
+}
+
+// validatePrefixes rejects blank --prefix values, which would match every deployment
+func validatePrefixes(prefixes []string) error {
+	for _, prefix := range prefixes {
+		if strings.TrimSpace(prefix) == "" {
+			return withExitCode(ExitCodeValidation, fmt.Errorf("--prefix must not be empty"))
+		}
+	}
+	return nil
+}
+
+// addExcludeFlag registers the repeatable --exclude flag, which extends discovery.exclude
+func addExcludeFlag(flags *pflag.FlagSet, exclude *[]string) {
+	flags.StringArrayVar(exclude, "exclude", nil,
+		"never scale this deployment, given as an exact name or a regex matching the whole name; extends discovery.exclude (repeatable)")
+}
+
+// validateExcludes rejects --exclude values that are blank or invalid regexes
+func validateExcludes(exclude []string) error {
+	for _, pattern := range exclude {
+		if err := config.ValidateExcludePattern(pattern); err != nil {
+			return withExitCode(ExitCodeValidation, fmt.Errorf("invalid --exclude: %w", err))
+		}
+	}
+	return nil
+}
+
+// deploymentNames formats deployments as namespace/name for display
+func deploymentNames(deployments []appsv1.Deployment) []string {
+	names := make([]string, 0, len(deployments))
+	for _, d := range deployments {
+		names = append(names, fmt.Sprintf("%s/%s", d.Namespace, d.Name))
+	}
+	return names
+}
+
+// downPlanNames formats workloads as namespace/name for the down plan,
+// marking those already at 0 replicas that a re-run leaves alone
+func downPlanNames(workloads []k8s.Workload) []string {
+	names := make([]string, 0, len(workloads))
+	for _, w := range workloads {
+		name := w.String()
+		if w.Replicas == 0 {
+			name += " (already down)"
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// statefulSetNames formats StatefulSets as namespace/name for display
+func statefulSetNames(statefulSets []appsv1.StatefulSet) []string {
+	names := make([]string, 0, len(statefulSets))
+	for _, s := range statefulSets {
+		names = append(names, fmt.Sprintf("%s/%s", s.Namespace, s.Name))
+	}
+	return names
+}
+
+// excludedNames formats excluded deployments and StatefulSets for display,
+// marking the StatefulSets by kind
+func excludedNames(deployments []appsv1.Deployment, statefulSets []appsv1.StatefulSet) []string {
+	names := deploymentNames(deployments)
+	for _, name := range statefulSetNames(statefulSets) {
+		names = append(names, name+" (statefulset)")
+	}
+	return names
+}
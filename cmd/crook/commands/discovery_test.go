@@ -0,0 +1,47 @@
+package commands_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestPrefixFlagRejectsEmpty(t *testing.T) {
+	tests := [][]string{
+		{"down", "worker-1", "--prefix", ""},
+		{"up", "worker-1", "--prefix", "rook-ceph-osd", "--prefix", " "},
+		{"ls", "--prefix", ""},
+	}
+
+	for _, args := range tests {
+		t.Run(args[0], func(t *testing.T) {
+			cmd := commands.NewRootCmd()
+			cmd.SetArgs(args)
+
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), "--prefix must not be empty") {
+				t.Errorf("expected empty prefix error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestExcludeFlagValidation(t *testing.T) {
+	tests := [][]string{
+		{"down", "worker-1", "--exclude", ""},
+		{"up", "worker-1", "--exclude", "custom-(exporter"},
+	}
+
+	for _, args := range tests {
+		t.Run(args[0], func(t *testing.T) {
+			cmd := commands.NewRootCmd()
+			cmd.SetArgs(args)
+
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), "invalid --exclude") {
+				t.Errorf("expected invalid exclude error, got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// DocsOptions holds options for the docs generate command
+type DocsOptions struct {
+	// Dir is the output directory for generated files
+	Dir string
+
+	// Format selects what to generate: man, markdown, or all
+	Format string
+}
+
+// newDocsCmd creates the hidden docs command used by packaging
+func newDocsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "docs",
+		Short:  "Generate CLI documentation",
+		Hidden: true,
+	}
+
+	cmd.AddCommand(newDocsGenerateCmd())
+
+	return cmd
+}
+
+// newDocsGenerateCmd creates the docs generate subcommand
+func newDocsGenerateCmd() *cobra.Command {
+	opts := &DocsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate man pages and markdown reference from the command tree",
+		Example: `  # Generate man pages and markdown into ./docs/cli
+  crook docs generate --dir docs/cli
+
+  # Generate only man pages
+  crook docs generate --dir man --format man`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDocsGenerate(cmd, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Dir, "dir", "docs/cli",
+		"output directory for generated documentation")
+	flags.StringVar(&opts.Format, "format", "all",
+		"documentation format: man, markdown, all")
+
+	return cmd
+}
+
+// runDocsGenerate writes documentation for the full command tree
+func runDocsGenerate(cmd *cobra.Command, opts *DocsOptions) error {
+	format := strings.ToLower(opts.Format)
+	if format != "man" && format != "markdown" && format != "all" {
+		return withExitCode(ExitCodeValidation,
+			fmt.Errorf("invalid format %q: must be one of man, markdown, all", opts.Format))
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", opts.Dir, err)
+	}
+
+	root := cmd.Root()
+	// Omit the generation timestamp so output is reproducible across builds
+	root.DisableAutoGenTag = true
+
+	if format == "man" || format == "all" {
+		header := &doc.GenManHeader{
+			Title:   "CROOK",
+			Section: "1",
+			Source:  "crook " + version,
+			Manual:  "crook manual",
+		}
+		if err := doc.GenManTree(root, header, opts.Dir); err != nil {
+			return fmt.Errorf("failed to generate man pages: %w", err)
+		}
+	}
+
+	if format == "markdown" || format == "all" {
+		if err := doc.GenMarkdownTree(root, opts.Dir); err != nil {
+			return fmt.Errorf("failed to generate markdown reference: %w", err)
+		}
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Documentation written to %s\n", opts.Dir)
+	return nil
+}
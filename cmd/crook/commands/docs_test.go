@@ -0,0 +1,88 @@
+package commands_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestDocsCmdIsHidden(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	for _, subCmd := range cmd.Commands() {
+		if subCmd.Name() == "docs" {
+			if !subCmd.Hidden {
+				t.Error("expected docs command to be hidden")
+			}
+			return
+		}
+	}
+	t.Fatal("expected 'docs' subcommand to exist")
+}
+
+func TestDocsGenerate(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		wantFiles []string
+		noFiles   []string
+	}{
+		{
+			name:      "all formats",
+			format:    "all",
+			wantFiles: []string{"crook.1", "crook-down.1", "crook.md", "crook_down.md", "crook_ls.md"},
+		},
+		{
+			name:      "man only",
+			format:    "man",
+			wantFiles: []string{"crook.1", "crook-up.1"},
+			noFiles:   []string{"crook.md"},
+		},
+		{
+			name:      "markdown only",
+			format:    "markdown",
+			wantFiles: []string{"crook.md", "crook_up.md"},
+			noFiles:   []string{"crook.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cmd := commands.NewRootCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetArgs([]string{"docs", "generate", "--dir", dir, "--format", tt.format})
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("docs generate failed: %v", err)
+			}
+
+			for _, name := range tt.wantFiles {
+				if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+					t.Errorf("expected %s to be generated: %v", name, err)
+				}
+			}
+			for _, name := range tt.noFiles {
+				if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+					t.Errorf("expected %s not to be generated", name)
+				}
+			}
+		})
+	}
+}
+
+func TestDocsGenerateInvalidFormat(t *testing.T) {
+	cmd := commands.NewRootCmd()
+	cmd.SetArgs([]string{"docs", "generate", "--dir", t.TempDir(), "--format", "pdf"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for invalid format")
+	}
+	if got := commands.ExitCode(err); got != commands.ExitCodeValidation {
+		t.Errorf("ExitCode() = %d, want %d", got, commands.ExitCodeValidation)
+	}
+}
@@ -3,11 +3,15 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/internal/telemetry"
 	"github.com/andri/crook/pkg/cli"
+	crookerrors "github.com/andri/crook/pkg/errors"
 	"github.com/andri/crook/pkg/k8s"
 	"github.com/andri/crook/pkg/maintenance"
 	"github.com/spf13/cobra"
@@ -20,6 +24,63 @@ type DownOptions struct {
 
 	// Yes skips the confirmation prompt
 	Yes bool
+
+	// ReportDir receives Markdown and HTML reports of the run when set
+	ReportDir string
+
+	// Headless holds --summary-path, --wait and --wait-health-ok
+	Headless HeadlessOptions
+
+	// Drain evicts the node's remaining pods after the deployments are scaled down
+	Drain bool
+
+	// Prefixes limits the run to node-pinned deployments whose names start
+	// with one of these prefixes
+	Prefixes []string
+
+	// Exclude extends discovery.exclude with deployments never to scale
+	Exclude []string
+
+	// SignOff links the run to its change request (--reason, --ticket)
+	SignOff maintenance.SignOff
+
+	// Request records an approval request and waits for a second user to
+	// approve it before running the down phase (two-person rule)
+	Request bool
+
+	// ApprovalTimeout bounds the wait for the approval of --request
+	ApprovalTimeout time.Duration
+
+	// AcknowledgeWarnings proceeds past pre-flight checks that only warn
+	// (e.g. HEALTH_WARN, another node in maintenance)
+	AcknowledgeWarnings bool
+
+	// AllowControlPlane permits taking down a control-plane node
+	AllowControlPlane bool
+
+	// DeadNode runs the emergency variant for a node that is NotReady
+	DeadNode bool
+
+	// ForceDeletePods force-deletes the pods stuck in Terminating on a dead node
+	ForceDeletePods bool
+
+	// DisableScaleDown annotates the node so the cluster autoscaler does not
+	// remove it until the up phase
+	DisableScaleDown bool
+
+	// KuredPre is set by 'crook hook kured-pre', where kured is about to
+	// reboot the node
+	KuredPre bool
+
+	// Reboot reboots the node through hooks.reboot once the down phase
+	// completed, waits for it and offers to run the up phase
+	Reboot bool
+
+	// NoOutScope overrides ceph.noout-scope: cluster, host or osd
+	NoOutScope string
+
+	// NoOutWatchdogTTL enables the noout watchdog with this TTL
+	NoOutWatchdogTTL time.Duration
 }
 
 // newDownCmd creates the down subcommand
@@ -34,13 +95,62 @@ func newDownCmd() *cobra.Command {
 This command performs the following steps:
   1. Validates pre-flight conditions (node exists, Ceph healthy, etc.)
   2. Cordons the node (marks it unschedulable)
-  3. Sets the Ceph 'noout' flag to prevent data rebalancing
+  3. Sets the Ceph 'noout' flag to prevent data rebalancing: cluster-wide, or
+     with --noout-scope host|osd (ceph.noout-scope) only on the node's CRUSH
+     host or OSDs, so other nodes' failed OSDs are still marked out
   4. Scales down the rook-ceph-operator
-  5. Discovers and scales down node-pinned Rook-Ceph deployments
+  5. Discovers and scales down node-pinned Rook-Ceph deployments (limited to
+     names starting with a --prefix, when given), then node-pinned
+     StatefulSets when discovery.statefulsets is set. Workloads matching
+     discovery.exclude or --exclude are never scaled
+  6. Optionally drains the node: evicts the remaining pods through the
+     Eviction API, honoring PodDisruptionBudgets (--drain or drain.enabled)
 
 After running this command, the node is safe for maintenance operations
 like reboots, hardware changes, or OS upgrades.
 
+--reason and --ticket link the maintenance to its change request: they are
+recorded in crook.io/reason and crook.io/ticket annotations on the node, in the
+maintenance report, and in the --report-dir reports.
+
+--request enforces the two-person rule: after confirmation crook records an
+approval request and waits until another user runs 'crook approve <id>'; --timeout
+starts once the request is approved. With policy.require-approval-before-down set,
+the down phase refuses to run without an approved request.
+
+Control-plane nodes (labelled node-role.kubernetes.io/control-plane or master)
+are refused unless --allow-control-plane is passed, and then need a confirmation
+of their own unless -y is given; the TUI and 'crook serve' always refuse them.
+
+Nodes of managed node groups (EKS, GKE and AKS labels) get a warning, since the
+cluster autoscaler or the provider may remove or replace a cordoned node.
+--disable-scale-down (or cloud.disable-scale-down) annotates the node with
+cluster-autoscaler.kubernetes.io/scale-down-disabled until the up phase.
+
+--dead-node is the emergency handling of a node that is already NotReady or
+unreachable: node-local pre-flight checks are skipped, scaled-down pods are not
+waited for and the node is not drained, since its kubelet can no longer confirm
+that pods stopped. --force-delete-pods then deletes the pods stuck in
+Terminating on the node with a zero grace period so they are replaced
+elsewhere, like 'crook force-delete-pods' (drain.force-delete-on-dead-node
+enables it by default). The run is labelled as emergency handling in the output and the
+maintenance report, and nodes that are Ready are refused.
+
+--reboot turns crook into a reboot orchestrator: once the down phase completed,
+the node is rebooted through hooks.reboot (a command such as SSH, an HTTP
+endpoint or the node's Metal3 BareMetalHost), crook waits until it is back
+Ready within hooks.reboot.ready-timeout-seconds and then offers to run the up
+phase, which -y runs without asking.
+
+--noout-watchdog-ttl (or ceph.noout-watchdog) is a safety timer for the noout
+flag: a Job running ceph.noout-watchdog.image clears the flag once the TTL
+expires, unless 'crook up' runs first and deletes it. It keeps a crashed or
+forgotten maintenance from leaving Ceph unable to mark failed OSDs out.
+
+For pipelines, --wait blocks until the node is verified down within --timeout,
+and --summary-path writes a JSON summary of the run (outcome, exit code, stage
+durations, scaled deployments, final Ceph health).
+
 Use 'crook up <node>' to restore the node after maintenance is complete.`,
 		Example: `  # Prepare node 'worker-1' for maintenance
   crook down worker-1
@@ -49,8 +159,53 @@ Use 'crook up <node>' to restore the node after maintenance is complete.`,
   crook down -y worker-1
 
   # Set a timeout for the operation
-  crook down worker-1 --timeout 10m`,
+  crook down worker-1 --timeout 10m
+
+  # Also evict the remaining pods, like 'kubectl drain'
+  crook down worker-1 --drain
+
+  # Only scale down the node's OSDs and MONs
+  crook down worker-1 --prefix rook-ceph-osd --prefix rook-ceph-mon
+
+  # Keep a pinned toolbox and custom exporters running
+  crook down worker-1 --exclude rook-ceph-tools --exclude "custom-exporter-.*"
+
+  # Link the maintenance to its change request
+  crook down worker-1 --ticket CHG-1234 --reason "replace failed disk"
+
+  # Wait for a second user to approve before taking the node down
+  crook down worker-1 --request --ticket CHG-1234
+
+  # In a pipeline: block until the node is verified down, keep a summary
+  crook down -y worker-1 --wait --summary-path down.json
+
+  # Take the node down, reboot it and bring it back up in one command
+  crook down worker-1 --reboot
+
+  # A node died: take it out of service and release its stuck pods
+  crook down worker-1 --dead-node --force-delete-pods
+
+  # Only keep the node's own OSDs from being marked out
+  crook down worker-1 --noout-scope host
+
+  # Clear noout after 4 hours should 'crook up' never run
+  crook down worker-1 --noout-watchdog-ttl 4h`,
 		Args: cobra.ExactArgs(1),
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if err := validatePrefixes(opts.Prefixes); err != nil {
+				return err
+			}
+			if opts.ForceDeletePods && !opts.DeadNode {
+				return withExitCode(ExitCodeValidation, errors.New("--force-delete-pods requires --dead-node"))
+			}
+			if err := validateNoOutScope(opts.NoOutScope); err != nil {
+				return err
+			}
+			if opts.NoOutWatchdogTTL < 0 || (opts.NoOutWatchdogTTL > 0 && opts.NoOutWatchdogTTL < time.Second) {
+				return withExitCode(ExitCodeValidation, fmt.Errorf("invalid --noout-watchdog-ttl %s: must be at least 1s", opts.NoOutWatchdogTTL))
+			}
+			return validateExcludes(opts.Exclude)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			nodeName := args[0]
 			return runDown(cmd, nodeName, opts)
@@ -63,14 +218,109 @@ Use 'crook up <node>' to restore the node after maintenance is complete.`,
 		"timeout for the overall operation")
 	flags.BoolVarP(&opts.Yes, "yes", "y", false,
 		"skip confirmation prompt")
+	flags.StringVar(&opts.ReportDir, "report-dir", "",
+		"write Markdown and HTML reports of the run to this directory")
+	flags.BoolVar(&opts.Drain, "drain", false,
+		"evict the remaining pods after scaling down, honoring PodDisruptionBudgets (same as drain.enabled)")
+	addPrefixFlag(flags, &opts.Prefixes, "only scale down node-pinned deployments whose names start with this prefix")
+	addExcludeFlag(flags, &opts.Exclude)
+	addSignOffFlags(flags, &opts.SignOff)
+	addHeadlessFlags(flags, &opts.Headless)
+	flags.BoolVar(&opts.Request, "request", false,
+		"record an approval request and wait for another user to run 'crook approve <id>' before proceeding")
+	flags.DurationVar(&opts.ApprovalTimeout, "approval-timeout", time.Hour,
+		"how long --request waits for the approval")
+	flags.BoolVar(&opts.AcknowledgeWarnings, "acknowledge-warnings", false,
+		"proceed past pre-flight warnings such as HEALTH_WARN or another node in maintenance")
+	flags.BoolVar(&opts.AllowControlPlane, "allow-control-plane", false,
+		"permit taking down a control-plane node (asks for an extra confirmation unless -y)")
+	flags.BoolVar(&opts.DeadNode, "dead-node", false,
+		"emergency handling of a NotReady node: skip node-local checks and do not wait for its pods")
+	flags.BoolVar(&opts.ForceDeletePods, "force-delete-pods", false,
+		"with --dead-node, force-delete the pods stuck in Terminating on the node")
+	flags.BoolVar(&opts.DisableScaleDown, "disable-scale-down", false,
+		"annotate the node so the cluster autoscaler does not remove it until 'crook up' (same as cloud.disable-scale-down)")
+	flags.BoolVar(&opts.Reboot, "reboot", false,
+		"after the down phase, reboot the node through hooks.reboot, wait for it to come back Ready and offer to run the up phase")
+	addNoOutScopeFlag(flags, &opts.NoOutScope,
+		"set noout cluster-wide (cluster), or only on the node's CRUSH host (host) or OSDs (osd); overrides ceph.noout-scope")
+	flags.DurationVar(&opts.NoOutWatchdogTTL, "noout-watchdog-ttl", 0,
+		"create a Job that clears the noout flag after this long unless 'crook up' runs first (enables ceph.noout-watchdog)")
 
 	return cmd
 }
 
+// confirmControlPlane asks for a confirmation of its own before a
+// control-plane node is taken down; -y skips it along with the regular prompt
+func confirmControlPlane(cmd *cobra.Command, yes bool, nodeName string) error {
+	pw := cli.NewProgressWriter(cmd.OutOrStdout())
+	pw.PrintWarning(fmt.Sprintf("%s is a control-plane node: cordoning it affects the whole cluster, not only its storage", nodeName))
+	if yes {
+		return nil
+	}
+	confirmed, err := cli.Confirm(cli.ConfirmOptions{
+		Question: fmt.Sprintf("Really take down control-plane node %s?", nodeName),
+		Input:    cmd.InOrStdin(),
+		Output:   cmd.OutOrStdout(),
+	})
+	if err != nil {
+		return fmt.Errorf("confirmation failed: %w", err)
+	}
+	if !confirmed {
+		return withExitCode(ExitCodeDeclined, fmt.Errorf("operation cancelled by user"))
+	}
+	return nil
+}
+
+// acknowledgeWarnings handles a phase stopped by pre-flight warnings: an
+// interactive run shows them and reruns the phase once the user accepts
+// them, a run with -y fails with a hint to pass --acknowledge-warnings
+func acknowledgeWarnings(cmd *cobra.Command, yes bool, err error, rerun func() error) error {
+	if !yes {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), err.Error())
+		confirmed, confirmErr := cli.Confirm(cli.ConfirmOptions{
+			Question: "Proceed despite the warnings?",
+			Input:    cmd.InOrStdin(),
+			Output:   cmd.OutOrStdout(),
+		})
+		if confirmErr != nil {
+			return fmt.Errorf("confirmation failed: %w", confirmErr)
+		}
+		if confirmed {
+			return rerun()
+		}
+	}
+	return crookerrors.Wrap(crookerrors.KindUnacknowledged, err,
+		"review the warnings, then rerun with --acknowledge-warnings to proceed anyway")
+}
+
 // runDown executes the down phase workflow
 func runDown(cmd *cobra.Command, nodeName string, opts *DownOptions) error {
 	cfg := GlobalOptions.Config
 	ctx := cmd.Context()
+	if opts.Drain {
+		cfg.Drain.Enabled = true
+	}
+	if opts.DisableScaleDown {
+		cfg.Cloud.DisableScaleDown = true
+	}
+	if opts.NoOutScope != "" {
+		cfg.Ceph.NoOutScope = opts.NoOutScope
+	}
+	if opts.NoOutWatchdogTTL > 0 {
+		cfg.Ceph.NoOutWatchdog.Enabled = true
+		cfg.Ceph.NoOutWatchdog.TTLSeconds = int(opts.NoOutWatchdogTTL.Round(time.Second).Seconds())
+	}
+	if cfg.Ceph.NoOutWatchdog.Enabled && cfg.Ceph.NoOutWatchdog.Image == "" {
+		return withExitCode(ExitCodeValidation, errors.New("the noout watchdog needs ceph.noout-watchdog.image, the crook image its Job runs"))
+	}
+	cfg.Discovery.Exclude = append(slices.Clone(cfg.Discovery.Exclude), opts.Exclude...)
+	if cfg.Policy.RequireApprovalBeforeDown && !opts.Request {
+		return withExitCode(ExitCodeValidation, maintenance.ErrApprovalRequired)
+	}
+	if opts.Reboot && !cfg.Hooks.Reboot.Configured() {
+		return withExitCode(ExitCodeValidation, maintenance.ErrNoRebootHook)
+	}
 
 	// Apply timeout to context
 	if opts.Timeout > 0 {
@@ -81,33 +331,70 @@ func runDown(cmd *cobra.Command, nodeName string, opts *DownOptions) error {
 
 	// Initialize Kubernetes client
 	logger.Info("connecting to kubernetes cluster")
-	client, err := newK8sClient(ctx, k8s.ClientConfig{
-		CephCommandTimeout: time.Duration(cfg.Timeouts.CephCommandTimeoutSeconds) * time.Second,
-	})
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
 	if err != nil {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
-	// Validate node exists
-	exists, err := client.NodeExists(ctx, nodeName)
+	// Resolve partial node names, confirming fuzzy matches and asking which
+	// node is meant when ambiguous
+	nodeName, err = resolveNodeName(ctx, cmd, client, nodeName, actOnNodeName(opts.Yes))
 	if err != nil {
-		return fmt.Errorf("failed to check if node %q exists: %w", nodeName, err)
+		return err
 	}
-	if !exists {
-		return fmt.Errorf("node %q not found in cluster", nodeName)
+
+	// Control-plane nodes are refused unless forced, and confirmed separately
+	if controlPlaneErr := maintenance.RefuseControlPlane(ctx, client, nodeName); controlPlaneErr != nil {
+		if !opts.AllowControlPlane {
+			return withExitCode(ExitCodeValidation, controlPlaneErr)
+		}
+		if err := confirmControlPlane(cmd, opts.Yes, nodeName); err != nil {
+			return err
+		}
 	}
 
 	// Discover deployments to show summary
-	deployments, err := client.ListNodePinnedDeployments(ctx, cfg.Namespace, nodeName)
+	discovered, err := client.ListNodePinnedDeployments(ctx, cfg.Namespace, nodeName)
 	if err != nil {
 		return fmt.Errorf("failed to discover deployments: %w", err)
 	}
+	deployments, excluded := maintenance.ExcludeDeployments(
+		maintenance.FilterByPrefixes(discovered, opts.Prefixes), cfg.Discovery)
+	statefulSets, excludedSets, err := maintenance.DiscoverStatefulSets(ctx, client, cfg, nodeName, opts.Prefixes)
+	if err != nil {
+		return err
+	}
 
 	pw := cli.NewProgressWriter(cmd.OutOrStdout())
+	pw.SetQuiet(GlobalOptions.Quiet)
+
+	// A dead node needs the emergency handling, and it is labelled as such
+	switch dead := maintenance.IsNodeDead(ctx, client, nodeName); {
+	case opts.DeadNode:
+		pw.PrintWarning(maintenance.DeadNodeNote)
+		if opts.ForceDeletePods || cfg.Drain.ForceDeleteOnDeadNode {
+			pw.PrintWarning("Pods stuck in Terminating on the node will be force-deleted with a zero grace period")
+			pw.PrintWarning(maintenance.ForceDeleteWarning)
+		}
+	case dead:
+		pw.PrintWarning(fmt.Sprintf("Node %s is NotReady: waiting for its pods to stop may never finish; consider --dead-node", nodeName))
+	}
+
+	// Autoscalers of managed node groups may remove or replace a cordoned node
+	if group := maintenance.DetectNodeGroup(ctx, client, nodeName, cfg.Cloud.DisableScaleDown); group != nil {
+		pw.PrintWarning(group.String())
+	}
+
+	// kured may be rebooting other nodes, or about to reboot this one
+	if kured := maintenance.DetectKured(ctx, client, cfg, nodeName); kured != nil {
+		for _, warning := range kured.Warnings(nodeName, opts.KuredPre) {
+			pw.PrintWarning(warning)
+		}
+	}
 
 	if maintenance.IsInDownState(ctx, client, cfg, nodeName, deployments) {
 		pw.PrintSuccess(fmt.Sprintf("Node %s is already prepared for maintenance (cordoned, noout set, operator down)", nodeName))
-		return nil
+		return finishUnchanged(ctx, client, cfg, pw, nodeName, maintenance.ScalePhaseDown, opts.Prefixes, opts.Headless)
 	}
 
 	// Check if other nodes are in maintenance
@@ -116,20 +403,101 @@ func runDown(cmd *cobra.Command, nodeName string, opts *DownOptions) error {
 		logger.Warn("failed to check for other nodes in maintenance", "error", err)
 	}
 
-	// Build deployment names for display
-	var deploymentNames []string
-	for _, d := range deployments {
-		deploymentNames = append(deploymentNames, fmt.Sprintf("%s/%s", d.Namespace, d.Name))
+	// Check for CephCluster settings that conflict with manual maintenance
+	rookConflicts, err := maintenance.CheckRookSettings(ctx, client, cfg)
+	if err != nil {
+		logger.Debug("failed to check CephCluster settings", "error", err)
+	}
+
+	// Check for balancer/autoscaler activity that would move data while the node is down
+	dataMovementRisks := maintenance.CheckDataMovementRisks(ctx, client, cfg)
+
+	// Estimate the capacity, redundancy and failure domains lost while the node's OSDs are offline
+	var capacityImpact *maintenance.CapacityImpact
+	var crushRisks []maintenance.CrushRisk
+	placement, err := maintenance.LoadPlacement(ctx, client, cfg)
+	if err != nil {
+		logger.Debug("failed to read CRUSH placement", "error", err)
+	} else {
+		capacityImpact = placement.CapacityImpact(nodeName)
+		crushRisks = placement.CrushRisks(nodeName)
+	}
+
+	// Check the node's zone and quorum state in a stretch cluster
+	stretch, err := maintenance.CheckStretchMode(ctx, client, cfg, nodeName)
+	if err != nil {
+		logger.Debug("failed to check stretch mode", "error", err)
+	}
+
+	// Show summary; on re-entry workloads already at 0 replicas are left alone
+	deploymentWorkloads := k8s.DeploymentWorkloads(deployments)
+	statefulSetWorkloads := k8s.StatefulSetWorkloads(statefulSets)
+	pw.PrintSummary(nodeName, len(deployments), downPlanNames(deploymentWorkloads))
+	pw.PrintStatefulSets(downPlanNames(statefulSetWorkloads))
+	workloads := slices.Concat(deploymentWorkloads, statefulSetWorkloads)
+	if plan := maintenance.NewPartialPlan(workloads); plan.Partial() {
+		pw.PrintPartialPlan(plan.String())
+	}
+
+	// Topology changes since the node's previous maintenance deserve a review
+	if diff := maintenance.DiffLastMaintenance(ctx, client, cfg, nodeName, workloads); diff.Changed() {
+		pw.PrintWarning(diff.String())
+	}
+	pw.PrintPrefixes(opts.Prefixes)
+	pw.PrintExcluded(excludedNames(excluded, excludedSets))
+	printSignOff(cmd, opts.SignOff)
+	printNoOutScope(ctx, cmd, client, cfg, nodeName, maintenance.ResolveNoOutScope)
+	if watchdog := cfg.Ceph.NoOutWatchdog; watchdog.Enabled && !GlobalOptions.Quiet {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Noout watchdog: the flag is cleared after %s unless 'crook up' runs first\n",
+			time.Duration(watchdog.TTLSeconds)*time.Second)
+	}
+	if cfg.Drain.Enabled && !GlobalOptions.Quiet {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "The node will be drained: remaining pods are evicted, honoring PodDisruptionBudgets")
+	}
+	if maintenance.IsExternalCluster(ctx, client, cfg) && !GlobalOptions.Quiet {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), maintenance.ExternalClusterNote)
+	}
+
+	// Show capacity impact and the pools left without redundancy margin
+	if capacityImpact != nil {
+		if !GlobalOptions.Quiet {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), capacityImpact.Summary())
+		}
+		for _, pool := range capacityImpact.Pools {
+			pw.PrintWarning(pool.String())
+		}
+	}
+
+	for _, risk := range crushRisks {
+		pw.PrintWarning(risk.String())
 	}
 
-	// Show summary
-	pw.PrintSummary(nodeName, len(deployments), deploymentNames)
+	// Show the stretch cluster zone; a degraded stretch cluster fails pre-flight validation
+	if stretch != nil {
+		if !GlobalOptions.Quiet {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), stretch.Summary())
+		}
+		if reason := stretch.Blocking(); reason != "" {
+			pw.PrintWarning(reason)
+		}
+		for _, warning := range stretch.Warnings() {
+			pw.PrintWarning(warning)
+		}
+	}
 
 	// Show warning if other nodes are in maintenance
 	if maintenanceInfo != nil && maintenanceInfo.HasWarning() {
 		_, _ = fmt.Fprintln(cmd.OutOrStdout(), maintenanceInfo.WarningMessage())
 	}
 
+	// Show warnings for conflicting Rook settings
+	for _, conflict := range rookConflicts {
+		pw.PrintWarning(conflict.String())
+	}
+	for _, risk := range dataMovementRisks {
+		pw.PrintWarning(risk.String())
+	}
+
 	// Confirm unless -y
 	if !opts.Yes {
 		confirmed, confirmErr := cli.Confirm(cli.ConfirmOptions{
@@ -141,19 +509,77 @@ func runDown(cmd *cobra.Command, nodeName string, opts *DownOptions) error {
 			return fmt.Errorf("confirmation failed: %w", confirmErr)
 		}
 		if !confirmed {
-			return fmt.Errorf("operation cancelled by user")
+			return withExitCode(ExitCodeDeclined, fmt.Errorf("operation cancelled by user"))
+		}
+	}
+
+	// Two-person rule: wait for a second user to approve the request
+	var approvalID string
+	if opts.Request {
+		approvalID, err = awaitApproval(cmd.Context(), client, cfg, pw, nodeName, opts.SignOff, opts.ApprovalTimeout)
+		if err != nil {
+			return err
+		}
+
+		// --timeout covers the phase, not the wait for the approver
+		if opts.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(cmd.Context(), opts.Timeout)
+			defer cancel()
+		}
+	}
+
+	// Record the run's timeline when reports or telemetry are requested
+	progress := pw.OnDownProgress
+	var recorder *maintenance.FlowRecorder
+	reports := opts.ReportDir != "" || opts.Headless.SummaryPath != ""
+	if reports || telemetry.Enabled() {
+		recorder = maintenance.NewFlowRecorder(nodeName, "down")
+		recorder.SetSignOff(opts.SignOff)
+		if reports {
+			recorder.SampleHealth(ctx, client, cfg)
+		}
+		progress = func(p maintenance.DownPhaseProgress) {
+			pw.OnDownProgress(p)
+			recorder.OnDownProgress(p)
 		}
 	}
 
 	// Execute the down phase with progress callback
-	executeErr := executeDownPhase(ctx, client, cfg, nodeName, maintenance.DownPhaseOptions{
-		ProgressCallback: pw.OnDownProgress,
-	})
+	downOpts := maintenance.DownPhaseOptions{
+		ProgressCallback:    progress,
+		Prefixes:            opts.Prefixes,
+		SignOff:             opts.SignOff,
+		ApprovalID:          approvalID,
+		AcknowledgeWarnings: opts.AcknowledgeWarnings,
+		AllowControlPlane:   opts.AllowControlPlane,
+		DeadNode:            opts.DeadNode,
+		ForceDeletePods:     opts.ForceDeletePods,
+	}
+	executeErr := executeDownPhase(ctx, client, cfg, nodeName, downOpts)
+	if errors.Is(executeErr, maintenance.ErrAcknowledgmentRequired) {
+		executeErr = acknowledgeWarnings(cmd, opts.Yes, executeErr, func() error {
+			downOpts.AcknowledgeWarnings = true
+			return executeDownPhase(ctx, client, cfg, nodeName, downOpts)
+		})
+	}
+	if executeErr == nil {
+		executeErr = waitForEndState(ctx, client, cfg, pw, nodeName, maintenance.ScalePhaseDown, opts.Prefixes, opts.Headless)
+	}
+	if recorder != nil {
+		writeFlowReports(ctx, client, cfg, pw, opts.ReportDir, opts.Headless.SummaryPath, recorder, executeErr)
+	}
 	if executeErr != nil {
 		pw.PrintError(fmt.Sprintf("Down phase failed: %s", executeErr.Error()))
-		return executeErr
+		return withExitCode(phaseExitCode(ctx, executeErr), executeErr)
 	}
 
 	pw.PrintSuccess(fmt.Sprintf("Node %s is now ready for maintenance", nodeName))
+	if !opts.DeadNode {
+		printVolumeWarnings(ctx, client, pw, nodeName, maintenance.ScalePhaseDown)
+	}
+	if opts.Reboot {
+		return rebootAndOfferUp(cmd, client, pw, nodeName, opts)
+	}
 	return nil
 }
@@ -60,7 +60,7 @@ func TestDownCmdHasRequiredFlags(t *testing.T) {
 		}
 	}
 
-	expectedFlags := []string{"timeout", "yes"}
+	expectedFlags := []string{"timeout", "yes", "report-dir", "drain", "prefix", "exclude", "request", "approval-timeout", "acknowledge-warnings", "allow-control-plane", "dead-node", "force-delete-pods", "disable-scale-down", "reboot", "noout-scope", "noout-watchdog-ttl", "summary-path", "wait", "wait-health-ok"}
 
 	for _, flagName := range expectedFlags {
 		found := false
@@ -135,3 +135,42 @@ func TestDownCmdDefaultTimeout(t *testing.T) {
 
 	t.Fatal("down subcommand not found")
 }
+
+func TestDownCmdRebootRequiresHook(t *testing.T) {
+	cmd := commands.NewRootCmd()
+	cmd.SetArgs([]string{"down", "worker-1", "--reboot", "-y"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "no reboot hook configured") {
+		t.Fatalf("expected a missing reboot hook error, got: %v", err)
+	}
+	if code := commands.ExitCode(err); code != commands.ExitCodeValidation {
+		t.Errorf("ExitCode() = %d, want %d", code, commands.ExitCodeValidation)
+	}
+}
+
+func TestDownCmdNoOutWatchdogRequiresImage(t *testing.T) {
+	cmd := commands.NewRootCmd()
+	cmd.SetArgs([]string{"down", "worker-1", "--noout-watchdog-ttl", "4h", "-y"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "ceph.noout-watchdog.image") {
+		t.Fatalf("expected a missing watchdog image error, got: %v", err)
+	}
+	if code := commands.ExitCode(err); code != commands.ExitCodeValidation {
+		t.Errorf("ExitCode() = %d, want %d", code, commands.ExitCodeValidation)
+	}
+}
+
+func TestDownCmdRejectsInvalidNoOutScope(t *testing.T) {
+	cmd := commands.NewRootCmd()
+	cmd.SetArgs([]string{"down", "worker-1", "--noout-scope", "rack", "-y"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid --noout-scope") {
+		t.Fatalf("expected an invalid --noout-scope error, got: %v", err)
+	}
+	if code := commands.ExitCode(err); code != commands.ExitCodeValidation {
+		t.Errorf("ExitCode() = %d, want %d", code, commands.ExitCodeValidation)
+	}
+}
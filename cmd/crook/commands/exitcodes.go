@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andri/crook/pkg/maintenance"
+)
+
+// Exit codes returned by the crook CLI. Wrappers can branch on these
+// instead of parsing stderr text.
+const (
+	// ExitCodeOK indicates the command completed successfully
+	ExitCodeOK = 0
+
+	// ExitCodeError indicates an unclassified failure
+	ExitCodeError = 1
+
+	// ExitCodeValidation indicates invalid input or failed pre-flight validation.
+	// No changes were made to the cluster.
+	ExitCodeValidation = 2
+
+	// ExitCodeDeclined indicates the user declined the confirmation prompt
+	ExitCodeDeclined = 3
+
+	// ExitCodePartialFailure indicates the operation failed after it started
+	// changing the cluster; the node may be in an intermediate state
+	ExitCodePartialFailure = 4
+
+	// ExitCodeTimeout indicates the operation did not finish within --timeout
+	ExitCodeTimeout = 5
+
+	// ExitCodeAlreadyInState indicates nothing was done because the node
+	// was already in the requested state
+	ExitCodeAlreadyInState = 6
+)
+
+// ExitError wraps an error with the exit code the process should return.
+// A nil Err means the outcome is not an error and nothing should be printed.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error implements the error interface
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit code %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Silent reports whether the error should exit without printing a message
+func (e *ExitError) Silent() bool {
+	return e.Err == nil
+}
+
+// withExitCode wraps err with an explicit exit code
+func withExitCode(code int, err error) error {
+	return &ExitError{Code: code, Err: err}
+}
+
+// ExitCode maps an error returned by Execute to a process exit code
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitCodeTimeout
+	}
+	if errors.Is(err, maintenance.ErrValidationFailed) {
+		return ExitCodeValidation
+	}
+	return ExitCodeError
+}
+
+// phaseExitCode classifies an error returned by a maintenance phase.
+// Failures after pre-flight validation are partial failures since the
+// cluster may already have been modified.
+func phaseExitCode(ctx context.Context, err error) int {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		return ExitCodeTimeout
+	case errors.Is(err, maintenance.ErrValidationFailed):
+		return ExitCodeValidation
+	default:
+		return ExitCodePartialFailure
+	}
+}
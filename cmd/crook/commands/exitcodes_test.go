@@ -0,0 +1,99 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+	"github.com/andri/crook/pkg/maintenance"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+			want: commands.ExitCodeOK,
+		},
+		{
+			name: "plain error",
+			err:  errors.New("boom"),
+			want: commands.ExitCodeError,
+		},
+		{
+			name: "explicit exit code",
+			err:  &commands.ExitError{Code: commands.ExitCodeDeclined, Err: errors.New("cancelled")},
+			want: commands.ExitCodeDeclined,
+		},
+		{
+			name: "wrapped exit error",
+			err:  fmt.Errorf("outer: %w", &commands.ExitError{Code: commands.ExitCodePartialFailure}),
+			want: commands.ExitCodePartialFailure,
+		},
+		{
+			name: "deadline exceeded",
+			err:  fmt.Errorf("waiting: %w", context.DeadlineExceeded),
+			want: commands.ExitCodeTimeout,
+		},
+		{
+			name: "validation failed",
+			err:  fmt.Errorf("%w: node missing", maintenance.ErrValidationFailed),
+			want: commands.ExitCodeValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commands.ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitErrorSilent(t *testing.T) {
+	silent := &commands.ExitError{Code: commands.ExitCodeAlreadyInState}
+	if !silent.Silent() {
+		t.Error("expected ExitError without wrapped error to be silent")
+	}
+
+	loud := &commands.ExitError{Code: commands.ExitCodeValidation, Err: errors.New("bad input")}
+	if loud.Silent() {
+		t.Error("expected ExitError with wrapped error not to be silent")
+	}
+	if loud.Error() != "bad input" {
+		t.Errorf("Error() = %q, want %q", loud.Error(), "bad input")
+	}
+}
+
+func TestUnknownFlagIsValidationError(t *testing.T) {
+	cmd := commands.NewRootCmd()
+	cmd.SetArgs([]string{"ls", "--no-such-flag"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for unknown flag")
+	}
+	if got := commands.ExitCode(err); got != commands.ExitCodeValidation {
+		t.Errorf("ExitCode() = %d, want %d", got, commands.ExitCodeValidation)
+	}
+}
+
+func TestLsInvalidOutputIsValidationError(t *testing.T) {
+	cmd := commands.NewRootCmd()
+	cmd.SetArgs([]string{"ls", "--output", "xml"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for invalid output format")
+	}
+	if got := commands.ExitCode(err); got != commands.ExitCodeValidation {
+		t.Errorf("ExitCode() = %d, want %d", got, commands.ExitCodeValidation)
+	}
+}
@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/andri/crook/pkg/cli"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/spf13/cobra"
+)
+
+// ForceDeletePodsOptions holds options for the force-delete-pods command
+type ForceDeletePodsOptions struct {
+	// Node is the dead node whose stuck pods are deleted
+	Node string
+
+	// Yes skips the confirmation prompt
+	Yes bool
+}
+
+// newForceDeletePodsCmd creates the force-delete-pods subcommand
+func newForceDeletePodsCmd() *cobra.Command {
+	opts := &ForceDeletePodsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "force-delete-pods --node <node>",
+		Short: "Force-delete the pods stuck in Terminating on a dead node",
+		Long: `Force-delete the pods stuck in Terminating on a node that is NotReady or
+unreachable, like 'kubectl delete pod --force --grace-period=0' for each of them.
+
+Deleting a pod only marks it for deletion: the node's kubelet removes it once
+its containers stopped. On a dead node that never happens, so the pods stay in
+Terminating and their controllers do not replace them; StatefulSet pods in
+particular are never recreated elsewhere. This is a standard step of the
+dead-node runbook, also run by 'crook down --dead-node --force-delete-pods'.
+
+DANGER: force-deleting skips the confirmation that the containers stopped. If
+the node is in fact still running them, their replacements run alongside them
+and can corrupt the data both write. Only run this when the node is powered off
+or confirmed unreachable. Nodes that are Ready are refused, and the pods are
+listed and confirmed before anything is deleted unless -y is given.`,
+		Example: `  # Release the pods stuck on dead node worker-1
+  crook force-delete-pods --node worker-1`,
+		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if opts.Node == "" {
+				return withExitCode(ExitCodeValidation, errors.New("--node is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runForceDeletePods(cmd, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Node, "node", "",
+		"dead node whose pods stuck in Terminating are force-deleted (required)")
+	flags.BoolVarP(&opts.Yes, "yes", "y", false,
+		"skip confirmation prompt")
+
+	return cmd
+}
+
+// runForceDeletePods lists the pods stuck in Terminating on the node and
+// force-deletes them once confirmed
+func runForceDeletePods(cmd *cobra.Command, opts *ForceDeletePodsOptions) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	nodeName, err := resolveNodeName(ctx, cmd, client, opts.Node, actOnNodeName(opts.Yes))
+	if err != nil {
+		return err
+	}
+	if err := maintenance.RefuseReadyNode(ctx, client, nodeName); err != nil {
+		if errors.Is(err, maintenance.ErrNodeReady) {
+			return withExitCode(ExitCodeValidation, err)
+		}
+		return fmt.Errorf("failed to check node readiness: %w", err)
+	}
+
+	pods, err := client.ListTerminatingPods(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+
+	pw := cli.NewProgressWriter(cmd.OutOrStdout())
+	pw.SetQuiet(GlobalOptions.Quiet)
+	if len(pods) == 0 {
+		pw.PrintSuccess(fmt.Sprintf("No pods stuck in Terminating on %s", nodeName))
+		return nil
+	}
+
+	pw.PrintWarning(maintenance.ForceDeleteWarning)
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%d pod(s) stuck in Terminating on %s:\n", len(pods), nodeName)
+	for _, pod := range pods {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - %s/%s\n", pod.Namespace, pod.Name)
+	}
+
+	if !opts.Yes {
+		confirmed, confirmErr := cli.Confirm(cli.ConfirmOptions{
+			Question: fmt.Sprintf("Force-delete %d pod(s) from %s?", len(pods), nodeName),
+			Input:    cmd.InOrStdin(),
+			Output:   cmd.OutOrStdout(),
+		})
+		if confirmErr != nil {
+			return fmt.Errorf("confirmation failed: %w", confirmErr)
+		}
+		if !confirmed {
+			return withExitCode(ExitCodeDeclined, fmt.Errorf("operation cancelled by user"))
+		}
+	}
+
+	deleted := 0
+	err = client.ForceDeletePods(ctx, pods, func(pod string) {
+		deleted++
+		pw.PrintSuccess("Force-deleted " + pod)
+	})
+	if err != nil {
+		pw.PrintError(fmt.Sprintf("Force-deleted %d of %d pod(s): %s", deleted, len(pods), err.Error()))
+		if deleted > 0 {
+			// Some pods are already gone, so the node is left in between
+			return withExitCode(ExitCodePartialFailure, err)
+		}
+		return err
+	}
+	pw.PrintSuccess(fmt.Sprintf("Force-deleted %d pod(s) stuck on %s", deleted, nodeName))
+	return nil
+}
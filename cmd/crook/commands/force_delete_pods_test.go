@@ -0,0 +1,42 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestForceDeletePodsCmdExists(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	forceDeleteCmd, _, err := cmd.Find([]string{"force-delete-pods"})
+	if err != nil || forceDeleteCmd.Name() != "force-delete-pods" {
+		t.Fatalf("expected 'force-delete-pods' subcommand to exist: %v", err)
+	}
+	for _, flag := range []string{"node", "yes"} {
+		if forceDeleteCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected force-delete-pods flag %q", flag)
+		}
+	}
+}
+
+func TestForceDeletePodsCmdValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing node", args: []string{"force-delete-pods"}},
+		{name: "unexpected argument", args: []string{"force-delete-pods", "--node", "worker-1", "extra"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := commands.NewRootCmd()
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andri/crook/pkg/cli"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/spf13/pflag"
+)
+
+// HeadlessOptions make down and up easy to drive from pipelines (Terraform, CI)
+type HeadlessOptions struct {
+	// SummaryPath receives a JSON summary of the run when set
+	SummaryPath string
+
+	// Wait blocks until the node is verified in the requested state
+	Wait bool
+
+	// WaitHealthOK also blocks until Ceph reports HEALTH_OK; implies Wait
+	WaitHealthOK bool
+}
+
+// waiting reports whether the run blocks until the end state is reached
+func (o HeadlessOptions) waiting() bool {
+	return o.Wait || o.WaitHealthOK
+}
+
+// addHeadlessFlags registers --summary-path, --wait and --wait-health-ok
+func addHeadlessFlags(flags *pflag.FlagSet, opts *HeadlessOptions) {
+	flags.StringVar(&opts.SummaryPath, "summary-path", "",
+		"write a JSON summary of the run (outcome, exit code, stage durations, deployments, final health) to this file")
+	flags.BoolVar(&opts.Wait, "wait", false,
+		"block until the node is verified in the requested state, within --timeout")
+	flags.BoolVar(&opts.WaitHealthOK, "wait-health-ok", false,
+		"block until the node is in the requested state and Ceph reports HEALTH_OK (implies --wait)")
+}
+
+// waitForEndState blocks, with --wait, until the node is in the end state of
+// the phase
+func waitForEndState(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	pw *cli.ProgressWriter,
+	nodeName, phase string,
+	prefixes []string,
+	opts HeadlessOptions,
+) error {
+	if !opts.waiting() {
+		return nil
+	}
+
+	target := fmt.Sprintf("node %s to be %s", nodeName, phase)
+	if opts.WaitHealthOK {
+		target += " and Ceph HEALTH_OK"
+	}
+	pw.PrintWaiting(target)
+	if err := waitEndState(ctx, client, cfg, nodeName, phase, prefixes, opts.WaitHealthOK, 0); err != nil {
+		return err
+	}
+	pw.PrintSuccess(fmt.Sprintf("Verified %s", target))
+	return nil
+}
+
+// finishUnchanged completes a run that found the node already in the
+// requested state: it still honors --wait-health-ok and --summary-path
+func finishUnchanged(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	pw *cli.ProgressWriter,
+	nodeName, phase string,
+	prefixes []string,
+	opts HeadlessOptions,
+) error {
+	err := waitForEndState(ctx, client, cfg, pw, nodeName, phase, prefixes, opts)
+	exitCode := ExitCodeAlreadyInState
+	if err != nil {
+		exitCode = phaseExitCode(ctx, err)
+	}
+
+	if opts.SummaryPath != "" {
+		recorder := maintenance.NewFlowRecorder(nodeName, phase)
+		recorder.SampleHealth(context.WithoutCancel(ctx), client, cfg)
+		summary := maintenance.NewFlowSummary(recorder.Finish(err), exitCode)
+		if err == nil {
+			summary.Outcome = maintenance.OutcomeUnchanged
+		}
+		writeFlowSummary(pw, opts.SummaryPath, summary)
+	}
+
+	if err != nil {
+		pw.PrintError(err.Error())
+		return withExitCode(exitCode, err)
+	}
+	return withExitCode(ExitCodeAlreadyInState, nil)
+}
+
+// writeFlowSummary writes the --summary-path file. A failure only produces a
+// warning; the phase result is unaffected.
+func writeFlowSummary(pw *cli.ProgressWriter, path string, summary maintenance.FlowSummary) {
+	if err := maintenance.WriteFlowSummary(path, summary); err != nil {
+		pw.PrintWarning(fmt.Sprintf("Failed to write summary: %v", err))
+		return
+	}
+	pw.PrintSuccess(fmt.Sprintf("Summary written to %s", path))
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/output"
+	"github.com/andri/crook/pkg/tui/models"
+	"github.com/andri/crook/pkg/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// HistoryOptions holds options for the history command
+type HistoryOptions struct {
+	// Output specifies the output format: table, json
+	Output string
+
+	// TUI browses the history interactively instead of printing it
+	TUI bool
+}
+
+// newHistoryCmd creates the history subcommand
+func newHistoryCmd() *cobra.Command {
+	opts := &HistoryOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "history [node]",
+		Short: "List past maintenances of the cluster or a node",
+		Long: `List past maintenances, newest first: when each started, how long the node
+was out of service, who ran 'crook down' and 'crook up', the ticket it was done
+for, the outcome and how many cluster changes the report recorded.
+
+When 'crook up' completes a maintenance its report is archived in the ConfigMap
+crook-maintenance-history in the Rook namespace, which keeps the latest 200.
+Maintenances not completed yet are listed from the node reports as "in progress",
+or "failed" when their last phase failed. Operators are the local user names of
+whoever ran the phases.`,
+		Example: `  # Maintenances of the whole cluster
+  crook history
+
+  # Maintenances of worker-1 as JSON
+  crook history worker-1 -o json
+
+  # Browse the history interactively
+  crook history --tui`,
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if _, err := output.ParseFormat(opts.Output); err != nil {
+				return withExitCode(ExitCodeValidation, err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nodeName := ""
+			if len(args) == 1 {
+				nodeName = args[0]
+			}
+			return runHistory(cmd, nodeName, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.Output, "output", "o", "table",
+		"output format: table, json")
+	flags.BoolVar(&opts.TUI, "tui", false,
+		"browse the history interactively")
+
+	return cmd
+}
+
+// runHistory lists the maintenances of the cluster or of nodeName
+func runHistory(cmd *cobra.Command, nodeName string, opts *HistoryOptions) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+	format, err := output.ParseFormat(opts.Output)
+	if err != nil {
+		return err
+	}
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	entries, err := maintenance.ListHistory(ctx, client, cfg, nodeName)
+	if err != nil {
+		return fmt.Errorf("failed to load maintenance history: %w", err)
+	}
+
+	if opts.TUI {
+		model := models.NewHistoryModel(models.HistoryModelConfig{Node: nodeName, Entries: entries})
+		var programOpts []tea.ProgramOption
+		if profile, forced := styles.ColorProfile(); forced {
+			programOpts = append(programOpts, tea.WithColorProfile(profile))
+		}
+		if _, runErr := tea.NewProgram(model, programOpts...).Run(); runErr != nil {
+			return fmt.Errorf("TUI error: %w", runErr)
+		}
+		return nil
+	}
+
+	list := &output.HistoryList{Node: nodeName, Entries: entries}
+	if renderErr := output.RenderHistory(cmd.OutOrStdout(), list, format); renderErr != nil {
+		return fmt.Errorf("failed to render output: %w", renderErr)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/andri/crook/pkg/cli"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/spf13/cobra"
+)
+
+// kuredNodeEnv names the node in the kured DaemonSet's environment
+const kuredNodeEnv = "KURED_NODE_ID"
+
+// HookOptions holds options for the hook subcommands
+type HookOptions struct {
+	// Timeout for the phase
+	Timeout time.Duration
+
+	// AcknowledgeWarnings proceeds past pre-flight checks that only warn
+	AcknowledgeWarnings bool
+
+	// AllowControlPlane permits taking down a control-plane node
+	AllowControlPlane bool
+}
+
+// newHookCmd creates the hook command grouping the entry points of reboot
+// daemons
+func newHookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Run the maintenance phases from the hooks of reboot daemons such as kured",
+		Long: `Entry points for automated OS patching: a reboot daemon runs them around
+the reboots it performs, so crook takes the node through the Ceph-safe down phase
+before the reboot and the up phase once it is back.
+
+The node is the argument, or the KURED_NODE_ID environment variable kured sets.
+The hooks never prompt and succeed when the node is already in the phase's end
+state, so a retried hook is harmless.`,
+		Args: cobra.NoArgs,
+	}
+	cmd.AddCommand(newKuredPreCmd())
+	cmd.AddCommand(newKuredPostCmd())
+	return cmd
+}
+
+// newKuredPreCmd creates the hook kured-pre subcommand
+func newKuredPreCmd() *cobra.Command {
+	opts := &HookOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "kured-pre [node]",
+		Short: "Run the down phase before kured reboots the node",
+		Long: `Run the down phase non-interactively before kured reboots the node: cordon,
+noout, scale down the operator and the node-pinned workloads. The run is signed
+off with the reason "kured reboot", which 'crook hook kured-post' looks for.
+
+A failed pre-flight check exits non-zero, so a reboot command chained after
+the hook does not run. Run it on the node, where the reboot sentinel
+(hooks.kured.sentinel-path) is reported too.`,
+		Example: `  # Before the reboot, from kured's reboot step on the node
+  crook hook kured-pre && systemctl reboot
+
+  # For a named node
+  crook hook kured-pre worker-1 --timeout 15m`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runKuredPre(cmd, args, opts)
+		},
+	}
+	addHookFlags(cmd, opts, 10*time.Minute)
+	cmd.Flags().BoolVar(&opts.AllowControlPlane, "allow-control-plane", false,
+		"permit taking down a control-plane node")
+	return cmd
+}
+
+// newKuredPostCmd creates the hook kured-post subcommand
+func newKuredPostCmd() *cobra.Command {
+	opts := &HookOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "kured-post [node]",
+		Short: "Run the up phase once kured rebooted the node",
+		Long: `Run the up phase non-interactively once the node is back from a kured reboot.
+
+Only a maintenance started by 'crook hook kured-pre' is ended: a node taken down
+by hand is left for 'crook up'. kured keeps a node it found cordoned cordoned
+after the reboot, so this hook is what brings it back into service.`,
+		Example: `  # Once the node booted, e.g. from a systemd unit on the node
+  crook hook kured-post`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runKuredPost(cmd, args, opts)
+		},
+	}
+	addHookFlags(cmd, opts, 15*time.Minute)
+	return cmd
+}
+
+// addHookFlags registers the flags the hook subcommands share
+func addHookFlags(cmd *cobra.Command, opts *HookOptions, timeout time.Duration) {
+	flags := cmd.Flags()
+	flags.DurationVar(&opts.Timeout, "timeout", timeout,
+		"timeout for the phase")
+	flags.BoolVar(&opts.AcknowledgeWarnings, "acknowledge-warnings", false,
+		"proceed past pre-flight warnings such as HEALTH_WARN")
+}
+
+// hookNode returns the node a hook runs for: the argument or KURED_NODE_ID
+func hookNode(args []string) (string, error) {
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	if node := os.Getenv(kuredNodeEnv); node != "" {
+		return node, nil
+	}
+	return "", withExitCode(ExitCodeValidation, fmt.Errorf("no node given and %s is not set", kuredNodeEnv))
+}
+
+// runKuredPre runs the down phase before a kured reboot
+func runKuredPre(cmd *cobra.Command, args []string, opts *HookOptions) error {
+	nodeName, err := hookNode(args)
+	if err != nil {
+		return err
+	}
+
+	if path := GlobalOptions.Config.Hooks.Kured.SentinelPath; maintenance.KuredSentinelPresent(path) && !GlobalOptions.Quiet {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Reboot required: %s is present\n", path)
+	}
+
+	err = runDown(cmd, nodeName, &DownOptions{
+		Timeout:             opts.Timeout,
+		Yes:                 true,
+		AcknowledgeWarnings: opts.AcknowledgeWarnings,
+		AllowControlPlane:   opts.AllowControlPlane,
+		SignOff:             maintenance.SignOff{Reason: maintenance.KuredReason},
+		KuredPre:            true,
+	})
+	return hookResult(err)
+}
+
+// runKuredPost runs the up phase after a kured reboot, when kured-pre started
+// the maintenance
+func runKuredPost(cmd *cobra.Command, args []string, opts *HookOptions) error {
+	nodeName, err := hookNode(args)
+	if err != nil {
+		return err
+	}
+
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	node, err := client.GetNode(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+	if node.Annotations[k8s.ReasonAnnotation] != maintenance.KuredReason {
+		pw := cli.NewProgressWriter(cmd.OutOrStdout())
+		pw.SetQuiet(GlobalOptions.Quiet)
+		pw.PrintWarning(fmt.Sprintf("Node %s is not in a maintenance started by 'crook hook kured-pre'; leaving it for 'crook up'", nodeName))
+		return nil
+	}
+
+	err = runUp(cmd, nodeName, &UpOptions{Timeout: opts.Timeout, Yes: true})
+	return hookResult(err)
+}
+
+// hookResult treats a node already in the end state as success, so kured
+// can retry a hook
+func hookResult(err error) error {
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) && exitErr.Code == ExitCodeAlreadyInState {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+package commands_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestHookCmdExists(t *testing.T) {
+	for _, name := range []string{"kured-pre", "kured-post"} {
+		cmd := commands.NewRootCmd()
+		hookCmd, _, err := cmd.Find([]string{"hook", name})
+		if err != nil || hookCmd.Name() != name {
+			t.Fatalf("expected 'hook %s' subcommand to exist: %v", name, err)
+		}
+		for _, flag := range []string{"timeout", "acknowledge-warnings"} {
+			if hookCmd.Flags().Lookup(flag) == nil {
+				t.Errorf("expected hook %s flag %q", name, flag)
+			}
+		}
+	}
+}
+
+func TestHookCmdRequiresNode(t *testing.T) {
+	t.Setenv("KURED_NODE_ID", "")
+
+	for _, name := range []string{"kured-pre", "kured-post"} {
+		t.Run(name, func(t *testing.T) {
+			cmd := commands.NewRootCmd()
+			cmd.SetArgs([]string{"hook", name})
+
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), "KURED_NODE_ID") {
+				t.Fatalf("expected a missing node error, got: %v", err)
+			}
+			if code := commands.ExitCode(err); code != commands.ExitCodeValidation {
+				t.Errorf("ExitCode() = %d, want %d", code, commands.ExitCodeValidation)
+			}
+		})
+	}
+}
@@ -9,3 +9,4 @@ import (
 var newK8sClient = k8s.NewClient
 var executeUpPhase = maintenance.ExecuteUpPhase
 var executeDownPhase = maintenance.ExecuteDownPhase
+var waitEndState = maintenance.WaitForEndState
@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/andri/crook/pkg/cli"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+// defaultLogcatLines is the number of cluster log entries fetched per poll
+const defaultLogcatLines = 50
+
+// LogcatOptions holds options for the logcat command
+type LogcatOptions struct {
+	// Lines is the number of recent cluster log entries to fetch
+	Lines int
+
+	// Level is the minimum severity to print: debug, info, sec, warn, error
+	Level string
+
+	// Follow keeps polling the cluster log and prints new entries
+	Follow bool
+}
+
+// newLogcatCmd creates the logcat subcommand
+func newLogcatCmd() *cobra.Command {
+	opts := &LogcatOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "logcat",
+		Short: "Show recent Ceph cluster log entries",
+		Long: `Show recent entries of the Ceph cluster log, as reported by 'ceph log last'.
+
+The cluster log is Ceph's own account of what happens during maintenance: OSDs
+marked down, health checks raised and cleared, and PGs peering and recovering.
+With --follow the log is polled at the ui.ceph-refresh-ms interval and new
+entries are printed as they arrive, similar to 'ceph -w'.
+
+In the interactive TUI, press L to show the same log in place of the panes.`,
+		Example: `  # Last 50 entries at info level and above
+  crook logcat
+
+  # Only warnings and errors, following new entries
+  crook logcat --level warn --follow
+
+  # Include debug entries such as pgmap updates
+  crook logcat --level debug --lines 200`,
+		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateLogcatOptions(opts)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runLogcat(cmd, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVarP(&opts.Lines, "lines", "n", defaultLogcatLines,
+		"number of recent cluster log entries to fetch")
+	flags.StringVarP(&opts.Level, "level", "l", "info",
+		"minimum severity: debug, info, sec, warn, error")
+	flags.BoolVarP(&opts.Follow, "follow", "f", false,
+		"keep polling and print new entries until interrupted")
+
+	return cmd
+}
+
+// validateLogcatOptions validates the logcat command options
+func validateLogcatOptions(opts *LogcatOptions) error {
+	if opts.Lines <= 0 {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("--lines must be > 0, got: %d", opts.Lines))
+	}
+	if _, err := k8s.ParseClusterLogSeverity(opts.Level); err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+	return nil
+}
+
+// runLogcat prints the recent cluster log and, with --follow, new entries
+// until the command context is cancelled
+func runLogcat(cmd *cobra.Command, opts *LogcatOptions) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+	minSeverity, _ := k8s.ParseClusterLogSeverity(opts.Level)
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	entries, err := client.GetClusterLog(ctx, cfg.Namespace, opts.Lines)
+	if err != nil {
+		return err
+	}
+	writeClusterLog(cmd.OutOrStdout(), k8s.FilterClusterLog(entries, minSeverity))
+	if !opts.Follow {
+		return nil
+	}
+
+	var last k8s.ClusterLogEntry
+	if len(entries) > 0 {
+		last = entries[len(entries)-1]
+	}
+
+	pw := cli.NewProgressWriter(cmd.ErrOrStderr())
+	interval := time.Duration(cfg.UI.CephRefreshMS) * time.Millisecond
+	if interval <= 0 {
+		interval = config.DefaultCephRefreshMS * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		polled, pollErr := client.GetClusterLog(ctx, cfg.Namespace, opts.Lines)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if pollErr != nil {
+			// Keep following through transient failures such as a restarting toolbox
+			pw.PrintWarning(pollErr.Error())
+			continue
+		}
+
+		newer := k8s.ClusterLogAfter(polled, last)
+		if len(newer) > 0 {
+			last = newer[len(newer)-1]
+		}
+		writeClusterLog(cmd.OutOrStdout(), k8s.FilterClusterLog(newer, minSeverity))
+	}
+}
+
+// writeClusterLog prints one line per cluster log entry
+func writeClusterLog(w io.Writer, entries []k8s.ClusterLogEntry) {
+	for _, entry := range entries {
+		stamp := "-"
+		if !entry.Stamp.IsZero() {
+			stamp = entry.Stamp.Local().Format(time.DateTime)
+		}
+		_, _ = fmt.Fprintf(w, "%s %s [%s] %s\n", stamp, entry.Name, entry.Severity, entry.Message)
+	}
+}
@@ -0,0 +1,43 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestLogcatCmdExists(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	logcatCmd, _, err := cmd.Find([]string{"logcat"})
+	if err != nil || logcatCmd.Name() != "logcat" {
+		t.Fatalf("expected 'logcat' subcommand to exist: %v", err)
+	}
+	for _, flag := range []string{"lines", "level", "follow"} {
+		if logcatCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected logcat flag %q", flag)
+		}
+	}
+}
+
+func TestLogcatCmdValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "unexpected argument", args: []string{"logcat", "worker-1"}},
+		{name: "invalid level", args: []string{"logcat", "--level", "fatal"}},
+		{name: "zero lines", args: []string{"logcat", "--lines", "0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := commands.NewRootCmd()
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
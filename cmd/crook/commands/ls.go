@@ -3,7 +3,6 @@ package commands
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/andri/crook/pkg/k8s"
 	"github.com/andri/crook/pkg/output"
@@ -20,6 +19,12 @@ type LsOptions struct {
 
 	// NodeFilter is the optional node name to filter by (positional arg)
 	NodeFilter string
+
+	// Prefixes replaces the default deployment name prefixes for this run
+	Prefixes []string
+
+	// Selector narrows the nodes, deployments and pods listed
+	Selector k8s.ListSelector
 }
 
 // newLsCmd creates the ls subcommand
@@ -45,7 +50,14 @@ Use 'crook' without arguments to launch the interactive TUI instead.`,
   crook ls --output json
 
   # Show only specific resource types
-  crook ls --show nodes,osds`,
+  crook ls --show nodes,osds
+
+  # List the manager deployments instead of the default Rook-Ceph set
+  crook ls --show deployments --prefix rook-ceph-mgr
+
+  # Only the OSD pods, and only the nodes carrying a label
+  crook ls --show pods -l app=rook-ceph-osd
+  crook ls --show nodes -l topology.kubernetes.io/zone=zone-a`,
 		Args: cobra.MaximumNArgs(1),
 		PreRunE: func(_ *cobra.Command, args []string) error {
 			// Store positional arg if provided
@@ -65,6 +77,8 @@ Use 'crook' without arguments to launch the interactive TUI instead.`,
 		"output format: table, json")
 	flags.StringVar(&opts.Show, "show", "",
 		"resource types to display (comma-separated): nodes,deployments,osds,pods")
+	addPrefixFlag(flags, &opts.Prefixes, "list deployments whose names start with this prefix instead of the default Rook-Ceph prefixes")
+	addSelectorFlags(flags, &opts.Selector)
 
 	return cmd
 }
@@ -72,11 +86,17 @@ Use 'crook' without arguments to launch the interactive TUI instead.`,
 // validateLsOptions validates the ls command options
 func validateLsOptions(opts *LsOptions) error {
 	if _, err := output.ParseFormat(opts.Output); err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+	if err := validatePrefixes(opts.Prefixes); err != nil {
+		return err
+	}
+	if err := validateSelector(opts.Selector); err != nil {
 		return err
 	}
 	if opts.Show != "" {
 		if _, err := output.ParseResourceTypes(opts.Show); err != nil {
-			return err
+			return withExitCode(ExitCodeValidation, err)
 		}
 	}
 
@@ -89,22 +109,18 @@ func runLs(cmd *cobra.Command, opts *LsOptions) error {
 	ctx := cmd.Context()
 
 	// Initialize Kubernetes client with config-derived settings
-	client, err := k8s.NewClient(ctx, k8s.ClientConfig{
-		CephCommandTimeout: time.Duration(cfg.Timeouts.CephCommandTimeoutSeconds) * time.Second,
-	})
+	client, err := k8s.NewClient(ctx, k8sClientConfig(cfg))
 	if err != nil {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
 	// Validate node filter if provided
 	if opts.NodeFilter != "" {
-		exists, checkErr := client.NodeExists(ctx, opts.NodeFilter)
-		if checkErr != nil {
-			return fmt.Errorf("failed to verify node: %w", checkErr)
-		}
-		if !exists {
-			return fmt.Errorf("node %q not found in cluster", opts.NodeFilter)
+		resolved, resolveErr := resolveNodeName(ctx, cmd, client, opts.NodeFilter, nodeNameList)
+		if resolveErr != nil {
+			return resolveErr
 		}
+		opts.NodeFilter = resolved
 	}
 
 	// Parse output format
@@ -125,6 +141,8 @@ func runLs(cmd *cobra.Command, opts *LsOptions) error {
 		Config:        cfg,
 		ResourceTypes: resourceTypes,
 		NodeFilter:    opts.NodeFilter,
+		Prefixes:      opts.Prefixes,
+		Selector:      opts.Selector,
 	})
 	if fetchErr != nil {
 		return fmt.Errorf("failed to fetch data: %w", fetchErr)
@@ -48,7 +48,7 @@ func TestLsCmdHasRequiredFlags(t *testing.T) {
 		}
 	}
 
-	expectedFlags := []string{"output", "show"}
+	expectedFlags := []string{"output", "show", "prefix", "selector", "field-selector"}
 
 	for _, flagName := range expectedFlags {
 		found := false
@@ -214,6 +214,40 @@ func TestLsCmdValidatesShowFlag(t *testing.T) {
 	}
 }
 
+func TestLsCmdValidatesSelectorFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantError string
+	}{
+		{"valid label selector", []string{"-l", "app=rook-ceph-osd"}, ""},
+		{"valid set selector", []string{"--selector", "app in (rook-ceph-osd,rook-ceph-mon)"}, ""},
+		{"valid field selector", []string{"--field-selector", "spec.unschedulable=false"}, ""},
+		{"invalid label selector", []string{"-l", "app in (osd"}, "invalid label selector"},
+		{"invalid field selector", []string{"--field-selector", "spec.nodeName"}, "invalid field selector"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := commands.NewRootCmd()
+			cmd.SetArgs(append([]string{"ls"}, tt.args...))
+
+			err := cmd.Execute()
+
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("expected error containing %q, got: %v", tt.wantError, err)
+				}
+				return
+			}
+			// Fails later connecting to Kubernetes, not on validation
+			if err != nil && strings.Contains(err.Error(), "selector") {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
 func TestLsCmdHelp(t *testing.T) {
 	cmd := commands.NewRootCmd()
 	cmd.SetArgs([]string{"ls", "--help"})
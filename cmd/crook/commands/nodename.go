@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andri/crook/pkg/cli"
+	crookerrors "github.com/andri/crook/pkg/errors"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+// nodeNameResolution is how resolveNodeName treats partial node names
+type nodeNameResolution int
+
+const (
+	// nodeNameList accepts a single partial match with a note and fails when
+	// several nodes match, for commands that only read
+	nodeNameList nodeNameResolution = iota
+
+	// nodeNamePick is nodeNameList, letting the user pick when several match
+	nodeNamePick
+
+	// nodeNameConfirm lets the user confirm a partial match or pick one of
+	// several, for commands acting on the node interactively
+	nodeNameConfirm
+
+	// nodeNameStrict accepts only the exact name or the name's first DNS
+	// label, for commands acting on the node without prompting (--yes)
+	nodeNameStrict
+)
+
+// pickNodeNameIf returns nodeNamePick when interactive, else nodeNameList
+func pickNodeNameIf(interactive bool) nodeNameResolution {
+	if interactive {
+		return nodeNamePick
+	}
+	return nodeNameList
+}
+
+// actOnNodeName returns how a command acting on the node resolves its name:
+// confirming partial matches, or with --yes refusing them
+func actOnNodeName(yes bool) nodeNameResolution {
+	if yes {
+		return nodeNameStrict
+	}
+	return nodeNameConfirm
+}
+
+// resolveNodeName turns a node name given on the command line into the name
+// of a cluster node. Partial names are accepted when they match a single node
+// (see k8s.MatchNodeNames), with a note naming the node they resolved to;
+// commands acting on the node have the user confirm them, or with --yes only
+// accept the exact name or its first DNS label. When several nodes match, the
+// user picks one if the resolution allows it; otherwise, and when the pick is
+// declined, the command fails listing the candidates.
+func resolveNodeName(ctx context.Context, cmd *cobra.Command, client *k8s.Client, query string, resolution nodeNameResolution) (string, error) {
+	candidates, err := client.ResolveNodeName(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("failed to check if node %q exists: %w", query, err)
+	}
+
+	switch {
+	case len(candidates) == 0:
+		return "", withExitCode(ExitCodeValidation, crookerrors.NodeNotFound(query))
+	case len(candidates) == 1:
+		return acceptNodeName(cmd, query, candidates[0], resolution)
+	case resolution == nodeNameList || resolution == nodeNameStrict:
+		return "", withExitCode(ExitCodeValidation, crookerrors.NodeAmbiguous(query, candidates))
+	}
+
+	choice, err := cli.Select(cli.SelectOptions{
+		Question: fmt.Sprintf("Node name %q matches %d nodes:", query, len(candidates)),
+		Choices:  candidates,
+		Input:    cmd.InOrStdin(),
+		Output:   cmd.OutOrStdout(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("node selection failed: %w", err)
+	}
+	if choice < 0 {
+		return "", withExitCode(ExitCodeValidation, crookerrors.NodeAmbiguous(query, candidates))
+	}
+	return candidates[choice], nil
+}
+
+// acceptNodeName accepts the single node the query matched, unless it only
+// matched by prefix or substring and the resolution asks for more
+func acceptNodeName(cmd *cobra.Command, query, name string, resolution nodeNameResolution) (string, error) {
+	if name == query {
+		return name, nil
+	}
+	if k8s.NodeNameMatchesExactly(name, query) || resolution == nodeNameList || resolution == nodeNamePick {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Resolved node %q to %s\n", query, name)
+		return name, nil
+	}
+	if resolution == nodeNameStrict {
+		return "", withExitCode(ExitCodeValidation, crookerrors.NodePartialMatch(query, name))
+	}
+
+	confirmed, err := cli.Confirm(cli.ConfirmOptions{
+		Question: fmt.Sprintf("Node name %q matches %s. Use it?", query, name),
+		Input:    cmd.InOrStdin(),
+		Output:   cmd.OutOrStdout(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("node confirmation failed: %w", err)
+	}
+	if !confirmed {
+		return "", withExitCode(ExitCodeValidation, crookerrors.NodePartialMatch(query, name))
+	}
+	return name, nil
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/andri/crook/pkg/cli"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// NodesOptions holds options for the nodes command
+type NodesOptions struct {
+	// Output specifies the output format: table, json
+	Output string
+
+	// Watch keeps polling and prints the list again whenever it changes
+	Watch bool
+}
+
+// newNodesCmd creates the nodes subcommand
+func newNodesCmd() *cobra.Command {
+	opts := &NodesOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "nodes",
+		Short: "List nodes with their Ceph roles and maintenance state",
+		Long: `List the cluster's nodes in a plain table, without launching the TUI.
+
+Each node shows whether it is Ready and cordoned, the Ceph daemons running on
+it (mon, mgr, osd, mds, rgw), and its maintenance state:
+
+  down         cordoned with Ceph deployments scaled down
+  scaled down  Ceph deployments scaled down on a schedulable node
+  cordoned     cordoned, all Ceph deployments up
+
+Node annotations that record maintenance (crook.io/* and any key mentioning
+maintenance) are listed as well. With --watch the list is polled at the
+ui.k8s-refresh-ms interval and printed again whenever it changes.`,
+		Example: `  # Table output (default)
+  crook nodes
+
+  # JSON output for automation
+  crook nodes -o json
+
+  # Keep watching while nodes go through maintenance
+  crook nodes --watch`,
+		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateNodesOptions(opts)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runNodes(cmd, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.Output, "output", "o", "table",
+		"output format: table, json")
+	flags.BoolVarP(&opts.Watch, "watch", "w", false,
+		"keep polling and print the list again whenever it changes")
+
+	return cmd
+}
+
+// validateNodesOptions validates the nodes command options
+func validateNodesOptions(opts *NodesOptions) error {
+	if _, err := output.ParseFormat(opts.Output); err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+	return nil
+}
+
+// runNodes prints the node list and, with --watch, every changed list until
+// the command context is cancelled
+func runNodes(cmd *cobra.Command, opts *NodesOptions) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+	format, err := output.ParseFormat(opts.Output)
+	if err != nil {
+		return err
+	}
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	render := func() ([]byte, error) {
+		list, fetchErr := output.FetchNodeList(ctx, client, cfg)
+		if fetchErr != nil {
+			return nil, fmt.Errorf("failed to list nodes: %w", fetchErr)
+		}
+		var buf bytes.Buffer
+		if renderErr := output.RenderNodeList(&buf, list, format); renderErr != nil {
+			return nil, fmt.Errorf("failed to render output: %w", renderErr)
+		}
+		return buf.Bytes(), nil
+	}
+
+	last, err := render()
+	if err != nil {
+		return err
+	}
+	_, _ = cmd.OutOrStdout().Write(last)
+	if !opts.Watch {
+		return nil
+	}
+
+	pw := cli.NewProgressWriter(cmd.ErrOrStderr())
+	interval := time.Duration(cfg.UI.K8sRefreshMS) * time.Millisecond
+	if interval <= 0 {
+		interval = config.DefaultK8sRefreshMS * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, pollErr := render()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if pollErr != nil {
+			// Keep watching through transient API failures
+			pw.PrintWarning(pollErr.Error())
+			continue
+		}
+		if bytes.Equal(current, last) {
+			continue
+		}
+		last = current
+
+		if format == output.FormatTable {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\n# %s\n", time.Now().Format(time.TimeOnly))
+		}
+		_, _ = cmd.OutOrStdout().Write(current)
+	}
+}
@@ -0,0 +1,42 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestNodesCmdExists(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	nodesCmd, _, err := cmd.Find([]string{"nodes"})
+	if err != nil || nodesCmd.Name() != "nodes" {
+		t.Fatalf("expected 'nodes' subcommand to exist: %v", err)
+	}
+	for _, flag := range []string{"output", "watch"} {
+		if nodesCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected nodes flag %q", flag)
+		}
+	}
+}
+
+func TestNodesCmdValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "unexpected argument", args: []string{"nodes", "worker-1"}},
+		{name: "invalid output", args: []string{"nodes", "--output", "yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := commands.NewRootCmd()
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// addNoOutScopeFlag registers the --noout-scope flag, which overrides ceph.noout-scope
+func addNoOutScopeFlag(flags *pflag.FlagSet, scope *string, usage string) {
+	flags.StringVar(scope, "noout-scope", "", usage)
+}
+
+// validateNoOutScope rejects --noout-scope values other than config.NoOutScopes
+func validateNoOutScope(scope string) error {
+	if scope != "" && !slices.Contains(config.NoOutScopes, scope) {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("invalid --noout-scope %q: allowed values are %v", scope, config.NoOutScopes))
+	}
+	return nil
+}
+
+// printNoOutScope shows in the plan what the phase sets or unsets the noout
+// flag on, as resolved by resolve (best-effort)
+func printNoOutScope(
+	ctx context.Context,
+	cmd *cobra.Command,
+	client *k8s.Client,
+	cfg config.Config,
+	nodeName string,
+	resolve func(context.Context, *k8s.Client, config.Config, string) (maintenance.NoOutScope, error),
+) {
+	scope, err := resolve(ctx, client, cfg, nodeName)
+	if err != nil {
+		logger.Debug("failed to resolve noout scope", "node", nodeName, "error", err)
+		return
+	}
+	if !GlobalOptions.Quiet {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Ceph noout flag scope: %s\n", scope)
+	}
+}
+
+// NoOutWatchdogOptions holds options for the noout-watchdog command
+type NoOutWatchdogOptions struct {
+	// Expires is when the flag is cleared, in RFC 3339
+	Expires string
+
+	// Scope and Groups are the noout scope the down phase set
+	Scope  string
+	Groups []string
+}
+
+// newNoOutWatchdogCmd creates the hidden noout-watchdog command run by the
+// watchdog Job the down phase creates with ceph.noout-watchdog
+func newNoOutWatchdogCmd() *cobra.Command {
+	opts := &NoOutWatchdogOptions{}
+
+	cmd := &cobra.Command{
+		Use:    "noout-watchdog <node> --expires TIME",
+		Short:  "Clear the noout flag of a maintenance once it expires",
+		Hidden: true,
+		Long: `Wait until --expires, then clear the noout flag the down phase set on
+--scope and --groups, unless the node's maintenance was closed meanwhile.
+
+This runs inside the Job 'crook down' creates when ceph.noout-watchdog is
+enabled; 'crook up' deletes the Job once it unset the flag itself.`,
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if _, err := time.Parse(time.RFC3339, opts.Expires); err != nil {
+				return withExitCode(ExitCodeValidation, fmt.Errorf("invalid --expires %q: %w", opts.Expires, err))
+			}
+			if opts.Scope == "" {
+				return withExitCode(ExitCodeValidation, errors.New("--scope is required"))
+			}
+			return validateNoOutScope(opts.Scope)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNoOutWatchdog(cmd, args[0], opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Expires, "expires", "",
+		"when to clear the flag (RFC 3339)")
+	flags.StringVar(&opts.Scope, "scope", "",
+		"noout scope the down phase set: cluster, host or osd")
+	flags.StringSliceVar(&opts.Groups, "groups", nil,
+		"CRUSH host or OSDs the flag was set on, for the host and osd scopes")
+
+	return cmd
+}
+
+// runNoOutWatchdog waits for the expiry and clears the flag
+func runNoOutWatchdog(cmd *cobra.Command, nodeName string, opts *NoOutWatchdogOptions) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+	expires, _ := time.Parse(time.RFC3339, opts.Expires)
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	scope := maintenance.NoOutScope{Scope: opts.Scope, Groups: opts.Groups}
+	return maintenance.RunNoOutWatchdog(ctx, client, cfg, nodeName, scope, expires)
+}
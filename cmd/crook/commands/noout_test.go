@@ -0,0 +1,46 @@
+package commands_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestNoOutWatchdogCmdIsHidden(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	watchdogCmd, _, err := cmd.Find([]string{"noout-watchdog"})
+	if err != nil || watchdogCmd.Name() != "noout-watchdog" {
+		t.Fatalf("expected 'noout-watchdog' subcommand to exist, err = %v", err)
+	}
+	if !watchdogCmd.Hidden {
+		t.Error("expected noout-watchdog command to be hidden")
+	}
+}
+
+func TestNoOutWatchdogCmdValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"invalid expiry", []string{"--expires", "in 4 hours", "--scope", "cluster"}, "invalid --expires"},
+		{"missing scope", []string{"--expires", "2026-10-16T12:00:00Z"}, "--scope is required"},
+		{"invalid scope", []string{"--expires", "2026-10-16T12:00:00Z", "--scope", "rack"}, "invalid --noout-scope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := commands.NewRootCmd()
+			cmd.SetArgs(append([]string{"noout-watchdog", "worker-1"}, tt.args...))
+
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected an error containing %q, got: %v", tt.want, err)
+			}
+			if code := commands.ExitCode(err); code != commands.ExitCodeValidation {
+				t.Errorf("ExitCode() = %d, want %d", code, commands.ExitCodeValidation)
+			}
+		})
+	}
+}
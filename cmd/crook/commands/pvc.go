@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/andri/crook/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// PVCListOptions holds options for the pvc ls command
+type PVCListOptions struct {
+	// Output specifies the output format: table, json
+	Output string
+
+	// Node keeps only the claims used on this node
+	Node string
+
+	// ClaimNamespace keeps only the claims in this namespace; all when empty
+	ClaimNamespace string
+}
+
+// newPVCCmd creates the pvc command grouping the claim views
+func newPVCCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pvc",
+		Short: "Inspect the PersistentVolumeClaims served by Ceph",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newPVCListCmd())
+	return cmd
+}
+
+// newPVCListCmd creates the pvc ls subcommand
+func newPVCListCmd() *cobra.Command {
+	opts := &PVCListOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "ls",
+		Short: "List the PVCs served by Ceph storage classes and the nodes using them",
+		Long: `List the PersistentVolumeClaims whose storage class is provisioned by a Ceph
+CSI driver (RBD or CephFS), with their size, the pool or filesystem serving
+them, the nodes their volume is attached to or mounted on, and an IO status:
+
+  active       in use on Ready, schedulable nodes
+  maintenance  a node using it is cordoned
+  stalled      a node using it is NotReady or gone, so its IO is likely blocked
+  error        an attachment of the volume reported an error
+  idle         bound, but no node uses it
+  pending      not bound to a volume yet
+
+Use it to see which workloads a node's maintenance affects.`,
+		Example: `  # Every Ceph-backed claim
+  crook pvc ls
+
+  # Claims used on node 'worker-1'
+  crook pvc ls --node worker-1
+
+  # Claims in one namespace, as JSON
+  crook pvc ls --claim-namespace apps -o json`,
+		Aliases: []string{"list"},
+		Args:    cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if _, err := output.ParseFormat(opts.Output); err != nil {
+				return withExitCode(ExitCodeValidation, err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runPVCList(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "table",
+		"output format: table, json")
+	cmd.Flags().StringVar(&opts.Node, "node", "",
+		"only list the claims used on this node")
+	cmd.Flags().StringVar(&opts.ClaimNamespace, "claim-namespace", "",
+		"only list the claims in this namespace (default: all namespaces)")
+
+	return cmd
+}
+
+// runPVCList prints the Ceph-backed claims
+func runPVCList(cmd *cobra.Command, opts *PVCListOptions) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+	format, err := output.ParseFormat(opts.Output)
+	if err != nil {
+		return err
+	}
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	nodeName := opts.Node
+	if nodeName != "" {
+		nodeName, err = resolveNodeName(ctx, cmd, client, nodeName, pickNodeNameIf(format == output.FormatTable))
+		if err != nil {
+			return err
+		}
+	}
+
+	list, err := output.FetchPVCList(ctx, client, opts.ClaimNamespace, nodeName)
+	if err != nil {
+		return fmt.Errorf("failed to list persistent volume claims: %w", err)
+	}
+	if err := output.RenderPVCList(cmd.OutOrStdout(), list, format); err != nil {
+		return fmt.Errorf("failed to render output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestPVCListCmd(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	lsCmd, _, err := cmd.Find([]string{"pvc", "ls"})
+	if err != nil || lsCmd.Name() != "ls" {
+		t.Fatalf("expected 'pvc ls' subcommand to exist: %v", err)
+	}
+	for _, flag := range []string{"output", "node", "claim-namespace"} {
+		if lsCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected pvc ls flag %q", flag)
+		}
+	}
+
+	cmd = commands.NewRootCmd()
+	cmd.SetArgs([]string{"pvc", "ls", "--output", "yaml"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an invalid output format to fail")
+	}
+}
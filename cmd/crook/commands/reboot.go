@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/andri/crook/pkg/cli"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/spf13/cobra"
+)
+
+// rebootAndOfferUp reboots a node the down phase prepared through
+// hooks.reboot, waits for it to come back Ready, then runs the up phase once
+// the user accepts; -y accepts without asking
+func rebootAndOfferUp(cmd *cobra.Command, client *k8s.Client, pw *cli.ProgressWriter, nodeName string, opts *DownOptions) error {
+	cfg := GlobalOptions.Config
+
+	// The reboot has timeouts of its own, --timeout only covers the down phase
+	err := maintenance.RebootNode(cmd.Context(), client, cfg, nodeName, maintenance.RebootOptions{
+		ProgressCallback: pw.OnRebootProgress,
+	})
+	if err != nil {
+		pw.PrintError(fmt.Sprintf("Reboot failed: %s", err.Error()))
+		pw.PrintWarning(fmt.Sprintf("Node %s stays in maintenance; run 'crook up %s' once it is back", nodeName, nodeName))
+		return err
+	}
+
+	if !opts.Yes {
+		confirmed, confirmErr := cli.Confirm(cli.ConfirmOptions{
+			Question: fmt.Sprintf("Node %s is back. Run the up phase now?", nodeName),
+			Input:    cmd.InOrStdin(),
+			Output:   cmd.OutOrStdout(),
+		})
+		if confirmErr != nil {
+			return fmt.Errorf("confirmation failed: %w", confirmErr)
+		}
+		if !confirmed {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Run 'crook up %s' when the node is ready to rejoin\n", nodeName)
+			return nil
+		}
+	}
+
+	// The answer above stands in for the up phase's own confirmation
+	return runUp(cmd, nodeName, &UpOptions{
+		Timeout:  opts.Timeout,
+		Yes:      true,
+		Prefixes: opts.Prefixes,
+		Exclude:  opts.Exclude,
+		SignOff:  opts.SignOff,
+	})
+}
@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/andri/crook/internal/telemetry"
+	"github.com/andri/crook/pkg/cli"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// ReportOptions holds options for the report command
+type ReportOptions struct {
+	// Output specifies the output format: table, json
+	Output string
+}
+
+// newReportCmd creates the report subcommand
+func newReportCmd() *cobra.Command {
+	opts := &ReportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "report <node>",
+		Short: "Show the before/after report of a node's latest maintenance",
+		Long: `Show what changed in the cluster across the latest maintenance of a node.
+
+'crook down' records a snapshot of Ceph nodes, deployments, OSDs, flags and health
+before it changes anything; 'crook up' records another one when it completes and
+stores the differences. Reports are kept in the ConfigMap crook-report-<node> in the
+Rook namespace, so they can be retrieved from any workstation and attached to
+change tickets.`,
+		Example: `  # Show the latest report for worker-1
+  crook report worker-1
+
+  # Full snapshots as JSON
+  crook report worker-1 --output json > worker-1-report.json`,
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if _, err := output.ParseFormat(opts.Output); err != nil {
+				return withExitCode(ExitCodeValidation, err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReport(cmd, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "table",
+		"output format: table, json")
+
+	return cmd
+}
+
+// runReport loads and renders the node's latest maintenance report
+func runReport(cmd *cobra.Command, nodeName string, opts *ReportOptions) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	report, err := maintenance.LoadReport(ctx, client, cfg, nodeName)
+	if errors.Is(err, maintenance.ErrNoReport) {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("%w (reports are recorded by 'crook down' and 'crook up')", err))
+	}
+	if err != nil {
+		return err
+	}
+
+	format, _ := output.ParseFormat(opts.Output)
+	if format == output.FormatJSON {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return maintenance.WriteReport(cmd.OutOrStdout(), report)
+}
+
+// writeFlowReports finishes a run's timeline, records it for telemetry and
+// writes its reports to dir and its JSON summary to summaryPath, whichever is
+// set. Failures only produce a warning; the phase result is unaffected.
+func writeFlowReports(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	pw *cli.ProgressWriter,
+	dir, summaryPath string,
+	recorder *maintenance.FlowRecorder,
+	phaseErr error,
+) {
+	// The phase context may have timed out; the final health sample should still run
+	if dir != "" || summaryPath != "" {
+		recorder.SampleHealth(context.WithoutCancel(ctx), client, cfg)
+	}
+	report := recorder.Finish(phaseErr)
+	recordFlowTelemetry(report)
+
+	if summaryPath != "" {
+		exitCode := ExitCodeOK
+		if phaseErr != nil {
+			exitCode = phaseExitCode(ctx, phaseErr)
+		}
+		writeFlowSummary(pw, summaryPath, maintenance.NewFlowSummary(report, exitCode))
+	}
+	if dir == "" {
+		return
+	}
+
+	paths, err := maintenance.WriteFlowReports(dir, report)
+	if err != nil {
+		pw.PrintWarning(fmt.Sprintf("Failed to write maintenance report: %v", err))
+		return
+	}
+	for _, path := range paths {
+		pw.PrintSuccess(fmt.Sprintf("Report written to %s", path))
+	}
+}
+
+// recordFlowTelemetry adds the run's phase, duration and, when it failed, the
+// stage it stopped at to the telemetry report
+func recordFlowTelemetry(report *maintenance.FlowReport) {
+	flow := telemetry.Flow{
+		Phase:      report.Phase,
+		Outcome:    telemetry.OutcomeSucceeded,
+		DurationMS: report.Duration().Milliseconds(),
+	}
+	if !report.Succeeded() {
+		flow.Outcome = telemetry.OutcomeFailed
+		if n := len(report.Stages); n > 0 {
+			flow.FailureStage = report.Stages[n-1].Stage
+		}
+	}
+	telemetry.RecordFlow(flow)
+}
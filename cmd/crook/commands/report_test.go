@@ -0,0 +1,75 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestReportCmdExists(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	reportCmd, _, err := cmd.Find([]string{"report"})
+	if err != nil || reportCmd.Name() != "report" {
+		t.Fatalf("expected 'report' subcommand to exist: %v", err)
+	}
+	if reportCmd.Flags().Lookup("output") == nil {
+		t.Error("expected report flag \"output\"")
+	}
+}
+
+func TestReportCmdValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing node", args: []string{"report"}},
+		{name: "invalid output format", args: []string{"report", "worker-1", "--output", "yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := commands.NewRootCmd()
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestHistoryCmdExists(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	historyCmd, _, err := cmd.Find([]string{"history"})
+	if err != nil || historyCmd.Name() != "history" {
+		t.Fatalf("expected 'history' subcommand to exist: %v", err)
+	}
+	for _, flag := range []string{"output", "tui"} {
+		if historyCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected history flag %q", flag)
+		}
+	}
+}
+
+func TestHistoryCmdValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "too many nodes", args: []string{"history", "worker-1", "worker-2"}},
+		{name: "invalid output format", args: []string{"history", "--output", "yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := commands.NewRootCmd()
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
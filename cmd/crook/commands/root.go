@@ -7,16 +7,22 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/internal/metrics"
+	"github.com/andri/crook/internal/telemetry"
 	"github.com/andri/crook/pkg/config"
 	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/tui/keys"
 	"github.com/andri/crook/pkg/tui/models"
+	"github.com/andri/crook/pkg/tui/styles"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/term"
 )
 
 // version information set by build flags
@@ -41,12 +47,34 @@ type RootOptions struct {
 	// Namespace sets both rook-operator-namespace and rook-cluster-namespace
 	Namespace string
 
+	// Cluster selects one CephCluster when several share the namespace
+	Cluster string
+
 	// LogLevel sets the logging level (debug, info, warn, error)
 	LogLevel string
 
 	// LogFile sets the file path for log output
 	LogFile string
 
+	// InCluster forces in-cluster service account credentials
+	InCluster bool
+
+	// KubeContext selects a kubeconfig context; the TUI asks for one when
+	// empty and the kubeconfig has several
+	KubeContext string
+
+	// Quiet suppresses progress and informational output
+	Quiet bool
+
+	// NoColor disables colored output
+	NoColor bool
+
+	// DebugListen is the address of the diagnostics HTTP endpoint (disabled when empty)
+	DebugListen string
+
+	// ProfileDir receives CPU and heap profiles of the command when set
+	ProfileDir string
+
 	// Config holds the loaded configuration
 	Config config.Config
 
@@ -58,6 +86,9 @@ type RootOptions struct {
 
 	// logFileHandle tracks the opened log file for cleanup
 	logFileHandle *os.File
+
+	// stopProfiling finishes the profiles started for --profile-dir
+	stopProfiling func() error
 }
 
 // GlobalOptions is the singleton instance for root options
@@ -65,6 +96,12 @@ var GlobalOptions = &RootOptions{}
 
 // NewRootCmd creates the root cobra command
 func NewRootCmd() *cobra.Command {
+	var (
+		tuiNode     string
+		tuiReadOnly bool
+		tuiSelector k8s.ListSelector
+	)
+
 	rootCmd := &cobra.Command{
 		Use:   "crook",
 		Short: "Kubernetes node maintenance automation for Rook-Ceph clusters",
@@ -81,7 +118,9 @@ Key features:
   - Interactive TUI with real-time feedback
   - Pre-flight validation and health monitoring
 
-Run 'crook' without arguments to launch the interactive TUI.`,
+Run 'crook' without arguments to launch the interactive TUI, or
+'crook --node <node>' to open it scoped to one node: its details, its
+deployments, pods and OSDs, and the maintenance pane ready for it.`,
 		SilenceUsage:      true,
 		SilenceErrors:     true,
 		CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true},
@@ -89,12 +128,23 @@ Run 'crook' without arguments to launch the interactive TUI.`,
 			return initializeGlobals(cmd)
 		},
 		PersistentPostRun: func(_ *cobra.Command, _ []string) {
+			telemetry.Finish(context.Background(), ExitCodeOK)
 			cleanup()
 		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runInteractiveTUI(cmd.Context())
+			return runInteractiveTUI(cmd, tuiNode, tuiReadOnly, tuiSelector)
 		},
 	}
+	rootCmd.Flags().StringVar(&tuiNode, "node", "",
+		"open the TUI scoped to this node")
+	rootCmd.Flags().BoolVar(&tuiReadOnly, "read-only", false,
+		"open the TUI without the maintenance pane and its down/up keys (also ui.maintenance-pane: false)")
+	addSelectorFlags(rootCmd.Flags(), &tuiSelector)
+
+	// Flag parsing errors are input validation failures
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return withExitCode(ExitCodeValidation, err)
+	})
 
 	// Add global flags
 	addGlobalFlags(rootCmd)
@@ -102,8 +152,23 @@ Run 'crook' without arguments to launch the interactive TUI.`,
 	// Add subcommands
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newDownCmd())
+	rootCmd.AddCommand(newForceDeletePodsCmd())
+	rootCmd.AddCommand(newHookCmd())
 	rootCmd.AddCommand(newUpCmd())
 	rootCmd.AddCommand(newLsCmd())
+	rootCmd.AddCommand(newNodesCmd())
+	rootCmd.AddCommand(newDeploymentsCmd())
+	rootCmd.AddCommand(newReportCmd())
+	rootCmd.AddCommand(newHistoryCmd())
+	rootCmd.AddCommand(newApproveCmd())
+	rootCmd.AddCommand(newCheckCmd())
+	rootCmd.AddCommand(newPVCCmd())
+	rootCmd.AddCommand(newLogcatCmd())
+	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newRunInClusterCmd())
+	rootCmd.AddCommand(newNoOutWatchdogCmd())
+	rootCmd.AddCommand(newDocsCmd())
+	rootCmd.AddCommand(newChaosTestCmd())
 
 	return rootCmd
 }
@@ -116,10 +181,25 @@ func addGlobalFlags(cmd *cobra.Command) {
 		"config file (default: ./crook.yaml, ~/.config/crook/config.yaml, /etc/crook/config.yaml)")
 	flags.StringVar(&GlobalOptions.Namespace, "namespace", "",
 		"rook-ceph namespace (default: rook-ceph)")
+	flags.StringVar(&GlobalOptions.Cluster, "cluster", "",
+		"CephCluster name to operate on when the namespace has several (default: all)")
 	flags.StringVar(&GlobalOptions.LogLevel, "log-level", "",
 		"log level: debug, info, warn, error (default: info)")
 	flags.StringVar(&GlobalOptions.LogFile, "log-file", "",
 		"log file path (default: stderr)")
+	flags.BoolVar(&GlobalOptions.InCluster, "in-cluster", false,
+		"use in-cluster service account credentials instead of kubeconfig")
+	flags.StringVar(&GlobalOptions.KubeContext, "context", "",
+		"kubeconfig context to use (default: current context; the TUI asks when there are several)")
+	flags.BoolVarP(&GlobalOptions.Quiet, "quiet", "q", false,
+		"suppress progress output (errors and warnings are still shown)")
+	flags.BoolVar(&GlobalOptions.NoColor, "no-color", false,
+		"disable colored output (also honors NO_COLOR)")
+	flags.StringVar(&GlobalOptions.DebugListen, "debug-listen", "",
+		"serve internal metrics on this address (e.g. 127.0.0.1:6060) for diagnosis")
+	_ = flags.MarkHidden("debug-listen")
+	flags.StringVar(&GlobalOptions.ProfileDir, "profile-dir", "",
+		"write CPU and heap profiles of the command to this directory")
 }
 
 // initializeGlobals initializes global options from flags, env, and config file
@@ -132,15 +212,25 @@ func initializeGlobals(cmd *cobra.Command) error {
 	// Set cobra's context so cmd.Context() returns our signal-aware context
 	cmd.SetContext(ctx)
 
+	// Apply output controls before anything is rendered
+	styles.SetColorEnabled(!GlobalOptions.NoColor)
+
 	// Load configuration with flag bindings
 	loadOpts := config.LoadOptions{
 		ConfigFile: GlobalOptions.ConfigFile,
 		Flags:      buildFlagSet(cmd),
 	}
 
+	// Inside a pod without a kubeconfig, default to the pod's own namespace
+	if k8s.UsesInClusterConfig(k8s.ClientConfig{InCluster: GlobalOptions.InCluster}) {
+		if namespace, ok := k8s.InClusterNamespace(); ok {
+			loadOpts.DefaultNamespace = namespace
+		}
+	}
+
 	result, err := config.LoadConfig(loadOpts)
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return withExitCode(ExitCodeValidation, fmt.Errorf("failed to load configuration: %w", err))
 	}
 
 	GlobalOptions.Config = result.Config
@@ -155,9 +245,44 @@ func initializeGlobals(cmd *cobra.Command) error {
 		logger.Debug("loaded configuration", "file", result.ConfigFileUsed)
 	}
 
+	// Record anonymous usage when the user opted in
+	startTelemetry(cmd)
+
+	// Profile the whole command when requested
+	if GlobalOptions.ProfileDir != "" {
+		stop, profileErr := metrics.StartProfiling(GlobalOptions.ProfileDir)
+		if profileErr != nil {
+			return withExitCode(ExitCodeValidation, profileErr)
+		}
+		GlobalOptions.stopProfiling = stop
+	}
+
+	// Serve internal metrics and pprof when diagnosing slow refreshes
+	if GlobalOptions.DebugListen != "" {
+		if serveErr := metrics.Serve(ctx, GlobalOptions.DebugListen); serveErr != nil {
+			return withExitCode(ExitCodeValidation, serveErr)
+		}
+	}
+
 	return nil
 }
 
+// startTelemetry starts the usage report of the command: its path, the
+// names of the flags set and the terminal size. It does nothing unless
+// telemetry is enabled.
+func startTelemetry(cmd *cobra.Command) {
+	var flags []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		flags = append(flags, f.Name)
+	})
+	command := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+	telemetry.Start(GlobalOptions.Config.Telemetry, version, command, flags)
+
+	if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		telemetry.RecordTerminal(width, height)
+	}
+}
+
 // buildFlagSet creates a pflag.FlagSet from cobra command flags for config binding
 func buildFlagSet(cmd *cobra.Command) *pflag.FlagSet {
 	flags := pflag.NewFlagSet("config", pflag.ContinueOnError)
@@ -177,12 +302,25 @@ func buildFlagSet(cmd *cobra.Command) *pflag.FlagSet {
 
 	// Add relevant flags for config binding
 	addIfExists("namespace")
+	addIfExists("cluster")
 	addIfExists("log-level")
 	addIfExists("log-file")
 
 	return flags
 }
 
+// k8sClientConfig builds the Kubernetes client configuration from global options
+func k8sClientConfig(cfg config.Config) k8s.ClientConfig {
+	return k8s.ClientConfig{
+		CephCommandTimeout: time.Duration(cfg.Timeouts.CephCommandTimeoutSeconds) * time.Second,
+		InCluster:          GlobalOptions.InCluster,
+		Context:            GlobalOptions.KubeContext,
+		CephCommands:       cfg.Ceph.Commands,
+		CephCluster:        cfg.Cluster,
+		DeploymentFilters:  cfg.Discovery.Filters,
+	}
+}
+
 // initLogger initializes the logger based on configuration
 func initLogger() error {
 	cfg := GlobalOptions.Config.Logging
@@ -237,6 +375,12 @@ func cleanup() {
 	if GlobalOptions.CancelFunc != nil {
 		GlobalOptions.CancelFunc()
 	}
+	if GlobalOptions.stopProfiling != nil {
+		if err := GlobalOptions.stopProfiling(); err != nil {
+			logger.Warn("failed to write profiles", "error", err)
+		}
+		GlobalOptions.stopProfiling = nil
+	}
 	if GlobalOptions.logFileHandle != nil {
 		_ = GlobalOptions.logFileHandle.Close()
 		GlobalOptions.logFileHandle = nil
@@ -260,34 +404,78 @@ func newVersionCmd() *cobra.Command {
 
 // Execute runs the root command
 func Execute() error {
-	return NewRootCmd().Execute()
+	err := NewRootCmd().Execute()
+	// PersistentPostRun is skipped when a command fails; report usage, flush
+	// profiles and close the log file regardless
+	telemetry.Finish(context.Background(), ExitCode(err))
+	cleanup()
+	return err
 }
 
-// runInteractiveTUI launches the interactive TUI for node management
-func runInteractiveTUI(ctx context.Context) error {
+// runInteractiveTUI launches the interactive TUI for node management,
+// scoped to nodeName when it is set, without maintenance when readOnly is set
+// or the config disables the maintenance pane, and listing only what the
+// selector matches
+func runInteractiveTUI(cmd *cobra.Command, nodeName string, readOnly bool, selector k8s.ListSelector) error {
 	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+
+	if err := validateSelector(selector); err != nil {
+		return err
+	}
+
+	// Refuse to start when one key would silently shadow another's action
+	if err := keys.CheckConflicts(keys.DefaultLsKeyMap(), keys.DefaultFlowBindings()); err != nil {
+		return err
+	}
+
+	// Ask which cluster to open before connecting to one
+	if err := pickKubeContext(); err != nil {
+		return err
+	}
 
 	// Initialize Kubernetes client
 	logger.Info("connecting to kubernetes cluster")
-	client, err := k8s.NewClient(ctx, k8s.ClientConfig{
-		CephCommandTimeout: time.Duration(cfg.Timeouts.CephCommandTimeoutSeconds) * time.Second,
-	})
+	clientCfg := k8sClientConfig(cfg)
+	clientCfg.NonInteractiveAuth = true
+	client, err := k8s.NewClient(ctx, clientCfg)
 	if err != nil {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
-	// Create the ls model (multi-pane TUI with embedded up/down flows)
+	if nodeName != "" {
+		if nodeName, err = resolveNodeName(ctx, cmd, client, nodeName, nodeNamePick); err != nil {
+			return err
+		}
+	}
+
+	// Create the ls model (multi-pane TUI with embedded up/down flows),
+	// restoring the setup of the last session on this cluster
+	kubeContext := kubeContextName()
+	stateKey := models.UIStateKey(kubeContext, cfg.Namespace, cfg.Cluster)
 	model := models.NewLsModel(models.LsModelConfig{
-		Config:  cfg,
-		Client:  client,
-		Context: ctx,
+		NodeFilter:  nodeName,
+		ReadOnly:    readOnly || !cfg.UI.MaintenancePane,
+		Selector:    selector,
+		Config:      cfg,
+		KubeContext: kubeContext,
+		State:       models.LoadUIState(stateKey),
+		Client:      client,
+		Context:     ctx,
 	})
 
 	// Run the TUI
-	p := tea.NewProgram(model)
+	var opts []tea.ProgramOption
+	if profile, forced := styles.ColorProfile(); forced {
+		opts = append(opts, tea.WithColorProfile(profile))
+	}
+	p := tea.NewProgram(model, opts...)
 	if _, runErr := p.Run(); runErr != nil {
 		return fmt.Errorf("TUI error: %w", runErr)
 	}
 
+	if err := models.SaveUIState(stateKey, model.UIState()); err != nil {
+		logger.Warn("failed to save UI state", "error", err)
+	}
 	return nil
 }
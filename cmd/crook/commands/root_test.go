@@ -28,7 +28,7 @@ func TestRootCmdHasGlobalFlags(t *testing.T) {
 	cmd := commands.NewRootCmd()
 	flags := cmd.PersistentFlags()
 
-	expectedFlags := []string{"config", "namespace", "log-level", "log-file"}
+	expectedFlags := []string{"config", "namespace", "log-level", "log-file", "quiet", "no-color", "in-cluster", "context", "debug-listen", "profile-dir"}
 
 	for _, flagName := range expectedFlags {
 		if flags.Lookup(flagName) == nil {
@@ -37,6 +37,19 @@ func TestRootCmdHasGlobalFlags(t *testing.T) {
 	}
 }
 
+func TestRootCmdHasTUIFlags(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	for _, name := range []string{"node", "read-only", "selector", "field-selector"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Fatalf("expected root flag %q to exist", name)
+		}
+		if cmd.PersistentFlags().Lookup(name) != nil {
+			t.Errorf("--%s configures the TUI and should not be inherited by subcommands", name)
+		}
+	}
+}
+
 func TestRootCmdHasVersionSubcommand(t *testing.T) {
 	cmd := commands.NewRootCmd()
 
@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/cli"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+// runInClusterPollInterval is how often Job pod state is polled
+const runInClusterPollInterval = 2 * time.Second
+
+// RunInClusterOptions holds options for the run-in-cluster command
+type RunInClusterOptions struct {
+	// Image is the crook container image to run
+	Image string
+
+	// ServiceAccount provides the Job's in-cluster credentials
+	ServiceAccount string
+
+	// Timeout for the phase; zero uses the phase default
+	Timeout time.Duration
+
+	// TTL keeps the finished Job for inspection before cleanup
+	TTL time.Duration
+
+	// Yes skips the confirmation prompt
+	Yes bool
+}
+
+// newRunInClusterCmd creates the run-in-cluster command
+func newRunInClusterCmd() *cobra.Command {
+	opts := &RunInClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "run-in-cluster",
+		Short: "Run a maintenance phase as a Kubernetes Job",
+		Long: `Run 'crook down' or 'crook up' inside the cluster as a Kubernetes Job.
+
+The Job uses in-cluster service account credentials, so no privileged access is
+needed from the workstation beyond creating the Job and reading its logs. The Job
+pod is scheduled away from the target node and its logs are streamed back.
+
+The service account needs the same permissions as a user running crook directly.`,
+		Example: `  # Prepare worker-1 for maintenance from inside the cluster
+  crook run-in-cluster down worker-1 --image registry.example.com/crook:v1.2.0
+
+  # Restore it, using a custom service account
+  crook run-in-cluster up worker-1 --image registry.example.com/crook:v1.2.0 --service-account crook-admin`,
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&opts.Image, "image", "",
+		"crook container image to run (required)")
+	flags.StringVar(&opts.ServiceAccount, "service-account", "crook",
+		"service account used by the Job")
+	flags.DurationVar(&opts.Timeout, "timeout", 0,
+		"timeout for the phase (default: 10m for down, 15m for up)")
+	flags.DurationVar(&opts.TTL, "ttl", time.Hour,
+		"how long to keep the finished Job before it is cleaned up")
+	flags.BoolVarP(&opts.Yes, "yes", "y", false,
+		"skip confirmation prompt")
+
+	cmd.AddCommand(newRunInClusterPhaseCmd("down", 10*time.Minute, opts))
+	cmd.AddCommand(newRunInClusterPhaseCmd("up", 15*time.Minute, opts))
+
+	return cmd
+}
+
+// newRunInClusterPhaseCmd creates the down/up subcommand of run-in-cluster
+func newRunInClusterPhaseCmd(phase string, defaultTimeout time.Duration, opts *RunInClusterOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   phase + " <node>",
+		Short: fmt.Sprintf("Run 'crook %s <node>' as a Kubernetes Job", phase),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			timeout := opts.Timeout
+			if timeout == 0 {
+				timeout = defaultTimeout
+			}
+			return runInCluster(cmd, phase, args[0], timeout, opts)
+		},
+	}
+}
+
+// runInCluster creates the maintenance Job, streams its logs, and returns its outcome
+func runInCluster(cmd *cobra.Command, phase, nodeName string, timeout time.Duration, opts *RunInClusterOptions) error {
+	if opts.Image == "" {
+		return withExitCode(ExitCodeValidation, errors.New("--image is required"))
+	}
+
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	nodeName, err = resolveNodeName(ctx, cmd, client, nodeName, actOnNodeName(opts.Yes))
+	if err != nil {
+		return err
+	}
+
+	pw := cli.NewProgressWriter(out)
+	pw.SetQuiet(GlobalOptions.Quiet)
+
+	if !opts.Yes {
+		_, _ = fmt.Fprintf(out, "Job: crook %s %s\nNamespace: %s\nImage: %s\nService account: %s\n\n",
+			phase, nodeName, cfg.Namespace, opts.Image, opts.ServiceAccount)
+		confirmed, confirmErr := cli.Confirm(cli.ConfirmOptions{
+			Question: fmt.Sprintf("Create Job to run %s phase in the cluster?", phase),
+			Input:    cmd.InOrStdin(),
+			Output:   out,
+		})
+		if confirmErr != nil {
+			return fmt.Errorf("confirmation failed: %w", confirmErr)
+		}
+		if !confirmed {
+			return withExitCode(ExitCodeDeclined, fmt.Errorf("operation cancelled by user"))
+		}
+	}
+
+	job := k8s.BuildMaintenanceJob(k8s.MaintenanceJobOptions{
+		Namespace:        cfg.Namespace,
+		Phase:            phase,
+		NodeName:         nodeName,
+		Image:            opts.Image,
+		ServiceAccount:   opts.ServiceAccount,
+		Args:             []string{"--namespace", cfg.Namespace, "--timeout", timeout.String()},
+		ActiveDeadline:   timeout + time.Minute,
+		TTLAfterFinished: opts.TTL,
+	})
+
+	created, err := client.CreateJob(ctx, job)
+	if err != nil {
+		return err
+	}
+	pw.PrintSuccess(fmt.Sprintf("Created job %s/%s", created.Namespace, created.Name))
+
+	podName, err := client.WaitForJobPod(ctx, created.Namespace, created.Name, runInClusterPollInterval)
+	if err != nil {
+		return withExitCode(ExitCodeTimeout, fmt.Errorf("%w (job %s keeps running in the cluster)", err, created.Name))
+	}
+
+	logger.Debug("streaming job logs", "job", created.Name, "pod", podName)
+	if logErr := client.StreamPodLogs(ctx, created.Namespace, podName, out); logErr != nil {
+		pw.PrintWarning(fmt.Sprintf("Log streaming interrupted: %v", logErr))
+	}
+
+	exitCode, err := client.WaitForPodExitCode(ctx, created.Namespace, podName, runInClusterPollInterval)
+	if err != nil {
+		return withExitCode(ExitCodeTimeout, fmt.Errorf("%w (job %s keeps running in the cluster)", err, created.Name))
+	}
+
+	switch int(exitCode) {
+	case ExitCodeOK:
+		return nil
+	case ExitCodeAlreadyInState:
+		return withExitCode(ExitCodeAlreadyInState, nil)
+	default:
+		return withExitCode(int(exitCode), fmt.Errorf("job %s/%s failed with exit code %d", created.Namespace, created.Name, exitCode))
+	}
+}
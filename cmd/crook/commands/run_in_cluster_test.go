@@ -0,0 +1,42 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestRunInClusterCmdHasPhaseSubcommands(t *testing.T) {
+	cmd := commands.NewRootCmd()
+
+	runCmd, _, err := cmd.Find([]string{"run-in-cluster"})
+	if err != nil || runCmd.Name() != "run-in-cluster" {
+		t.Fatalf("expected 'run-in-cluster' subcommand to exist: %v", err)
+	}
+
+	for _, phase := range []string{"down", "up"} {
+		sub, _, findErr := cmd.Find([]string{"run-in-cluster", phase})
+		if findErr != nil || sub.Name() != phase {
+			t.Errorf("expected 'run-in-cluster %s' subcommand to exist", phase)
+		}
+	}
+
+	for _, flagName := range []string{"image", "service-account", "timeout", "ttl", "yes"} {
+		if runCmd.PersistentFlags().Lookup(flagName) == nil {
+			t.Errorf("expected run-in-cluster flag %q", flagName)
+		}
+	}
+}
+
+func TestRunInClusterRequiresImage(t *testing.T) {
+	cmd := commands.NewRootCmd()
+	cmd.SetArgs([]string{"run-in-cluster", "down", "worker-1"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error when --image is missing")
+	}
+	if got := commands.ExitCode(err); got != commands.ExitCodeValidation {
+		t.Errorf("ExitCode() = %d, want %d", got, commands.ExitCodeValidation)
+	}
+}
@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/spf13/pflag"
+)
+
+// addSelectorFlags registers the kubectl-style -l/--selector and
+// --field-selector flags narrowing the nodes, deployments and pods listed
+func addSelectorFlags(flags *pflag.FlagSet, selector *k8s.ListSelector) {
+	flags.StringVarP(&selector.Label, "selector", "l", "",
+		"only list nodes, deployments and pods matching this label selector, e.g. app=rook-ceph-osd")
+	flags.StringVar(&selector.Field, "field-selector", "",
+		"only list nodes, deployments and pods matching this field selector, e.g. spec.unschedulable=false")
+}
+
+// validateSelector rejects malformed --selector and --field-selector values
+func validateSelector(selector k8s.ListSelector) error {
+	if err := selector.Validate(); err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/chatops"
+	"github.com/andri/crook/pkg/server"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+	"github.com/spf13/cobra"
+)
+
+// Environment variables holding serve credentials
+const (
+	serveTokenEnv    = "CROOK_SERVE_TOKEN"
+	slackAppTokenEnv = "SLACK_APP_TOKEN"
+	slackBotTokenEnv = "SLACK_BOT_TOKEN"
+)
+
+// ServeOptions holds options for the serve command
+type ServeOptions struct {
+	// Listen is the address to listen on
+	Listen string
+
+	// TokenFile is a file containing the bearer token clients must present
+	TokenFile string
+
+	// TLSCert and TLSKey enable HTTPS when both are set
+	TLSCert string
+	TLSKey  string
+
+	// Slack enables the Slack socket mode integration
+	Slack bool
+
+	// SlackChannels restricts Slack commands to these channel IDs
+	SlackChannels []string
+}
+
+// newServeCmd creates the serve subcommand
+func newServeCmd() *cobra.Command {
+	opts := &ServeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run an HTTP API for remote maintenance control",
+		Long: `Run crook as a daemon exposing an authenticated HTTP API.
+
+The API uses the same maintenance engine as the CLI and TUI, so web UIs and
+chatops bots can list cluster state, preview plans, and run down/up phases.
+
+Endpoints (all require "Authorization: Bearer <token>"):
+  GET  /api/v1/status                  Cluster data (same as 'crook ls -o json')
+  GET  /api/v1/nodes                   Nodes hosting Ceph pods
+  GET  /api/v1/osds                    Ceph OSDs
+  GET  /api/v1/nodes/{node}/plan       Deployments affected (?phase=down|up)
+  POST /api/v1/nodes/{node}/down       Start the down phase (?acknowledge_warnings=true)
+  POST /api/v1/nodes/{node}/up         Start the up phase
+  GET  /api/v1/operations              All operations
+  GET  /api/v1/operations/{id}         Operation status
+  GET  /api/v1/operations/{id}/events  Stream progress as newline-delimited JSON
+
+Only one maintenance operation runs at a time.
+
+The token is read from --token-file or the ` + serveTokenEnv + ` environment variable.
+
+With --slack, a Slack app in socket mode handles "/crook status|down|up <node>".
+Down and up post an approval message; progress is posted in its thread.
+Requires ` + slackAppTokenEnv + ` (xapp-...) and ` + slackBotTokenEnv + ` (xoxb-...).`,
+		Example: `  # Serve on localhost with a token file
+  crook serve --listen 127.0.0.1:8080 --token-file /etc/crook/token
+
+  # Serve over HTTPS
+  crook serve --listen :8443 --token-file token --tls-cert tls.crt --tls-key tls.key`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runServe(cmd, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Listen, "listen", "127.0.0.1:8080",
+		"address to listen on")
+	flags.StringVar(&opts.TokenFile, "token-file", "",
+		"file containing the API bearer token (default: $"+serveTokenEnv+")")
+	flags.StringVar(&opts.TLSCert, "tls-cert", "",
+		"TLS certificate file (enables HTTPS with --tls-key)")
+	flags.StringVar(&opts.TLSKey, "tls-key", "",
+		"TLS private key file")
+	flags.BoolVar(&opts.Slack, "slack", false,
+		"enable Slack slash commands via socket mode ($"+slackAppTokenEnv+", $"+slackBotTokenEnv+")")
+	flags.StringSliceVar(&opts.SlackChannels, "slack-channel", nil,
+		"restrict Slack commands to these channel IDs (repeatable)")
+
+	return cmd
+}
+
+// runServe starts the API server and blocks until the context is cancelled
+func runServe(cmd *cobra.Command, opts *ServeOptions) error {
+	cfg := GlobalOptions.Config
+	ctx := cmd.Context()
+
+	token, err := loadServeToken(opts.TokenFile)
+	if err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+	if (opts.TLSCert == "") != (opts.TLSKey == "") {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("--tls-cert and --tls-key must be set together"))
+	}
+
+	logger.Info("connecting to kubernetes cluster")
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	srv, err := server.New(ctx, server.Options{
+		Client:      client,
+		Config:      cfg,
+		Token:       token,
+		ExecuteDown: executeDownPhase,
+		ExecuteUp:   executeUpPhase,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	if opts.Slack {
+		if slackErr := startSlackBot(ctx, srv, opts); slackErr != nil {
+			return withExitCode(ExitCodeValidation, slackErr)
+		}
+	}
+
+	httpServer := &http.Server{
+		Addr:              opts.Listen,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Info("api server listening", "address", opts.Listen, "tls", opts.TLSCert != "")
+		if opts.TLSCert != "" {
+			errCh <- httpServer.ListenAndServeTLS(opts.TLSCert, opts.TLSKey)
+		} else {
+			errCh <- httpServer.ListenAndServe()
+		}
+	}()
+
+	select {
+	case serveErr := <-errCh:
+		if !errors.Is(serveErr, http.ErrServerClosed) {
+			return fmt.Errorf("api server failed: %w", serveErr)
+		}
+	case <-ctx.Done():
+		logger.Info("shutting down api server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if shutdownErr := httpServer.Shutdown(shutdownCtx); shutdownErr != nil {
+			logger.Warn("api server shutdown did not complete cleanly", "error", shutdownErr)
+		}
+	}
+
+	// Running operations observe ctx cancellation; wait for them to return
+	srv.Wait()
+	return nil
+}
+
+// loadServeToken reads the API token from a file or the environment
+func loadServeToken(tokenFile string) (string, error) {
+	if tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file %s: %w", tokenFile, err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", tokenFile)
+		}
+		return token, nil
+	}
+
+	if token := strings.TrimSpace(os.Getenv(serveTokenEnv)); token != "" {
+		return token, nil
+	}
+	return "", fmt.Errorf("an API token is required: use --token-file or set %s", serveTokenEnv)
+}
+
+// startSlackBot connects the Slack integration in the background
+func startSlackBot(ctx context.Context, srv *server.Server, opts *ServeOptions) error {
+	appToken := strings.TrimSpace(os.Getenv(slackAppTokenEnv))
+	botToken := strings.TrimSpace(os.Getenv(slackBotTokenEnv))
+	if appToken == "" || botToken == "" {
+		return fmt.Errorf("--slack requires %s and %s to be set", slackAppTokenEnv, slackBotTokenEnv)
+	}
+
+	api := slack.New(botToken, slack.OptionAppLevelToken(appToken))
+	client := socketmode.New(api, socketmode.OptionLog(log.New(io.Discard, "", 0)))
+	bot := chatops.NewSlackBot(chatops.SlackOptions{
+		Engine:          srv,
+		Poster:          api,
+		AllowedChannels: opts.SlackChannels,
+	})
+
+	go func() {
+		if err := bot.Run(ctx, client); err != nil {
+			logger.Error("slack integration stopped", "error", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// addSignOffFlags registers --reason and --ticket, which link a maintenance
+// to its change request
+func addSignOffFlags(flags *pflag.FlagSet, signOff *maintenance.SignOff) {
+	flags.StringVar(&signOff.Reason, "reason", "",
+		"why the maintenance is done; recorded on the node and in the maintenance report")
+	flags.StringVar(&signOff.Ticket, "ticket", "",
+		"change request or ticket ID (e.g. CHG-1234); recorded on the node and in the maintenance report")
+}
+
+// printSignOff shows the run's sign-off with the plan, unless quiet
+func printSignOff(cmd *cobra.Command, signOff maintenance.SignOff) {
+	if signOff.IsZero() || GlobalOptions.Quiet {
+		return
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Sign-off: %s\n", signOff)
+}
@@ -0,0 +1,57 @@
+package commands_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+)
+
+func TestTelemetryReportsCommandWhenEnabled(t *testing.T) {
+	var events []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("invalid telemetry body: %v", err)
+		}
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	run := func() {
+		cmd := commands.NewRootCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs([]string{"version", "--no-color"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error: %v", err)
+		}
+	}
+
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv("CROOK_TELEMETRY_ENDPOINT", server.URL)
+	run()
+	if len(events) != 0 {
+		t.Fatalf("telemetry sent %d events without opting in", len(events))
+	}
+
+	t.Setenv("CROOK_TELEMETRY_ENABLED", "true")
+	run()
+	if len(events) != 1 {
+		t.Fatalf("got %d telemetry events, want 1", len(events))
+	}
+	if events[0]["command"] != "version" {
+		t.Errorf("command = %v, want version", events[0]["command"])
+	}
+	if flags, _ := events[0]["flags"].([]any); len(flags) != 1 || flags[0] != "no-color" {
+		t.Errorf("flags = %v, want [no-color]", events[0]["flags"])
+	}
+
+	t.Setenv("DO_NOT_TRACK", "1")
+	run()
+	if len(events) != 1 {
+		t.Errorf("telemetry sent despite DO_NOT_TRACK")
+	}
+}
@@ -4,11 +4,13 @@ package commands
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/internal/telemetry"
 	"github.com/andri/crook/pkg/cli"
-	"github.com/andri/crook/pkg/k8s"
 	"github.com/andri/crook/pkg/maintenance"
 	"github.com/spf13/cobra"
 )
@@ -20,6 +22,30 @@ type UpOptions struct {
 
 	// Yes skips the confirmation prompt
 	Yes bool
+
+	// ReportDir receives Markdown and HTML reports of the run when set
+	ReportDir string
+
+	// Headless holds --summary-path, --wait and --wait-health-ok
+	Headless HeadlessOptions
+
+	// Prefixes limits the run to scaled-down deployments whose names start
+	// with one of these prefixes
+	Prefixes []string
+
+	// Exclude extends discovery.exclude with deployments never to scale
+	Exclude []string
+
+	// SignOff links the run to its change request (--reason, --ticket)
+	SignOff maintenance.SignOff
+
+	// SmokeTest writes and reads back data through the configured storage
+	// classes before closing the maintenance
+	SmokeTest bool
+
+	// NoOutScope overrides ceph.noout-scope when the node's maintenance
+	// report did not record the scope the down phase set noout on
+	NoOutScope string
 }
 
 // newUpCmd creates the up subcommand
@@ -35,9 +61,27 @@ This command performs the following steps:
   1. Validates pre-flight conditions (node exists, etc.)
   2. Discovers scaled-down node-pinned deployments
   3. Uncordons the node (marks it schedulable again)
-  4. Restores Rook-Ceph deployments to 1 replica
+  4. Restores Rook-Ceph deployments to their original replica count (limited
+     to names starting with a --prefix, when given, and skipping
+     discovery.exclude or --exclude), then node-pinned StatefulSets when
+     discovery.statefulsets is set. The count comes from the open maintenance
+     report, else the crook.io/original-replicas annotation written by
+     'crook down', else 1
   5. Scales up the rook-ceph-operator
-  6. Unsets the Ceph 'noout' flag
+  6. Unsets the Ceph 'noout' flag on the scope 'crook down' set it on, as
+     recorded in the maintenance report (else --noout-scope or
+     ceph.noout-scope), and deletes the node's noout watchdog Job
+  7. Optionally smoke tests the storage: a small PVC and pod per
+     smoke-test.storage-classes entry, pinned to the node, write and read
+     back data, then are deleted (--smoke-test or smoke-test.enabled)
+
+The up phase removes the crook.io/reason and crook.io/ticket node annotations
+set by 'crook down'; --reason and --ticket given here are recorded in the
+maintenance report.
+
+For pipelines, --wait blocks until the node is verified up (plus HEALTH_OK with
+--wait-health-ok) within --timeout, and --summary-path writes a JSON summary of
+the run.
 
 This command should be run after 'crook down <node>' and after node maintenance
 is complete.`,
@@ -48,8 +92,29 @@ is complete.`,
   crook up -y worker-1
 
   # Set a timeout for the operation
-  crook up worker-1 --timeout 15m`,
+  crook up worker-1 --timeout 15m
+
+  # Only restore the node's OSDs
+  crook up worker-1 --prefix rook-ceph-osd
+
+  # Prove the node can mount Ceph volumes again before closing the maintenance
+  crook up worker-1 --smoke-test
+
+  # Record the change request that closes the maintenance
+  crook up worker-1 --ticket CHG-1234 --reason "disk replaced"
+
+  # In a pipeline: block until the cluster is healthy again, keep a summary
+  crook up -y worker-1 --wait-health-ok --timeout 30m --summary-path up.json`,
 		Args: cobra.ExactArgs(1),
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if err := validatePrefixes(opts.Prefixes); err != nil {
+				return err
+			}
+			if err := validateNoOutScope(opts.NoOutScope); err != nil {
+				return err
+			}
+			return validateExcludes(opts.Exclude)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			nodeName := args[0]
 			return runUp(cmd, nodeName, opts)
@@ -62,6 +127,16 @@ is complete.`,
 		"timeout for the overall operation")
 	flags.BoolVarP(&opts.Yes, "yes", "y", false,
 		"skip confirmation prompt")
+	flags.StringVar(&opts.ReportDir, "report-dir", "",
+		"write Markdown and HTML reports of the run to this directory")
+	flags.BoolVar(&opts.SmokeTest, "smoke-test", false,
+		"write and read back data through smoke-test.storage-classes before closing the maintenance (same as smoke-test.enabled)")
+	addPrefixFlag(flags, &opts.Prefixes, "only restore scaled-down deployments whose names start with this prefix")
+	addExcludeFlag(flags, &opts.Exclude)
+	addSignOffFlags(flags, &opts.SignOff)
+	addHeadlessFlags(flags, &opts.Headless)
+	addNoOutScopeFlag(flags, &opts.NoOutScope,
+		"scope to unset noout on when the maintenance report recorded none: cluster, host or osd; overrides ceph.noout-scope")
 
 	return cmd
 }
@@ -70,6 +145,16 @@ is complete.`,
 func runUp(cmd *cobra.Command, nodeName string, opts *UpOptions) error {
 	cfg := GlobalOptions.Config
 	ctx := cmd.Context()
+	cfg.Discovery.Exclude = append(slices.Clone(cfg.Discovery.Exclude), opts.Exclude...)
+	if opts.NoOutScope != "" {
+		cfg.Ceph.NoOutScope = opts.NoOutScope
+	}
+	if opts.SmokeTest {
+		if len(cfg.SmokeTest.StorageClasses) == 0 {
+			return fmt.Errorf("--smoke-test needs smoke-test.storage-classes in the configuration")
+		}
+		cfg.SmokeTest.Enabled = true
+	}
 
 	// Apply timeout to context
 	if opts.Timeout > 0 {
@@ -80,43 +165,53 @@ func runUp(cmd *cobra.Command, nodeName string, opts *UpOptions) error {
 
 	// Initialize Kubernetes client
 	logger.Info("connecting to kubernetes cluster")
-	client, err := newK8sClient(ctx, k8s.ClientConfig{
-		CephCommandTimeout: time.Duration(cfg.Timeouts.CephCommandTimeoutSeconds) * time.Second,
-	})
+	client, err := newK8sClient(ctx, k8sClientConfig(cfg))
 	if err != nil {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
-	// Validate node exists
-	exists, err := client.NodeExists(ctx, nodeName)
+	// Resolve partial node names, confirming fuzzy matches and asking which
+	// node is meant when ambiguous
+	nodeName, err = resolveNodeName(ctx, cmd, client, nodeName, actOnNodeName(opts.Yes))
 	if err != nil {
-		return fmt.Errorf("failed to check if node %q exists: %w", nodeName, err)
-	}
-	if !exists {
-		return fmt.Errorf("node %q not found in cluster", nodeName)
+		return err
 	}
 
 	// Discover scaled-down deployments to show summary
-	deployments, err := client.ListScaledDownDeploymentsForNode(ctx, cfg.Namespace, nodeName)
+	discovered, err := client.ListScaledDownDeploymentsForNode(ctx, cfg.Namespace, nodeName)
 	if err != nil {
 		return fmt.Errorf("failed to discover deployments: %w", err)
 	}
+	deployments, excluded := maintenance.ExcludeDeployments(
+		maintenance.FilterByPrefixes(discovered, opts.Prefixes), cfg.Discovery)
+	statefulSets, excludedSets, err := maintenance.DiscoverScaledDownStatefulSets(ctx, client, cfg, nodeName, opts.Prefixes)
+	if err != nil {
+		return err
+	}
 
 	pw := cli.NewProgressWriter(cmd.OutOrStdout())
+	pw.SetQuiet(GlobalOptions.Quiet)
 
-	if maintenance.IsInUpState(ctx, client, cfg, nodeName, deployments) || len(deployments) == 0 {
+	// External clusters never have scaled-down deployments, only a cordon and noout to undo
+	external := maintenance.IsExternalCluster(ctx, client, cfg)
+	if maintenance.IsInUpState(ctx, client, cfg, nodeName, deployments) || (len(deployments) == 0 && len(statefulSets) == 0 && !external) {
 		pw.PrintSuccess(fmt.Sprintf("Node %s is already operational (uncordoned, noout unset, operator running)", nodeName))
-		return nil
-	}
-
-	// Build deployment names for display
-	var deploymentNames []string
-	for _, d := range deployments {
-		deploymentNames = append(deploymentNames, fmt.Sprintf("%s/%s", d.Namespace, d.Name))
+		return finishUnchanged(ctx, client, cfg, pw, nodeName, maintenance.ScalePhaseUp, opts.Prefixes, opts.Headless)
 	}
 
 	// Show summary
-	pw.PrintSummary(nodeName, len(deployments), deploymentNames)
+	pw.PrintSummary(nodeName, len(deployments), deploymentNames(deployments))
+	pw.PrintStatefulSets(statefulSetNames(statefulSets))
+	pw.PrintPrefixes(opts.Prefixes)
+	pw.PrintExcluded(excludedNames(excluded, excludedSets))
+	printSignOff(cmd, opts.SignOff)
+	printNoOutScope(ctx, cmd, client, cfg, nodeName, maintenance.UpNoOutScope)
+	if cfg.SmokeTest.Enabled && !GlobalOptions.Quiet {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Storage will be smoke tested on the node: %s\n", strings.Join(cfg.SmokeTest.StorageClasses, ", "))
+	}
+	if external && !GlobalOptions.Quiet {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), maintenance.ExternalClusterNote)
+	}
 
 	// Confirm unless -y
 	if !opts.Yes {
@@ -129,21 +224,47 @@ func runUp(cmd *cobra.Command, nodeName string, opts *UpOptions) error {
 			return fmt.Errorf("confirmation failed: %w", confirmErr)
 		}
 		if !confirmed {
-			return fmt.Errorf("operation cancelled by user")
+			return withExitCode(ExitCodeDeclined, fmt.Errorf("operation cancelled by user"))
+		}
+	}
+
+	// Record the run's timeline when reports or telemetry are requested
+	progress := pw.OnUpProgress
+	var recorder *maintenance.FlowRecorder
+	reports := opts.ReportDir != "" || opts.Headless.SummaryPath != ""
+	if reports || telemetry.Enabled() {
+		recorder = maintenance.NewFlowRecorder(nodeName, "up")
+		recorder.SetSignOff(opts.SignOff)
+		if reports {
+			recorder.SampleHealth(ctx, client, cfg)
+		}
+		progress = func(p maintenance.UpPhaseProgress) {
+			pw.OnUpProgress(p)
+			recorder.OnUpProgress(p)
 		}
 	}
 
 	// Execute the up phase with progress callback
 	// Pass discovered deployments to ensure consistency between confirmation and execution
 	executeErr := executeUpPhase(ctx, client, cfg, nodeName, maintenance.UpPhaseOptions{
-		ProgressCallback: pw.OnUpProgress,
+		ProgressCallback: progress,
 		Deployments:      deployments,
+		StatefulSets:     statefulSets,
+		Prefixes:         opts.Prefixes,
+		SignOff:          opts.SignOff,
 	})
+	if executeErr == nil {
+		executeErr = waitForEndState(ctx, client, cfg, pw, nodeName, maintenance.ScalePhaseUp, opts.Prefixes, opts.Headless)
+	}
+	if recorder != nil {
+		writeFlowReports(ctx, client, cfg, pw, opts.ReportDir, opts.Headless.SummaryPath, recorder, executeErr)
+	}
 	if executeErr != nil {
 		pw.PrintError(fmt.Sprintf("Up phase failed: %s", executeErr.Error()))
-		return executeErr
+		return withExitCode(phaseExitCode(ctx, executeErr), executeErr)
 	}
 
 	pw.PrintSuccess(fmt.Sprintf("Node %s has been restored and is operational", nodeName))
+	printVolumeWarnings(ctx, client, pw, nodeName, maintenance.ScalePhaseUp)
 	return nil
 }
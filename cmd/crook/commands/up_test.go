@@ -60,7 +60,7 @@ func TestUpCmdHasRequiredFlags(t *testing.T) {
 		}
 	}
 
-	expectedFlags := []string{"timeout", "yes"}
+	expectedFlags := []string{"timeout", "yes", "report-dir", "smoke-test", "noout-scope", "prefix", "exclude", "summary-path", "wait", "wait-health-ok"}
 
 	for _, flagName := range expectedFlags {
 		found := false
@@ -2,10 +2,12 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/andri/crook/cmd/crook/commands"
+	crookerrors "github.com/andri/crook/pkg/errors"
 )
 
 // These variables are set at build time via ldflags
@@ -21,7 +23,13 @@ func main() {
 
 	// Execute the root command
 	if err := commands.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		var exitErr *commands.ExitError
+		if !errors.As(err, &exitErr) || !exitErr.Silent() {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			if hint := crookerrors.Hint(err); hint != "" {
+				fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
+			}
+		}
+		os.Exit(commands.ExitCode(err))
 	}
 }
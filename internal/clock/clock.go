@@ -0,0 +1,65 @@
+// Package clock abstracts the time source of monitors and TUI models
+// (elapsed timers, staleness checks, poll backoff) so tests can advance time
+// deterministically with Fake instead of sleeping on real intervals.
+package clock
+
+import "time"
+
+// Clock tells the time and creates timers
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// Since returns the time elapsed since t
+	Since(t time.Time) time.Duration
+
+	// Until returns the duration until t
+	Until(t time.Time) time.Duration
+
+	// NewTimer creates a timer that fires once after d
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the subset of time.Timer used by crook
+type Timer interface {
+	// C returns the channel the timer fires on
+	C() <-chan time.Time
+
+	// Reset changes the timer to fire after d; see time.Timer.Reset
+	Reset(d time.Duration) bool
+
+	// Stop prevents the timer from firing; see time.Timer.Stop
+	Stop() bool
+}
+
+// Real returns the clock backed by the time package
+func Real() Clock {
+	return realClock{}
+}
+
+// OrReal returns c, or the real clock when c is nil, so config structs can
+// leave their clock unset
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real()
+	}
+	return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                  { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration { return time.Since(t) }
+func (realClock) Until(t time.Time) time.Duration { return time.Until(t) }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	t *time.Timer
+}
+
+func (r realTimer) C() <-chan time.Time        { return r.t.C }
+func (r realTimer) Reset(d time.Duration) bool { return r.t.Reset(d) }
+func (r realTimer) Stop() bool                 { return r.t.Stop() }
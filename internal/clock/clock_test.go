@@ -0,0 +1,32 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// stoppedClock is a Clock that never moves
+type stoppedClock struct {
+	realClock
+	now time.Time
+}
+
+func (c stoppedClock) Now() time.Time { return c.now }
+
+func TestOrReal(t *testing.T) {
+	if _, ok := OrReal(nil).(realClock); !ok {
+		t.Error("OrReal(nil) should return the real clock")
+	}
+	c := stoppedClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	if OrReal(c) != Clock(c) {
+		t.Error("OrReal() should keep a configured clock")
+	}
+}
+
+func TestReal_Timer(t *testing.T) {
+	timer := Real().NewTimer(time.Millisecond)
+	<-timer.C()
+	if timer.Stop() {
+		t.Error("Stop() of a fired timer should report it was not armed")
+	}
+}
@@ -0,0 +1,263 @@
+// Package faults fails chosen Kubernetes API requests and ceph commands on
+// demand, so integration tests and 'crook chaos-test' can exercise error,
+// rollback and re-authentication paths against a healthy cluster.
+//
+// Fault injection is only compiled into binaries built with the "faults" tag
+// (go build -tags faults). Rules come from the CROOK_FAULTS environment
+// variable or from Activate, as a comma-separated list of
+//
+//	<target>=<fault>[*<times>]
+//
+// where target is k8s:<verb>:<resource> (e.g. k8s:patch:deployments,
+// k8s:create:pods/eviction, k8s:*:nodes) or ceph:<command prefix> (e.g.
+// ceph:osd set noout), fault is error, timeout, unauthorized or forbidden,
+// and times limits how often the rule fires (default: every time).
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EnvVar holds the fault rules of a process
+const EnvVar = "CROOK_FAULTS"
+
+// Fault kinds
+const (
+	KindError        = "error"
+	KindTimeout      = "timeout"
+	KindUnauthorized = "unauthorized"
+	KindForbidden    = "forbidden"
+)
+
+// Rule targets
+const (
+	scopeK8s  = "k8s"
+	scopeCeph = "ceph"
+)
+
+// ErrDisabled is returned by Activate in binaries built without the faults tag
+var ErrDisabled = errors.New("crook was built without fault injection (rebuild with -tags faults)")
+
+// ErrInjected marks every error produced by a fault rule
+var ErrInjected = errors.New("injected fault")
+
+// Rule fails matching calls
+type Rule struct {
+	// Scope is "k8s" or "ceph"
+	Scope string
+
+	// Verb and Resource select Kubernetes requests; "*" matches any
+	Verb     string
+	Resource string
+
+	// Command is the ceph command prefix, without the leading "ceph"
+	Command string
+
+	// Kind is the fault returned
+	Kind string
+
+	// Times limits how often the rule fires; zero fires every time
+	Times int
+
+	fired int
+}
+
+// String returns the rule in CROOK_FAULTS syntax
+func (r *Rule) String() string {
+	target := scopeCeph + ":" + r.Command
+	if r.Scope == scopeK8s {
+		target = scopeK8s + ":" + r.Verb + ":" + r.Resource
+	}
+	s := target + "=" + r.Kind
+	if r.Times > 0 {
+		s += "*" + strconv.Itoa(r.Times)
+	}
+	return s
+}
+
+// Injector holds the active rules. It is safe for concurrent use.
+type Injector struct {
+	mu    sync.Mutex
+	rules []*Rule
+}
+
+// Parse parses rules in CROOK_FAULTS syntax
+func Parse(spec string) (*Injector, error) {
+	injector := &Injector{}
+	for part := range strings.SplitSeq(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		rule, err := parseRule(part)
+		if err != nil {
+			return nil, err
+		}
+		injector.rules = append(injector.rules, rule)
+	}
+	return injector, nil
+}
+
+func parseRule(s string) (*Rule, error) {
+	target, fault, ok := strings.Cut(s, "=")
+	if !ok {
+		return nil, fmt.Errorf("fault rule %q: expected <target>=<fault>", s)
+	}
+
+	rule := &Rule{}
+	kind, times, limited := strings.Cut(strings.TrimSpace(fault), "*")
+	if limited {
+		n, err := strconv.Atoi(times)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("fault rule %q: times must be a positive number", s)
+		}
+		rule.Times = n
+	}
+	switch kind {
+	case KindError, KindTimeout, KindUnauthorized, KindForbidden:
+		rule.Kind = kind
+	default:
+		return nil, fmt.Errorf("fault rule %q: unknown fault %q (want error, timeout, unauthorized or forbidden)", s, kind)
+	}
+
+	scope, rest, _ := strings.Cut(strings.TrimSpace(target), ":")
+	switch scope {
+	case scopeK8s:
+		verb, resource, ok := strings.Cut(rest, ":")
+		if !ok || verb == "" || resource == "" {
+			return nil, fmt.Errorf("fault rule %q: expected k8s:<verb>:<resource>", s)
+		}
+		rule.Scope, rule.Verb, rule.Resource = scopeK8s, verb, resource
+	case scopeCeph:
+		command := strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(rest), "ceph ")), " ")
+		if command == "" {
+			return nil, fmt.Errorf("fault rule %q: expected ceph:<command>", s)
+		}
+		rule.Scope, rule.Command = scopeCeph, command
+	default:
+		return nil, fmt.Errorf("fault rule %q: target must start with k8s: or ceph:", s)
+	}
+	return rule, nil
+}
+
+// String returns the rules in CROOK_FAULTS syntax
+func (i *Injector) String() string {
+	if i == nil {
+		return ""
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	rules := make([]string, len(i.rules))
+	for n, r := range i.rules {
+		rules[n] = r.String()
+	}
+	return strings.Join(rules, ",")
+}
+
+// K8sFault returns the fault kind of the first rule matching a Kubernetes
+// request, or "" when the request goes through
+func (i *Injector) K8sFault(verb, resource string) string {
+	return i.fire(func(r *Rule) bool {
+		return r.Scope == scopeK8s && matches(r.Verb, verb) && matches(r.Resource, resource)
+	})
+}
+
+// CephFault returns the error of the first rule matching a ceph command, or
+// nil when the command runs
+func (i *Injector) CephFault(command []string) error {
+	invocation := strings.Join(command, " ")
+	invocation = strings.TrimPrefix(invocation, "ceph ")
+	kind := i.fire(func(r *Rule) bool {
+		return r.Scope == scopeCeph && (invocation == r.Command || strings.HasPrefix(invocation, r.Command+" "))
+	})
+	desc := "ceph " + invocation
+	switch kind {
+	case "":
+		return nil
+	case KindTimeout:
+		return fmt.Errorf("%w: %s: %w", ErrInjected, desc, context.DeadlineExceeded)
+	case KindUnauthorized, KindForbidden:
+		return fmt.Errorf("%w: %w", ErrInjected, statusError(kind, "pods/exec", desc))
+	default:
+		return fmt.Errorf("%w: %s failed", ErrInjected, desc)
+	}
+}
+
+// fire returns the kind of the first matching rule that has not used up its
+// times, counting the firing
+func (i *Injector) fire(match func(*Rule) bool) string {
+	if i == nil {
+		return ""
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, r := range i.rules {
+		if !match(r) || (r.Times > 0 && r.fired >= r.Times) {
+			continue
+		}
+		r.fired++
+		return r.Kind
+	}
+	return ""
+}
+
+func matches(pattern, value string) bool {
+	return pattern == "*" || pattern == value
+}
+
+var (
+	activeOnce sync.Once
+	activeMu   sync.Mutex
+	active     *Injector
+	activeErr  error
+)
+
+// Active returns the injector of the process: the rules passed to Activate,
+// else those in CROOK_FAULTS. It returns nil when no rules are set or the
+// binary was built without the faults tag.
+func Active() (*Injector, error) {
+	if !Enabled {
+		return nil, nil
+	}
+	activeOnce.Do(func() {
+		spec := os.Getenv(EnvVar)
+		if spec == "" {
+			return
+		}
+		injector, err := Parse(spec)
+		activeMu.Lock()
+		defer activeMu.Unlock()
+		if active == nil {
+			active, activeErr = injector, err
+		}
+	})
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	if active == nil || len(active.rules) == 0 {
+		return nil, activeErr
+	}
+	return active, activeErr
+}
+
+// Activate replaces the rules of the process; clients created afterwards
+// inject them
+func Activate(spec string) error {
+	if !Enabled {
+		return ErrDisabled
+	}
+	injector, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+	activeOnce.Do(func() {})
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	active, activeErr = injector, nil
+	return nil
+}
@@ -0,0 +1,177 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty", spec: "", want: ""},
+		{name: "k8s rule", spec: "k8s:patch:deployments=error", want: "k8s:patch:deployments=error"},
+		{name: "ceph rule with times", spec: " ceph:ceph osd  set noout=timeout*2 ", want: "ceph:osd set noout=timeout*2"},
+		{name: "several rules", spec: "k8s:*:nodes=unauthorized,ceph:status=forbidden", want: "k8s:*:nodes=unauthorized,ceph:status=forbidden"},
+		{name: "missing fault", spec: "k8s:get:pods", wantErr: true},
+		{name: "unknown fault", spec: "k8s:get:pods=explode", wantErr: true},
+		{name: "bad times", spec: "k8s:get:pods=error*0", wantErr: true},
+		{name: "missing resource", spec: "k8s:get=error", wantErr: true},
+		{name: "unknown target", spec: "etcd:put=error", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector, err := Parse(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) should fail", tt.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.spec, err)
+			}
+			if got := injector.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInjector_K8sFault(t *testing.T) {
+	injector, err := Parse("k8s:patch:deployments=error*1,k8s:*:nodes=timeout")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got := injector.K8sFault("patch", "deployments"); got != KindError {
+		t.Errorf("first patch fault = %q, want %q", got, KindError)
+	}
+	if got := injector.K8sFault("patch", "deployments"); got != "" {
+		t.Errorf("second patch fault = %q, want none after the rule used up its times", got)
+	}
+	if got := injector.K8sFault("list", "nodes"); got != KindTimeout {
+		t.Errorf("list nodes fault = %q, want %q", got, KindTimeout)
+	}
+	if got := injector.K8sFault("get", "pods"); got != "" {
+		t.Errorf("get pods fault = %q, want none", got)
+	}
+
+	var none *Injector
+	if got := none.K8sFault("get", "pods"); got != "" {
+		t.Errorf("nil injector fault = %q, want none", got)
+	}
+}
+
+func TestInjector_CephFault(t *testing.T) {
+	injector, err := Parse("ceph:osd set=error,ceph:status=timeout,ceph:osd tree=unauthorized")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if err := injector.CephFault([]string{"ceph", "osd", "set", "noout"}); !errors.Is(err, ErrInjected) {
+		t.Errorf("osd set error = %v, want an injected fault", err)
+	}
+	if err := injector.CephFault([]string{"ceph", "osd", "setcrushmap"}); err != nil {
+		t.Errorf("a prefix must match whole words, got %v", err)
+	}
+	if err := injector.CephFault([]string{"ceph", "status"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("status error = %v, want a deadline", err)
+	}
+	if err := injector.CephFault([]string{"ceph", "osd", "tree"}); !apierrors.IsUnauthorized(err) {
+		t.Errorf("osd tree error = %v, want unauthorized", err)
+	}
+}
+
+func TestRequestInfo(t *testing.T) {
+	tests := []struct {
+		method       string
+		path         string
+		wantVerb     string
+		wantResource string
+	}{
+		{method: http.MethodGet, path: "/api/v1/nodes", wantVerb: "list", wantResource: "nodes"},
+		{method: http.MethodGet, path: "/api/v1/nodes/worker-1", wantVerb: "get", wantResource: "nodes"},
+		{method: http.MethodGet, path: "/api/v1/namespaces/rook-ceph/pods?watch=true", wantVerb: "watch", wantResource: "pods"},
+		{method: http.MethodPost, path: "/api/v1/namespaces/rook-ceph/pods/osd-0/eviction", wantVerb: "create", wantResource: "pods/eviction"},
+		{method: http.MethodPatch, path: "/apis/apps/v1/namespaces/rook-ceph/deployments/rook-ceph-osd-0/scale", wantVerb: "patch", wantResource: "deployments/scale"},
+		{method: http.MethodDelete, path: "/api/v1/namespaces/rook-ceph/pods", wantVerb: "deletecollection", wantResource: "pods"},
+		{method: http.MethodGet, path: "/api/v1/namespaces/rook-ceph", wantVerb: "get", wantResource: "namespaces"},
+		{method: http.MethodGet, path: "/version", wantVerb: "", wantResource: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			verb, resource := requestInfo(req)
+			if verb != tt.wantVerb || resource != tt.wantResource {
+				t.Errorf("requestInfo() = (%q, %q), want (%q, %q)", verb, resource, tt.wantVerb, tt.wantResource)
+			}
+		})
+	}
+}
+
+func TestWrapTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"Node","apiVersion":"v1","metadata":{"name":"worker-1"}}`))
+	}))
+	defer srv.Close()
+
+	injector, err := Parse("k8s:get:nodes=unauthorized*1,k8s:patch:nodes=forbidden")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	config := &rest.Config{Host: srv.URL}
+	config.Wrap(injector.WrapTransport)
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("NewForConfig() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := clientset.CoreV1().Nodes().Get(ctx, "worker-1", metav1.GetOptions{}); !apierrors.IsUnauthorized(err) {
+		t.Fatalf("first Get() error = %v, want unauthorized", err)
+	}
+	node, err := clientset.CoreV1().Nodes().Get(ctx, "worker-1", metav1.GetOptions{})
+	if err != nil || node.Name != "worker-1" {
+		t.Fatalf("second Get() = %v, %v; want the node from the server", node, err)
+	}
+	_, err = clientset.CoreV1().Nodes().Patch(ctx, "worker-1", "application/merge-patch+json", []byte(`{}`), metav1.PatchOptions{})
+	if !apierrors.IsForbidden(err) {
+		t.Errorf("Patch() error = %v, want forbidden", err)
+	}
+}
+
+func TestActivate(t *testing.T) {
+	err := Activate("k8s:get:nodes=error")
+	if !Enabled {
+		if !errors.Is(err, ErrDisabled) {
+			t.Errorf("Activate() error = %v, want ErrDisabled", err)
+		}
+		if injector, _ := Active(); injector != nil {
+			t.Error("Active() should be nil without the faults tag")
+		}
+		return
+	}
+
+	if err != nil {
+		t.Fatalf("Activate() error = %v", err)
+	}
+	injector, err := Active()
+	if err != nil || injector.String() != "k8s:get:nodes=error" {
+		t.Errorf("Active() = %v, %v; want the activated rules", injector, err)
+	}
+}
@@ -0,0 +1,119 @@
+package faults
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// WrapTransport returns a round tripper that answers Kubernetes API requests
+// matching a rule with the rule's error status instead of sending them. It
+// fits rest.Config.Wrap.
+func (i *Injector) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		verb, resource := requestInfo(req)
+		if resource == "" {
+			return rt.RoundTrip(req)
+		}
+		kind := i.K8sFault(verb, resource)
+		if kind == "" {
+			return rt.RoundTrip(req)
+		}
+		return statusResponse(req, statusError(kind, resource, verb+" "+resource))
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// requestInfo returns the Kubernetes verb and resource of an API request,
+// e.g. ("create", "pods/eviction"). The resource is empty for non-resource
+// paths such as /version.
+func requestInfo(req *http.Request) (verb, resource string) {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(segments) >= 3 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 4 && segments[0] == "apis":
+		segments = segments[3:]
+	default:
+		return "", ""
+	}
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		segments = segments[2:]
+	}
+
+	resource = segments[0]
+	named := len(segments) >= 2
+	if len(segments) >= 3 {
+		resource += "/" + segments[2]
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		switch {
+		case req.URL.Query().Get("watch") == "true":
+			verb = "watch"
+		case named:
+			verb = "get"
+		default:
+			verb = "list"
+		}
+	case http.MethodPost:
+		verb = "create"
+	case http.MethodPut:
+		verb = "update"
+	case http.MethodPatch:
+		verb = "patch"
+	case http.MethodDelete:
+		verb = "delete"
+		if !named {
+			verb = "deletecollection"
+		}
+	default:
+		verb = strings.ToLower(req.Method)
+	}
+	return verb, resource
+}
+
+// statusError returns the API error a fault kind stands for
+func statusError(kind, resource, desc string) *apierrors.StatusError {
+	msg := fmt.Sprintf("%s: %s", ErrInjected, desc)
+	switch kind {
+	case KindTimeout:
+		return apierrors.NewTimeoutError(msg, 0)
+	case KindUnauthorized:
+		return apierrors.NewUnauthorized(msg)
+	case KindForbidden:
+		return apierrors.NewForbidden(schema.GroupResource{Resource: resource}, "", errors.New(msg))
+	default:
+		return apierrors.NewInternalError(errors.New(msg))
+	}
+}
+
+// statusResponse encodes err as the API server would
+func statusResponse(req *http.Request, err *apierrors.StatusError) (*http.Response, error) {
+	status := err.ErrStatus
+	status.Kind, status.APIVersion = "Status", "v1"
+	body, marshalErr := json.Marshal(status)
+	if marshalErr != nil {
+		return nil, fmt.Errorf("failed to encode injected fault: %w", marshalErr)
+	}
+	return &http.Response{
+		StatusCode: int(status.Code),
+		Status:     fmt.Sprintf("%d %s", status.Code, http.StatusText(int(status.Code))),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,127 @@
+// Package metrics holds internal counters used to diagnose crook itself
+// (slow refreshes, failing sources, ceph exec latency, dropped messages).
+// They are published through expvar and served by --debug-listen.
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Timing accumulates duration observations. It implements expvar.Var.
+type Timing struct {
+	count atomic.Int64
+	total atomic.Int64 // nanoseconds
+	max   atomic.Int64 // nanoseconds
+	last  atomic.Int64 // nanoseconds
+}
+
+// Observe records one duration
+func (t *Timing) Observe(d time.Duration) {
+	n := int64(d)
+	t.count.Add(1)
+	t.total.Add(n)
+	t.last.Store(n)
+	for {
+		current := t.max.Load()
+		if n <= current || t.max.CompareAndSwap(current, n) {
+			return
+		}
+	}
+}
+
+// Count returns the number of observations
+func (t *Timing) Count() int64 {
+	return t.count.Load()
+}
+
+// Max returns the longest observed duration
+func (t *Timing) Max() time.Duration {
+	return time.Duration(t.max.Load())
+}
+
+// String renders the timing as JSON in milliseconds
+func (t *Timing) String() string {
+	count := t.count.Load()
+	avg := 0.0
+	if count > 0 {
+		avg = float64(t.total.Load()) / float64(count) / float64(time.Millisecond)
+	}
+	data, _ := json.Marshal(struct {
+		Count  int64   `json:"count"`
+		AvgMS  float64 `json:"avg_ms"`
+		MaxMS  float64 `json:"max_ms"`
+		LastMS float64 `json:"last_ms"`
+	}{
+		Count:  count,
+		AvgMS:  avg,
+		MaxMS:  float64(t.max.Load()) / float64(time.Millisecond),
+		LastMS: float64(t.last.Load()) / float64(time.Millisecond),
+	})
+	return string(data)
+}
+
+// Published metrics
+var (
+	// refreshDurations holds a Timing per monitor source (nodes, osds, header, ...)
+	refreshDurations = expvar.NewMap("crook_refresh_duration")
+
+	// refreshErrors counts failed refreshes per monitor source
+	refreshErrors = expvar.NewMap("crook_refresh_errors")
+
+	// cephExecLatency times ceph commands run in the toolbox pod
+	cephExecLatency = &Timing{}
+
+	// cephExecErrors counts failed ceph commands
+	cephExecErrors = expvar.NewInt("crook_ceph_exec_errors")
+
+	// droppedMessages counts updates discarded because a consumer was not keeping up
+	droppedMessages = expvar.NewMap("crook_dropped_messages")
+
+	// timingsMu serializes creation of per-source timings
+	timingsMu sync.Mutex
+)
+
+func init() {
+	expvar.Publish("crook_ceph_exec_latency", cephExecLatency)
+}
+
+// ObserveRefresh records one monitor refresh of source
+func ObserveRefresh(source string, d time.Duration, err error) {
+	timing(refreshDurations, source).Observe(d)
+	if err != nil {
+		refreshErrors.Add(source, 1)
+	}
+}
+
+// ObserveCephExec records one ceph command execution
+func ObserveCephExec(d time.Duration, err error) {
+	cephExecLatency.Observe(d)
+	if err != nil {
+		cephExecErrors.Add(1)
+	}
+}
+
+// DroppedMessage counts an update discarded on the named channel
+// (e.g. "ls-updates", "down-progress")
+func DroppedMessage(channel string) {
+	droppedMessages.Add(channel, 1)
+}
+
+// timing returns the Timing stored under key, creating it on first use
+func timing(m *expvar.Map, key string) *Timing {
+	if t, ok := m.Get(key).(*Timing); ok {
+		return t
+	}
+	timingsMu.Lock()
+	defer timingsMu.Unlock()
+	if t, ok := m.Get(key).(*Timing); ok {
+		return t
+	}
+	t := &Timing{}
+	m.Set(key, t)
+	return t
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTiming(t *testing.T) {
+	var timing Timing
+	timing.Observe(10 * time.Millisecond)
+	timing.Observe(30 * time.Millisecond)
+	timing.Observe(20 * time.Millisecond)
+
+	if timing.Count() != 3 {
+		t.Errorf("Count() = %d, want 3", timing.Count())
+	}
+	if timing.Max() != 30*time.Millisecond {
+		t.Errorf("Max() = %v, want 30ms", timing.Max())
+	}
+
+	var got struct {
+		Count  int64   `json:"count"`
+		AvgMS  float64 `json:"avg_ms"`
+		LastMS float64 `json:"last_ms"`
+	}
+	if err := json.Unmarshal([]byte(timing.String()), &got); err != nil {
+		t.Fatalf("String() is not JSON: %v", err)
+	}
+	if got.Count != 3 || got.AvgMS != 20 || got.LastMS != 20 {
+		t.Errorf("String() = %s, want count=3 avg=20 last=20", timing.String())
+	}
+}
+
+func TestHandlerServesMetrics(t *testing.T) {
+	ObserveRefresh("osds", 50*time.Millisecond, errors.New("exec failed"))
+	ObserveCephExec(40*time.Millisecond, nil)
+	DroppedMessage("ls-updates")
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var vars map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &vars); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	for _, name := range []string{"crook_refresh_duration", "crook_refresh_errors", "crook_ceph_exec_latency", "crook_ceph_exec_errors", "crook_dropped_messages"} {
+		if _, ok := vars[name]; !ok {
+			t.Errorf("missing metric %q", name)
+		}
+	}
+
+	var errs map[string]int
+	if err := json.Unmarshal(vars["crook_refresh_errors"], &errs); err != nil || errs["osds"] < 1 {
+		t.Errorf("crook_refresh_errors = %s, want osds >= 1", vars["crook_refresh_errors"])
+	}
+}
+
+func TestHandlerServesPprof(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+// StartProfiling writes a CPU profile to dir until the returned stop function
+// is called; stop then writes a heap profile next to it. Profile names carry
+// the start time so repeated runs do not overwrite each other.
+func StartProfiling(dir string) (stop func() error, err error) {
+	if mkErr := os.MkdirAll(dir, 0o750); mkErr != nil {
+		return nil, fmt.Errorf("failed to create profile directory %s: %w", dir, mkErr)
+	}
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	cpuPath := filepath.Join(dir, "crook-cpu-"+stamp+".pprof")
+	heapPath := filepath.Join(dir, "crook-heap-"+stamp+".pprof")
+
+	cpuFile, err := os.Create(cpuPath) //nolint:gosec // path is built from the user-provided profile directory
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+	}
+	if startErr := pprof.StartCPUProfile(cpuFile); startErr != nil {
+		_ = cpuFile.Close()
+		return nil, fmt.Errorf("failed to start CPU profile: %w", startErr)
+	}
+
+	stop = func() error {
+		pprof.StopCPUProfile()
+		cpuErr := cpuFile.Close()
+		return errors.Join(cpuErr, writeHeapProfile(heapPath))
+	}
+	return stop, nil
+}
+
+// writeHeapProfile writes a heap profile reflecting live objects
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path) //nolint:gosec // path is built from the user-provided profile directory
+	if err != nil {
+		return fmt.Errorf("failed to create heap profile: %w", err)
+	}
+	runtime.GC()
+	if writeErr := pprof.WriteHeapProfile(f); writeErr != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write heap profile: %w", writeErr)
+	}
+	return f.Close()
+}
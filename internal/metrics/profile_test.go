@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStartProfiling(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "profiles")
+
+	stop, err := StartProfiling(dir)
+	if err != nil {
+		t.Fatalf("StartProfiling() error = %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("stop() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read profile dir: %v", err)
+	}
+	var cpu, heap bool
+	for _, e := range entries {
+		cpu = cpu || strings.HasPrefix(e.Name(), "crook-cpu-")
+		heap = heap || strings.HasPrefix(e.Name(), "crook-heap-")
+	}
+	if !cpu || !heap {
+		t.Errorf("profiles = %v, want cpu and heap profiles", entries)
+	}
+}
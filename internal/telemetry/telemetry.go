@@ -0,0 +1,192 @@
+// Package telemetry reports anonymous feature usage to help the maintainers
+// prioritize: the command run, the names of the flags set, the duration and
+// failure stage of each maintenance flow, and the terminal size. It is off
+// unless telemetry.enabled is set, and DO_NOT_TRACK turns it off regardless.
+// Node, cluster and workload names, flag values and error messages are never
+// reported.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+)
+
+// sendTimeout bounds the report so telemetry never delays the exit noticeably
+const sendTimeout = 2 * time.Second
+
+// Flow outcomes
+const (
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+)
+
+// Event is the report of one crook run
+type Event struct {
+	Version string `json:"version"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+
+	// Command is the command path without "crook", e.g. "down" or "pvc ls";
+	// empty for the TUI
+	Command string `json:"command"`
+
+	// Flags are the names of the flags set, without their values
+	Flags []string `json:"flags,omitempty"`
+
+	ExitCode   int   `json:"exit_code"`
+	DurationMS int64 `json:"duration_ms"`
+
+	// Flows are the maintenance flows run, in order
+	Flows []Flow `json:"flows,omitempty"`
+
+	// Terminal is the last known terminal size, if crook ran in one
+	Terminal *Terminal `json:"terminal,omitempty"`
+}
+
+// Flow is one down or up flow of a run
+type Flow struct {
+	// Phase is the flow, e.g. "down" or "up"
+	Phase string `json:"phase"`
+
+	// Outcome is OutcomeSucceeded or OutcomeFailed
+	Outcome    string `json:"outcome"`
+	DurationMS int64  `json:"duration_ms"`
+
+	// FailureStage is the progress stage a failed flow stopped at, e.g. "noout"
+	FailureStage string `json:"failure_stage,omitempty"`
+}
+
+// Terminal is a terminal size in cells
+type Terminal struct {
+	Columns int `json:"columns"`
+	Rows    int `json:"rows"`
+}
+
+// session is the run being recorded
+type session struct {
+	endpoint string
+	started  time.Time
+	event    Event
+}
+
+var (
+	mu      sync.Mutex
+	current *session
+
+	// httpClient sends the reports; replaced in tests
+	httpClient = &http.Client{Timeout: sendTimeout}
+)
+
+// Start begins recording a run of command with the given flags set, when
+// telemetry is enabled and DO_NOT_TRACK is not set
+func Start(cfg config.TelemetryConfig, version, command string, flags []string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	current = nil
+	if !cfg.Enabled || doNotTrack() {
+		return
+	}
+	current = &session{
+		endpoint: cfg.Endpoint,
+		started:  time.Now(),
+		event: Event{
+			Version: version,
+			OS:      runtime.GOOS,
+			Arch:    runtime.GOARCH,
+			Command: command,
+			Flags:   slices.Sorted(slices.Values(flags)),
+		},
+	}
+}
+
+// Enabled reports whether the current run is recorded
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return current != nil
+}
+
+// RecordFlow adds a finished maintenance flow to the run
+func RecordFlow(flow Flow) {
+	mu.Lock()
+	defer mu.Unlock()
+	if current != nil {
+		current.event.Flows = append(current.event.Flows, flow)
+	}
+}
+
+// RecordTerminal records the terminal size, e.g. after a resize
+func RecordTerminal(columns, rows int) {
+	mu.Lock()
+	defer mu.Unlock()
+	if current != nil && columns > 0 && rows > 0 {
+		current.event.Terminal = &Terminal{Columns: columns, Rows: rows}
+	}
+}
+
+// Finish ends the run with its exit code and sends its report. Failures are
+// only logged at debug level; telemetry never affects the run.
+func Finish(ctx context.Context, exitCode int) {
+	mu.Lock()
+	run := current
+	current = nil
+	mu.Unlock()
+	if run == nil {
+		return
+	}
+
+	run.event.ExitCode = exitCode
+	run.event.DurationMS = time.Since(run.started).Milliseconds()
+	if err := send(ctx, run.endpoint, run.event); err != nil {
+		logger.Debug("failed to send telemetry", "error", err)
+	}
+}
+
+// send posts the event as JSON to the endpoint
+func send(ctx context.Context, endpoint string, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+	logger.Debug("sending telemetry", "endpoint", endpoint, "event", string(data))
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", endpoint, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("POST %s: %s", endpoint, resp.Status)
+	}
+	return nil
+}
+
+// doNotTrack reports whether DO_NOT_TRACK opts out of telemetry
+func doNotTrack() bool {
+	value := strings.TrimSpace(os.Getenv("DO_NOT_TRACK"))
+	return value != "" && value != "0" && !strings.EqualFold(value, "false")
+}
@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+)
+
+// collector records the events posted to a test endpoint
+func collector(t *testing.T) (*httptest.Server, *[]Event) {
+	t.Helper()
+	var events []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("invalid telemetry body: %v", err)
+		}
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+	return server, &events
+}
+
+func TestTelemetryReportsRun(t *testing.T) {
+	t.Setenv("DO_NOT_TRACK", "")
+	server, events := collector(t)
+
+	Start(config.TelemetryConfig{Enabled: true, Endpoint: server.URL}, "v1.2.0", "down", []string{"yes", "drain"})
+	if !Enabled() {
+		t.Fatal("expected telemetry to be enabled")
+	}
+	RecordTerminal(120, 40)
+	RecordFlow(Flow{Phase: "down", Outcome: OutcomeFailed, DurationMS: 1500, FailureStage: "noout"})
+	Finish(context.Background(), 1)
+
+	if len(*events) != 1 {
+		t.Fatalf("got %d events, want 1", len(*events))
+	}
+	event := (*events)[0]
+	if event.Version != "v1.2.0" || event.Command != "down" || event.ExitCode != 1 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if !slices.Equal(event.Flags, []string{"drain", "yes"}) {
+		t.Errorf("Flags = %v, want sorted flag names", event.Flags)
+	}
+	if event.Terminal == nil || *event.Terminal != (Terminal{Columns: 120, Rows: 40}) {
+		t.Errorf("Terminal = %+v", event.Terminal)
+	}
+	if len(event.Flows) != 1 || event.Flows[0].FailureStage != "noout" {
+		t.Errorf("Flows = %+v", event.Flows)
+	}
+	if Enabled() {
+		t.Error("expected Finish to end the run")
+	}
+}
+
+func TestTelemetryOptIn(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		doNotTrack string
+	}{
+		{name: "disabled by default", enabled: false},
+		{name: "DO_NOT_TRACK", enabled: true, doNotTrack: "1"},
+		{name: "DO_NOT_TRACK true", enabled: true, doNotTrack: "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DO_NOT_TRACK", tt.doNotTrack)
+			server, events := collector(t)
+
+			Start(config.TelemetryConfig{Enabled: tt.enabled, Endpoint: server.URL}, "v1.2.0", "ls", nil)
+			RecordFlow(Flow{Phase: "up", Outcome: OutcomeSucceeded})
+			Finish(context.Background(), 0)
+
+			if Enabled() || len(*events) != 0 {
+				t.Errorf("expected no telemetry, got %d events", len(*events))
+			}
+		})
+	}
+}
+
+func TestTelemetryFailureIsIgnored(t *testing.T) {
+	t.Setenv("DO_NOT_TRACK", "")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := send(context.Background(), server.URL, Event{Command: "ls"}); err == nil {
+		t.Error("expected send to report the server error")
+	}
+
+	// Finish only logs the failure
+	Start(config.TelemetryConfig{Enabled: true, Endpoint: server.URL}, "v1.2.0", "ls", nil)
+	Finish(context.Background(), 0)
+}
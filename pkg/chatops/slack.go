@@ -0,0 +1,466 @@
+// Package chatops lets maintenance be driven from chat. Commands run through the
+// same maintenance engine as the CLI, TUI, and HTTP API.
+package chatops
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/server"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// Action IDs for the approval buttons
+const (
+	actionApprove = "crook_approve"
+	actionCancel  = "crook_cancel"
+)
+
+// acknowledgeWarningsWord in a down command proceeds past pre-flight checks
+// that only warn, like 'crook down --acknowledge-warnings'
+const acknowledgeWarningsWord = "acknowledge-warnings"
+
+// Engine is the maintenance engine used by chat integrations.
+// *server.Server implements it.
+type Engine interface {
+	Plan(ctx context.Context, nodeName, phase string) (*server.Plan, error)
+	StartDown(nodeName string, signOff maintenance.SignOff, acknowledgeWarnings bool) (server.OperationStatus, error)
+	StartUp(nodeName string, signOff maintenance.SignOff) (server.OperationStatus, error)
+	Subscribe(ctx context.Context, id string, fn func(server.ProgressEvent)) error
+	Operation(id string) (server.OperationStatus, bool)
+}
+
+// Poster posts and updates Slack messages. *slack.Client implements it.
+type Poster interface {
+	PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error)
+	UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+}
+
+// SlackOptions configures the Slack bot
+type SlackOptions struct {
+	// Engine runs maintenance operations
+	Engine Engine
+
+	// Poster posts messages to Slack
+	Poster Poster
+
+	// AllowedChannels restricts commands to these channel IDs. Empty allows all channels.
+	AllowedChannels []string
+}
+
+// SlackBot handles `/crook` slash commands and approval buttons
+type SlackBot struct {
+	engine          Engine
+	poster          Poster
+	allowedChannels map[string]bool
+
+	// wg tracks goroutines streaming progress into threads
+	wg sync.WaitGroup
+}
+
+// NewSlackBot creates a Slack bot
+func NewSlackBot(opts SlackOptions) *SlackBot {
+	allowed := make(map[string]bool, len(opts.AllowedChannels))
+	for _, ch := range opts.AllowedChannels {
+		allowed[ch] = true
+	}
+	return &SlackBot{
+		engine:          opts.Engine,
+		poster:          opts.Poster,
+		allowedChannels: allowed,
+	}
+}
+
+// Run connects to Slack in socket mode and dispatches events until ctx is done
+func (b *SlackBot) Run(ctx context.Context, client *socketmode.Client) error {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt, ok := <-client.Events:
+				if !ok {
+					return
+				}
+				b.dispatch(ctx, client, evt)
+			}
+		}
+	}()
+
+	err := client.RunContext(ctx)
+	b.wg.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// dispatch acknowledges and handles a single socket mode event
+func (b *SlackBot) dispatch(ctx context.Context, client *socketmode.Client, evt socketmode.Event) {
+	switch evt.Type { //nolint:exhaustive // only commands and interactions are handled
+	case socketmode.EventTypeConnected:
+		logger.Info("connected to slack")
+	case socketmode.EventTypeSlashCommand:
+		cmd, ok := evt.Data.(slack.SlashCommand)
+		if !ok || evt.Request == nil {
+			return
+		}
+		response := b.HandleSlashCommand(ctx, cmd)
+		if err := client.Ack(*evt.Request, response); err != nil {
+			logger.Warn("failed to acknowledge slack command", "error", err)
+		}
+	case socketmode.EventTypeInteractive:
+		callback, ok := evt.Data.(slack.InteractionCallback)
+		if !ok || evt.Request == nil {
+			return
+		}
+		if err := client.Ack(*evt.Request); err != nil {
+			logger.Warn("failed to acknowledge slack interaction", "error", err)
+		}
+		b.HandleInteraction(ctx, callback)
+	}
+}
+
+// HandleSlashCommand handles `/crook <subcommand> [node]` and returns the
+// ephemeral response shown to the invoking user. down and up take an
+// optional sign-off after the node: `ticket=<id>` and a free-text reason;
+// down also takes `acknowledge-warnings`.
+func (b *SlackBot) HandleSlashCommand(ctx context.Context, cmd slack.SlashCommand) slack.Msg {
+	if !b.channelAllowed(cmd.ChannelID) {
+		return ephemeral("crook commands are not allowed in this channel")
+	}
+
+	args := strings.Fields(cmd.Text)
+	if len(args) == 0 || args[0] == "help" {
+		return ephemeral(helpText(cmd.Command))
+	}
+	if len(args) < 2 || (args[0] == "status" && len(args) != 2) {
+		return ephemeral(fmt.Sprintf("Usage: %s %s <node>", cmd.Command, args[0]))
+	}
+
+	subcommand, nodeName := args[0], args[1]
+	switch subcommand {
+	case "status":
+		return b.status(ctx, nodeName)
+	case server.PhaseDown, server.PhaseUp:
+		request := parseApprovalRequest(subcommand, nodeName, args[2:])
+		if request.acknowledgeWarnings && subcommand != server.PhaseDown {
+			return ephemeral(fmt.Sprintf("%s only applies to down", acknowledgeWarningsWord))
+		}
+		return b.requestApproval(ctx, cmd, request)
+	default:
+		return ephemeral(fmt.Sprintf("Unknown subcommand %q\n%s", subcommand, helpText(cmd.Command)))
+	}
+}
+
+// status summarizes the node's maintenance state using both phase plans
+func (b *SlackBot) status(ctx context.Context, nodeName string) slack.Msg {
+	downPlan, err := b.engine.Plan(ctx, nodeName, server.PhaseDown)
+	if err != nil {
+		return ephemeral(fmt.Sprintf("Failed to get status for %s: %v", nodeName, err))
+	}
+	upPlan, err := b.engine.Plan(ctx, nodeName, server.PhaseUp)
+	if err != nil {
+		return ephemeral(fmt.Sprintf("Failed to get status for %s: %v", nodeName, err))
+	}
+
+	state := "operational"
+	switch {
+	case downPlan.AlreadyInState:
+		state = "in maintenance"
+	case len(upPlan.Deployments) > 0:
+		state = "partially down"
+	}
+
+	return ephemeral(fmt.Sprintf("*%s* is %s\n• %d node-pinned deployment(s)\n• %d scaled down",
+		nodeName, state, len(downPlan.Deployments), len(upPlan.Deployments)))
+}
+
+// approvalRequest is the operation an approval message asks for and its
+// Approve button starts
+type approvalRequest struct {
+	phase    string
+	nodeName string
+	signOff  maintenance.SignOff
+
+	// requester is the Slack user ID of whoever ran the slash command; they
+	// may not approve their own down request
+	requester string
+
+	// acknowledgeWarnings proceeds past pre-flight checks that only warn
+	acknowledgeWarnings bool
+}
+
+// parseApprovalRequest reads the words following the node: a `ticket=<id>`
+// word, `acknowledge-warnings`, and the remaining words as the reason
+func parseApprovalRequest(phase, nodeName string, args []string) approvalRequest {
+	request := approvalRequest{phase: phase, nodeName: nodeName}
+	var reason []string
+	for _, arg := range args {
+		if ticket, ok := strings.CutPrefix(arg, "ticket="); ok {
+			request.signOff.Ticket = ticket
+			continue
+		}
+		if arg == acknowledgeWarningsWord {
+			request.acknowledgeWarnings = true
+			continue
+		}
+		reason = append(reason, arg)
+	}
+	request.signOff.Reason = strings.Join(reason, " ")
+	return request
+}
+
+// approvalValue encodes the request into the button value, e.g.
+// "down:worker-1?ticket=CHG-1"
+func approvalValue(request approvalRequest) string {
+	value := request.phase + ":" + request.nodeName
+	query := url.Values{}
+	if request.signOff.Reason != "" {
+		query.Set("reason", request.signOff.Reason)
+	}
+	if request.signOff.Ticket != "" {
+		query.Set("ticket", request.signOff.Ticket)
+	}
+	if request.acknowledgeWarnings {
+		query.Set("acknowledge_warnings", "true")
+	}
+	if request.requester != "" {
+		query.Set("requester", request.requester)
+	}
+	if len(query) == 0 {
+		return value
+	}
+	return value + "?" + query.Encode()
+}
+
+// parseApprovalValue decodes a button value built by approvalValue
+func parseApprovalValue(value string) (approvalRequest, bool) {
+	value, rawQuery, _ := strings.Cut(value, "?")
+	phase, nodeName, ok := strings.Cut(value, ":")
+	if !ok {
+		return approvalRequest{}, false
+	}
+	request := approvalRequest{phase: phase, nodeName: nodeName}
+	if query, err := url.ParseQuery(rawQuery); err == nil {
+		request.signOff = maintenance.SignOff{Reason: query.Get("reason"), Ticket: query.Get("ticket")}
+		request.acknowledgeWarnings = query.Get("acknowledge_warnings") == "true"
+		request.requester = query.Get("requester")
+	}
+	return request, true
+}
+
+// signOffSuffix formats a sign-off to append to a message, empty when zero
+func signOffSuffix(signOff maintenance.SignOff) string {
+	if signOff.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", signOff)
+}
+
+// requestApproval posts the plan with Approve/Cancel buttons to the channel
+func (b *SlackBot) requestApproval(ctx context.Context, cmd slack.SlashCommand, request approvalRequest) slack.Msg {
+	request.requester = cmd.UserID
+	phase, nodeName, signOff := request.phase, request.nodeName, request.signOff
+	plan, err := b.engine.Plan(ctx, nodeName, phase)
+	if err != nil {
+		return ephemeral(fmt.Sprintf("Failed to plan %s for %s: %v", phase, nodeName, err))
+	}
+	if plan.AlreadyInState {
+		return ephemeral(fmt.Sprintf("%s is already in the %s state - nothing to do", nodeName, phase))
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "<@%s> requested *%s* for node *%s*\n", cmd.UserID, phase, nodeName)
+	if signOff.Ticket != "" {
+		fmt.Fprintf(&summary, "Ticket: %s\n", signOff.Ticket)
+	}
+	if signOff.Reason != "" {
+		fmt.Fprintf(&summary, "Reason: %s\n", signOff.Reason)
+	}
+	if request.acknowledgeWarnings {
+		summary.WriteString("Pre-flight warnings are acknowledged\n")
+	}
+	target := "0"
+	if phase == server.PhaseUp {
+		target = "1"
+	}
+	fmt.Fprintf(&summary, "%d deployment(s) will be scaled to %s:", len(plan.Deployments), target)
+	for _, dep := range plan.Deployments {
+		fmt.Fprintf(&summary, "\n• `%s`", dep.Name)
+	}
+
+	value := approvalValue(request)
+	approve := slack.NewButtonBlockElement(actionApprove, value,
+		slack.NewTextBlockObject(slack.PlainTextType, "Approve", false, false)).WithStyle(slack.StylePrimary)
+	cancel := slack.NewButtonBlockElement(actionCancel, value,
+		slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false)).WithStyle(slack.StyleDanger)
+
+	_, _, err = b.poster.PostMessageContext(ctx, cmd.ChannelID,
+		slack.MsgOptionText(fmt.Sprintf("%s requested for %s", phase, nodeName), false),
+		slack.MsgOptionBlocks(
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, summary.String(), false, false), nil, nil),
+			slack.NewActionBlock("crook_approval", approve, cancel),
+		),
+	)
+	if err != nil {
+		return ephemeral(fmt.Sprintf("Failed to post approval request: %v", err))
+	}
+	return ephemeral(fmt.Sprintf("Approval requested for %s of %s", phase, nodeName))
+}
+
+// HandleInteraction handles the Approve/Cancel buttons of an approval request.
+// Buttons are only honoured in the allowed channels, and a down request must
+// be approved by someone other than its requester.
+func (b *SlackBot) HandleInteraction(ctx context.Context, callback slack.InteractionCallback) {
+	if callback.Type != slack.InteractionTypeBlockActions || len(callback.ActionCallback.BlockActions) == 0 {
+		return
+	}
+
+	action := callback.ActionCallback.BlockActions[0]
+	request, ok := parseApprovalValue(action.Value)
+	if !ok {
+		return
+	}
+
+	channelID := callback.Container.ChannelID
+	if channelID == "" {
+		channelID = callback.Channel.ID
+	}
+	messageTS := callback.Container.MessageTs
+	if !b.channelAllowed(channelID) {
+		logger.Warn("ignoring slack interaction outside the allowed channels", "channel", channelID, "user", callback.User.ID)
+		return
+	}
+
+	switch action.ActionID {
+	case actionCancel:
+		b.replaceApproval(ctx, channelID, messageTS,
+			fmt.Sprintf("*%s* of *%s* cancelled by <@%s>", request.phase, request.nodeName, callback.User.ID))
+	case actionApprove:
+		if refusal := approvalRefusal(request, callback.User.ID); refusal != "" {
+			b.reply(ctx, channelID, messageTS, ":x: "+refusal)
+			return
+		}
+		b.approve(ctx, channelID, messageTS, request, callback.User.ID)
+	}
+}
+
+// approvalRefusal explains why the user may not approve the request, empty
+// when they may: a down request needs a second person
+func approvalRefusal(request approvalRequest, userID string) string {
+	if request.phase != server.PhaseDown {
+		return ""
+	}
+	switch request.requester {
+	case "":
+		return fmt.Sprintf("The down request for %s does not name its requester; run the command again", request.nodeName)
+	case userID:
+		return fmt.Sprintf("<@%s> cannot approve their own down request; another user has to approve it", userID)
+	}
+	return ""
+}
+
+// channelAllowed reports whether commands and buttons are honoured in the channel
+func (b *SlackBot) channelAllowed(channelID string) bool {
+	return len(b.allowedChannels) == 0 || b.allowedChannels[channelID]
+}
+
+// approve starts the operation and streams its progress into the message thread
+func (b *SlackBot) approve(ctx context.Context, channelID, threadTS string, request approvalRequest, approver string) {
+	phase, nodeName, signOff := request.phase, request.nodeName, request.signOff
+	var status server.OperationStatus
+	var err error
+	switch phase {
+	case server.PhaseDown:
+		status, err = b.engine.StartDown(nodeName, signOff, request.acknowledgeWarnings)
+	case server.PhaseUp:
+		status, err = b.engine.StartUp(nodeName, signOff)
+	default:
+		return
+	}
+	if err != nil {
+		b.reply(ctx, channelID, threadTS, fmt.Sprintf(":x: Failed to start %s of %s: %v", phase, nodeName, err))
+		return
+	}
+
+	b.replaceApproval(ctx, channelID, threadTS,
+		fmt.Sprintf("*%s* of *%s* approved by <@%s> (operation `%s`)%s", phase, nodeName, approver, status.ID,
+			signOffSuffix(signOff)))
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.streamProgress(ctx, channelID, threadTS, status.ID)
+	}()
+}
+
+// streamProgress posts each progress event and the final result as thread replies
+func (b *SlackBot) streamProgress(ctx context.Context, channelID, threadTS, operationID string) {
+	err := b.engine.Subscribe(ctx, operationID, func(event server.ProgressEvent) {
+		b.reply(ctx, channelID, threadTS, fmt.Sprintf("→ %s", event.Description))
+	})
+	if err != nil {
+		logger.Warn("stopped streaming progress to slack", "operation", operationID, "error", err)
+		return
+	}
+
+	status, ok := b.engine.Operation(operationID)
+	if !ok {
+		return
+	}
+	signOff := signOffSuffix(maintenance.SignOff{Reason: status.Reason, Ticket: status.Ticket})
+	if status.Error != "" {
+		b.reply(ctx, channelID, threadTS, fmt.Sprintf(":x: %s of %s%s failed: %s", status.Phase, status.Node, signOff, status.Error))
+		return
+	}
+	b.reply(ctx, channelID, threadTS, fmt.Sprintf(":white_check_mark: %s of %s%s completed", status.Phase, status.Node, signOff))
+}
+
+// replaceApproval replaces the approval message (removing the buttons)
+func (b *SlackBot) replaceApproval(ctx context.Context, channelID, ts, text string) {
+	if ts == "" {
+		b.reply(ctx, channelID, "", text)
+		return
+	}
+	_, _, _, err := b.poster.UpdateMessageContext(ctx, channelID, ts,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionBlocks(slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil)),
+	)
+	if err != nil {
+		logger.Warn("failed to update slack message", "channel", channelID, "error", err)
+	}
+}
+
+// reply posts a message, threaded under threadTS when set
+func (b *SlackBot) reply(ctx context.Context, channelID, threadTS, text string) {
+	opts := []slack.MsgOption{slack.MsgOptionText(text, false)}
+	if threadTS != "" {
+		opts = append(opts, slack.MsgOptionTS(threadTS))
+	}
+	if _, _, err := b.poster.PostMessageContext(ctx, channelID, opts...); err != nil {
+		logger.Warn("failed to post slack message", "channel", channelID, "error", err)
+	}
+}
+
+// ephemeral builds a response visible only to the invoking user
+func ephemeral(text string) slack.Msg {
+	return slack.Msg{ResponseType: slack.ResponseTypeEphemeral, Text: text}
+}
+
+// helpText returns usage for the slash command
+func helpText(command string) string {
+	if command == "" {
+		command = "/crook"
+	}
+	return fmt.Sprintf("Usage:\n"+
+		"• `%[1]s status <node>` - show the node's maintenance state\n"+
+		"• `%[1]s down <node> [ticket=<id>] [acknowledge-warnings] [reason]` - request approval to prepare the node for maintenance\n"+
+		"• `%[1]s up <node> [ticket=<id>] [reason]` - request approval to restore the node", command)
+}
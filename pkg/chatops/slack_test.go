@@ -0,0 +1,353 @@
+package chatops
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/server"
+	"github.com/slack-go/slack"
+)
+
+type fakeEngine struct {
+	plans        map[string]*server.Plan
+	started      []string
+	signOffs     []maintenance.SignOff
+	acknowledged []bool
+	events       []server.ProgressEvent
+	startFn      func(nodeName string) error
+}
+
+func (f *fakeEngine) Plan(_ context.Context, nodeName, phase string) (*server.Plan, error) {
+	plan, ok := f.plans[phase]
+	if !ok {
+		return nil, errors.New("node not found")
+	}
+	plan.Node = nodeName
+	return plan, nil
+}
+
+func (f *fakeEngine) start(nodeName, phase string, signOff maintenance.SignOff) (server.OperationStatus, error) {
+	if f.startFn != nil {
+		if err := f.startFn(nodeName); err != nil {
+			return server.OperationStatus{}, err
+		}
+	}
+	f.started = append(f.started, phase+":"+nodeName)
+	f.signOffs = append(f.signOffs, signOff)
+	return server.OperationStatus{ID: "op-1", Node: nodeName, Phase: phase, State: server.StateRunning}, nil
+}
+
+func (f *fakeEngine) StartDown(nodeName string, signOff maintenance.SignOff, acknowledgeWarnings bool) (server.OperationStatus, error) {
+	f.acknowledged = append(f.acknowledged, acknowledgeWarnings)
+	return f.start(nodeName, server.PhaseDown, signOff)
+}
+
+func (f *fakeEngine) StartUp(nodeName string, signOff maintenance.SignOff) (server.OperationStatus, error) {
+	return f.start(nodeName, server.PhaseUp, signOff)
+}
+
+func (f *fakeEngine) Subscribe(_ context.Context, _ string, fn func(server.ProgressEvent)) error {
+	for _, event := range f.events {
+		fn(event)
+	}
+	return nil
+}
+
+func (f *fakeEngine) Operation(id string) (server.OperationStatus, bool) {
+	return server.OperationStatus{ID: id, Node: "worker-3", Phase: server.PhaseDown, State: server.StateSucceeded}, true
+}
+
+type postedMessage struct {
+	channel string
+	update  bool
+}
+
+type fakePoster struct {
+	mu       sync.Mutex
+	messages []postedMessage
+}
+
+func (f *fakePoster) PostMessageContext(_ context.Context, channelID string, _ ...slack.MsgOption) (string, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = append(f.messages, postedMessage{channel: channelID})
+	return channelID, "1700000000.000100", nil
+}
+
+func (f *fakePoster) UpdateMessageContext(_ context.Context, channelID, _ string, _ ...slack.MsgOption) (string, string, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = append(f.messages, postedMessage{channel: channelID, update: true})
+	return channelID, "1700000000.000100", "", nil
+}
+
+func (f *fakePoster) count() (posts, updates int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, m := range f.messages {
+		if m.update {
+			updates++
+		} else {
+			posts++
+		}
+	}
+	return posts, updates
+}
+
+func newTestEngine() *fakeEngine {
+	return &fakeEngine{
+		plans: map[string]*server.Plan{
+			server.PhaseDown: {Phase: server.PhaseDown, Deployments: []server.PlanItem{{Namespace: "rook-ceph", Name: "rook-ceph-osd-0", Replicas: 1}}},
+			server.PhaseUp:   {Phase: server.PhaseUp},
+		},
+		events: []server.ProgressEvent{
+			{Seq: 1, Stage: "cordon", Description: "Cordoning node worker-3"},
+			{Seq: 2, Stage: "complete", Description: "Down phase completed"},
+		},
+	}
+}
+
+func TestHandleSlashCommand(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		channel       string
+		allowed       []string
+		wantContains  string
+		wantApprovals int
+	}{
+		{name: "help", text: "help", wantContains: "Usage"},
+		{name: "empty", text: "", wantContains: "Usage"},
+		{name: "missing node", text: "down", wantContains: "Usage: /crook down <node>"},
+		{name: "unknown subcommand", text: "reboot worker-3", wantContains: "Unknown subcommand"},
+		{name: "status", text: "status worker-3", wantContains: "worker-3* is operational"},
+		{name: "down requests approval", text: "down worker-3", wantContains: "Approval requested", wantApprovals: 1},
+		{name: "down with sign-off", text: "down worker-3 ticket=CHG-1 replace disk", wantContains: "Approval requested", wantApprovals: 1},
+		{name: "down acknowledging warnings", text: "down worker-3 acknowledge-warnings", wantContains: "Approval requested", wantApprovals: 1},
+		{name: "up takes no acknowledgment", text: "up worker-3 acknowledge-warnings", wantContains: "only applies to down"},
+		{name: "status takes no sign-off", text: "status worker-3 ticket=CHG-1", wantContains: "Usage: /crook status <node>"},
+		{name: "up already in state", text: "up worker-3", wantContains: "nothing to do"},
+		{name: "channel not allowed", text: "down worker-3", channel: "C2", allowed: []string{"C1"}, wantContains: "not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine()
+			engine.plans[server.PhaseUp].AlreadyInState = true
+			poster := &fakePoster{}
+			bot := NewSlackBot(SlackOptions{Engine: engine, Poster: poster, AllowedChannels: tt.allowed})
+
+			channel := tt.channel
+			if channel == "" {
+				channel = "C1"
+			}
+			msg := bot.HandleSlashCommand(context.Background(), slack.SlashCommand{
+				Command:   "/crook",
+				Text:      tt.text,
+				ChannelID: channel,
+				UserID:    "U1",
+			})
+
+			if msg.ResponseType != slack.ResponseTypeEphemeral {
+				t.Errorf("ResponseType = %q, want ephemeral", msg.ResponseType)
+			}
+			if !strings.Contains(msg.Text, tt.wantContains) {
+				t.Errorf("Text = %q, want to contain %q", msg.Text, tt.wantContains)
+			}
+			if posts, _ := poster.count(); posts != tt.wantApprovals {
+				t.Errorf("posted %d messages, want %d", posts, tt.wantApprovals)
+			}
+			if len(engine.started) != 0 {
+				t.Errorf("slash command must not start operations, started %v", engine.started)
+			}
+		})
+	}
+}
+
+func blockAction(actionID, value string) slack.InteractionCallback {
+	return slack.InteractionCallback{
+		Type:      slack.InteractionTypeBlockActions,
+		User:      slack.User{ID: "U2"},
+		Container: slack.Container{ChannelID: "C1", MessageTs: "1700000000.000100"},
+		ActionCallback: slack.ActionCallbacks{
+			BlockActions: []*slack.BlockAction{{ActionID: actionID, Value: value}},
+		},
+	}
+}
+
+func TestHandleInteractionApprove(t *testing.T) {
+	engine := newTestEngine()
+	poster := &fakePoster{}
+	bot := NewSlackBot(SlackOptions{Engine: engine, Poster: poster})
+
+	bot.HandleInteraction(context.Background(), blockAction(actionApprove, "down:worker-3?requester=U1"))
+	bot.wg.Wait()
+
+	if len(engine.started) != 1 || engine.started[0] != "down:worker-3" {
+		t.Fatalf("started = %v, want [down:worker-3]", engine.started)
+	}
+
+	posts, updates := poster.count()
+	if updates != 1 {
+		t.Errorf("expected approval message to be replaced once, got %d updates", updates)
+	}
+	// Two progress events plus the final result
+	if posts != 3 {
+		t.Errorf("expected 3 thread replies, got %d", posts)
+	}
+}
+
+func TestHandleInteractionApproveSignOff(t *testing.T) {
+	engine := newTestEngine()
+	poster := &fakePoster{}
+	bot := NewSlackBot(SlackOptions{Engine: engine, Poster: poster})
+
+	request := parseApprovalRequest(server.PhaseDown, "worker-3", strings.Fields("ticket=CHG-1 acknowledge-warnings replace failed disk"))
+	request.requester = "U1"
+	bot.HandleInteraction(context.Background(), blockAction(actionApprove, approvalValue(request)))
+	bot.wg.Wait()
+
+	if len(engine.started) != 1 || engine.started[0] != "down:worker-3" {
+		t.Fatalf("started = %v, want [down:worker-3]", engine.started)
+	}
+	want := maintenance.SignOff{Reason: "replace failed disk", Ticket: "CHG-1"}
+	if engine.signOffs[0] != want {
+		t.Errorf("sign-off = %+v, want %+v", engine.signOffs[0], want)
+	}
+	if !engine.acknowledged[0] {
+		t.Error("down phase did not acknowledge the pre-flight warnings")
+	}
+}
+
+func TestApprovalValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		request approvalRequest
+		want    string
+	}{
+		{name: "no sign-off", request: approvalRequest{phase: server.PhaseUp, nodeName: "worker-3"}, want: "up:worker-3"},
+		{
+			name:    "ticket only",
+			request: approvalRequest{phase: server.PhaseUp, nodeName: "worker-3", signOff: maintenance.SignOff{Ticket: "CHG-1"}},
+			want:    "up:worker-3?ticket=CHG-1",
+		},
+		{
+			name:    "reason with separators",
+			request: approvalRequest{phase: server.PhaseUp, nodeName: "worker-3", signOff: maintenance.SignOff{Reason: "a:b?c&d"}},
+		},
+		{
+			name:    "acknowledged warnings",
+			request: approvalRequest{phase: server.PhaseDown, nodeName: "worker-3", acknowledgeWarnings: true},
+			want:    "down:worker-3?acknowledge_warnings=true",
+		},
+		{
+			name:    "requester",
+			request: approvalRequest{phase: server.PhaseDown, nodeName: "worker-3", requester: "U1"},
+			want:    "down:worker-3?requester=U1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := approvalValue(tt.request)
+			if tt.want != "" && value != tt.want {
+				t.Errorf("approvalValue() = %q, want %q", value, tt.want)
+			}
+
+			request, ok := parseApprovalValue(value)
+			if !ok || request != tt.request {
+				t.Errorf("parseApprovalValue(%q) = %+v, %v, want %+v", value, request, ok, tt.request)
+			}
+		})
+	}
+}
+
+func TestHandleInteractionCancel(t *testing.T) {
+	engine := newTestEngine()
+	poster := &fakePoster{}
+	bot := NewSlackBot(SlackOptions{Engine: engine, Poster: poster})
+
+	bot.HandleInteraction(context.Background(), blockAction(actionCancel, "down:worker-3"))
+
+	if len(engine.started) != 0 {
+		t.Errorf("cancel must not start operations, started %v", engine.started)
+	}
+	if _, updates := poster.count(); updates != 1 {
+		t.Errorf("expected approval message to be replaced, got %d updates", updates)
+	}
+}
+
+func TestHandleInteractionStartFailure(t *testing.T) {
+	engine := newTestEngine()
+	engine.startFn = func(string) error { return server.ErrOperationInProgress }
+	poster := &fakePoster{}
+	bot := NewSlackBot(SlackOptions{Engine: engine, Poster: poster})
+
+	bot.HandleInteraction(context.Background(), blockAction(actionApprove, "down:worker-3?requester=U1"))
+	bot.wg.Wait()
+
+	posts, updates := poster.count()
+	if posts != 1 || updates != 0 {
+		t.Errorf("expected a single failure reply, got %d posts and %d updates", posts, updates)
+	}
+}
+
+func TestHandleInteractionRefusedApproval(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		wantStarted bool
+	}{
+		{name: "requester approves own down", value: "down:worker-3?requester=U2"},
+		{name: "down without requester", value: "down:worker-3"},
+		{name: "requester approves own up", value: "up:worker-3?requester=U2", wantStarted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine()
+			poster := &fakePoster{}
+			bot := NewSlackBot(SlackOptions{Engine: engine, Poster: poster})
+
+			// blockAction presses the button as U2
+			bot.HandleInteraction(context.Background(), blockAction(actionApprove, tt.value))
+			bot.wg.Wait()
+
+			if started := len(engine.started) > 0; started != tt.wantStarted {
+				t.Fatalf("started = %v, want started %v", engine.started, tt.wantStarted)
+			}
+			if tt.wantStarted {
+				return
+			}
+			posts, updates := poster.count()
+			if posts != 1 || updates != 0 {
+				t.Errorf("expected a single refusal reply, got %d posts and %d updates", posts, updates)
+			}
+		})
+	}
+}
+
+func TestHandleInteractionChannelNotAllowed(t *testing.T) {
+	for _, actionID := range []string{actionApprove, actionCancel} {
+		t.Run(actionID, func(t *testing.T) {
+			engine := newTestEngine()
+			poster := &fakePoster{}
+			bot := NewSlackBot(SlackOptions{Engine: engine, Poster: poster, AllowedChannels: []string{"C-OPS"}})
+
+			// blockAction presses the button in C1
+			bot.HandleInteraction(context.Background(), blockAction(actionID, "down:worker-3?requester=U1"))
+			bot.wg.Wait()
+
+			if len(engine.started) != 0 {
+				t.Errorf("started = %v, want none outside the allowed channels", engine.started)
+			}
+			if posts, updates := poster.count(); posts != 0 || updates != 0 {
+				t.Errorf("got %d posts and %d updates, want none", posts, updates)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -64,3 +65,54 @@ func Confirm(opts ConfirmOptions) (bool, error) {
 		return false, nil
 	}
 }
+
+// SelectOptions holds options for the selection prompt.
+type SelectOptions struct {
+	// Question is the prompt to display above the choices.
+	Question string
+
+	// Choices are the options, listed with numbers starting at 1.
+	Choices []string
+
+	// Input is the reader for user input (defaults to os.Stdin).
+	Input io.Reader
+
+	// Output is the writer for the prompt (defaults to os.Stdout).
+	Output io.Writer
+}
+
+// Select prompts the user to pick one of several choices by number.
+// Returns the index of the chosen option, or -1 if the user entered
+// nothing or something other than a listed number.
+func Select(opts SelectOptions) (int, error) {
+	input := opts.Input
+	if input == nil {
+		input = os.Stdin
+	}
+
+	output := opts.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	_, _ = fmt.Fprintln(output, opts.Question)
+	for i, choice := range opts.Choices {
+		_, _ = fmt.Fprintf(output, "  %d) %s\n", i+1, choice)
+	}
+	_, _ = fmt.Fprintf(output, "Enter a number (1-%d): ", len(opts.Choices))
+
+	scanner := bufio.NewScanner(input)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return -1, fmt.Errorf("failed to read input: %w", err)
+		}
+		// EOF without input
+		return -1, nil
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil || n < 1 || n > len(opts.Choices) {
+		return -1, nil
+	}
+	return n - 1, nil
+}
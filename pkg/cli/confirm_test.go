@@ -132,3 +132,43 @@ func TestConfirm_DefaultsToStdio(t *testing.T) {
 		t.Error("expected true when skip is set")
 	}
 }
+
+func TestSelect(t *testing.T) {
+	choices := []string{"worker-3.prod.example.com", "worker-3.staging.example.com"}
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{name: "first", input: "1\n", want: 0},
+		{name: "second with spaces", input: " 2 \n", want: 1},
+		{name: "out of range", input: "3\n", want: -1},
+		{name: "zero", input: "0\n", want: -1},
+		{name: "not a number", input: "worker-3\n", want: -1},
+		{name: "empty", input: "\n", want: -1},
+		{name: "EOF", input: "", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			got, err := cli.Select(cli.SelectOptions{
+				Question: "Which node?",
+				Choices:  choices,
+				Input:    strings.NewReader(tt.input),
+				Output:   &output,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+			for _, want := range []string{"Which node?", "1) worker-3.prod.example.com", "2) worker-3.staging.example.com", "(1-2)"} {
+				if !strings.Contains(output.String(), want) {
+					t.Errorf("output missing %q: %s", want, output.String())
+				}
+			}
+		})
+	}
+}
@@ -7,11 +7,21 @@ import (
 	"strings"
 
 	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/tui/styles"
+	"golang.org/x/term"
+)
+
+// ansiFaint and ansiReset grey out plan items that are not acted on
+const (
+	ansiFaint = "\033[2m"
+	ansiReset = "\033[0m"
 )
 
 // ProgressWriter outputs progress updates to the terminal.
 type ProgressWriter struct {
-	w io.Writer
+	w     io.Writer
+	quiet bool
+	color bool
 }
 
 // NewProgressWriter creates a new ProgressWriter.
@@ -20,7 +30,21 @@ func NewProgressWriter(w io.Writer) *ProgressWriter {
 	if w == nil {
 		w = os.Stdout
 	}
-	return &ProgressWriter{w: w}
+	return &ProgressWriter{w: w, color: isTerminal(w) && styles.ColorEnabled()}
+}
+
+// isTerminal checks if the writer is a terminal
+func isTerminal(w io.Writer) bool {
+	if f, ok := w.(*os.File); ok {
+		return term.IsTerminal(int(f.Fd()))
+	}
+	return false
+}
+
+// SetQuiet suppresses progress, summary, and success output.
+// Errors and warnings are still printed.
+func (pw *ProgressWriter) SetQuiet(quiet bool) {
+	pw.quiet = quiet
 }
 
 // OnDownProgress handles progress updates from the down phase.
@@ -33,8 +57,17 @@ func (pw *ProgressWriter) OnUpProgress(p maintenance.UpPhaseProgress) {
 	pw.printProgress(p.Stage, p.Description)
 }
 
+// OnRebootProgress handles progress updates from a reboot through hooks.reboot.
+func (pw *ProgressWriter) OnRebootProgress(description string) {
+	pw.printProgress("reboot", description)
+}
+
 // printProgress prints a progress message with appropriate formatting.
 func (pw *ProgressWriter) printProgress(stage, description string) {
+	if pw.quiet && stage != "error" {
+		return
+	}
+
 	var prefix string
 	switch stage {
 	case "complete":
@@ -56,6 +89,9 @@ func (pw *ProgressWriter) printProgress(stage, description string) {
 
 // PrintSummary prints a summary of deployments that will be affected.
 func (pw *ProgressWriter) PrintSummary(nodeName string, deploymentCount int, deploymentNames []string) {
+	if pw.quiet {
+		return
+	}
 	_, _ = fmt.Fprintf(pw.w, "Target node: %s\n", nodeName)
 	_, _ = fmt.Fprintf(pw.w, "Deployments to process: %d\n", deploymentCount)
 
@@ -68,8 +104,67 @@ func (pw *ProgressWriter) PrintSummary(nodeName string, deploymentCount int, dep
 	_, _ = fmt.Fprintln(pw.w)
 }
 
+// PrintStatefulSets prints the node-pinned StatefulSets the plan scales after
+// its deployments. Nothing is printed without any.
+func (pw *ProgressWriter) PrintStatefulSets(names []string) {
+	if pw.quiet || len(names) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(pw.w, "StatefulSets to process: %d\n", len(names))
+	for _, name := range names {
+		_, _ = fmt.Fprintf(pw.w, "  - %s\n", name)
+	}
+	_, _ = fmt.Fprintln(pw.w)
+}
+
+// PrintPartialPlan prints how much of a re-entered down phase is left, e.g.
+// "3 already down, 5 to scale".
+func (pw *ProgressWriter) PrintPartialPlan(summary string) {
+	if pw.quiet {
+		return
+	}
+	_, _ = fmt.Fprintf(pw.w, "Partial plan: %s\n\n", summary)
+}
+
+// PrintPrefixes prints the deployment name prefixes the plan is limited to.
+func (pw *ProgressWriter) PrintPrefixes(prefixes []string) {
+	if pw.quiet {
+		return
+	}
+	if len(prefixes) == 0 {
+		_, _ = fmt.Fprintln(pw.w, "Deployment prefixes: none (all node-pinned deployments)")
+		return
+	}
+	_, _ = fmt.Fprintf(pw.w, "Deployment prefixes: %s\n", strings.Join(prefixes, ", "))
+}
+
+// PrintExcluded prints the deployments discovery.exclude keeps running,
+// greyed out on a color terminal.
+func (pw *ProgressWriter) PrintExcluded(deploymentNames []string) {
+	if pw.quiet || len(deploymentNames) == 0 {
+		return
+	}
+	start, end := "", ""
+	if pw.color {
+		start, end = ansiFaint, ansiReset
+	}
+	_, _ = fmt.Fprintf(pw.w, "%sExcluded (kept running):\n", start)
+	for _, name := range deploymentNames {
+		_, _ = fmt.Fprintf(pw.w, "  - %s\n", name)
+	}
+	_, _ = fmt.Fprint(pw.w, end)
+}
+
+// PrintWaiting prints what the command is waiting for, e.g. "node worker-1 to be up".
+func (pw *ProgressWriter) PrintWaiting(target string) {
+	pw.printProgress("wait", "Waiting for "+target)
+}
+
 // PrintSuccess prints a success message.
 func (pw *ProgressWriter) PrintSuccess(message string) {
+	if pw.quiet {
+		return
+	}
 	_, _ = fmt.Fprintf(pw.w, "\u2713 %s\n", message)
 }
 
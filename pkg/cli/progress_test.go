@@ -136,6 +136,73 @@ func TestProgressWriter_PrintSummary(t *testing.T) {
 	}
 }
 
+func TestProgressWriter_PrintPrefixes(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefixes []string
+		want     string
+	}{
+		{name: "none", want: "Deployment prefixes: none (all node-pinned deployments)\n"},
+		{name: "override", prefixes: []string{"rook-ceph-osd", "rook-ceph-mgr"}, want: "Deployment prefixes: rook-ceph-osd, rook-ceph-mgr\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			pw := cli.NewProgressWriter(buf)
+
+			pw.PrintPrefixes(tt.prefixes)
+
+			if buf.String() != tt.want {
+				t.Errorf("PrintPrefixes() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressWriter_PrintExcluded(t *testing.T) {
+	buf := &bytes.Buffer{}
+	pw := cli.NewProgressWriter(buf)
+
+	pw.PrintExcluded(nil)
+	if buf.Len() != 0 {
+		t.Errorf("expected no output without excluded deployments, got: %q", buf.String())
+	}
+
+	pw.PrintExcluded([]string{"rook-ceph/rook-ceph-tools"})
+	want := "Excluded (kept running):\n  - rook-ceph/rook-ceph-tools\n"
+	if buf.String() != want {
+		t.Errorf("PrintExcluded() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestProgressWriter_PrintStatefulSets(t *testing.T) {
+	buf := &bytes.Buffer{}
+	pw := cli.NewProgressWriter(buf)
+
+	pw.PrintStatefulSets(nil)
+	if buf.Len() != 0 {
+		t.Errorf("expected no output without statefulsets, got: %q", buf.String())
+	}
+
+	pw.PrintStatefulSets([]string{"rook-ceph/rook-ceph-rgw-store-a"})
+	want := "StatefulSets to process: 1\n  - rook-ceph/rook-ceph-rgw-store-a\n\n"
+	if buf.String() != want {
+		t.Errorf("PrintStatefulSets() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestProgressWriter_PrintPartialPlan(t *testing.T) {
+	buf := &bytes.Buffer{}
+	pw := cli.NewProgressWriter(buf)
+
+	pw.PrintPartialPlan("3 already down, 5 to scale")
+	want := "Partial plan: 3 already down, 5 to scale\n\n"
+	if buf.String() != want {
+		t.Errorf("PrintPartialPlan() = %q, want %q", buf.String(), want)
+	}
+}
+
 func TestProgressWriter_PrintSuccess(t *testing.T) {
 	buf := &bytes.Buffer{}
 	pw := cli.NewProgressWriter(buf)
@@ -173,3 +240,27 @@ func TestProgressWriter_NilWriter(t *testing.T) {
 		t.Error("expected non-nil ProgressWriter")
 	}
 }
+
+func TestProgressWriter_Quiet(t *testing.T) {
+	buf := &bytes.Buffer{}
+	pw := cli.NewProgressWriter(buf)
+	pw.SetQuiet(true)
+
+	pw.OnDownProgress(maintenance.DownPhaseProgress{Stage: "cordon", Description: "Cordoning node"})
+	pw.PrintSummary("worker-1", 1, []string{"rook-ceph/rook-ceph-osd-0"})
+	pw.PrintSuccess("Operation completed")
+	if buf.Len() != 0 {
+		t.Errorf("expected no output in quiet mode, got: %s", buf.String())
+	}
+
+	pw.PrintWarning("Heads up")
+	pw.PrintError("Something failed")
+	pw.OnDownProgress(maintenance.DownPhaseProgress{Stage: "error", Description: "Step failed"})
+
+	output := buf.String()
+	for _, want := range []string{"Heads up", "Something failed", "Step failed"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in quiet output, got: %s", want, output)
+		}
+	}
+}
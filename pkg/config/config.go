@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -16,8 +19,74 @@ const (
 	DefaultCephCommandTimeoutSeconds    = 20
 	DefaultLogLevel                     = "info"
 	DefaultLogFormat                    = "text"
+	DefaultDrainGracePeriodSeconds      = -1 // Each pod's own terminationGracePeriodSeconds
+	DefaultDrainTimeoutSeconds          = 300
+	DefaultMaintenanceWindowMinutes     = 240
+	DefaultOpsgenieURL                  = "https://api.opsgenie.com"
+	DefaultCustomCheckTimeoutSeconds    = 30
+	DefaultRebootTimeoutSeconds         = 60
+	DefaultRebootReadyTimeoutSeconds    = 900
+	DefaultKuredNamespace               = "kube-system"
+	DefaultSmokeTestImage               = "busybox:1.36"
+	DefaultSmokeTestSize                = "1Gi"
+	DefaultSmokeTestTimeoutSeconds      = 300
+	DefaultKuredDaemonSet               = "kured"
+	DefaultKuredSentinelPath            = "/var/run/reboot-required"
+	DefaultBell                         = true
+	DefaultIdleLockSeconds              = 900
+	DefaultTerminalTitle                = true
+	DefaultMaintenancePane              = true
+	DefaultLayout                       = LayoutAuto
+	DefaultNoOutScope                   = NoOutScopeCluster
+	DefaultNoOutWatchdogTTLSeconds      = 14400
+	DefaultNoOutWatchdogServiceAccount  = "crook"
 )
 
+// TUI layouts for ui.layout
+const (
+	// LayoutAuto picks the wide layout on terminals wide enough for it
+	LayoutAuto = "auto"
+
+	// LayoutStacked stacks the Deployments/Pods and OSDs panes below the Nodes pane
+	LayoutStacked = "stacked"
+
+	// LayoutWide puts Nodes and OSDs on the left and Deployments/Pods on the
+	// right at full height
+	LayoutWide = "wide"
+)
+
+// Layouts lists the supported ui.layout values
+var Layouts = []string{LayoutAuto, LayoutStacked, LayoutWide}
+
+// Desktop notification escape sequences for ui.desktop-notification
+const (
+	// DesktopNotificationOSC9 is iTerm2's OSC 9, also understood by Windows
+	// Terminal, ConEmu and WezTerm
+	DesktopNotificationOSC9 = "osc9"
+
+	// DesktopNotificationOSC777 is the OSC 777 notify extension of rxvt,
+	// also understood by foot, Ghostty and VTE based terminals
+	DesktopNotificationOSC777 = "osc777"
+)
+
+// Scopes of the noout flag for ceph.noout-scope
+const (
+	// NoOutScopeCluster sets the cluster-wide noout flag
+	NoOutScopeCluster = "cluster"
+
+	// NoOutScopeHost sets noout on the node's CRUSH host bucket only
+	NoOutScopeHost = "host"
+
+	// NoOutScopeOSD sets noout on each of the node's OSDs only
+	NoOutScopeOSD = "osd"
+)
+
+// NoOutScopes lists the supported ceph.noout-scope values
+var NoOutScopes = []string{NoOutScopeCluster, NoOutScopeHost, NoOutScopeOSD}
+
+// DesktopNotifications lists the supported ui.desktop-notification values
+var DesktopNotifications = []string{DesktopNotificationOSC9, DesktopNotificationOSC777}
+
 // Config holds the full configuration schema for crook.
 type Config struct {
 	// Namespace is the rook-ceph namespace (used for both operator and cluster).
@@ -26,6 +95,80 @@ type Config struct {
 	UI        UIConfig      `mapstructure:"ui" yaml:"ui" json:"ui"`
 	Timeouts  TimeoutConfig `mapstructure:"timeouts" yaml:"timeouts" json:"timeouts"`
 	Logging   LoggingConfig `mapstructure:"logging" yaml:"logging" json:"logging"`
+	Ceph      CephConfig    `mapstructure:"ceph" yaml:"ceph" json:"ceph"`
+	Policy    PolicyConfig  `mapstructure:"policy" yaml:"policy" json:"policy"`
+	Drain     DrainConfig   `mapstructure:"drain" yaml:"drain" json:"drain"`
+
+	// Discovery adjusts which node-pinned deployments the maintenance phases act on
+	Discovery DiscoveryConfig `mapstructure:"discovery" yaml:"discovery,omitempty" json:"discovery,omitempty"`
+
+	// Cluster selects the CephCluster crook operates on when several share the
+	// namespace. Empty means every Rook-managed daemon in the namespace.
+	// Set via --cluster flag, CROOK_CLUSTER env var, or "cluster:" in config file.
+	Cluster string `mapstructure:"cluster" yaml:"cluster,omitempty" json:"cluster,omitempty"`
+
+	// Notifications opens maintenance windows in incident management tools
+	Notifications NotificationsConfig `mapstructure:"notifications" yaml:"notifications,omitempty" json:"notifications,omitempty"`
+
+	// Preflight adds organization-specific checks to the pre-flight validation
+	Preflight PreflightConfig `mapstructure:"preflight" yaml:"preflight,omitempty" json:"preflight,omitempty"`
+
+	// Hooks integrates crook with the tools that act on the host itself
+	Hooks HooksConfig `mapstructure:"hooks" yaml:"hooks" json:"hooks"`
+
+	// Cloud adjusts the maintenance of nodes in managed cloud node groups
+	Cloud CloudConfig `mapstructure:"cloud" yaml:"cloud" json:"cloud"`
+
+	// SmokeTest verifies the storage path after the up phase
+	SmokeTest SmokeTestConfig `mapstructure:"smoke-test" yaml:"smoke-test" json:"smoke-test"`
+
+	// Telemetry reports anonymous feature usage; off by default
+	Telemetry TelemetryConfig `mapstructure:"telemetry" yaml:"telemetry" json:"telemetry"`
+}
+
+// TelemetryConfig controls the opt-in usage reports: the command run, the
+// names of the flags set, flow durations and failure stages, and the terminal
+// size, sent once per run. Node, cluster and workload names, flag values and
+// error messages are never included. DO_NOT_TRACK disables it regardless.
+type TelemetryConfig struct {
+	// Enabled sends the reports (CROOK_TELEMETRY_ENABLED)
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+
+	// Endpoint receives each report as a JSON POST (CROOK_TELEMETRY_ENDPOINT)
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+}
+
+// SmokeTestConfig controls the optional smoke test stage at the end of the up
+// phase: for each storage class a small PVC is mounted by a pod on the node,
+// which writes and reads back a file, before the maintenance is closed
+type SmokeTestConfig struct {
+	// Enabled runs the smoke test stage ('crook up --smoke-test')
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+
+	// StorageClasses are the classes tested, e.g. an RBD and a CephFS one
+	StorageClasses []string `mapstructure:"storage-classes" yaml:"storage-classes,omitempty" json:"storage-classes,omitempty"`
+
+	// Namespace receives the test PVCs and pods; empty means the Rook namespace
+	Namespace string `mapstructure:"namespace" yaml:"namespace,omitempty" json:"namespace,omitempty"`
+
+	// Image runs the test pod; it needs sh, cat and sync
+	Image string `mapstructure:"image" yaml:"image" json:"image"`
+
+	// Size is the capacity requested by each test PVC
+	Size string `mapstructure:"size" yaml:"size" json:"size"`
+
+	// TimeoutSeconds bounds each storage class's test, provisioning included
+	TimeoutSeconds int `mapstructure:"timeout-seconds" yaml:"timeout-seconds" json:"timeout-seconds"`
+}
+
+// CloudConfig controls how the maintenance phases treat nodes of managed
+// node groups (EKS, GKE, AKS), which autoscalers may remove or replace while
+// they are cordoned
+type CloudConfig struct {
+	// DisableScaleDown annotates the node with
+	// cluster-autoscaler.kubernetes.io/scale-down-disabled from the down
+	// phase until the up phase
+	DisableScaleDown bool `mapstructure:"disable-scale-down" yaml:"disable-scale-down" json:"disable-scale-down"`
 }
 
 // UIConfig holds terminal UI settings.
@@ -35,6 +178,28 @@ type UIConfig struct {
 
 	// CephRefreshMS is the refresh interval for Ceph CLI operations (OSDs, header)
 	CephRefreshMS int `mapstructure:"ceph-refresh-ms" yaml:"ceph-refresh-ms" json:"ceph-refresh-ms"`
+
+	// Bell rings the terminal bell when a down or up flow completes or fails
+	Bell bool `mapstructure:"bell" yaml:"bell" json:"bell"`
+
+	// DesktopNotification additionally sends a desktop notification when a
+	// down or up flow finishes: "osc9" or "osc777". Empty sends none.
+	DesktopNotification string `mapstructure:"desktop-notification" yaml:"desktop-notification,omitempty" json:"desktop-notification,omitempty"`
+
+	// IdleLockSeconds locks the mutating keys of the TUI after this long
+	// without input until they are unlocked again. Zero never locks.
+	IdleLockSeconds int `mapstructure:"idle-lock-seconds" yaml:"idle-lock-seconds" json:"idle-lock-seconds"`
+
+	// TerminalTitle sets the terminal (and tmux pane) title to the context
+	// and the running flow's progress
+	TerminalTitle bool `mapstructure:"terminal-title" yaml:"terminal-title" json:"terminal-title"`
+
+	// MaintenancePane shows the TUI's maintenance pane and its down/up keys.
+	// Disabled, the TUI is strictly read-only.
+	MaintenancePane bool `mapstructure:"maintenance-pane" yaml:"maintenance-pane" json:"maintenance-pane"`
+
+	// Layout arranges the TUI's panes: "auto", "stacked" or "wide"
+	Layout string `mapstructure:"layout" yaml:"layout" json:"layout"`
 }
 
 // TimeoutConfig captures configurable timeouts.
@@ -51,13 +216,372 @@ type LoggingConfig struct {
 	Format string `mapstructure:"format" yaml:"format" json:"format"`
 }
 
+// CephConfig holds settings for Ceph CLI invocations.
+type CephConfig struct {
+	Commands CephCommandsConfig `mapstructure:"commands" yaml:"commands" json:"commands"`
+
+	// External marks a Ceph cluster running outside Kubernetes and consumed
+	// through Rook external mode. crook then only cordons the node and manages
+	// noout. External CephClusters are also detected automatically.
+	External bool `mapstructure:"external" yaml:"external,omitempty" json:"external,omitempty"`
+
+	// NoOutScope is what the down phase sets noout on: the whole cluster
+	// (cluster), or only the node's CRUSH host (host) or OSDs (osd), so the
+	// other nodes' OSDs are still marked out when they fail
+	NoOutScope string `mapstructure:"noout-scope" yaml:"noout-scope" json:"noout-scope"`
+
+	// NoOutWatchdog clears a forgotten noout flag after a TTL
+	NoOutWatchdog NoOutWatchdogConfig `mapstructure:"noout-watchdog" yaml:"noout-watchdog" json:"noout-watchdog"`
+}
+
+// NoOutWatchdogConfig controls the optional noout safety timer: the down
+// phase creates a Job that clears the noout flag once the TTL expires, unless
+// the up phase deletes it first. It guards against a crook that crashed or was
+// never re-run leaving Ceph unable to mark OSDs out.
+type NoOutWatchdogConfig struct {
+	// Enabled creates the watchdog Job ('crook down --noout-watchdog-ttl')
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+
+	// TTLSeconds is how long after the down phase the flag is cleared
+	TTLSeconds int `mapstructure:"ttl-seconds" yaml:"ttl-seconds" json:"ttl-seconds"`
+
+	// Image is the crook container image the Job runs
+	Image string `mapstructure:"image" yaml:"image,omitempty" json:"image,omitempty"`
+
+	// ServiceAccount provides the Job's in-cluster credentials
+	ServiceAccount string `mapstructure:"service-account" yaml:"service-account" json:"service-account"`
+}
+
+// CephCommandsConfig adapts the ceph commands crook runs in the toolbox pod to
+// deployments that need extra options or a wrapper (e.g. a cephadm shell).
+type CephCommandsConfig struct {
+	// Prefix is prepended to every command (e.g. [cephadm, shell, --])
+	Prefix []string `mapstructure:"prefix" yaml:"prefix,omitempty" json:"prefix,omitempty"`
+
+	// Args are inserted right after "ceph" (e.g. [--connect-timeout, "10"])
+	Args []string `mapstructure:"args" yaml:"args,omitempty" json:"args,omitempty"`
+
+	// Overrides replace exact invocations; Prefix and Args are not applied to them
+	Overrides []CephCommandOverride `mapstructure:"overrides" yaml:"overrides,omitempty" json:"overrides,omitempty"`
+}
+
+// CephCommandOverride replaces one exact ceph invocation with another.
+type CephCommandOverride struct {
+	// Match is the invocation crook would run, e.g. "ceph osd dump --format json"
+	Match string `mapstructure:"match" yaml:"match" json:"match"`
+
+	// Command is the command line to run instead, split on whitespace
+	Command string `mapstructure:"command" yaml:"command" json:"command"`
+}
+
+// PolicyConfig holds the risk thresholds the down pre-flight checks enforce.
+// Unset fields keep crook's built-in behavior, which does not gate on Ceph
+// health; organizations set them to encode their own policy.
+type PolicyConfig struct {
+	// RequireHealthOKBeforeDown fails the down pre-flight unless Ceph reports HEALTH_OK
+	RequireHealthOKBeforeDown bool `mapstructure:"require-health-ok-before-down" yaml:"require-health-ok-before-down" json:"require-health-ok-before-down"`
+
+	// MaxDegradedPGsPercent fails the down pre-flight when a larger share of PGs
+	// is degraded. Unset means no limit.
+	MaxDegradedPGsPercent *float64 `mapstructure:"max-degraded-pgs-percent" yaml:"max-degraded-pgs-percent,omitempty" json:"max-degraded-pgs-percent,omitempty"`
+
+	// AllowWhenNearFull permits taking a node down while OSDs or pools are near
+	// full. Unset means allowed.
+	AllowWhenNearFull *bool `mapstructure:"allow-when-near-full" yaml:"allow-when-near-full,omitempty" json:"allow-when-near-full,omitempty"`
+
+	// RequireApprovalBeforeDown enforces the two-person rule: the down phase
+	// only runs for a request ('crook down --request') approved by a second
+	// user with 'crook approve'
+	RequireApprovalBeforeDown bool `mapstructure:"require-approval-before-down" yaml:"require-approval-before-down,omitempty" json:"require-approval-before-down,omitempty"`
+}
+
+// NearFullAllowed reports whether a node may go down while Ceph is near full
+func (p PolicyConfig) NearFullAllowed() bool {
+	return p.AllowWhenNearFull == nil || *p.AllowWhenNearFull
+}
+
+// GatesHealth reports whether the policy is stricter than the built-in
+// behavior and needs the Ceph status before a node goes down.
+func (p PolicyConfig) GatesHealth() bool {
+	return p.RequireHealthOKBeforeDown || p.MaxDegradedPGsPercent != nil || !p.NearFullAllowed()
+}
+
+// PreflightConfig holds user-defined pre-flight checks, e.g. "no backup job
+// running" or "outside the change freeze", run alongside the built-in ones.
+type PreflightConfig struct {
+	Checks []CustomCheck `mapstructure:"checks" yaml:"checks,omitempty" json:"checks,omitempty"`
+}
+
+// HooksConfig holds the host actions crook can trigger around a maintenance
+type HooksConfig struct {
+	// Reboot restarts the host once the down phase completed ('crook down --reboot')
+	Reboot RebootHook `mapstructure:"reboot" yaml:"reboot" json:"reboot"`
+
+	// Kured locates the kured reboot daemon, whose reboots 'crook hook
+	// kured-pre' and 'crook hook kured-post' wrap in the down and up phases
+	Kured KuredHook `mapstructure:"kured" yaml:"kured" json:"kured"`
+}
+
+// KuredHook locates kured (https://kured.dev), which reboots nodes after OS
+// updates, so crook can tell when it is about to reboot a node
+type KuredHook struct {
+	// Namespace of the kured DaemonSet
+	Namespace string `mapstructure:"namespace" yaml:"namespace" json:"namespace"`
+
+	// DaemonSet is the kured DaemonSet holding the reboot lock annotation
+	DaemonSet string `mapstructure:"daemon-set" yaml:"daemon-set" json:"daemon-set"`
+
+	// SentinelPath is the file whose presence tells kured a reboot is
+	// required; it is only read when crook runs on the node itself
+	SentinelPath string `mapstructure:"sentinel-path" yaml:"sentinel-path" json:"sentinel-path"`
+}
+
+// RebootHook reboots a node through exactly one mechanism: a command (e.g.
+// SSH or a cloud CLI), an HTTP endpoint (e.g. a power-management API) or the
+// Metal3 BareMetalHost of the node
+type RebootHook struct {
+	// Command is run with 'sh -c' where crook runs, with CROOK_NODE and
+	// CROOK_PHASE=reboot set; it must exit 0
+	Command string `mapstructure:"command" yaml:"command,omitempty" json:"command,omitempty"`
+
+	// URL receives a POST with {"node": "<node>", "action": "reboot"}; it
+	// must answer with a 2xx status
+	URL string `mapstructure:"url" yaml:"url,omitempty" json:"url,omitempty"`
+
+	// BareMetalHostNamespace reboots through Metal3: the BareMetalHost
+	// named like the node in this namespace gets the reboot.metal3.io annotation
+	BareMetalHostNamespace string `mapstructure:"bare-metal-host-namespace" yaml:"bare-metal-host-namespace,omitempty" json:"bare-metal-host-namespace,omitempty"`
+
+	// TimeoutSeconds bounds triggering the reboot
+	TimeoutSeconds int `mapstructure:"timeout-seconds" yaml:"timeout-seconds" json:"timeout-seconds"`
+
+	// ReadyTimeoutSeconds bounds the wait for the node to come back Ready
+	ReadyTimeoutSeconds int `mapstructure:"ready-timeout-seconds" yaml:"ready-timeout-seconds" json:"ready-timeout-seconds"`
+}
+
+// Configured reports whether a reboot mechanism is set
+func (h RebootHook) Configured() bool {
+	return h.Mechanism() != ""
+}
+
+// Mechanism names the configured reboot mechanism: "command", "url" or
+// "metal3"; empty when none is set
+func (h RebootHook) Mechanism() string {
+	switch {
+	case h.Command != "":
+		return "command"
+	case h.URL != "":
+		return "url"
+	case h.BareMetalHostNamespace != "":
+		return "metal3"
+	default:
+		return ""
+	}
+}
+
+// Custom check severities
+const (
+	CheckSeverityBlock = "block"
+	CheckSeverityWarn  = "warn"
+)
+
+// Phases a custom check runs before
+const (
+	PhaseDown = "down"
+	PhaseUp   = "up"
+)
+
+// CustomCheck is a pre-flight check running a shell command or probing an
+// HTTP endpoint. Exactly one of Command and URL is set.
+type CustomCheck struct {
+	// Name is shown with the built-in checks
+	Name string `mapstructure:"name" yaml:"name" json:"name"`
+
+	// Command is run with 'sh -c' where crook runs, with CROOK_NODE and
+	// CROOK_PHASE set; it passes when it exits 0
+	Command string `mapstructure:"command" yaml:"command,omitempty" json:"command,omitempty"`
+
+	// URL is fetched with GET; it passes when the response has ExpectStatus
+	URL string `mapstructure:"url" yaml:"url,omitempty" json:"url,omitempty"`
+
+	// ExpectStatus is the HTTP status the probe expects; unset means 200
+	ExpectStatus int `mapstructure:"expect-status" yaml:"expect-status,omitempty" json:"expect-status,omitempty"`
+
+	// Expect is a regular expression the command output or response body,
+	// trimmed of surrounding whitespace, must also match
+	Expect string `mapstructure:"expect" yaml:"expect,omitempty" json:"expect,omitempty"`
+
+	// Severity is "block" (default) or "warn", which lets the phase proceed
+	// once the failure is acknowledged
+	Severity string `mapstructure:"severity" yaml:"severity,omitempty" json:"severity,omitempty"`
+
+	// Phases lists the phases the check runs before ("down", "up"); unset
+	// means down only
+	Phases []string `mapstructure:"phases" yaml:"phases,omitempty" json:"phases,omitempty"`
+
+	// TimeoutSeconds bounds the check; unset means DefaultCustomCheckTimeoutSeconds
+	TimeoutSeconds int `mapstructure:"timeout-seconds" yaml:"timeout-seconds,omitempty" json:"timeout-seconds,omitempty"`
+}
+
+// RunsBefore reports whether the check runs before the phase
+func (c CustomCheck) RunsBefore(phase string) bool {
+	if len(c.Phases) == 0 {
+		return phase == PhaseDown
+	}
+	return slices.Contains(c.Phases, phase)
+}
+
+// Timeout returns how long the check may run
+func (c CustomCheck) Timeout() time.Duration {
+	if c.TimeoutSeconds <= 0 {
+		return DefaultCustomCheckTimeoutSeconds * time.Second
+	}
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// DrainConfig controls the optional drain stage of the down phase, which
+// evicts the node's remaining pods through the Eviction API so
+// PodDisruptionBudgets are honored, like 'kubectl drain'.
+type DrainConfig struct {
+	// Enabled runs the drain stage after the Rook deployments are scaled down
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+
+	// GracePeriodSeconds overrides the pods' termination grace period.
+	// Negative means each pod's own terminationGracePeriodSeconds.
+	GracePeriodSeconds int `mapstructure:"grace-period-seconds" yaml:"grace-period-seconds" json:"grace-period-seconds"`
+
+	// TimeoutSeconds bounds the whole drain, including waiting on PodDisruptionBudgets
+	TimeoutSeconds int `mapstructure:"timeout-seconds" yaml:"timeout-seconds" json:"timeout-seconds"`
+
+	// ForceDeleteOnDeadNode replaces the drain of a dead-node down phase: the
+	// pods stuck in Terminating on the node are force-deleted instead
+	ForceDeleteOnDeadNode bool `mapstructure:"force-delete-on-dead-node" yaml:"force-delete-on-dead-node" json:"force-delete-on-dead-node"`
+}
+
+// NotificationsConfig configures the maintenance windows crook opens in
+// PagerDuty and Opsgenie when a node goes down, so the services it affects
+// do not page anyone, and closes when 'crook up' completes.
+// Credentials are never rendered with the configuration.
+type NotificationsConfig struct {
+	// WindowMinutes is how long a window lasts unless 'crook up' closes it first
+	WindowMinutes int `mapstructure:"window-minutes" yaml:"window-minutes,omitempty" json:"window-minutes,omitempty"`
+
+	PagerDuty PagerDutyConfig `mapstructure:"pagerduty" yaml:"pagerduty,omitempty" json:"pagerduty,omitempty"`
+	Opsgenie  OpsgenieConfig  `mapstructure:"opsgenie" yaml:"opsgenie,omitempty" json:"opsgenie,omitempty"`
+}
+
+// PagerDutyConfig selects the PagerDuty services put in maintenance
+type PagerDutyConfig struct {
+	// Token is a REST API key (CROOK_NOTIFICATIONS_PAGERDUTY_TOKEN)
+	Token string `mapstructure:"token" yaml:"-" json:"-"`
+
+	// From is the email of the PagerDuty user the windows are created as
+	From string `mapstructure:"from" yaml:"from,omitempty" json:"from,omitempty"`
+
+	// Services are the IDs of the affected services, e.g. PIJ90N7
+	Services []string `mapstructure:"services" yaml:"services,omitempty" json:"services,omitempty"`
+}
+
+// Enabled reports whether windows are opened in PagerDuty
+func (p PagerDutyConfig) Enabled() bool {
+	return len(p.Services) > 0
+}
+
+// OpsgenieConfig selects the Opsgenie integrations put in maintenance
+type OpsgenieConfig struct {
+	// APIKey is an API integration key (CROOK_NOTIFICATIONS_OPSGENIE_API_KEY)
+	APIKey string `mapstructure:"api-key" yaml:"-" json:"-"`
+
+	// URL is the API endpoint; https://api.eu.opsgenie.com for the EU instance
+	URL string `mapstructure:"url" yaml:"url,omitempty" json:"url,omitempty"`
+
+	// Integrations are the IDs of the integrations whose alerts are suppressed
+	Integrations []string `mapstructure:"integrations" yaml:"integrations,omitempty" json:"integrations,omitempty"`
+}
+
+// Enabled reports whether windows are opened in Opsgenie
+func (o OpsgenieConfig) Enabled() bool {
+	return len(o.Integrations) > 0
+}
+
+// DiscoveryConfig adjusts the deployments discovered for a node.
+type DiscoveryConfig struct {
+	// Exclude lists deployments discovery always skips, each given as an exact
+	// name or a regular expression matched against the whole name
+	// (e.g. rook-ceph-tools, "custom-exporter-.*")
+	Exclude []string `mapstructure:"exclude" yaml:"exclude,omitempty" json:"exclude,omitempty"`
+
+	// Filters decide which deployments count as Rook-Ceph deployments in
+	// listings ('crook ls', 'crook nodes', the TUI) when the built-in name
+	// prefixes do not match a distribution's naming. Their exclude rules are
+	// skipped by discovery like Exclude.
+	Filters DeploymentFiltersConfig `mapstructure:"filters" yaml:"filters,omitempty" json:"filters,omitempty"`
+
+	// StatefulSets includes node-pinned StatefulSets in the down and up
+	// plans, scaled after the deployments. Exclude applies to them as well.
+	StatefulSets bool `mapstructure:"statefulsets" yaml:"statefulsets,omitempty" json:"statefulsets,omitempty"`
+}
+
+// DeploymentFiltersConfig selects deployments by name and labels. A
+// deployment matching any exclude rule is never selected; otherwise it is
+// selected when it matches any include rule. Without include rules the
+// built-in Rook-Ceph name prefixes are used.
+type DeploymentFiltersConfig struct {
+	Include []DeploymentFilterRule `mapstructure:"include" yaml:"include,omitempty" json:"include,omitempty"`
+	Exclude []DeploymentFilterRule `mapstructure:"exclude" yaml:"exclude,omitempty" json:"exclude,omitempty"`
+}
+
+// DeploymentFilterRule matches a deployment when every field set on it matches
+type DeploymentFilterRule struct {
+	// Prefix the deployment name starts with
+	Prefix string `mapstructure:"prefix" yaml:"prefix,omitempty" json:"prefix,omitempty"`
+
+	// Suffix the deployment name ends with
+	Suffix string `mapstructure:"suffix" yaml:"suffix,omitempty" json:"suffix,omitempty"`
+
+	// Regex matched against the whole deployment name, like discovery.exclude
+	Regex string `mapstructure:"regex" yaml:"regex,omitempty" json:"regex,omitempty"`
+
+	// Selector is a label selector the deployment's labels must match (e.g. "app=rook-ceph-osd")
+	Selector string `mapstructure:"selector" yaml:"selector,omitempty" json:"selector,omitempty"`
+}
+
+// IsEmpty reports whether no field of the rule is set
+func (r DeploymentFilterRule) IsEmpty() bool {
+	return r == DeploymentFilterRule{}
+}
+
+// Excludes reports whether discovery skips the deployment with the given name
+func (d DiscoveryConfig) Excludes(name string) bool {
+	for _, pattern := range d.Exclude {
+		if pattern == name {
+			return true
+		}
+		if re, err := CompileNamePattern(pattern); err == nil && re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompileNamePattern compiles a discovery.exclude entry or filter rule regex,
+// anchored so it has to match the whole name
+func CompileNamePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
 // DefaultConfig returns a config with all default values applied.
 func DefaultConfig() Config {
 	return Config{
 		Namespace: DefaultRookNamespace,
 		UI: UIConfig{
-			K8sRefreshMS:  DefaultK8sRefreshMS,
-			CephRefreshMS: DefaultCephRefreshMS,
+			K8sRefreshMS:    DefaultK8sRefreshMS,
+			CephRefreshMS:   DefaultCephRefreshMS,
+			Bell:            DefaultBell,
+			IdleLockSeconds: DefaultIdleLockSeconds,
+			TerminalTitle:   DefaultTerminalTitle,
+			MaintenancePane: DefaultMaintenancePane,
+			Layout:          DefaultLayout,
 		},
 		Timeouts: TimeoutConfig{
 			APICallTimeoutSeconds:        DefaultAPICallTimeoutSeconds,
@@ -69,6 +593,37 @@ func DefaultConfig() Config {
 			File:   "",
 			Format: DefaultLogFormat,
 		},
+		Ceph: CephConfig{
+			NoOutScope: DefaultNoOutScope,
+			NoOutWatchdog: NoOutWatchdogConfig{
+				TTLSeconds:     DefaultNoOutWatchdogTTLSeconds,
+				ServiceAccount: DefaultNoOutWatchdogServiceAccount,
+			},
+		},
+		Drain: DrainConfig{
+			GracePeriodSeconds: DefaultDrainGracePeriodSeconds,
+			TimeoutSeconds:     DefaultDrainTimeoutSeconds,
+		},
+		Notifications: NotificationsConfig{
+			WindowMinutes: DefaultMaintenanceWindowMinutes,
+			Opsgenie:      OpsgenieConfig{URL: DefaultOpsgenieURL},
+		},
+		Hooks: HooksConfig{
+			Reboot: RebootHook{
+				TimeoutSeconds:      DefaultRebootTimeoutSeconds,
+				ReadyTimeoutSeconds: DefaultRebootReadyTimeoutSeconds,
+			},
+			Kured: KuredHook{
+				Namespace:    DefaultKuredNamespace,
+				DaemonSet:    DefaultKuredDaemonSet,
+				SentinelPath: DefaultKuredSentinelPath,
+			},
+		},
+		SmokeTest: SmokeTestConfig{
+			Image:          DefaultSmokeTestImage,
+			Size:           DefaultSmokeTestSize,
+			TimeoutSeconds: DefaultSmokeTestTimeoutSeconds,
+		},
 	}
 }
 
@@ -23,3 +23,25 @@ func TestConfigStringIncludesSections(t *testing.T) {
 		t.Fatal("kubernetes section should not appear in YAML output")
 	}
 }
+
+func TestDiscoveryConfigExcludes(t *testing.T) {
+	discovery := config.DiscoveryConfig{Exclude: []string{"rook-ceph-tools", "custom-exporter-.*"}}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"rook-ceph-tools", true},
+		{"custom-exporter-worker-1", true},
+		{"rook-ceph-tools-extra", false}, // patterns match the whole name
+		{"rook-ceph-osd-0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := discovery.Excludes(tt.name); got != tt.want {
+				t.Errorf("Excludes(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -16,6 +16,10 @@ type LoadOptions struct {
 	ConfigFile  string
 	ConfigFiles []string
 	Flags       *pflag.FlagSet
+
+	// DefaultNamespace replaces DefaultRookNamespace when no namespace is
+	// configured (e.g. the pod namespace when running in-cluster).
+	DefaultNamespace string
 }
 
 // LoadResult contains the merged configuration and validation output.
@@ -52,7 +56,7 @@ func LoadConfig(opts LoadOptions) (LoadResult, error) {
 	if unmarshalErr := v.Unmarshal(&cfg); unmarshalErr != nil {
 		return LoadResult{}, fmt.Errorf("unmarshal config: %w", unmarshalErr)
 	}
-	applyNamespaceDefault(v, &cfg)
+	applyNamespaceDefault(v, &cfg, opts.DefaultNamespace)
 
 	validation := ValidateConfig(cfg)
 
@@ -75,6 +79,7 @@ func LoadConfig(opts LoadOptions) (LoadResult, error) {
 func BindFlags(v *viper.Viper, flags *pflag.FlagSet) error {
 	bindings := map[string]string{
 		"namespace": "namespace",
+		"cluster":   "cluster",
 		"log-level": "logging.level",
 		"log-file":  "logging.file",
 	}
@@ -96,6 +101,12 @@ func setDefaults(v *viper.Viper) {
 
 	v.SetDefault("ui.k8s-refresh-ms", defaults.UI.K8sRefreshMS)
 	v.SetDefault("ui.ceph-refresh-ms", defaults.UI.CephRefreshMS)
+	v.SetDefault("ui.bell", defaults.UI.Bell)
+	v.SetDefault("ui.desktop-notification", defaults.UI.DesktopNotification)
+	v.SetDefault("ui.idle-lock-seconds", defaults.UI.IdleLockSeconds)
+	v.SetDefault("ui.terminal-title", defaults.UI.TerminalTitle)
+	v.SetDefault("ui.maintenance-pane", defaults.UI.MaintenancePane)
+	v.SetDefault("ui.layout", defaults.UI.Layout)
 
 	v.SetDefault("timeouts.api-call-timeout-seconds", defaults.Timeouts.APICallTimeoutSeconds)
 	v.SetDefault("timeouts.wait-deployment-timeout-seconds", defaults.Timeouts.WaitDeploymentTimeoutSeconds)
@@ -104,6 +115,41 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("logging.level", defaults.Logging.Level)
 	v.SetDefault("logging.file", defaults.Logging.File)
 	v.SetDefault("logging.format", defaults.Logging.Format)
+
+	v.SetDefault("ceph.noout-scope", defaults.Ceph.NoOutScope)
+	v.SetDefault("ceph.noout-watchdog.enabled", defaults.Ceph.NoOutWatchdog.Enabled)
+	v.SetDefault("ceph.noout-watchdog.ttl-seconds", defaults.Ceph.NoOutWatchdog.TTLSeconds)
+	v.SetDefault("ceph.noout-watchdog.service-account", defaults.Ceph.NoOutWatchdog.ServiceAccount)
+
+	v.SetDefault("drain.enabled", defaults.Drain.Enabled)
+	v.SetDefault("drain.grace-period-seconds", defaults.Drain.GracePeriodSeconds)
+	v.SetDefault("drain.timeout-seconds", defaults.Drain.TimeoutSeconds)
+	v.SetDefault("drain.force-delete-on-dead-node", defaults.Drain.ForceDeleteOnDeadNode)
+
+	v.SetDefault("discovery.statefulsets", defaults.Discovery.StatefulSets)
+
+	v.SetDefault("cloud.disable-scale-down", defaults.Cloud.DisableScaleDown)
+
+	v.SetDefault("smoke-test.enabled", defaults.SmokeTest.Enabled)
+	v.SetDefault("smoke-test.image", defaults.SmokeTest.Image)
+	v.SetDefault("smoke-test.size", defaults.SmokeTest.Size)
+	v.SetDefault("smoke-test.timeout-seconds", defaults.SmokeTest.TimeoutSeconds)
+
+	// Set so CROOK_TELEMETRY_ENABLED and CROOK_TELEMETRY_ENDPOINT are picked up
+	v.SetDefault("telemetry.enabled", defaults.Telemetry.Enabled)
+	v.SetDefault("telemetry.endpoint", defaults.Telemetry.Endpoint)
+
+	v.SetDefault("hooks.reboot.timeout-seconds", defaults.Hooks.Reboot.TimeoutSeconds)
+	v.SetDefault("hooks.reboot.ready-timeout-seconds", defaults.Hooks.Reboot.ReadyTimeoutSeconds)
+	v.SetDefault("hooks.kured.namespace", defaults.Hooks.Kured.Namespace)
+	v.SetDefault("hooks.kured.daemon-set", defaults.Hooks.Kured.DaemonSet)
+	v.SetDefault("hooks.kured.sentinel-path", defaults.Hooks.Kured.SentinelPath)
+
+	// Credentials have defaults so the environment variables are picked up
+	v.SetDefault("notifications.window-minutes", defaults.Notifications.WindowMinutes)
+	v.SetDefault("notifications.pagerduty.token", defaults.Notifications.PagerDuty.Token)
+	v.SetDefault("notifications.opsgenie.api-key", defaults.Notifications.Opsgenie.APIKey)
+	v.SetDefault("notifications.opsgenie.url", defaults.Notifications.Opsgenie.URL)
 }
 
 func configureEnv(v *viper.Viper) {
@@ -159,7 +205,7 @@ func defaultConfigFiles() []string {
 }
 
 // applyNamespaceDefault applies default namespace if not set via config/env/flag.
-func applyNamespaceDefault(v *viper.Viper, cfg *Config) {
+func applyNamespaceDefault(v *viper.Viper, cfg *Config, fallback string) {
 	if cfg == nil {
 		return
 	}
@@ -168,6 +214,9 @@ func applyNamespaceDefault(v *viper.Viper, cfg *Config) {
 	if namespace != "" {
 		cfg.Namespace = namespace
 	} else if cfg.Namespace == "" {
-		cfg.Namespace = DefaultRookNamespace
+		cfg.Namespace = fallback
+		if cfg.Namespace == "" {
+			cfg.Namespace = DefaultRookNamespace
+		}
 	}
 }
@@ -30,6 +30,18 @@ func TestLoadConfigDefaults(t *testing.T) {
 	if cfg.Timeouts.APICallTimeoutSeconds != config.DefaultAPICallTimeoutSeconds {
 		t.Fatalf("expected api timeout default %d, got %d", config.DefaultAPICallTimeoutSeconds, cfg.Timeouts.APICallTimeoutSeconds)
 	}
+	if cfg.Policy.GatesHealth() {
+		t.Fatalf("expected default policy not to gate on health, got %+v", cfg.Policy)
+	}
+	if cfg.Drain.Enabled || cfg.Drain.ForceDeleteOnDeadNode || cfg.Drain.TimeoutSeconds != config.DefaultDrainTimeoutSeconds {
+		t.Fatalf("expected drain disabled with default timeout, got %+v", cfg.Drain)
+	}
+	if cfg.SmokeTest.Enabled || cfg.SmokeTest.Image != config.DefaultSmokeTestImage || cfg.SmokeTest.TimeoutSeconds != config.DefaultSmokeTestTimeoutSeconds {
+		t.Fatalf("expected smoke test disabled with default image and timeout, got %+v", cfg.SmokeTest)
+	}
+	if cfg.Cloud.DisableScaleDown {
+		t.Fatalf("expected autoscaler scale-down left alone by default, got %+v", cfg.Cloud)
+	}
 	if result.Validation.HasErrors() {
 		t.Fatalf("unexpected validation errors: %v", result.Validation.Errors)
 	}
@@ -107,6 +119,21 @@ func TestLoadConfigFromFileFixture(t *testing.T) {
 	if cfg.Logging.Format != "json" {
 		t.Fatalf("expected log format from file, got %q", cfg.Logging.Format)
 	}
+	if len(cfg.Ceph.Commands.Args) != 2 || cfg.Ceph.Commands.Args[0] != "--connect-timeout" {
+		t.Fatalf("expected ceph command args from file, got %v", cfg.Ceph.Commands.Args)
+	}
+	if len(cfg.Ceph.Commands.Overrides) != 1 || cfg.Ceph.Commands.Overrides[0].Match != "ceph status --format json" {
+		t.Fatalf("expected ceph command override from file, got %+v", cfg.Ceph.Commands.Overrides)
+	}
+	if !cfg.Policy.RequireHealthOKBeforeDown || cfg.Policy.NearFullAllowed() {
+		t.Fatalf("expected health policy from file, got %+v", cfg.Policy)
+	}
+	if cfg.Policy.MaxDegradedPGsPercent == nil || *cfg.Policy.MaxDegradedPGsPercent != 5 {
+		t.Fatalf("expected max degraded PGs percent from file, got %v", cfg.Policy.MaxDegradedPGsPercent)
+	}
+	if !cfg.Policy.RequireApprovalBeforeDown {
+		t.Fatalf("expected approval policy from file, got %+v", cfg.Policy)
+	}
 	if result.Validation.HasErrors() {
 		t.Fatalf("unexpected validation errors: %v", result.Validation.Errors)
 	}
@@ -134,6 +161,9 @@ func TestLoadConfigPartialUsesDefaults(t *testing.T) {
 	if cfg.UI.CephRefreshMS != config.DefaultCephRefreshMS {
 		t.Fatalf("expected default ceph refresh, got %d", cfg.UI.CephRefreshMS)
 	}
+	if cfg.UI.Bell != config.DefaultBell {
+		t.Fatalf("expected default bell, got %v", cfg.UI.Bell)
+	}
 }
 
 func TestLoadConfigEnvOverridesDefault(t *testing.T) {
@@ -157,6 +187,48 @@ func TestLoadConfigEnvOverridesDefault(t *testing.T) {
 	}
 }
 
+func TestLoadConfigTelemetryFromEnv(t *testing.T) {
+	result, err := config.LoadConfig(config.LoadOptions{ConfigFile: testdataPath(t, "partial.yaml")})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if result.Config.Telemetry.Enabled {
+		t.Fatal("expected telemetry to be off by default")
+	}
+
+	t.Setenv("CROOK_TELEMETRY_ENABLED", "true")
+	t.Setenv("CROOK_TELEMETRY_ENDPOINT", "https://telemetry.example.com/v1/events")
+
+	result, err = config.LoadConfig(config.LoadOptions{ConfigFile: testdataPath(t, "partial.yaml")})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if telemetry := result.Config.Telemetry; !telemetry.Enabled || telemetry.Endpoint != "https://telemetry.example.com/v1/events" {
+		t.Fatalf("expected telemetry from env, got %+v", telemetry)
+	}
+}
+
+func TestLoadConfigNotificationCredentialsFromEnv(t *testing.T) {
+	t.Setenv("CROOK_NOTIFICATIONS_PAGERDUTY_TOKEN", "pd-token")
+	t.Setenv("CROOK_NOTIFICATIONS_OPSGENIE_API_KEY", "og-key")
+
+	result, err := config.LoadConfig(config.LoadOptions{ConfigFile: testdataPath(t, "partial.yaml")})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	cfg := result.Config
+	if cfg.Notifications.PagerDuty.Token != "pd-token" || cfg.Notifications.Opsgenie.APIKey != "og-key" {
+		t.Fatalf("expected credentials from env, got %+v", cfg.Notifications)
+	}
+	if cfg.Notifications.Opsgenie.URL != config.DefaultOpsgenieURL {
+		t.Fatalf("expected default opsgenie url, got %q", cfg.Notifications.Opsgenie.URL)
+	}
+	if strings.Contains(cfg.String(), "pd-token") {
+		t.Fatal("credentials must not be rendered with the configuration")
+	}
+}
+
 func TestLoadConfigConfigFileDiscovery(t *testing.T) {
 	tempDir := t.TempDir()
 	missing := filepath.Join(tempDir, "missing.yaml")
@@ -312,3 +384,27 @@ func testdataPath(t *testing.T, name string) string {
 	t.Helper()
 	return filepath.Join("testdata", name)
 }
+
+func TestLoadConfigDefaultNamespaceOption(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("# empty\n"), 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+
+	result, err := config.LoadConfig(config.LoadOptions{ConfigFile: configPath, DefaultNamespace: "pod-ns"})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if result.Config.Namespace != "pod-ns" {
+		t.Fatalf("expected fallback namespace, got %q", result.Config.Namespace)
+	}
+
+	t.Setenv("CROOK_NAMESPACE", "env-ns")
+	result, err = config.LoadConfig(config.LoadOptions{ConfigFile: configPath, DefaultNamespace: "pod-ns"})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if result.Config.Namespace != "env-ns" {
+		t.Fatalf("expected env to take precedence over fallback namespace, got %q", result.Config.Namespace)
+	}
+}
@@ -3,9 +3,13 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"regexp"
 	"slices"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/validation"
 )
 
@@ -67,6 +71,13 @@ func ValidateConfig(cfg Config) ValidationResult {
 		result.Errors = append(result.Errors, err)
 	}
 
+	if cfg.Cluster != "" {
+		if errs := validation.IsDNS1123Subdomain(cfg.Cluster); len(errs) > 0 {
+			result.Errors = append(result.Errors, fmt.Errorf(
+				"invalid cluster '%s': must be a CephCluster resource name", cfg.Cluster))
+		}
+	}
+
 	for _, timeout := range []int{
 		cfg.Timeouts.APICallTimeoutSeconds,
 		cfg.Timeouts.WaitDeploymentTimeoutSeconds,
@@ -91,6 +102,114 @@ func ValidateConfig(cfg Config) ValidationResult {
 			cfg.Logging.Format, allowedLogFormats))
 	}
 
+	if cfg.UI.IdleLockSeconds < 0 {
+		result.Errors = append(result.Errors, fmt.Errorf(
+			"ui.idle-lock-seconds must be >= 0, got: %d", cfg.UI.IdleLockSeconds))
+	}
+
+	// Validate ui.layout
+	if cfg.UI.Layout != "" && !slices.Contains(Layouts, cfg.UI.Layout) {
+		result.Errors = append(result.Errors, fmt.Errorf(
+			"invalid ui.layout %q: allowed values are %v",
+			cfg.UI.Layout, Layouts))
+	}
+
+	// Validate ui.desktop-notification
+	if cfg.UI.DesktopNotification != "" && !slices.Contains(DesktopNotifications, cfg.UI.DesktopNotification) {
+		result.Errors = append(result.Errors, fmt.Errorf(
+			"invalid ui.desktop-notification %q: allowed values are %v",
+			cfg.UI.DesktopNotification, DesktopNotifications))
+	}
+
+	// Validate ceph command overrides: both sides are required
+	for i, override := range cfg.Ceph.Commands.Overrides {
+		if strings.TrimSpace(override.Match) == "" || strings.TrimSpace(override.Command) == "" {
+			result.Errors = append(result.Errors, fmt.Errorf(
+				"ceph.commands.overrides[%d]: both match and command must be set", i))
+		}
+	}
+
+	// Validate ceph.noout-scope
+	if cfg.Ceph.NoOutScope != "" && !slices.Contains(NoOutScopes, cfg.Ceph.NoOutScope) {
+		result.Errors = append(result.Errors, fmt.Errorf(
+			"invalid ceph.noout-scope %q: allowed values are %v",
+			cfg.Ceph.NoOutScope, NoOutScopes))
+	}
+	if err := validateNoOutWatchdog(cfg.Ceph.NoOutWatchdog); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("ceph.noout-watchdog: %w", err))
+	}
+
+	// Validate policy thresholds
+	if p := cfg.Policy.MaxDegradedPGsPercent; p != nil && (*p < 0 || *p > 100) {
+		result.Errors = append(result.Errors, fmt.Errorf(
+			"policy.max-degraded-pgs-percent must be between 0 and 100, got: %g", *p))
+	}
+
+	// Validate the drain stage timeout
+	if cfg.Drain.TimeoutSeconds < 1 {
+		result.Errors = append(result.Errors, fmt.Errorf(
+			"drain.timeout-seconds must be >= 1, got: %d", cfg.Drain.TimeoutSeconds))
+	}
+
+	// Validate maintenance windows: enabled tools need their credentials
+	if cfg.Notifications.WindowMinutes < 1 {
+		result.Errors = append(result.Errors, fmt.Errorf(
+			"notifications.window-minutes must be >= 1, got: %d", cfg.Notifications.WindowMinutes))
+	}
+	if pd := cfg.Notifications.PagerDuty; pd.Enabled() && (pd.Token == "" || pd.From == "") {
+		result.Errors = append(result.Errors, errors.New(
+			"notifications.pagerduty: token and from are required with services"))
+	}
+	if og := cfg.Notifications.Opsgenie; og.Enabled() && (og.APIKey == "" || og.URL == "") {
+		result.Errors = append(result.Errors, errors.New(
+			"notifications.opsgenie: api-key and url are required with integrations"))
+	}
+
+	// Validate custom pre-flight checks
+	for i, check := range cfg.Preflight.Checks {
+		if err := validateCustomCheck(check); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("preflight.checks[%d]: %w", i, err))
+		}
+	}
+
+	// Validate the reboot hook: one mechanism, positive timeouts
+	if err := validateRebootHook(cfg.Hooks.Reboot); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("hooks.reboot: %w", err))
+	}
+	if err := validateKuredHook(cfg.Hooks.Kured); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("hooks.kured: %w", err))
+	}
+
+	// Validate the smoke test stage of the up phase
+	if err := validateSmokeTest(cfg.SmokeTest); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("smoke-test: %w", err))
+	}
+
+	// Validate the telemetry endpoint
+	if err := validateTelemetry(cfg.Telemetry); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("telemetry: %w", err))
+	}
+
+	// Validate discovery exclusions: each entry must be a name or a valid regex
+	for i, pattern := range cfg.Discovery.Exclude {
+		if err := ValidateExcludePattern(pattern); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("discovery.exclude[%d]: %w", i, err))
+		}
+	}
+
+	// Validate deployment filter rules: each needs a condition, and regexes and
+	// selectors must parse
+	for i, rule := range cfg.Discovery.Filters.Include {
+		if err := validateFilterRule(rule); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("discovery.filters.include[%d]: %w", i, err))
+		}
+	}
+	for i, rule := range cfg.Discovery.Filters.Exclude {
+		if err := validateFilterRule(rule); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("discovery.filters.exclude[%d]: %w", i, err))
+		}
+	}
+
 	// Validate refresh intervals: must be > 0
 	if cfg.UI.K8sRefreshMS <= 0 {
 		result.Errors = append(result.Errors, fmt.Errorf(
@@ -123,3 +242,169 @@ func validateNamespace(namespace string) error {
 	}
 	return nil
 }
+
+// ValidateExcludePattern checks a discovery exclusion: an exact deployment
+// name or a regular expression matched against the whole name
+func ValidateExcludePattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("exclusion must not be empty")
+	}
+	if _, err := CompileNamePattern(pattern); err != nil {
+		return fmt.Errorf("%q is not a valid regular expression: %w", pattern, err)
+	}
+	return nil
+}
+
+// validateRebootHook checks the reboot hook sets at most one mechanism, a
+// URL that parses, and positive timeouts
+func validateRebootHook(hook RebootHook) error {
+	set := 0
+	for _, mechanism := range []string{hook.Command, hook.URL, hook.BareMetalHostNamespace} {
+		if strings.TrimSpace(mechanism) != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return errors.New("set only one of command, url and bare-metal-host-namespace")
+	}
+	if hook.URL != "" {
+		u, err := url.Parse(hook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("url %q must be an http or https URL", hook.URL)
+		}
+	}
+	if hook.TimeoutSeconds < 1 {
+		return fmt.Errorf("timeout-seconds must be >= 1, got: %d", hook.TimeoutSeconds)
+	}
+	if hook.ReadyTimeoutSeconds < 1 {
+		return fmt.Errorf("ready-timeout-seconds must be >= 1, got: %d", hook.ReadyTimeoutSeconds)
+	}
+	return nil
+}
+
+// validateKuredHook checks the kured DaemonSet reference is a valid name
+func validateKuredHook(hook KuredHook) error {
+	if err := validateNamespace(hook.Namespace); err != nil {
+		return err
+	}
+	if errs := validation.IsDNS1123Subdomain(hook.DaemonSet); len(errs) > 0 {
+		return fmt.Errorf("invalid daemon-set '%s': must be a DaemonSet name", hook.DaemonSet)
+	}
+	return nil
+}
+
+// validateSmokeTest checks the smoke test names valid storage classes, a
+// namespace, an image, a quantity and a positive timeout; an enabled test
+// needs at least one storage class
+func validateSmokeTest(test SmokeTestConfig) error {
+	if test.Enabled && len(test.StorageClasses) == 0 {
+		return errors.New("storage-classes must list at least one storage class when enabled")
+	}
+	for _, class := range test.StorageClasses {
+		if errs := validation.IsDNS1123Subdomain(class); len(errs) > 0 {
+			return fmt.Errorf("invalid storage class '%s': must be a StorageClass name", class)
+		}
+	}
+	if test.Namespace != "" {
+		if err := validateNamespace(test.Namespace); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(test.Image) == "" {
+		return errors.New("image must not be empty")
+	}
+	if _, err := resource.ParseQuantity(test.Size); err != nil {
+		return fmt.Errorf("invalid size %q: %w", test.Size, err)
+	}
+	if test.TimeoutSeconds < 1 {
+		return fmt.Errorf("timeout-seconds must be >= 1, got: %d", test.TimeoutSeconds)
+	}
+	return nil
+}
+
+// validateNoOutWatchdog checks the watchdog has a positive TTL, and an image
+// and a service account when enabled
+func validateNoOutWatchdog(watchdog NoOutWatchdogConfig) error {
+	if watchdog.TTLSeconds < 1 {
+		return fmt.Errorf("ttl-seconds must be >= 1, got: %d", watchdog.TTLSeconds)
+	}
+	if !watchdog.Enabled {
+		return nil
+	}
+	if strings.TrimSpace(watchdog.Image) == "" {
+		return errors.New("image must be set to the crook image when enabled")
+	}
+	if errs := validation.IsDNS1123Subdomain(watchdog.ServiceAccount); len(errs) > 0 {
+		return fmt.Errorf("invalid service-account '%s': must be a ServiceAccount name", watchdog.ServiceAccount)
+	}
+	return nil
+}
+
+// validateTelemetry checks enabled telemetry has an http or https endpoint
+func validateTelemetry(telemetry TelemetryConfig) error {
+	if !telemetry.Enabled {
+		return nil
+	}
+	u, err := url.Parse(telemetry.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("endpoint %q must be an http or https URL when enabled", telemetry.Endpoint)
+	}
+	return nil
+}
+
+// validateCustomCheck checks a custom pre-flight check is named, runs either
+// a command or an HTTP probe, and that its expectations parse
+func validateCustomCheck(check CustomCheck) error {
+	if strings.TrimSpace(check.Name) == "" {
+		return errors.New("name must be set")
+	}
+	if (check.Command == "") == (check.URL == "") {
+		return fmt.Errorf("check %q must set exactly one of command and url", check.Name)
+	}
+	if check.URL != "" {
+		u, err := url.Parse(check.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("check %q: url %q must be an http or https URL", check.Name, check.URL)
+		}
+	}
+	if check.ExpectStatus != 0 && check.Command != "" {
+		return fmt.Errorf("check %q: expect-status only applies to url checks", check.Name)
+	}
+	if check.Expect != "" {
+		if _, err := regexp.Compile(check.Expect); err != nil {
+			return fmt.Errorf("check %q: invalid expect %q: %w", check.Name, check.Expect, err)
+		}
+	}
+	if check.Severity != "" && check.Severity != CheckSeverityBlock && check.Severity != CheckSeverityWarn {
+		return fmt.Errorf("check %q: severity must be %q or %q, got: %q",
+			check.Name, CheckSeverityBlock, CheckSeverityWarn, check.Severity)
+	}
+	for _, phase := range check.Phases {
+		if phase != PhaseDown && phase != PhaseUp {
+			return fmt.Errorf("check %q: phases must be %q or %q, got: %q", check.Name, PhaseDown, PhaseUp, phase)
+		}
+	}
+	if check.TimeoutSeconds < 0 {
+		return fmt.Errorf("check %q: timeout-seconds must be >= 0, got: %d", check.Name, check.TimeoutSeconds)
+	}
+	return nil
+}
+
+// validateFilterRule checks a deployment filter rule sets at least one
+// condition and that its regex and label selector parse
+func validateFilterRule(rule DeploymentFilterRule) error {
+	if rule.IsEmpty() {
+		return fmt.Errorf("rule must set prefix, suffix, regex or selector")
+	}
+	if rule.Regex != "" {
+		if _, err := CompileNamePattern(rule.Regex); err != nil {
+			return fmt.Errorf("invalid regex %q: %w", rule.Regex, err)
+		}
+	}
+	if rule.Selector != "" {
+		if _, err := labels.Parse(rule.Selector); err != nil {
+			return fmt.Errorf("invalid selector %q: %w", rule.Selector, err)
+		}
+	}
+	return nil
+}
@@ -89,6 +89,136 @@ func TestValidateConfigLoggingFormat(t *testing.T) {
 	}
 }
 
+func TestValidateConfigDesktopNotification(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"empty valid", "", false},
+		{"osc9 valid", DesktopNotificationOSC9, false},
+		{"osc777 valid", DesktopNotificationOSC777, false},
+		{"invalid value", "osc99", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.UI.DesktopNotification = tt.value
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "invalid ui.desktop-notification")
+			if hasErr != tt.wantErr {
+				t.Errorf("desktop-notification=%q: wantErr=%v, gotErr=%v, errors=%v",
+					tt.value, tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  string
+		wantErr bool
+	}{
+		{"default", DefaultLayout, false},
+		{"empty valid", "", false},
+		{"wide valid", LayoutWide, false},
+		{"invalid layout", "columns", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.UI.Layout = tt.layout
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "invalid ui.layout")
+			if hasErr != tt.wantErr {
+				t.Errorf("layout=%q: wantErr=%v, gotErr=%v, errors=%v",
+					tt.layout, tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigNoOutScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   string
+		wantErr bool
+	}{
+		{"default", DefaultNoOutScope, false},
+		{"empty valid", "", false},
+		{"host valid", NoOutScopeHost, false},
+		{"osd valid", NoOutScopeOSD, false},
+		{"invalid scope", "rack", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Ceph.NoOutScope = tt.scope
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "invalid ceph.noout-scope")
+			if hasErr != tt.wantErr {
+				t.Errorf("scope=%q: wantErr=%v, gotErr=%v, errors=%v",
+					tt.scope, tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigNoOutWatchdog(t *testing.T) {
+	tests := []struct {
+		name     string
+		watchdog func(*NoOutWatchdogConfig)
+		wantErr  bool
+	}{
+		{"disabled", func(*NoOutWatchdogConfig) {}, false},
+		{"enabled", func(w *NoOutWatchdogConfig) { w.Enabled = true; w.Image = "registry.example.com/crook:v1.2.0" }, false},
+		{"enabled without image", func(w *NoOutWatchdogConfig) { w.Enabled = true }, true},
+		{"invalid service account", func(w *NoOutWatchdogConfig) { w.Enabled = true; w.Image = "crook"; w.ServiceAccount = "Crook Admin" }, true},
+		{"zero ttl", func(w *NoOutWatchdogConfig) { w.TTLSeconds = 0 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.watchdog(&cfg.Ceph.NoOutWatchdog)
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "ceph.noout-watchdog")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigTelemetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		telemetry TelemetryConfig
+		wantErr   bool
+	}{
+		{"default off", TelemetryConfig{}, false},
+		{"enabled", TelemetryConfig{Enabled: true, Endpoint: "https://telemetry.example.com/v1/events"}, false},
+		{"enabled without endpoint", TelemetryConfig{Enabled: true}, true},
+		{"invalid endpoint", TelemetryConfig{Enabled: true, Endpoint: "telemetry.example.com"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Telemetry = tt.telemetry
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "telemetry")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
 func TestValidateConfigRefreshIntervals(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -126,6 +256,342 @@ func TestValidateConfigRefreshIntervals(t *testing.T) {
 	}
 }
 
+func TestValidateConfigCephCommandOverrides(t *testing.T) {
+	tests := []struct {
+		name     string
+		override CephCommandOverride
+		wantErr  bool
+	}{
+		{"complete", CephCommandOverride{Match: "ceph status --format json", Command: "ceph --id admin status --format json"}, false},
+		{"missing match", CephCommandOverride{Command: "ceph status"}, true},
+		{"blank command", CephCommandOverride{Match: "ceph status", Command: "  "}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Ceph.Commands.Overrides = []CephCommandOverride{tt.override}
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "ceph.commands.overrides[0]")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigPolicyDegradedPGs(t *testing.T) {
+	tests := []struct {
+		name    string
+		percent *float64
+		wantErr bool
+	}{
+		{"unset", nil, false},
+		{"zero", ptr(0.0), false},
+		{"hundred", ptr(100.0), false},
+		{"negative", ptr(-1.0), true},
+		{"above hundred", ptr(150.0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Policy.MaxDegradedPGsPercent = tt.percent
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "policy.max-degraded-pgs-percent")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigDrainTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout int
+		wantErr bool
+	}{
+		{"default", DefaultDrainTimeoutSeconds, false},
+		{"one second", 1, false},
+		{"zero", 0, true},
+		{"negative", -5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Drain.TimeoutSeconds = tt.timeout
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "drain.timeout-seconds")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigRebootHook(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    func(*RebootHook)
+		wantErr bool
+	}{
+		{"unset", func(*RebootHook) {}, false},
+		{"command", func(h *RebootHook) { h.Command = "ssh root@$CROOK_NODE systemctl reboot" }, false},
+		{"url", func(h *RebootHook) { h.URL = "https://power.example.com/reboot" }, false},
+		{"metal3", func(h *RebootHook) { h.BareMetalHostNamespace = "metal3" }, false},
+		{"two mechanisms", func(h *RebootHook) { h.Command = "true"; h.URL = "https://power.example.com" }, true},
+		{"not an http url", func(h *RebootHook) { h.URL = "ftp://power.example.com" }, true},
+		{"zero timeout", func(h *RebootHook) { h.TimeoutSeconds = 0 }, true},
+		{"zero ready timeout", func(h *RebootHook) { h.ReadyTimeoutSeconds = 0 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.hook(&cfg.Hooks.Reboot)
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "hooks.reboot")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigKuredHook(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    func(*KuredHook)
+		wantErr bool
+	}{
+		{"defaults", func(*KuredHook) {}, false},
+		{"custom daemonset", func(h *KuredHook) { h.Namespace = "kured"; h.DaemonSet = "kured-daemon" }, false},
+		{"empty namespace", func(h *KuredHook) { h.Namespace = "" }, true},
+		{"invalid daemonset", func(h *KuredHook) { h.DaemonSet = "Kured_DS" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.hook(&cfg.Hooks.Kured)
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "hooks.kured")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigSmokeTest(t *testing.T) {
+	tests := []struct {
+		name    string
+		test    func(*SmokeTestConfig)
+		wantErr bool
+	}{
+		{"disabled", func(*SmokeTestConfig) {}, false},
+		{"enabled", func(s *SmokeTestConfig) { s.Enabled = true; s.StorageClasses = []string{"ceph-block", "cephfs"} }, false},
+		{"enabled without classes", func(s *SmokeTestConfig) { s.Enabled = true }, true},
+		{"invalid class", func(s *SmokeTestConfig) { s.StorageClasses = []string{"Ceph Block"} }, true},
+		{"invalid namespace", func(s *SmokeTestConfig) { s.Namespace = "Smoke_Tests" }, true},
+		{"empty image", func(s *SmokeTestConfig) { s.Image = "" }, true},
+		{"invalid size", func(s *SmokeTestConfig) { s.Size = "one gig" }, true},
+		{"zero timeout", func(s *SmokeTestConfig) { s.TimeoutSeconds = 0 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.test(&cfg.SmokeTest)
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "smoke-test")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigIdleLock(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds int
+		wantErr bool
+	}{
+		{"default", DefaultIdleLockSeconds, false},
+		{"disabled", 0, false},
+		{"negative", -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.UI.IdleLockSeconds = tt.seconds
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "ui.idle-lock-seconds")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigNotifications(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*NotificationsConfig)
+		wantErr bool
+	}{
+		{"default", func(*NotificationsConfig) {}, false},
+		{"zero window", func(n *NotificationsConfig) { n.WindowMinutes = 0 }, true},
+		{"pagerduty complete", func(n *NotificationsConfig) {
+			n.PagerDuty = PagerDutyConfig{Token: "t", From: "ops@example.com", Services: []string{"P1"}}
+		}, false},
+		{"pagerduty without from", func(n *NotificationsConfig) {
+			n.PagerDuty = PagerDutyConfig{Token: "t", Services: []string{"P1"}}
+		}, true},
+		{"opsgenie without api key", func(n *NotificationsConfig) {
+			n.Opsgenie.Integrations = []string{"i1"}
+		}, true},
+		{"credentials without services", func(n *NotificationsConfig) { n.PagerDuty.Token = "t" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(&cfg.Notifications)
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "notifications.")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigPreflightChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   CustomCheck
+		wantErr bool
+	}{
+		{"command", CustomCheck{Name: "backup", Command: "! pgrep backup"}, false},
+		{"url with expectations", CustomCheck{Name: "freeze", URL: "https://freeze.example.com/status", ExpectStatus: 200, Expect: `"frozen":\s*false`, Severity: "warn"}, false},
+		{"both phases", CustomCheck{Name: "backup", Command: "true", Phases: []string{"down", "up"}}, false},
+		{"missing name", CustomCheck{Command: "true"}, true},
+		{"neither command nor url", CustomCheck{Name: "empty"}, true},
+		{"command and url", CustomCheck{Name: "both", Command: "true", URL: "https://example.com"}, true},
+		{"url without scheme", CustomCheck{Name: "freeze", URL: "freeze.example.com"}, true},
+		{"expect-status on a command", CustomCheck{Name: "backup", Command: "true", ExpectStatus: 200}, true},
+		{"invalid expect", CustomCheck{Name: "backup", Command: "true", Expect: "("}, true},
+		{"unknown severity", CustomCheck{Name: "backup", Command: "true", Severity: "fatal"}, true},
+		{"unknown phase", CustomCheck{Name: "backup", Command: "true", Phases: []string{"drain"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Preflight.Checks = []CustomCheck{tt.check}
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "preflight.checks[0]")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigDiscoveryExclude(t *testing.T) {
+	tests := []struct {
+		name    string
+		exclude []string
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"exact name", []string{"rook-ceph-tools"}, false},
+		{"regex", []string{"custom-exporter-.*"}, false},
+		{"empty entry", []string{" "}, true},
+		{"invalid regex", []string{"custom-(exporter"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Discovery.Exclude = tt.exclude
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "discovery.exclude")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigDiscoveryFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters DeploymentFiltersConfig
+		wantErr string
+	}{
+		{"none", DeploymentFiltersConfig{}, ""},
+		{"valid rules", DeploymentFiltersConfig{
+			Include: []DeploymentFilterRule{{Prefix: "rook-ceph-"}, {Regex: "osd-[0-9]+", Selector: "app=rook-ceph-osd"}},
+			Exclude: []DeploymentFilterRule{{Suffix: "-tools"}},
+		}, ""},
+		{"empty include rule", DeploymentFiltersConfig{Include: []DeploymentFilterRule{{}}}, "discovery.filters.include[0]"},
+		{"invalid regex", DeploymentFiltersConfig{Include: []DeploymentFilterRule{{Regex: "osd-("}}}, "discovery.filters.include[0]"},
+		{"invalid selector", DeploymentFiltersConfig{Exclude: []DeploymentFilterRule{{Selector: "app in (a"}}}, "discovery.filters.exclude[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Discovery.Filters = tt.filters
+			result := ValidateConfig(cfg)
+			if tt.wantErr == "" {
+				if hasErrorContaining(result.Errors, "discovery.filters") {
+					t.Errorf("unexpected errors: %v", result.Errors)
+				}
+				return
+			}
+			if !hasErrorContaining(result.Errors, tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfigCluster(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster string
+		wantErr bool
+	}{
+		{"unset", "", false},
+		{"resource name", "my-cluster", false},
+		{"uppercase", "MyCluster", true},
+		{"label selector", "a,b", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Cluster = tt.cluster
+			result := ValidateConfig(cfg)
+			hasErr := hasErrorContaining(result.Errors, "invalid cluster")
+			if hasErr != tt.wantErr {
+				t.Errorf("wantErr=%v, gotErr=%v, errors=%v", tt.wantErr, hasErr, result.Errors)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
 func TestValidationErrorMessage(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.Namespace = "invalid!"
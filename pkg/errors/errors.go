@@ -0,0 +1,164 @@
+// Package errors classifies common crook failures and attaches remediation
+// hints to them, so the CLI and the TUI error views can tell the user what to
+// do next instead of only what went wrong.
+//
+// Errors built with Wrap or the constructors carry their hint explicitly.
+// Hint also derives one for errors that were never wrapped, from Kubernetes
+// API status errors (RBAC denied, expired credentials) and context deadlines.
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Kind classifies a failure with a known remedy
+type Kind string
+
+// Failure kinds
+const (
+	// KindRBACDenied is a request the user's role does not allow
+	KindRBACDenied Kind = "rbac-denied"
+
+	// KindUnauthorized is a request rejected for missing or expired credentials
+	KindUnauthorized Kind = "unauthorized"
+
+	// KindToolboxMissing is a missing or unready rook-ceph-tools pod
+	KindToolboxMissing Kind = "toolbox-missing"
+
+	// KindNodeNotFound is a node name the cluster does not know
+	KindNodeNotFound Kind = "node-not-found"
+
+	// KindNodeAmbiguous is a partial node name matching several nodes
+	KindNodeAmbiguous Kind = "node-ambiguous"
+
+	// KindTimeout is an operation that ran past its deadline
+	KindTimeout Kind = "timeout"
+
+	// KindUnacknowledged is a phase stopped by pre-flight warnings the user
+	// has not acknowledged
+	KindUnacknowledged Kind = "unacknowledged"
+
+	// KindControlPlaneNode is a down phase refused for a control-plane node
+	KindControlPlaneNode Kind = "control-plane-node"
+)
+
+// Hints of the failure kinds that are derived rather than wrapped
+const (
+	hintRBACDenied   = "ask a cluster admin to grant the missing permission; 'crook check rbac' lists every permission crook needs"
+	hintUnauthorized = "refresh your cluster credentials (e.g. 'aws sso login', 'gcloud auth login') or check --kubeconfig and --context"
+	hintTimeout      = "the cluster may be degraded or slow; retry with a larger --timeout or raise the timeouts section of the config file"
+)
+
+// Error is a failure with a remediation hint
+type Error struct {
+	// Kind classifies the failure
+	Kind Kind
+
+	// Hint tells the user how to fix the failure
+	Hint string
+
+	// Err is the underlying error
+	Err error
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap attaches a kind and hint to err. It returns nil for a nil err.
+func Wrap(kind Kind, err error, hint string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Hint: hint, Err: err}
+}
+
+// NodeNotFound returns the error for a node name the cluster does not know
+func NodeNotFound(name string) error {
+	return &Error{
+		Kind: KindNodeNotFound,
+		Hint: "list the node names with 'crook nodes' or 'kubectl get nodes'; a unique part of a name, like its first label, is enough",
+		Err:  fmt.Errorf("node %q not found in cluster", name),
+	}
+}
+
+// NodeAmbiguous returns the error for a partial node name matching several
+// nodes
+func NodeAmbiguous(name string, candidates []string) error {
+	return &Error{
+		Kind: KindNodeAmbiguous,
+		Hint: "pass the full node name, or run interactively to pick one",
+		Err:  fmt.Errorf("node name %q is ambiguous, matching %s", name, strings.Join(candidates, ", ")),
+	}
+}
+
+// NodePartialMatch returns the error for a node name that only matches a node
+// by prefix or substring, which commands acting on the node need confirmed
+func NodePartialMatch(name, match string) error {
+	return &Error{
+		Kind: KindNodeAmbiguous,
+		Hint: "pass the full node name or its first label, or run without --yes to confirm the match",
+		Err:  fmt.Errorf("node name %q only partially matches %s", name, match),
+	}
+}
+
+// ToolboxMissing wraps err, the failure to find a ready rook-ceph-tools pod
+func ToolboxMissing(namespace string, err error) error {
+	return Wrap(KindToolboxMissing, err, fmt.Sprintf(
+		"check the toolbox with 'kubectl -n %s get deploy rook-ceph-tools'; with --cluster set, the toolbox pod needs the app.kubernetes.io/part-of=<cluster> label",
+		namespace))
+}
+
+// KindOf returns the kind of the first classified error in err's chain, or ""
+func KindOf(err error) Kind {
+	kind, _ := classify(err)
+	return kind
+}
+
+// Hint returns the remediation hint of err, or "" when none is known
+func Hint(err error) string {
+	_, hint := classify(err)
+	return hint
+}
+
+// classify returns the kind and hint of err: an explicit Error wins over
+// the kinds derived from API status errors and deadlines
+func classify(err error) (Kind, string) {
+	if err == nil {
+		return "", ""
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind, e.Hint
+	}
+	switch {
+	case apierrors.IsForbidden(err):
+		return KindRBACDenied, hintRBACDenied
+	case apierrors.IsUnauthorized(err):
+		return KindUnauthorized, hintUnauthorized
+	case errors.Is(err, context.DeadlineExceeded):
+		return KindTimeout, hintTimeout
+	default:
+		return "", ""
+	}
+}
+
+// RBACDenied returns the error for a permission the user's role lacks
+func RBACDenied(permission string) error {
+	return &Error{
+		Kind: KindRBACDenied,
+		Hint: hintRBACDenied,
+		Err:  fmt.Errorf("missing permission: %s", permission),
+	}
+}
@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassify(t *testing.T) {
+	nodes := schema.GroupResource{Resource: "nodes"}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantKind Kind
+		wantHint string
+	}{
+		{name: "nil", err: nil},
+		{name: "unclassified", err: errors.New("boom")},
+		{name: "node not found", err: NodeNotFound("worker-9"), wantKind: KindNodeNotFound, wantHint: "crook nodes"},
+		{name: "node ambiguous", err: NodeAmbiguous("worker-3", []string{"worker-3.a", "worker-3.b"}), wantKind: KindNodeAmbiguous, wantHint: "full node name"},
+		{name: "node partial match", err: NodePartialMatch("3", "worker-3"), wantKind: KindNodeAmbiguous, wantHint: "without --yes"},
+		{
+			name:     "toolbox missing through wrapping",
+			err:      fmt.Errorf("failed to get ceph status: %w", ToolboxMissing("rook-ceph", errors.New("no rook-ceph-tools pod"))),
+			wantKind: KindToolboxMissing,
+			wantHint: "kubectl -n rook-ceph get deploy rook-ceph-tools",
+		},
+		{name: "missing permission", err: RBACDenied("patch nodes [cluster]"), wantKind: KindRBACDenied, wantHint: "cluster admin"},
+		{
+			name:     "forbidden API error",
+			err:      fmt.Errorf("failed to cordon: %w", apierrors.NewForbidden(nodes, "worker-1", errors.New("denied"))),
+			wantKind: KindRBACDenied,
+			wantHint: "cluster admin",
+		},
+		{name: "unauthorized API error", err: apierrors.NewUnauthorized("token expired"), wantKind: KindUnauthorized, wantHint: "credentials"},
+		{name: "deadline", err: fmt.Errorf("wait: %w", context.DeadlineExceeded), wantKind: KindTimeout, wantHint: "--timeout"},
+		{
+			name:     "explicit hint wins over the derived one",
+			err:      Wrap(KindTimeout, fmt.Errorf("ceph timed out: %w", context.DeadlineExceeded), "raise the ceph timeout"),
+			wantKind: KindTimeout,
+			wantHint: "raise the ceph timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.wantKind {
+				t.Errorf("KindOf() = %q, want %q", got, tt.wantKind)
+			}
+			hint := Hint(tt.err)
+			if (hint == "") != (tt.wantHint == "") || !strings.Contains(hint, tt.wantHint) {
+				t.Errorf("Hint() = %q, want it to contain %q", hint, tt.wantHint)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if Wrap(KindTimeout, nil, "hint") != nil {
+		t.Error("Wrap(nil) should return nil")
+	}
+
+	cause := errors.New("boom")
+	err := Wrap(KindToolboxMissing, cause, "hint")
+	if !errors.Is(err, cause) {
+		t.Error("Wrap() should keep the cause in the chain")
+	}
+	if err.Error() != "boom" {
+		t.Errorf("Error() = %q, want the cause's message", err.Error())
+	}
+}
@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ItemResult is the outcome of one item of a batch operation
+type ItemResult struct {
+	// Item names the item for display, e.g. "deployment rook-ceph/rook-ceph-osd-0"
+	Item string
+
+	// Key identifies the item for a retry, e.g. "rook-ceph/rook-ceph-osd-0"
+	Key string
+
+	// Err is the failure; nil when the item succeeded or was not attempted
+	Err error
+
+	// Skipped is set for items not attempted because the batch was cancelled
+	Skipped bool
+}
+
+// Succeeded reports whether the item was done
+func (r ItemResult) Succeeded() bool {
+	return r.Err == nil && !r.Skipped
+}
+
+// MultiError aggregates the outcomes of an operation applied to several
+// items, so a failure reports which items failed, why, and which succeeded
+// instead of only the first error
+type MultiError struct {
+	// Op describes the operation, e.g. "scale down"
+	Op string
+
+	// Items holds the outcome of every item, in the order they were attempted
+	Items []ItemResult
+
+	// Cause is why the batch stopped before attempting every item (e.g. a
+	// cancelled context); nil when every item was attempted
+	Cause error
+}
+
+// Succeed records an item that was done
+func (m *MultiError) Succeed(item, key string) {
+	m.Items = append(m.Items, ItemResult{Item: item, Key: key})
+}
+
+// Fail records an item that failed with err
+func (m *MultiError) Fail(item, key string, err error) {
+	m.Items = append(m.Items, ItemResult{Item: item, Key: key, Err: err})
+}
+
+// Skip records an item that was not attempted because of cause
+func (m *MultiError) Skip(item, key string, cause error) {
+	m.Items = append(m.Items, ItemResult{Item: item, Key: key, Skipped: true})
+	if m.Cause == nil {
+		m.Cause = cause
+	}
+}
+
+// Err returns m when any item failed or was not attempted, else nil
+func (m *MultiError) Err() error {
+	if len(m.RetryKeys()) == 0 {
+		return nil
+	}
+	return m
+}
+
+// Failed returns the items that failed
+func (m *MultiError) Failed() []ItemResult {
+	var failed []ItemResult
+	for _, r := range m.Items {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// RetryKeys returns the keys of the items a retry has to redo: those that
+// failed or were not attempted
+func (m *MultiError) RetryKeys() []string {
+	var keys []string
+	for _, r := range m.Items {
+		if !r.Succeeded() {
+			keys = append(keys, r.Key)
+		}
+	}
+	return keys
+}
+
+// Error implements the error interface. A single failure keeps its own
+// message; several are joined behind a count.
+func (m *MultiError) Error() string {
+	failed := m.Failed()
+	switch len(failed) {
+	case 0:
+		return fmt.Sprintf("%s stopped with %d of %d items not attempted: %v", m.Op, len(m.RetryKeys()), len(m.Items), m.Cause)
+	case 1:
+		return failed[0].Err.Error()
+	}
+	messages := make([]string, len(failed))
+	for i, r := range failed {
+		messages[i] = r.Err.Error()
+	}
+	return fmt.Sprintf("%d of %d items failed to %s: %s", len(failed), len(m.Items), m.Op, strings.Join(messages, "; "))
+}
+
+// Unwrap returns the item errors, so errors.Is and errors.As see through the
+// aggregate (e.g. a context deadline or a hinted error of one item)
+func (m *MultiError) Unwrap() []error {
+	var errs []error
+	for _, r := range m.Failed() {
+		errs = append(errs, r.Err)
+	}
+	if m.Cause != nil {
+		errs = append(errs, m.Cause)
+	}
+	return errs
+}
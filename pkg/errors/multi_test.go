@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMultiError(t *testing.T) {
+	tests := []struct {
+		name      string
+		build     func(m *MultiError)
+		wantNil   bool
+		wantError string
+		wantRetry []string
+	}{
+		{
+			name: "all succeeded",
+			build: func(m *MultiError) {
+				m.Succeed("deployment ns/a", "ns/a")
+				m.Succeed("deployment ns/b", "ns/b")
+			},
+			wantNil: true,
+		},
+		{
+			name: "one failure keeps its message",
+			build: func(m *MultiError) {
+				m.Succeed("deployment ns/a", "ns/a")
+				m.Fail("deployment ns/b", "ns/b", errors.New("failed to scale ns/b"))
+			},
+			wantError: "failed to scale ns/b",
+			wantRetry: []string{"ns/b"},
+		},
+		{
+			name: "several failures are counted",
+			build: func(m *MultiError) {
+				m.Fail("deployment ns/a", "ns/a", errors.New("a broke"))
+				m.Succeed("deployment ns/b", "ns/b")
+				m.Fail("deployment ns/c", "ns/c", errors.New("c broke"))
+			},
+			wantError: "2 of 3 items failed to scale down: a broke; c broke",
+			wantRetry: []string{"ns/a", "ns/c"},
+		},
+		{
+			name: "cancelled before the end",
+			build: func(m *MultiError) {
+				m.Succeed("deployment ns/a", "ns/a")
+				m.Skip("deployment ns/b", "ns/b", context.Canceled)
+				m.Skip("deployment ns/c", "ns/c", context.Canceled)
+			},
+			wantError: "scale down stopped with 2 of 3 items not attempted: context canceled",
+			wantRetry: []string{"ns/b", "ns/c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MultiError{Op: "scale down"}
+			tt.build(m)
+
+			err := m.Err()
+			if tt.wantNil {
+				if err != nil {
+					t.Errorf("Err() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Err() = nil, want an error")
+			}
+			if err.Error() != tt.wantError {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantError)
+			}
+			if got := m.RetryKeys(); !slices.Equal(got, tt.wantRetry) {
+				t.Errorf("RetryKeys() = %v, want %v", got, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestMultiError_Unwrap(t *testing.T) {
+	m := &MultiError{Op: "scale up"}
+	m.Fail("deployment ns/a", "ns/a", NodeNotFound("worker-9"))
+	m.Skip("deployment ns/b", "ns/b", context.DeadlineExceeded)
+
+	if !errors.Is(m, context.DeadlineExceeded) {
+		t.Error("errors.Is() should see the cause through the aggregate")
+	}
+	if KindOf(m) != KindNodeNotFound {
+		t.Errorf("KindOf() = %q, want the kind of the failed item", KindOf(m))
+	}
+}
@@ -0,0 +1,43 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// execCredentialErrorPrefix is how client-go reports exec credential plugin failures
+const execCredentialErrorPrefix = "getting credentials"
+
+// IsAuthError reports whether err was caused by expired or rejected credentials.
+//
+// client-go re-runs exec credential plugins (OIDC, EKS, GKE) after a 401, so a
+// request that fails this way is expected to succeed when retried.
+func IsAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apierrors.IsUnauthorized(err) {
+		return true
+	}
+	return strings.Contains(err.Error(), execCredentialErrorPrefix)
+}
+
+// AuthenticatedUser returns the user name the API server authenticated the
+// client's credentials as (SelfSubjectReview). Unlike the local OS user it
+// cannot be changed without other credentials.
+func (c *Client) AuthenticatedUser(ctx context.Context) (string, error) {
+	review, err := c.Clientset.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to review the authenticated user: %w", err)
+	}
+	if review.Status.UserInfo.Username == "" {
+		return "", errors.New("the API server did not report an authenticated user name")
+	}
+	return review.Status.UserInfo.Username, nil
+}
@@ -0,0 +1,61 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestIsAuthError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "unauthorized", err: apierrors.NewUnauthorized("token expired"), want: true},
+		{name: "wrapped unauthorized", err: fmt.Errorf("failed to list nodes: %w", apierrors.NewUnauthorized("")), want: true},
+		{name: "exec plugin failure", err: errors.New(`Get "https://api": getting credentials: exec: executable aws failed with exit code 255`), want: true},
+		{name: "forbidden", err: apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "", errors.New("rbac")), want: false},
+		{name: "other", err: errors.New("connection refused"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAuthError(tt.err); got != tt.want {
+				t.Errorf("IsAuthError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticatedUser(t *testing.T) {
+	clientset := fake.NewClientset()
+	client := newClientFromClientset(clientset)
+
+	// The fake echoes the empty review back without a user
+	if _, err := client.AuthenticatedUser(context.Background()); err == nil {
+		t.Error("AuthenticatedUser() without a user name should fail")
+	}
+
+	clientset.PrependReactor("create", "selfsubjectreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &authenticationv1.SelfSubjectReview{Status: authenticationv1.SelfSubjectReviewStatus{
+			UserInfo: authenticationv1.UserInfo{Username: "oidc:alice@example.com"},
+		}}, nil
+	})
+	user, err := client.AuthenticatedUser(context.Background())
+	if err != nil {
+		t.Fatalf("AuthenticatedUser() error = %v", err)
+	}
+	if user != "oidc:alice@example.com" {
+		t.Errorf("AuthenticatedUser() = %q, want oidc:alice@example.com", user)
+	}
+}
@@ -0,0 +1,51 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// BareMetalHostGVR identifies the Metal3 BareMetalHost custom resource
+var BareMetalHostGVR = schema.GroupVersionResource{
+	Group:    "metal3.io",
+	Version:  "v1alpha1",
+	Resource: "baremetalhosts",
+}
+
+// BareMetalHostRebootAnnotation asks the Metal3 baremetal-operator to power
+// cycle the host; the operator removes it once the host was rebooted
+const BareMetalHostRebootAnnotation = "reboot.metal3.io"
+
+// RebootBareMetalHost requests a reboot of a Metal3 BareMetalHost by setting
+// its reboot annotation
+func (c *Client) RebootBareMetalHost(ctx context.Context, namespace, name string) error {
+	if c.Dynamic == nil {
+		return fmt.Errorf("dynamic client not configured")
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"annotations": map[string]string{BareMetalHostRebootAnnotation: ""}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode reboot annotation patch: %w", err)
+	}
+	_, err = c.Dynamic.Resource(BareMetalHostGVR).Namespace(namespace).Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to request reboot of baremetalhost %s/%s: %w", namespace, name, err)
+	}
+
+	c.recordChange(Change{
+		Kind:      "BareMetalHost",
+		Namespace: namespace,
+		Name:      name,
+		Field:     "metadata.annotations." + BareMetalHostRebootAnnotation,
+		Before:    "",
+		After:     "set",
+	})
+	return nil
+}
@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRebootBareMetalHost(t *testing.T) {
+	host := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "metal3.io/v1alpha1",
+		"kind":       "BareMetalHost",
+		"metadata":   map[string]interface{}{"name": "worker-1", "namespace": "metal3"},
+	}}
+	client := newClientFromClientset(fake.NewClientset())
+	client.Dynamic = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{BareMetalHostGVR: "BareMetalHostList"},
+		host,
+	)
+	ctx := context.Background()
+
+	mark := client.ChangeCount()
+	if err := client.RebootBareMetalHost(ctx, "metal3", "worker-1"); err != nil {
+		t.Fatalf("RebootBareMetalHost() error = %v", err)
+	}
+	if err := client.RebootBareMetalHost(ctx, "metal3", "missing"); err == nil {
+		t.Error("RebootBareMetalHost() expected error for a missing host")
+	}
+
+	got, err := client.Dynamic.Resource(BareMetalHostGVR).Namespace("metal3").Get(ctx, "worker-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, ok := got.GetAnnotations()[BareMetalHostRebootAnnotation]; !ok {
+		t.Errorf("annotations = %v, want %s", got.GetAnnotations(), BareMetalHostRebootAnnotation)
+	}
+	if changes := client.ChangesSince(mark); len(changes) != 1 || changes[0].Object() != "BareMetalHost metal3/worker-1" {
+		t.Errorf("ChangesSince() = %+v, want the annotated host", changes)
+	}
+}
@@ -3,9 +3,15 @@ package k8s
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/andri/crook/internal/metrics"
+	"github.com/andri/crook/pkg/config"
+	crookerrors "github.com/andri/crook/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -14,6 +20,8 @@ import (
 type CephStatus struct {
 	Health struct {
 		Status string `json:"status"`
+		// Checks maps active health check codes (e.g. OSD_NEARFULL) to details
+		Checks map[string]CephHealthCheck `json:"checks"`
 	} `json:"health"`
 	OSDMap struct {
 		NumOSDs   int  `json:"num_osds"`
@@ -22,6 +30,15 @@ type CephStatus struct {
 		Full      bool `json:"full"`
 		NearFull  bool `json:"nearfull"`
 	} `json:"osdmap"`
+	PGMap CephPGMap `json:"pgmap"`
+}
+
+// CephHealthCheck is an active health check in 'ceph status'
+type CephHealthCheck struct {
+	Severity string `json:"severity"`
+	Summary  struct {
+		Message string `json:"message"`
+	} `json:"summary"`
 }
 
 // CephOSDTree represents the parsed output of 'ceph osd tree --format json'
@@ -42,7 +59,8 @@ type CephOSDNode struct {
 }
 
 // ExecuteCephCommand executes a Ceph command via the rook-ceph-tools pod.
-// It applies a timeout to prevent hanging on degraded clusters.
+// It applies a timeout to prevent hanging on degraded clusters, and rewrites
+// the command according to the configured ceph.commands section.
 func (c *Client) ExecuteCephCommand(ctx context.Context, namespace string, command []string) (string, error) {
 	// Apply timeout to prevent hanging when cluster is degraded
 	timeout := c.cephCommandTimeout
@@ -52,6 +70,13 @@ func (c *Client) ExecuteCephCommand(ctx context.Context, namespace string, comma
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	if err := c.faults.CephFault(command); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return "", cephTimeout(timeout, err)
+		}
+		return "", fmt.Errorf("failed to execute ceph command: %w", err)
+	}
+
 	// Find the rook-ceph-tools pod
 	pod, err := c.findRookCephToolsPod(ctx, namespace)
 	if err != nil {
@@ -59,11 +84,13 @@ func (c *Client) ExecuteCephCommand(ctx context.Context, namespace string, comma
 	}
 
 	// Execute the command in the pod
-	output, err := c.ExecInPod(ctx, namespace, pod.Name, "", command)
+	start := time.Now()
+	output, err := c.ExecInPod(ctx, namespace, pod.Name, "", resolveCephCommand(c.cephCommands, command))
+	metrics.ObserveCephExec(time.Since(start), err)
 	if err != nil {
 		// Provide more context if it was a timeout
 		if ctx.Err() == context.DeadlineExceeded {
-			return "", fmt.Errorf("ceph command timed out after %v (cluster may be degraded): %w", timeout, err)
+			return "", cephTimeout(timeout, err)
 		}
 		return "", fmt.Errorf("failed to execute ceph command: %w", err)
 	}
@@ -71,25 +98,58 @@ func (c *Client) ExecuteCephCommand(ctx context.Context, namespace string, comma
 	return output, nil
 }
 
-// findRookCephToolsPod finds a ready rook-ceph-tools pod in the namespace
-func (c *Client) findRookCephToolsPod(ctx context.Context, namespace string) (*corev1.Pod, error) {
-	// List pods with label selector for rook-ceph-tools
-	listOptions := metav1.ListOptions{
-		LabelSelector: "app=rook-ceph-tools",
+// cephTimeout returns the error of a ceph command that ran past timeout
+func cephTimeout(timeout time.Duration, err error) error {
+	return crookerrors.Wrap(crookerrors.KindTimeout,
+		fmt.Errorf("ceph command timed out after %v (cluster may be degraded): %w", timeout, err),
+		"check 'ceph status' for blocked monitors or slow ops; raise timeouts.ceph-command-timeout-seconds if the cluster is just slow")
+}
+
+// resolveCephCommand applies the configured overrides, extra arguments, and
+// prefix to a ceph command. An exact override wins over Args and Prefix.
+func resolveCephCommand(cfg config.CephCommandsConfig, command []string) []string {
+	invocation := strings.Join(command, " ")
+	for _, override := range cfg.Overrides {
+		if strings.Join(strings.Fields(override.Match), " ") == invocation {
+			return strings.Fields(override.Command)
+		}
 	}
 
-	podList, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, listOptions)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list rook-ceph-tools pods: %w", err)
+	resolved := make([]string, 0, len(cfg.Prefix)+len(cfg.Args)+len(command))
+	resolved = append(resolved, cfg.Prefix...)
+	if len(command) > 0 && command[0] == "ceph" {
+		resolved = append(resolved, command[0])
+		resolved = append(resolved, cfg.Args...)
+		resolved = append(resolved, command[1:]...)
+	} else {
+		resolved = append(resolved, command...)
+	}
+	return resolved
+}
+
+// findRookCephToolsPod finds a ready rook-ceph-tools pod in the namespace
+func (c *Client) findRookCephToolsPod(ctx context.Context, namespace string) (*corev1.Pod, error) {
+	// List pods with label selector for rook-ceph-tools, preferring the
+	// toolbox of the selected CephCluster
+	var podList *corev1.PodList
+	for _, selector := range c.toolboxSelectors() {
+		list, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list rook-ceph-tools pods: %w", err)
+		}
+		podList = list
+		if len(podList.Items) > 0 {
+			break
+		}
 	}
 
 	if len(podList.Items) == 0 {
-		return nil, fmt.Errorf(
+		return nil, crookerrors.ToolboxMissing(namespace, fmt.Errorf(
 			"no rook-ceph-tools pod found in namespace %s. "+
 				"Please ensure the rook-ceph-tools deployment is running. "+
 				"See https://rook.io/docs/rook/latest/Troubleshooting/ceph-toolbox/",
 			namespace,
-		)
+		))
 	}
 
 	// Find a ready pod
@@ -99,12 +159,12 @@ func (c *Client) findRookCephToolsPod(ctx context.Context, namespace string) (*c
 		}
 	}
 
-	return nil, fmt.Errorf(
+	return nil, crookerrors.ToolboxMissing(namespace, fmt.Errorf(
 		"no ready rook-ceph-tools pod found in namespace %s. "+
 			"Found %d pod(s) but none are ready",
 		namespace,
 		len(podList.Items),
-	)
+	))
 }
 
 // isPodReady checks if a pod is in the Ready state
@@ -140,6 +200,26 @@ func (c *Client) UnsetNoOut(ctx context.Context, namespace string) error {
 	return nil
 }
 
+// SetNoOutGroup sets the noout flag on CRUSH buckets (e.g. a host) or on
+// OSDs (osd.N) only, with 'ceph osd set-group', leaving the cluster-wide flag
+// alone
+func (c *Client) SetNoOutGroup(ctx context.Context, namespace string, who []string) error {
+	_, err := c.ExecuteCephCommand(ctx, namespace, append([]string{"ceph", "osd", "set-group", "noout"}, who...))
+	if err != nil {
+		return fmt.Errorf("failed to set noout flag on %s: %w", strings.Join(who, ", "), err)
+	}
+	return nil
+}
+
+// UnsetNoOutGroup unsets the noout flag SetNoOutGroup set
+func (c *Client) UnsetNoOutGroup(ctx context.Context, namespace string, who []string) error {
+	_, err := c.ExecuteCephCommand(ctx, namespace, append([]string{"ceph", "osd", "unset-group", "noout"}, who...))
+	if err != nil {
+		return fmt.Errorf("failed to unset noout flag on %s: %w", strings.Join(who, ", "), err)
+	}
+	return nil
+}
+
 // GetCephStatus gets the Ceph cluster status
 func (c *Client) GetCephStatus(ctx context.Context, namespace string) (*CephStatus, error) {
 	output, err := c.ExecuteCephCommand(ctx, namespace, []string{"ceph", "status", "--format", "json"})
@@ -147,9 +227,40 @@ func (c *Client) GetCephStatus(ctx context.Context, namespace string) (*CephStat
 		return nil, fmt.Errorf("failed to get ceph status: %w", err)
 	}
 
+	return parseCephStatus(output)
+}
+
+// cephStatusCompat holds the keys older releases use for 'ceph status' fields
+type cephStatusCompat struct {
+	Health struct {
+		// OverallStatus was replaced by status in Luminous
+		OverallStatus string `json:"overall_status"`
+	} `json:"health"`
+	OSDMap struct {
+		// Nautilus nests the OSD map counters one level deeper
+		OSDMap json.RawMessage `json:"osdmap"`
+	} `json:"osdmap"`
+}
+
+// parseCephStatus parses the output of 'ceph status --format json' from
+// Nautilus onwards
+func parseCephStatus(output string) (*CephStatus, error) {
 	var status CephStatus
-	if unmarshalErr := json.Unmarshal([]byte(output), &status); unmarshalErr != nil {
-		return nil, fmt.Errorf("failed to parse ceph status JSON: %w", unmarshalErr)
+	if err := json.Unmarshal([]byte(output), &status); err != nil {
+		return nil, fmt.Errorf("failed to parse ceph status JSON: %w", err)
+	}
+
+	var compat cephStatusCompat
+	if err := json.Unmarshal([]byte(output), &compat); err != nil {
+		return nil, fmt.Errorf("failed to parse ceph status JSON: %w", err)
+	}
+	if status.Health.Status == "" {
+		status.Health.Status = compat.Health.OverallStatus
+	}
+	if len(compat.OSDMap.OSDMap) > 0 {
+		if err := json.Unmarshal(compat.OSDMap.OSDMap, &status.OSDMap); err != nil {
+			return nil, fmt.Errorf("failed to parse ceph status osdmap: %w", err)
+		}
 	}
 
 	return &status, nil
@@ -162,9 +273,14 @@ func (c *Client) GetOSDTree(ctx context.Context, namespace string) (*CephOSDTree
 		return nil, fmt.Errorf("failed to get ceph osd tree: %w", err)
 	}
 
+	return parseOSDTree(output)
+}
+
+// parseOSDTree parses the output of 'ceph osd tree --format json'
+func parseOSDTree(output string) (*CephOSDTree, error) {
 	var tree CephOSDTree
-	if unmarshalErr := json.Unmarshal([]byte(output), &tree); unmarshalErr != nil {
-		return nil, fmt.Errorf("failed to parse ceph osd tree JSON: %w", unmarshalErr)
+	if err := json.Unmarshal([]byte(output), &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse ceph osd tree JSON: %w", err)
 	}
 
 	return &tree, nil
@@ -185,6 +301,20 @@ func (s *CephStatus) IsError() bool {
 	return strings.ToUpper(s.Health.Status) == "HEALTH_ERR"
 }
 
+// IsNearFull checks if any OSD or pool is near full or full. Recent releases
+// only report this as a health check; older ones also set osdmap flags.
+func (s *CephStatus) IsNearFull() bool {
+	if s.OSDMap.NearFull || s.OSDMap.Full {
+		return true
+	}
+	for _, code := range []string{"OSD_NEARFULL", "OSD_FULL", "POOL_NEARFULL", "POOL_FULL"} {
+		if _, ok := s.Health.Checks[code]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // CephFlags represents the state of Ceph cluster flags
 type CephFlags struct {
 	NoOut       bool `json:"noout"`
@@ -197,11 +327,72 @@ type CephFlags struct {
 	NoDeepScrub bool `json:"nodeep-scrub"`
 	NoBackfill  bool `json:"nobackfill"`
 	Pause       bool `json:"pause"`
+
+	// NoOutGroups are the CRUSH buckets and OSDs (osd.N) with a noout flag of
+	// their own, set with 'ceph osd set-group noout'
+	NoOutGroups []string `json:"noout_groups,omitempty"`
 }
 
 // cephOSDDump represents the parsed output of 'ceph osd dump --format json'
 type cephOSDDump struct {
 	Flags string `json:"flags"`
+	// FlagsSet lists the flags since Octopus; preferred over the flags string
+	FlagsSet []string `json:"flags_set"`
+
+	// CrushNodeFlags are the flags of CRUSH buckets, by bucket name
+	CrushNodeFlags map[string][]string `json:"crush_node_flags"`
+
+	// OSDs carry the per-OSD flags, such as noout, in their state
+	OSDs []struct {
+		OSD   int      `json:"osd"`
+		State []string `json:"state"`
+	} `json:"osds"`
+
+	StretchMode struct {
+		Enabled           bool `json:"stretch_mode_enabled"`
+		BucketCount       int  `json:"stretch_bucket_count"`
+		DegradedStretch   int  `json:"degraded_stretch_mode"`
+		RecoveringStretch int  `json:"recovering_stretch_mode"`
+	} `json:"stretch_mode"`
+}
+
+// StretchModeStatus is the stretch mode state of the OSD map
+type StretchModeStatus struct {
+	Enabled bool
+
+	// BucketCount is the number of zones data is stretched across
+	BucketCount int
+
+	// Degraded is set while a zone is down and pools run with reduced min_size
+	Degraded bool
+
+	// Recovering is set while a returning zone catches up
+	Recovering bool
+}
+
+// GetStretchModeStatus gets the stretch mode state from the OSD map
+func (c *Client) GetStretchModeStatus(ctx context.Context, namespace string) (*StretchModeStatus, error) {
+	output, err := c.ExecuteCephCommand(ctx, namespace, []string{"ceph", "osd", "dump", "--format", "json"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ceph osd dump: %w", err)
+	}
+
+	return parseStretchModeStatus(output)
+}
+
+// parseStretchModeStatus parses the stretch_mode section of ceph osd dump output
+func parseStretchModeStatus(output string) (*StretchModeStatus, error) {
+	var dump cephOSDDump
+	if err := json.Unmarshal([]byte(output), &dump); err != nil {
+		return nil, fmt.Errorf("failed to parse ceph osd dump JSON: %w", err)
+	}
+
+	return &StretchModeStatus{
+		Enabled:     dump.StretchMode.Enabled,
+		BucketCount: dump.StretchMode.BucketCount,
+		Degraded:    dump.StretchMode.DegradedStretch != 0,
+		Recovering:  dump.StretchMode.RecoveringStretch != 0,
+	}, nil
 }
 
 // GetCephFlags gets the current Ceph cluster flags
@@ -221,7 +412,23 @@ func parseCephFlags(output string) (*CephFlags, error) {
 		return nil, fmt.Errorf("failed to parse ceph osd dump JSON: %w", err)
 	}
 
-	return parseFlagsString(dump.Flags), nil
+	flags := parseFlagsString(dump.Flags)
+	if dump.FlagsSet != nil {
+		flags = parseFlagsString(strings.Join(dump.FlagsSet, ","))
+	}
+
+	for bucket, bucketFlags := range dump.CrushNodeFlags {
+		if slices.Contains(bucketFlags, "noout") {
+			flags.NoOutGroups = append(flags.NoOutGroups, bucket)
+		}
+	}
+	slices.Sort(flags.NoOutGroups)
+	for _, osd := range dump.OSDs {
+		if slices.Contains(osd.State, "noout") {
+			flags.NoOutGroups = append(flags.NoOutGroups, fmt.Sprintf("osd.%d", osd.OSD))
+		}
+	}
+	return flags, nil
 }
 
 // parseFlagsString parses a comma-separated flags string into CephFlags
@@ -326,16 +533,18 @@ type PoolUsage struct {
 // cephDF represents the parsed output of 'ceph df --format json'
 type cephDF struct {
 	Stats struct {
-		TotalBytes        int64 `json:"total_bytes"`
-		TotalUsedBytes    int64 `json:"total_used_bytes"`
-		TotalAvailBytes   int64 `json:"total_avail_bytes"`
-		TotalUsedRawBytes int64 `json:"total_used_raw_bytes"`
+		TotalBytes        int64  `json:"total_bytes"`
+		TotalUsedBytes    *int64 `json:"total_used_bytes"`
+		TotalAvailBytes   int64  `json:"total_avail_bytes"`
+		TotalUsedRawBytes int64  `json:"total_used_raw_bytes"`
 	} `json:"stats"`
 	Pools []struct {
 		Name  string `json:"name"`
 		ID    int    `json:"id"`
 		Stats struct {
-			Stored      int64   `json:"stored"`
+			Stored *int64 `json:"stored"`
+			// BytesUsed held the stored bytes before Nautilus added stored
+			BytesUsed   int64   `json:"bytes_used"`
 			Objects     int64   `json:"objects"`
 			PercentUsed float64 `json:"percent_used"`
 			MaxAvail    int64   `json:"max_avail"`
@@ -360,23 +569,39 @@ func parseStorageUsage(output string) (*StorageUsage, error) {
 		return nil, fmt.Errorf("failed to parse ceph df JSON: %w", err)
 	}
 
+	// Not every release reports total_used_bytes: fall back to the raw usage,
+	// then to what the available bytes leave
+	var used int64
+	switch {
+	case df.Stats.TotalUsedBytes != nil:
+		used = *df.Stats.TotalUsedBytes
+	case df.Stats.TotalUsedRawBytes > 0:
+		used = df.Stats.TotalUsedRawBytes
+	case df.Stats.TotalBytes > df.Stats.TotalAvailBytes:
+		used = df.Stats.TotalBytes - df.Stats.TotalAvailBytes
+	}
+
 	usage := &StorageUsage{
 		TotalBytes:     df.Stats.TotalBytes,
-		UsedBytes:      df.Stats.TotalUsedBytes,
+		UsedBytes:      used,
 		AvailableBytes: df.Stats.TotalAvailBytes,
 	}
 
 	// Calculate percentage (avoid division by zero)
 	if df.Stats.TotalBytes > 0 {
-		usage.UsedPercent = float64(df.Stats.TotalUsedBytes) / float64(df.Stats.TotalBytes) * 100
+		usage.UsedPercent = float64(used) / float64(df.Stats.TotalBytes) * 100
 	}
 
 	// Parse pool statistics
 	for _, pool := range df.Pools {
+		stored := pool.Stats.BytesUsed
+		if pool.Stats.Stored != nil {
+			stored = *pool.Stats.Stored
+		}
 		usage.Pools = append(usage.Pools, PoolUsage{
 			Name:        pool.Name,
 			ID:          pool.ID,
-			StoredBytes: pool.Stats.Stored,
+			StoredBytes: stored,
 			// Ceph JSON API returns percent_used as a fraction (0.0-1.0), not a percentage.
 			// Source: PGMap.cc dump_object_stat_sum() calculates used = used_bytes/(used_bytes+avail)
 			// Verified in Quincy, Reef, and Tentacle branches at github.com/ceph/ceph/blob/main/src/mon/PGMap.cc
@@ -440,9 +665,29 @@ type OSDInfo struct {
 
 	// PGCount is the number of primary PGs (if available)
 	PGCount int `json:"pg_count,omitempty"`
+
+	// SlowOps is set while 'ceph health detail' reports slow ops on the OSD
+	SlowOps bool `json:"slow_ops,omitempty"`
+
+	// SlowHeartbeatMS is the longest slow heartbeat ping time involving the OSD
+	SlowHeartbeatMS float64 `json:"slow_heartbeat_ms,omitempty"`
+}
+
+// HealthIssue returns a short description of the OSD's slow ops or slow
+// heartbeats, or "" when neither is reported. Slow ops take precedence.
+func (o OSDInfo) HealthIssue() string {
+	switch {
+	case o.SlowOps:
+		return "slow ops"
+	case o.SlowHeartbeatMS > 0:
+		return fmt.Sprintf("hb %.0fms", o.SlowHeartbeatMS)
+	default:
+		return ""
+	}
 }
 
-// GetOSDInfoList returns a list of OSD info with hostname mappings from the CRUSH tree
+// GetOSDInfoList returns a list of OSD info with hostname mappings from the
+// CRUSH tree and the slow ops and heartbeats reported by 'ceph health detail'
 func (c *Client) GetOSDInfoList(ctx context.Context, namespace string) ([]OSDInfo, error) {
 	// Get the OSD tree
 	tree, err := c.GetOSDTree(ctx, namespace)
@@ -450,6 +695,9 @@ func (c *Client) GetOSDInfoList(ctx context.Context, namespace string) ([]OSDInf
 		return nil, fmt.Errorf("failed to get osd tree: %w", err)
 	}
 
+	// Health issues are best-effort; the OSD list is still useful without them
+	issues, _ := c.GetOSDHealthIssues(ctx, namespace)
+
 	// Build hostname map (osd id -> hostname)
 	hostMap := buildHostnameMap(tree)
 
@@ -476,6 +724,10 @@ func (c *Client) GetOSDInfoList(ctx context.Context, namespace string) ([]OSDInf
 			DeviceClass:    node.DeviceClass,
 			DeploymentName: fmt.Sprintf("rook-ceph-osd-%d", node.ID),
 		}
+		if issue, ok := issues[node.ID]; ok {
+			info.SlowOps = issue.SlowOps
+			info.SlowHeartbeatMS = issue.SlowHeartbeatMS
+		}
 		result = append(result, info)
 	}
 
@@ -517,6 +769,15 @@ type MonitorStatus struct {
 
 	// ElectionEpoch is the current election epoch
 	ElectionEpoch int `json:"election_epoch"`
+
+	// StretchMode is set when the monitors run in stretch mode
+	StretchMode bool `json:"stretch_mode,omitempty"`
+
+	// TiebreakerMon is the monitor that breaks ties between the stretch zones
+	TiebreakerMon string `json:"tiebreaker_mon,omitempty"`
+
+	// Locations maps monitor names to their CRUSH location (e.g. "{zone=a}")
+	Locations map[string]string `json:"locations,omitempty"`
 }
 
 // cephQuorumStatus represents the parsed output of 'ceph quorum_status --format json'
@@ -527,12 +788,15 @@ type cephQuorumStatus struct {
 	QuorumLeaderName string   `json:"quorum_leader_name"`
 	QuorumAge        int64    `json:"quorum_age"`
 	Monmap           struct {
-		Epoch   int `json:"epoch"`
-		NumMons int `json:"num_mons"`
-		Mons    []struct {
-			Rank int    `json:"rank"`
-			Name string `json:"name"`
-			Addr string `json:"addr"`
+		Epoch         int    `json:"epoch"`
+		NumMons       int    `json:"num_mons"`
+		StretchMode   bool   `json:"stretch_mode"`
+		TiebreakerMon string `json:"tiebreaker_mon"`
+		Mons          []struct {
+			Rank          int    `json:"rank"`
+			Name          string `json:"name"`
+			Addr          string `json:"addr"`
+			CrushLocation string `json:"crush_location"`
 		} `json:"mons"`
 	} `json:"monmap"`
 }
@@ -566,6 +830,8 @@ func parseMonitorStatus(output string) (*MonitorStatus, error) {
 		QuorumNames:   qs.QuorumNames,
 		Leader:        qs.QuorumLeaderName,
 		ElectionEpoch: qs.ElectionEpoch,
+		StretchMode:   qs.Monmap.StretchMode,
+		TiebreakerMon: qs.Monmap.TiebreakerMon,
 	}
 
 	// Determine which monitors are out of quorum
@@ -578,6 +844,12 @@ func parseMonitorStatus(output string) (*MonitorStatus, error) {
 		if !quorumSet[mon.Name] {
 			status.OutOfQuorum = append(status.OutOfQuorum, mon.Name)
 		}
+		if mon.CrushLocation != "" && mon.CrushLocation != "{}" {
+			if status.Locations == nil {
+				status.Locations = make(map[string]string)
+			}
+			status.Locations[mon.Name] = mon.CrushLocation
+		}
 	}
 
 	return status, nil
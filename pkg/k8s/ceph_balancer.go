@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Pool autoscale modes reported by 'ceph osd pool autoscale-status'
+const (
+	AutoscaleModeOn   = "on"
+	AutoscaleModeWarn = "warn"
+	AutoscaleModeOff  = "off"
+)
+
+// BalancerStatus represents the output of 'ceph balancer status --format json'
+type BalancerStatus struct {
+	// Active reports whether the balancer module is enabled
+	Active bool `json:"active"`
+
+	// Mode is the balancing mode (upmap, crush-compat, read, none)
+	Mode string `json:"mode"`
+}
+
+// PoolAutoscale is the pg_autoscaler mode of a single pool
+type PoolAutoscale struct {
+	Pool string `json:"pool_name"`
+	Mode string `json:"pg_autoscale_mode"`
+}
+
+// AutoscaleStatus holds the pg_autoscaler mode of all pools
+type AutoscaleStatus struct {
+	Pools []PoolAutoscale
+}
+
+// EnabledPools returns the names of pools where the autoscaler may change PG counts
+func (s *AutoscaleStatus) EnabledPools() []string {
+	var pools []string
+	for _, p := range s.Pools {
+		if p.Mode == AutoscaleModeOn {
+			pools = append(pools, p.Pool)
+		}
+	}
+	return pools
+}
+
+// GetBalancerStatus retrieves the state of the Ceph balancer module
+func (c *Client) GetBalancerStatus(ctx context.Context, namespace string) (*BalancerStatus, error) {
+	output, err := c.ExecuteCephCommand(ctx, namespace, []string{"ceph", "balancer", "status", "--format", "json"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ceph balancer status: %w", err)
+	}
+
+	return parseBalancerStatus(output)
+}
+
+// parseBalancerStatus parses 'ceph balancer status' JSON output
+func parseBalancerStatus(output string) (*BalancerStatus, error) {
+	var status BalancerStatus
+	if err := json.Unmarshal([]byte(output), &status); err != nil {
+		return nil, fmt.Errorf("failed to parse ceph balancer status JSON: %w", err)
+	}
+	return &status, nil
+}
+
+// GetAutoscaleStatus retrieves the pg_autoscaler mode of every pool
+func (c *Client) GetAutoscaleStatus(ctx context.Context, namespace string) (*AutoscaleStatus, error) {
+	output, err := c.ExecuteCephCommand(ctx, namespace, []string{"ceph", "osd", "pool", "autoscale-status", "--format", "json"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ceph pool autoscale status: %w", c.explainUnsupported(ctx, namespace, FeaturePGAutoscaler, err))
+	}
+
+	status, err := parseAutoscaleStatus(output)
+	if err != nil {
+		return nil, c.explainUnsupported(ctx, namespace, FeaturePGAutoscaler, err)
+	}
+	return status, nil
+}
+
+// parseAutoscaleStatus parses 'ceph osd pool autoscale-status' JSON output.
+// Ceph prints nothing at all when the cluster has no pools.
+func parseAutoscaleStatus(output string) (*AutoscaleStatus, error) {
+	status := &AutoscaleStatus{}
+	if strings.TrimSpace(output) == "" {
+		return status, nil
+	}
+	if err := json.Unmarshal([]byte(output), &status.Pools); err != nil {
+		return nil, fmt.Errorf("failed to parse ceph pool autoscale status JSON: %w", err)
+	}
+	return status, nil
+}
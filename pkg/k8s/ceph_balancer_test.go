@@ -0,0 +1,102 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBalancerStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *BalancerStatus
+		wantErr  bool
+	}{
+		{
+			name:     "active upmap",
+			input:    `{"active": true, "last_optimize_duration": "0:00:00.001", "mode": "upmap", "optimize_result": "Unable to find further optimization", "plans": []}`,
+			expected: &BalancerStatus{Active: true, Mode: "upmap"},
+		},
+		{
+			name:     "inactive",
+			input:    `{"active": false, "mode": "none", "plans": []}`,
+			expected: &BalancerStatus{Active: false, Mode: "none"},
+		},
+		{
+			name:    "invalid json",
+			input:   "not json",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBalancerStatus(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != *tt.expected {
+				t.Errorf("parseBalancerStatus() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseAutoscaleStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantPools   int
+		wantEnabled []string
+		wantErr     bool
+	}{
+		{
+			name: "mixed modes",
+			input: `[
+				{"pool_name": ".mgr", "pg_autoscale_mode": "on", "pg_num_target": 1},
+				{"pool_name": "replicapool", "pg_autoscale_mode": "warn"},
+				{"pool_name": "cephfs-data", "pg_autoscale_mode": "on"},
+				{"pool_name": "archive", "pg_autoscale_mode": "off"}
+			]`,
+			wantPools:   4,
+			wantEnabled: []string{".mgr", "cephfs-data"},
+		},
+		{
+			name:      "no pools",
+			input:     "\n",
+			wantPools: 0,
+		},
+		{
+			name:    "invalid json",
+			input:   "{",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAutoscaleStatus(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got.Pools) != tt.wantPools {
+				t.Errorf("got %d pools, want %d", len(got.Pools), tt.wantPools)
+			}
+			if enabled := got.EnabledPools(); !reflect.DeepEqual(enabled, tt.wantEnabled) {
+				t.Errorf("EnabledPools() = %v, want %v", enabled, tt.wantEnabled)
+			}
+		})
+	}
+}
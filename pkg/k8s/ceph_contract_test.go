@@ -0,0 +1,246 @@
+package k8s
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// cephReleases are the releases with recorded outputs in
+// test/fixtures/ceph-releases. Every release describes the same cluster:
+// 3 OSDs on 3 hosts, mons a, b and c led by a, noout set, 30 of 300 GiB used.
+var cephReleases = []string{"nautilus", "octopus", "pacific", "quincy", "reef", "squid"}
+
+const gib = int64(1) << 30
+
+func readReleaseFixture(t *testing.T, release, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "ceph-releases", release, name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return string(data)
+}
+
+func TestCephParsers_Releases(t *testing.T) {
+	for _, release := range cephReleases {
+		t.Run(release, func(t *testing.T) {
+			version, err := parseCephVersions(readReleaseFixture(t, release, "versions.json"))
+			if err != nil {
+				t.Fatalf("parseCephVersions() error = %v", err)
+			}
+			if version.Release != release || version.Mixed {
+				t.Errorf("version = %+v, want unmixed %s", version, release)
+			}
+
+			status, err := parseCephStatus(readReleaseFixture(t, release, "status.json"))
+			if err != nil {
+				t.Fatalf("parseCephStatus() error = %v", err)
+			}
+			if !status.IsWarning() {
+				t.Errorf("health status = %q, want HEALTH_WARN", status.Health.Status)
+			}
+			if _, ok := status.Health.Checks["OSDMAP_FLAGS"]; !ok {
+				t.Errorf("health checks = %v, want OSDMAP_FLAGS", status.Health.Checks)
+			}
+			if status.OSDMap.NumOSDs != 3 || status.OSDMap.NumUpOSDs != 3 || status.OSDMap.NumInOSDs != 3 {
+				t.Errorf("osdmap = %+v, want 3 OSDs up and in", status.OSDMap)
+			}
+			if status.PGMap.NumPGs != 33 {
+				t.Errorf("pgmap num_pgs = %d, want 33", status.PGMap.NumPGs)
+			}
+
+			tree, err := parseOSDTree(readReleaseFixture(t, release, "osd_tree.json"))
+			if err != nil {
+				t.Fatalf("parseOSDTree() error = %v", err)
+			}
+			hosts := buildHostnameMap(tree)
+			for id, want := range map[int]string{0: "worker-1", 1: "worker-2", 2: "worker-3"} {
+				if hosts[id] != want {
+					t.Errorf("host of osd.%d = %q, want %q", id, hosts[id], want)
+				}
+			}
+
+			dump := readReleaseFixture(t, release, "osd_dump.json")
+			flags, err := parseCephFlags(dump)
+			if err != nil {
+				t.Fatalf("parseCephFlags() error = %v", err)
+			}
+			if got := flags.ActiveFlags(); len(got) != 1 || !flags.NoOut {
+				t.Errorf("ActiveFlags() = %v, want [noout]", got)
+			}
+			stretch, err := parseStretchModeStatus(dump)
+			if err != nil {
+				t.Fatalf("parseStretchModeStatus() error = %v", err)
+			}
+			if stretch.Enabled {
+				t.Error("stretch mode should be disabled")
+			}
+
+			usage, err := parseStorageUsage(readReleaseFixture(t, release, "df.json"))
+			if err != nil {
+				t.Fatalf("parseStorageUsage() error = %v", err)
+			}
+			if usage.TotalBytes != 300*gib || usage.UsedBytes != 30*gib || usage.AvailableBytes != 270*gib {
+				t.Errorf("usage = %d/%d (avail %d), want 30 GiB of 300 GiB", usage.UsedBytes, usage.TotalBytes, usage.AvailableBytes)
+			}
+			if math.Abs(usage.UsedPercent-10) > 0.01 {
+				t.Errorf("UsedPercent = %.2f, want 10", usage.UsedPercent)
+			}
+			pool := usage.GetPoolByName("replicapool")
+			if pool == nil {
+				t.Fatal("replicapool missing from pools")
+			}
+			if pool.StoredBytes != 10*gib || pool.Objects != 2600 {
+				t.Errorf("replicapool = %+v, want 10 GiB in 2600 objects", pool)
+			}
+
+			mons, err := parseMonitorStatus(readReleaseFixture(t, release, "quorum_status.json"))
+			if err != nil {
+				t.Fatalf("parseMonitorStatus() error = %v", err)
+			}
+			if !mons.IsHealthy() || mons.TotalCount != 3 || mons.Leader != "a" {
+				t.Errorf("monitor status = %+v, want 3 mons in quorum led by a", mons)
+			}
+			if len(mons.Locations) != 0 {
+				t.Errorf("Locations = %v, want none outside stretch mode", mons.Locations)
+			}
+		})
+	}
+}
+
+func TestParseCephStatus_Compat(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantHealth string
+		wantOSDs   int
+	}{
+		{
+			name:       "flat osdmap",
+			input:      `{"health": {"status": "HEALTH_OK"}, "osdmap": {"num_osds": 4, "num_up_osds": 4}}`,
+			wantHealth: "HEALTH_OK",
+			wantOSDs:   4,
+		},
+		{
+			name:       "nested osdmap",
+			input:      `{"health": {"status": "HEALTH_OK"}, "osdmap": {"osdmap": {"num_osds": 5, "num_up_osds": 5}}}`,
+			wantHealth: "HEALTH_OK",
+			wantOSDs:   5,
+		},
+		{
+			name:       "overall_status only",
+			input:      `{"health": {"overall_status": "HEALTH_ERR"}, "osdmap": {"num_osds": 2}}`,
+			wantHealth: "HEALTH_ERR",
+			wantOSDs:   2,
+		},
+		{
+			name:       "status wins over overall_status",
+			input:      `{"health": {"status": "HEALTH_WARN", "overall_status": "HEALTH_OK"}}`,
+			wantHealth: "HEALTH_WARN",
+		},
+		{
+			name:       "unknown fields",
+			input:      `{"health": {"status": "HEALTH_OK", "mutes": [{"code": "X"}]}, "osdmap": {"num_osds": 1, "future": {"a": 1}}, "new_section": [1, 2]}`,
+			wantHealth: "HEALTH_OK",
+			wantOSDs:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := parseCephStatus(tt.input)
+			if err != nil {
+				t.Fatalf("parseCephStatus() error = %v", err)
+			}
+			if status.Health.Status != tt.wantHealth {
+				t.Errorf("health status = %q, want %q", status.Health.Status, tt.wantHealth)
+			}
+			if status.OSDMap.NumOSDs != tt.wantOSDs {
+				t.Errorf("NumOSDs = %d, want %d", status.OSDMap.NumOSDs, tt.wantOSDs)
+			}
+		})
+	}
+
+	if _, err := parseCephStatus("not json"); err == nil {
+		t.Error("parseCephStatus() of invalid JSON should fail")
+	}
+}
+
+func TestParseStorageUsage_Compat(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantUsed   int64
+		wantStored int64
+	}{
+		{
+			name:       "total_used_bytes and stored",
+			input:      `{"stats": {"total_bytes": 100, "total_used_bytes": 10, "total_used_raw_bytes": 12, "total_avail_bytes": 88}, "pools": [{"name": "p", "stats": {"stored": 3, "bytes_used": 9}}]}`,
+			wantUsed:   10,
+			wantStored: 3,
+		},
+		{
+			name:       "empty pool keeps zero stored",
+			input:      `{"stats": {"total_bytes": 100, "total_used_bytes": 0, "total_avail_bytes": 100}, "pools": [{"name": "p", "stats": {"stored": 0, "bytes_used": 4}}]}`,
+			wantUsed:   0,
+			wantStored: 0,
+		},
+		{
+			name:       "raw usage only",
+			input:      `{"stats": {"total_bytes": 100, "total_used_raw_bytes": 12, "total_avail_bytes": 88}, "pools": [{"name": "p", "stats": {"bytes_used": 9}}]}`,
+			wantUsed:   12,
+			wantStored: 9,
+		},
+		{
+			name:       "available bytes only",
+			input:      `{"stats": {"total_bytes": 100, "total_avail_bytes": 75}, "pools": [{"name": "p", "stats": {"stored": 5, "stored_raw": 15}}]}`,
+			wantUsed:   25,
+			wantStored: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := parseStorageUsage(tt.input)
+			if err != nil {
+				t.Fatalf("parseStorageUsage() error = %v", err)
+			}
+			if usage.UsedBytes != tt.wantUsed {
+				t.Errorf("UsedBytes = %d, want %d", usage.UsedBytes, tt.wantUsed)
+			}
+			if want := float64(tt.wantUsed); usage.UsedPercent != want {
+				t.Errorf("UsedPercent = %.2f, want %.2f", usage.UsedPercent, want)
+			}
+			if got := usage.GetPoolByName("p"); got == nil || got.StoredBytes != tt.wantStored {
+				t.Errorf("pool p = %+v, want %d bytes stored", got, tt.wantStored)
+			}
+		})
+	}
+}
+
+func TestParseCephFlags_Compat(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantNoOut bool
+		wantPause bool
+	}{
+		{name: "flags string", input: `{"flags": "noout,sortbitwise"}`, wantNoOut: true},
+		{name: "flags_set preferred", input: `{"flags": "sortbitwise", "flags_set": ["pauserd", "pausewr", "pause"]}`, wantPause: true},
+		{name: "empty flags_set", input: `{"flags": "noout", "flags_set": []}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := parseCephFlags(tt.input)
+			if err != nil {
+				t.Fatalf("parseCephFlags() error = %v", err)
+			}
+			if flags.NoOut != tt.wantNoOut || flags.Pause != tt.wantPause {
+				t.Errorf("flags = %+v, want noout=%v pause=%v", flags, tt.wantNoOut, tt.wantPause)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package k8s
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -436,3 +437,26 @@ func TestCephFlags_ActiveFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCephFlags_NoOutGroups(t *testing.T) {
+	input := `{
+		"flags_set": ["sortbitwise"],
+		"crush_node_flags": {"worker-2": ["noout"], "worker-1": ["noout", "nodown"], "rack1": ["noin"]},
+		"osds": [
+			{"osd": 0, "state": ["exists", "up"]},
+			{"osd": 3, "state": ["exists", "up", "noout"]}
+		]
+	}`
+
+	flags, err := parseCephFlags(input)
+	if err != nil {
+		t.Fatalf("parseCephFlags() error: %v", err)
+	}
+	if flags.NoOut {
+		t.Error("NoOut = true, want false: only groups carry noout")
+	}
+	want := []string{"worker-1", "worker-2", "osd.3"}
+	if !slices.Equal(flags.NoOutGroups, want) {
+		t.Errorf("NoOutGroups = %v, want %v", flags.NoOutGroups, want)
+	}
+}
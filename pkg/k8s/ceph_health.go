@@ -0,0 +1,114 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Health checks in 'ceph health detail' that name individual OSDs
+const (
+	healthCheckSlowOps           = "SLOW_OPS"
+	healthCheckSlowPingTimeBack  = "OSD_SLOW_PING_TIME_BACK"
+	healthCheckSlowPingTimeFront = "OSD_SLOW_PING_TIME_FRONT"
+)
+
+// OSDHealthIssue is the per-OSD state reported by 'ceph health detail'
+type OSDHealthIssue struct {
+	// SlowOps is set while the OSD has ops blocked beyond osd_op_complaint_time
+	SlowOps bool
+
+	// SlowHeartbeatMS is the longest reported heartbeat ping time involving the OSD
+	SlowHeartbeatMS float64
+}
+
+// cephHealthDetail represents the parsed output of 'ceph health detail --format json'
+type cephHealthDetail struct {
+	Checks map[string]struct {
+		Summary struct {
+			Message string `json:"message"`
+		} `json:"summary"`
+		Detail []struct {
+			Message string `json:"message"`
+		} `json:"detail"`
+	} `json:"checks"`
+}
+
+var (
+	// osdNamePattern matches OSD names such as osd.12
+	osdNamePattern = regexp.MustCompile(`\bosd\.(\d+)\b`)
+
+	// slowOpsDaemonsPattern matches the daemon list of the SLOW_OPS summary,
+	// e.g. "daemons [osd.1,osd.3] have slow ops."
+	slowOpsDaemonsPattern = regexp.MustCompile(`daemons \[([^\]]*)\] have slow ops`)
+
+	// heartbeatPattern matches a slow heartbeat detail line, e.g.
+	// "Slow OSD heartbeats on back from osd.0 [...] to osd.2 [...] 1234.567 msec"
+	heartbeatPattern = regexp.MustCompile(`from osd\.(\d+)\b.* to osd\.(\d+)\b.*?([\d.]+) msec`)
+)
+
+// GetOSDHealthIssues returns the OSDs with slow ops or slow heartbeats, keyed by OSD ID
+func (c *Client) GetOSDHealthIssues(ctx context.Context, namespace string) (map[int]OSDHealthIssue, error) {
+	output, err := c.ExecuteCephCommand(ctx, namespace, []string{"ceph", "health", "detail", "--format", "json"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ceph health detail: %w", err)
+	}
+
+	return parseOSDHealthIssues(output)
+}
+
+// parseOSDHealthIssues extracts per-OSD slow ops and heartbeat issues from
+// 'ceph health detail' JSON output. Both ends of a slow heartbeat are flagged,
+// since either the sender or the receiver may be the cause.
+func parseOSDHealthIssues(output string) (map[int]OSDHealthIssue, error) {
+	var detail cephHealthDetail
+	if err := json.Unmarshal([]byte(output), &detail); err != nil {
+		return nil, fmt.Errorf("failed to parse ceph health detail JSON: %w", err)
+	}
+
+	issues := make(map[int]OSDHealthIssue)
+
+	if check, ok := detail.Checks[healthCheckSlowOps]; ok {
+		var messages []string
+		if m := slowOpsDaemonsPattern.FindStringSubmatch(check.Summary.Message); m != nil {
+			messages = append(messages, m[1])
+		}
+		for _, d := range check.Detail {
+			if strings.Contains(d.Message, "slow ops") {
+				messages = append(messages, d.Message)
+			}
+		}
+		for _, message := range messages {
+			for _, m := range osdNamePattern.FindAllStringSubmatch(message, -1) {
+				id, _ := strconv.Atoi(m[1])
+				issue := issues[id]
+				issue.SlowOps = true
+				issues[id] = issue
+			}
+		}
+	}
+
+	for _, name := range []string{healthCheckSlowPingTimeBack, healthCheckSlowPingTimeFront} {
+		for _, d := range detail.Checks[name].Detail {
+			m := heartbeatPattern.FindStringSubmatch(d.Message)
+			if m == nil {
+				continue
+			}
+			ms, err := strconv.ParseFloat(m[3], 64)
+			if err != nil {
+				continue
+			}
+			for _, osd := range m[1:3] {
+				id, _ := strconv.Atoi(osd)
+				issue := issues[id]
+				issue.SlowHeartbeatMS = max(issue.SlowHeartbeatMS, ms)
+				issues[id] = issue
+			}
+		}
+	}
+
+	return issues, nil
+}
@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseOSDHealthIssues(t *testing.T) {
+	fixturePath := filepath.Join("..", "..", "test", "fixtures", "ceph_health_detail_slow_ops.json")
+	data, err := os.ReadFile(fixturePath) //nolint:gosec // G304: test fixture path is hardcoded
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	issues, err := parseOSDHealthIssues(string(data))
+	if err != nil {
+		t.Fatalf("failed to parse health detail: %v", err)
+	}
+
+	want := map[int]OSDHealthIssue{
+		0: {SlowHeartbeatMS: 2143.812},
+		1: {SlowOps: true},
+		3: {SlowOps: true, SlowHeartbeatMS: 2143.812},
+		4: {SlowHeartbeatMS: 1520.004},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %d OSDs with issues, want %d: %v", len(issues), len(want), issues)
+	}
+	for id, w := range want {
+		if got := issues[id]; got != w {
+			t.Errorf("osd.%d = %+v, want %+v", id, got, w)
+		}
+	}
+}
+
+func TestParseOSDHealthIssues_Edge(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[int]OSDHealthIssue
+		wantErr bool
+	}{
+		{
+			name:  "healthy",
+			input: `{"status":"HEALTH_OK","checks":{}}`,
+			want:  map[int]OSDHealthIssue{},
+		},
+		{
+			name: "slow ops listed in detail",
+			input: `{"checks":{"SLOW_OPS":{"summary":{"message":"2 slow ops, oldest one blocked for 31 sec, osd.7 has slow ops"},
+				"detail":[{"message":"osd.7 has slow ops"}]}}}`,
+			want: map[int]OSDHealthIssue{7: {SlowOps: true}},
+		},
+		{
+			name: "slow ops on a mon are ignored",
+			input: `{"checks":{"SLOW_OPS":{"summary":{"message":"1 slow ops, oldest one blocked for 40 sec, mon.a has slow ops"},
+				"detail":[]}}}`,
+			want: map[int]OSDHealthIssue{},
+		},
+		{
+			name:    "invalid JSON",
+			input:   `not json`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := parseOSDHealthIssues(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("got %v, want %v", issues, tt.want)
+			}
+			for id, w := range tt.want {
+				if got := issues[id]; got != w {
+					t.Errorf("osd.%d = %+v, want %+v", id, got, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClusterLogSeverity is the priority of a cluster log entry, ordered from
+// least to most severe
+type ClusterLogSeverity int
+
+const (
+	// ClusterLogDebug is reported as [DBG]
+	ClusterLogDebug ClusterLogSeverity = iota
+	// ClusterLogInfo is reported as [INF]
+	ClusterLogInfo
+	// ClusterLogSecurity is reported as [SEC]
+	ClusterLogSecurity
+	// ClusterLogWarning is reported as [WRN]
+	ClusterLogWarning
+	// ClusterLogError is reported as [ERR]
+	ClusterLogError
+)
+
+// clusterLogSeverityNames maps each severity to its Ceph abbreviation and the
+// level name accepted by 'ceph log last'
+var clusterLogSeverityNames = []struct {
+	severity ClusterLogSeverity
+	abbrev   string
+	level    string
+}{
+	{ClusterLogDebug, "DBG", "debug"},
+	{ClusterLogInfo, "INF", "info"},
+	{ClusterLogSecurity, "SEC", "sec"},
+	{ClusterLogWarning, "WRN", "warn"},
+	{ClusterLogError, "ERR", "error"},
+}
+
+// String returns the Ceph abbreviation of the severity, e.g. WRN
+func (s ClusterLogSeverity) String() string {
+	for _, name := range clusterLogSeverityNames {
+		if name.severity == s {
+			return name.abbrev
+		}
+	}
+	return "UNK"
+}
+
+// Level returns the level name of the severity, e.g. warn
+func (s ClusterLogSeverity) Level() string {
+	for _, name := range clusterLogSeverityNames {
+		if name.severity == s {
+			return name.level
+		}
+	}
+	return "unknown"
+}
+
+// ParseClusterLogSeverity parses a level name (debug, info, sec, warn, error)
+// or a Ceph abbreviation such as WRN or [WRN]
+func ParseClusterLogSeverity(s string) (ClusterLogSeverity, error) {
+	value := strings.Trim(strings.TrimSpace(s), "[]")
+	for _, name := range clusterLogSeverityNames {
+		if strings.EqualFold(value, name.abbrev) || strings.EqualFold(value, name.level) {
+			return name.severity, nil
+		}
+	}
+	return ClusterLogDebug, fmt.Errorf("invalid cluster log level %q: must be one of debug, info, sec, warn, error", s)
+}
+
+// ClusterLogEntry is a single entry of the Ceph cluster log
+type ClusterLogEntry struct {
+	// Seq is the sequence number assigned by the daemon that logged the entry
+	Seq uint64 `json:"seq"`
+
+	// Stamp is when the entry was logged; zero if Ceph reported an unknown format
+	Stamp time.Time `json:"stamp"`
+
+	// Name is the daemon that logged the entry, e.g. mon.a
+	Name string `json:"name"`
+
+	// Channel is the log channel, normally cluster
+	Channel string `json:"channel"`
+
+	// Severity is the entry's priority
+	Severity ClusterLogSeverity `json:"-"`
+
+	// Message is the log text
+	Message string `json:"message"`
+}
+
+// cephLogEntry represents one entry of 'ceph log last --format json'
+type cephLogEntry struct {
+	Seq      uint64 `json:"seq"`
+	Stamp    string `json:"stamp"`
+	Name     string `json:"name"`
+	Channel  string `json:"channel"`
+	Priority string `json:"priority"`
+	Message  string `json:"message"`
+}
+
+// clusterLogStampLayouts are the timestamp formats used by Ceph releases
+var clusterLogStampLayouts = []string{
+	"2006-01-02T15:04:05.999999-0700",
+	"2006-01-02 15:04:05.999999",
+}
+
+// GetClusterLog returns the last lines entries of the cluster log channel,
+// oldest first, at every severity
+func (c *Client) GetClusterLog(ctx context.Context, namespace string, lines int) ([]ClusterLogEntry, error) {
+	command := []string{"ceph", "log", "last", strconv.Itoa(lines), "debug", "cluster", "--format", "json"}
+	output, err := c.ExecuteCephCommand(ctx, namespace, command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ceph cluster log: %w", err)
+	}
+
+	return parseClusterLog(output)
+}
+
+// parseClusterLog parses 'ceph log last --format json' output
+func parseClusterLog(output string) ([]ClusterLogEntry, error) {
+	var raw []cephLogEntry
+	if err := json.Unmarshal([]byte(output), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse ceph log JSON: %w", err)
+	}
+
+	entries := make([]ClusterLogEntry, 0, len(raw))
+	for _, r := range raw {
+		// Unknown priorities are kept at the lowest severity rather than dropped
+		severity, _ := ParseClusterLogSeverity(r.Priority)
+		entries = append(entries, ClusterLogEntry{
+			Seq:      r.Seq,
+			Stamp:    parseClusterLogStamp(r.Stamp),
+			Name:     r.Name,
+			Channel:  r.Channel,
+			Severity: severity,
+			Message:  r.Message,
+		})
+	}
+	return entries, nil
+}
+
+// parseClusterLogStamp parses a cluster log timestamp, returning the zero
+// time for unknown formats
+func parseClusterLogStamp(stamp string) time.Time {
+	for _, layout := range clusterLogStampLayouts {
+		if t, err := time.Parse(layout, stamp); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// FilterClusterLog returns the entries at or above minSeverity
+func FilterClusterLog(entries []ClusterLogEntry, minSeverity ClusterLogSeverity) []ClusterLogEntry {
+	var filtered []ClusterLogEntry
+	for _, entry := range entries {
+		if entry.Severity >= minSeverity {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// ClusterLogAfter returns the entries logged after last, for polling the log
+// without repeating entries. Sequence numbers are per daemon, so an entry is
+// identified by its daemon, sequence number and timestamp. If last is no
+// longer in entries, every entry newer than it is returned.
+func ClusterLogAfter(entries []ClusterLogEntry, last ClusterLogEntry) []ClusterLogEntry {
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Name == last.Name && e.Seq == last.Seq && e.Stamp.Equal(last.Stamp) {
+			return entries[i+1:]
+		}
+	}
+
+	var newer []ClusterLogEntry
+	for _, entry := range entries {
+		if entry.Stamp.After(last.Stamp) {
+			newer = append(newer, entry)
+		}
+	}
+	return newer
+}
@@ -0,0 +1,176 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseClusterLog(t *testing.T) {
+	fixturePath := filepath.Join("..", "..", "test", "fixtures", "ceph_log_last.json")
+	data, err := os.ReadFile(fixturePath) //nolint:gosec // G304: test fixture path is hardcoded
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	entries, err := parseClusterLog(string(data))
+	if err != nil {
+		t.Fatalf("failed to parse cluster log: %v", err)
+	}
+
+	if len(entries) != 5 {
+		t.Fatalf("got %d entries, want 5", len(entries))
+	}
+
+	wantSeverities := []ClusterLogSeverity{
+		ClusterLogDebug, ClusterLogWarning, ClusterLogDebug, ClusterLogInfo, ClusterLogError,
+	}
+	for i, want := range wantSeverities {
+		if entries[i].Severity != want {
+			t.Errorf("entry %d severity = %v, want %v", i, entries[i].Severity, want)
+		}
+	}
+
+	down := entries[3]
+	if down.Name != "mon.a" || down.Seq != 4103 || down.Channel != "cluster" {
+		t.Errorf("entry 3 = %+v, want mon.a seq 4103 on cluster", down)
+	}
+	if down.Message != "osd.3 marked itself down and dead" {
+		t.Errorf("entry 3 message = %q", down.Message)
+	}
+	wantStamp := time.Date(2026, 10, 16, 9, 13, 5, 551873000, time.UTC)
+	if !down.Stamp.Equal(wantStamp) {
+		t.Errorf("entry 3 stamp = %v, want %v", down.Stamp, wantStamp)
+	}
+}
+
+func TestParseClusterLog_Edge(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantLen      int
+		wantZeroTime bool
+		wantErr      bool
+	}{
+		{name: "empty log", input: `[]`, wantLen: 0},
+		{
+			name:    "legacy timestamp",
+			input:   `[{"name":"mon.a","stamp":"2020-01-15 10:30:00.123456","seq":1,"channel":"cluster","priority":"[INF]","message":"x"}]`,
+			wantLen: 1,
+		},
+		{
+			name:         "unknown timestamp",
+			input:        `[{"name":"mon.a","stamp":"yesterday","seq":1,"channel":"cluster","priority":"[INF]","message":"x"}]`,
+			wantLen:      1,
+			wantZeroTime: true,
+		},
+		{name: "invalid JSON", input: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := parseClusterLog(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(entries) != tt.wantLen {
+				t.Fatalf("got %d entries, want %d", len(entries), tt.wantLen)
+			}
+			if tt.wantLen > 0 && entries[0].Stamp.IsZero() != tt.wantZeroTime {
+				t.Errorf("stamp = %v, want zero %v", entries[0].Stamp, tt.wantZeroTime)
+			}
+		})
+	}
+}
+
+func TestParseClusterLogSeverity(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ClusterLogSeverity
+		wantErr bool
+	}{
+		{input: "debug", want: ClusterLogDebug},
+		{input: "info", want: ClusterLogInfo},
+		{input: "WARN", want: ClusterLogWarning},
+		{input: "[WRN]", want: ClusterLogWarning},
+		{input: "err", want: ClusterLogError},
+		{input: "error", want: ClusterLogError},
+		{input: "sec", want: ClusterLogSecurity},
+		{input: "fatal", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseClusterLogSeverity(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseClusterLogSeverity(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterClusterLog(t *testing.T) {
+	entries := []ClusterLogEntry{
+		{Seq: 1, Severity: ClusterLogDebug},
+		{Seq: 2, Severity: ClusterLogInfo},
+		{Seq: 3, Severity: ClusterLogWarning},
+		{Seq: 4, Severity: ClusterLogError},
+	}
+
+	if got := FilterClusterLog(entries, ClusterLogDebug); len(got) != 4 {
+		t.Errorf("debug filter kept %d entries, want 4", len(got))
+	}
+	got := FilterClusterLog(entries, ClusterLogWarning)
+	if len(got) != 2 || got[0].Seq != 3 || got[1].Seq != 4 {
+		t.Errorf("warn filter = %+v, want seq 3 and 4", got)
+	}
+}
+
+func TestClusterLogAfter(t *testing.T) {
+	base := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	entries := []ClusterLogEntry{
+		{Name: "mon.a", Seq: 10, Stamp: base},
+		{Name: "mgr.a", Seq: 10, Stamp: base},
+		{Name: "mon.a", Seq: 11, Stamp: base.Add(time.Second)},
+		{Name: "mon.a", Seq: 12, Stamp: base.Add(2 * time.Second)},
+	}
+
+	tests := []struct {
+		name    string
+		last    ClusterLogEntry
+		wantLen int
+	}{
+		{name: "last entry seen", last: entries[3], wantLen: 0},
+		{name: "same seq from another daemon", last: entries[1], wantLen: 2},
+		{name: "first entry seen", last: entries[0], wantLen: 3},
+		{
+			name:    "last entry rotated out",
+			last:    ClusterLogEntry{Name: "mon.b", Seq: 99, Stamp: base.Add(500 * time.Millisecond)},
+			wantLen: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClusterLogAfter(entries, tt.last); len(got) != tt.wantLen {
+				t.Errorf("ClusterLogAfter() returned %d entries, want %d", len(got), tt.wantLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PoolDetail is a pool's redundancy settings from 'ceph osd pool ls detail --format json'
+type PoolDetail struct {
+	Name      string `json:"pool_name"`
+	ID        int    `json:"pool"`
+	Size      int    `json:"size"`
+	MinSize   int    `json:"min_size"`
+	CrushRule int    `json:"crush_rule"`
+}
+
+// CrushRule is a placement rule from 'ceph osd crush rule dump --format json'
+type CrushRule struct {
+	ID    int             `json:"rule_id"`
+	Name  string          `json:"rule_name"`
+	Steps []CrushRuleStep `json:"steps"`
+}
+
+// CrushRuleStep is one step of a CRUSH rule (take, choose*, emit)
+type CrushRuleStep struct {
+	Op       string `json:"op"`
+	ItemName string `json:"item_name,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
+
+// FailureDomain returns the bucket type replicas are spread across (e.g.
+// host), which is the type of the rule's first choose step
+func (r CrushRule) FailureDomain() string {
+	for _, step := range r.Steps {
+		if strings.HasPrefix(step.Op, "choose") && step.Type != "" {
+			return step.Type
+		}
+	}
+	return ""
+}
+
+// DeviceClass returns the device class the rule is restricted to, or "" for
+// all classes. Class-specific rules take a shadow bucket like "default~ssd".
+func (r CrushRule) DeviceClass() string {
+	for _, step := range r.Steps {
+		if step.Op == "take" {
+			if _, class, ok := strings.Cut(step.ItemName, "~"); ok {
+				return class
+			}
+		}
+	}
+	return ""
+}
+
+// GetPoolDetails retrieves the size, min_size and CRUSH rule of every pool
+func (c *Client) GetPoolDetails(ctx context.Context, namespace string) ([]PoolDetail, error) {
+	output, err := c.ExecuteCephCommand(ctx, namespace, []string{"ceph", "osd", "pool", "ls", "detail", "--format", "json"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ceph pool details: %w", err)
+	}
+
+	return parsePoolDetails(output)
+}
+
+// parsePoolDetails parses 'ceph osd pool ls detail' JSON output
+func parsePoolDetails(output string) ([]PoolDetail, error) {
+	var pools []PoolDetail
+	if err := json.Unmarshal([]byte(output), &pools); err != nil {
+		return nil, fmt.Errorf("failed to parse ceph pool details JSON: %w", err)
+	}
+	return pools, nil
+}
+
+// GetCrushRules retrieves the CRUSH rules of the cluster
+func (c *Client) GetCrushRules(ctx context.Context, namespace string) ([]CrushRule, error) {
+	output, err := c.ExecuteCephCommand(ctx, namespace, []string{"ceph", "osd", "crush", "rule", "dump", "--format", "json"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ceph crush rules: %w", err)
+	}
+
+	return parseCrushRules(output)
+}
+
+// parseCrushRules parses 'ceph osd crush rule dump' JSON output
+func parseCrushRules(output string) ([]CrushRule, error) {
+	var rules []CrushRule
+	if err := json.Unmarshal([]byte(output), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse ceph crush rules JSON: %w", err)
+	}
+	return rules, nil
+}
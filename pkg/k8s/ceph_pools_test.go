@@ -0,0 +1,65 @@
+package k8s
+
+import "testing"
+
+func TestParsePoolDetails(t *testing.T) {
+	output := `[
+		{"pool": 1, "pool_name": ".mgr", "size": 3, "min_size": 2, "crush_rule": 0, "type": 1},
+		{"pool": 2, "pool_name": "ec-data", "size": 6, "min_size": 5, "crush_rule": 1, "type": 3}
+	]`
+
+	pools, err := parsePoolDetails(output)
+	if err != nil {
+		t.Fatalf("parsePoolDetails() error = %v", err)
+	}
+	if len(pools) != 2 {
+		t.Fatalf("got %d pools, want 2", len(pools))
+	}
+	if got := pools[1]; got.Name != "ec-data" || got.Size != 6 || got.MinSize != 5 || got.CrushRule != 1 {
+		t.Errorf("pools[1] = %+v", got)
+	}
+
+	if _, err := parsePoolDetails("not json"); err == nil {
+		t.Error("parsePoolDetails() should fail on invalid JSON")
+	}
+}
+
+func TestParseCrushRules(t *testing.T) {
+	output := `[
+		{"rule_id": 0, "rule_name": "replicated_rule", "steps": [
+			{"op": "take", "item": -1, "item_name": "default"},
+			{"op": "chooseleaf_firstn", "num": 0, "type": "host"},
+			{"op": "emit"}
+		]},
+		{"rule_id": 1, "rule_name": "ssd_by_rack", "steps": [
+			{"op": "take", "item": -2, "item_name": "default~ssd"},
+			{"op": "choose_firstn", "num": 0, "type": "rack"},
+			{"op": "chooseleaf_firstn", "num": 1, "type": "host"},
+			{"op": "emit"}
+		]}
+	]`
+
+	rules, err := parseCrushRules(output)
+	if err != nil {
+		t.Fatalf("parseCrushRules() error = %v", err)
+	}
+
+	tests := []struct {
+		rule       CrushRule
+		wantDomain string
+		wantClass  string
+	}{
+		{rule: rules[0], wantDomain: "host", wantClass: ""},
+		{rule: rules[1], wantDomain: "rack", wantClass: "ssd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule.Name, func(t *testing.T) {
+			if got := tt.rule.FailureDomain(); got != tt.wantDomain {
+				t.Errorf("FailureDomain() = %q, want %q", got, tt.wantDomain)
+			}
+			if got := tt.rule.DeviceClass(); got != tt.wantClass {
+				t.Errorf("DeviceClass() = %q, want %q", got, tt.wantClass)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// CephPGMap holds the placement group summary from 'ceph status --format json'.
+// Rate fields are only present while the cluster is recovering.
+type CephPGMap struct {
+	NumPGs                  int                `json:"num_pgs"`
+	NumObjects              int64              `json:"num_objects"`
+	DegradedObjects         int64              `json:"degraded_objects"`
+	DegradedTotal           int64              `json:"degraded_total"`
+	MisplacedObjects        int64              `json:"misplaced_objects"`
+	MisplacedTotal          int64              `json:"misplaced_total"`
+	RecoveringObjectsPerSec float64            `json:"recovering_objects_per_sec"`
+	RecoveringBytesPerSec   float64            `json:"recovering_bytes_per_sec"`
+	PGsByState              []CephPGStateCount `json:"pgs_by_state"`
+}
+
+// CephPGStateCount is the number of PGs in one combined state (e.g. "active+clean")
+type CephPGStateCount struct {
+	StateName string `json:"state_name"`
+	Count     int    `json:"count"`
+}
+
+// DegradedPGsPercent returns the share of PGs in a degraded state
+func (m CephPGMap) DegradedPGsPercent() float64 {
+	if m.NumPGs == 0 {
+		return 0
+	}
+	degraded := 0
+	for _, state := range m.PGsByState {
+		if strings.Contains(state.StateName, "degraded") {
+			degraded += state.Count
+		}
+	}
+	return float64(degraded) / float64(m.NumPGs) * 100
+}
+
+// RecoveryStats is a snapshot of Ceph data recovery progress
+type RecoveryStats struct {
+	// ObjectsPerSec is the current recovery rate in objects per second
+	ObjectsPerSec float64
+
+	// BytesPerSec is the current recovery rate in bytes per second
+	BytesPerSec float64
+
+	// MisplacedObjects is the number of objects not yet on their target OSDs
+	MisplacedObjects int64
+
+	// DegradedObjects is the number of object copies missing
+	DegradedObjects int64
+
+	// TotalObjects is the total number of object copies in the cluster
+	TotalObjects int64
+
+	// Time is when the snapshot was taken
+	Time time.Time
+}
+
+// RecoveryFromStatus extracts recovery progress from a ceph status snapshot
+func RecoveryFromStatus(status *CephStatus, at time.Time) RecoveryStats {
+	total := status.PGMap.MisplacedTotal
+	if status.PGMap.DegradedTotal > total {
+		total = status.PGMap.DegradedTotal
+	}
+	return RecoveryStats{
+		ObjectsPerSec:    status.PGMap.RecoveringObjectsPerSec,
+		BytesPerSec:      status.PGMap.RecoveringBytesPerSec,
+		MisplacedObjects: status.PGMap.MisplacedObjects,
+		DegradedObjects:  status.PGMap.DegradedObjects,
+		TotalObjects:     total,
+		Time:             at,
+	}
+}
+
+// GetRecoveryStats returns the current Ceph recovery progress
+func (c *Client) GetRecoveryStats(ctx context.Context, namespace string) (*RecoveryStats, error) {
+	status, err := c.GetCephStatus(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	stats := RecoveryFromStatus(status, time.Now())
+	return &stats, nil
+}
+
+// Remaining returns the number of objects that still need to be recovered or moved
+func (r RecoveryStats) Remaining() int64 {
+	return r.MisplacedObjects + r.DegradedObjects
+}
+
+// Active reports whether data is still being recovered or rebalanced
+func (r RecoveryStats) Active() bool {
+	return r.Remaining() > 0
+}
+
+// ETA estimates the time until recovery completes at the current rate.
+// It returns false when no estimate is possible (idle or no progress).
+func (r RecoveryStats) ETA() (time.Duration, bool) {
+	if !r.Active() || r.ObjectsPerSec <= 0 {
+		return 0, false
+	}
+	seconds := float64(r.Remaining()) / r.ObjectsPerSec
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second), true
+}
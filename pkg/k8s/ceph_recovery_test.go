@@ -0,0 +1,63 @@
+package k8s
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+const recoveringStatusJSON = `{
+	"health": {"status": "HEALTH_WARN"},
+	"osdmap": {"num_osds": 6, "num_up_osds": 6, "num_in_osds": 6},
+	"pgmap": {
+		"num_pgs": 129,
+		"num_objects": 5000,
+		"degraded_objects": 300,
+		"degraded_total": 15000,
+		"misplaced_objects": 900,
+		"misplaced_total": 15000,
+		"recovering_objects_per_sec": 40,
+		"recovering_bytes_per_sec": 167772160
+	}
+}`
+
+func TestRecoveryFromStatus(t *testing.T) {
+	var status CephStatus
+	if err := json.Unmarshal([]byte(recoveringStatusJSON), &status); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	stats := RecoveryFromStatus(&status, time.Now())
+	if stats.ObjectsPerSec != 40 || stats.BytesPerSec != 167772160 {
+		t.Errorf("unexpected rates: %+v", stats)
+	}
+	if stats.Remaining() != 1200 || !stats.Active() {
+		t.Errorf("Remaining() = %d, Active() = %v; want 1200, true", stats.Remaining(), stats.Active())
+	}
+	if stats.TotalObjects != 15000 {
+		t.Errorf("TotalObjects = %d, want 15000", stats.TotalObjects)
+	}
+
+	eta, ok := stats.ETA()
+	if !ok || eta != 30*time.Second {
+		t.Errorf("ETA() = %v, %v; want 30s, true", eta, ok)
+	}
+}
+
+func TestRecoveryStats_ETAUnavailable(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats RecoveryStats
+	}{
+		{name: "idle cluster", stats: RecoveryStats{}},
+		{name: "no progress", stats: RecoveryStats{MisplacedObjects: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := tt.stats.ETA(); ok {
+				t.Error("expected no ETA")
+			}
+		})
+	}
+}
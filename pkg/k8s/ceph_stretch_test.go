@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseMonitorStatus_Stretch(t *testing.T) {
+	fixturePath := filepath.Join("..", "..", "test", "fixtures", "ceph_quorum_status_stretch.json")
+	data, err := os.ReadFile(fixturePath) //nolint:gosec // G304: test fixture path is hardcoded
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	status, err := parseMonitorStatus(string(data))
+	if err != nil {
+		t.Fatalf("failed to parse monitor status: %v", err)
+	}
+
+	if !status.StretchMode || status.TiebreakerMon != "e" {
+		t.Errorf("StretchMode = %v, TiebreakerMon = %q; want true, e", status.StretchMode, status.TiebreakerMon)
+	}
+	if len(status.OutOfQuorum) != 1 || status.OutOfQuorum[0] != "e" {
+		t.Errorf("OutOfQuorum = %v, want [e]", status.OutOfQuorum)
+	}
+	if got := status.Locations["c"]; got != "{zone=b}" {
+		t.Errorf("Locations[c] = %q, want {zone=b}", got)
+	}
+}
+
+func TestParseMonitorStatus_FlatHasNoLocations(t *testing.T) {
+	fixturePath := filepath.Join("..", "..", "test", "fixtures", "ceph_quorum_status.json")
+	data, err := os.ReadFile(fixturePath) //nolint:gosec // G304: test fixture path is hardcoded
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	status, err := parseMonitorStatus(string(data))
+	if err != nil {
+		t.Fatalf("failed to parse monitor status: %v", err)
+	}
+	if status.StretchMode || len(status.Locations) != 0 {
+		t.Errorf("StretchMode = %v, Locations = %v; want false, none", status.StretchMode, status.Locations)
+	}
+}
+
+func TestParseStretchModeStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   StretchModeStatus
+	}{
+		{
+			name:   "flat cluster",
+			output: `{"flags": "sortbitwise", "stretch_mode": {"stretch_mode_enabled": false}}`,
+			want:   StretchModeStatus{},
+		},
+		{
+			name:   "older release without stretch section",
+			output: `{"flags": "sortbitwise"}`,
+			want:   StretchModeStatus{},
+		},
+		{
+			name: "degraded stretch cluster",
+			output: `{"stretch_mode": {"stretch_mode_enabled": true, "stretch_bucket_count": 2,
+				"degraded_stretch_mode": 1, "recovering_stretch_mode": 0, "stretch_mode_bucket": 8}}`,
+			want: StretchModeStatus{Enabled: true, BucketCount: 2, Degraded: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStretchModeStatus(tt.output)
+			if err != nil {
+				t.Fatalf("parseStretchModeStatus() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("parseStretchModeStatus() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
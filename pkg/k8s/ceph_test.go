@@ -3,8 +3,13 @@ package k8s
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/andri/crook/internal/faults"
+	"github.com/andri/crook/pkg/config"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -305,3 +310,158 @@ func TestCephOSDTree_ParseJSON(t *testing.T) {
 		t.Errorf("expected 2 children, got %d", len(tree.Nodes[0].Children))
 	}
 }
+
+func TestResolveCephCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.CephCommandsConfig
+		command []string
+		want    []string
+	}{
+		{
+			name:    "no configuration",
+			command: []string{"ceph", "status", "--format", "json"},
+			want:    []string{"ceph", "status", "--format", "json"},
+		},
+		{
+			name:    "extra args after ceph",
+			cfg:     config.CephCommandsConfig{Args: []string{"--connect-timeout", "10"}},
+			command: []string{"ceph", "osd", "set", "noout"},
+			want:    []string{"ceph", "--connect-timeout", "10", "osd", "set", "noout"},
+		},
+		{
+			name:    "prefix and args",
+			cfg:     config.CephCommandsConfig{Prefix: []string{"cephadm", "shell", "--"}, Args: []string{"--id", "admin"}},
+			command: []string{"ceph", "status"},
+			want:    []string{"cephadm", "shell", "--", "ceph", "--id", "admin", "status"},
+		},
+		{
+			name: "exact override wins",
+			cfg: config.CephCommandsConfig{
+				Prefix: []string{"cephadm", "shell", "--"},
+				Overrides: []config.CephCommandOverride{
+					{Match: "ceph  osd dump --format json", Command: "ceph --cluster prod osd dump --format json"},
+				},
+			},
+			command: []string{"ceph", "osd", "dump", "--format", "json"},
+			want:    []string{"ceph", "--cluster", "prod", "osd", "dump", "--format", "json"},
+		},
+		{
+			name: "override must match exactly",
+			cfg: config.CephCommandsConfig{
+				Overrides: []config.CephCommandOverride{{Match: "ceph osd dump", Command: "true"}},
+			},
+			command: []string{"ceph", "osd", "dump", "--format", "json"},
+			want:    []string{"ceph", "osd", "dump", "--format", "json"},
+		},
+		{
+			name:    "args only apply to ceph",
+			cfg:     config.CephCommandsConfig{Args: []string{"--connect-timeout", "10"}},
+			command: []string{"rados", "df"},
+			want:    []string{"rados", "df"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveCephCommand(tt.cfg, tt.command)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveCephCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindRookCephToolsPod_CephCluster(t *testing.T) {
+	toolbox := func(name, cluster string) *corev1.Pod {
+		labels := map[string]string{"app": "rook-ceph-tools"}
+		if cluster != "" {
+			labels[CephClusterLabel] = cluster
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph", Labels: labels},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		cluster string
+		pods    []*corev1.Pod
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "labeled toolbox wins",
+			cluster: "east",
+			pods:    []*corev1.Pod{toolbox("tools-plain", ""), toolbox("tools-east", "east"), toolbox("tools-west", "west")},
+			want:    "tools-east",
+		},
+		{
+			name:    "falls back to unlabeled toolbox",
+			cluster: "east",
+			pods:    []*corev1.Pod{toolbox("tools-west", "west"), toolbox("tools-plain", "")},
+			want:    "tools-plain",
+		},
+		{
+			name:    "never uses another cluster's toolbox",
+			cluster: "east",
+			pods:    []*corev1.Pod{toolbox("tools-west", "west")},
+			wantErr: true,
+		},
+		{
+			name: "no selection uses any toolbox",
+			pods: []*corev1.Pod{toolbox("tools-west", "west")},
+			want: "tools-west",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewClientset()
+			for _, pod := range tt.pods {
+				if _, err := clientset.CoreV1().Pods(pod.Namespace).Create(t.Context(), pod, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("failed to create pod: %v", err)
+				}
+			}
+			client := newClientFromInterface(clientset)
+			client.cephCluster = tt.cluster
+
+			pod, err := client.findRookCephToolsPod(t.Context(), "rook-ceph")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("findRookCephToolsPod() = %s, want error", pod.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findRookCephToolsPod() error = %v", err)
+			}
+			if pod.Name != tt.want {
+				t.Errorf("findRookCephToolsPod() = %s, want %s", pod.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteCephCommand_InjectedFault(t *testing.T) {
+	injector, err := faults.Parse("ceph:osd set noout=timeout,ceph:status=error")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	client := newClientFromClientset(fake.NewClientset())
+	client.faults = injector
+
+	// Faults fire before the toolbox pod is looked up
+	_, err = client.ExecuteCephCommand(context.Background(), "rook-ceph", []string{"ceph", "osd", "set", "noout"})
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("osd set noout error = %v, want an injected timeout", err)
+	}
+	_, err = client.ExecuteCephCommand(context.Background(), "rook-ceph", []string{"ceph", "status"})
+	if !errors.Is(err, faults.ErrInjected) {
+		t.Errorf("status error = %v, want an injected fault", err)
+	}
+}
@@ -0,0 +1,279 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// CephVersion is the version of a Ceph release, e.g. 18.2.4 reef
+type CephVersion struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+	Patch int `json:"patch"`
+
+	// Release is the release name (e.g. reef); empty when Ceph did not print it
+	Release string `json:"release,omitempty"`
+
+	// Mixed is set when the cluster's daemons run different versions; the
+	// version is then the oldest one
+	Mixed bool `json:"mixed,omitempty"`
+}
+
+// String returns the version as "18.2.4 (reef)"
+func (v CephVersion) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Release != "" {
+		s += " (" + v.Release + ")"
+	}
+	return s
+}
+
+// Less reports whether v is older than other
+func (v CephVersion) Less(other CephVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// cephVersionPattern matches the version line printed by Ceph daemons, e.g.
+// "ceph version 18.2.4 (e7ad5345525c7aa95470c26863873b581076945d) reef (stable)"
+var cephVersionPattern = regexp.MustCompile(`ceph version (\d+)\.(\d+)\.(\d+)\S*(?: \([0-9a-f]+\))?(?: ([a-z]+))?`)
+
+// ParseCephVersion parses a Ceph version line
+func ParseCephVersion(s string) (CephVersion, error) {
+	m := cephVersionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return CephVersion{}, fmt.Errorf("unrecognized ceph version %q", s)
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	return CephVersion{Major: major, Minor: minor, Patch: patch, Release: m[4]}, nil
+}
+
+// parseCephVersions parses the output of 'ceph versions --format json' and
+// returns the oldest version any daemon runs
+func parseCephVersions(output string) (*CephVersion, error) {
+	var daemons map[string]map[string]int
+	if err := json.Unmarshal([]byte(output), &daemons); err != nil {
+		return nil, fmt.Errorf("failed to parse ceph versions JSON: %w", err)
+	}
+
+	// overall sums up every daemon type; older releases may omit it
+	counts := daemons["overall"]
+	if len(counts) == 0 {
+		counts = make(map[string]int)
+		for _, versions := range daemons {
+			for line, n := range versions {
+				counts[line] += n
+			}
+		}
+	}
+
+	var oldest *CephVersion
+	for line := range counts {
+		v, err := ParseCephVersion(line)
+		if err != nil {
+			return nil, err
+		}
+		if oldest == nil || v.Less(*oldest) {
+			oldest = &v
+		}
+	}
+	if oldest == nil {
+		return nil, errors.New("ceph versions reported no daemons")
+	}
+	oldest.Mixed = len(counts) > 1
+	return oldest, nil
+}
+
+// parseCephVersion parses the output of 'ceph version --format json'
+func parseCephVersion(output string) (*CephVersion, error) {
+	var out struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal([]byte(output), &out); err != nil {
+		return nil, fmt.Errorf("failed to parse ceph version JSON: %w", err)
+	}
+	v, err := ParseCephVersion(out.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// GetCephVersion returns the oldest Ceph version the cluster's daemons run,
+// from 'ceph versions'. When the daemons cannot be queried it falls back to
+// 'ceph version', the version of the toolbox's ceph CLI. The result is
+// cached per namespace for the lifetime of the client.
+func (c *Client) GetCephVersion(ctx context.Context, namespace string) (*CephVersion, error) {
+	c.versionMu.Lock()
+	cached := c.cephVersions[namespace]
+	c.versionMu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	var version *CephVersion
+	output, err := c.ExecuteCephCommand(ctx, namespace, []string{"ceph", "versions", "--format", "json"})
+	if err == nil {
+		version, err = parseCephVersions(output)
+	}
+	if err != nil {
+		output, cliErr := c.ExecuteCephCommand(ctx, namespace, []string{"ceph", "version", "--format", "json"})
+		if cliErr != nil {
+			return nil, fmt.Errorf("failed to get ceph version: %w", errors.Join(err, cliErr))
+		}
+		if version, err = parseCephVersion(output); err != nil {
+			return nil, err
+		}
+	}
+
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+	if c.cephVersions == nil {
+		c.cephVersions = make(map[string]*CephVersion)
+	}
+	c.cephVersions[namespace] = version
+	return version, nil
+}
+
+// CephFeature is a command or output format that only some Ceph releases have
+type CephFeature string
+
+const (
+	// FeaturePGAutoscaler is 'ceph osd pool autoscale-status'
+	FeaturePGAutoscaler CephFeature = "pg-autoscaler"
+
+	// FeatureOkToStopJSON is the JSON report of 'ceph osd ok-to-stop'; older
+	// releases only answer through the exit status
+	FeatureOkToStopJSON CephFeature = "osd-ok-to-stop-json"
+)
+
+// cephFeatureGate is the first release providing a feature
+type cephFeatureGate struct {
+	since   CephVersion
+	command string
+}
+
+// cephFeatureGates maps each feature to the first release providing it
+var cephFeatureGates = map[CephFeature]cephFeatureGate{
+	FeaturePGAutoscaler: {since: CephVersion{Major: 14, Release: "nautilus"}, command: "ceph osd pool autoscale-status"},
+	FeatureOkToStopJSON: {since: CephVersion{Major: 16, Release: "pacific"}, command: "ceph osd ok-to-stop --format json"},
+}
+
+// MinSupportedCephVersion is the oldest release crook is tested against
+var MinSupportedCephVersion = CephVersion{Major: 14, Release: "nautilus"}
+
+// ErrCephFeatureUnsupported is returned for features the cluster's Ceph
+// release does not have
+var ErrCephFeatureUnsupported = errors.New("not supported by this ceph release")
+
+// Supports reports whether the release provides feature
+func (v CephVersion) Supports(feature CephFeature) bool {
+	gate, ok := cephFeatureGates[feature]
+	return !ok || !v.Less(gate.since)
+}
+
+// Require returns an error wrapping ErrCephFeatureUnsupported that names the
+// release introducing feature when v does not provide it
+func (v CephVersion) Require(feature CephFeature) error {
+	if v.Supports(feature) {
+		return nil
+	}
+	gate := cephFeatureGates[feature]
+	return fmt.Errorf("%s needs ceph %s (%d) or newer, the cluster runs %s: %w",
+		gate.command, gate.since.Release, gate.since.Major, v, ErrCephFeatureUnsupported)
+}
+
+// explainUnsupported replaces the error of a failed ceph command with one
+// naming the release that introduced feature when the cluster predates it,
+// so users see why instead of a parse or "unrecognized command" error. The
+// version is only looked up once a command failed.
+func (c *Client) explainUnsupported(ctx context.Context, namespace string, feature CephFeature, err error) error {
+	version, versionErr := c.GetCephVersion(ctx, namespace)
+	if versionErr != nil {
+		return err
+	}
+	if unsupported := version.Require(feature); unsupported != nil {
+		return unsupported
+	}
+	return err
+}
+
+// ebusy is the exit status of 'ceph osd ok-to-stop' when stopping the OSDs
+// would make placement groups unavailable
+const ebusy = 16
+
+// OkToStopResult is the answer of 'ceph osd ok-to-stop'
+type OkToStopResult struct {
+	// OkToStop reports whether the OSDs can stop without PGs becoming inactive
+	OkToStop bool `json:"ok_to_stop"`
+
+	// OSDs are the OSDs asked about
+	OSDs []int `json:"osds"`
+
+	// NumOkPGs and NumNotOkPGs count the affected PGs; only reported by
+	// releases with FeatureOkToStopJSON
+	NumOkPGs    int `json:"num_ok_pgs"`
+	NumNotOkPGs int `json:"num_not_ok_pgs"`
+
+	// Reason is Ceph's explanation of a negative answer
+	Reason string `json:"-"`
+}
+
+// OSDsOkToStop asks Ceph whether the OSDs can stop without PGs becoming
+// inactive. Releases before Pacific only answer through the exit status, so
+// their result carries no PG counts.
+func (c *Client) OSDsOkToStop(ctx context.Context, namespace string, ids []int) (*OkToStopResult, error) {
+	jsonReport := true
+	if version, err := c.GetCephVersion(ctx, namespace); err == nil {
+		jsonReport = version.Supports(FeatureOkToStopJSON)
+	}
+
+	command := []string{"ceph", "osd", "ok-to-stop"}
+	for _, id := range ids {
+		command = append(command, strconv.Itoa(id))
+	}
+	if jsonReport {
+		command = append(command, "--format", "json")
+	}
+	output, err := c.ExecuteCephCommand(ctx, namespace, command)
+	return parseOkToStop(output, err, jsonReport, ids)
+}
+
+// parseOkToStop interprets the output and error of 'ceph osd ok-to-stop'
+func parseOkToStop(output string, err error, jsonReport bool, ids []int) (*OkToStopResult, error) {
+	if err != nil {
+		// The JSON report is lost with a non-zero exit status; EBUSY is the
+		// negative answer, anything else a failure to ask
+		var exitErr utilexec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitStatus() == ebusy {
+			return &OkToStopResult{OSDs: ids, Reason: err.Error()}, nil
+		}
+		return nil, fmt.Errorf("failed to run ceph osd ok-to-stop: %w", err)
+	}
+
+	if jsonReport && strings.TrimSpace(output) != "" {
+		var result OkToStopResult
+		if unmarshalErr := json.Unmarshal([]byte(output), &result); unmarshalErr == nil {
+			if result.OSDs == nil {
+				result.OSDs = ids
+			}
+			return &result, nil
+		}
+	}
+	// A zero exit status is a positive answer even without a readable report
+	return &OkToStopResult{OkToStop: true, OSDs: ids}, nil
+}
@@ -0,0 +1,185 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+func TestParseCephVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    CephVersion
+		wantErr bool
+	}{
+		{
+			name:  "stable release",
+			input: "ceph version 18.2.4 (e7ad5345525c7aa95470c26863873b581076945d) reef (stable)",
+			want:  CephVersion{Major: 18, Minor: 2, Patch: 4, Release: "reef"},
+		},
+		{
+			name:  "downstream build suffix",
+			input: "ceph version 17.2.6-100.el9cp (ea4e3ef8df2cf26540aae06479df031dcfc80343) quincy (stable)",
+			want:  CephVersion{Major: 17, Minor: 2, Patch: 6, Release: "quincy"},
+		},
+		{
+			name:  "development build without release name",
+			input: "ceph version 20.0.0-1234-gdeadbeef",
+			want:  CephVersion{Major: 20},
+		},
+		{name: "garbage", input: "mon.a: 18.2.4", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCephVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCephVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCephVersion() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCephVersions(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		wantMixed bool
+		wantErr   bool
+	}{
+		{
+			name:  "uniform cluster",
+			input: `{"mon": {"ceph version 18.2.4 (abc) reef (stable)": 3}, "overall": {"ceph version 18.2.4 (abc) reef (stable)": 3}}`,
+			want:  "18.2.4 (reef)",
+		},
+		{
+			name: "upgrade in progress reports the oldest",
+			input: `{"overall": {
+				"ceph version 18.2.4 (abc) reef (stable)": 5,
+				"ceph version 17.2.7 (def) quincy (stable)": 3
+			}}`,
+			want:      "17.2.7 (quincy)",
+			wantMixed: true,
+		},
+		{
+			name:      "no overall section",
+			input:     `{"mon": {"ceph version 16.2.15 (abc) pacific (stable)": 3}, "osd": {"ceph version 16.2.14 (def) pacific (stable)": 6}}`,
+			want:      "16.2.14 (pacific)",
+			wantMixed: true,
+		},
+		{name: "no daemons", input: `{}`, wantErr: true},
+		{name: "invalid JSON", input: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCephVersions(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCephVersions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.String() != tt.want || got.Mixed != tt.wantMixed {
+				t.Errorf("parseCephVersions() = %s (mixed %v), want %s (mixed %v)", got, got.Mixed, tt.want, tt.wantMixed)
+			}
+		})
+	}
+}
+
+func TestParseCephVersion_CLI(t *testing.T) {
+	got, err := parseCephVersion(`{"version": "ceph version 19.2.1 (58a7fab8be0a062d730ad7da874972fd3fba59fb) squid (stable)"}`)
+	if err != nil {
+		t.Fatalf("parseCephVersion() error = %v", err)
+	}
+	if got.String() != "19.2.1 (squid)" {
+		t.Errorf("parseCephVersion() = %s, want 19.2.1 (squid)", got)
+	}
+}
+
+func TestCephVersion_Require(t *testing.T) {
+	tests := []struct {
+		name    string
+		version CephVersion
+		feature CephFeature
+		wantErr bool
+	}{
+		{name: "autoscaler on nautilus", version: CephVersion{Major: 14, Minor: 2, Release: "nautilus"}, feature: FeaturePGAutoscaler},
+		{name: "autoscaler on mimic", version: CephVersion{Major: 13, Minor: 2, Patch: 10, Release: "mimic"}, feature: FeaturePGAutoscaler, wantErr: true},
+		{name: "ok-to-stop JSON on pacific", version: CephVersion{Major: 16, Minor: 2}, feature: FeatureOkToStopJSON},
+		{name: "ok-to-stop JSON on octopus", version: CephVersion{Major: 15, Minor: 2, Patch: 17}, feature: FeatureOkToStopJSON, wantErr: true},
+		{name: "unknown feature", version: CephVersion{Major: 12}, feature: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.version.Require(tt.feature)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Require() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrCephFeatureUnsupported) {
+				t.Errorf("Require() error = %v, want ErrCephFeatureUnsupported", err)
+			}
+			if tt.version.Supports(tt.feature) == tt.wantErr {
+				t.Errorf("Supports() = %v, want %v", !tt.wantErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseOkToStop(t *testing.T) {
+	busy := utilexec.CodeExitError{Err: errors.New("command terminated with exit code 16"), Code: ebusy}
+	ids := []int{3, 4}
+
+	tests := []struct {
+		name       string
+		output     string
+		err        error
+		jsonReport bool
+		wantOk     bool
+		wantNotOk  int
+		wantErr    bool
+	}{
+		{
+			name:       "json report ok",
+			output:     `{"ok_to_stop": true, "osds": [3, 4], "num_ok_pgs": 40, "num_not_ok_pgs": 0}`,
+			jsonReport: true,
+			wantOk:     true,
+		},
+		{
+			name:       "json report not ok",
+			output:     `{"ok_to_stop": false, "osds": [3, 4], "num_ok_pgs": 30, "num_not_ok_pgs": 10}`,
+			jsonReport: true,
+			wantNotOk:  10,
+		},
+		{name: "exit status ok", output: "OSD(s) 3,4 are ok to stop without reducing availability", wantOk: true},
+		{name: "unreadable json report with exit status ok", output: "OSD(s) 3,4 are ok to stop", jsonReport: true, wantOk: true},
+		{name: "EBUSY", err: fmt.Errorf("failed to execute ceph command: %w", busy)},
+		{name: "other failure", err: errors.New("toolbox pod not ready"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOkToStop(tt.output, tt.err, tt.jsonReport, ids)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOkToStop() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.OkToStop != tt.wantOk || got.NumNotOkPGs != tt.wantNotOk || len(got.OSDs) != 2 {
+				t.Errorf("parseOkToStop() = %+v, want ok=%v notOk=%d for 2 OSDs", got, tt.wantOk, tt.wantNotOk)
+			}
+			if !got.OkToStop && tt.err != nil && got.Reason == "" {
+				t.Error("a negative exit status answer should carry Ceph's reason")
+			}
+		})
+	}
+}
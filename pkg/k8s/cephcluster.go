@@ -0,0 +1,117 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CephClusterGVR identifies the Rook CephCluster custom resource
+var CephClusterGVR = schema.GroupVersionResource{
+	Group:    "ceph.rook.io",
+	Version:  "v1",
+	Resource: "cephclusters",
+}
+
+// CephClusterLabel is the label Rook sets to the owning CephCluster's name on
+// every daemon deployment and pod it manages
+const CephClusterLabel = "app.kubernetes.io/part-of"
+
+// CephCluster returns the CephCluster name the client is restricted to, or ""
+func (c *Client) CephCluster() string {
+	return c.cephCluster
+}
+
+// cephClusterListOptions restricts a list of Rook daemons to the selected
+// CephCluster. Without a selection every object in the namespace is listed.
+func (c *Client) cephClusterListOptions() metav1.ListOptions {
+	if c.cephCluster == "" {
+		return metav1.ListOptions{}
+	}
+	return metav1.ListOptions{LabelSelector: CephClusterLabel + "=" + c.cephCluster}
+}
+
+// toolboxSelectors returns the label selectors tried in order to find the
+// toolbox pod. With a CephCluster selected, a toolbox labeled for it wins and
+// an unlabeled toolbox is the fallback; toolboxes labeled for another cluster
+// are never used.
+func (c *Client) toolboxSelectors() []string {
+	if c.cephCluster == "" {
+		return []string{"app=rook-ceph-tools"}
+	}
+	return []string{
+		"app=rook-ceph-tools," + CephClusterLabel + "=" + c.cephCluster,
+		"app=rook-ceph-tools,!" + CephClusterLabel,
+	}
+}
+
+// CephClusterSettings holds the CephCluster CR settings that interact with
+// node maintenance. Only the fields crook cares about are extracted.
+type CephClusterSettings struct {
+	// Name is the CephCluster resource name
+	Name string
+
+	// Namespace is the CephCluster resource namespace
+	Namespace string
+
+	// ManagePodBudgets is spec.disruptionManagement.managePodBudgets.
+	// When enabled, Rook manages OSD PodDisruptionBudgets and sets noout on
+	// failure domains during node drains.
+	ManagePodBudgets bool
+
+	// OSDMaintenanceTimeout is spec.disruptionManagement.osdMaintenanceTimeout (minutes)
+	OSDMaintenanceTimeout int64
+
+	// SkipUpgradeChecks is spec.skipUpgradeChecks
+	SkipUpgradeChecks bool
+
+	// ContinueUpgradeAfterChecksEvenIfNotHealthy is spec.continueUpgradeAfterChecksEvenIfNotHealthy
+	ContinueUpgradeAfterChecksEvenIfNotHealthy bool
+
+	// OSDConfig holds spec.cephConfig.osd key/value overrides (e.g. osd_memory_target)
+	OSDConfig map[string]string
+
+	// External is spec.external.enable: the Ceph daemons run outside
+	// Kubernetes and Rook only consumes the cluster
+	External bool
+}
+
+// ListCephClusters returns the maintenance-relevant settings of all CephCluster
+// resources in the namespace.
+func (c *Client) ListCephClusters(ctx context.Context, namespace string) ([]CephClusterSettings, error) {
+	if c.Dynamic == nil {
+		return nil, fmt.Errorf("dynamic client not configured")
+	}
+
+	list, err := c.Dynamic.Resource(CephClusterGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cephclusters in namespace %s: %w", namespace, err)
+	}
+
+	result := make([]CephClusterSettings, 0, len(list.Items))
+	for i := range list.Items {
+		result = append(result, parseCephClusterSettings(&list.Items[i]))
+	}
+	return result, nil
+}
+
+// parseCephClusterSettings extracts maintenance-relevant fields from a CephCluster object.
+// Missing or mistyped fields are left at their zero values.
+func parseCephClusterSettings(obj *unstructured.Unstructured) CephClusterSettings {
+	settings := CephClusterSettings{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+	}
+
+	settings.ManagePodBudgets, _, _ = unstructured.NestedBool(obj.Object, "spec", "disruptionManagement", "managePodBudgets")
+	settings.OSDMaintenanceTimeout, _, _ = unstructured.NestedInt64(obj.Object, "spec", "disruptionManagement", "osdMaintenanceTimeout")
+	settings.SkipUpgradeChecks, _, _ = unstructured.NestedBool(obj.Object, "spec", "skipUpgradeChecks")
+	settings.ContinueUpgradeAfterChecksEvenIfNotHealthy, _, _ = unstructured.NestedBool(obj.Object, "spec", "continueUpgradeAfterChecksEvenIfNotHealthy")
+	settings.OSDConfig, _, _ = unstructured.NestedStringMap(obj.Object, "spec", "cephConfig", "osd")
+	settings.External, _, _ = unstructured.NestedBool(obj.Object, "spec", "external", "enable")
+
+	return settings
+}
@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newCephCluster(namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "ceph.rook.io/v1",
+			"kind":       "CephCluster",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": namespace,
+			},
+			"spec": spec,
+		},
+	}
+}
+
+func TestListCephClusters(t *testing.T) {
+	cluster := newCephCluster("rook-ceph", "rook-ceph", map[string]interface{}{
+		"disruptionManagement": map[string]interface{}{
+			"managePodBudgets":      true,
+			"osdMaintenanceTimeout": int64(30),
+		},
+		"skipUpgradeChecks": true,
+		"external": map[string]interface{}{
+			"enable": true,
+		},
+		"cephConfig": map[string]interface{}{
+			"osd": map[string]interface{}{
+				"osd_memory_target": "4294967296",
+			},
+		},
+	})
+	other := newCephCluster("other-ns", "other", map[string]interface{}{})
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{CephClusterGVR: "CephClusterList"},
+		cluster, other,
+	)
+
+	client := newClientFromClientset(fake.NewClientset())
+	client.Dynamic = dynamicClient
+
+	clusters, err := client.ListCephClusters(context.Background(), "rook-ceph")
+	if err != nil {
+		t.Fatalf("ListCephClusters() error = %v", err)
+	}
+	if len(clusters) != 1 {
+		t.Fatalf("ListCephClusters() returned %d clusters, want 1", len(clusters))
+	}
+
+	got := clusters[0]
+	if got.Name != "rook-ceph" || got.Namespace != "rook-ceph" {
+		t.Errorf("unexpected cluster identity %s/%s", got.Namespace, got.Name)
+	}
+	if !got.ManagePodBudgets {
+		t.Error("ManagePodBudgets = false, want true")
+	}
+	if got.OSDMaintenanceTimeout != 30 {
+		t.Errorf("OSDMaintenanceTimeout = %d, want 30", got.OSDMaintenanceTimeout)
+	}
+	if !got.SkipUpgradeChecks {
+		t.Error("SkipUpgradeChecks = false, want true")
+	}
+	if got.ContinueUpgradeAfterChecksEvenIfNotHealthy {
+		t.Error("ContinueUpgradeAfterChecksEvenIfNotHealthy = true, want false")
+	}
+	if got.OSDConfig["osd_memory_target"] != "4294967296" {
+		t.Errorf("OSDConfig[osd_memory_target] = %q, want 4294967296", got.OSDConfig["osd_memory_target"])
+	}
+	if !got.External {
+		t.Error("External = false, want true")
+	}
+}
+
+func TestListCephClusters_NoDynamicClient(t *testing.T) {
+	client := newClientFromClientset(fake.NewClientset())
+
+	if _, err := client.ListCephClusters(context.Background(), "rook-ceph"); err == nil {
+		t.Error("ListCephClusters() expected error without dynamic client")
+	}
+}
@@ -0,0 +1,73 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Change is one modification crook made to a cluster object: the field it
+// set and its value before and after
+type Change struct {
+	Time      time.Time `json:"time"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	Field     string    `json:"field"`
+	Before    string    `json:"before"`
+	After     string    `json:"after"`
+}
+
+// Object names the changed object, e.g. "Deployment rook-ceph/rook-ceph-osd-3"
+func (c Change) Object() string {
+	if c.Namespace == "" {
+		return c.Kind + " " + c.Name
+	}
+	return fmt.Sprintf("%s %s/%s", c.Kind, c.Namespace, c.Name)
+}
+
+// Diff renders the change as a unified diff of the changed field
+func (c Change) Diff() string {
+	return strings.Join([]string{
+		"--- " + c.Object(),
+		"+++ " + c.Object(),
+		fmt.Sprintf("-%s: %s", c.Field, c.Before),
+		fmt.Sprintf("+%s: %s", c.Field, c.After),
+	}, "\n")
+}
+
+// changeLog records the changes a client made, in order
+type changeLog struct {
+	mu      sync.Mutex
+	changes []Change
+}
+
+// recordChange adds a change made by the client to its change log
+func (c *Client) recordChange(change Change) {
+	if change.Time.IsZero() {
+		change.Time = time.Now()
+	}
+	c.changes.mu.Lock()
+	defer c.changes.mu.Unlock()
+	c.changes.changes = append(c.changes.changes, change)
+}
+
+// ChangeCount returns how many changes the client has made, a mark for
+// ChangesSince
+func (c *Client) ChangeCount() int {
+	c.changes.mu.Lock()
+	defer c.changes.mu.Unlock()
+	return len(c.changes.changes)
+}
+
+// ChangesSince returns the changes the client made after the given
+// ChangeCount mark, oldest first
+func (c *Client) ChangesSince(mark int) []Change {
+	c.changes.mu.Lock()
+	defer c.changes.mu.Unlock()
+	if mark < 0 || mark >= len(c.changes.changes) {
+		return nil
+	}
+	return append([]Change(nil), c.changes.changes[mark:]...)
+}
@@ -0,0 +1,40 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestScaleWorkload_RecordsChange(t *testing.T) {
+	clientset := fake.NewClientset()
+	replicas := int32(2)
+	addResourceScaleReactors(clientset, "deployments", map[string]*int32{"rook-ceph/rook-ceph-osd-3": &replicas})
+	client := newClientFromClientset(clientset)
+
+	mark := client.ChangeCount()
+	if err := client.ScaleDeployment(context.Background(), "rook-ceph", "rook-ceph-osd-3", 0); err != nil {
+		t.Fatalf("ScaleDeployment() error = %v", err)
+	}
+	// A failed scale changes nothing and is not recorded
+	if err := client.ScaleDeployment(context.Background(), "rook-ceph", "missing", 0); err == nil {
+		t.Fatal("ScaleDeployment() expected error for a missing deployment")
+	}
+
+	changes := client.ChangesSince(mark)
+	if len(changes) != 1 {
+		t.Fatalf("ChangesSince() = %+v, want one change", changes)
+	}
+	got := changes[0]
+	if got.Object() != "Deployment rook-ceph/rook-ceph-osd-3" || got.Before != "2" || got.After != "0" || got.Time.IsZero() {
+		t.Errorf("change = %+v", got)
+	}
+	want := "--- Deployment rook-ceph/rook-ceph-osd-3\n+++ Deployment rook-ceph/rook-ceph-osd-3\n-spec.replicas: 2\n+spec.replicas: 0"
+	if diff := got.Diff(); diff != want {
+		t.Errorf("Diff() = %q, want %q", diff, want)
+	}
+	if later := client.ChangesSince(client.ChangeCount()); later != nil {
+		t.Errorf("ChangesSince(current) = %+v, want none", later)
+	}
+}
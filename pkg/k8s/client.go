@@ -3,9 +3,16 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/andri/crook/internal/faults"
+	"github.com/andri/crook/internal/logger"
 	"github.com/andri/crook/pkg/config"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -14,11 +21,30 @@ import (
 // DefaultCephTimeout is the default timeout for Ceph CLI commands.
 const DefaultCephTimeout = time.Duration(config.DefaultCephCommandTimeoutSeconds) * time.Second
 
+// serviceAccountDir is where Kubernetes mounts the pod's service account credentials
+var serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
 // Client wraps Kubernetes clientset with additional functionality
 type Client struct {
 	Clientset          kubernetes.Interface
+	Dynamic            dynamic.Interface
 	config             *rest.Config
 	cephCommandTimeout time.Duration
+	cephCommands       config.CephCommandsConfig
+	cephCluster        string
+	deploymentFilters  config.DeploymentFiltersConfig
+
+	// faults fails chosen requests and ceph commands; nil outside fault
+	// injection builds
+	faults *faults.Injector
+
+	// cephVersions caches GetCephVersion by namespace
+	versionMu    sync.Mutex
+	cephVersions map[string]*CephVersion
+
+	// changes records the changes made to cluster objects, for the audit
+	// trail of a maintenance
+	changes changeLog
 }
 
 // ClientConfig holds configuration for creating a Kubernetes client
@@ -26,6 +52,28 @@ type ClientConfig struct {
 	// CephCommandTimeout is the timeout for Ceph CLI commands.
 	// If zero, uses DefaultCephTimeout.
 	CephCommandTimeout time.Duration
+
+	// CephCommands customizes the ceph invocations run in the toolbox pod
+	CephCommands config.CephCommandsConfig
+
+	// CephCluster restricts daemon discovery and the toolbox pod to one
+	// CephCluster by name. Empty means every Rook daemon in the namespace.
+	CephCluster string
+
+	// DeploymentFilters decide which deployments are listed as Rook-Ceph
+	// deployments. Empty means DefaultRookCephPrefixes().
+	DeploymentFilters config.DeploymentFiltersConfig
+
+	// InCluster forces in-cluster service account credentials instead of
+	// kubeconfig resolution (e.g. when running as a Kubernetes Job).
+	InCluster bool
+
+	// Context selects a kubeconfig context other than the current one
+	Context string
+
+	// NonInteractiveAuth tells exec credential plugins that stdin is unavailable,
+	// so credential refresh during a TUI session never reads from the terminal.
+	NonInteractiveAuth bool
 }
 
 // NewClient creates a new Kubernetes client with the given configuration
@@ -35,11 +83,36 @@ func NewClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
 		return nil, fmt.Errorf("failed to build kubernetes config: %w", err)
 	}
 
+	injector, err := faults.Active()
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", faults.EnvVar, err)
+	}
+	if injector != nil {
+		logger.Warn("fault injection enabled", "rules", injector.String())
+		config.Wrap(injector.WrapTransport)
+	}
+
+	// client-go re-runs exec plugins when credentials expire or are rejected;
+	// make sure such a refresh cannot block on a terminal owned by the TUI
+	if cfg.NonInteractiveAuth && config.ExecProvider != nil {
+		config.ExecProvider.StdinUnavailable = true
+		config.ExecProvider.StdinUnavailableMessage = "crook is running an interactive TUI"
+	}
+
 	clientset, clientErr := kubernetes.NewForConfig(config)
 	if clientErr != nil {
 		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", clientErr)
 	}
 
+	dynamicClient, dynamicErr := dynamic.NewForConfig(config)
+	if dynamicErr != nil {
+		return nil, fmt.Errorf("failed to create kubernetes dynamic client: %w", dynamicErr)
+	}
+
+	if _, filterErr := NewDeploymentFilter(cfg.DeploymentFilters); filterErr != nil {
+		return nil, fmt.Errorf("invalid deployment filters: %w", filterErr)
+	}
+
 	cephTimeout := cfg.CephCommandTimeout
 	if cephTimeout == 0 {
 		cephTimeout = DefaultCephTimeout
@@ -47,8 +120,13 @@ func NewClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
 
 	client := &Client{
 		Clientset:          clientset,
+		Dynamic:            dynamicClient,
 		config:             config,
 		cephCommandTimeout: cephTimeout,
+		cephCommands:       cfg.CephCommands,
+		cephCluster:        cfg.CephCluster,
+		deploymentFilters:  cfg.DeploymentFilters,
+		faults:             injector,
 	}
 
 	// Validate connectivity by checking the /version endpoint
@@ -59,21 +137,29 @@ func NewClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
 	return client, nil
 }
 
-// buildConfig builds a Kubernetes REST config using standard resolution order:
-// 1. In-cluster config (when running inside a pod)
-// 2. KUBECONFIG environment variable (supports colon-separated paths)
-// 3. Default kubeconfig location (~/.kube/config)
-func buildConfig(_ ClientConfig) (*rest.Config, error) {
+// buildConfig builds a Kubernetes REST config. In-cluster service account
+// credentials are used when cfg.InCluster is set, or when no kubeconfig file is
+// present and crook runs inside a pod. Otherwise the standard resolution applies:
+// 1. KUBECONFIG environment variable (supports colon-separated paths)
+// 2. Default kubeconfig location (~/.kube/config)
+func buildConfig(cfg ClientConfig) (*rest.Config, error) {
 	// Use client-go's standard loading rules which handle:
-	// - In-cluster config detection
 	// - KUBECONFIG env var (including colon-separated paths)
 	// - Default ~/.kube/config location
 	// - Proper path expansion
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 
+	if cfg.InCluster || (!kubeconfigPresent(loadingRules) && RunningInCluster()) {
+		config, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load in-cluster config: %w", err)
+		}
+		return config, nil
+	}
+
 	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
 		loadingRules,
-		&clientcmd.ConfigOverrides{},
+		&clientcmd.ConfigOverrides{CurrentContext: cfg.Context},
 	)
 
 	config, err := clientConfig.ClientConfig()
@@ -84,6 +170,46 @@ func buildConfig(_ ClientConfig) (*rest.Config, error) {
 	return config, nil
 }
 
+// UsesInClusterConfig reports whether a client built from cfg will use
+// in-cluster service account credentials rather than a kubeconfig.
+func UsesInClusterConfig(cfg ClientConfig) bool {
+	return cfg.InCluster || (!kubeconfigPresent(clientcmd.NewDefaultClientConfigLoadingRules()) && RunningInCluster())
+}
+
+// RunningInCluster reports whether crook runs inside a pod with a mounted
+// service account token.
+func RunningInCluster() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" || os.Getenv("KUBERNETES_SERVICE_PORT") == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(serviceAccountDir, "token"))
+	return err == nil
+}
+
+// InClusterNamespace returns the namespace of the pod crook runs in, read from
+// the mounted service account. It returns false outside a pod.
+func InClusterNamespace() (string, bool) {
+	data, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+	if err != nil {
+		return "", false
+	}
+	namespace := strings.TrimSpace(string(data))
+	return namespace, namespace != ""
+}
+
+// kubeconfigPresent reports whether any kubeconfig file in the loading precedence exists
+func kubeconfigPresent(rules *clientcmd.ClientConfigLoadingRules) bool {
+	if rules.ExplicitPath != "" {
+		return true
+	}
+	for _, path := range rules.GetLoadingPrecedence() {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
 // validateConnectivity validates that the client can communicate with the Kubernetes API
 func (c *Client) validateConnectivity(_ context.Context) error {
 	_, err := c.Clientset.Discovery().ServerVersion()
@@ -89,3 +89,70 @@ func TestNewClient_WithValidKubeconfig(t *testing.T) {
 		t.Fatal("expected non-nil clientset")
 	}
 }
+
+// fakeServiceAccount mounts a fake service account directory for the test
+func fakeServiceAccount(t *testing.T, namespace string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "namespace"), []byte(namespace+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := serviceAccountDir
+	serviceAccountDir = dir
+	t.Cleanup(func() { serviceAccountDir = old })
+}
+
+func TestInClusterDetection(t *testing.T) {
+	fakeServiceAccount(t, "rook-ceph-debug")
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("HOME", t.TempDir())
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+	if RunningInCluster() {
+		t.Error("RunningInCluster() = true without service environment")
+	}
+	if UsesInClusterConfig(ClientConfig{}) {
+		t.Error("UsesInClusterConfig() = true outside a pod")
+	}
+	if !UsesInClusterConfig(ClientConfig{InCluster: true}) {
+		t.Error("UsesInClusterConfig() = false with InCluster forced")
+	}
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.96.0.1")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "443")
+	if !RunningInCluster() {
+		t.Error("RunningInCluster() = false with service account mounted")
+	}
+	if !UsesInClusterConfig(ClientConfig{}) {
+		t.Error("UsesInClusterConfig() = false without kubeconfig inside a pod")
+	}
+
+	namespace, ok := InClusterNamespace()
+	if !ok || namespace != "rook-ceph-debug" {
+		t.Errorf("InClusterNamespace() = %q, %v; want rook-ceph-debug, true", namespace, ok)
+	}
+
+	// A kubeconfig takes precedence over the mounted service account
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfig, []byte("apiVersion: v1\nkind: Config\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+	if UsesInClusterConfig(ClientConfig{}) {
+		t.Error("UsesInClusterConfig() = true although a kubeconfig exists")
+	}
+}
+
+func TestInClusterNamespace_NotMounted(t *testing.T) {
+	old := serviceAccountDir
+	serviceAccountDir = t.TempDir()
+	t.Cleanup(func() { serviceAccountDir = old })
+
+	if namespace, ok := InClusterNamespace(); ok {
+		t.Errorf("InClusterNamespace() = %q, true; want false", namespace)
+	}
+}
@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetConfigMap returns a ConfigMap by name
+func (c *Client) GetConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	cm, err := c.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configmap %s/%s: %w", namespace, name, err)
+	}
+	return cm, nil
+}
+
+// ListConfigMaps returns the ConfigMaps in the namespace matching the label selector
+func (c *Client) ListConfigMaps(ctx context.Context, namespace, selector string) ([]corev1.ConfigMap, error) {
+	list, err := c.Clientset.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list configmaps in %s: %w", namespace, err)
+	}
+	return list.Items, nil
+}
+
+// UpdateConfigMap replaces a ConfigMap previously read with GetConfigMap. It
+// fails with a conflict error when the ConfigMap changed in the meantime.
+func (c *Client) UpdateConfigMap(ctx context.Context, cm *corev1.ConfigMap) error {
+	if _, err := c.Clientset.CoreV1().ConfigMaps(cm.Namespace).Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update configmap %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+	return nil
+}
+
+// DeleteConfigMap deletes a ConfigMap by name. A missing ConfigMap is not an error.
+func (c *Client) DeleteConfigMap(ctx context.Context, namespace, name string) error {
+	err := c.Clientset.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete configmap %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// ApplyConfigMap creates the ConfigMap, or replaces the labels and data of an
+// existing ConfigMap with the same name
+func (c *Client) ApplyConfigMap(ctx context.Context, cm *corev1.ConfigMap) error {
+	configMaps := c.Clientset.CoreV1().ConfigMaps(cm.Namespace)
+
+	existing, err := configMaps.Get(ctx, cm.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, createErr := configMaps.Create(ctx, cm, metav1.CreateOptions{}); createErr != nil {
+			return fmt.Errorf("failed to create configmap %s/%s: %w", cm.Namespace, cm.Name, createErr)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get configmap %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+
+	existing.Labels = cm.Labels
+	existing.Data = cm.Data
+	if _, updateErr := configMaps.Update(ctx, existing, metav1.UpdateOptions{}); updateErr != nil {
+		return fmt.Errorf("failed to update configmap %s/%s: %w", cm.Namespace, cm.Name, updateErr)
+	}
+	return nil
+}
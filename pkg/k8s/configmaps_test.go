@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestApplyConfigMap(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{Clientset: fake.NewClientset()}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "crook-report-worker-1", Namespace: "rook-ceph"},
+		Data:       map[string]string{"report.json": "{}"},
+	}
+	if err := client.ApplyConfigMap(ctx, cm); err != nil {
+		t.Fatalf("create: unexpected error: %v", err)
+	}
+
+	updated := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crook-report-worker-1",
+			Namespace: "rook-ceph",
+			Labels:    map[string]string{"app.kubernetes.io/name": "crook"},
+		},
+		Data: map[string]string{"report.json": `{"node":"worker-1"}`},
+	}
+	if err := client.ApplyConfigMap(ctx, updated); err != nil {
+		t.Fatalf("update: unexpected error: %v", err)
+	}
+
+	got, err := client.GetConfigMap(ctx, "rook-ceph", "crook-report-worker-1")
+	if err != nil {
+		t.Fatalf("get: unexpected error: %v", err)
+	}
+	if got.Data["report.json"] != `{"node":"worker-1"}` {
+		t.Errorf("data = %v, want updated report", got.Data)
+	}
+	if got.Labels["app.kubernetes.io/name"] != "crook" {
+		t.Errorf("labels = %v, want updated labels", got.Labels)
+	}
+}
+
+func TestGetConfigMap_NotFound(t *testing.T) {
+	client := &Client{Clientset: fake.NewClientset()}
+
+	if _, err := client.GetConfigMap(context.Background(), "rook-ceph", "missing"); err == nil {
+		t.Fatal("expected error for missing configmap")
+	}
+}
+
+func TestDeleteConfigMap(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{Clientset: fake.NewClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "crook-windows-worker-1", Namespace: "rook-ceph"},
+	})}
+
+	if err := client.DeleteConfigMap(ctx, "rook-ceph", "crook-windows-worker-1"); err != nil {
+		t.Fatalf("DeleteConfigMap() error = %v", err)
+	}
+	if _, err := client.GetConfigMap(ctx, "rook-ceph", "crook-windows-worker-1"); err == nil {
+		t.Error("configmap still exists after delete")
+	}
+	// Deleting it again is not an error
+	if err := client.DeleteConfigMap(ctx, "rook-ceph", "crook-windows-worker-1"); err != nil {
+		t.Errorf("DeleteConfigMap() of a missing configmap error = %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/andri/crook/pkg/config"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -39,24 +40,7 @@ type DeploymentStatus struct {
 // Uses the /scale subresource API for least-privilege RBAC (only requires
 // deployments/scale permission, not full deployments update permission).
 func (c *Client) ScaleDeployment(ctx context.Context, namespace, name string, replicas int32) error {
-	deploymentsClient := c.Clientset.AppsV1().Deployments(namespace)
-
-	// Get the current scale
-	scale, err := deploymentsClient.GetScale(ctx, name, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to get scale for deployment %s/%s: %w", namespace, name, err)
-	}
-
-	// Update the replicas
-	scale.Spec.Replicas = replicas
-
-	// Update the scale subresource
-	_, err = deploymentsClient.UpdateScale(ctx, name, scale, metav1.UpdateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to scale deployment %s/%s to %d replicas: %w", namespace, name, replicas, err)
-	}
-
-	return nil
+	return c.ScaleWorkload(ctx, WorkloadKindDeployment, namespace, name, replicas)
 }
 
 // GetDeploymentStatus returns the status of a deployment
@@ -81,34 +65,45 @@ func (c *Client) GetDeploymentStatus(ctx context.Context, namespace, name string
 	}, nil
 }
 
-// ListDeploymentsInNamespace returns all deployments in a namespace
+// ListDeploymentsInNamespace returns all deployments in a namespace, limited to
+// the selected CephCluster if any
 func (c *Client) ListDeploymentsInNamespace(ctx context.Context, namespace string) ([]appsv1.Deployment, error) {
-	deploymentList, err := c.Clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	return c.listDeploymentsMatching(ctx, namespace, ListSelector{})
+}
+
+// listDeploymentsMatching is ListDeploymentsInNamespace limited to the
+// deployments matching the selector
+func (c *Client) listDeploymentsMatching(ctx context.Context, namespace string, selector ListSelector) ([]appsv1.Deployment, error) {
+	deployments, err := c.listDeployments(ctx, namespace, selector.apply(c.cephClusterListOptions()))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list deployments in namespace %s: %w", namespace, err)
 	}
 
-	return deploymentList.Items, nil
+	return deployments, nil
 }
 
 // FilterDeploymentsByPrefix returns deployments whose names start with any of the given prefixes.
 // If prefixes is nil or empty, uses DefaultRookCephPrefixes().
 func FilterDeploymentsByPrefix(deployments []appsv1.Deployment, prefixes []string) []appsv1.Deployment {
-	if len(prefixes) == 0 {
-		prefixes = DefaultRookCephPrefixes()
-	}
+	// Prefix-only rules always compile
+	filter, _ := NewDeploymentFilter(config.DeploymentFiltersConfig{Include: PrefixRules(prefixes)})
+	return filter.Filter(deployments)
+}
 
-	var filtered []appsv1.Deployment
-	for _, deployment := range deployments {
-		for _, prefix := range prefixes {
-			if strings.HasPrefix(deployment.Name, prefix) {
-				filtered = append(filtered, deployment)
-				break
-			}
-		}
+// CephDeploymentFilter returns the filter selecting Rook-Ceph deployments:
+// the configured deployment filters, with their include rules replaced by
+// the given prefixes when there are any. Exclude rules always apply.
+func (c *Client) CephDeploymentFilter(prefixes []string) *DeploymentFilter {
+	filters := c.deploymentFilters
+	if len(prefixes) > 0 {
+		filters.Include = PrefixRules(prefixes)
 	}
-
-	return filtered
+	filter, err := NewDeploymentFilter(filters)
+	if err != nil {
+		// Validated when the client is created; fall back to the built-in prefixes
+		filter, _ = NewDeploymentFilter(config.DeploymentFiltersConfig{})
+	}
+	return filter
 }
 
 // WaitForReplicasOptions holds options for waiting for replicas
@@ -212,84 +207,171 @@ type DeploymentInfo struct {
 
 	// OsdID is the OSD ID (from label ceph-osd-id, if applicable)
 	OsdID string `json:"osd_id,omitempty"`
+
+	// Image is the image of the pod template's first container
+	Image string `json:"image,omitempty"`
+
+	// CephVersion is the Ceph version Rook recorded on a Ceph daemon deployment
+	CephVersion string `json:"ceph_version,omitempty"`
+}
+
+// CephVersionLabel is the label Rook sets on Ceph daemon deployments to the
+// version of Ceph they run, e.g. 18.2.2-0
+const CephVersionLabel = "ceph-version"
+
+// Version returns the deployment's Ceph version, or its image tag for
+// deployments that do not run a Ceph daemon
+func (d DeploymentInfo) Version() string {
+	if d.CephVersion != "" {
+		return d.CephVersion
+	}
+	return ImageTag(d.Image)
+}
+
+// ImageTag returns the tag of an image reference, a shortened digest for
+// images pinned by digest, latest for untagged images, or "" without an image
+func ImageTag(image string) string {
+	if image == "" {
+		return ""
+	}
+	if _, digest, ok := strings.Cut(image, "@"); ok {
+		_, hex, _ := strings.Cut(digest, ":")
+		return "@" + hex[:min(len(hex), 12)]
+	}
+	// A colon before the last slash belongs to a registry port
+	name := image[strings.LastIndex(image, "/")+1:]
+	if _, tag, ok := strings.Cut(name, ":"); ok {
+		return tag
+	}
+	return "latest"
+}
+
+// PredominantCephVersion returns the Ceph version most deployments run, or ""
+// if none reports one. Ties go to the higher version string, so a half-finished
+// upgrade flags the daemons still on the old version.
+func PredominantCephVersion(deployments []DeploymentInfo) string {
+	counts := make(map[string]int)
+	for _, dep := range deployments {
+		if dep.CephVersion != "" {
+			counts[dep.CephVersion]++
+		}
+	}
+
+	var predominant string
+	for version, count := range counts {
+		best := counts[predominant]
+		if count > best || (count == best && version > predominant) {
+			predominant = version
+		}
+	}
+	return predominant
 }
 
 // ListCephDeployments returns Ceph deployments with detailed info.
-// Uses DefaultRookCephPrefixes() to filter deployments.
+// Uses the client's deployment filters, DefaultRookCephPrefixes() by default.
 func (c *Client) ListCephDeployments(ctx context.Context, namespace string) ([]DeploymentInfo, error) {
-	// Get all deployments in the namespace
-	deployments, err := c.ListDeploymentsInNamespace(ctx, namespace)
+	return c.ListCephDeploymentsByPrefix(ctx, namespace, nil)
+}
+
+// ListCephDeploymentsByPrefix returns Ceph deployments with detailed info,
+// filtered by the given name prefixes instead of the include rules of the
+// client's deployment filters. If prefixes is nil or empty, the deployment
+// filters apply unchanged (see CephDeploymentFilter).
+func (c *Client) ListCephDeploymentsByPrefix(ctx context.Context, namespace string, prefixes []string) ([]DeploymentInfo, error) {
+	return c.ListCephDeploymentsMatching(ctx, namespace, prefixes, ListSelector{})
+}
+
+// ListCephDeploymentsMatching is ListCephDeploymentsByPrefix limited to the
+// deployments matching the selector
+func (c *Client) ListCephDeploymentsMatching(ctx context.Context, namespace string, prefixes []string, selector ListSelector) ([]DeploymentInfo, error) {
+	filter := c.CephDeploymentFilter(prefixes)
+	now := time.Now()
+
+	// Describe the selected deployments as each page arrives. The nodeSelector
+	// is the primary source of the node (works for 0-replica deployments).
+	result, err := projectDeployments(ctx, c, namespace, selector.apply(c.cephClusterListOptions()),
+		func(dep *appsv1.Deployment) (DeploymentInfo, bool) {
+			if !filter.Matches(dep) {
+				return DeploymentInfo{}, false
+			}
+			return NewDeploymentInfo(dep, GetDeploymentTargetNode(dep), now), true
+		})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list deployments: %w", err)
+		return nil, fmt.Errorf("failed to list deployments in namespace %s: %w", namespace, err)
 	}
 
-	// Filter by default prefixes
-	filtered := FilterDeploymentsByPrefix(deployments, nil)
-
-	// Get pods in namespace to map deployments to nodes
-	podList, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	// Fallback: without a nodeSelector, find the node via the pods; only
+	// their ReplicaSet owner and node are kept
+	pods, err := projectPods(ctx, c, namespace, c.cephClusterListOptions(), func(pod *corev1.Pod) (podOwner, bool) {
+		for _, ownerRef := range pod.OwnerReferences {
+			if ownerRef.Kind == "ReplicaSet" {
+				return podOwner{replicaSet: ownerRef.Name, node: pod.Spec.NodeName}, true
+			}
+		}
+		return podOwner{}, false
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
 	}
 
 	// Build deployment -> node map via pods
 	deploymentNodes := make(map[string]string)
-	for _, pod := range podList.Items {
-		// Find deployment via owner references
-		for _, ownerRef := range pod.OwnerReferences {
-			if ownerRef.Kind == "ReplicaSet" {
-				// ReplicaSet name format: <deployment-name>-<hash>
-				// Find the best (longest) matching deployment name to handle cases
-				// where one deployment name is a prefix of another (e.g., "rook-ceph-exporter-rook"
-				// vs "rook-ceph-exporter-rook-m02")
-				rsName := ownerRef.Name
-				var bestMatch string
-				for _, dep := range filtered {
-					prefix := dep.Name + "-"
-					if strings.HasPrefix(rsName, prefix) {
-						// Keep the longest matching deployment name
-						if len(dep.Name) > len(bestMatch) {
-							bestMatch = dep.Name
-						}
-					}
-				}
-				if bestMatch != "" {
-					deploymentNodes[bestMatch] = pod.Spec.NodeName
+	for _, pod := range pods {
+		// ReplicaSet name format: <deployment-name>-<hash>
+		// Find the best (longest) matching deployment name to handle cases
+		// where one deployment name is a prefix of another (e.g., "rook-ceph-exporter-rook"
+		// vs "rook-ceph-exporter-rook-m02")
+		var bestMatch string
+		for _, dep := range result {
+			prefix := dep.Name + "-"
+			if strings.HasPrefix(pod.replicaSet, prefix) {
+				// Keep the longest matching deployment name
+				if len(dep.Name) > len(bestMatch) {
+					bestMatch = dep.Name
 				}
 			}
 		}
-	}
-
-	// Build result
-	result := make([]DeploymentInfo, 0, len(filtered))
-	now := time.Now()
-
-	for _, dep := range filtered {
-		// Use nodeSelector as primary source (works for 0-replica deployments)
-		nodeName := GetDeploymentTargetNode(&dep)
-
-		// Fallback: If no nodeSelector, try pod-based detection
-		if nodeName == "" {
-			nodeName = deploymentNodes[dep.Name]
+		if bestMatch != "" {
+			deploymentNodes[bestMatch] = pod.node
 		}
+	}
 
-		info := DeploymentInfo{
-			Name:            dep.Name,
-			Namespace:       dep.Namespace,
-			ReadyReplicas:   dep.Status.ReadyReplicas,
-			DesiredReplicas: getDeploymentDesiredReplicas(&dep),
-			NodeName:        nodeName,
-			Age:             duration.HumanDuration(now.Sub(dep.CreationTimestamp.Time)),
-			Status:          getDeploymentStatusString(&dep),
-			Type:            extractDeploymentType(dep.Name),
-			OsdID:           extractOsdID(&dep),
+	for i := range result {
+		if result[i].NodeName == "" {
+			result[i].NodeName = deploymentNodes[result[i].Name]
 		}
-		result = append(result, info)
+	}
+	if result == nil {
+		result = []DeploymentInfo{}
 	}
 
 	return result, nil
 }
 
+// podOwner is the slim projection of a pod owned by a ReplicaSet
+type podOwner struct {
+	replicaSet string
+	node       string
+}
+
+// NewDeploymentInfo describes a deployment whose pod runs on nodeName, with
+// its age relative to now
+func NewDeploymentInfo(dep *appsv1.Deployment, nodeName string, now time.Time) DeploymentInfo {
+	return DeploymentInfo{
+		Name:            dep.Name,
+		Namespace:       dep.Namespace,
+		ReadyReplicas:   dep.Status.ReadyReplicas,
+		DesiredReplicas: getDeploymentDesiredReplicas(dep),
+		NodeName:        nodeName,
+		Age:             duration.HumanDuration(now.Sub(dep.CreationTimestamp.Time)),
+		Status:          getDeploymentStatusString(dep),
+		Type:            extractDeploymentType(dep.Name),
+		OsdID:           extractOsdID(dep),
+		Image:           deploymentImage(dep),
+		CephVersion:     dep.Labels[CephVersionLabel],
+	}
+}
+
 // getDeploymentDesiredReplicas returns the desired replicas for a deployment
 func getDeploymentDesiredReplicas(dep *appsv1.Deployment) int32 {
 	if dep.Spec.Replicas != nil {
@@ -378,18 +460,50 @@ func extractOsdID(dep *appsv1.Deployment) string {
 	return ""
 }
 
+// deploymentImage returns the image of the pod template's first container,
+// which is the daemon container in Rook deployments
+func deploymentImage(dep *appsv1.Deployment) string {
+	if containers := dep.Spec.Template.Spec.Containers; len(containers) > 0 {
+		return containers[0].Image
+	}
+	return ""
+}
+
+// How a deployment is pinned to its node, as reported by DeploymentPinSource
+const (
+	PinSourceNodeSelector = "nodeSelector"
+	PinSourceNodeAffinity = "nodeAffinity"
+)
+
 // GetDeploymentTargetNode extracts the target node from a deployment's spec.
 // Returns empty string if deployment is not node-pinned.
 func GetDeploymentTargetNode(dep *appsv1.Deployment) string {
+	node, _ := DeploymentPinSource(dep)
+	return node
+}
+
+// DeploymentPinSource returns the node a deployment is pinned to and the part
+// of its pod spec that pins it: PinSourceNodeSelector or PinSourceNodeAffinity.
+// Both are empty if the deployment is not node-pinned.
+func DeploymentPinSource(dep *appsv1.Deployment) (node, source string) {
+	return podSpecPinSource(&dep.Spec.Template.Spec)
+}
+
+// podSpecPinSource returns the node a pod spec is pinned to and the part of
+// the spec that pins it
+func podSpecPinSource(spec *corev1.PodSpec) (node, source string) {
 	// Primary: Check nodeSelector (used by OSDs, MONs, crashcollector, exporter)
-	if ns := dep.Spec.Template.Spec.NodeSelector; ns != nil {
+	if ns := spec.NodeSelector; ns != nil {
 		if hostname, ok := ns["kubernetes.io/hostname"]; ok {
-			return hostname
+			return hostname, PinSourceNodeSelector
 		}
 	}
 
 	// Fallback: Check nodeAffinity requiredDuringScheduling
-	return getNodeAffinityHostname(dep.Spec.Template.Spec.Affinity)
+	if hostname := getNodeAffinityHostname(spec.Affinity); hostname != "" {
+		return hostname, PinSourceNodeAffinity
+	}
+	return "", ""
 }
 
 // getNodeAffinityHostname extracts the hostname from nodeAffinity requiredDuringScheduling.
@@ -24,11 +24,19 @@ func TestListCephDeployments(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Name:              "rook-ceph-osd-0",
 					Namespace:         "rook-ceph",
-					Labels:            map[string]string{"ceph-osd-id": "0"},
+					Labels:            map[string]string{"ceph-osd-id": "0", CephVersionLabel: "18.2.2-0"},
 					CreationTimestamp: metav1.Time{Time: time.Now().Add(-24 * time.Hour)},
 				},
 				Spec: appsv1.DeploymentSpec{
 					Replicas: &replicas,
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{Name: "osd", Image: "quay.io/ceph/ceph:v18.2.2"},
+								{Name: "log-collector", Image: "quay.io/ceph/ceph:v18.2.2"},
+							},
+						},
+					},
 				},
 				Status: appsv1.DeploymentStatus{
 					ReadyReplicas: 1,
@@ -144,6 +152,12 @@ func TestListCephDeployments(t *testing.T) {
 	if osd.NodeName != "worker-1" {
 		t.Errorf("osd NodeName = %s, want worker-1", osd.NodeName)
 	}
+	if osd.Image != "quay.io/ceph/ceph:v18.2.2" {
+		t.Errorf("osd Image = %s, want quay.io/ceph/ceph:v18.2.2", osd.Image)
+	}
+	if osd.CephVersion != "18.2.2-0" {
+		t.Errorf("osd CephVersion = %s, want 18.2.2-0", osd.CephVersion)
+	}
 
 	// Check MON deployment
 	mon := depMap["rook-ceph-mon-a"]
@@ -469,3 +483,101 @@ func TestGetDeploymentDesiredReplicas(t *testing.T) {
 func int32Ptr(i int32) *int32 {
 	return &i
 }
+
+func TestImageTag(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "quay.io/ceph/ceph:v18.2.2", want: "v18.2.2"},
+		{image: "registry.local:5000/ceph/ceph:v19.2.0", want: "v19.2.0"},
+		{image: "registry.local:5000/ceph/ceph", want: "latest"},
+		{image: "rook/ceph", want: "latest"},
+		{image: "quay.io/ceph/ceph@sha256:0123456789abcdef0123", want: "@0123456789ab"},
+		{image: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if got := ImageTag(tt.image); got != tt.want {
+				t.Errorf("ImageTag(%q) = %q, want %q", tt.image, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeploymentInfo_Version(t *testing.T) {
+	tests := []struct {
+		name string
+		dep  DeploymentInfo
+		want string
+	}{
+		{name: "ceph version label wins", dep: DeploymentInfo{CephVersion: "18.2.2-0", Image: "quay.io/ceph/ceph:v18.2.2"}, want: "18.2.2-0"},
+		{name: "image tag fallback", dep: DeploymentInfo{Image: "rook/ceph:v1.14.0"}, want: "v1.14.0"},
+		{name: "nothing known", dep: DeploymentInfo{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dep.Version(); got != tt.want {
+				t.Errorf("Version() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPredominantCephVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		want     string
+	}{
+		{name: "no versions", versions: []string{"", ""}, want: ""},
+		{name: "majority", versions: []string{"18.2.2-0", "18.2.2-0", "19.2.0-0", ""}, want: "18.2.2-0"},
+		{name: "tie goes to newer", versions: []string{"18.2.2-0", "19.2.0-0"}, want: "19.2.0-0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployments := make([]DeploymentInfo, 0, len(tt.versions))
+			for _, v := range tt.versions {
+				deployments = append(deployments, DeploymentInfo{CephVersion: v})
+			}
+			if got := PredominantCephVersion(deployments); got != tt.want {
+				t.Errorf("PredominantCephVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListCephDeploymentsByPrefix(t *testing.T) {
+	newDeployment := func(name string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"}}
+	}
+	clientset := fake.NewClientset(
+		newDeployment("rook-ceph-osd-0"),
+		newDeployment("rook-ceph-mgr-a"),
+	)
+	client := &Client{Clientset: clientset}
+
+	tests := []struct {
+		name     string
+		prefixes []string
+		want     string
+	}{
+		{name: "default prefixes", want: "rook-ceph-osd-0"},
+		{name: "override", prefixes: []string{"rook-ceph-mgr"}, want: "rook-ceph-mgr-a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.ListCephDeploymentsByPrefix(context.Background(), "rook-ceph", tt.prefixes)
+			if err != nil {
+				t.Fatalf("ListCephDeploymentsByPrefix() error = %v", err)
+			}
+			if len(result) != 1 || result[0].Name != tt.want {
+				t.Errorf("ListCephDeploymentsByPrefix() = %v, want only %s", result, tt.want)
+			}
+		})
+	}
+}
@@ -19,8 +19,14 @@ import (
 // The fake clientset doesn't handle scale subresource by default.
 // deploymentReplicas is a map of "namespace/name" -> replicas that tracks state.
 func addScaleReactors(clientset *fake.Clientset, deploymentReplicas map[string]*int32) {
+	addResourceScaleReactors(clientset, "deployments", deploymentReplicas)
+}
+
+// addResourceScaleReactors adds GetScale and UpdateScale reactors for the
+// scale subresource of the given resource (deployments, statefulsets).
+func addResourceScaleReactors(clientset *fake.Clientset, resource string, deploymentReplicas map[string]*int32) {
 	// Add reactor for GetScale
-	clientset.PrependReactor("get", "deployments/scale", func(action k8stesting.Action) (bool, runtime.Object, error) {
+	clientset.PrependReactor("get", resource+"/scale", func(action k8stesting.Action) (bool, runtime.Object, error) {
 		getAction, ok := action.(k8stesting.GetAction)
 		if !ok {
 			return true, nil, fmt.Errorf("unexpected action type: %T", action)
@@ -28,7 +34,7 @@ func addScaleReactors(clientset *fake.Clientset, deploymentReplicas map[string]*
 		key := getAction.GetNamespace() + "/" + getAction.GetName()
 		replicas, found := deploymentReplicas[key]
 		if !found {
-			return true, nil, fmt.Errorf("deployment %q not found", key)
+			return true, nil, fmt.Errorf("%s %q not found", resource, key)
 		}
 		replicaVal := int32(1)
 		if replicas != nil {
@@ -47,7 +53,7 @@ func addScaleReactors(clientset *fake.Clientset, deploymentReplicas map[string]*
 	})
 
 	// Add reactor for UpdateScale
-	clientset.PrependReactor("update", "deployments/scale", func(action k8stesting.Action) (bool, runtime.Object, error) {
+	clientset.PrependReactor("update", resource+"/scale", func(action k8stesting.Action) (bool, runtime.Object, error) {
 		updateAction, ok := action.(k8stesting.UpdateAction)
 		if !ok {
 			return true, nil, fmt.Errorf("unexpected action type: %T", action)
@@ -58,7 +64,7 @@ func addScaleReactors(clientset *fake.Clientset, deploymentReplicas map[string]*
 		}
 		key := updateAction.GetNamespace() + "/" + scale.Name
 		if _, found := deploymentReplicas[key]; !found {
-			return true, nil, fmt.Errorf("deployment %q not found", key)
+			return true, nil, fmt.Errorf("%s %q not found", resource, key)
 		}
 		deploymentReplicas[key] = &scale.Spec.Replicas
 		return true, scale, nil
@@ -218,6 +224,28 @@ func TestListDeploymentsInNamespace(t *testing.T) {
 	}
 }
 
+func TestListDeploymentsInNamespace_CephCluster(t *testing.T) {
+	deployment := func(name, cluster string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "rook-ceph",
+			Labels:    map[string]string{CephClusterLabel: cluster},
+		}}
+	}
+	clientset := fake.NewClientset(deployment("rook-ceph-osd-0", "east"), deployment("rook-ceph-osd-1", "west"))
+	client := newClientFromClientset(clientset)
+	client.cephCluster = "east"
+
+	deployments, err := client.ListDeploymentsInNamespace(t.Context(), "rook-ceph")
+	if err != nil {
+		t.Fatalf("failed to list deployments: %v", err)
+	}
+
+	if len(deployments) != 1 || deployments[0].Name != "rook-ceph-osd-0" {
+		t.Errorf("expected only rook-ceph-osd-0 of CephCluster east, got %v", deployments)
+	}
+}
+
 func TestFilterDeploymentsByPrefix(t *testing.T) {
 	replicas := int32(1)
 	deployments := []appsv1.Deployment{
@@ -619,6 +647,56 @@ func TestGetDeploymentTargetNode(t *testing.T) {
 	}
 }
 
+func TestDeploymentPinSource(t *testing.T) {
+	withSpec := func(spec corev1.PodSpec) *appsv1.Deployment {
+		return &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: spec}}}
+	}
+	affinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      "kubernetes.io/hostname",
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{"affinity-node"},
+				}},
+			}},
+		},
+	}}
+
+	tests := []struct {
+		name       string
+		dep        *appsv1.Deployment
+		wantNode   string
+		wantSource string
+	}{
+		{
+			name:       "nodeSelector",
+			dep:        withSpec(corev1.PodSpec{NodeSelector: map[string]string{"kubernetes.io/hostname": "selector-node"}}),
+			wantNode:   "selector-node",
+			wantSource: PinSourceNodeSelector,
+		},
+		{
+			name:       "nodeAffinity",
+			dep:        withSpec(corev1.PodSpec{Affinity: affinity}),
+			wantNode:   "affinity-node",
+			wantSource: PinSourceNodeAffinity,
+		},
+		{
+			name: "not pinned",
+			dep:  withSpec(corev1.PodSpec{}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, source := DeploymentPinSource(tt.dep)
+			if node != tt.wantNode || source != tt.wantSource {
+				t.Errorf("DeploymentPinSource() = (%q, %q), want (%q, %q)", node, source, tt.wantNode, tt.wantSource)
+			}
+		})
+	}
+}
+
 func TestListNodePinnedDeployments(t *testing.T) {
 	ctx := context.Background()
 
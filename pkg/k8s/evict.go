@@ -0,0 +1,226 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultEvictionInterval is how often blocked evictions are retried and
+// evicted pods are checked for deletion
+const DefaultEvictionInterval = 2 * time.Second
+
+// mirrorPodAnnotation marks static pods mirrored by the kubelet, which the API
+// server cannot evict
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// EvictionStatus is the state of one pod while a node is drained
+type EvictionStatus string
+
+const (
+	// EvictionEvicting means the eviction was accepted and the pod is terminating
+	EvictionEvicting EvictionStatus = "evicting"
+	// EvictionBlocked means a PodDisruptionBudget refused the eviction; it is retried
+	EvictionBlocked EvictionStatus = "blocked"
+	// EvictionEvicted means the pod is gone
+	EvictionEvicted EvictionStatus = "evicted"
+)
+
+// EvictionProgress reports a change in the eviction state of one pod
+type EvictionProgress struct {
+	// Pod is the pod in "namespace/name" format
+	Pod string
+
+	// Status is the pod's new eviction state
+	Status EvictionStatus
+
+	// Message explains a blocked eviction
+	Message string
+}
+
+// EvictionOptions controls how EvictPods removes pods
+type EvictionOptions struct {
+	// GracePeriodSeconds overrides the pods' termination grace period.
+	// Nil uses each pod's own terminationGracePeriodSeconds.
+	GracePeriodSeconds *int64
+
+	// Interval between retries of blocked evictions and checks for deleted
+	// pods. Zero uses DefaultEvictionInterval.
+	Interval time.Duration
+
+	// OnProgress is called whenever a pod changes eviction state
+	// Optional - if nil, no progress updates are sent
+	OnProgress func(progress EvictionProgress)
+}
+
+// ListPodsToEvict returns the pods a drain of the node evicts, like 'kubectl
+// drain --ignore-daemonsets': pods owned by a DaemonSet, mirror pods and
+// pods that already finished stay.
+func (c *Client) ListPodsToEvict(ctx context.Context, nodeName string) ([]corev1.Pod, error) {
+	podList, err := c.Clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %w", nodeName, err)
+	}
+
+	var pods []corev1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		// Field selectors are not applied by every client, e.g. fakes
+		if pod.Spec.NodeName != nodeName || !evictable(pod) {
+			continue
+		}
+		pods = append(pods, *pod)
+	}
+	return pods, nil
+}
+
+// evictable reports whether a drain should evict the pod
+func evictable(pod *corev1.Pod) bool {
+	if _, mirror := pod.Annotations[mirrorPodAnnotation]; mirror {
+		return false
+	}
+	switch pod.Status.Phase { //nolint:exhaustive // only finished pods are skipped
+	case corev1.PodSucceeded, corev1.PodFailed:
+		return false
+	}
+	if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "DaemonSet" {
+		return false
+	}
+	return true
+}
+
+// EvictPod asks the API server to evict a pod through the policy/v1 Eviction
+// API, which honors PodDisruptionBudgets. A refusal by a budget is returned as
+// a TooManyRequests error.
+func (c *Client) EvictPod(ctx context.Context, pod *corev1.Pod, gracePeriodSeconds *int64) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+		DeleteOptions: &metav1.DeleteOptions{
+			GracePeriodSeconds: gracePeriodSeconds,
+			Preconditions:      &metav1.Preconditions{UID: &pod.UID},
+		},
+	}
+	if err := c.Clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction); err != nil {
+		return fmt.Errorf("failed to evict pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+	return nil
+}
+
+// evictionTarget tracks one pod through EvictPods
+type evictionTarget struct {
+	pod    *corev1.Pod
+	name   string
+	status EvictionStatus
+	// requested is set once the API server accepted the eviction
+	requested bool
+}
+
+// EvictPods evicts the pods and waits until all of them are deleted. Evictions
+// refused by a PodDisruptionBudget are retried until the budget allows them
+// or ctx ends; any other API error aborts the drain.
+func (c *Client) EvictPods(ctx context.Context, pods []corev1.Pod, opts EvictionOptions) error {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultEvictionInterval
+	}
+	report := func(target *evictionTarget, status EvictionStatus, message string) {
+		target.status = status
+		if opts.OnProgress != nil {
+			opts.OnProgress(EvictionProgress{Pod: target.name, Status: status, Message: message})
+		}
+	}
+
+	targets := make([]*evictionTarget, 0, len(pods))
+	for i := range pods {
+		targets = append(targets, &evictionTarget{
+			pod:  &pods[i],
+			name: fmt.Sprintf("%s/%s", pods[i].Namespace, pods[i].Name),
+		})
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		remaining := 0
+		for _, target := range targets {
+			if target.status == EvictionEvicted {
+				continue
+			}
+			if !target.requested {
+				err := c.EvictPod(ctx, target.pod, opts.GracePeriodSeconds)
+				switch {
+				case err == nil:
+					target.requested = true
+					report(target, EvictionEvicting, "")
+				case apierrors.IsNotFound(err), apierrors.IsConflict(err):
+					// Gone already, or replaced by a pod with another UID
+					report(target, EvictionEvicted, "")
+					continue
+				case apierrors.IsTooManyRequests(err):
+					// Report a blocked pod once, not on every retry
+					if target.status != EvictionBlocked {
+						report(target, EvictionBlocked, apiErrorMessage(err))
+					}
+				default:
+					return err
+				}
+			}
+			if target.requested {
+				gone, err := c.podGone(ctx, target.pod.Namespace, target.pod.Name, target.pod.UID)
+				if err != nil {
+					return err
+				}
+				if gone {
+					report(target, EvictionEvicted, "")
+					continue
+				}
+			}
+			remaining++
+		}
+		if remaining == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for %d pod(s) to be evicted: %w", remaining, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// podGone reports whether the pod with the given UID no longer exists. A pod
+// recreated under the same name, e.g. by a StatefulSet, has a new UID.
+func (c *Client) podGone(ctx context.Context, namespace, name string, uid types.UID) (bool, error) {
+	pod, err := c.Clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get pod %s/%s: %w", namespace, name, err)
+	}
+	return pod.UID != uid, nil
+}
+
+// apiErrorMessage returns the server's message for an API error
+func apiErrorMessage(err error) string {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) && status.Status().Message != "" {
+		return status.Status().Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,166 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func evictTestPod(name, node string, mutate func(*corev1.Pod)) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps", UID: types.UID("uid-" + name)},
+		Spec:       corev1.PodSpec{NodeName: node},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if mutate != nil {
+		mutate(pod)
+	}
+	return pod
+}
+
+func TestListPodsToEvict(t *testing.T) {
+	isController := true
+	clientset := fake.NewClientset(
+		evictTestPod("web", "worker-1", nil),
+		evictTestPod("other-node", "worker-2", nil),
+		evictTestPod("agent", "worker-1", func(pod *corev1.Pod) {
+			pod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: &isController}}
+		}),
+		evictTestPod("static", "worker-1", func(pod *corev1.Pod) {
+			pod.Annotations = map[string]string{mirrorPodAnnotation: "hash"}
+		}),
+		evictTestPod("finished", "worker-1", func(pod *corev1.Pod) {
+			pod.Status.Phase = corev1.PodSucceeded
+		}),
+	)
+	client := newClientFromClientset(clientset)
+
+	pods, err := client.ListPodsToEvict(context.Background(), "worker-1")
+	if err != nil {
+		t.Fatalf("ListPodsToEvict() error = %v", err)
+	}
+	if len(pods) != 1 || pods[0].Name != "web" {
+		names := make([]string, 0, len(pods))
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		t.Errorf("ListPodsToEvict() = %v, want [web]", names)
+	}
+}
+
+func TestEvictPods(t *testing.T) {
+	podsResource := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	budgetErr := apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+
+	tests := []struct {
+		name string
+		// refusals returns the error for the nth eviction request of a pod
+		refusals     func(pod string, attempt int) error
+		timeout      time.Duration
+		wantErr      bool
+		wantStatuses []EvictionStatus
+	}{
+		{
+			name:         "evicted",
+			refusals:     func(string, int) error { return nil },
+			wantStatuses: []EvictionStatus{EvictionEvicting, EvictionEvicted},
+		},
+		{
+			name: "blocked by budget then allowed",
+			refusals: func(_ string, attempt int) error {
+				if attempt < 3 {
+					return budgetErr
+				}
+				return nil
+			},
+			wantStatuses: []EvictionStatus{EvictionBlocked, EvictionEvicting, EvictionEvicted},
+		},
+		{
+			name: "already gone",
+			refusals: func(pod string, _ int) error {
+				return apierrors.NewNotFound(podsResource.GroupResource(), pod)
+			},
+			wantStatuses: []EvictionStatus{EvictionEvicted},
+		},
+		{
+			name:         "blocked until timeout",
+			refusals:     func(string, int) error { return budgetErr },
+			timeout:      50 * time.Millisecond,
+			wantErr:      true,
+			wantStatuses: []EvictionStatus{EvictionBlocked},
+		},
+		{
+			name: "api error aborts",
+			refusals: func(string, int) error {
+				return apierrors.NewForbidden(podsResource.GroupResource(), "web", errors.New("denied"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := evictTestPod("web", "worker-1", nil)
+			clientset := fake.NewClientset(pod)
+			attempts := 0
+			clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "eviction" {
+					return false, nil, nil
+				}
+				eviction, ok := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+				if !ok {
+					t.Fatalf("unexpected eviction object %T", action.(k8stesting.CreateAction).GetObject())
+				}
+				attempts++
+				if err := tt.refusals(eviction.Name, attempts); err != nil {
+					return true, nil, err
+				}
+				if err := clientset.Tracker().Delete(podsResource, eviction.Namespace, eviction.Name); err != nil {
+					return true, nil, err
+				}
+				return true, nil, nil
+			})
+			client := newClientFromClientset(clientset)
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			var statuses []EvictionStatus
+			err := client.EvictPods(ctx, []corev1.Pod{*pod}, EvictionOptions{
+				Interval: time.Millisecond,
+				OnProgress: func(progress EvictionProgress) {
+					if progress.Pod != "apps/web" {
+						t.Errorf("progress pod = %q, want apps/web", progress.Pod)
+					}
+					if progress.Status == EvictionBlocked && progress.Message != budgetErr.ErrStatus.Message {
+						t.Errorf("blocked message = %q, want the budget refusal", progress.Message)
+					}
+					statuses = append(statuses, progress.Status)
+				},
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvictPods() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(statuses, tt.wantStatuses) {
+				t.Errorf("statuses = %v, want %v", statuses, tt.wantStatuses)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/andri/crook/pkg/config"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// DeploymentFilter selects deployments by name and labels.
+//
+// Precedence:
+//  1. A deployment matching any exclude rule is never selected.
+//  2. Otherwise it is selected when it matches any include rule.
+//  3. Without include rules, DefaultRookCephPrefixes() are the include rules.
+//
+// A rule matches when every condition set on it matches (prefix AND suffix
+// AND regex AND selector). Regexes match the whole name, like
+// discovery.exclude.
+type DeploymentFilter struct {
+	include []deploymentRule
+	exclude []deploymentRule
+}
+
+// deploymentRule is a compiled config.DeploymentFilterRule
+type deploymentRule struct {
+	prefix   string
+	suffix   string
+	regex    *regexp.Regexp
+	selector labels.Selector
+}
+
+// NewDeploymentFilter compiles the configured filter rules
+func NewDeploymentFilter(cfg config.DeploymentFiltersConfig) (*DeploymentFilter, error) {
+	include := cfg.Include
+	if len(include) == 0 {
+		include = PrefixRules(DefaultRookCephPrefixes())
+	}
+
+	f := &DeploymentFilter{}
+	for i, rule := range include {
+		compiled, err := compileDeploymentRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("include rule %d: %w", i, err)
+		}
+		f.include = append(f.include, compiled)
+	}
+	for i, rule := range cfg.Exclude {
+		compiled, err := compileDeploymentRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("exclude rule %d: %w", i, err)
+		}
+		f.exclude = append(f.exclude, compiled)
+	}
+	return f, nil
+}
+
+// PrefixRules returns one include rule per name prefix
+func PrefixRules(prefixes []string) []config.DeploymentFilterRule {
+	rules := make([]config.DeploymentFilterRule, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		rules = append(rules, config.DeploymentFilterRule{Prefix: prefix})
+	}
+	return rules
+}
+
+// compileDeploymentRule parses a rule's regex and label selector
+func compileDeploymentRule(rule config.DeploymentFilterRule) (deploymentRule, error) {
+	if rule.IsEmpty() {
+		return deploymentRule{}, fmt.Errorf("rule must set prefix, suffix, regex or selector")
+	}
+
+	compiled := deploymentRule{prefix: rule.Prefix, suffix: rule.Suffix}
+	if rule.Regex != "" {
+		re, err := config.CompileNamePattern(rule.Regex)
+		if err != nil {
+			return deploymentRule{}, fmt.Errorf("invalid regex %q: %w", rule.Regex, err)
+		}
+		compiled.regex = re
+	}
+	if rule.Selector != "" {
+		selector, err := labels.Parse(rule.Selector)
+		if err != nil {
+			return deploymentRule{}, fmt.Errorf("invalid selector %q: %w", rule.Selector, err)
+		}
+		compiled.selector = selector
+	}
+	return compiled, nil
+}
+
+// matches reports whether every condition of the rule matches the object
+func (r deploymentRule) matches(obj metav1.Object) bool {
+	name := obj.GetName()
+	if r.prefix != "" && !strings.HasPrefix(name, r.prefix) {
+		return false
+	}
+	if r.suffix != "" && !strings.HasSuffix(name, r.suffix) {
+		return false
+	}
+	if r.regex != nil && !r.regex.MatchString(name) {
+		return false
+	}
+	if r.selector != nil && !r.selector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+	return true
+}
+
+// Matches reports whether the filter selects the deployment
+func (f *DeploymentFilter) Matches(dep *appsv1.Deployment) bool {
+	if f.Excludes(dep) {
+		return false
+	}
+	for _, rule := range f.include {
+		if rule.matches(dep) {
+			return true
+		}
+	}
+	return false
+}
+
+// Excludes reports whether an exclude rule matches the object. Discovery
+// applies it to the deployments and StatefulSets the phases scale.
+func (f *DeploymentFilter) Excludes(obj metav1.Object) bool {
+	for _, rule := range f.exclude {
+		if rule.matches(obj) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the deployments the filter selects, preserving their order
+func (f *DeploymentFilter) Filter(deployments []appsv1.Deployment) []appsv1.Deployment {
+	var filtered []appsv1.Deployment
+	for i := range deployments {
+		if f.Matches(&deployments[i]) {
+			filtered = append(filtered, deployments[i])
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,161 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func filterTestDeployment(name string, labels map[string]string) appsv1.Deployment {
+	return appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestDeploymentFilter_Matches(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters config.DeploymentFiltersConfig
+		dep     appsv1.Deployment
+		want    bool
+	}{
+		{
+			name: "no include rules falls back to default prefixes",
+			dep:  filterTestDeployment("rook-ceph-osd-0", nil),
+			want: true,
+		},
+		{
+			name: "no include rules rejects other names",
+			dep:  filterTestDeployment("nginx", nil),
+			want: false,
+		},
+		{
+			name: "any include rule selects",
+			filters: config.DeploymentFiltersConfig{Include: []config.DeploymentFilterRule{
+				{Prefix: "ceph-"},
+				{Suffix: "-exporter"},
+			}},
+			dep:  filterTestDeployment("node-exporter", nil),
+			want: true,
+		},
+		{
+			name: "configured include rules replace the defaults",
+			filters: config.DeploymentFiltersConfig{Include: []config.DeploymentFilterRule{
+				{Prefix: "ceph-"},
+			}},
+			dep:  filterTestDeployment("rook-ceph-osd-0", nil),
+			want: false,
+		},
+		{
+			name: "regex matches the whole name",
+			filters: config.DeploymentFiltersConfig{Include: []config.DeploymentFilterRule{
+				{Regex: `osd-\d+`},
+			}},
+			dep:  filterTestDeployment("custom-osd-12", nil),
+			want: false,
+		},
+		{
+			name: "regex covering the whole name",
+			filters: config.DeploymentFiltersConfig{Include: []config.DeploymentFilterRule{
+				{Regex: `.*osd-\d+`},
+			}},
+			dep:  filterTestDeployment("custom-osd-12", nil),
+			want: true,
+		},
+		{
+			name: "all conditions of a rule must match",
+			filters: config.DeploymentFiltersConfig{Include: []config.DeploymentFilterRule{
+				{Prefix: "rook-ceph-", Selector: "app=rook-ceph-osd"},
+			}},
+			dep:  filterTestDeployment("rook-ceph-mon-a", map[string]string{"app": "rook-ceph-mon"}),
+			want: false,
+		},
+		{
+			name: "selector matches labels",
+			filters: config.DeploymentFiltersConfig{Include: []config.DeploymentFilterRule{
+				{Selector: "app in (rook-ceph-osd,rook-ceph-mon)"},
+			}},
+			dep:  filterTestDeployment("storage-a", map[string]string{"app": "rook-ceph-mon"}),
+			want: true,
+		},
+		{
+			name: "exclude beats include",
+			filters: config.DeploymentFiltersConfig{
+				Include: []config.DeploymentFilterRule{{Prefix: "rook-ceph-"}},
+				Exclude: []config.DeploymentFilterRule{{Suffix: "-tools"}},
+			},
+			dep:  filterTestDeployment("rook-ceph-tools", nil),
+			want: false,
+		},
+		{
+			name: "exclude applies to the default prefixes",
+			filters: config.DeploymentFiltersConfig{
+				Exclude: []config.DeploymentFilterRule{{Selector: "crook.io/skip"}},
+			},
+			dep:  filterTestDeployment("rook-ceph-rgw-a", map[string]string{"crook.io/skip": "true"}),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewDeploymentFilter(tt.filters)
+			if err != nil {
+				t.Fatalf("NewDeploymentFilter() error = %v", err)
+			}
+			if got := filter.Matches(&tt.dep); got != tt.want {
+				t.Errorf("Matches(%s) = %v, want %v", tt.dep.Name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDeploymentFilter_InvalidRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters config.DeploymentFiltersConfig
+	}{
+		{"empty include rule", config.DeploymentFiltersConfig{Include: []config.DeploymentFilterRule{{}}}},
+		{"empty exclude rule", config.DeploymentFiltersConfig{Exclude: []config.DeploymentFilterRule{{}}}},
+		{"invalid regex", config.DeploymentFiltersConfig{Include: []config.DeploymentFilterRule{{Regex: "osd-("}}}},
+		{"invalid selector", config.DeploymentFiltersConfig{Exclude: []config.DeploymentFilterRule{{Selector: "app in (a"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDeploymentFilter(tt.filters); err == nil {
+				t.Error("NewDeploymentFilter() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestCephDeploymentFilter_PrefixesKeepExcludes(t *testing.T) {
+	client := &Client{deploymentFilters: config.DeploymentFiltersConfig{
+		Include: []config.DeploymentFilterRule{{Prefix: "ceph-"}},
+		Exclude: []config.DeploymentFilterRule{{Regex: ".*-canary"}},
+	}}
+	deployments := []appsv1.Deployment{
+		filterTestDeployment("ceph-osd-0", nil),
+		filterTestDeployment("rook-ceph-osd-0", nil),
+		filterTestDeployment("rook-ceph-osd-canary", nil),
+	}
+
+	got := client.CephDeploymentFilter([]string{"rook-ceph-osd"}).Filter(deployments)
+	if len(got) != 1 || got[0].Name != "rook-ceph-osd-0" {
+		t.Errorf("Filter() = %v, want only rook-ceph-osd-0", deploymentNamesOf(got))
+	}
+
+	got = client.CephDeploymentFilter(nil).Filter(deployments)
+	if len(got) != 1 || got[0].Name != "ceph-osd-0" {
+		t.Errorf("Filter() = %v, want only ceph-osd-0", deploymentNamesOf(got))
+	}
+}
+
+func deploymentNamesOf(deployments []appsv1.Deployment) []string {
+	names := make([]string, 0, len(deployments))
+	for _, dep := range deployments {
+		names = append(names, dep.Name)
+	}
+	return names
+}
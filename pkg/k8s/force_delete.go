@@ -0,0 +1,67 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// ListTerminatingPods returns the pods on the node stuck in Terminating: the
+// API server marked them for deletion, but only the node's kubelet can
+// confirm that their containers stopped, and on a dead node it never does
+func (c *Client) ListTerminatingPods(ctx context.Context, nodeName string) ([]corev1.Pod, error) {
+	pods, err := c.listPods(ctx, metav1.NamespaceAll, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %w", nodeName, err)
+	}
+
+	var terminating []corev1.Pod
+	for _, pod := range pods {
+		// Field selectors are not applied by every client, e.g. fakes
+		if pod.Spec.NodeName == nodeName && pod.DeletionTimestamp != nil {
+			terminating = append(terminating, pod)
+		}
+	}
+	return terminating, nil
+}
+
+// ForceDeletePods deletes the pods with a zero grace period, without waiting
+// for their kubelet to confirm the containers stopped, like 'kubectl delete
+// --force --grace-period=0'. Only use it for pods on a node that is down:
+// if the node is still running them, their replacements run alongside them.
+// onDeleted is called with "namespace/name" after each pod; it may be nil.
+func (c *Client) ForceDeletePods(ctx context.Context, pods []corev1.Pod, onDeleted func(pod string)) error {
+	zero := int64(0)
+	for i := range pods {
+		pod := &pods[i]
+		err := c.Clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{
+			GracePeriodSeconds: &zero,
+			Preconditions:      &metav1.Preconditions{UID: &pod.UID},
+		})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to force-delete pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+
+		c.recordChange(Change{
+			Kind:      "Pod",
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Field:     "metadata",
+			Before:    "terminating",
+			After:     "force-deleted",
+		})
+		if onDeleted != nil {
+			onDeleted(pod.Namespace + "/" + pod.Name)
+		}
+	}
+	return nil
+}
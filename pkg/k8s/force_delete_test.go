@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestForceDeleteTerminatingPods(t *testing.T) {
+	deleted := metav1.Now()
+	pod := func(name, node string, terminating bool) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps", UID: types.UID("uid-" + name)},
+			Spec:       corev1.PodSpec{NodeName: node},
+		}
+		if terminating {
+			p.DeletionTimestamp = &deleted
+		}
+		return p
+	}
+	clientset := fake.NewClientset(
+		pod("stuck", "worker-1", true),
+		pod("running", "worker-1", false),
+		pod("elsewhere", "worker-2", true),
+	)
+	client := newClientFromClientset(clientset)
+	ctx := context.Background()
+
+	pods, err := client.ListTerminatingPods(ctx, "worker-1")
+	if err != nil {
+		t.Fatalf("ListTerminatingPods() error = %v", err)
+	}
+	if len(pods) != 1 || pods[0].Name != "stuck" {
+		t.Fatalf("ListTerminatingPods() = %v, want only apps/stuck", pods)
+	}
+
+	// A pod that is already gone is skipped
+	gone := *pod("gone", "worker-1", true)
+	mark := client.ChangeCount()
+	var reported []string
+	if err := client.ForceDeletePods(ctx, append(pods, gone), func(p string) { reported = append(reported, p) }); err != nil {
+		t.Fatalf("ForceDeletePods() error = %v", err)
+	}
+	if !slices.Equal(reported, []string{"apps/stuck"}) {
+		t.Errorf("onDeleted pods = %v, want [apps/stuck]", reported)
+	}
+
+	remaining, err := clientset.CoreV1().Pods("apps").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var names []string
+	for _, p := range remaining.Items {
+		names = append(names, p.Name)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"elsewhere", "running"}) {
+		t.Errorf("remaining pods = %v, want [elsewhere running]", names)
+	}
+
+	changes := client.ChangesSince(mark)
+	if len(changes) != 1 || changes[0].Object() != "Pod apps/stuck" || changes[0].After != "force-deleted" {
+		t.Errorf("ChangesSince() = %+v, want one force-deleted Pod apps/stuck", changes)
+	}
+}
@@ -0,0 +1,193 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Labels applied to maintenance Jobs
+const (
+	JobLabelName  = "app.kubernetes.io/name"
+	JobLabelNode  = "crook.io/node"
+	JobLabelPhase = "crook.io/phase"
+)
+
+// jobContainerName is the name of the container running crook inside the Job
+const jobContainerName = "crook"
+
+// MaintenanceJobOptions describes a Job that runs a crook phase in the cluster
+type MaintenanceJobOptions struct {
+	// Namespace to create the Job in
+	Namespace string
+
+	// Phase is the crook subcommand to run (down or up)
+	Phase string
+
+	// NodeName is the node under maintenance
+	NodeName string
+
+	// Image is the crook container image
+	Image string
+
+	// ServiceAccount provides the in-cluster credentials for the Job
+	ServiceAccount string
+
+	// Args are extra arguments appended after "<phase> <node>"
+	Args []string
+
+	// ActiveDeadline bounds the Job runtime
+	ActiveDeadline time.Duration
+
+	// TTLAfterFinished controls automatic cleanup of the finished Job
+	TTLAfterFinished time.Duration
+}
+
+// BuildMaintenanceJob returns a Job that runs `crook <phase> <node> --yes` with
+// in-cluster credentials. The pod is kept off the target node so it survives
+// the maintenance it performs.
+func BuildMaintenanceJob(opts MaintenanceJobOptions) *batchv1.Job {
+	backoffLimit := int32(0)
+	ttl := int32(opts.TTLAfterFinished.Seconds())
+	labels := map[string]string{
+		JobLabelName:  "crook",
+		JobLabelNode:  opts.NodeName,
+		JobLabelPhase: opts.Phase,
+	}
+
+	args := append([]string{opts.Phase, opts.NodeName, "--yes", "--in-cluster"}, opts.Args...)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("crook-%s-", opts.Phase),
+			Namespace:    opts.Namespace,
+			Labels:       labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: opts.ServiceAccount,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Affinity:           awayFromNode(opts.NodeName),
+					Containers: []corev1.Container{{
+						Name:  jobContainerName,
+						Image: opts.Image,
+						Args:  args,
+					}},
+				},
+			},
+		},
+	}
+
+	if opts.ActiveDeadline > 0 {
+		deadline := int64(opts.ActiveDeadline.Seconds())
+		job.Spec.ActiveDeadlineSeconds = &deadline
+	}
+
+	return job
+}
+
+// awayFromNode keeps a Job pod off the node under maintenance
+func awayFromNode(nodeName string) *corev1.Affinity {
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      corev1.LabelHostname,
+						Operator: corev1.NodeSelectorOpNotIn,
+						Values:   []string{nodeName},
+					}},
+				}},
+			},
+		},
+	}
+}
+
+// CreateJob creates a Job and returns the created object
+func (c *Client) CreateJob(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error) {
+	created, err := c.Clientset.BatchV1().Jobs(job.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job in namespace %s: %w", job.Namespace, err)
+	}
+	return created, nil
+}
+
+// WaitForJobPod waits until a pod of the Job has started (or already finished)
+// and returns its name.
+func (c *Client) WaitForJobPod(ctx context.Context, namespace, jobName string, pollInterval time.Duration) (string, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	selector := fmt.Sprintf("job-name=%s", jobName)
+	for {
+		pods, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return "", fmt.Errorf("failed to list pods for job %s/%s: %w", namespace, jobName, err)
+		}
+		for i := range pods.Items {
+			switch pods.Items[i].Status.Phase { //nolint:exhaustive // only started pods are of interest
+			case corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed:
+				return pods.Items[i].Name, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("waiting for pod of job %s/%s: %w", namespace, jobName, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// StreamPodLogs follows the logs of a pod container and copies them to w until
+// the container exits or ctx is cancelled.
+func (c *Client) StreamPodLogs(ctx context.Context, namespace, podName string, w io.Writer) error {
+	req := c.Clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container: jobContainerName,
+		Follow:    true,
+	})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to stream logs of pod %s/%s: %w", namespace, podName, err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	if _, err := io.Copy(w, stream); err != nil {
+		return fmt.Errorf("failed reading logs of pod %s/%s: %w", namespace, podName, err)
+	}
+	return nil
+}
+
+// WaitForPodExitCode waits for the crook container of a pod to terminate and
+// returns its exit code.
+func (c *Client) WaitForPodExitCode(ctx context.Context, namespace, podName string, pollInterval time.Duration) (int32, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		pod, err := c.Clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get pod %s/%s: %w", namespace, podName, err)
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == jobContainerName && status.State.Terminated != nil {
+				return status.State.Terminated.ExitCode, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("waiting for pod %s/%s to finish: %w", namespace, podName, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
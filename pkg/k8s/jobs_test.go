@@ -0,0 +1,125 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuildMaintenanceJob(t *testing.T) {
+	job := BuildMaintenanceJob(MaintenanceJobOptions{
+		Namespace:        "rook-ceph",
+		Phase:            "down",
+		NodeName:         "worker-1",
+		Image:            "example.com/crook:v1",
+		ServiceAccount:   "crook",
+		Args:             []string{"--timeout", "10m0s"},
+		ActiveDeadline:   11 * time.Minute,
+		TTLAfterFinished: time.Hour,
+	})
+
+	if job.Namespace != "rook-ceph" || job.GenerateName != "crook-down-" {
+		t.Errorf("unexpected job metadata: %s/%s", job.Namespace, job.GenerateName)
+	}
+	if job.Labels[JobLabelNode] != "worker-1" || job.Labels[JobLabelPhase] != "down" {
+		t.Errorf("unexpected labels: %v", job.Labels)
+	}
+	if *job.Spec.BackoffLimit != 0 {
+		t.Errorf("BackoffLimit = %d, want 0", *job.Spec.BackoffLimit)
+	}
+	if *job.Spec.ActiveDeadlineSeconds != 660 {
+		t.Errorf("ActiveDeadlineSeconds = %d, want 660", *job.Spec.ActiveDeadlineSeconds)
+	}
+	if *job.Spec.TTLSecondsAfterFinished != 3600 {
+		t.Errorf("TTLSecondsAfterFinished = %d, want 3600", *job.Spec.TTLSecondsAfterFinished)
+	}
+
+	podSpec := job.Spec.Template.Spec
+	if podSpec.ServiceAccountName != "crook" {
+		t.Errorf("ServiceAccountName = %q, want crook", podSpec.ServiceAccountName)
+	}
+	if podSpec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("RestartPolicy = %q, want Never", podSpec.RestartPolicy)
+	}
+
+	wantArgs := []string{"down", "worker-1", "--yes", "--in-cluster", "--timeout", "10m0s"}
+	gotArgs := podSpec.Containers[0].Args
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("Args = %v, want %v", gotArgs, wantArgs)
+	}
+	for i := range wantArgs {
+		if gotArgs[i] != wantArgs[i] {
+			t.Errorf("Args[%d] = %q, want %q", i, gotArgs[i], wantArgs[i])
+		}
+	}
+
+	// The job pod must not be scheduled on the node under maintenance
+	expr := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0]
+	if expr.Key != corev1.LabelHostname || expr.Operator != corev1.NodeSelectorOpNotIn || expr.Values[0] != "worker-1" {
+		t.Errorf("unexpected node affinity: %+v", expr)
+	}
+}
+
+func TestCreateJobAndWaitForPod(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crook-down-abc-xyz",
+			Namespace: "rook-ceph",
+			Labels:    map[string]string{"job-name": "crook-down-abc"},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  jobContainerName,
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 3}},
+			}},
+		},
+	}
+	client := newClientFromClientset(fake.NewClientset(pod))
+	ctx := context.Background()
+
+	job := BuildMaintenanceJob(MaintenanceJobOptions{Namespace: "rook-ceph", Phase: "down", NodeName: "worker-1", Image: "crook"})
+	job.Name = "crook-down-abc"
+	if _, err := client.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	podName, err := client.WaitForJobPod(ctx, "rook-ceph", "crook-down-abc", time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForJobPod() error = %v", err)
+	}
+	if podName != pod.Name {
+		t.Errorf("WaitForJobPod() = %q, want %q", podName, pod.Name)
+	}
+
+	var logs bytes.Buffer
+	if logErr := client.StreamPodLogs(ctx, "rook-ceph", podName, &logs); logErr != nil {
+		t.Fatalf("StreamPodLogs() error = %v", logErr)
+	}
+	if logs.Len() == 0 {
+		t.Error("expected streamed logs")
+	}
+
+	code, err := client.WaitForPodExitCode(ctx, "rook-ceph", podName, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForPodExitCode() error = %v", err)
+	}
+	if code != 3 {
+		t.Errorf("WaitForPodExitCode() = %d, want 3", code)
+	}
+}
+
+func TestWaitForJobPod_ContextCancelled(t *testing.T) {
+	client := newClientFromClientset(fake.NewClientset())
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.WaitForJobPod(ctx, "rook-ceph", "missing", time.Millisecond); err == nil {
+		t.Error("expected error when no pod appears before the context is done")
+	}
+}
@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// KubeconfigContexts returns the sorted context names of the kubeconfig
+// client-go would load (KUBECONFIG or ~/.kube/config) and its current context
+func KubeconfigContexts() ([]string, string, error) {
+	raw, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	names := make([]string, 0, len(raw.Contexts))
+	for name := range raw.Contexts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, raw.CurrentContext, nil
+}
+
+// contextCachePath returns the file remembering the context picked in each
+// working directory
+var contextCachePath = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "crook", "contexts.json"), nil
+}
+
+// loadContextCache reads the remembered contexts, keyed by directory
+func loadContextCache() (map[string]string, error) {
+	path, err := contextCachePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	contexts := map[string]string{}
+	if err := json.Unmarshal(data, &contexts); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return contexts, nil
+}
+
+// LastContext returns the context last picked in the directory, or ""
+func LastContext(dir string) string {
+	contexts, err := loadContextCache()
+	if err != nil {
+		return ""
+	}
+	return contexts[dir]
+}
+
+// RememberContext records the context picked in the directory
+func RememberContext(dir, name string) error {
+	contexts, err := loadContextCache()
+	if err != nil {
+		// A corrupt cache is replaced rather than blocking the pick
+		contexts = map[string]string{}
+	}
+	contexts[dir] = name
+
+	path, err := contextCachePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(contexts, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to remember context: %w", err)
+	}
+	return nil
+}
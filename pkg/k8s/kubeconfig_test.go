@@ -0,0 +1,71 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: c
+  cluster: {server: https://127.0.0.1:6443}
+users:
+- name: u
+  user: {token: t}
+contexts:
+- name: staging
+  context: {cluster: c, user: u}
+- name: production
+  context: {cluster: c, user: u}
+`
+
+func TestKubeconfigContexts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", path)
+
+	contexts, current, err := KubeconfigContexts()
+	if err != nil {
+		t.Fatalf("KubeconfigContexts() error = %v", err)
+	}
+	if !slices.Equal(contexts, []string{"production", "staging"}) || current != "staging" {
+		t.Errorf("KubeconfigContexts() = %v, %q, want sorted contexts and staging", contexts, current)
+	}
+}
+
+func TestRememberContext(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "crook", "contexts.json")
+	original := contextCachePath
+	contextCachePath = func() (string, error) { return cachePath, nil }
+	t.Cleanup(func() { contextCachePath = original })
+
+	if got := LastContext("/srv/prod"); got != "" {
+		t.Errorf("LastContext() = %q before anything was remembered", got)
+	}
+	if err := RememberContext("/srv/prod", "production"); err != nil {
+		t.Fatalf("RememberContext() error = %v", err)
+	}
+	if err := RememberContext("/srv/staging", "staging"); err != nil {
+		t.Fatalf("RememberContext() error = %v", err)
+	}
+	if got := LastContext("/srv/prod"); got != "production" {
+		t.Errorf("LastContext(/srv/prod) = %q, want production", got)
+	}
+
+	// A corrupt cache is replaced on the next pick
+	if err := os.WriteFile(cachePath, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := LastContext("/srv/prod"); got != "" {
+		t.Errorf("LastContext() = %q from a corrupt cache", got)
+	}
+	if err := RememberContext("/srv/prod", "production"); err != nil || LastContext("/srv/prod") != "production" {
+		t.Errorf("RememberContext() should replace a corrupt cache: %v", err)
+	}
+}
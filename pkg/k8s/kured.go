@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotations of kured (https://kured.dev), the reboot daemon for OS updates
+const (
+	// KuredLockAnnotation on the kured DaemonSet holds the reboot lock
+	KuredLockAnnotation = "weave.works/kured-node-lock"
+
+	// KuredRebootInProgressAnnotation is set on a node kured is rebooting
+	// (kured --annotate-nodes)
+	KuredRebootInProgressAnnotation = "weave.works/kured-reboot-in-progress"
+
+	// KuredRebootNeededAnnotation is set on a node whose reboot sentinel
+	// kured found (kured --annotate-nodes)
+	KuredRebootNeededAnnotation = "weave.works/kured-most-recent-reboot-needed"
+)
+
+// kuredLock is the value of the kured lock annotation: a single holder, or
+// a list of them when kured allows concurrent reboots
+type kuredLock struct {
+	NodeID string `json:"nodeID"`
+	Locks  []struct {
+		NodeID string `json:"nodeID"`
+	} `json:"locks"`
+}
+
+// GetKuredLockHolders returns the nodes holding the reboot lock of the kured
+// DaemonSet, none when the lock is free
+func (c *Client) GetKuredLockHolders(ctx context.Context, namespace, name string) ([]string, error) {
+	ds, err := c.Clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daemonset %s/%s: %w", namespace, name, err)
+	}
+	value := ds.Annotations[KuredLockAnnotation]
+	if value == "" {
+		return nil, nil
+	}
+	holders, err := parseKuredLock(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s of daemonset %s/%s: %w", KuredLockAnnotation, namespace, name, err)
+	}
+	return holders, nil
+}
+
+// parseKuredLock returns the lock holders of a kured lock annotation
+func parseKuredLock(value string) ([]string, error) {
+	var lock kuredLock
+	if err := json.Unmarshal([]byte(value), &lock); err != nil {
+		return nil, err
+	}
+
+	var holders []string
+	if lock.NodeID != "" {
+		holders = append(holders, lock.NodeID)
+	}
+	for _, l := range lock.Locks {
+		if l.NodeID != "" {
+			holders = append(holders, l.NodeID)
+		}
+	}
+	return holders, nil
+}
@@ -0,0 +1,58 @@
+package k8s
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetKuredLockHolders(t *testing.T) {
+	tests := []struct {
+		name    string
+		lock    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "free"},
+		{
+			name: "single holder",
+			lock: `{"nodeID":"worker-1","metadata":{"unschedulable":false},"created":"2026-01-02T03:04:05Z","TTL":0}`,
+			want: []string{"worker-1"},
+		},
+		{
+			name: "concurrent reboots",
+			lock: `{"maxOwners":2,"locks":[{"nodeID":"worker-1"},{"nodeID":"worker-2"}]}`,
+			want: []string{"worker-1", "worker-2"},
+		},
+		{name: "not json", lock: "worker-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "kured", Namespace: "kube-system"}}
+			if tt.lock != "" {
+				ds.Annotations = map[string]string{KuredLockAnnotation: tt.lock}
+			}
+			client := newClientFromClientset(fake.NewClientset(ds))
+
+			got, err := client.GetKuredLockHolders(context.Background(), "kube-system", "kured")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetKuredLockHolders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GetKuredLockHolders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("not installed", func(t *testing.T) {
+		client := newClientFromClientset(fake.NewClientset())
+		if _, err := client.GetKuredLockHolders(context.Background(), "kube-system", "kured"); err == nil {
+			t.Error("GetKuredLockHolders() should fail without the daemonset")
+		}
+	})
+}
@@ -2,7 +2,9 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,6 +20,9 @@ type NodeStatus struct {
 	Unschedulable bool
 	Ready         bool
 	Conditions    []corev1.NodeCondition
+
+	// BootID changes each time the node's host boots
+	BootID string
 }
 
 // CordonNode marks a node as unschedulable
@@ -78,6 +83,24 @@ func (c *Client) UncordonNode(ctx context.Context, nodeName string) error {
 	return nil
 }
 
+// SetNodeAnnotations sets annotations on a node with a merge patch; a nil
+// value removes the annotation
+func (c *Client) SetNodeAnnotations(ctx context.Context, nodeName string, annotations map[string]*string) error {
+	patch := map[string]any{
+		"metadata": map[string]any{"annotations": annotations},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to encode annotation patch: %w", err)
+	}
+
+	_, err = c.Clientset.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to annotate node %s: %w", nodeName, err)
+	}
+	return nil
+}
+
 // GetNodeStatus returns the status of a node
 func (c *Client) GetNodeStatus(ctx context.Context, nodeName string) (*NodeStatus, error) {
 	node, err := c.Clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
@@ -90,6 +113,7 @@ func (c *Client) GetNodeStatus(ctx context.Context, nodeName string) (*NodeStatu
 		Unschedulable: node.Spec.Unschedulable,
 		Ready:         false,
 		Conditions:    node.Status.Conditions,
+		BootID:        node.Status.NodeInfo.BootID,
 	}
 
 	// Determine if node is ready
@@ -114,7 +138,12 @@ func (c *Client) GetNode(ctx context.Context, nodeName string) (*corev1.Node, er
 
 // ListNodes returns all nodes in the cluster
 func (c *Client) ListNodes(ctx context.Context) ([]corev1.Node, error) {
-	nodeList, err := c.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	return c.ListNodesMatching(ctx, ListSelector{})
+}
+
+// ListNodesMatching returns the nodes matching the selector
+func (c *Client) ListNodesMatching(ctx context.Context, selector ListSelector) ([]corev1.Node, error) {
+	nodeList, err := c.Clientset.CoreV1().Nodes().List(ctx, selector.apply(metav1.ListOptions{}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list nodes: %w", err)
 	}
@@ -149,56 +178,161 @@ type NodeInfo struct {
 
 	// KubeletVersion is the kubelet version
 	KubeletVersion string `json:"kubelet_version"`
+
+	// OSImage is the operating system the node reports, e.g. "Ubuntu 24.04.1 LTS"
+	OSImage string `json:"os_image"`
+
+	// KernelVersion is the running kernel, to verify a node booted a new one
+	KernelVersion string `json:"kernel_version"`
+
+	// ContainerRuntime is the container runtime and its version
+	ContainerRuntime string `json:"container_runtime"`
+
+	// Uptime is the human-readable time since the node last became Ready.
+	// The API does not report the boot time, so it approximates the host's
+	// uptime; it also restarts with the kubelet.
+	Uptime string `json:"uptime,omitempty"`
+
+	// CephRoles are the Ceph daemon types running on the node (osd, mon, ...)
+	CephRoles []string `json:"ceph_roles,omitempty"`
+
+	// MaintenanceAnnotations are the node's annotations that record maintenance
+	MaintenanceAnnotations map[string]string `json:"maintenance_annotations,omitempty"`
+}
+
+// cephDaemonRoles are the pod types reported as a node's Ceph roles, in
+// display order
+var cephDaemonRoles = []string{"mon", "mgr", "osd", "mds", "rgw"}
+
+// MaintenanceAnnotationPrefix is the prefix of annotations crook owns
+const MaintenanceAnnotationPrefix = "crook.io/"
+
+// Node annotations recording why a node is in maintenance (crook down
+// --reason/--ticket); removed by the up phase
+const (
+	ReasonAnnotation = MaintenanceAnnotationPrefix + "reason"
+	TicketAnnotation = MaintenanceAnnotationPrefix + "ticket"
+)
+
+// ScaleDownDisabledAnnotation keeps the cluster autoscaler from removing a
+// node. ScaleDownDisabledByCrookAnnotation records that crook set it, so the
+// up phase only removes an annotation crook owns.
+const (
+	ScaleDownDisabledAnnotation        = "cluster-autoscaler.kubernetes.io/scale-down-disabled"
+	ScaleDownDisabledByCrookAnnotation = MaintenanceAnnotationPrefix + "scale-down-disabled"
+)
+
+// IsMaintenanceAnnotation reports whether a node annotation records
+// maintenance: crook's own annotations and any whose key mentions maintenance,
+// as set by other tooling.
+func IsMaintenanceAnnotation(key string) bool {
+	return strings.HasPrefix(key, MaintenanceAnnotationPrefix) ||
+		strings.Contains(strings.ToLower(key), "maintenance")
+}
+
+// maintenanceAnnotations returns the node's maintenance annotations, nil when
+// there are none
+func maintenanceAnnotations(node *corev1.Node) map[string]string {
+	var annotations map[string]string
+	for key, value := range node.Annotations {
+		if !IsMaintenanceAnnotation(key) {
+			continue
+		}
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[key] = value
+	}
+	return annotations
 }
 
 // ListNodesWithCephPods returns all nodes with Ceph pod counts.
 // Uses DefaultRookCephPrefixes() to filter pods.
 func (c *Client) ListNodesWithCephPods(ctx context.Context, namespace string) ([]NodeInfo, error) {
-	prefixes := DefaultRookCephPrefixes()
+	return c.ListNodesWithCephPodsMatching(ctx, namespace, ListSelector{})
+}
 
-	// Get all nodes
-	nodes, err := c.ListNodes(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list nodes: %w", err)
-	}
+// ListNodesWithCephPodsMatching returns the nodes matching the selector with
+// their Ceph pod counts. The selector does not apply to the pods counted.
+func (c *Client) ListNodesWithCephPodsMatching(ctx context.Context, namespace string, selector ListSelector) ([]NodeInfo, error) {
+	prefixes := DefaultRookCephPrefixes()
 
-	// Get all pods in the namespace to count per-node Ceph pods
-	podList, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	// Get the Ceph pods in the namespace to count per-node Ceph pods; only
+	// their node and daemon type are kept
+	pods, err := projectPods(ctx, c, namespace, c.cephClusterListOptions(), func(pod *corev1.Pod) (cephPodPlacement, bool) {
+		if !matchesAnyPrefix(pod.Name, prefixes) {
+			return cephPodPlacement{}, false
+		}
+		return cephPodPlacement{node: pod.Spec.NodeName, podType: extractPodType(pod.Name)}, true
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
 	}
 
-	// Build a map of node -> Ceph pod count
+	// Build a map of node -> Ceph pod count and daemon types
 	nodePodCounts := make(map[string]int)
-	for _, pod := range podList.Items {
-		// Check if pod matches any of the prefixes
-		if matchesAnyPrefix(pod.Name, prefixes) {
-			nodePodCounts[pod.Spec.NodeName]++
+	nodePodTypes := make(map[string]map[string]bool)
+	for _, pod := range pods {
+		nodePodCounts[pod.node]++
+		if nodePodTypes[pod.node] == nil {
+			nodePodTypes[pod.node] = make(map[string]bool)
 		}
+		nodePodTypes[pod.node][pod.podType] = true
 	}
 
-	// Build result
-	result := make([]NodeInfo, 0, len(nodes))
+	// Describe the selected nodes as each page arrives
 	now := time.Now()
-
-	for _, node := range nodes {
+	result, err := projectNodes(ctx, c, selector.apply(metav1.ListOptions{}), func(node *corev1.Node) (NodeInfo, bool) {
 		info := NodeInfo{
-			Name:           node.Name,
-			IP:             extractNodeIP(&node),
-			Status:         getNodeStatus(&node),
-			Roles:          extractNodeRoles(&node),
-			Schedulable:    !node.Spec.Unschedulable,
-			Cordoned:       node.Spec.Unschedulable,
-			CephPodCount:   nodePodCounts[node.Name],
-			Age:            duration.HumanDuration(now.Sub(node.CreationTimestamp.Time)),
-			KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+			Name:                   node.Name,
+			IP:                     extractNodeIP(node),
+			Status:                 getNodeStatus(node),
+			Roles:                  extractNodeRoles(node),
+			Schedulable:            !node.Spec.Unschedulable,
+			Cordoned:               node.Spec.Unschedulable,
+			CephPodCount:           nodePodCounts[node.Name],
+			Age:                    duration.HumanDuration(now.Sub(node.CreationTimestamp.Time)),
+			KubeletVersion:         node.Status.NodeInfo.KubeletVersion,
+			OSImage:                node.Status.NodeInfo.OSImage,
+			KernelVersion:          node.Status.NodeInfo.KernelVersion,
+			ContainerRuntime:       node.Status.NodeInfo.ContainerRuntimeVersion,
+			Uptime:                 nodeUptime(node, now),
+			MaintenanceAnnotations: maintenanceAnnotations(node),
+		}
+		for _, role := range cephDaemonRoles {
+			if nodePodTypes[node.Name][role] {
+				info.CephRoles = append(info.CephRoles, role)
+			}
 		}
-		result = append(result, info)
+		return info, true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	if result == nil {
+		result = []NodeInfo{}
 	}
 
 	return result, nil
 }
 
+// cephPodPlacement is the slim projection of a Ceph pod counted per node
+type cephPodPlacement struct {
+	node    string
+	podType string
+}
+
+// nodeUptime returns the time since the node's Ready condition turned True,
+// empty when it is not Ready
+func nodeUptime(node *corev1.Node, now time.Time) string {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue && !condition.LastTransitionTime.IsZero() {
+			return duration.HumanDuration(now.Sub(condition.LastTransitionTime.Time))
+		}
+	}
+	return ""
+}
+
 // getNodeStatus extracts the status string from a node
 func getNodeStatus(node *corev1.Node) string {
 	for _, condition := range node.Status.Conditions {
@@ -212,6 +346,23 @@ func getNodeStatus(node *corev1.Node) string {
 	return "Unknown"
 }
 
+// controlPlaneRoleLabels mark control-plane nodes; "master" is the label
+// of clusters set up before Kubernetes 1.20
+var controlPlaneRoleLabels = []string{
+	"node-role.kubernetes.io/control-plane",
+	"node-role.kubernetes.io/master",
+}
+
+// IsControlPlaneNode reports whether the node carries a control-plane role label
+func IsControlPlaneNode(node *corev1.Node) bool {
+	for _, label := range controlPlaneRoleLabels {
+		if _, ok := node.Labels[label]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // extractNodeRoles extracts roles from node labels
 func extractNodeRoles(node *corev1.Node) []string {
 	var roles []string
@@ -259,3 +410,67 @@ func (c *Client) NodeExists(ctx context.Context, nodeName string) (bool, error)
 	}
 	return true, nil
 }
+
+// MatchNodeNames returns the names a partial node name refers to, trying
+// ever looser matches and stopping at the first that finds any: the exact
+// name, the name's first DNS label (worker-3 for worker-3.prod.example.com),
+// a prefix and finally a substring. Matching ignores case.
+func MatchNodeNames(names []string, query string) []string {
+	query = strings.ToLower(query)
+	tiers := []func(name string) bool{
+		func(name string) bool { return name == query },
+		func(name string) bool { return shortNodeName(name) == query },
+		func(name string) bool { return strings.HasPrefix(name, query) },
+		func(name string) bool { return strings.Contains(name, query) },
+	}
+	for _, match := range tiers {
+		var matches []string
+		for _, name := range names {
+			if match(strings.ToLower(name)) {
+				matches = append(matches, name)
+			}
+		}
+		if len(matches) > 0 {
+			sort.Strings(matches)
+			return matches
+		}
+	}
+	return nil
+}
+
+// NodeNameMatchesExactly reports whether query is the node's name or its
+// first DNS label, ignoring case: the matches of MatchNodeNames that are safe
+// to act on without asking
+func NodeNameMatchesExactly(name, query string) bool {
+	name, query = strings.ToLower(name), strings.ToLower(query)
+	return name == query || shortNodeName(name) == query
+}
+
+// shortNodeName returns the name's first DNS label
+func shortNodeName(name string) string {
+	return strings.SplitN(name, ".", 2)[0]
+}
+
+// ResolveNodeName returns the nodes a name given on the command line may
+// refer to. A node with exactly that name is returned without listing the
+// others; otherwise the candidates come from MatchNodeNames, and none means
+// no node matches.
+func (c *Client) ResolveNodeName(ctx context.Context, query string) ([]string, error) {
+	exists, err := c.NodeExists(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return []string{query}, nil
+	}
+
+	nodes, err := c.ListNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(nodes))
+	for i := range nodes {
+		names[i] = nodes[i].Name
+	}
+	return MatchNodeNames(names, query), nil
+}
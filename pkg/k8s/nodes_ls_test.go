@@ -29,10 +29,13 @@ func TestListNodesWithCephPods(t *testing.T) {
 				},
 				Status: corev1.NodeStatus{
 					Conditions: []corev1.NodeCondition{
-						{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+						{Type: corev1.NodeReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.Time{Time: time.Now().Add(-3 * time.Hour)}},
 					},
 					NodeInfo: corev1.NodeSystemInfo{
-						KubeletVersion: "v1.28.0",
+						KubeletVersion:          "v1.28.0",
+						OSImage:                 "Ubuntu 24.04.1 LTS",
+						KernelVersion:           "6.8.0-51-generic",
+						ContainerRuntimeVersion: "containerd://1.7.24",
 					},
 				},
 			},
@@ -42,6 +45,11 @@ func TestListNodesWithCephPods(t *testing.T) {
 					Labels: map[string]string{
 						"node-role.kubernetes.io/worker": "",
 					},
+					Annotations: map[string]string{
+						"example.com/maintenance-window": "sat 02:00",
+						"crook.io/reason":                "disk swap",
+						"node.alpha.kubernetes.io/ttl":   "0",
+					},
 					CreationTimestamp: metav1.Time{Time: time.Now().Add(-48 * time.Hour)},
 				},
 				Spec: corev1.NodeSpec{
@@ -153,6 +161,18 @@ func TestListNodesWithCephPods(t *testing.T) {
 	if w1.Cordoned {
 		t.Errorf("worker-1 Cordoned = true, want false")
 	}
+	if len(w1.CephRoles) != 1 || w1.CephRoles[0] != "osd" {
+		t.Errorf("worker-1 CephRoles = %v, want [osd]", w1.CephRoles)
+	}
+	if w1.OSImage != "Ubuntu 24.04.1 LTS" || w1.KernelVersion != "6.8.0-51-generic" || w1.ContainerRuntime != "containerd://1.7.24" {
+		t.Errorf("worker-1 system info = %q, %q, %q, want the node's OS, kernel and runtime", w1.OSImage, w1.KernelVersion, w1.ContainerRuntime)
+	}
+	if w1.Uptime != "3h" {
+		t.Errorf("worker-1 Uptime = %q, want 3h", w1.Uptime)
+	}
+	if w1.MaintenanceAnnotations != nil {
+		t.Errorf("worker-1 MaintenanceAnnotations = %v, want none", w1.MaintenanceAnnotations)
+	}
 
 	// Check worker-2
 	w2 := nodeMap["worker-2"]
@@ -162,6 +182,12 @@ func TestListNodesWithCephPods(t *testing.T) {
 	if !w2.Cordoned {
 		t.Errorf("worker-2 Cordoned = false, want true")
 	}
+	if len(w2.CephRoles) != 1 || w2.CephRoles[0] != "mon" {
+		t.Errorf("worker-2 CephRoles = %v, want [mon]", w2.CephRoles)
+	}
+	if len(w2.MaintenanceAnnotations) != 2 || w2.MaintenanceAnnotations["crook.io/reason"] != "disk swap" {
+		t.Errorf("worker-2 MaintenanceAnnotations = %v, want crook.io/reason and the maintenance window", w2.MaintenanceAnnotations)
+	}
 
 	// Check control-plane-1
 	cp1 := nodeMap["control-plane-1"]
@@ -171,6 +197,9 @@ func TestListNodesWithCephPods(t *testing.T) {
 	if cp1.Status != "NotReady" {
 		t.Errorf("control-plane-1 Status = %s, want NotReady", cp1.Status)
 	}
+	if cp1.Uptime != "" {
+		t.Errorf("control-plane-1 Uptime = %q, want none while NotReady", cp1.Uptime)
+	}
 }
 
 func TestGetNodeStatusString(t *testing.T) {
@@ -288,3 +288,111 @@ func TestListNodes(t *testing.T) {
 		t.Errorf("expected node-1 and node-2, got %v", names)
 	}
 }
+
+func TestIsControlPlaneNode(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   bool
+	}{
+		{name: "control-plane", labels: map[string]string{"node-role.kubernetes.io/control-plane": ""}, want: true},
+		{name: "legacy master", labels: map[string]string{"node-role.kubernetes.io/master": "true"}, want: true},
+		{name: "worker", labels: map[string]string{"node-role.kubernetes.io/worker": ""}},
+		{name: "no labels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n", Labels: tt.labels}}
+			if got := IsControlPlaneNode(node); got != tt.want {
+				t.Errorf("IsControlPlaneNode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchNodeNames(t *testing.T) {
+	names := []string{
+		"worker-3.prod.example.com",
+		"worker-30.prod.example.com",
+		"worker-3.staging.example.com",
+		"storage-1",
+		"Storage-10",
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "exact", query: "storage-1", want: []string{"storage-1"}},
+		{name: "exact ignores case", query: "STORAGE-10", want: []string{"Storage-10"}},
+		{name: "first label beats prefix", query: "worker-30", want: []string{"worker-30.prod.example.com"}},
+		{name: "first label ambiguous", query: "worker-3", want: []string{"worker-3.prod.example.com", "worker-3.staging.example.com"}},
+		{name: "prefix", query: "worker-3.st", want: []string{"worker-3.staging.example.com"}},
+		{name: "substring", query: "staging", want: []string{"worker-3.staging.example.com"}},
+		{name: "no match", query: "worker-4", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchNodeNames(names, tt.query)
+			if len(got) != len(tt.want) {
+				t.Fatalf("MatchNodeNames(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("MatchNodeNames(%q) = %v, want %v", tt.query, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNodeNameMatchesExactly(t *testing.T) {
+	tests := []struct {
+		name  string
+		node  string
+		query string
+		want  bool
+	}{
+		{name: "exact", node: "worker-3", query: "worker-3", want: true},
+		{name: "exact ignores case", node: "Worker-3", query: "worker-3", want: true},
+		{name: "first label", node: "worker-3.prod.example.com", query: "worker-3", want: true},
+		{name: "prefix", node: "worker-30", query: "worker-3", want: false},
+		{name: "substring", node: "worker-3", query: "3", want: false},
+		{name: "partial domain", node: "worker-3.prod.example.com", query: "worker-3.prod", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NodeNameMatchesExactly(tt.node, tt.query); got != tt.want {
+				t.Errorf("NodeNameMatchesExactly(%q, %q) = %v, want %v", tt.node, tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveNodeName(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-3"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-3.prod.example.com"}},
+	)
+	client := newClientFromInterface(clientset)
+
+	got, err := client.ResolveNodeName(ctx, "worker-3")
+	if err != nil {
+		t.Fatalf("ResolveNodeName() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != "worker-3" {
+		t.Errorf("ResolveNodeName(worker-3) = %v, want the exact node only", got)
+	}
+
+	got, err = client.ResolveNodeName(ctx, "prod")
+	if err != nil {
+		t.Fatalf("ResolveNodeName() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != "worker-3.prod.example.com" {
+		t.Errorf("ResolveNodeName(prod) = %v, want worker-3.prod.example.com", got)
+	}
+}
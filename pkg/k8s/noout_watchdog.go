@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NoOutWatchdogLabelValue is the app.kubernetes.io/name of noout watchdog Jobs
+const NoOutWatchdogLabelValue = "crook-noout-watchdog"
+
+// noOutWatchdogBackoffLimit retries a watchdog pod that failed to clear the
+// flag; retries do not restart the timer since the expiry is absolute
+const noOutWatchdogBackoffLimit = 3
+
+// noOutWatchdogGrace bounds how long a watchdog may keep retrying after expiry
+const noOutWatchdogGrace = time.Hour
+
+// noOutWatchdogKeep keeps a finished watchdog Job for inspection
+const noOutWatchdogKeep = 24 * time.Hour
+
+// NoOutWatchdogOptions describes a Job that clears the noout flag a down
+// phase set once it expires
+type NoOutWatchdogOptions struct {
+	// Namespace is the Rook namespace, where the Job is created
+	Namespace string
+
+	// Cluster is the selected CephCluster, if any
+	Cluster string
+
+	// NodeName is the node under maintenance
+	NodeName string
+
+	// Image is the crook container image
+	Image string
+
+	// ServiceAccount provides the in-cluster credentials for the Job
+	ServiceAccount string
+
+	// Expires is when the flag is cleared
+	Expires time.Time
+
+	// Scope and Groups are the noout scope to clear: the cluster-wide flag,
+	// or the CRUSH host or OSDs the flag was set on
+	Scope  string
+	Groups []string
+}
+
+// BuildNoOutWatchdogJob returns a Job that runs `crook noout-watchdog <node>`
+// with in-cluster credentials. The pod sleeps until the expiry and then
+// clears the flag; like maintenance Jobs it is kept off the target node.
+func BuildNoOutWatchdogJob(opts NoOutWatchdogOptions, now time.Time) *batchv1.Job {
+	backoffLimit := int32(noOutWatchdogBackoffLimit)
+	ttl := int32(noOutWatchdogKeep.Seconds())
+	deadline := int64((opts.Expires.Sub(now) + noOutWatchdogGrace).Seconds())
+	labels := map[string]string{
+		JobLabelName: NoOutWatchdogLabelValue,
+		JobLabelNode: opts.NodeName,
+	}
+
+	args := []string{
+		"noout-watchdog", opts.NodeName,
+		"--expires", opts.Expires.UTC().Format(time.RFC3339),
+		"--scope", opts.Scope,
+		"--in-cluster", "--namespace", opts.Namespace,
+	}
+	if len(opts.Groups) > 0 {
+		args = append(args, "--groups", strings.Join(opts.Groups, ","))
+	}
+	if opts.Cluster != "" {
+		args = append(args, "--cluster", opts.Cluster)
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: NoOutWatchdogLabelValue + "-",
+			Namespace:    opts.Namespace,
+			Labels:       labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			ActiveDeadlineSeconds:   &deadline,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: opts.ServiceAccount,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Affinity:           awayFromNode(opts.NodeName),
+					Containers: []corev1.Container{{
+						Name:  jobContainerName,
+						Image: opts.Image,
+						Args:  args,
+					}},
+				},
+			},
+		},
+	}
+}
+
+// noOutWatchdogSelector selects the watchdog Jobs of a node
+func noOutWatchdogSelector(nodeName string) string {
+	return fmt.Sprintf("%s=%s,%s=%s", JobLabelName, NoOutWatchdogLabelValue, JobLabelNode, nodeName)
+}
+
+// DeleteNoOutWatchdogs deletes the node's noout watchdog Jobs and their pods,
+// and returns the names of the deleted Jobs
+func (c *Client) DeleteNoOutWatchdogs(ctx context.Context, namespace, nodeName string) ([]string, error) {
+	jobs := c.Clientset.BatchV1().Jobs(namespace)
+	list, err := jobs.List(ctx, metav1.ListOptions{LabelSelector: noOutWatchdogSelector(nodeName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list noout watchdog jobs of %s: %w", nodeName, err)
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	var deleted []string
+	for _, job := range list.Items {
+		if err := jobs.Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+			return deleted, fmt.Errorf("failed to delete noout watchdog job %s/%s: %w", namespace, job.Name, err)
+		}
+		deleted = append(deleted, job.Name)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuildNoOutWatchdogJob(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	job := BuildNoOutWatchdogJob(NoOutWatchdogOptions{
+		Namespace:      "rook-ceph",
+		Cluster:        "prod",
+		NodeName:       "worker-1",
+		Image:          "example.com/crook:v1",
+		ServiceAccount: "crook",
+		Expires:        now.Add(4 * time.Hour),
+		Scope:          "osd",
+		Groups:         []string{"osd.0", "osd.3"},
+	}, now)
+
+	if job.Namespace != "rook-ceph" || job.GenerateName != "crook-noout-watchdog-" {
+		t.Errorf("unexpected job metadata: %s/%s", job.Namespace, job.GenerateName)
+	}
+	if job.Labels[JobLabelName] != NoOutWatchdogLabelValue || job.Labels[JobLabelNode] != "worker-1" {
+		t.Errorf("unexpected labels: %v", job.Labels)
+	}
+	if *job.Spec.ActiveDeadlineSeconds != 5*3600 {
+		t.Errorf("ActiveDeadlineSeconds = %d, want %d", *job.Spec.ActiveDeadlineSeconds, 5*3600)
+	}
+
+	wantArgs := []string{
+		"noout-watchdog", "worker-1", "--expires", "2026-10-16T12:00:00Z", "--scope", "osd",
+		"--in-cluster", "--namespace", "rook-ceph", "--groups", "osd.0,osd.3", "--cluster", "prod",
+	}
+	if args := job.Spec.Template.Spec.Containers[0].Args; !slices.Equal(args, wantArgs) {
+		t.Errorf("Args = %v, want %v", args, wantArgs)
+	}
+	terms := job.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if terms[0].MatchExpressions[0].Values[0] != "worker-1" {
+		t.Errorf("expected the pod to be kept off worker-1, got %+v", terms)
+	}
+}
+
+func TestDeleteNoOutWatchdogs(t *testing.T) {
+	job := func(name, app, node string) *batchv1.Job {
+		return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "rook-ceph",
+			Labels:    map[string]string{JobLabelName: app, JobLabelNode: node},
+		}}
+	}
+	clientset := fake.NewClientset(
+		job("watchdog-1", NoOutWatchdogLabelValue, "worker-1"),
+		job("watchdog-2", NoOutWatchdogLabelValue, "worker-2"),
+		job("crook-down-1", "crook", "worker-1"),
+	)
+	client := newClientFromClientset(clientset)
+
+	deleted, err := client.DeleteNoOutWatchdogs(context.Background(), "rook-ceph", "worker-1")
+	if err != nil {
+		t.Fatalf("DeleteNoOutWatchdogs() error = %v", err)
+	}
+	if !slices.Equal(deleted, []string{"watchdog-1"}) {
+		t.Errorf("DeleteNoOutWatchdogs() = %v, want [watchdog-1]", deleted)
+	}
+
+	remaining, err := clientset.BatchV1().Jobs("rook-ceph").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining.Items) != 2 {
+		t.Errorf("expected the other node's watchdog and the maintenance job to remain, got %d jobs", len(remaining.Items))
+	}
+}
@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// Labels Rook sets on OSD pods: the OSD ID and the CRUSH host bucket, which
+// Rook derives from the node's hostname and sanitizes (e.g. dots to dashes)
+const (
+	osdIDLabel     = "ceph-osd-id"
+	crushHostLabel = "topology-location-host"
+)
+
+// OSDPlacement is what Kubernetes knows about where a node's OSDs sit in the
+// CRUSH map. The CRUSH host bucket need not be named like the node, e.g. for
+// FQDN node names.
+type OSDPlacement struct {
+	// HostNames are the names the node's CRUSH host bucket may have: the node
+	// name, its kubernetes.io/hostname label and the CRUSH host its OSD pods
+	// and deployments are labelled with
+	HostNames []string
+
+	// OSDIDs are the IDs of the OSD pods scheduled to the node and of the OSD
+	// deployments pinned to it, so they are known while scaled down
+	OSDIDs []int
+}
+
+// GetOSDPlacement returns the CRUSH host names and OSD IDs of the node
+func (c *Client) GetOSDPlacement(ctx context.Context, namespace, nodeName string) (*OSDPlacement, error) {
+	node, err := c.GetNode(ctx, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	placement := &OSDPlacement{HostNames: []string{nodeName}}
+	if hostname := node.Labels[hostnameLabel]; hostname != "" && hostname != nodeName {
+		placement.HostNames = append(placement.HostNames, hostname)
+	}
+
+	addOSD := func(labels map[string]string) {
+		if host := labels[crushHostLabel]; host != "" && !slices.Contains(placement.HostNames, host) {
+			placement.HostNames = append(placement.HostNames, host)
+		}
+		if id, err := strconv.Atoi(labels[osdIDLabel]); err == nil && !slices.Contains(placement.OSDIDs, id) {
+			placement.OSDIDs = append(placement.OSDIDs, id)
+		}
+	}
+
+	pods, err := c.listPods(ctx, namespace, metav1.ListOptions{
+		LabelSelector: "app=rook-ceph-osd",
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list OSD pods on node %s: %w", nodeName, err)
+	}
+	for i := range pods {
+		// Field selectors are not applied by every client, e.g. fakes
+		if pods[i].Spec.NodeName == nodeName {
+			addOSD(pods[i].Labels)
+		}
+	}
+
+	// A scaled-down OSD has no pod, but its deployment stays pinned to the node
+	deployments, err := c.listDeployments(ctx, namespace, metav1.ListOptions{LabelSelector: "app=rook-ceph-osd"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list OSD deployments of node %s: %w", nodeName, err)
+	}
+	for i := range deployments {
+		if GetDeploymentTargetNode(&deployments[i]) == nodeName {
+			addOSD(deployments[i].Spec.Template.Labels)
+		}
+	}
+	slices.Sort(placement.OSDIDs)
+	return placement, nil
+}
@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetOSDPlacement(t *testing.T) {
+	osdPod := func(name, node, id string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph", Labels: map[string]string{
+				"app":          "rook-ceph-osd",
+				osdIDLabel:     id,
+				crushHostLabel: "worker-1-example-com",
+			}},
+			Spec: corev1.PodSpec{NodeName: node},
+		}
+	}
+	clientset := fake.NewClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   "worker-1.example.com",
+			Labels: map[string]string{hostnameLabel: "worker-1"},
+		}},
+		osdPod("rook-ceph-osd-3-abc", "worker-1.example.com", "3"),
+		osdPod("rook-ceph-osd-0-def", "worker-1.example.com", "0"),
+		osdPod("rook-ceph-osd-1-ghi", "worker-2.example.com", "1"),
+	)
+	client := newClientFromClientset(clientset)
+
+	placement, err := client.GetOSDPlacement(context.Background(), "rook-ceph", "worker-1.example.com")
+	if err != nil {
+		t.Fatalf("GetOSDPlacement() error = %v", err)
+	}
+	want := &OSDPlacement{
+		HostNames: []string{"worker-1.example.com", "worker-1", "worker-1-example-com"},
+		OSDIDs:    []int{0, 3},
+	}
+	if !reflect.DeepEqual(placement, want) {
+		t.Errorf("GetOSDPlacement() = %+v, want %+v", placement, want)
+	}
+}
+
+func TestGetOSDPlacement_ScaledDownOSDs(t *testing.T) {
+	osdDeployment := func(name, node, id string) *appsv1.Deployment {
+		labels := map[string]string{"app": "rook-ceph-osd", osdIDLabel: id, crushHostLabel: "worker-1-example-com"}
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph", Labels: labels},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: new(int32),
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec:       corev1.PodSpec{NodeSelector: map[string]string{hostnameLabel: node}},
+				},
+			},
+		}
+	}
+	clientset := fake.NewClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1.example.com"}},
+		osdDeployment("rook-ceph-osd-2", "worker-1.example.com", "2"),
+		osdDeployment("rook-ceph-osd-1", "worker-2.example.com", "1"),
+	)
+	client := newClientFromClientset(clientset)
+
+	placement, err := client.GetOSDPlacement(context.Background(), "rook-ceph", "worker-1.example.com")
+	if err != nil {
+		t.Fatalf("GetOSDPlacement() error = %v", err)
+	}
+	want := &OSDPlacement{
+		HostNames: []string{"worker-1.example.com", "worker-1-example-com"},
+		OSDIDs:    []int{2},
+	}
+	if !reflect.DeepEqual(placement, want) {
+		t.Errorf("GetOSDPlacement() = %+v, want %+v", placement, want)
+	}
+}
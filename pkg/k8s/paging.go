@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// listPageSize is how many objects a paginated list requests at once, so a
+// namespace with thousands of pods never comes back as one huge response
+const listPageSize = 500
+
+// listProjected lists in pages of listPageSize, following the continue token
+// of each page, and projects every object as its page arrives, so only one
+// page of full objects is held at a time. project drops an object by
+// returning false. When a token expires mid-list the list restarts as one
+// unpaginated request, as client-go's pager does.
+func listProjected[T, R any](
+	ctx context.Context,
+	opts metav1.ListOptions,
+	list func(context.Context, metav1.ListOptions) ([]T, metav1.ListMeta, error),
+	project func(*T) (R, bool),
+) ([]R, error) {
+	opts.Limit = listPageSize
+	var projected []R
+	for {
+		page, meta, err := list(ctx, opts)
+		if apierrors.IsResourceExpired(err) && opts.Continue != "" {
+			opts.Limit, opts.Continue, projected = 0, "", nil
+			page, meta, err = list(ctx, opts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := range page {
+			if r, ok := project(&page[i]); ok {
+				projected = append(projected, r)
+			}
+		}
+		if meta.Continue == "" || opts.Limit == 0 {
+			return projected, nil
+		}
+		opts.Continue = meta.Continue
+	}
+}
+
+// keep projects an object to itself
+func keep[T any](obj *T) (T, bool) {
+	return *obj, true
+}
+
+// listPods lists the pods of a namespace page by page
+func (c *Client) listPods(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+	return projectPods(ctx, c, namespace, opts, keep[corev1.Pod])
+}
+
+// projectPods lists the pods of a namespace page by page, keeping only
+// their projections
+func projectPods[R any](ctx context.Context, c *Client, namespace string, opts metav1.ListOptions, project func(*corev1.Pod) (R, bool)) ([]R, error) {
+	return listProjected(ctx, opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Pod, metav1.ListMeta, error) {
+		list, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return list.Items, list.ListMeta, nil
+	}, project)
+}
+
+// projectNodes lists the nodes page by page, keeping only their projections
+func projectNodes[R any](ctx context.Context, c *Client, opts metav1.ListOptions, project func(*corev1.Node) (R, bool)) ([]R, error) {
+	return listProjected(ctx, opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, metav1.ListMeta, error) {
+		list, err := c.Clientset.CoreV1().Nodes().List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return list.Items, list.ListMeta, nil
+	}, project)
+}
+
+// listDeployments lists the deployments of a namespace page by page
+func (c *Client) listDeployments(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
+	return projectDeployments(ctx, c, namespace, opts, keep[appsv1.Deployment])
+}
+
+// projectDeployments lists the deployments of a namespace page by page,
+// keeping only their projections
+func projectDeployments[R any](ctx context.Context, c *Client, namespace string, opts metav1.ListOptions, project func(*appsv1.Deployment) (R, bool)) ([]R, error) {
+	return listProjected(ctx, opts, func(ctx context.Context, opts metav1.ListOptions) ([]appsv1.Deployment, metav1.ListMeta, error) {
+		list, err := c.Clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return list.Items, list.ListMeta, nil
+	}, project)
+}
@@ -0,0 +1,101 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// pagedPods serves n pods in pages of the requested limit; expireAfter > 0
+// expires the continue token once that many pages were served
+func pagedPods(clientset *fake.Clientset, n, expireAfter int) *[]metav1.ListOptions {
+	var requests []metav1.ListOptions
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		restrictions := action.(k8stesting.ListActionImpl).ListOptions
+		requests = append(requests, restrictions)
+		start := 0
+		if restrictions.Continue != "" {
+			if expireAfter > 0 && len(requests) > expireAfter {
+				return true, nil, apierrors.NewResourceExpired("continue token expired")
+			}
+			_, _ = fmt.Sscanf(restrictions.Continue, "%d", &start)
+		}
+		end := n
+		if restrictions.Limit > 0 {
+			end = min(start+int(restrictions.Limit), n)
+		}
+		list := &corev1.PodList{}
+		for i := start; i < end; i++ {
+			list.Items = append(list.Items, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("rook-ceph-osd-%d", i), Namespace: "rook-ceph"}})
+		}
+		if end < n {
+			list.Continue = fmt.Sprint(end)
+		}
+		return true, list, nil
+	})
+	return &requests
+}
+
+func TestListPodsInNamespace_Paginated(t *testing.T) {
+	tests := []struct {
+		name         string
+		pods         int
+		expireAfter  int
+		wantRequests int
+	}{
+		{name: "single page", pods: 3, wantRequests: 1},
+		{name: "several pages", pods: 2*listPageSize + 1, wantRequests: 3},
+		{name: "expired token restarts unpaginated", pods: 2*listPageSize + 1, expireAfter: 1, wantRequests: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewClientset()
+			requests := pagedPods(clientset, tt.pods, tt.expireAfter)
+			client := newClientFromClientset(clientset)
+
+			pods, err := client.ListPodsInNamespace(context.Background(), "rook-ceph")
+			if err != nil {
+				t.Fatalf("ListPodsInNamespace() error = %v", err)
+			}
+			if len(pods) != tt.pods {
+				t.Errorf("got %d pods, want %d", len(pods), tt.pods)
+			}
+			if len(*requests) != tt.wantRequests {
+				t.Errorf("list requests = %d, want %d", len(*requests), tt.wantRequests)
+			}
+			if (*requests)[0].Limit != listPageSize {
+				t.Errorf("first request limit = %d, want %d", (*requests)[0].Limit, listPageSize)
+			}
+		})
+	}
+}
+
+func TestListProjected(t *testing.T) {
+	// Three pages of numbers; the projection keeps the even ones as strings
+	pages := map[string][]int{"": {0, 1, 2}, "1": {3, 4, 5}, "2": {6}}
+	next := map[string]string{"": "1", "1": "2"}
+	list := func(_ context.Context, opts metav1.ListOptions) ([]int, metav1.ListMeta, error) {
+		if opts.Limit != listPageSize {
+			t.Errorf("Limit = %d, want %d", opts.Limit, listPageSize)
+		}
+		return pages[opts.Continue], metav1.ListMeta{Continue: next[opts.Continue]}, nil
+	}
+
+	got, err := listProjected(context.Background(), metav1.ListOptions{}, list, func(n *int) (string, bool) {
+		return fmt.Sprint(*n), *n%2 == 0
+	})
+	if err != nil {
+		t.Fatalf("listProjected() error = %v", err)
+	}
+	if fmt.Sprint(got) != "[0 2 4 6]" {
+		t.Errorf("listProjected() = %v, want [0 2 4 6]", got)
+	}
+}
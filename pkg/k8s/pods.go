@@ -187,11 +187,11 @@ func (c *Client) GetPod(ctx context.Context, namespace, name string) (*corev1.Po
 
 // ListPodsInNamespace returns all pods in a namespace
 func (c *Client) ListPodsInNamespace(ctx context.Context, namespace string) ([]corev1.Pod, error) {
-	podList, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	pods, err := c.listPods(ctx, namespace, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
 	}
-	return podList.Items, nil
+	return pods, nil
 }
 
 // PodInfo holds pod information for display and serialization
@@ -233,44 +233,42 @@ type PodInfo struct {
 // ListCephPods returns Ceph pods with detailed info.
 // Uses DefaultRookCephPrefixes() to filter pods.
 func (c *Client) ListCephPods(ctx context.Context, namespace string, nodeFilter string) ([]PodInfo, error) {
+	return c.ListCephPodsMatching(ctx, namespace, nodeFilter, ListSelector{})
+}
+
+// ListCephPodsMatching is ListCephPods limited to the pods matching the selector
+func (c *Client) ListCephPodsMatching(ctx context.Context, namespace, nodeFilter string, selector ListSelector) ([]PodInfo, error) {
 	prefixes := DefaultRookCephPrefixes()
 
 	// Build list options
-	listOpts := metav1.ListOptions{}
+	listOpts := c.cephClusterListOptions()
 	if nodeFilter != "" {
 		listOpts.FieldSelector = fmt.Sprintf("spec.nodeName=%s", nodeFilter)
 	}
+	listOpts = selector.apply(listOpts)
 
-	// Get pods in namespace
-	podList, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, listOpts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
-	}
-
-	// Build result
-	var result []PodInfo
+	// Describe the Ceph pods in the namespace as each page arrives
 	now := time.Now()
-
-	for _, pod := range podList.Items {
+	result, err := projectPods(ctx, c, namespace, listOpts, func(pod *corev1.Pod) (PodInfo, bool) {
 		// Check if pod matches any of the prefixes
 		if !matchesAnyPrefix(pod.Name, prefixes) {
-			continue
+			return PodInfo{}, false
 		}
 
 		// Get owner deployment
 		ownerDeployment := ""
-		chain, chainErr := c.GetOwnerChain(ctx, &pod)
+		chain, chainErr := c.GetOwnerChain(ctx, pod)
 		if chainErr == nil && chain.Deployment != nil {
 			ownerDeployment = chain.Deployment.Name
 		}
 
 		// Calculate ready containers and restarts
-		readyContainers, totalContainers, restarts := getPodContainerStats(&pod)
+		readyContainers, totalContainers, restarts := getPodContainerStats(pod)
 
-		info := PodInfo{
+		return PodInfo{
 			Name:            pod.Name,
 			Namespace:       pod.Namespace,
-			Status:          getPodStatus(&pod),
+			Status:          getPodStatus(pod),
 			ReadyContainers: readyContainers,
 			TotalContainers: totalContainers,
 			Restarts:        restarts,
@@ -279,8 +277,10 @@ func (c *Client) ListCephPods(ctx context.Context, namespace string, nodeFilter
 			Type:            extractPodType(pod.Name),
 			IP:              pod.Status.PodIP,
 			OwnerDeployment: ownerDeployment,
-		}
-		result = append(result, info)
+		}, true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
 	}
 
 	return result, nil
@@ -0,0 +1,200 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PVCInfo is a PersistentVolumeClaim served by a Ceph CSI storage class
+type PVCInfo struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// StorageClass is the claim's storage class
+	StorageClass string `json:"storage_class"`
+
+	// Driver is the storage class's Ceph CSI driver
+	Driver string `json:"driver"`
+
+	// Volume is the bound PV, empty while the claim is pending
+	Volume string `json:"volume,omitempty"`
+
+	// Phase is the claim's phase: Bound, Pending or Lost
+	Phase string `json:"phase"`
+
+	// Size is the provisioned capacity, or the requested size while pending
+	Size string `json:"size"`
+
+	// Pool is the RBD pool, or the CephFS filesystem, serving the volume
+	Pool string `json:"pool,omitempty"`
+
+	// Nodes are the nodes the volume is attached to or mounted on
+	Nodes []string `json:"nodes,omitempty"`
+
+	// Pods are the pods mounting the claim, in its namespace
+	Pods []string `json:"pods,omitempty"`
+
+	// AttachError is the last attach or detach error of the volume's
+	// attachments
+	AttachError string `json:"attach_error,omitempty"`
+}
+
+// ListCephPVCs returns the claims in the namespace, all namespaces when
+// empty, whose storage class is served by a Ceph CSI driver, sorted by
+// namespace and name. Each lists the nodes its volume is attached to or, for
+// CephFS, which needs no attachment, the nodes of the pods mounting it.
+func (c *Client) ListCephPVCs(ctx context.Context, namespace string) ([]PVCInfo, error) {
+	classes, err := c.Clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage classes: %w", err)
+	}
+	cephClasses := map[string]*storagev1.StorageClass{}
+	for i := range classes.Items {
+		if IsCephCSIDriver(classes.Items[i].Provisioner) {
+			cephClasses[classes.Items[i].Name] = &classes.Items[i]
+		}
+	}
+	if len(cephClasses) == 0 {
+		return nil, nil
+	}
+
+	claims, err := listProjected(ctx, metav1.ListOptions{}, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, metav1.ListMeta, error) {
+		list, err := c.Clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return list.Items, list.ListMeta, nil
+	}, func(claim *corev1.PersistentVolumeClaim) (corev1.PersistentVolumeClaim, bool) {
+		return *claim, claim.Spec.StorageClassName != nil && cephClasses[*claim.Spec.StorageClassName] != nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %w", err)
+	}
+	if len(claims) == 0 {
+		return nil, nil
+	}
+
+	pvs, err := listProjected(ctx, metav1.ListOptions{}, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.PersistentVolume, metav1.ListMeta, error) {
+		list, err := c.Clientset.CoreV1().PersistentVolumes().List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return list.Items, list.ListMeta, nil
+	}, func(pv *corev1.PersistentVolume) (corev1.PersistentVolume, bool) {
+		return *pv, pv.Spec.CSI != nil && IsCephCSIDriver(pv.Spec.CSI.Driver)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volumes: %w", err)
+	}
+	volumeAttributes := make(map[string]map[string]string, len(pvs))
+	for _, pv := range pvs {
+		volumeAttributes[pv.Name] = pv.Spec.CSI.VolumeAttributes
+	}
+
+	attachments, err := listProjected(ctx, metav1.ListOptions{}, func(ctx context.Context, opts metav1.ListOptions) ([]storagev1.VolumeAttachment, metav1.ListMeta, error) {
+		list, err := c.Clientset.StorageV1().VolumeAttachments().List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return list.Items, list.ListMeta, nil
+	}, func(attachment *storagev1.VolumeAttachment) (VolumeInfo, bool) {
+		volume := volumeFromAttachment(attachment)
+		volume.Attachment = attachment.Spec.NodeName
+		return volume, attachment.Spec.Source.PersistentVolumeName != nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume attachments: %w", err)
+	}
+
+	pods, err := c.listPods(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	consumers := map[string][]*corev1.Pod{}
+	for i := range pods {
+		for _, claim := range podClaims(&pods[i]) {
+			consumers[claim] = append(consumers[claim], &pods[i])
+		}
+	}
+
+	result := make([]PVCInfo, 0, len(claims))
+	for i := range claims {
+		claim := &claims[i]
+		class := cephClasses[*claim.Spec.StorageClassName]
+		info := PVCInfo{
+			Namespace:    claim.Namespace,
+			Name:         claim.Name,
+			StorageClass: class.Name,
+			Driver:       class.Provisioner,
+			Volume:       claim.Spec.VolumeName,
+			Phase:        string(claim.Status.Phase),
+			Size:         claimSize(claim),
+			Pool:         cephPool(volumeAttributes[claim.Spec.VolumeName], class.Parameters),
+		}
+
+		var nodes []string
+		for _, attachment := range attachments {
+			if info.Volume == "" || attachment.PersistentVolume != info.Volume {
+				continue
+			}
+			nodes = append(nodes, attachment.Attachment)
+			if attachment.Error != "" {
+				info.AttachError = attachment.Error
+			}
+		}
+		for _, pod := range consumers[claim.Namespace+"/"+claim.Name] {
+			info.Pods = append(info.Pods, pod.Name)
+			if pod.Spec.NodeName != "" {
+				nodes = append(nodes, pod.Spec.NodeName)
+			}
+		}
+		slices.Sort(nodes)
+		info.Nodes = slices.Compact(nodes)
+		slices.Sort(info.Pods)
+		result = append(result, info)
+	}
+
+	slices.SortFunc(result, func(a, b PVCInfo) int {
+		if a.Namespace != b.Namespace {
+			return strings.Compare(a.Namespace, b.Namespace)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result, nil
+}
+
+// claimSize returns the claim's provisioned capacity, or its request while
+// it is pending
+func claimSize(claim *corev1.PersistentVolumeClaim) string {
+	if size, ok := claim.Status.Capacity[corev1.ResourceStorage]; ok {
+		return size.String()
+	}
+	if size, ok := claim.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		return size.String()
+	}
+	return ""
+}
+
+// cephPool names the pool serving a volume from its CSI volume attributes,
+// else from its storage class parameters: the RBD pool, or the CephFS
+// filesystem with its data pool when one is set
+func cephPool(attributes, parameters map[string]string) string {
+	for _, source := range []map[string]string{attributes, parameters} {
+		pool, fsName := source["pool"], source["fsName"]
+		switch {
+		case fsName != "" && pool != "":
+			return fsName + "/" + pool
+		case fsName != "":
+			return fsName
+		case pool != "":
+			return pool
+		}
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+package k8s
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestListCephPVCs(t *testing.T) {
+	const (
+		rbd    = "rook-ceph.rbd.csi.ceph.com"
+		cephfs = "rook-ceph.cephfs.csi.ceph.com"
+	)
+
+	class := func(name, provisioner string, parameters map[string]string) *storagev1.StorageClass {
+		return &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name}, Provisioner: provisioner, Parameters: parameters}
+	}
+	claim := func(name, className, volume string) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &className,
+				VolumeName:       volume,
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		}
+		if volume != "" {
+			pvc.Status.Phase = corev1.ClaimBound
+			pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}
+		}
+		return pvc
+	}
+	pv := func(name, driver string, attributes map[string]string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: driver, VolumeAttributes: attributes},
+				},
+			},
+		}
+	}
+	pod := func(name, node, claimName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec: corev1.PodSpec{NodeName: node, Volumes: []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName}},
+			}}},
+		}
+	}
+	pvName := "pv-db"
+	attachment := &storagev1.VolumeAttachment{
+		ObjectMeta: metav1.ObjectMeta{Name: "csi-db"},
+		Spec: storagev1.VolumeAttachmentSpec{
+			Attacher: rbd,
+			NodeName: "worker-1",
+			Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+		},
+		Status: storagev1.VolumeAttachmentStatus{
+			Attached:    true,
+			AttachError: &storagev1.VolumeError{Message: "rbd: map failed"},
+		},
+	}
+
+	clientset := fake.NewClientset(
+		class("ceph-block", rbd, map[string]string{"pool": "replicapool"}),
+		class("ceph-fs", cephfs, map[string]string{"fsName": "myfs", "pool": "myfs-replicated"}),
+		class("local", "kubernetes.io/no-provisioner", nil),
+		claim("db", "ceph-block", "pv-db"),
+		claim("shared", "ceph-fs", "pv-shared"),
+		claim("new", "ceph-block", ""),
+		claim("cache", "local", "pv-cache"),
+		pv("pv-db", rbd, map[string]string{"pool": "replicapool-ssd"}),
+		pv("pv-shared", cephfs, map[string]string{"fsName": "myfs"}),
+		attachment,
+		pod("db-0", "worker-1", "db"),
+		pod("web-0", "worker-2", "shared"),
+		pod("web-1", "worker-3", "shared"),
+	)
+	client := newClientFromClientset(clientset)
+
+	pvcs, err := client.ListCephPVCs(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ListCephPVCs() error = %v", err)
+	}
+
+	want := []PVCInfo{
+		{Namespace: "apps", Name: "db", StorageClass: "ceph-block", Driver: rbd, Volume: "pv-db", Phase: "Bound", Size: "10Gi",
+			Pool: "replicapool-ssd", Nodes: []string{"worker-1"}, Pods: []string{"db-0"}, AttachError: "rbd: map failed"},
+		{Namespace: "apps", Name: "new", StorageClass: "ceph-block", Driver: rbd, Phase: "Pending", Size: "1Gi", Pool: "replicapool"},
+		{Namespace: "apps", Name: "shared", StorageClass: "ceph-fs", Driver: cephfs, Volume: "pv-shared", Phase: "Bound", Size: "10Gi",
+			Pool: "myfs", Nodes: []string{"worker-2", "worker-3"}, Pods: []string{"web-0", "web-1"}},
+	}
+	if len(pvcs) != len(want) {
+		t.Fatalf("ListCephPVCs() = %+v, want %d claims", pvcs, len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(pvcs[i], want[i]) {
+			t.Errorf("claim %d = %+v, want %+v", i, pvcs[i], want[i])
+		}
+	}
+}
+
+func TestCephPool(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]string
+		parameters map[string]string
+		want       string
+	}{
+		{name: "rbd volume", attributes: map[string]string{"pool": "ssd"}, parameters: map[string]string{"pool": "hdd"}, want: "ssd"},
+		{name: "storage class fallback", parameters: map[string]string{"pool": "hdd"}, want: "hdd"},
+		{name: "cephfs", attributes: map[string]string{"fsName": "myfs"}, want: "myfs"},
+		{name: "cephfs data pool", attributes: map[string]string{"fsName": "myfs", "pool": "myfs-data"}, want: "myfs/myfs-data"},
+		{name: "unknown", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cephPool(tt.attributes, tt.parameters); got != tt.want {
+				t.Errorf("cephPool() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
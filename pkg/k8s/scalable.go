@@ -0,0 +1,268 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Workload kinds the maintenance phases can scale
+const (
+	WorkloadKindDeployment  = "Deployment"
+	WorkloadKindStatefulSet = "StatefulSet"
+	WorkloadKindReplicaSet  = "ReplicaSet"
+)
+
+// OriginalReplicasAnnotation records a workload's replica count before the
+// down phase scaled it to 0, so the up phase can restore it even when the
+// plan held in memory was lost
+const OriginalReplicasAnnotation = MaintenanceAnnotationPrefix + "original-replicas"
+
+// NoOutScopeAnnotation records, as JSON, the noout scope a node's down phase
+// or the scale-down of a single deployment set, on the node or the
+// deployment, so the up clears exactly that flag. It is absent on a
+// deployment when the flag was already set by someone else.
+const NoOutScopeAnnotation = MaintenanceAnnotationPrefix + "noout-scope"
+
+// Scalable is a workload API exposing the /scale subresource. The typed
+// clients for deployments, statefulsets and replicasets all implement it.
+type Scalable interface {
+	GetScale(ctx context.Context, name string, options metav1.GetOptions) (*autoscalingv1.Scale, error)
+	UpdateScale(ctx context.Context, name string, scale *autoscalingv1.Scale, opts metav1.UpdateOptions) (*autoscalingv1.Scale, error)
+}
+
+// scalableKind describes how to reach a workload kind's scale subresource
+// and status
+type scalableKind struct {
+	// resource is the lowercase API resource name used in messages
+	resource string
+	scalable func(c *Client, namespace string) Scalable
+	status   func(ctx context.Context, c *Client, namespace, name string) (*DeploymentStatus, error)
+	patch    func(ctx context.Context, c *Client, namespace, name string, data []byte) error
+}
+
+// scalableKinds registers the workload kinds ScaleWorkload and
+// GetWorkloadStatus support. Adding a kind here is all the maintenance phases
+// need to scale it.
+var scalableKinds = map[string]scalableKind{
+	WorkloadKindDeployment: {
+		resource: "deployment",
+		scalable: func(c *Client, namespace string) Scalable { return c.Clientset.AppsV1().Deployments(namespace) },
+		status: func(ctx context.Context, c *Client, namespace, name string) (*DeploymentStatus, error) {
+			return c.GetDeploymentStatus(ctx, namespace, name)
+		},
+		patch: func(ctx context.Context, c *Client, namespace, name string, data []byte) error {
+			_, err := c.Clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{})
+			return err
+		},
+	},
+	WorkloadKindStatefulSet: {
+		resource: "statefulset",
+		scalable: func(c *Client, namespace string) Scalable { return c.Clientset.AppsV1().StatefulSets(namespace) },
+		status: func(ctx context.Context, c *Client, namespace, name string) (*DeploymentStatus, error) {
+			return c.GetStatefulSetStatus(ctx, namespace, name)
+		},
+		patch: func(ctx context.Context, c *Client, namespace, name string, data []byte) error {
+			_, err := c.Clientset.AppsV1().StatefulSets(namespace).Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{})
+			return err
+		},
+	},
+	WorkloadKindReplicaSet: {
+		resource: "replicaset",
+		scalable: func(c *Client, namespace string) Scalable { return c.Clientset.AppsV1().ReplicaSets(namespace) },
+		status:   getReplicaSetStatus,
+		patch: func(ctx context.Context, c *Client, namespace, name string, data []byte) error {
+			_, err := c.Clientset.AppsV1().ReplicaSets(namespace).Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{})
+			return err
+		},
+	},
+}
+
+// Workload identifies a scalable workload discovered for a node
+type Workload struct {
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Replicas is the spec replica count when the workload was discovered
+	Replicas int32
+
+	// OriginalReplicas is read from OriginalReplicasAnnotation; 0 when the
+	// annotation is missing or invalid
+	OriginalReplicas int32
+}
+
+// String returns namespace/name
+func (w Workload) String() string {
+	return w.Namespace + "/" + w.Name
+}
+
+// DisplayName returns namespace/name for deployments, the default kind, and
+// "<kind> namespace/name" for other kinds
+func (w Workload) DisplayName() string {
+	if w.Kind == WorkloadKindDeployment || w.Kind == "" {
+		return w.String()
+	}
+	return strings.ToLower(w.Kind) + " " + w.String()
+}
+
+// DeploymentWorkloads describes deployments as workloads, preserving their order
+func DeploymentWorkloads(deployments []appsv1.Deployment) []Workload {
+	workloads := make([]Workload, 0, len(deployments))
+	for i := range deployments {
+		workloads = append(workloads, Workload{
+			Kind:             WorkloadKindDeployment,
+			Namespace:        deployments[i].Namespace,
+			Name:             deployments[i].Name,
+			Replicas:         getDeploymentDesiredReplicas(&deployments[i]),
+			OriginalReplicas: OriginalReplicas(deployments[i].Annotations),
+		})
+	}
+	return workloads
+}
+
+// StatefulSetWorkloads describes StatefulSets as workloads, preserving their order
+func StatefulSetWorkloads(statefulSets []appsv1.StatefulSet) []Workload {
+	workloads := make([]Workload, 0, len(statefulSets))
+	for _, sts := range statefulSets {
+		replicas := int32(1)
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+		workloads = append(workloads, Workload{
+			Kind:             WorkloadKindStatefulSet,
+			Namespace:        sts.Namespace,
+			Name:             sts.Name,
+			Replicas:         replicas,
+			OriginalReplicas: OriginalReplicas(sts.Annotations),
+		})
+	}
+	return workloads
+}
+
+// OriginalReplicas parses OriginalReplicasAnnotation from a workload's
+// annotations, returning 0 when it is missing or not a positive number
+func OriginalReplicas(annotations map[string]string) int32 {
+	value, ok := annotations[OriginalReplicasAnnotation]
+	if !ok {
+		return 0
+	}
+	replicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || replicas <= 0 {
+		return 0
+	}
+	return int32(replicas)
+}
+
+// lookupScalableKind returns the registered kind or an error for an unknown one
+func lookupScalableKind(kind string) (scalableKind, error) {
+	sk, ok := scalableKinds[kind]
+	if !ok {
+		return scalableKind{}, fmt.Errorf("unsupported workload kind %q", kind)
+	}
+	return sk, nil
+}
+
+// ScaleWorkload scales a workload of any registered kind through its /scale
+// subresource, which only needs the <resource>/scale RBAC permission. The
+// replica change is recorded in the client's change log.
+func (c *Client) ScaleWorkload(ctx context.Context, kind, namespace, name string, replicas int32) error {
+	sk, err := lookupScalableKind(kind)
+	if err != nil {
+		return err
+	}
+	scalable := sk.scalable(c, namespace)
+
+	scale, err := scalable.GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get scale for %s %s/%s: %w", sk.resource, namespace, name, err)
+	}
+
+	before := scale.Spec.Replicas
+	scale.Spec.Replicas = replicas
+
+	if _, err = scalable.UpdateScale(ctx, name, scale, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to scale %s %s/%s to %d replicas: %w", sk.resource, namespace, name, replicas, err)
+	}
+
+	c.recordChange(Change{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Field:     "spec.replicas",
+		Before:    strconv.Itoa(int(before)),
+		After:     strconv.Itoa(int(replicas)),
+	})
+	return nil
+}
+
+// GetWorkloadStatus returns the replica status of a workload of any
+// registered kind
+func (c *Client) GetWorkloadStatus(ctx context.Context, kind, namespace, name string) (*DeploymentStatus, error) {
+	sk, err := lookupScalableKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	return sk.status(ctx, c, namespace, name)
+}
+
+// SetWorkloadAnnotation sets an annotation on a workload of any registered
+// kind with a merge patch, or removes it when value is nil
+func (c *Client) SetWorkloadAnnotation(ctx context.Context, kind, namespace, name, key string, value *string) error {
+	sk, err := lookupScalableKind(kind)
+	if err != nil {
+		return err
+	}
+
+	patch := map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]any{key: value},
+		},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to encode annotation patch: %w", err)
+	}
+
+	if patchErr := sk.patch(ctx, c, namespace, name, data); patchErr != nil {
+		return fmt.Errorf("failed to annotate %s %s/%s: %w", sk.resource, namespace, name, patchErr)
+	}
+	return nil
+}
+
+// WorkloadResource returns the lowercase API resource name of a kind
+// (deployment, statefulset, replicaset), or the lowercased kind if unknown
+func WorkloadResource(kind string) string {
+	if sk, ok := scalableKinds[kind]; ok {
+		return sk.resource
+	}
+	return strings.ToLower(kind)
+}
+
+// getReplicaSetStatus returns the status of a ReplicaSet
+func getReplicaSetStatus(ctx context.Context, c *Client, namespace, name string) (*DeploymentStatus, error) {
+	rs, err := c.Clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replicaset %s/%s: %w", namespace, name, err)
+	}
+
+	replicas := int32(0)
+	if rs.Spec.Replicas != nil {
+		replicas = *rs.Spec.Replicas
+	}
+
+	return &DeploymentStatus{
+		Name:              rs.Name,
+		Namespace:         rs.Namespace,
+		Replicas:          replicas,
+		ReadyReplicas:     rs.Status.ReadyReplicas,
+		AvailableReplicas: rs.Status.AvailableReplicas,
+	}, nil
+}
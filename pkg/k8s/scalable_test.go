@@ -0,0 +1,143 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestScaleWorkload(t *testing.T) {
+	tests := []struct {
+		kind     string
+		resource string
+	}{
+		{WorkloadKindDeployment, "deployments"},
+		{WorkloadKindStatefulSet, "statefulsets"},
+		{WorkloadKindReplicaSet, "replicasets"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			clientset := fake.NewClientset()
+			replicas := int32(1)
+			tracked := map[string]*int32{"rook-ceph/workload": &replicas}
+			addResourceScaleReactors(clientset, tt.resource, tracked)
+			client := newClientFromClientset(clientset)
+
+			if err := client.ScaleWorkload(context.Background(), tt.kind, "rook-ceph", "workload", 0); err != nil {
+				t.Fatalf("ScaleWorkload() error = %v", err)
+			}
+			if got := *tracked["rook-ceph/workload"]; got != 0 {
+				t.Errorf("replicas = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestScaleWorkload_UnsupportedKind(t *testing.T) {
+	client := newClientFromClientset(fake.NewClientset())
+
+	if err := client.ScaleWorkload(context.Background(), "DaemonSet", "rook-ceph", "x", 0); err == nil {
+		t.Error("ScaleWorkload() expected error for an unsupported kind, got nil")
+	}
+	if _, err := client.GetWorkloadStatus(context.Background(), "DaemonSet", "rook-ceph", "x"); err == nil {
+		t.Error("GetWorkloadStatus() expected error for an unsupported kind, got nil")
+	}
+}
+
+func TestGetWorkloadStatus(t *testing.T) {
+	replicas := int32(2)
+	client := newClientFromClientset(fake.NewClientset(
+		&appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "exporter", Namespace: "rook-ceph"},
+			Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+			Status:     appsv1.ReplicaSetStatus{ReadyReplicas: 1},
+		},
+		pinnedStatefulSet("metrics-agent", "worker-1", 1),
+	))
+
+	status, err := client.GetWorkloadStatus(context.Background(), WorkloadKindReplicaSet, "rook-ceph", "exporter")
+	if err != nil {
+		t.Fatalf("GetWorkloadStatus(ReplicaSet) error = %v", err)
+	}
+	if status.Replicas != 2 || status.ReadyReplicas != 1 {
+		t.Errorf("ReplicaSet status = %+v, want 2 replicas, 1 ready", status)
+	}
+
+	status, err = client.GetWorkloadStatus(context.Background(), WorkloadKindStatefulSet, "rook-ceph", "metrics-agent")
+	if err != nil {
+		t.Fatalf("GetWorkloadStatus(StatefulSet) error = %v", err)
+	}
+	if status.Replicas != 1 || status.ReadyReplicas != 1 {
+		t.Errorf("StatefulSet status = %+v, want 1 replica, 1 ready", status)
+	}
+}
+
+func TestWorkloads(t *testing.T) {
+	deployments := DeploymentWorkloads([]appsv1.Deployment{
+		{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-0", Namespace: "rook-ceph"}},
+	})
+	statefulSets := StatefulSetWorkloads([]appsv1.StatefulSet{*pinnedStatefulSet("metrics-agent", "worker-1", 0)})
+
+	if got := deployments[0]; got.Kind != WorkloadKindDeployment || got.Replicas != 1 || got.DisplayName() != "rook-ceph/rook-ceph-osd-0" {
+		t.Errorf("deployment workload = %+v (%s)", got, got.DisplayName())
+	}
+	if got := statefulSets[0]; got.Kind != WorkloadKindStatefulSet || got.Replicas != 0 || got.DisplayName() != "statefulset rook-ceph/metrics-agent" {
+		t.Errorf("statefulset workload = %+v (%s)", got, got.DisplayName())
+	}
+}
+
+func TestOriginalReplicas(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        int32
+	}{
+		{"missing", nil, 0},
+		{"valid", map[string]string{OriginalReplicasAnnotation: "3"}, 3},
+		{"zero", map[string]string{OriginalReplicasAnnotation: "0"}, 0},
+		{"negative", map[string]string{OriginalReplicasAnnotation: "-1"}, 0},
+		{"not a number", map[string]string{OriginalReplicasAnnotation: "two"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OriginalReplicas(tt.annotations); got != tt.want {
+				t.Errorf("OriginalReplicas() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetWorkloadAnnotation(t *testing.T) {
+	ctx := context.Background()
+	client := newClientFromClientset(fake.NewClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-0", Namespace: "rook-ceph"}},
+	))
+
+	value := "2"
+	if err := client.SetWorkloadAnnotation(ctx, WorkloadKindDeployment, "rook-ceph", "rook-ceph-osd-0", OriginalReplicasAnnotation, &value); err != nil {
+		t.Fatalf("SetWorkloadAnnotation() error = %v", err)
+	}
+	dep, err := client.Clientset.AppsV1().Deployments("rook-ceph").Get(ctx, "rook-ceph-osd-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := OriginalReplicas(dep.Annotations); got != 2 {
+		t.Errorf("annotated replicas = %d, want 2", got)
+	}
+
+	if err := client.SetWorkloadAnnotation(ctx, WorkloadKindDeployment, "rook-ceph", "rook-ceph-osd-0", OriginalReplicasAnnotation, nil); err != nil {
+		t.Fatalf("SetWorkloadAnnotation(nil) error = %v", err)
+	}
+	dep, err = client.Clientset.AppsV1().Deployments("rook-ceph").Get(ctx, "rook-ceph-osd-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, ok := dep.Annotations[OriginalReplicasAnnotation]; ok {
+		t.Errorf("annotation still present after removal: %v", dep.Annotations)
+	}
+}
@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ListSelector narrows the nodes, deployments and pods listed for display
+// with kubectl-style label and field selectors. The API server applies them,
+// so objects that do not match are never transferred.
+type ListSelector struct {
+	// Label is a label selector, e.g. "app=rook-ceph-osd"
+	Label string
+
+	// Field is a field selector, e.g. "spec.unschedulable=false"
+	Field string
+}
+
+// IsZero reports whether the selector selects everything
+func (s ListSelector) IsZero() bool {
+	return s.Label == "" && s.Field == ""
+}
+
+// Validate checks the syntax of both selectors
+func (s ListSelector) Validate() error {
+	if _, err := labels.Parse(s.Label); err != nil {
+		return fmt.Errorf("invalid label selector %q: %w", s.Label, err)
+	}
+	if _, err := fields.ParseSelector(s.Field); err != nil {
+		return fmt.Errorf("invalid field selector %q: %w", s.Field, err)
+	}
+	return nil
+}
+
+// apply adds the selectors to list options; objects must match both the
+// existing selectors and these
+func (s ListSelector) apply(opts metav1.ListOptions) metav1.ListOptions {
+	opts.LabelSelector = joinSelectors(opts.LabelSelector, s.Label)
+	opts.FieldSelector = joinSelectors(opts.FieldSelector, s.Field)
+	return opts
+}
+
+// joinSelectors requires both selectors, either of which may be empty
+func joinSelectors(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return a + "," + b
+	}
+}
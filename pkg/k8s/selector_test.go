@@ -0,0 +1,85 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestListSelector_Apply(t *testing.T) {
+	tests := []struct {
+		name      string
+		selector  ListSelector
+		opts      metav1.ListOptions
+		wantLabel string
+		wantField string
+	}{
+		{name: "zero keeps options", opts: metav1.ListOptions{LabelSelector: "a=b"}, wantLabel: "a=b"},
+		{name: "sets empty options", selector: ListSelector{Label: "app=osd", Field: "spec.nodeName=n1"}, wantLabel: "app=osd", wantField: "spec.nodeName=n1"},
+		{
+			name:      "requires both",
+			selector:  ListSelector{Label: "app=osd", Field: "status.phase=Running"},
+			opts:      metav1.ListOptions{LabelSelector: CephClusterLabel + "=ceph", FieldSelector: "spec.nodeName=n1"},
+			wantLabel: CephClusterLabel + "=ceph,app=osd",
+			wantField: "spec.nodeName=n1,status.phase=Running",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.selector.apply(tt.opts)
+			if got.LabelSelector != tt.wantLabel || got.FieldSelector != tt.wantField {
+				t.Errorf("apply() = %q/%q, want %q/%q", got.LabelSelector, got.FieldSelector, tt.wantLabel, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestListSelector_Validate(t *testing.T) {
+	tests := []struct {
+		selector ListSelector
+		wantErr  bool
+	}{
+		{ListSelector{}, false},
+		{ListSelector{Label: "app=rook-ceph-osd,!ceph-osd-id"}, false},
+		{ListSelector{Field: "spec.unschedulable!=true"}, false},
+		{ListSelector{Label: "app in (osd"}, true},
+		{ListSelector{Field: "spec.nodeName"}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.selector.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.selector, err, tt.wantErr)
+		}
+	}
+}
+
+func TestListNodesWithCephPodsMatching(t *testing.T) {
+	clientset := fake.NewClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "zone-a-1", Labels: map[string]string{"zone": "a"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "zone-b-1", Labels: map[string]string{"zone": "b"}}},
+	)
+	var fieldSelectors []string
+	clientset.PrependReactor("list", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		fieldSelectors = append(fieldSelectors, action.(k8stesting.ListAction).GetListRestrictions().Fields.String())
+		return false, nil, nil
+	})
+	client := newClientFromClientset(clientset)
+
+	nodes, err := client.ListNodesWithCephPodsMatching(context.Background(), "rook-ceph", ListSelector{Label: "zone=a", Field: "spec.unschedulable=false"})
+	if err != nil {
+		t.Fatalf("ListNodesWithCephPodsMatching() error = %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Name != "zone-a-1" {
+		t.Errorf("nodes = %+v, want only zone-a-1", nodes)
+	}
+	// The fake clientset ignores field selectors; check the server gets it
+	if len(fieldSelectors) != 1 || fieldSelectors[0] != "spec.unschedulable=false" {
+		t.Errorf("field selectors sent = %q", fieldSelectors)
+	}
+}
@@ -0,0 +1,187 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SmokeTestLabelValue is the app.kubernetes.io/name of smoke test PVCs and pods
+const SmokeTestLabelValue = "crook-smoke-test"
+
+// smokeTestContainerName is the name of the container writing and reading
+// the test file
+const smokeTestContainerName = "smoke-test"
+
+// smokeTestScript writes a token to the volume, flushes it and reads it back
+const smokeTestScript = `echo "$TOKEN" > /data/crook-smoke-test && sync && [ "$(cat /data/crook-smoke-test)" = "$TOKEN" ]`
+
+// SmokeTestOptions describes a storage smoke test: a PVC of the storage
+// class and a pod on the node that writes to it and reads it back
+type SmokeTestOptions struct {
+	// Namespace to create the PVC and the pod in
+	Namespace string
+
+	// Name of both the PVC and the pod
+	Name string
+
+	// StorageClass provisions the PVC
+	StorageClass string
+
+	// NodeName is the node the pod must run on
+	NodeName string
+
+	// Image runs the pod; it needs sh, cat and sync
+	Image string
+
+	// Size is the capacity requested by the PVC
+	Size resource.Quantity
+}
+
+// BuildSmokeTestPVC returns the PVC of a smoke test
+func BuildSmokeTestPVC(opts SmokeTestOptions) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: smokeTestMeta(opts),
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: &opts.StorageClass,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: opts.Size},
+			},
+		},
+	}
+}
+
+// BuildSmokeTestPod returns the pod of a smoke test. It is pinned to the
+// node by node affinity rather than spec.nodeName, so the scheduler still
+// triggers the provisioning of WaitForFirstConsumer classes.
+func BuildSmokeTestPod(opts SmokeTestOptions) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: smokeTestMeta(opts),
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchFields: []corev1.NodeSelectorRequirement{{
+								Key:      "metadata.name",
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{opts.NodeName},
+							}},
+						}},
+					},
+				},
+			},
+			Containers: []corev1.Container{{
+				Name:         smokeTestContainerName,
+				Image:        opts.Image,
+				Command:      []string{"sh", "-c", smokeTestScript},
+				Env:          []corev1.EnvVar{{Name: "TOKEN", Value: opts.Name}},
+				VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: opts.Name},
+				},
+			}},
+		},
+	}
+}
+
+func smokeTestMeta(opts SmokeTestOptions) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      opts.Name,
+		Namespace: opts.Namespace,
+		Labels: map[string]string{
+			JobLabelName: SmokeTestLabelValue,
+			JobLabelNode: opts.NodeName,
+		},
+	}
+}
+
+// CreateSmokeTest creates the PVC and the pod of a smoke test
+func (c *Client) CreateSmokeTest(ctx context.Context, opts SmokeTestOptions) error {
+	_, err := c.Clientset.CoreV1().PersistentVolumeClaims(opts.Namespace).Create(ctx, BuildSmokeTestPVC(opts), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create PVC %s/%s: %w", opts.Namespace, opts.Name, err)
+	}
+	_, err = c.Clientset.CoreV1().Pods(opts.Namespace).Create(ctx, BuildSmokeTestPod(opts), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create pod %s/%s: %w", opts.Namespace, opts.Name, err)
+	}
+	return nil
+}
+
+// WaitForSmokeTest waits for the smoke test pod to finish. It fails when the
+// pod fails, with the container's termination reason.
+func (c *Client) WaitForSmokeTest(ctx context.Context, namespace, name string, pollInterval time.Duration) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		pod, err := c.Clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get pod %s/%s: %w", namespace, name, err)
+		}
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded:
+			return nil
+		case corev1.PodFailed:
+			return fmt.Errorf("pod %s/%s failed: %s", namespace, name, smokeTestFailure(pod))
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for pod %s/%s (%s): %w", namespace, name, smokeTestPending(pod), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// smokeTestFailure describes why a failed smoke test pod failed
+func smokeTestFailure(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if t := status.State.Terminated; t != nil {
+			return fmt.Sprintf("exit code %d (%s)", t.ExitCode, t.Reason)
+		}
+	}
+	return pod.Status.Reason
+}
+
+// smokeTestPending describes where an unfinished smoke test pod is stuck,
+// e.g. waiting for its volume to be provisioned or attached
+func smokeTestPending(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if w := status.State.Waiting; w != nil {
+			return w.Reason
+		}
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue && condition.Message != "" {
+			return condition.Message
+		}
+	}
+	return string(pod.Status.Phase)
+}
+
+// DeleteSmokeTest deletes the pod and the PVC of a smoke test; missing
+// objects are ignored
+func (c *Client) DeleteSmokeTest(ctx context.Context, namespace, name string) error {
+	zero := int64(0)
+	err := c.Clientset.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{GracePeriodSeconds: &zero})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete pod %s/%s: %w", namespace, name, err)
+	}
+	err = c.Clientset.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete PVC %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
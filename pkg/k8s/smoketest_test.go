@@ -0,0 +1,114 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func testSmokeTestOptions() SmokeTestOptions {
+	return SmokeTestOptions{
+		Namespace:    "rook-ceph",
+		Name:         "crook-smoke-test-abcde",
+		StorageClass: "ceph-block",
+		NodeName:     "worker-1",
+		Image:        "busybox:1.36",
+		Size:         resource.MustParse("1Gi"),
+	}
+}
+
+func TestBuildSmokeTest(t *testing.T) {
+	opts := testSmokeTestOptions()
+
+	pvc := BuildSmokeTestPVC(opts)
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != "ceph-block" {
+		t.Errorf("PVC storage class = %v, want ceph-block", pvc.Spec.StorageClassName)
+	}
+	if got := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; got.String() != "1Gi" {
+		t.Errorf("PVC size = %s, want 1Gi", got.String())
+	}
+
+	pod := BuildSmokeTestPod(opts)
+	if pod.Spec.NodeName != "" {
+		t.Errorf("pod NodeName = %q, want it scheduled by affinity", pod.Spec.NodeName)
+	}
+	term := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0]
+	if len(term.MatchFields) != 1 || term.MatchFields[0].Key != "metadata.name" || term.MatchFields[0].Values[0] != "worker-1" {
+		t.Errorf("pod node affinity = %+v, want metadata.name in [worker-1]", term)
+	}
+	if claim := pod.Spec.Volumes[0].PersistentVolumeClaim; claim == nil || claim.ClaimName != opts.Name {
+		t.Errorf("pod volume = %+v, want the PVC %s", pod.Spec.Volumes[0], opts.Name)
+	}
+	if pod.Labels[JobLabelName] != SmokeTestLabelValue || pod.Labels[JobLabelNode] != "worker-1" {
+		t.Errorf("pod labels = %v", pod.Labels)
+	}
+}
+
+func TestSmokeTestLifecycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  corev1.PodStatus
+		wantErr string
+	}{
+		{name: "succeeded", status: corev1.PodStatus{Phase: corev1.PodSucceeded}},
+		{
+			name: "failed",
+			status: corev1.PodStatus{Phase: corev1.PodFailed, ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  smokeTestContainerName,
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+			}}},
+			wantErr: "exit code 1 (Error)",
+		},
+		{
+			name: "volume never attaches",
+			status: corev1.PodStatus{Phase: corev1.PodPending, ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  smokeTestContainerName,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+			}}},
+			wantErr: "ContainerCreating",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testSmokeTestOptions()
+			clientset := fake.NewClientset()
+			clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+				pod.Status = tt.status
+				return false, nil, nil
+			})
+			client := newClientFromClientset(clientset)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			if err := client.CreateSmokeTest(ctx, opts); err != nil {
+				t.Fatalf("CreateSmokeTest() error = %v", err)
+			}
+			err := client.WaitForSmokeTest(ctx, opts.Namespace, opts.Name, time.Millisecond)
+			if (err != nil) != (tt.wantErr != "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("WaitForSmokeTest() error = %v, want %q", err, tt.wantErr)
+			}
+
+			if err := client.DeleteSmokeTest(context.Background(), opts.Namespace, opts.Name); err != nil {
+				t.Fatalf("DeleteSmokeTest() error = %v", err)
+			}
+			pvcs, _ := clientset.CoreV1().PersistentVolumeClaims(opts.Namespace).List(context.Background(), metav1.ListOptions{})
+			pods, _ := clientset.CoreV1().Pods(opts.Namespace).List(context.Background(), metav1.ListOptions{})
+			if len(pvcs.Items) != 0 || len(pods.Items) != 0 {
+				t.Errorf("left %d PVC(s) and %d pod(s) behind", len(pvcs.Items), len(pods.Items))
+			}
+			if err := client.DeleteSmokeTest(context.Background(), opts.Namespace, opts.Name); err != nil {
+				t.Errorf("DeleteSmokeTest() of a removed test error = %v", err)
+			}
+		})
+	}
+}
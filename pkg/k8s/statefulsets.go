@@ -0,0 +1,100 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScaleStatefulSet scales a StatefulSet to the specified number of replicas.
+// Like ScaleDeployment it uses the /scale subresource, so only the
+// statefulsets/scale permission is required.
+func (c *Client) ScaleStatefulSet(ctx context.Context, namespace, name string, replicas int32) error {
+	return c.ScaleWorkload(ctx, WorkloadKindStatefulSet, namespace, name, replicas)
+}
+
+// GetStatefulSetStatus returns the status of a StatefulSet in the same shape
+// as GetDeploymentStatus, so the wait helpers can poll either kind
+func (c *Client) GetStatefulSetStatus(ctx context.Context, namespace, name string) (*DeploymentStatus, error) {
+	sts, err := c.Clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statefulset %s/%s: %w", namespace, name, err)
+	}
+
+	replicas := int32(0)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	return &DeploymentStatus{
+		Name:              sts.Name,
+		Namespace:         sts.Namespace,
+		Replicas:          replicas,
+		ReadyReplicas:     sts.Status.ReadyReplicas,
+		AvailableReplicas: sts.Status.AvailableReplicas,
+		UpdatedReplicas:   sts.Status.UpdatedReplicas,
+	}, nil
+}
+
+// ListStatefulSetsInNamespace returns all StatefulSets in a namespace, limited
+// to the selected CephCluster if any
+func (c *Client) ListStatefulSetsInNamespace(ctx context.Context, namespace string) ([]appsv1.StatefulSet, error) {
+	list, err := c.Clientset.AppsV1().StatefulSets(namespace).List(ctx, c.cephClusterListOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets in namespace %s: %w", namespace, err)
+	}
+
+	return list.Items, nil
+}
+
+// GetStatefulSetTargetNode returns the node a StatefulSet's pods are pinned
+// to, read from the same nodeSelector and nodeAffinity keys as deployments.
+// Returns empty string if the StatefulSet is not node-pinned.
+func GetStatefulSetTargetNode(sts *appsv1.StatefulSet) string {
+	node, _ := podSpecPinSource(&sts.Spec.Template.Spec)
+	return node
+}
+
+// ListNodePinnedStatefulSets returns StatefulSets pinned to a specific node.
+// Works regardless of replica count - examines the spec, not running pods.
+func (c *Client) ListNodePinnedStatefulSets(
+	ctx context.Context,
+	namespace string,
+	nodeName string,
+) ([]appsv1.StatefulSet, error) {
+	statefulSets, err := c.ListStatefulSetsInNamespace(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+
+	var pinned []appsv1.StatefulSet
+	for i := range statefulSets {
+		if GetStatefulSetTargetNode(&statefulSets[i]) == nodeName {
+			pinned = append(pinned, statefulSets[i])
+		}
+	}
+	return pinned, nil
+}
+
+// ListScaledDownStatefulSetsForNode returns node-pinned StatefulSets with 0
+// replicas. Used during the up phase to discover StatefulSets to restore.
+func (c *Client) ListScaledDownStatefulSetsForNode(
+	ctx context.Context,
+	namespace string,
+	nodeName string,
+) ([]appsv1.StatefulSet, error) {
+	pinned, err := c.ListNodePinnedStatefulSets(ctx, namespace, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	var scaledDown []appsv1.StatefulSet
+	for _, sts := range pinned {
+		if sts.Spec.Replicas != nil && *sts.Spec.Replicas == 0 {
+			scaledDown = append(scaledDown, sts)
+		}
+	}
+	return scaledDown, nil
+}
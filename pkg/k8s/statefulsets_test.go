@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func pinnedStatefulSet(name, node string, replicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{NodeSelector: map[string]string{"kubernetes.io/hostname": node}},
+			},
+		},
+		Status: appsv1.StatefulSetStatus{ReadyReplicas: replicas},
+	}
+}
+
+func TestListNodePinnedStatefulSets(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewClientset(
+		pinnedStatefulSet("rook-ceph-rgw-store-a", "worker-1", 1),
+		pinnedStatefulSet("metrics-agent", "worker-1", 0),
+		pinnedStatefulSet("rook-ceph-rgw-store-b", "worker-2", 1),
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "unpinned", Namespace: "rook-ceph"}},
+	)
+	client := newClientFromClientset(clientset)
+
+	pinned, err := client.ListNodePinnedStatefulSets(ctx, "rook-ceph", "worker-1")
+	if err != nil {
+		t.Fatalf("ListNodePinnedStatefulSets() error = %v", err)
+	}
+	if len(pinned) != 2 {
+		t.Fatalf("ListNodePinnedStatefulSets() returned %d statefulsets, want 2", len(pinned))
+	}
+
+	scaledDown, err := client.ListScaledDownStatefulSetsForNode(ctx, "rook-ceph", "worker-1")
+	if err != nil {
+		t.Fatalf("ListScaledDownStatefulSetsForNode() error = %v", err)
+	}
+	if len(scaledDown) != 1 || scaledDown[0].Name != "metrics-agent" {
+		t.Errorf("ListScaledDownStatefulSetsForNode() = %v, want only metrics-agent", scaledDown)
+	}
+}
+
+func TestScaleStatefulSet(t *testing.T) {
+	ctx := context.Background()
+	sts := pinnedStatefulSet("rook-ceph-rgw-store-a", "worker-1", 1)
+	clientset := fake.NewClientset(sts)
+
+	replicas := int32(1)
+	statefulSetReplicas := map[string]*int32{"rook-ceph/rook-ceph-rgw-store-a": &replicas}
+	addResourceScaleReactors(clientset, "statefulsets", statefulSetReplicas)
+
+	client := newClientFromClientset(clientset)
+
+	if err := client.ScaleStatefulSet(ctx, "rook-ceph", "rook-ceph-rgw-store-a", 0); err != nil {
+		t.Fatalf("ScaleStatefulSet() error = %v", err)
+	}
+	if got := *statefulSetReplicas["rook-ceph/rook-ceph-rgw-store-a"]; got != 0 {
+		t.Errorf("replicas = %d, want 0", got)
+	}
+
+	if err := client.ScaleStatefulSet(ctx, "rook-ceph", "missing", 0); err == nil {
+		t.Error("expected error when scaling a nonexistent statefulset, got nil")
+	}
+}
+
+func TestGetStatefulSetStatus(t *testing.T) {
+	client := newClientFromClientset(fake.NewClientset(pinnedStatefulSet("rook-ceph-rgw-store-a", "worker-1", 1)))
+
+	status, err := client.GetStatefulSetStatus(context.Background(), "rook-ceph", "rook-ceph-rgw-store-a")
+	if err != nil {
+		t.Fatalf("GetStatefulSetStatus() error = %v", err)
+	}
+	if status.Replicas != 1 || status.ReadyReplicas != 1 {
+		t.Errorf("status = %+v, want 1 replica, 1 ready", status)
+	}
+
+	if _, err := client.GetStatefulSetStatus(context.Background(), "rook-ceph", "missing"); err == nil {
+		t.Error("expected error for a nonexistent statefulset, got nil")
+	}
+}
@@ -0,0 +1,235 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// hostnameLabel is the node label local and topology-pinned PVs select on
+const hostnameLabel = "kubernetes.io/hostname"
+
+// VolumeInfo is a persistent volume tied to a node, by a VolumeAttachment to
+// it or by a node affinity that pins the volume to it
+type VolumeInfo struct {
+	// PersistentVolume is the PV name
+	PersistentVolume string `json:"persistent_volume"`
+
+	// Claim is the bound PVC as "namespace/name", empty when unbound
+	Claim string `json:"claim,omitempty"`
+
+	// StorageClass is the PV's storage class
+	StorageClass string `json:"storage_class,omitempty"`
+
+	// Driver is the CSI driver attaching the volume, e.g.
+	// rook-ceph.rbd.csi.ceph.com
+	Driver string `json:"driver,omitempty"`
+
+	// Attachment is the VolumeAttachment name, empty for a volume only
+	// pinned to the node by its node affinity
+	Attachment string `json:"attachment,omitempty"`
+
+	// Attached is the attachment's status.attached
+	Attached bool `json:"attached"`
+
+	// Detaching is set when the attachment was deleted but the driver has
+	// not confirmed the detach yet
+	Detaching bool `json:"detaching,omitempty"`
+
+	// Error is the attachment's last attach or detach error
+	Error string `json:"error,omitempty"`
+
+	// NodeAffinity is set when the PV's node affinity pins it to the node
+	NodeAffinity bool `json:"node_affinity,omitempty"`
+
+	// InUse is set when a pod scheduled to the node mounts the claim
+	InUse bool `json:"in_use"`
+}
+
+// Ceph reports whether a Ceph CSI driver serves the volume
+func (v VolumeInfo) Ceph() bool {
+	return IsCephCSIDriver(v.Driver)
+}
+
+// IsCephCSIDriver reports whether a CSI driver name is one of Ceph CSI's RBD
+// or CephFS drivers, e.g. rook-ceph.rbd.csi.ceph.com
+func IsCephCSIDriver(driver string) bool {
+	return strings.HasSuffix(driver, ".rbd.csi.ceph.com") || strings.HasSuffix(driver, ".cephfs.csi.ceph.com")
+}
+
+// ListNodeVolumes returns the volumes tied to the node: every VolumeAttachment
+// to it, and the PVs whose node affinity pins them to it. Each is marked in
+// use when a pod scheduled to the node mounts its claim. The result is sorted
+// by PV name.
+func (c *Client) ListNodeVolumes(ctx context.Context, nodeName string) ([]VolumeInfo, error) {
+	node, err := c.GetNode(ctx, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	hostname := node.Labels[hostnameLabel]
+	if hostname == "" {
+		hostname = nodeName
+	}
+
+	pvList, err := listProjected(ctx, metav1.ListOptions{}, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.PersistentVolume, metav1.ListMeta, error) {
+		list, err := c.Clientset.CoreV1().PersistentVolumes().List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return list.Items, list.ListMeta, nil
+	}, keep[corev1.PersistentVolume])
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volumes: %w", err)
+	}
+	pvs := make(map[string]*corev1.PersistentVolume, len(pvList))
+	for i := range pvList {
+		pvs[pvList[i].Name] = &pvList[i]
+	}
+
+	attachments, err := listProjected(ctx, metav1.ListOptions{}, func(ctx context.Context, opts metav1.ListOptions) ([]storagev1.VolumeAttachment, metav1.ListMeta, error) {
+		list, err := c.Clientset.StorageV1().VolumeAttachments().List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return list.Items, list.ListMeta, nil
+	}, func(attachment *storagev1.VolumeAttachment) (storagev1.VolumeAttachment, bool) {
+		return *attachment, attachment.Spec.NodeName == nodeName
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume attachments: %w", err)
+	}
+
+	claims, err := c.nodeClaims(ctx, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []VolumeInfo
+	seen := map[string]bool{}
+	for i := range attachments {
+		volume := volumeFromAttachment(&attachments[i])
+		if pv := pvs[volume.PersistentVolume]; pv != nil {
+			describePV(&volume, pv, nodeName, hostname)
+		}
+		volume.InUse = volume.Claim != "" && claims[volume.Claim]
+		volumes = append(volumes, volume)
+		seen[volume.PersistentVolume] = true
+	}
+	for _, pv := range pvs {
+		if seen[pv.Name] || !pvPinnedToNode(pv, nodeName, hostname) {
+			continue
+		}
+		volume := VolumeInfo{PersistentVolume: pv.Name}
+		if pv.Spec.CSI != nil {
+			volume.Driver = pv.Spec.CSI.Driver
+		}
+		describePV(&volume, pv, nodeName, hostname)
+		volume.InUse = volume.Claim != "" && claims[volume.Claim]
+		volumes = append(volumes, volume)
+	}
+
+	slices.SortFunc(volumes, func(a, b VolumeInfo) int {
+		return strings.Compare(a.PersistentVolume, b.PersistentVolume)
+	})
+	return volumes, nil
+}
+
+// volumeFromAttachment describes a VolumeAttachment; inline volumes without a
+// PV are named by their attachment
+func volumeFromAttachment(attachment *storagev1.VolumeAttachment) VolumeInfo {
+	volume := VolumeInfo{
+		PersistentVolume: attachment.Name,
+		Driver:           attachment.Spec.Attacher,
+		Attachment:       attachment.Name,
+		Attached:         attachment.Status.Attached,
+		Detaching:        attachment.DeletionTimestamp != nil,
+	}
+	if name := attachment.Spec.Source.PersistentVolumeName; name != nil {
+		volume.PersistentVolume = *name
+	}
+	switch {
+	case attachment.Status.DetachError != nil:
+		volume.Error = attachment.Status.DetachError.Message
+	case attachment.Status.AttachError != nil:
+		volume.Error = attachment.Status.AttachError.Message
+	}
+	return volume
+}
+
+// describePV fills in the claim, storage class and node affinity of a volume
+func describePV(volume *VolumeInfo, pv *corev1.PersistentVolume, nodeName, hostname string) {
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		volume.Claim = ref.Namespace + "/" + ref.Name
+	}
+	volume.StorageClass = pv.Spec.StorageClassName
+	volume.NodeAffinity = pvPinnedToNode(pv, nodeName, hostname)
+}
+
+// pvPinnedToNode reports whether a term of the PV's required node affinity
+// selects the node by its hostname label or its name
+func pvPinnedToNode(pv *corev1.PersistentVolume, nodeName, hostname string) bool {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return false
+	}
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == hostnameLabel && expr.Operator == corev1.NodeSelectorOpIn && slices.Contains(expr.Values, hostname) {
+				return true
+			}
+		}
+		for _, field := range term.MatchFields {
+			if field.Key == "metadata.name" && field.Operator == corev1.NodeSelectorOpIn && slices.Contains(field.Values, nodeName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nodeClaims returns the claims, as "namespace/name", mounted by the pods
+// scheduled to the node that have not terminated, including the claims of
+// their generic ephemeral volumes
+func (c *Client) nodeClaims(ctx context.Context, nodeName string) (map[string]bool, error) {
+	pods, err := c.listPods(ctx, metav1.NamespaceAll, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %w", nodeName, err)
+	}
+
+	claims := map[string]bool{}
+	for i := range pods {
+		// Field selectors are not applied by every client, e.g. fakes
+		if pods[i].Spec.NodeName != nodeName {
+			continue
+		}
+		for _, claim := range podClaims(&pods[i]) {
+			claims[claim] = true
+		}
+	}
+	return claims, nil
+}
+
+// podClaims returns the claims, as "namespace/name", a pod that has not
+// terminated mounts, including the claims of its generic ephemeral volumes
+func podClaims(pod *corev1.Pod) []string {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return nil
+	}
+	var claims []string
+	for _, volume := range pod.Spec.Volumes {
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			claims = append(claims, pod.Namespace+"/"+volume.PersistentVolumeClaim.ClaimName)
+		case volume.Ephemeral != nil:
+			claims = append(claims, pod.Namespace+"/"+pod.Name+"-"+volume.Name)
+		}
+	}
+	return claims
+}
@@ -0,0 +1,100 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestListNodeVolumes(t *testing.T) {
+	const rbd = "rook-ceph.rbd.csi.ceph.com"
+	deleted := metav1.Now()
+
+	pv := func(name, claim string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				StorageClassName: "ceph-block",
+				ClaimRef:         &corev1.ObjectReference{Namespace: "apps", Name: claim},
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: rbd},
+				},
+			},
+		}
+	}
+	attachment := func(name, pvName, node string, attached bool) *storagev1.VolumeAttachment {
+		return &storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: rbd,
+				NodeName: node,
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+			},
+			Status: storagev1.VolumeAttachmentStatus{Attached: attached},
+		}
+	}
+
+	local := pv("pv-local", "cache")
+	local.Spec.CSI = nil
+	local.Spec.StorageClassName = "local"
+	local.Spec.NodeAffinity = &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{
+		NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: hostnameLabel, Operator: corev1.NodeSelectorOpIn, Values: []string{"worker-1.example.com"}},
+		}}},
+	}}
+	stuck := attachment("csi-stuck", "pv-stuck", "worker-1", true)
+	stuck.Status.DetachError = &storagev1.VolumeError{Message: "rbd: unmap failed"}
+	detaching := attachment("csi-detaching", "pv-detaching", "worker-1", true)
+	detaching.DeletionTimestamp = &deleted
+	detaching.Finalizers = []string{"external-attacher/" + rbd}
+
+	clientset := fake.NewClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{hostnameLabel: "worker-1.example.com"}}},
+		pv("pv-used", "db"), pv("pv-stuck", "old"), pv("pv-detaching", "gone"), pv("pv-elsewhere", "web"), local,
+		attachment("csi-used", "pv-used", "worker-1", true), stuck, detaching,
+		attachment("csi-elsewhere", "pv-elsewhere", "worker-2", true),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "apps"},
+			Spec: corev1.PodSpec{NodeName: "worker-1", Volumes: []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "db"}},
+			}}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "apps"},
+			Spec: corev1.PodSpec{NodeName: "worker-1", Volumes: []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "old"}},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+	)
+	client := newClientFromClientset(clientset)
+
+	volumes, err := client.ListNodeVolumes(context.Background(), "worker-1")
+	if err != nil {
+		t.Fatalf("ListNodeVolumes() error = %v", err)
+	}
+
+	want := []VolumeInfo{
+		{PersistentVolume: "pv-detaching", Claim: "apps/gone", StorageClass: "ceph-block", Driver: rbd, Attachment: "csi-detaching", Attached: true, Detaching: true},
+		{PersistentVolume: "pv-local", Claim: "apps/cache", StorageClass: "local", NodeAffinity: true},
+		{PersistentVolume: "pv-stuck", Claim: "apps/old", StorageClass: "ceph-block", Driver: rbd, Attachment: "csi-stuck", Attached: true, Error: "rbd: unmap failed"},
+		{PersistentVolume: "pv-used", Claim: "apps/db", StorageClass: "ceph-block", Driver: rbd, Attachment: "csi-used", Attached: true, InUse: true},
+	}
+	if len(volumes) != len(want) {
+		t.Fatalf("ListNodeVolumes() = %+v, want %d volumes", volumes, len(want))
+	}
+	for i := range want {
+		if volumes[i] != want[i] {
+			t.Errorf("volume %d = %+v, want %+v", i, volumes[i], want[i])
+		}
+	}
+	if !volumes[0].Ceph() || volumes[1].Ceph() {
+		t.Errorf("Ceph() = %v, %v; want true for the RBD volume only", volumes[0].Ceph(), volumes[1].Ceph())
+	}
+}
@@ -0,0 +1,280 @@
+package maintenance
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Approval storage: one ConfigMap per request ('crook down --request'), so the
+// requester and the approver only need access to the cluster
+const (
+	approvalConfigMapPrefix = "crook-approval-"
+	approvalDataKey         = "request.json"
+	approvalLabelNode       = "crook.io/approval-node"
+
+	// ApprovalValidity is how long a request stays valid without being used
+	ApprovalValidity = 24 * time.Hour
+)
+
+// Approval request states
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+	ApprovalUsed     = "used"
+	ApprovalExpired  = "expired"
+)
+
+// Approval errors
+var (
+	// ErrApprovalRequired is returned by the down phase when
+	// policy.require-approval-before-down is set and no approved request was given
+	ErrApprovalRequired = errors.New("the down phase requires an approved request (crook down --request)")
+
+	// ErrApprovalNotFound is returned for an unknown request ID
+	ErrApprovalNotFound = errors.New("approval request not found")
+
+	// ErrSelfApproval is returned when the requester tries to approve their own request
+	ErrSelfApproval = errors.New("a request must be approved by someone other than its requester")
+)
+
+// ApprovalRequest is a pending or decided request to take a node down under
+// the two-person rule: one user requests, a second one approves. Requester and
+// Approver are the users the API server authenticated, not local OS users, so
+// operators sharing a bastion account are still told apart.
+type ApprovalRequest struct {
+	ID        string    `json:"id"`
+	Node      string    `json:"node"`
+	Requester string    `json:"requester"`
+	Requested time.Time `json:"requested"`
+	SignOff   *SignOff  `json:"sign_off,omitempty"`
+
+	// Approver approved or rejected the request at Decided
+	Approver string     `json:"approver,omitempty"`
+	Decided  *time.Time `json:"decided,omitempty"`
+	Rejected bool       `json:"rejected,omitempty"`
+
+	// Used is when the down phase consumed the approval; a request is used once
+	Used *time.Time `json:"used,omitempty"`
+}
+
+// Status returns the state of the request at now
+func (r *ApprovalRequest) Status(now time.Time) string {
+	switch {
+	case r.Used != nil:
+		return ApprovalUsed
+	case r.Rejected:
+		return ApprovalRejected
+	case now.Sub(r.Requested) > ApprovalValidity:
+		return ApprovalExpired
+	case r.Decided != nil:
+		return ApprovalApproved
+	default:
+		return ApprovalPending
+	}
+}
+
+// approvalConfigMapName returns the name of the ConfigMap holding a request
+func approvalConfigMapName(id string) string {
+	return approvalConfigMapPrefix + id
+}
+
+// newApprovalID returns a short random request ID, e.g. "3f9a1c2e"
+func newApprovalID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate approval request ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CreateApprovalRequest records a pending request by the authenticated user to
+// take the node down
+func CreateApprovalRequest(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, signOff SignOff) (*ApprovalRequest, error) {
+	requester, err := client.AuthenticatedUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to identify the requester: %w", err)
+	}
+	id, err := newApprovalID()
+	if err != nil {
+		return nil, err
+	}
+
+	request := &ApprovalRequest{
+		ID:        id,
+		Node:      nodeName,
+		Requester: requester,
+		Requested: time.Now().UTC(),
+	}
+	if !signOff.IsZero() {
+		request.SignOff = &signOff
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode approval request: %w", err)
+	}
+	err = client.ApplyConfigMap(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      approvalConfigMapName(id),
+			Namespace: cfg.Namespace,
+			Labels: map[string]string{
+				k8s.JobLabelName:  "crook",
+				approvalLabelNode: nodeName,
+			},
+		},
+		Data: map[string]string{approvalDataKey: string(data)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// LoadApprovalRequest returns the request with the given ID
+func LoadApprovalRequest(ctx context.Context, client *k8s.Client, cfg config.Config, id string) (*ApprovalRequest, error) {
+	request, _, err := loadApprovalRequest(ctx, client, cfg, id)
+	return request, err
+}
+
+// loadApprovalRequest returns the request and the ConfigMap holding it, so
+// updates fail when someone else changed the request in the meantime
+func loadApprovalRequest(ctx context.Context, client *k8s.Client, cfg config.Config, id string) (*ApprovalRequest, *corev1.ConfigMap, error) {
+	cm, err := client.GetConfigMap(ctx, cfg.Namespace, approvalConfigMapName(id))
+	if apierrors.IsNotFound(err) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrApprovalNotFound, id)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var request ApprovalRequest
+	if err := json.Unmarshal([]byte(cm.Data[approvalDataKey]), &request); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse approval request %s: %w", id, err)
+	}
+	return &request, cm, nil
+}
+
+// updateApprovalRequest stores a request loaded with loadApprovalRequest
+func updateApprovalRequest(ctx context.Context, client *k8s.Client, cm *corev1.ConfigMap, request *ApprovalRequest) error {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode approval request: %w", err)
+	}
+	cm.Data = map[string]string{approvalDataKey: string(data)}
+	return client.UpdateConfigMap(ctx, cm)
+}
+
+// ListApprovalRequests returns every recorded request, oldest first
+func ListApprovalRequests(ctx context.Context, client *k8s.Client, cfg config.Config) ([]ApprovalRequest, error) {
+	configMaps, err := client.ListConfigMaps(ctx, cfg.Namespace, approvalLabelNode)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]ApprovalRequest, 0, len(configMaps))
+	for i := range configMaps {
+		var request ApprovalRequest
+		if err := json.Unmarshal([]byte(configMaps[i].Data[approvalDataKey]), &request); err != nil {
+			continue
+		}
+		requests = append(requests, request)
+	}
+	slices.SortFunc(requests, func(a, b ApprovalRequest) int {
+		return cmp.Compare(a.Requested.UnixNano(), b.Requested.UnixNano())
+	})
+	return requests, nil
+}
+
+// DecideApprovalRequest approves, or with reject set rejects, a pending
+// request as the authenticated user, who must differ from the requester
+func DecideApprovalRequest(ctx context.Context, client *k8s.Client, cfg config.Config, id string, reject bool) (*ApprovalRequest, error) {
+	approver, err := client.AuthenticatedUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to identify the approver: %w", err)
+	}
+	request, cm, err := loadApprovalRequest(ctx, client, cfg, id)
+	if err != nil {
+		return nil, err
+	}
+	if status := request.Status(time.Now()); status != ApprovalPending {
+		return nil, fmt.Errorf("approval request %s is %s", id, status)
+	}
+	if approver == "" || approver == request.Requester {
+		return nil, ErrSelfApproval
+	}
+
+	now := time.Now().UTC()
+	request.Approver = approver
+	request.Decided = &now
+	request.Rejected = reject
+	if err := updateApprovalRequest(ctx, client, cm, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// WaitForApproval polls the request until it is approved. It fails when the
+// request is rejected or expires, or when ctx is done.
+func WaitForApproval(ctx context.Context, client *k8s.Client, cfg config.Config, id string, pollInterval time.Duration) (*ApprovalRequest, error) {
+	if pollInterval == 0 {
+		pollInterval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		request, err := LoadApprovalRequest(ctx, client, cfg, id)
+		if err != nil {
+			return nil, err
+		}
+		switch status := request.Status(time.Now()); status {
+		case ApprovalApproved:
+			return request, nil
+		case ApprovalPending:
+		default:
+			return nil, fmt.Errorf("approval request %s is %s", id, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for approval of request %s: %w", id, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// claimApproval consumes the approved request for the node's down phase. An
+// approval is only valid once.
+func claimApproval(ctx context.Context, client *k8s.Client, cfg config.Config, id, nodeName string) error {
+	if id == "" {
+		return ErrApprovalRequired
+	}
+	request, cm, err := loadApprovalRequest(ctx, client, cfg, id)
+	if err != nil {
+		return err
+	}
+	if request.Node != nodeName {
+		return fmt.Errorf("approval request %s is for node %s, not %s", id, request.Node, nodeName)
+	}
+	if status := request.Status(time.Now()); status != ApprovalApproved {
+		return fmt.Errorf("approval request %s is %s", id, status)
+	}
+
+	now := time.Now().UTC()
+	request.Used = &now
+	return updateApprovalRequest(ctx, client, cm, request)
+}
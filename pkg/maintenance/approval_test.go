@@ -0,0 +1,175 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// authenticatedAs makes the API server authenticate the client as *user
+func authenticatedAs(clientset *fake.Clientset, user *string) {
+	clientset.PrependReactor("create", "selfsubjectreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &authenticationv1.SelfSubjectReview{Status: authenticationv1.SelfSubjectReviewStatus{
+			UserInfo: authenticationv1.UserInfo{Username: *user},
+		}}, nil
+	})
+}
+
+func TestApprovalWorkflow(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewClientset()
+	client := &k8s.Client{Clientset: clientset}
+	cfg := config.DefaultConfig()
+	user := "alice"
+	authenticatedAs(clientset, &user)
+
+	request, err := CreateApprovalRequest(ctx, client, cfg, "worker-1", SignOff{Ticket: "CHG-1"})
+	if err != nil {
+		t.Fatalf("CreateApprovalRequest() error = %v", err)
+	}
+	if got := request.Status(time.Now()); got != ApprovalPending {
+		t.Fatalf("Status() = %s, want %s", got, ApprovalPending)
+	}
+
+	// A pending request cannot be used
+	if err := claimApproval(ctx, client, cfg, request.ID, "worker-1"); err == nil {
+		t.Fatal("claimApproval() of a pending request should fail")
+	}
+
+	if request.Requester != "alice" {
+		t.Fatalf("Requester = %q, want the authenticated user alice", request.Requester)
+	}
+
+	// The requester cannot approve their own request, whatever $USER says
+	t.Setenv("USER", "bob")
+	if _, err := DecideApprovalRequest(ctx, client, cfg, request.ID, false); !errors.Is(err, ErrSelfApproval) {
+		t.Fatalf("DecideApprovalRequest() by the requester error = %v, want ErrSelfApproval", err)
+	}
+
+	user = "bob"
+	approved, err := DecideApprovalRequest(ctx, client, cfg, request.ID, false)
+	if err != nil {
+		t.Fatalf("DecideApprovalRequest() error = %v", err)
+	}
+	if approved.Approver != "bob" || approved.Status(time.Now()) != ApprovalApproved {
+		t.Fatalf("approved request = %+v", approved)
+	}
+
+	waited, err := WaitForApproval(ctx, client, cfg, request.ID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForApproval() error = %v", err)
+	}
+	if waited.SignOff == nil || waited.SignOff.Ticket != "CHG-1" {
+		t.Errorf("SignOff = %+v, want ticket CHG-1", waited.SignOff)
+	}
+
+	// The approval only covers its node, and only once
+	if err := claimApproval(ctx, client, cfg, request.ID, "worker-2"); err == nil {
+		t.Error("claimApproval() for another node should fail")
+	}
+	if err := claimApproval(ctx, client, cfg, request.ID, "worker-1"); err != nil {
+		t.Fatalf("claimApproval() error = %v", err)
+	}
+	if err := claimApproval(ctx, client, cfg, request.ID, "worker-1"); err == nil {
+		t.Error("claimApproval() of a used request should fail")
+	}
+
+	requests, err := ListApprovalRequests(ctx, client, cfg)
+	if err != nil {
+		t.Fatalf("ListApprovalRequests() error = %v", err)
+	}
+	if len(requests) != 1 || requests[0].Status(time.Now()) != ApprovalUsed {
+		t.Errorf("ListApprovalRequests() = %+v, want one used request", requests)
+	}
+}
+
+func TestWaitForApproval_Rejected(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewClientset()
+	client := &k8s.Client{Clientset: clientset}
+	cfg := config.DefaultConfig()
+	user := "alice"
+	authenticatedAs(clientset, &user)
+
+	request, err := CreateApprovalRequest(ctx, client, cfg, "worker-1", SignOff{})
+	if err != nil {
+		t.Fatalf("CreateApprovalRequest() error = %v", err)
+	}
+	user = "bob"
+	if _, err := DecideApprovalRequest(ctx, client, cfg, request.ID, true); err != nil {
+		t.Fatalf("DecideApprovalRequest() error = %v", err)
+	}
+
+	if _, err := WaitForApproval(ctx, client, cfg, request.ID, time.Millisecond); err == nil {
+		t.Fatal("WaitForApproval() of a rejected request should fail")
+	}
+	user = "carol"
+	if _, err := DecideApprovalRequest(ctx, client, cfg, request.ID, false); err == nil {
+		t.Error("a rejected request should not be approved later")
+	}
+}
+
+func TestWaitForApproval_ContextDone(t *testing.T) {
+	clientset := fake.NewClientset()
+	client := &k8s.Client{Clientset: clientset}
+	cfg := config.DefaultConfig()
+	user := "alice"
+	authenticatedAs(clientset, &user)
+
+	request, err := CreateApprovalRequest(context.Background(), client, cfg, "worker-1", SignOff{})
+	if err != nil {
+		t.Fatalf("CreateApprovalRequest() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := WaitForApproval(ctx, client, cfg, request.ID, time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForApproval() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestApprovalRequest_Status(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	decided := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		request ApprovalRequest
+		want    string
+	}{
+		{name: "pending", request: ApprovalRequest{Requested: now.Add(-time.Hour)}, want: ApprovalPending},
+		{name: "approved", request: ApprovalRequest{Requested: now.Add(-2 * time.Hour), Decided: &decided}, want: ApprovalApproved},
+		{name: "rejected", request: ApprovalRequest{Requested: now.Add(-2 * time.Hour), Decided: &decided, Rejected: true}, want: ApprovalRejected},
+		{name: "used", request: ApprovalRequest{Requested: now.Add(-2 * time.Hour), Decided: &decided, Used: &now}, want: ApprovalUsed},
+		{name: "expired", request: ApprovalRequest{Requested: now.Add(-ApprovalValidity - time.Minute), Decided: &decided}, want: ApprovalExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.request.Status(now); got != tt.want {
+				t.Errorf("Status() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimApproval_Required(t *testing.T) {
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+
+	err := claimApproval(context.Background(), client, config.DefaultConfig(), "", "worker-1")
+	if !errors.Is(err, ErrApprovalRequired) {
+		t.Errorf("claimApproval() error = %v, want ErrApprovalRequired", err)
+	}
+	err = claimApproval(context.Background(), client, config.DefaultConfig(), "deadbeef", "worker-1")
+	if !errors.Is(err, ErrApprovalNotFound) {
+		t.Errorf("claimApproval() error = %v, want ErrApprovalNotFound", err)
+	}
+}
@@ -0,0 +1,180 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// PoolImpact describes a pool left without redundancy margin while a node's
+// OSDs are offline
+type PoolImpact struct {
+	Pool          string
+	Size          int
+	MinSize       int
+	FailureDomain string
+
+	// Remaining is the worst-case number of replicas (or EC shards) left for a PG
+	Remaining int
+}
+
+// Unavailable reports whether affected PGs drop below min_size and block I/O
+func (p PoolImpact) Unavailable() bool {
+	return p.Remaining < p.MinSize
+}
+
+// String returns a human-readable description of the impact
+func (p PoolImpact) String() string {
+	if p.Unavailable() {
+		return fmt.Sprintf("pool %s: %d of %d replicas left, below min_size %d - I/O to affected PGs blocks",
+			p.Pool, p.Remaining, p.Size, p.MinSize)
+	}
+	return fmt.Sprintf("pool %s: %d of %d replicas left at min_size %d - no margin for another failure",
+		p.Pool, p.Remaining, p.Size, p.MinSize)
+}
+
+// CapacityImpact estimates what taking a node's OSDs offline costs the cluster
+type CapacityImpact struct {
+	// OSDs are the names of the node's OSDs in the CRUSH tree
+	OSDs []string
+
+	// RemovedWeight is the CRUSH weight (roughly TiB of raw capacity) of the node's OSDs
+	RemovedWeight float64
+
+	// TotalWeight is the CRUSH weight of all OSDs
+	TotalWeight float64
+
+	// Pools lists the pools left at or below min_size
+	Pools []PoolImpact
+}
+
+// RemovedPercent returns the share of raw capacity that goes offline
+func (c *CapacityImpact) RemovedPercent() float64 {
+	if c.TotalWeight == 0 {
+		return 0
+	}
+	return c.RemovedWeight / c.TotalWeight * 100
+}
+
+// Summary returns a one-line description of the capacity going offline
+func (c *CapacityImpact) Summary() string {
+	if len(c.OSDs) == 0 {
+		return "No OSDs on this node in the CRUSH tree - no capacity goes offline"
+	}
+	return fmt.Sprintf("%d OSD(s) go offline: %.2f TiB raw, %.1f%% of cluster capacity",
+		len(c.OSDs), c.RemovedWeight, c.RemovedPercent())
+}
+
+// HasUnavailablePools reports whether any pool would block I/O
+func (c *CapacityImpact) HasUnavailablePools() bool {
+	for _, pool := range c.Pools {
+		if pool.Unavailable() {
+			return true
+		}
+	}
+	return false
+}
+
+// Placement is the CRUSH hierarchy and pool settings that decide where data
+// lives, read once for the capacity and failure-domain estimates
+type Placement struct {
+	Tree  *k8s.CephOSDTree
+	Pools []k8s.PoolDetail
+	Rules []k8s.CrushRule
+}
+
+// LoadPlacement reads the OSD tree, pools and CRUSH rules
+func LoadPlacement(ctx context.Context, client *k8s.Client, cfg config.Config) (*Placement, error) {
+	tree, err := client.GetOSDTree(ctx, cfg.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	pools, err := client.GetPoolDetails(ctx, cfg.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := client.GetCrushRules(ctx, cfg.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return &Placement{Tree: tree, Pools: pools, Rules: rules}, nil
+}
+
+// CapacityImpact estimates the impact of taking nodeName's OSDs offline
+func (p *Placement) CapacityImpact(nodeName string) *CapacityImpact {
+	return EstimateCapacityImpact(p.Tree, p.Pools, p.Rules, nodeName)
+}
+
+// CrushRisks returns the failure-domain risks of taking nodeName down
+func (p *Placement) CrushRisks(nodeName string) []CrushRisk {
+	return DetectCrushRisks(p.Tree, p.Pools, p.Rules, nodeName)
+}
+
+// EstimateCapacityImpact computes the worst case for each pool: one replica
+// is lost per PG when replicas are spread across hosts or larger buckets, and
+// up to one per OSD on the node when the failure domain is osd.
+func EstimateCapacityImpact(tree *k8s.CephOSDTree, pools []k8s.PoolDetail, rules []k8s.CrushRule, nodeName string) *CapacityImpact {
+	impact := &CapacityImpact{}
+
+	onNode := make(map[int]bool)
+	for _, node := range tree.Nodes {
+		if node.Type == "host" && node.Name == nodeName {
+			for _, id := range node.Children {
+				onNode[id] = true
+			}
+		}
+	}
+
+	// OSD count on the node per device class
+	classes := make(map[string]int)
+	for _, node := range tree.Nodes {
+		if node.Type != "osd" {
+			continue
+		}
+		impact.TotalWeight += node.CrushWeight
+		if onNode[node.ID] {
+			impact.OSDs = append(impact.OSDs, node.Name)
+			impact.RemovedWeight += node.CrushWeight
+			classes[node.DeviceClass]++
+		}
+	}
+	if len(impact.OSDs) == 0 {
+		return impact
+	}
+
+	rulesByID := make(map[int]k8s.CrushRule, len(rules))
+	for _, rule := range rules {
+		rulesByID[rule.ID] = rule
+	}
+
+	for _, pool := range pools {
+		rule := rulesByID[pool.CrushRule]
+		osds := len(impact.OSDs)
+		if class := rule.DeviceClass(); class != "" {
+			osds = classes[class]
+		}
+		if osds == 0 {
+			continue
+		}
+
+		lost := 1
+		if rule.FailureDomain() == "osd" {
+			lost = min(osds, pool.Size)
+		}
+		remaining := pool.Size - lost
+		if remaining > pool.MinSize {
+			continue
+		}
+		impact.Pools = append(impact.Pools, PoolImpact{
+			Pool:          pool.Name,
+			Size:          pool.Size,
+			MinSize:       pool.MinSize,
+			FailureDomain: rule.FailureDomain(),
+			Remaining:     remaining,
+		})
+	}
+
+	return impact
+}
@@ -0,0 +1,97 @@
+package maintenance
+
+import (
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func TestEstimateCapacityImpact(t *testing.T) {
+	tree := &k8s.CephOSDTree{Nodes: []k8s.CephOSDNode{
+		{ID: -2, Name: "node-a", Type: "host", Children: []int{0, 1}},
+		{ID: -3, Name: "node-b", Type: "host", Children: []int{2, 3}},
+		{ID: 0, Name: "osd.0", Type: "osd", CrushWeight: 1, DeviceClass: "ssd"},
+		{ID: 1, Name: "osd.1", Type: "osd", CrushWeight: 1, DeviceClass: "hdd"},
+		{ID: 2, Name: "osd.2", Type: "osd", CrushWeight: 1, DeviceClass: "ssd"},
+		{ID: 3, Name: "osd.3", Type: "osd", CrushWeight: 1, DeviceClass: "hdd"},
+	}}
+	rules := []k8s.CrushRule{
+		{ID: 0, Name: "by-host", Steps: []k8s.CrushRuleStep{
+			{Op: "take", ItemName: "default"},
+			{Op: "chooseleaf_firstn", Type: "host"},
+		}},
+		{ID: 1, Name: "by-osd", Steps: []k8s.CrushRuleStep{
+			{Op: "take", ItemName: "default"},
+			{Op: "choose_firstn", Type: "osd"},
+		}},
+		{ID: 2, Name: "nvme-only", Steps: []k8s.CrushRuleStep{
+			{Op: "take", ItemName: "default~nvme"},
+			{Op: "chooseleaf_firstn", Type: "host"},
+		}},
+	}
+
+	tests := []struct {
+		name          string
+		pool          k8s.PoolDetail
+		wantRemaining int
+		wantListed    bool
+		wantBlocked   bool
+	}{
+		{
+			name: "replicated across hosts keeps margin",
+			pool: k8s.PoolDetail{Name: "rbd", Size: 3, MinSize: 1, CrushRule: 0},
+		},
+		{
+			name:          "replicated across hosts at min_size",
+			pool:          k8s.PoolDetail{Name: "rbd", Size: 3, MinSize: 2, CrushRule: 0},
+			wantRemaining: 2,
+			wantListed:    true,
+		},
+		{
+			name:          "two replicas may sit on the node",
+			pool:          k8s.PoolDetail{Name: "scratch", Size: 2, MinSize: 1, CrushRule: 1},
+			wantRemaining: 0,
+			wantListed:    true,
+			wantBlocked:   true,
+		},
+		{
+			name: "device class without OSDs on the node",
+			pool: k8s.PoolDetail{Name: "fast", Size: 2, MinSize: 2, CrushRule: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			impact := EstimateCapacityImpact(tree, []k8s.PoolDetail{tt.pool}, rules, "node-a")
+
+			if len(impact.OSDs) != 2 || impact.RemovedPercent() != 50 {
+				t.Errorf("OSDs = %v, RemovedPercent() = %v; want 2 OSDs, 50", impact.OSDs, impact.RemovedPercent())
+			}
+			if got := len(impact.Pools) == 1; got != tt.wantListed {
+				t.Fatalf("pools = %+v, want listed = %v", impact.Pools, tt.wantListed)
+			}
+			if !tt.wantListed {
+				return
+			}
+			if got := impact.Pools[0].Remaining; got != tt.wantRemaining {
+				t.Errorf("Remaining = %d, want %d", got, tt.wantRemaining)
+			}
+			if got := impact.HasUnavailablePools(); got != tt.wantBlocked {
+				t.Errorf("HasUnavailablePools() = %v, want %v", got, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestEstimateCapacityImpact_NodeWithoutOSDs(t *testing.T) {
+	tree := &k8s.CephOSDTree{Nodes: []k8s.CephOSDNode{
+		{ID: -2, Name: "node-a", Type: "host", Children: []int{0}},
+		{ID: 0, Name: "osd.0", Type: "osd", CrushWeight: 2},
+	}}
+	pools := []k8s.PoolDetail{{Name: "rbd", Size: 2, MinSize: 2}}
+
+	impact := EstimateCapacityImpact(tree, pools, nil, "node-z")
+	if len(impact.OSDs) != 0 || len(impact.Pools) != 0 || impact.RemovedPercent() != 0 {
+		t.Errorf("impact = %+v, want none", impact)
+	}
+}
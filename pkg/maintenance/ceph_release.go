@@ -0,0 +1,84 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// CheckCephRelease returns warnings about the cluster's Ceph release: releases
+// older than crook supports, and daemons left on different versions by an
+// unfinished upgrade. Best-effort; an undetectable version yields none.
+func CheckCephRelease(ctx context.Context, client *k8s.Client, cfg config.Config) []string {
+	version, err := client.GetCephVersion(ctx, cfg.Namespace)
+	if err != nil {
+		logger.Debug("skipping ceph release check", "error", err)
+		return nil
+	}
+	return describeCephRelease(version)
+}
+
+// describeCephRelease returns the warnings implied by the cluster's version
+func describeCephRelease(version *k8s.CephVersion) []string {
+	var warnings []string
+	if version.Less(k8s.MinSupportedCephVersion) {
+		warnings = append(warnings, fmt.Sprintf(
+			"ceph %s is older than %s, the oldest release crook supports - some checks are skipped",
+			version, k8s.MinSupportedCephVersion.Release))
+	}
+	if version.Mixed {
+		warnings = append(warnings, fmt.Sprintf(
+			"ceph daemons run mixed versions (oldest %s) - finish the upgrade before taking a node down", version))
+	}
+	return warnings
+}
+
+// CheckOSDsOkToStop asks Ceph whether the OSDs on nodeName can stop without
+// placement groups becoming inactive, and returns a warning when they cannot.
+// Best-effort; a failed lookup yields no warning.
+func CheckOSDsOkToStop(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) string {
+	osds, err := client.GetOSDInfoList(ctx, cfg.Namespace)
+	if err != nil {
+		logger.Debug("skipping ok-to-stop check", "error", err)
+		return ""
+	}
+	var ids []int
+	for _, osd := range osds {
+		if osd.Hostname == nodeName {
+			ids = append(ids, osd.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return ""
+	}
+
+	result, err := client.OSDsOkToStop(ctx, cfg.Namespace, ids)
+	if err != nil {
+		logger.Debug("skipping ok-to-stop check", "error", err)
+		return ""
+	}
+	return describeOkToStop(result)
+}
+
+// describeOkToStop returns the warning for a negative ok-to-stop answer
+func describeOkToStop(result *k8s.OkToStopResult) string {
+	if result.OkToStop {
+		return ""
+	}
+	names := make([]string, len(result.OSDs))
+	for i, id := range result.OSDs {
+		names[i] = "osd." + strconv.Itoa(id)
+	}
+	warning := fmt.Sprintf("ceph reports %s not ok to stop", strings.Join(names, ", "))
+	if result.NumNotOkPGs > 0 {
+		warning += fmt.Sprintf(" - %d PG(s) would become inactive", result.NumNotOkPGs)
+	} else if result.Reason != "" {
+		warning += " - " + result.Reason
+	}
+	return warning
+}
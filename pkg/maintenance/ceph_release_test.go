@@ -0,0 +1,62 @@
+package maintenance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func TestDescribeCephRelease(t *testing.T) {
+	tests := []struct {
+		name    string
+		version k8s.CephVersion
+		want    []string
+	}{
+		{name: "supported", version: k8s.CephVersion{Major: 18, Minor: 2, Patch: 4, Release: "reef"}},
+		{name: "too old", version: k8s.CephVersion{Major: 13, Minor: 2, Patch: 10, Release: "mimic"}, want: []string{"older than nautilus"}},
+		{name: "mixed", version: k8s.CephVersion{Major: 17, Minor: 2, Patch: 7, Release: "quincy", Mixed: true}, want: []string{"mixed versions (oldest 17.2.7 (quincy))"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := describeCephRelease(&tt.version)
+			if len(got) != len(tt.want) {
+				t.Fatalf("describeCephRelease() = %q, want %d warning(s)", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("warning %q should contain %q", got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestDescribeOkToStop(t *testing.T) {
+	tests := []struct {
+		name   string
+		result k8s.OkToStopResult
+		want   string
+	}{
+		{name: "ok", result: k8s.OkToStopResult{OkToStop: true, OSDs: []int{1}}},
+		{
+			name:   "json report",
+			result: k8s.OkToStopResult{OSDs: []int{1, 2}, NumNotOkPGs: 12},
+			want:   "ceph reports osd.1, osd.2 not ok to stop - 12 PG(s) would become inactive",
+		},
+		{
+			name:   "exit status only",
+			result: k8s.OkToStopResult{OSDs: []int{4}, Reason: "unsafe to stop osd(s)"},
+			want:   "ceph reports osd.4 not ok to stop - unsafe to stop osd(s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeOkToStop(&tt.result); got != tt.want {
+				t.Errorf("describeOkToStop() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
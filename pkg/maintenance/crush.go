@@ -0,0 +1,156 @@
+package maintenance
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// CrushRisk describes how the CRUSH hierarchy makes taking a node down riskier
+// than losing a single replica
+type CrushRisk struct {
+	// DomainType is the failure domain pools replicate across (host, rack, zone, ...)
+	DomainType string
+
+	// Bucket is the failure domain bucket that holds the target node
+	Bucket string
+
+	// DownBucket is another bucket of the same type that already has down OSDs;
+	// empty when the risk is that the node is alone in Bucket
+	DownBucket string
+
+	// DownOSDs are the down OSDs in DownBucket
+	DownOSDs []string
+}
+
+// String returns a human-readable description of the risk
+func (r CrushRisk) String() string {
+	if r.DownBucket == "" {
+		return fmt.Sprintf("the node is the only host in %s %s - the whole %s is offline while it is down",
+			r.DomainType, r.Bucket, r.DomainType)
+	}
+	return fmt.Sprintf("%s %s already has down OSDs (%s) - PGs replicated across %ss may lose a second copy",
+		r.DomainType, r.DownBucket, strings.Join(r.DownOSDs, ", "), r.DomainType)
+}
+
+// crushHierarchy indexes an OSD tree by ID and parent
+type crushHierarchy struct {
+	nodes   map[int]k8s.CephOSDNode
+	parents map[int]int
+}
+
+func newCrushHierarchy(tree *k8s.CephOSDTree) crushHierarchy {
+	h := crushHierarchy{
+		nodes:   make(map[int]k8s.CephOSDNode, len(tree.Nodes)),
+		parents: make(map[int]int),
+	}
+	for _, node := range tree.Nodes {
+		h.nodes[node.ID] = node
+		for _, child := range node.Children {
+			h.parents[child] = node.ID
+		}
+	}
+	return h
+}
+
+// bucketOfType returns the bucket of the given type that contains id (id
+// itself included)
+func (h crushHierarchy) bucketOfType(id int, bucketType string) (k8s.CephOSDNode, bool) {
+	for {
+		node, ok := h.nodes[id]
+		if !ok {
+			return k8s.CephOSDNode{}, false
+		}
+		if node.Type == bucketType {
+			return node, true
+		}
+		parent, ok := h.parents[id]
+		if !ok {
+			return k8s.CephOSDNode{}, false
+		}
+		id = parent
+	}
+}
+
+// descendants returns the nodes of the given type below id
+func (h crushHierarchy) descendants(id int, nodeType string) []k8s.CephOSDNode {
+	var found []k8s.CephOSDNode
+	for _, child := range h.nodes[id].Children {
+		node := h.nodes[child]
+		if node.Type == nodeType {
+			found = append(found, node)
+			continue
+		}
+		found = append(found, h.descendants(child, nodeType)...)
+	}
+	return found
+}
+
+// failureDomains returns the bucket types the pools replicate across. Without
+// rule data it assumes Rook's default of host.
+func failureDomains(pools []k8s.PoolDetail, rules []k8s.CrushRule) []string {
+	var domains []string
+	for _, pool := range pools {
+		for _, rule := range rules {
+			if rule.ID != pool.CrushRule {
+				continue
+			}
+			if domain := rule.FailureDomain(); domain != "" && domain != "osd" && !slices.Contains(domains, domain) {
+				domains = append(domains, domain)
+			}
+		}
+	}
+	if len(domains) == 0 {
+		domains = []string{"host"}
+	}
+	return domains
+}
+
+// DetectCrushRisks warns when the node is the only host in a larger failure
+// domain, or when another failure domain of the same type already has down
+// OSDs so that PGs may lose two copies at once.
+func DetectCrushRisks(tree *k8s.CephOSDTree, pools []k8s.PoolDetail, rules []k8s.CrushRule, nodeName string) []CrushRisk {
+	h := newCrushHierarchy(tree)
+
+	hostID, found := 0, false
+	for _, node := range tree.Nodes {
+		if node.Type == "host" && node.Name == nodeName {
+			hostID, found = node.ID, true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	var risks []CrushRisk
+	for _, domain := range failureDomains(pools, rules) {
+		bucket, ok := h.bucketOfType(hostID, domain)
+		if !ok {
+			continue
+		}
+
+		if domain != "host" && len(h.descendants(bucket.ID, "host")) == 1 {
+			risks = append(risks, CrushRisk{DomainType: domain, Bucket: bucket.Name})
+		}
+
+		for _, other := range tree.Nodes {
+			if other.Type != domain || other.ID == bucket.ID {
+				continue
+			}
+			var down []string
+			for _, osd := range h.descendants(other.ID, "osd") {
+				if osd.Status == "down" {
+					down = append(down, osd.Name)
+				}
+			}
+			if len(down) > 0 {
+				risks = append(risks, CrushRisk{DomainType: domain, Bucket: bucket.Name, DownBucket: other.Name, DownOSDs: down})
+			}
+		}
+	}
+
+	return risks
+}
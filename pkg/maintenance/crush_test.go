@@ -0,0 +1,97 @@
+package maintenance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// rackTree has node-a alone in rack-1 and node-b, node-c sharing rack-2
+func rackTree(downOSD int) *k8s.CephOSDTree {
+	tree := &k8s.CephOSDTree{Nodes: []k8s.CephOSDNode{
+		{ID: -1, Name: "default", Type: "root", Children: []int{-2, -3}},
+		{ID: -2, Name: "rack-1", Type: "rack", Children: []int{-4}},
+		{ID: -3, Name: "rack-2", Type: "rack", Children: []int{-5, -6}},
+		{ID: -4, Name: "node-a", Type: "host", Children: []int{0}},
+		{ID: -5, Name: "node-b", Type: "host", Children: []int{1}},
+		{ID: -6, Name: "node-c", Type: "host", Children: []int{2}},
+		{ID: 0, Name: "osd.0", Type: "osd", Status: "up"},
+		{ID: 1, Name: "osd.1", Type: "osd", Status: "up"},
+		{ID: 2, Name: "osd.2", Type: "osd", Status: "up"},
+	}}
+	for i := range tree.Nodes {
+		if tree.Nodes[i].Type == "osd" && tree.Nodes[i].ID == downOSD {
+			tree.Nodes[i].Status = "down"
+		}
+	}
+	return tree
+}
+
+func TestDetectCrushRisks(t *testing.T) {
+	byRack := []k8s.CrushRule{{ID: 1, Steps: []k8s.CrushRuleStep{{Op: "chooseleaf_firstn", Type: "rack"}}}}
+	rackPools := []k8s.PoolDetail{{Name: "rbd", Size: 2, MinSize: 1, CrushRule: 1}}
+
+	tests := []struct {
+		name     string
+		tree     *k8s.CephOSDTree
+		pools    []k8s.PoolDetail
+		rules    []k8s.CrushRule
+		nodeName string
+		want     []string
+	}{
+		{
+			name:     "host domain with all OSDs up",
+			tree:     rackTree(-100),
+			nodeName: "node-b",
+		},
+		{
+			name:     "host domain with another host down",
+			tree:     rackTree(2),
+			nodeName: "node-a",
+			want:     []string{"host node-c already has down OSDs (osd.2)"},
+		},
+		{
+			name:     "only host in rack",
+			tree:     rackTree(-100),
+			pools:    rackPools,
+			rules:    byRack,
+			nodeName: "node-a",
+			want:     []string{"only host in rack rack-1"},
+		},
+		{
+			name:     "down OSD in the same rack is not another domain",
+			tree:     rackTree(2),
+			pools:    rackPools,
+			rules:    byRack,
+			nodeName: "node-b",
+		},
+		{
+			name:     "down OSD in another rack",
+			tree:     rackTree(0),
+			pools:    rackPools,
+			rules:    byRack,
+			nodeName: "node-b",
+			want:     []string{"rack rack-1 already has down OSDs (osd.0)"},
+		},
+		{
+			name:     "unknown node",
+			tree:     rackTree(2),
+			nodeName: "node-z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risks := DetectCrushRisks(tt.tree, tt.pools, tt.rules, tt.nodeName)
+			if len(risks) != len(tt.want) {
+				t.Fatalf("DetectCrushRisks() = %v, want %d risk(s)", risks, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := risks[i].String(); !strings.Contains(got, want) {
+					t.Errorf("risk %d = %q, want it to contain %q", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+)
+
+// maxCustomCheckOutput bounds the command output or response body a custom
+// check matches its expectation against
+const maxCustomCheckOutput = 1 << 20
+
+// customCheckWaitDelay is how long a timed-out command's output is drained
+const customCheckWaitDelay = 500 * time.Millisecond
+
+// customCheckHTTPClient probes the URLs of custom checks; each check bounds
+// its own request through the context
+var customCheckHTTPClient = &http.Client{}
+
+// customPreflightChecks returns the checks of preflight.checks that run
+// before the phase ("down" or "up")
+func customPreflightChecks(cfg config.Config, nodeName, phase string) []preflightCheck {
+	var checks []preflightCheck
+	for _, custom := range cfg.Preflight.Checks {
+		if !custom.RunsBefore(phase) {
+			continue
+		}
+		checks = append(checks, preflightCheck{name: custom.Name, run: func(ctx context.Context, results *ValidationResults) {
+			ctx, cancel := context.WithTimeout(ctx, custom.Timeout())
+			defer cancel()
+
+			message, err := runCustomCheck(ctx, custom, nodeName, phase)
+			switch {
+			case err == nil:
+				results.addResult(custom.Name, true, nil, message)
+			case custom.Severity == config.CheckSeverityWarn:
+				results.addWarning(custom.Name, err, err.Error())
+			default:
+				results.addResult(custom.Name, false, err, err.Error())
+			}
+		}})
+	}
+	return checks
+}
+
+// runCustomCheck runs the command or probes the URL of a custom check and
+// returns the message of a passing check
+func runCustomCheck(ctx context.Context, check config.CustomCheck, nodeName, phase string) (string, error) {
+	var (
+		output  string
+		message string
+		err     error
+	)
+	if check.URL != "" {
+		output, message, err = probeCustomCheck(ctx, check)
+	} else {
+		output, message, err = runCustomCheckCommand(ctx, check, nodeName, phase)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if check.Expect != "" {
+		re, err := regexp.Compile(check.Expect)
+		if err != nil {
+			return "", fmt.Errorf("invalid expect %q: %w", check.Expect, err)
+		}
+		if !re.MatchString(strings.TrimSpace(output)) {
+			return "", fmt.Errorf("output does not match %q: %s", check.Expect, firstLine(output))
+		}
+	}
+	return message, nil
+}
+
+// runCustomCheckCommand runs the command of a custom check with 'sh -c'
+func runCustomCheckCommand(ctx context.Context, check config.CustomCheck, nodeName, phase string) (output, message string, err error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", check.Command)
+	cmd.Env = append(os.Environ(), "CROOK_NODE="+nodeName, "CROOK_PHASE="+phase)
+	// Background processes the command started may hold the output open
+	// after it was killed on timeout
+	cmd.WaitDelay = customCheckWaitDelay
+	out, err := cmd.CombinedOutput()
+	if len(out) > maxCustomCheckOutput {
+		out = out[:maxCustomCheckOutput]
+	}
+	output = string(out)
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", "", fmt.Errorf("command did not finish: %w", ctxErr)
+	}
+	if err != nil {
+		if line := firstLine(output); line != "" {
+			return "", "", fmt.Errorf("command failed: %w: %s", err, line)
+		}
+		return "", "", fmt.Errorf("command failed: %w", err)
+	}
+
+	message = "Command succeeded"
+	if line := firstLine(output); line != "" {
+		message += ": " + line
+	}
+	return output, message, nil
+}
+
+// probeCustomCheck fetches the URL of a custom check and compares the status
+func probeCustomCheck(ctx context.Context, check config.CustomCheck) (output, message string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, check.URL, http.NoBody)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid url %q: %w", check.URL, err)
+	}
+	resp, err := customCheckHTTPClient.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("probe failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCustomCheckOutput))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	want := check.ExpectStatus
+	if want == 0 {
+		want = http.StatusOK
+	}
+	if resp.StatusCode != want {
+		return "", "", fmt.Errorf("%s returned HTTP %d, want %d", check.URL, resp.StatusCode, want)
+	}
+	return string(body), fmt.Sprintf("%s returned HTTP %d", check.URL, resp.StatusCode), nil
+}
+
+// firstLine returns the first non-empty line of s
+func firstLine(s string) string {
+	for line := range strings.SplitSeq(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package maintenance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+)
+
+func TestRunCustomCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/frozen" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_, _ = w.Write([]byte(`{"frozen": false}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		check       config.CustomCheck
+		wantErr     string
+		wantMessage string
+	}{
+		{name: "command succeeds", check: config.CustomCheck{Command: "echo no backup running"}, wantMessage: "Command succeeded: no backup running"},
+		{name: "command fails", check: config.CustomCheck{Command: "echo backup running; exit 3"}, wantErr: "backup running"},
+		{name: "command sees the node", check: config.CustomCheck{Command: "echo $CROOK_PHASE $CROOK_NODE", Expect: "^down worker-1$"}},
+		{name: "command output mismatch", check: config.CustomCheck{Command: "echo frozen", Expect: "^open$"}, wantErr: "does not match"},
+		{name: "probe succeeds", check: config.CustomCheck{URL: server.URL + "/status", Expect: `"frozen": false`}, wantMessage: "returned HTTP 200"},
+		{name: "probe status mismatch", check: config.CustomCheck{URL: server.URL + "/frozen"}, wantErr: "HTTP 503, want 200"},
+		{name: "probe expected status", check: config.CustomCheck{URL: server.URL + "/frozen", ExpectStatus: http.StatusServiceUnavailable}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := runCustomCheck(context.Background(), tt.check, "worker-1", config.PhaseDown)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("runCustomCheck() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runCustomCheck() error = %v", err)
+			}
+			if !strings.Contains(message, tt.wantMessage) {
+				t.Errorf("message = %q, want it to contain %q", message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestCustomPreflightChecks(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Preflight.Checks = []config.CustomCheck{
+		{Name: "backup", Command: "exit 1"},
+		{Name: "freeze", Command: "exit 1", Severity: config.CheckSeverityWarn, Phases: []string{config.PhaseDown, config.PhaseUp}},
+		{Name: "up only", Command: "true", Phases: []string{config.PhaseUp}},
+	}
+
+	down := runPreflightChecks(context.Background(), customPreflightChecks(cfg, "worker-1", config.PhaseDown), nil)
+	if len(down.Results) != 2 {
+		t.Fatalf("down ran %d checks, want backup and freeze", len(down.Results))
+	}
+	if !down.Results[0].Blocking() || down.Results[1].Blocking() {
+		t.Errorf("results = %+v, want backup to block and freeze to warn", down.Results)
+	}
+
+	up := runPreflightChecks(context.Background(), customPreflightChecks(cfg, "worker-1", config.PhaseUp), nil)
+	if len(up.Results) != 2 || up.Blocked() {
+		t.Errorf("up results = %+v, want freeze warning and up only passing", up.Results)
+	}
+}
+
+func TestCustomPreflightChecks_Timeout(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Preflight.Checks = []config.CustomCheck{{Name: "slow", Command: "sleep 5", TimeoutSeconds: 1}}
+
+	results := runPreflightChecks(context.Background(), customPreflightChecks(cfg, "worker-1", config.PhaseDown), nil)
+	if !results.Blocked() || !strings.Contains(results.Results[0].Message, "did not finish") {
+		t.Errorf("results = %+v, want the slow check to fail on its timeout", results.Results)
+	}
+}
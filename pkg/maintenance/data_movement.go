@@ -0,0 +1,75 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// DataMovementRisk describes a Ceph mgr module that may start moving data while
+// a node is down, even with noout set.
+type DataMovementRisk struct {
+	// Module is the ceph mgr module (balancer or pg_autoscaler)
+	Module string
+
+	// State describes the current configuration (e.g. "active, mode upmap")
+	State string
+
+	// Reason explains why it matters during maintenance
+	Reason string
+
+	// Suggestion is the command that temporarily pauses the module
+	Suggestion string
+}
+
+// String returns a human-readable description of the risk
+func (r DataMovementRisk) String() string {
+	return fmt.Sprintf("ceph %s is %s - %s; consider '%s' until 'crook up' completes", r.Module, r.State, r.Reason, r.Suggestion)
+}
+
+// CheckDataMovementRisks reads the balancer and pg_autoscaler state and reports
+// modules that would move data while the node is down. Each lookup is
+// best-effort; a failed lookup simply omits that module.
+func CheckDataMovementRisks(ctx context.Context, client *k8s.Client, cfg config.Config) []DataMovementRisk {
+	balancer, err := client.GetBalancerStatus(ctx, cfg.Namespace)
+	if err != nil {
+		logger.Debug("skipping balancer check", "error", err)
+	}
+	autoscale, err := client.GetAutoscaleStatus(ctx, cfg.Namespace)
+	if err != nil {
+		logger.Debug("skipping pg_autoscaler check", "error", err)
+	}
+	return DetectDataMovementRisks(balancer, autoscale)
+}
+
+// DetectDataMovementRisks returns the risks implied by the given balancer and
+// autoscaler state. Either argument may be nil when it could not be read.
+func DetectDataMovementRisks(balancer *k8s.BalancerStatus, autoscale *k8s.AutoscaleStatus) []DataMovementRisk {
+	var risks []DataMovementRisk
+
+	if balancer != nil && balancer.Active && balancer.Mode != "none" {
+		risks = append(risks, DataMovementRisk{
+			Module:     "balancer",
+			State:      fmt.Sprintf("active (mode %s)", balancer.Mode),
+			Reason:     "it optimizes placement around the down OSDs and moves data again once they return",
+			Suggestion: "ceph balancer off",
+		})
+	}
+
+	if autoscale != nil {
+		if pools := autoscale.EnabledPools(); len(pools) > 0 {
+			risks = append(risks, DataMovementRisk{
+				Module:     "pg_autoscaler",
+				State:      fmt.Sprintf("on for %d pool(s) (%s)", len(pools), strings.Join(pools, ", ")),
+				Reason:     "PG count changes trigger backfill while OSDs are down",
+				Suggestion: "ceph osd pool set noautoscale",
+			})
+		}
+	}
+
+	return risks
+}
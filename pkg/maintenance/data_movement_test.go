@@ -0,0 +1,72 @@
+package maintenance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func TestDetectDataMovementRisks(t *testing.T) {
+	tests := []struct {
+		name        string
+		balancer    *k8s.BalancerStatus
+		autoscale   *k8s.AutoscaleStatus
+		wantModules []string
+	}{
+		{
+			name:        "nothing known",
+			wantModules: nil,
+		},
+		{
+			name:        "balancer off and autoscaler warn",
+			balancer:    &k8s.BalancerStatus{Active: false, Mode: "upmap"},
+			autoscale:   &k8s.AutoscaleStatus{Pools: []k8s.PoolAutoscale{{Pool: "replicapool", Mode: k8s.AutoscaleModeWarn}}},
+			wantModules: nil,
+		},
+		{
+			name:        "balancer active with mode none",
+			balancer:    &k8s.BalancerStatus{Active: true, Mode: "none"},
+			wantModules: nil,
+		},
+		{
+			name:        "balancer active",
+			balancer:    &k8s.BalancerStatus{Active: true, Mode: "upmap"},
+			wantModules: []string{"balancer"},
+		},
+		{
+			name:     "balancer and autoscaler",
+			balancer: &k8s.BalancerStatus{Active: true, Mode: "crush-compat"},
+			autoscale: &k8s.AutoscaleStatus{Pools: []k8s.PoolAutoscale{
+				{Pool: "replicapool", Mode: k8s.AutoscaleModeOn},
+				{Pool: "archive", Mode: k8s.AutoscaleModeOff},
+			}},
+			wantModules: []string{"balancer", "pg_autoscaler"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risks := DetectDataMovementRisks(tt.balancer, tt.autoscale)
+			if len(risks) != len(tt.wantModules) {
+				t.Fatalf("got %d risks, want %d: %v", len(risks), len(tt.wantModules), risks)
+			}
+			for i, want := range tt.wantModules {
+				if risks[i].Module != want {
+					t.Errorf("risk[%d].Module = %q, want %q", i, risks[i].Module, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDataMovementRiskString(t *testing.T) {
+	risk := DetectDataMovementRisks(&k8s.BalancerStatus{Active: true, Mode: "upmap"}, nil)[0]
+
+	got := risk.String()
+	for _, want := range []string{"balancer", "mode upmap", "ceph balancer off", "crook up"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() = %q, missing %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// forceDeleteStage is the progress stage of a dead-node down phase removing
+// the pods stuck in Terminating on the node
+const forceDeleteStage = "force-delete"
+
+// ForceDeleteProgressStages lists the progress stages of the force-delete
+// stage of a dead-node down phase
+var ForceDeleteProgressStages = []string{forceDeleteStage}
+
+// ErrNodeReady is returned, wrapped in ErrValidationFailed, when dead-node
+// mode targets a node that is Ready: its pods would be force-deleted while
+// still running
+var ErrNodeReady = errors.New("node is Ready")
+
+// DeadNodeNote labels a down phase in dead-node mode wherever it is shown
+const DeadNodeNote = "EMERGENCY: dead-node handling. The node is NotReady, so node-local checks are skipped " +
+	"and scaled-down pods are not waited for; the node must stay down until 'crook up'."
+
+// ForceDeleteWarning is shown before pods are force-deleted
+const ForceDeleteWarning = "DANGER: force-deleting removes pods from the API without their node confirming that " +
+	"the containers stopped. If the node is still running them, their replacements run alongside them and can " +
+	"corrupt the data both write. Only continue when the node is powered off or confirmed unreachable."
+
+// IsNodeDead reports whether the node's Ready condition is False or Unknown,
+// the latter when its kubelet stopped reporting. A node that cannot be read
+// or reports no Ready condition is not reported dead.
+func IsNodeDead(ctx context.Context, client *k8s.Client, nodeName string) bool {
+	status, err := client.GetNodeStatus(ctx, nodeName)
+	if err != nil {
+		logger.Debug("failed to read node readiness", "node", nodeName, "error", err)
+		return false
+	}
+	for _, condition := range status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status != corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// RefuseReadyNode returns an ErrNodeReady error unless the node is NotReady
+func RefuseReadyNode(ctx context.Context, client *k8s.Client, nodeName string) error {
+	status, err := client.GetNodeStatus(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+	if status.Ready {
+		return fmt.Errorf("%w: %s; dead-node mode is only for nodes that are NotReady or unreachable", ErrNodeReady, nodeName)
+	}
+	return nil
+}
+
+// forceDeleteTerminatingPods force-deletes the pods stuck in Terminating on a
+// dead node, so their controllers can replace them elsewhere
+func forceDeleteTerminatingPods(ctx context.Context, client *k8s.Client, nodeName string, callback func(DownPhaseProgress)) error {
+	pods, err := client.ListTerminatingPods(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+	updateProgress(callback, forceDeleteStage, fmt.Sprintf("Force-deleting %d pod(s) stuck in Terminating on %s", len(pods), nodeName), "")
+	return client.ForceDeletePods(ctx, pods, func(pod string) {
+		updatePodProgress(callback, forceDeleteStage, "Force-deleted "+pod, pod)
+	})
+}
+
+// recordReportDeadNode marks the node's open maintenance report as handled in
+// dead-node mode (best-effort)
+func recordReportDeadNode(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) {
+	report, err := LoadReport(ctx, client, cfg, nodeName)
+	if err != nil {
+		if !errors.Is(err, ErrNoReport) {
+			logger.Warn("failed to load maintenance report", "node", nodeName, "error", err)
+		}
+		return
+	}
+	report.DeadNode = true
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		logger.Warn("failed to save maintenance report", "node", nodeName, "error", err)
+	}
+}
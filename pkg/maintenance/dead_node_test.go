@@ -0,0 +1,170 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func nodeWithReady(status corev1.ConditionStatus) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}}
+	if status != "" {
+		node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}
+	}
+	return node
+}
+
+func TestIsNodeDead(t *testing.T) {
+	tests := []struct {
+		name      string
+		node      *corev1.Node
+		wantDead  bool
+		wantReady bool
+	}{
+		{name: "ready", node: nodeWithReady(corev1.ConditionTrue), wantReady: true},
+		{name: "not ready", node: nodeWithReady(corev1.ConditionFalse), wantDead: true},
+		{name: "kubelet stopped reporting", node: nodeWithReady(corev1.ConditionUnknown), wantDead: true},
+		{name: "no ready condition", node: nodeWithReady("")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &k8s.Client{Clientset: fake.NewClientset(tt.node)}
+			if got := IsNodeDead(context.Background(), client, "worker-1"); got != tt.wantDead {
+				t.Errorf("IsNodeDead() = %v, want %v", got, tt.wantDead)
+			}
+			err := RefuseReadyNode(context.Background(), client, "worker-1")
+			if got := errors.Is(err, ErrNodeReady); got != tt.wantReady {
+				t.Errorf("RefuseReadyNode() error = %v, want ErrNodeReady %v", err, tt.wantReady)
+			}
+		})
+	}
+
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	if IsNodeDead(context.Background(), client, "worker-1") {
+		t.Error("IsNodeDead() = true for a missing node")
+	}
+}
+
+func TestCompleteDownPhase_DeadNode(t *testing.T) {
+	tests := []struct {
+		name        string
+		forceDelete bool
+		configured  bool
+		wantStages  []string
+		wantPods    []string
+	}{
+		{
+			name:       "drain skipped",
+			wantStages: []string{"complete"},
+			wantPods:   []string{"running", "stuck"},
+		},
+		{
+			name:        "force delete",
+			forceDelete: true,
+			wantStages:  []string{"force-delete", "force-delete", "complete"},
+			wantPods:    []string{"running"},
+		},
+		{
+			name:       "force delete configured",
+			configured: true,
+			wantStages: []string{"force-delete", "force-delete", "complete"},
+			wantPods:   []string{"running"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := metav1.Now()
+			clientset := fake.NewClientset(
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "stuck", Namespace: "apps", UID: "stuck-uid", DeletionTimestamp: &deleted},
+					Spec:       corev1.PodSpec{NodeName: "worker-1"},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "apps", UID: "running-uid"},
+					Spec:       corev1.PodSpec{NodeName: "worker-1"},
+				},
+			)
+			client := &k8s.Client{Clientset: clientset}
+
+			// Draining a dead node would wait on evictions its kubelet never confirms
+			cfg := config.DefaultConfig()
+			cfg.Drain.Enabled = true
+			cfg.Drain.ForceDeleteOnDeadNode = tt.configured
+
+			var stages []string
+			err := completeDownPhase(context.Background(), client, cfg, "worker-1", DownPhaseOptions{
+				DeadNode:         true,
+				ForceDeletePods:  tt.forceDelete,
+				ProgressCallback: func(p DownPhaseProgress) { stages = append(stages, p.Stage) },
+			}, "done")
+			if err != nil {
+				t.Fatalf("completeDownPhase() error = %v", err)
+			}
+			if !slices.Equal(stages, tt.wantStages) {
+				t.Errorf("stages = %v, want %v", stages, tt.wantStages)
+			}
+
+			remaining, err := clientset.CoreV1().Pods("apps").List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			var names []string
+			for _, pod := range remaining.Items {
+				names = append(names, pod.Name)
+			}
+			slices.Sort(names)
+			if !slices.Equal(names, tt.wantPods) {
+				t.Errorf("remaining pods = %v, want %v", names, tt.wantPods)
+			}
+		})
+	}
+}
+
+func TestScaleDownWorkloads_DeadNodeDoesNotWait(t *testing.T) {
+	replicas := int32(1)
+	// The pods of a dead node are never reported stopped
+	clientset := fake.NewClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-0", Namespace: "rook-ceph"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{Replicas: 1, ReadyReplicas: 1},
+	})
+	clientset.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		return true, &autoscalingv1.Scale{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-0", Namespace: "rook-ceph"}}, nil
+	})
+	clientset.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		return true, action.(k8stesting.UpdateAction).GetObject(), nil
+	})
+	client := &k8s.Client{Clientset: clientset}
+	workloads := []k8s.Workload{{Kind: k8s.WorkloadKindDeployment, Namespace: "rook-ceph", Name: "rook-ceph-osd-0", Replicas: 1}}
+
+	for _, deadNode := range []bool{false, true} {
+		opts := DownPhaseOptions{
+			DeadNode:    deadNode,
+			WaitOptions: WaitOptions{PollInterval: time.Millisecond, Timeout: 50 * time.Millisecond},
+		}
+		err := scaleDownWorkloads(context.Background(), client, workloads, &ScaleHistory{}, opts)
+		if (err == nil) != deadNode {
+			t.Errorf("scaleDownWorkloads(DeadNode=%v) error = %v", deadNode, err)
+		}
+	}
+}
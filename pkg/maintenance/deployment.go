@@ -0,0 +1,301 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// DeploymentPhaseProgress tracks progress of a single deployment operation
+type DeploymentPhaseProgress struct {
+	Stage       string
+	Description string
+}
+
+// DeploymentPhaseOptions holds options for single deployment operations
+type DeploymentPhaseOptions struct {
+	// ProgressCallback is called on each major step with progress updates
+	// Optional - if nil, no progress updates are sent
+	ProgressCallback func(progress DeploymentPhaseProgress)
+
+	// ApprovalID is the approved request ('crook down --request') for the
+	// deployment's node the scale-down consumes. Required when
+	// policy.require-approval-before-down is set.
+	ApprovalID string
+
+	// AcknowledgeWarnings proceeds past failed SeverityWarn pre-flight
+	// checks of the scale-down, as DownPhaseOptions.AcknowledgeWarnings
+	AcknowledgeWarnings bool
+
+	// WaitOptions for deployment scaling operations
+	WaitOptions WaitOptions
+}
+
+// ExecuteDeploymentDown scales a single deployment to 0 with the safeguards of
+// the down phase: the pre-flight checks and approval of the deployment's node,
+// noout on the node's ceph.noout-scope so Ceph does not rebalance, and the
+// operator stopped so it does not reconcile the deployment back up. The node
+// stays schedulable. Used to bounce one OSD without taking its node down. Like
+// the node phases, the replica count is recorded in
+// k8s.OriginalReplicasAnnotation for ExecuteDeploymentUp, and the noout scope
+// set in k8s.NoOutScopeAnnotation.
+// Steps: pre-flight → set noout → scale operator → scale deployment
+func ExecuteDeploymentDown(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	namespace, name string,
+	opts DeploymentPhaseOptions,
+) error {
+	deploymentName := fmt.Sprintf("%s/%s", namespace, name)
+
+	deployment, err := client.GetDeployment(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+	// Pre-flight, approval and the noout scope all belong to a node
+	nodeName := k8s.GetDeploymentTargetNode(deployment)
+	if nodeName == "" {
+		return fmt.Errorf("%w: deployment %s is not pinned to a node", ErrValidationFailed, deploymentName)
+	}
+
+	sendDeploymentProgress(opts.ProgressCallback, preflightStage, fmt.Sprintf("Running pre-flight validation checks for node %s", nodeName))
+	err = runDownGate(ctx, client, cfg, nodeName, false, opts.AcknowledgeWarnings, opts.ApprovalID, func(check CheckStatus) {
+		sendDeploymentProgress(opts.ProgressCallback, preflightCheckStage, check.String())
+	})
+	if err != nil {
+		return err
+	}
+
+	scope, err := ResolveNoOutScope(ctx, client, cfg, nodeName)
+	if err != nil {
+		return err
+	}
+	if err := setDeploymentNoOut(ctx, client, cfg, deployment, scope, opts.ProgressCallback); err != nil {
+		return err
+	}
+
+	sendDeploymentProgress(opts.ProgressCallback, "operator", "Scaling down rook-ceph-operator to 0")
+	if err := client.ScaleDeployment(ctx, cfg.Namespace, operatorDeploymentName, 0); err != nil {
+		return fmt.Errorf("failed to scale operator to 0: %w", err)
+	}
+	if err := WaitForDeploymentScaleDown(ctx, client, cfg.Namespace, operatorDeploymentName, opts.WaitOptions); err != nil {
+		return fmt.Errorf("failed waiting for operator to scale down: %w", err)
+	}
+
+	sendDeploymentProgress(opts.ProgressCallback, "scale-down", fmt.Sprintf("Scaling down %s to 0", deploymentName))
+	if w := deploymentWorkload(deployment); w.Replicas > 0 {
+		recordOriginalReplicas(ctx, client, w)
+	}
+	if err := client.ScaleDeployment(ctx, namespace, name, 0); err != nil {
+		return fmt.Errorf("failed to scale deployment %s to 0: %w", deploymentName, err)
+	}
+	if err := WaitForDeploymentScaleDown(ctx, client, namespace, name, opts.WaitOptions); err != nil {
+		return fmt.Errorf("failed waiting for deployment %s to scale down: %w", deploymentName, err)
+	}
+
+	sendDeploymentProgress(opts.ProgressCallback, "complete", fmt.Sprintf("Deployment %s scaled down", deploymentName))
+	return nil
+}
+
+// ExecuteDeploymentUp restores a single deployment to its DeploymentRestoreReplicas
+// count. The operator is scaled back up only when no other Ceph deployment is
+// still scaled down, so restoring one deployment never ends another
+// maintenance in progress. Only the noout flag the scale-down recorded setting
+// is unset; a flag someone else set is left to them.
+// Steps: scale deployment → scale operator → unset noout
+func ExecuteDeploymentUp(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	namespace, name string,
+	opts DeploymentPhaseOptions,
+) error {
+	deploymentName := fmt.Sprintf("%s/%s", namespace, name)
+
+	deployment, err := client.GetDeployment(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+	w := deploymentWorkload(deployment)
+	target := PersistedReplicas(nil).RestoreReplicas(w)
+
+	sendDeploymentProgress(opts.ProgressCallback, "scale-up", fmt.Sprintf("Scaling up %s to %s", deploymentName, replicaCount(target)))
+	if err := client.ScaleDeployment(ctx, namespace, name, target); err != nil {
+		return fmt.Errorf("failed to scale deployment %s to %d: %w", deploymentName, target, err)
+	}
+	if err := WaitForDeploymentScaleUp(ctx, client, namespace, name, target, opts.WaitOptions); err != nil {
+		return fmt.Errorf("failed waiting for deployment %s to scale up: %w", deploymentName, err)
+	}
+	clearOriginalReplicas(ctx, client, w)
+
+	deployments, err := client.ListDeploymentsInNamespace(ctx, cfg.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to check for other scaled-down deployments: %w", err)
+	}
+	remaining := scaledDownCephDeployments(client.CephDeploymentFilter(nil), deployments, namespace, name)
+	if len(remaining) > 0 {
+		sendDeploymentProgress(opts.ProgressCallback, "operator",
+			fmt.Sprintf("Leaving rook-ceph-operator scaled down: %d other deployment(s) still scaled down", len(remaining)))
+	} else {
+		sendDeploymentProgress(opts.ProgressCallback, "operator", "Scaling up rook-ceph-operator to 1")
+		if err := client.ScaleDeployment(ctx, cfg.Namespace, operatorDeploymentName, 1); err != nil {
+			return fmt.Errorf("failed to scale operator to 1: %w", err)
+		}
+		if err := WaitForDeploymentScaleUp(ctx, client, cfg.Namespace, operatorDeploymentName, 1, opts.WaitOptions); err != nil {
+			return fmt.Errorf("failed waiting for operator to scale up: %w", err)
+		}
+	}
+
+	if err := unsetDeploymentNoOut(ctx, client, cfg, deployment, remaining, opts.ProgressCallback); err != nil {
+		return err
+	}
+
+	sendDeploymentProgress(opts.ProgressCallback, "complete", fmt.Sprintf("Deployment %s restored", deploymentName))
+	return nil
+}
+
+// setDeploymentNoOut sets noout on the scope and records it on the deployment
+// for ExecuteDeploymentUp. A flag that is already set, by another maintenance
+// or by hand, is left to its owner and not recorded.
+func setDeploymentNoOut(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	deployment *appsv1.Deployment,
+	scope NoOutScope,
+	callback func(DeploymentPhaseProgress),
+) error {
+	w := deploymentWorkload(deployment)
+	if !scope.Cluster() && len(scope.Groups) == 0 {
+		sendDeploymentProgress(callback, "noout", fmt.Sprintf("No Ceph noout flag to set (%s)", scope))
+		return recordDeploymentNoOutScope(ctx, client, w, nil)
+	}
+
+	flags, err := client.GetCephFlags(ctx, cfg.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to read Ceph flags: %w", err)
+	}
+	if scope.IsSet(flags) {
+		sendDeploymentProgress(callback, "noout", fmt.Sprintf("Ceph noout flag already set (%s), leaving it to its owner", scope))
+		return recordDeploymentNoOutScope(ctx, client, w, nil)
+	}
+
+	sendDeploymentProgress(callback, "noout", fmt.Sprintf("Setting Ceph noout flag (%s)", scope))
+	// Recorded first, so a flag that got set is never left without a record
+	if err := recordDeploymentNoOutScope(ctx, client, w, &scope); err != nil {
+		return err
+	}
+	if err := setNoOut(ctx, client, cfg, scope); err != nil {
+		return fmt.Errorf("failed to set noout flag: %w", err)
+	}
+	return nil
+}
+
+// unsetDeploymentNoOut unsets the noout flag the deployment's scale-down
+// recorded setting. While other scaled-down deployments still rely on it the
+// flag stays set and the record moves to them, so restoring the last one
+// clears it.
+func unsetDeploymentNoOut(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	deployment *appsv1.Deployment,
+	remaining []appsv1.Deployment,
+	callback func(DeploymentPhaseProgress),
+) error {
+	w := deploymentWorkload(deployment)
+	scope, recorded, err := recordedNoOutScope(deployment.Annotations)
+	if err != nil {
+		return fmt.Errorf("failed to read the noout scope of %s: %w", workloadName(w), err)
+	}
+	if !recorded {
+		sendDeploymentProgress(callback, "unset-noout", "Leaving Ceph noout flag alone: the scale-down did not set it")
+		return nil
+	}
+
+	if dependents := noOutDependents(scope, k8s.GetDeploymentTargetNode(deployment), remaining); len(dependents) > 0 {
+		for i := range dependents {
+			if _, own := dependents[i].Annotations[k8s.NoOutScopeAnnotation]; own {
+				continue
+			}
+			if err := recordDeploymentNoOutScope(ctx, client, deploymentWorkload(&dependents[i]), &scope); err != nil {
+				return err
+			}
+		}
+		sendDeploymentProgress(callback, "unset-noout",
+			fmt.Sprintf("Leaving Ceph noout flag set (%s): %d other deployment(s) still scaled down", scope, len(dependents)))
+	} else {
+		sendDeploymentProgress(callback, "unset-noout", fmt.Sprintf("Unsetting Ceph noout flag (%s)", scope))
+		if err := unsetNoOut(ctx, client, cfg, scope); err != nil {
+			return fmt.Errorf("failed to unset noout flag: %w", err)
+		}
+	}
+	return recordDeploymentNoOutScope(ctx, client, w, nil)
+}
+
+// noOutDependents returns the scaled-down deployments a noout scope set on
+// nodeName still protects: all of them for the cluster scope, else those
+// pinned to the same node
+func noOutDependents(scope NoOutScope, nodeName string, remaining []appsv1.Deployment) []appsv1.Deployment {
+	var dependents []appsv1.Deployment
+	for i := range remaining {
+		if scope.Cluster() || k8s.GetDeploymentTargetNode(&remaining[i]) == nodeName {
+			dependents = append(dependents, remaining[i])
+		}
+	}
+	return dependents
+}
+
+// recordDeploymentNoOutScope records the noout scope set for the deployment
+// in k8s.NoOutScopeAnnotation; nil removes the record
+func recordDeploymentNoOutScope(ctx context.Context, client *k8s.Client, w k8s.Workload, scope *NoOutScope) error {
+	value, err := noOutScopeAnnotation(scope)
+	if err != nil {
+		return err
+	}
+	if err := client.SetWorkloadAnnotation(ctx, w.Kind, w.Namespace, w.Name, k8s.NoOutScopeAnnotation, value); err != nil {
+		return fmt.Errorf("failed to record the noout scope of %s: %w", workloadName(w), err)
+	}
+	return nil
+}
+
+// DeploymentRestoreReplicas returns the replica count ExecuteDeploymentUp
+// restores the deployment to: its k8s.OriginalReplicasAnnotation, else 1
+func DeploymentRestoreReplicas(deployment *appsv1.Deployment) int32 {
+	return PersistedReplicas(nil).RestoreReplicas(deploymentWorkload(deployment))
+}
+
+// deploymentWorkload describes a single deployment as a workload
+func deploymentWorkload(deployment *appsv1.Deployment) k8s.Workload {
+	return k8s.DeploymentWorkloads([]appsv1.Deployment{*deployment})[0]
+}
+
+// scaledDownCephDeployments returns the Ceph deployments selected by filter
+// that are at 0 replicas, other than namespace/name
+func scaledDownCephDeployments(filter *k8s.DeploymentFilter, deployments []appsv1.Deployment, namespace, name string) []appsv1.Deployment {
+	var scaledDown []appsv1.Deployment
+	for _, dep := range filter.Filter(deployments) {
+		// The operator itself stays down while any deployment is scaled down
+		if (dep.Namespace == namespace && dep.Name == name) || dep.Name == operatorDeploymentName {
+			continue
+		}
+		if dep.Spec.Replicas != nil && *dep.Spec.Replicas == 0 {
+			scaledDown = append(scaledDown, dep)
+		}
+	}
+	return scaledDown
+}
+
+// sendDeploymentProgress safely calls the progress callback if it's not nil
+func sendDeploymentProgress(callback func(DeploymentPhaseProgress), stage, description string) {
+	if callback != nil {
+		callback(DeploymentPhaseProgress{
+			Stage:       stage,
+			Description: description,
+		})
+	}
+}
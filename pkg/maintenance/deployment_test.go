@@ -0,0 +1,217 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestScaledDownCephDeployments(t *testing.T) {
+	t.Parallel()
+
+	withReplicas := func(name string, replicas int32) appsv1.Deployment {
+		dep := makeTestDeployment(name)
+		dep.Spec.Replicas = &replicas
+		return dep
+	}
+
+	tests := []struct {
+		name        string
+		deployments []appsv1.Deployment
+		want        []string
+	}{
+		{
+			name: "only the restored deployment",
+			deployments: []appsv1.Deployment{
+				withReplicas("rook-ceph-osd-0", 0),
+				withReplicas("rook-ceph-osd-1", 1),
+			},
+		},
+		{
+			name: "operator is ignored",
+			deployments: []appsv1.Deployment{
+				withReplicas("rook-ceph-osd-0", 0),
+				withReplicas(operatorDeploymentName, 0),
+			},
+		},
+		{
+			name: "non-ceph deployments are ignored",
+			deployments: []appsv1.Deployment{
+				withReplicas("csi-rbdplugin-provisioner", 0),
+			},
+		},
+		{
+			name: "other maintenance in progress",
+			deployments: []appsv1.Deployment{
+				withReplicas("rook-ceph-osd-0", 0),
+				withReplicas("rook-ceph-osd-2", 0),
+				withReplicas("rook-ceph-mon-b", 0),
+			},
+			want: []string{"rook-ceph-osd-2", "rook-ceph-mon-b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := k8s.NewDeploymentFilter(config.DeploymentFiltersConfig{})
+			if err != nil {
+				t.Fatalf("NewDeploymentFilter() error = %v", err)
+			}
+			got := scaledDownCephDeployments(filter, tt.deployments, "rook-ceph", "rook-ceph-osd-0")
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d deployments, want %d", len(got), len(tt.want))
+			}
+			for i, dep := range got {
+				if dep.Name != tt.want[i] {
+					t.Errorf("deployment %d = %s, want %s", i, dep.Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDeploymentRestoreReplicas(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        int32
+	}{
+		{name: "original replicas recorded", annotations: map[string]string{k8s.OriginalReplicasAnnotation: "3"}, want: 3},
+		{name: "no annotation", want: 1},
+		{name: "invalid annotation", annotations: map[string]string{k8s.OriginalReplicasAnnotation: "many"}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dep := makeTestDeployment("rook-ceph-osd-0")
+			dep.Annotations = tt.annotations
+			if got := DeploymentRestoreReplicas(&dep); got != tt.want {
+				t.Errorf("DeploymentRestoreReplicas() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// pinnedTestDeployment returns a deployment pinned to the node, at 0 replicas
+func pinnedTestDeployment(name, nodeName string, annotations map[string]string) appsv1.Deployment {
+	dep := makeTestDeployment(name)
+	dep.Annotations = annotations
+	dep.Spec.Replicas = new(int32)
+	dep.Spec.Template.Spec.NodeSelector = map[string]string{"kubernetes.io/hostname": nodeName}
+	return dep
+}
+
+func TestExecuteDeploymentDown_RefusesUnpinnedDeployment(t *testing.T) {
+	dep := makeTestDeployment("rook-ceph-mgr-a")
+	clientset := fake.NewClientset(&dep)
+	client := &k8s.Client{Clientset: clientset}
+
+	err := ExecuteDeploymentDown(context.Background(), client, config.DefaultConfig(), "rook-ceph", "rook-ceph-mgr-a", DeploymentPhaseOptions{})
+	if !errors.Is(err, ErrValidationFailed) {
+		t.Fatalf("ExecuteDeploymentDown() error = %v, want ErrValidationFailed", err)
+	}
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() != "get" {
+			t.Errorf("unexpected %s %s before pre-flight", action.GetVerb(), action.GetResource().Resource)
+		}
+	}
+}
+
+func TestNoOutDependents(t *testing.T) {
+	t.Parallel()
+
+	remaining := []appsv1.Deployment{
+		pinnedTestDeployment("rook-ceph-osd-1", "worker-1", nil),
+		pinnedTestDeployment("rook-ceph-osd-5", "worker-2", nil),
+	}
+
+	tests := []struct {
+		name  string
+		scope NoOutScope
+		want  []string
+	}{
+		{name: "cluster scope", scope: NoOutScope{Scope: config.NoOutScopeCluster}, want: []string{"rook-ceph-osd-1", "rook-ceph-osd-5"}},
+		{name: "host scope", scope: NoOutScope{Scope: config.NoOutScopeHost, Groups: []string{"worker-1"}}, want: []string{"rook-ceph-osd-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := noOutDependents(tt.scope, "worker-1", remaining)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d deployments, want %d", len(got), len(tt.want))
+			}
+			for i, dep := range got {
+				if dep.Name != tt.want[i] {
+					t.Errorf("deployment %d = %s, want %s", i, dep.Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUnsetDeploymentNoOut_NotRecorded(t *testing.T) {
+	restored := pinnedTestDeployment("rook-ceph-osd-0", "worker-1", nil)
+	clientset := fake.NewClientset(&restored)
+	client := &k8s.Client{Clientset: clientset}
+
+	var stages []DeploymentPhaseProgress
+	err := unsetDeploymentNoOut(context.Background(), client, config.DefaultConfig(), &restored, nil, func(p DeploymentPhaseProgress) {
+		stages = append(stages, p)
+	})
+	if err != nil {
+		t.Fatalf("unsetDeploymentNoOut() error = %v", err)
+	}
+	if len(clientset.Actions()) != 0 {
+		t.Errorf("actions = %v, want a flag set by someone else left alone", clientset.Actions())
+	}
+	if len(stages) != 1 || stages[0].Stage != "unset-noout" {
+		t.Errorf("progress = %v, want one unset-noout update", stages)
+	}
+}
+
+func TestUnsetDeploymentNoOut_HandsRecordToDependents(t *testing.T) {
+	recorded := map[string]string{k8s.NoOutScopeAnnotation: `{"scope":"host","groups":["worker-1"]}`}
+	restored := pinnedTestDeployment("rook-ceph-osd-0", "worker-1", recorded)
+	sameNode := pinnedTestDeployment("rook-ceph-osd-1", "worker-1", nil)
+	otherNode := pinnedTestDeployment("rook-ceph-osd-5", "worker-2", nil)
+	clientset := fake.NewClientset(&restored, &sameNode, &otherNode)
+	client := &k8s.Client{Clientset: clientset}
+	ctx := context.Background()
+
+	remaining := []appsv1.Deployment{sameNode, otherNode}
+	if err := unsetDeploymentNoOut(ctx, client, config.DefaultConfig(), &restored, remaining, nil); err != nil {
+		t.Fatalf("unsetDeploymentNoOut() error = %v", err)
+	}
+
+	annotation := func(name string) (string, bool) {
+		dep, err := clientset.AppsV1().Deployments("rook-ceph").Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Get(%s) error = %v", name, err)
+		}
+		value, ok := dep.Annotations[k8s.NoOutScopeAnnotation]
+		return value, ok
+	}
+	if value, ok := annotation("rook-ceph-osd-0"); ok {
+		t.Errorf("restored deployment still records %s", value)
+	}
+	if value, _ := annotation("rook-ceph-osd-1"); value != recorded[k8s.NoOutScopeAnnotation] {
+		t.Errorf("deployment on the same node records %q, want %q", value, recorded[k8s.NoOutScopeAnnotation])
+	}
+	if value, ok := annotation("rook-ceph-osd-5"); ok {
+		t.Errorf("deployment on another node records %s", value)
+	}
+}
@@ -3,7 +3,10 @@ package maintenance
 import (
 	"strings"
 
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
 	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // OrderDeploymentsForDown returns deployments ordered for safe down phase.
@@ -79,3 +82,40 @@ func orderByPrefixes(deployments []appsv1.Deployment, prefixOrder []string) []ap
 
 	return ordered
 }
+
+// FilterByPrefixes narrows discovered deployments to those whose names start
+// with one of the given prefixes. An empty prefix list keeps every deployment.
+func FilterByPrefixes(deployments []appsv1.Deployment, prefixes []string) []appsv1.Deployment {
+	if len(prefixes) == 0 {
+		return deployments
+	}
+	return k8s.FilterDeploymentsByPrefix(deployments, prefixes)
+}
+
+// ExcludeDeployments splits discovered deployments into those the phases act
+// on and those discovery.exclude or discovery.filters.exclude keeps running,
+// preserving their order
+func ExcludeDeployments(deployments []appsv1.Deployment, discovery config.DiscoveryConfig) (kept, excluded []appsv1.Deployment) {
+	excludes := discoveryExcludes(discovery)
+	for i, deployment := range deployments {
+		if excludes(&deployments[i]) {
+			excluded = append(excluded, deployment)
+		} else {
+			kept = append(kept, deployment)
+		}
+	}
+	return kept, excluded
+}
+
+// discoveryExcludes returns whether discovery skips an object: its name is in
+// discovery.exclude or it matches a discovery.filters.exclude rule
+func discoveryExcludes(discovery config.DiscoveryConfig) func(metav1.Object) bool {
+	filter, err := k8s.NewDeploymentFilter(config.DeploymentFiltersConfig{Exclude: discovery.Filters.Exclude})
+	if err != nil {
+		// Validated when the config is loaded; discovery.exclude still applies
+		filter, _ = k8s.NewDeploymentFilter(config.DeploymentFiltersConfig{})
+	}
+	return func(obj metav1.Object) bool {
+		return discovery.Excludes(obj.GetName()) || filter.Excludes(obj)
+	}
+}
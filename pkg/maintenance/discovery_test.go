@@ -1,11 +1,17 @@
 package maintenance
 
 import (
+	"context"
+	"slices"
 	"testing"
 
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestOrderDeploymentsForDown(t *testing.T) {
@@ -128,6 +134,65 @@ func TestOrderDeploymentsForUp_EmptyInput(t *testing.T) {
 	}
 }
 
+func TestFilterByPrefixes(t *testing.T) {
+	deployments := []appsv1.Deployment{
+		*createDeployment("rook-ceph-osd-0"),
+		*createDeployment("rook-ceph-mon-a"),
+		*createDeployment("rook-ceph-mgr-a"),
+	}
+
+	tests := []struct {
+		name     string
+		prefixes []string
+		want     []string
+	}{
+		{name: "no prefixes keeps all", want: []string{"rook-ceph-osd-0", "rook-ceph-mon-a", "rook-ceph-mgr-a"}},
+		{name: "single prefix", prefixes: []string{"rook-ceph-mgr"}, want: []string{"rook-ceph-mgr-a"}},
+		{name: "multiple prefixes", prefixes: []string{"rook-ceph-osd", "rook-ceph-mon"}, want: []string{"rook-ceph-osd-0", "rook-ceph-mon-a"}},
+		{name: "no match", prefixes: []string{"rook-ceph-rgw"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := FilterByPrefixes(deployments, tt.prefixes)
+			var names []string
+			for _, dep := range filtered {
+				names = append(names, dep.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("FilterByPrefixes() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcludeDeployments(t *testing.T) {
+	deployments := []appsv1.Deployment{
+		*createDeployment("rook-ceph-osd-0"),
+		*createDeployment("rook-ceph-tools"),
+		*createDeployment("custom-exporter-worker-1"),
+		*createDeployment("rook-ceph-mon-a"),
+	}
+
+	kept, excluded := ExcludeDeployments(deployments, config.DiscoveryConfig{
+		Exclude: []string{"rook-ceph-tools", "custom-exporter-.*"},
+	})
+
+	names := func(deps []appsv1.Deployment) []string {
+		var result []string
+		for _, dep := range deps {
+			result = append(result, dep.Name)
+		}
+		return result
+	}
+	if want := []string{"rook-ceph-osd-0", "rook-ceph-mon-a"}; !slices.Equal(names(kept), want) {
+		t.Errorf("kept = %v, want %v", names(kept), want)
+	}
+	if want := []string{"rook-ceph-tools", "custom-exporter-worker-1"}; !slices.Equal(names(excluded), want) {
+		t.Errorf("excluded = %v, want %v", names(excluded), want)
+	}
+}
+
 // Helper functions
 
 func createDeployment(name string) *appsv1.Deployment {
@@ -143,3 +208,49 @@ func createDeployment(name string) *appsv1.Deployment {
 		},
 	}
 }
+
+func TestExcludeDeployments_DownDiscovery(t *testing.T) {
+	pinned := func(name string, labels map[string]string) *appsv1.Deployment {
+		dep := createDeployment(name)
+		dep.Labels = labels
+		dep.Spec.Template.Spec.NodeSelector = map[string]string{corev1.LabelHostname: "worker-1"}
+		return dep
+	}
+	client := &k8s.Client{Clientset: fake.NewClientset(
+		pinned("rook-ceph-osd-0", nil),
+		pinned("rook-ceph-osd-canary", nil),
+		pinned("rook-ceph-mon-a", map[string]string{"crook.io/skip": "true"}),
+		pinned("rook-ceph-tools", nil),
+	)}
+
+	discovery := config.DiscoveryConfig{
+		Exclude: []string{"rook-ceph-tools"},
+		Filters: config.DeploymentFiltersConfig{Exclude: []config.DeploymentFilterRule{
+			{Regex: ".*-canary"},
+			{Selector: "crook.io/skip"},
+			// Anchored like discovery.exclude: matches no whole name
+			{Regex: "osd"},
+		}},
+	}
+
+	// The same discovery the down phase runs before scaling
+	discovered, err := client.ListNodePinnedDeployments(context.Background(), "rook-ceph", "worker-1")
+	if err != nil {
+		t.Fatalf("ListNodePinnedDeployments() error = %v", err)
+	}
+	kept, excluded := ExcludeDeployments(OrderDeploymentsForDown(discovered), discovery)
+
+	names := func(deps []appsv1.Deployment) []string {
+		var result []string
+		for _, dep := range deps {
+			result = append(result, dep.Name)
+		}
+		return result
+	}
+	if want := []string{"rook-ceph-osd-0"}; !slices.Equal(names(kept), want) {
+		t.Errorf("kept = %v, want %v", names(kept), want)
+	}
+	if want := []string{"rook-ceph-osd-canary", "rook-ceph-mon-a", "rook-ceph-tools"}; !slices.Equal(names(excluded), want) {
+		t.Errorf("excluded = %v, want %v", names(excluded), want)
+	}
+}
@@ -14,7 +14,15 @@ import (
 type DownPhaseProgress struct {
 	Stage       string
 	Description string
-	Deployment  string // Optional: current deployment being processed
+	Deployment  string       // Optional: current deployment being processed
+	Pod         string       // Optional: current pod being evicted by the drain stage
+	Check       *CheckStatus // Optional: the pre-flight check that just finished
+
+	// Sequence increases by one with every update of a run, starting at 1,
+	// so consumers can detect dropped updates (see DroppedUpdates)
+	Sequence uint64
+	// Percent is the share of the phase done, 0-100; it never decreases
+	Percent int
 }
 
 // DownPhaseOptions holds options for the down phase operation
@@ -25,10 +33,64 @@ type DownPhaseOptions struct {
 
 	// WaitOptions for deployment scaling operations
 	WaitOptions WaitOptions
+
+	// Prefixes limits discovery to deployments whose names start with one of
+	// these prefixes. Optional - if empty, every node-pinned deployment is scaled down.
+	Prefixes []string
+
+	// SignOff is recorded on the node and in the maintenance report. Optional.
+	SignOff SignOff
+
+	// Operator is who runs the phase, recorded in the maintenance report.
+	// Optional - defaults to CurrentOperator.
+	Operator string
+
+	// ApprovalID is the approved request ('crook down --request') the phase
+	// consumes. Required when policy.require-approval-before-down is set.
+	ApprovalID string
+
+	// Only restricts scaling to these workloads (namespace/name), e.g. the
+	// RetryKeys of a failed run's *crookerrors.MultiError. Optional - if
+	// empty, every discovered workload is scaled down.
+	Only []string
+
+	// AcknowledgeWarnings proceeds past failed SeverityWarn pre-flight
+	// checks (e.g. HEALTH_WARN, another node in maintenance). Without it
+	// they fail the phase with ErrAcknowledgmentRequired.
+	AcknowledgeWarnings bool
+
+	// AllowControlPlane permits taking down a control-plane node. Without it
+	// the phase fails with ErrControlPlaneNode before changing anything.
+	AllowControlPlane bool
+
+	// ResumeFrom is the progress stage a failed run stopped at. The steps
+	// before it are not repeated: resuming from "scale-down" skips
+	// pre-flight, cordon, noout and the operator. Optional - if empty, every
+	// step runs.
+	ResumeFrom string
+
+	// DeadNode handles a node that is NotReady or unreachable: node-local
+	// pre-flight checks are skipped, scaled-down pods are not waited for and
+	// the node is not drained, since its kubelet cannot confirm that pods
+	// stopped. The phase refuses a node that is Ready.
+	DeadNode bool
+
+	// ForceDeletePods force-deletes the pods left in Terminating on the node
+	// at the end of a DeadNode phase, so their controllers replace them
+	// elsewhere; drain.force-delete-on-dead-node sets it by default. Ignored
+	// without DeadNode.
+	ForceDeletePods bool
 }
 
 // ExecuteDownPhase orchestrates the complete node down phase workflow
-// Steps: pre-flight → cordon → set noout → scale operator → discover → scale deployments
+// Steps: pre-flight → cordon → set noout → scale operator → discover → scale deployments → scale statefulsets → drain
+// StatefulSets are only scaled when discovery.statefulsets is set, and the
+// drain step only runs when drain.enabled is set. With opts.ResumeFrom a
+// failed run continues at the step that failed. opts.DeadNode runs the
+// emergency variant for a node that is already down. Once pre-flight passes, the
+// configured PagerDuty/Opsgenie maintenance windows are opened. A failure is
+// recorded in the node's open maintenance report for 'crook history', and the
+// changes the phase applied for 'crook report'.
 func ExecuteDownPhase(
 	ctx context.Context,
 	client *k8s.Client,
@@ -36,56 +98,117 @@ func ExecuteDownPhase(
 	nodeName string,
 	opts DownPhaseOptions,
 ) error {
-	// Step 1: Pre-flight validation
-	updateProgress(opts.ProgressCallback, "pre-flight", "Running pre-flight validation checks", "")
+	mark := client.ChangeCount()
+	err := runDownPhase(ctx, client, cfg, nodeName, opts)
+	// The phase context may have timed out; the result should still be recorded
+	recordAppliedChanges(context.WithoutCancel(ctx), client, cfg, nodeName, client.ChangesSince(mark))
+	recordReportResult(context.WithoutCancel(ctx), client, cfg, nodeName, ScalePhaseDown, err)
+	return err
+}
 
-	validationResults, err := ValidateDownPhase(ctx, client, cfg, nodeName)
-	if err != nil {
-		return fmt.Errorf("pre-flight validation failed: %w", err)
+// runDownPhase runs the steps of ExecuteDownPhase
+func runDownPhase(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	nodeName string,
+	opts DownPhaseOptions,
+) error {
+	progress := newProgressSequence(downStageWeights)
+	opts.ProgressCallback = progress.downCallback(opts.ProgressCallback)
+
+	// A resumed run only repeats the step that failed and those after it
+	resume := newResumePlan(downSteps, downStepAliases, opts.ResumeFrom)
+	if resume.resumed() {
+		logger.Info("resuming down phase", "node", nodeName, "from", opts.ResumeFrom, "only", opts.Only)
 	}
-	if !validationResults.AllPassed {
-		return fmt.Errorf("pre-flight validation failed:\n%s", validationResults.String())
+
+	// Step 1: Pre-flight validation
+	if resume.runs("pre-flight") {
+		if err := startDownPhase(ctx, client, cfg, nodeName, opts); err != nil {
+			return err
+		}
 	}
 
 	// Step 2: Cordon node
-	updateProgress(opts.ProgressCallback, "cordon", fmt.Sprintf("Cordoning node %s", nodeName), "")
+	if resume.runs("cordon") {
+		updateProgress(opts.ProgressCallback, "cordon", fmt.Sprintf("Cordoning node %s", nodeName), "")
 
-	if cordonErr := client.CordonNode(ctx, nodeName); cordonErr != nil {
-		return fmt.Errorf("failed to cordon node %s: %w", nodeName, cordonErr)
+		if cordonErr := client.CordonNode(ctx, nodeName); cordonErr != nil {
+			return fmt.Errorf("failed to cordon node %s: %w", nodeName, cordonErr)
+		}
+		annotateSignOff(ctx, client, nodeName, opts.SignOff)
+		if cfg.Cloud.DisableScaleDown {
+			disableScaleDown(ctx, client, nodeName)
+		}
 	}
 
-	// Step 3: Set Ceph noout flag
-	updateProgress(opts.ProgressCallback, "noout", "Setting Ceph noout flag", "")
+	// Step 3: Set Ceph noout flag, cluster-wide or on the node's OSDs only
+	if resume.runs("noout") {
+		scope, scopeErr := ResolveNoOutScope(ctx, client, cfg, nodeName)
+		if scopeErr != nil {
+			return scopeErr
+		}
+		updateProgress(opts.ProgressCallback, "noout", fmt.Sprintf("Setting Ceph noout flag (%s)", scope), "")
 
-	if nooutErr := client.SetNoOut(ctx, cfg.Namespace); nooutErr != nil {
-		return fmt.Errorf("failed to set noout flag: %w", nooutErr)
+		if nooutErr := setNoOut(ctx, client, cfg, scope); nooutErr != nil {
+			return fmt.Errorf("failed to set noout flag: %w", nooutErr)
+		}
+		recordReportNoOutScope(ctx, client, cfg, nodeName, scope)
+		annotateNoOutScope(ctx, client, nodeName, scope)
+		armNoOutWatchdog(ctx, client, cfg, nodeName, scope, opts.ProgressCallback)
+	}
+
+	// External clusters have no Rook daemons on the node to scale
+	if IsExternalCluster(ctx, client, cfg) {
+		return completeDownPhase(ctx, client, cfg, nodeName, opts,
+			"External Ceph cluster - no deployments to scale, down phase complete")
 	}
 
 	// Step 4: Scale down rook-ceph-operator
-	updateProgress(opts.ProgressCallback, "operator", "Scaling down rook-ceph-operator to 0", "")
+	if resume.runs("operator") {
+		updateProgress(opts.ProgressCallback, "operator", "Scaling down rook-ceph-operator to 0", "")
+
+		operatorName := "rook-ceph-operator"
+		operatorNamespace := cfg.Namespace
 
-	operatorName := "rook-ceph-operator"
-	operatorNamespace := cfg.Namespace
+		if scaleErr := client.ScaleDeployment(ctx, operatorNamespace, operatorName, 0); scaleErr != nil {
+			return fmt.Errorf("failed to scale operator to 0: %w", scaleErr)
+		}
 
-	if scaleErr := client.ScaleDeployment(ctx, operatorNamespace, operatorName, 0); scaleErr != nil {
-		return fmt.Errorf("failed to scale operator to 0: %w", scaleErr)
+		if waitErr := WaitForDeploymentScaleDown(ctx, client, operatorNamespace, operatorName, opts.WaitOptions); waitErr != nil {
+			return fmt.Errorf("failed waiting for operator to scale down: %w", waitErr)
+		}
 	}
 
-	if waitErr := WaitForDeploymentScaleDown(ctx, client, operatorNamespace, operatorName, opts.WaitOptions); waitErr != nil {
-		return fmt.Errorf("failed waiting for operator to scale down: %w", waitErr)
+	// Steps 5-7: Discover and scale down the node's workloads
+	if !resume.runs("scale-down") {
+		return completeDownPhase(ctx, client, cfg, nodeName, opts, "Down phase completed successfully")
 	}
 
 	// Step 5: Discover node-pinned deployments via nodeSelector
 	updateProgress(opts.ProgressCallback, "discover", fmt.Sprintf("Discovering node-pinned deployments on %s", nodeName), "")
 
-	deployments, err := client.ListNodePinnedDeployments(ctx, cfg.Namespace, nodeName)
+	discovered, err := client.ListNodePinnedDeployments(ctx, cfg.Namespace, nodeName)
 	if err != nil {
 		return fmt.Errorf("failed to discover node-pinned deployments: %w", err)
 	}
+	deployments, excluded := ExcludeDeployments(FilterByPrefixes(discovered, opts.Prefixes), cfg.Discovery)
+	for _, dep := range excluded {
+		logger.Info("skipping excluded deployment", "deployment", dep.Namespace+"/"+dep.Name)
+	}
+
+	statefulSets, excludedSets, err := DiscoverStatefulSets(ctx, client, cfg, nodeName, opts.Prefixes)
+	if err != nil {
+		return err
+	}
+	for _, sts := range excludedSets {
+		logger.Info("skipping excluded statefulset", "statefulset", sts.Namespace+"/"+sts.Name)
+	}
 
-	if len(deployments) == 0 {
-		updateProgress(opts.ProgressCallback, "complete", "No node-pinned deployments found - down phase complete", "")
-		return nil
+	if len(deployments) == 0 && len(statefulSets) == 0 {
+		return completeDownPhase(ctx, client, cfg, nodeName, opts,
+			"No node-pinned deployments found - down phase complete")
 	}
 
 	// Warn on unexpected replica counts (>1)
@@ -97,30 +220,123 @@ func ExecuteDownPhase(
 	// See OrderDeploymentsForDown documentation for the full rationale.
 	orderedDeployments := OrderDeploymentsForDown(deployments)
 
-	// Step 7: Scale down each deployment and wait
-	for _, deployment := range orderedDeployments {
-		deploymentName := fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name)
+	// Step 7: Scale down each deployment, then the StatefulSets (discovery.statefulsets), and wait
+	workloads := append(k8s.DeploymentWorkloads(orderedDeployments), k8s.StatefulSetWorkloads(statefulSets)...)
+	RecordPlannedWorkloads(ctx, client, cfg, nodeName, workloads)
+	workloads = selectWorkloads(workloads, opts.Only)
+	plan := NewPartialPlan(workloads)
+	progress.expect("scale-down", plan.ToScale)
+	if plan.Partial() {
+		updateProgress(opts.ProgressCallback, "skip", "Resuming partial down phase: "+plan.String(), "")
+	}
+	history := loadScaleHistory(ctx, client, cfg)
+	scaleErr := scaleDownWorkloads(ctx, client, workloads, history, opts)
+	saveScaleHistory(ctx, client, cfg, history)
+	if scaleErr != nil {
+		return scaleErr
+	}
 
-		// Skip deployments already at 0 replicas
-		if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0 {
-			logger.Debug("skipping deployment already at 0 replicas", "deployment", deploymentName)
-			continue
-		}
+	// Step 8: Drain (optional) and complete
+	return completeDownPhase(ctx, client, cfg, nodeName, opts, "Down phase completed successfully")
+}
 
-		updateProgress(opts.ProgressCallback, "scale-down", fmt.Sprintf("Scaling down %s to 0", deploymentName), deploymentName)
+// startDownPhase runs the pre-flight checks, consumes the approval and
+// records the start of maintenance: the steps before the node changes
+func startDownPhase(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, opts DownPhaseOptions) error {
+	updateProgress(opts.ProgressCallback, "pre-flight", "Running pre-flight validation checks", "")
 
-		if scaleErr := client.ScaleDeployment(ctx, deployment.Namespace, deployment.Name, 0); scaleErr != nil {
-			return fmt.Errorf("failed to scale deployment %s to 0: %w", deploymentName, scaleErr)
+	if !opts.AllowControlPlane {
+		if err := RefuseControlPlane(ctx, client, nodeName); err != nil {
+			return fmt.Errorf("%w: %w", ErrValidationFailed, err)
 		}
+	}
+	if opts.DeadNode {
+		if err := RefuseReadyNode(ctx, client, nodeName); err != nil {
+			return fmt.Errorf("%w: %w", ErrValidationFailed, err)
+		}
+	}
 
-		if waitErr := WaitForDeploymentScaleDown(ctx, client, deployment.Namespace, deployment.Name, opts.WaitOptions); waitErr != nil {
-			return fmt.Errorf("failed waiting for deployment %s to scale down: %w", deploymentName, waitErr)
+	err := runDownGate(ctx, client, cfg, nodeName, opts.DeadNode, opts.AcknowledgeWarnings, opts.ApprovalID, func(check CheckStatus) {
+		if opts.ProgressCallback != nil {
+			opts.ProgressCallback(DownPhaseProgress{Stage: preflightCheckStage, Description: check.String(), Check: &check})
 		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// Silence the affected services' alerts in PagerDuty/Opsgenie (best-effort)
+	openMaintenanceWindows(ctx, client, cfg, nodeName)
+
+	// Record the pre-maintenance state for 'crook report' (best-effort)
+	RecordBeforeSnapshot(ctx, client, cfg, nodeName)
+	recordReportRun(ctx, client, cfg, nodeName, ScalePhaseDown, opts.Operator, opts.SignOff)
+	if opts.DeadNode {
+		recordReportDeadNode(ctx, client, cfg, nodeName)
 	}
+	return nil
+}
 
-	// Step 8: Complete
-	updateProgress(opts.ProgressCallback, "complete", "Down phase completed successfully", "")
+// runDownGate runs the down phase pre-flight checks for the node and, when
+// policy.require-approval-before-down is set or an approval is given,
+// consumes the node's approval. Nothing has changed when it fails.
+func runDownGate(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	nodeName string,
+	deadNode, acknowledgeWarnings bool,
+	approvalID string,
+	onCheck func(CheckStatus),
+) error {
+	validationResults, err := validateDownPhase(ctx, client, cfg, nodeName, deadNode, onCheck)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+	if err := validationResults.Check(acknowledgeWarnings); err != nil {
+		return err
+	}
+	for _, warning := range validationResults.Warnings {
+		logger.Warn("pre-flight warning", "warning", warning)
+	}
+
+	// Two-person rule: consume the approval before anything changes
+	if cfg.Policy.RequireApprovalBeforeDown || approvalID != "" {
+		if approvalErr := claimApproval(ctx, client, cfg, approvalID, nodeName); approvalErr != nil {
+			return fmt.Errorf("%w: %w", ErrValidationFailed, approvalErr)
+		}
+	}
+	return nil
+}
+
+// completeDownPhase drains the node when drain.enabled is set, or in
+// dead-node mode force-deletes its stuck pods when asked to, and reports the
+// end of the down phase
+func completeDownPhase(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	nodeName string,
+	opts DownPhaseOptions,
+	description string,
+) error {
+	switch {
+	case opts.DeadNode && (opts.ForceDeletePods || cfg.Drain.ForceDeleteOnDeadNode):
+		if err := forceDeleteTerminatingPods(ctx, client, nodeName, opts.ProgressCallback); err != nil {
+			return err
+		}
+	case opts.DeadNode:
+		// Evictions from a dead node never complete
+		if cfg.Drain.Enabled {
+			logger.Info("skipping drain of a dead node", "node", nodeName)
+		}
+	case cfg.Drain.Enabled:
+		if err := drainNode(ctx, client, cfg, nodeName, opts.ProgressCallback); err != nil {
+			return err
+		}
+	}
 
+	updateProgress(opts.ProgressCallback, "complete", description, "")
 	return nil
 }
 
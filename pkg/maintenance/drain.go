@@ -0,0 +1,68 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// Progress stages reported while the node is drained
+const (
+	drainStage         = "drain"
+	drainEvictingStage = "drain-evicting"
+	drainBlockedStage  = "drain-blocked"
+	drainEvictedStage  = "drain-evicted"
+)
+
+// DrainProgressStages lists the progress stages of the optional drain stage
+var DrainProgressStages = []string{drainStage, drainEvictingStage, drainBlockedStage, drainEvictedStage}
+
+// drainNode evicts the pods left on the node through the Eviction API,
+// honoring PodDisruptionBudgets, and reports each pod's progress. It is
+// bounded by drain.timeout-seconds.
+func drainNode(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, callback func(DownPhaseProgress)) error {
+	drainCtx, cancel := context.WithTimeout(ctx, time.Duration(cfg.Drain.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	pods, err := client.ListPodsToEvict(drainCtx, nodeName)
+	if err != nil {
+		return fmt.Errorf("failed to list pods to evict: %w", err)
+	}
+	updatePodProgress(callback, drainStage, fmt.Sprintf("Draining node %s: %d pod(s) to evict", nodeName, len(pods)), "")
+
+	opts := k8s.EvictionOptions{
+		OnProgress: func(p k8s.EvictionProgress) {
+			switch p.Status {
+			case k8s.EvictionEvicting:
+				updatePodProgress(callback, drainEvictingStage, "Evicting "+p.Pod, p.Pod)
+			case k8s.EvictionBlocked:
+				updatePodProgress(callback, drainBlockedStage, fmt.Sprintf("Eviction of %s blocked: %s", p.Pod, p.Message), p.Pod)
+			case k8s.EvictionEvicted:
+				updatePodProgress(callback, drainEvictedStage, "Evicted "+p.Pod, p.Pod)
+			}
+		},
+	}
+	if cfg.Drain.GracePeriodSeconds >= 0 {
+		grace := int64(cfg.Drain.GracePeriodSeconds)
+		opts.GracePeriodSeconds = &grace
+	}
+
+	if evictErr := client.EvictPods(drainCtx, pods, opts); evictErr != nil {
+		return fmt.Errorf("failed to drain node %s: %w", nodeName, evictErr)
+	}
+	return nil
+}
+
+// updatePodProgress safely calls the progress callback for a pod being evicted
+func updatePodProgress(callback func(DownPhaseProgress), stage, description, pod string) {
+	if callback != nil {
+		callback(DownPhaseProgress{
+			Stage:       stage,
+			Description: description,
+			Pod:         pod,
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package maintenance
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCompleteDownPhase_Drain(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		wantStages []string
+		wantPods   int
+	}{
+		{
+			name:       "disabled",
+			wantStages: []string{"complete"},
+			wantPods:   1,
+		},
+		{
+			name:       "enabled",
+			enabled:    true,
+			wantStages: []string{"drain", "drain-evicting", "drain-evicted", "complete"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewClientset(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps", UID: "web-uid"},
+				Spec:       corev1.PodSpec{NodeName: "worker-1"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			})
+			podsResource := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+			clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				eviction, ok := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+				if action.GetSubresource() != "eviction" || !ok {
+					return false, nil, nil
+				}
+				return true, nil, clientset.Tracker().Delete(podsResource, eviction.Namespace, eviction.Name)
+			})
+			client := &k8s.Client{Clientset: clientset}
+
+			cfg := config.DefaultConfig()
+			cfg.Drain.Enabled = tt.enabled
+
+			var stages []string
+			var pods []string
+			err := completeDownPhase(context.Background(), client, cfg, "worker-1", DownPhaseOptions{
+				ProgressCallback: func(p DownPhaseProgress) {
+					stages = append(stages, p.Stage)
+					if p.Pod != "" {
+						pods = append(pods, p.Pod)
+					}
+				},
+			}, "done")
+			if err != nil {
+				t.Fatalf("completeDownPhase() error = %v", err)
+			}
+			if !slices.Equal(stages, tt.wantStages) {
+				t.Errorf("stages = %v, want %v", stages, tt.wantStages)
+			}
+			for _, pod := range pods {
+				if pod != "apps/web" {
+					t.Errorf("progress pod = %q, want apps/web", pod)
+				}
+			}
+
+			remaining, err := clientset.CoreV1().Pods("apps").List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("list pods: %v", err)
+			}
+			if len(remaining.Items) != tt.wantPods {
+				t.Errorf("remaining pods = %d, want %d", len(remaining.Items), tt.wantPods)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// WaitForEndState blocks until the node reaches the end state of the phase
+// (IsInDownState or IsInUpState) and, with requireHealthOK, Ceph reports
+// HEALTH_OK, so pipelines can rely on the state once crook exits ('--wait').
+// It fails when ctx is done first.
+func WaitForEndState(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	nodeName, phase string,
+	prefixes []string,
+	requireHealthOK bool,
+	pollInterval time.Duration,
+) error {
+	if pollInterval == 0 {
+		pollInterval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		pending := endStatePending(ctx, client, cfg, nodeName, phase, prefixes, requireHealthOK)
+		if pending == "" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("node %s did not reach the %s state (%s): %w", nodeName, phase, pending, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// endStatePending returns what the node still lacks to be in the end state of
+// the phase, or "" once it is reached
+func endStatePending(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	nodeName, phase string,
+	prefixes []string,
+	requireHealthOK bool,
+) string {
+	var reached bool
+	switch phase {
+	case ScalePhaseDown:
+		discovered, err := client.ListNodePinnedDeployments(ctx, cfg.Namespace, nodeName)
+		if err != nil {
+			return fmt.Sprintf("listing deployments: %v", err)
+		}
+		reached = IsInDownState(ctx, client, cfg, nodeName, filterEndStateDeployments(discovered, prefixes, cfg))
+	case ScalePhaseUp:
+		discovered, err := client.ListScaledDownDeploymentsForNode(ctx, cfg.Namespace, nodeName)
+		if err != nil {
+			return fmt.Sprintf("listing deployments: %v", err)
+		}
+		reached = IsInUpState(ctx, client, cfg, nodeName, filterEndStateDeployments(discovered, prefixes, cfg))
+	default:
+		return fmt.Sprintf("unknown phase %q", phase)
+	}
+	if !reached {
+		return "node not yet " + phase
+	}
+
+	if requireHealthOK {
+		status, err := client.GetCephStatus(ctx, cfg.Namespace)
+		if err != nil {
+			return fmt.Sprintf("reading Ceph health: %v", err)
+		}
+		if !status.IsHealthy() {
+			return "Ceph health " + status.Health.Status
+		}
+	}
+	return ""
+}
+
+// filterEndStateDeployments narrows discovered deployments the way the phase did
+func filterEndStateDeployments(discovered []appsv1.Deployment, prefixes []string, cfg config.Config) []appsv1.Deployment {
+	deployments, _ := ExcludeDeployments(FilterByPrefixes(discovered, prefixes), cfg.Discovery)
+	return deployments
+}
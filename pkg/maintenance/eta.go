@@ -0,0 +1,210 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Scale history storage: one ConfigMap per cluster holds the time each
+// workload took to scale in earlier runs
+const (
+	scaleHistoryConfigMapName = "crook-scale-history"
+	scaleHistoryDataKey       = "history.json"
+)
+
+// Phases a scale timing is recorded for
+const (
+	ScalePhaseDown = "down"
+	ScalePhaseUp   = "up"
+)
+
+// etaWindow is how many of a run's latest scale durations the rolling ETA averages
+const etaWindow = 5
+
+// ScaleTiming is the running average time a workload took to scale
+type ScaleTiming struct {
+	AverageSeconds float64 `json:"average_seconds"`
+	Samples        int     `json:"samples"`
+}
+
+// ScaleHistory holds the scale timings of a cluster's workloads, keyed by
+// phase and namespace/name, e.g. "down:rook-ceph/rook-ceph-osd-0"
+type ScaleHistory struct {
+	Timings map[string]ScaleTiming `json:"timings"`
+}
+
+// scaleHistoryKey returns the history key of a workload in a phase
+func scaleHistoryKey(phase, workload string) string {
+	return phase + ":" + workload
+}
+
+// Record adds a measured scale duration to the workload's average. A nil
+// history records nothing.
+func (h *ScaleHistory) Record(phase, workload string, d time.Duration) {
+	if h == nil {
+		return
+	}
+	if h.Timings == nil {
+		h.Timings = make(map[string]ScaleTiming)
+	}
+	key := scaleHistoryKey(phase, workload)
+	timing := h.Timings[key]
+	timing.AverageSeconds = (timing.AverageSeconds*float64(timing.Samples) + d.Seconds()) / float64(timing.Samples+1)
+	timing.Samples++
+	h.Timings[key] = timing
+}
+
+// Estimate returns the workload's average scale duration in the phase or,
+// for a workload never seen before, the average over the phase's workloads
+func (h *ScaleHistory) Estimate(phase, workload string) (time.Duration, bool) {
+	if h == nil || len(h.Timings) == 0 {
+		return 0, false
+	}
+	if timing, ok := h.Timings[scaleHistoryKey(phase, workload)]; ok {
+		return secondsDuration(timing.AverageSeconds), true
+	}
+
+	var total float64
+	var count int
+	prefix := scaleHistoryKey(phase, "")
+	for key, timing := range h.Timings {
+		if strings.HasPrefix(key, prefix) {
+			total += timing.AverageSeconds
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return secondsDuration(total / float64(count)), true
+}
+
+// secondsDuration converts fractional seconds to a duration
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// LoadScaleHistory returns the cluster's scale history, empty when none has
+// been recorded yet
+func LoadScaleHistory(ctx context.Context, client *k8s.Client, cfg config.Config) (*ScaleHistory, error) {
+	history := &ScaleHistory{Timings: make(map[string]ScaleTiming)}
+
+	cm, err := client.GetConfigMap(ctx, cfg.Namespace, scaleHistoryConfigMapName)
+	if apierrors.IsNotFound(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := cm.Data[scaleHistoryDataKey]
+	if !ok {
+		return history, nil
+	}
+	if err := json.Unmarshal([]byte(data), history); err != nil {
+		return nil, fmt.Errorf("failed to parse scale history: %w", err)
+	}
+	return history, nil
+}
+
+// SaveScaleHistory stores the cluster's scale history
+func SaveScaleHistory(ctx context.Context, client *k8s.Client, cfg config.Config, history *ScaleHistory) error {
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to encode scale history: %w", err)
+	}
+
+	return client.ApplyConfigMap(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      scaleHistoryConfigMapName,
+			Namespace: cfg.Namespace,
+			Labels:    map[string]string{k8s.JobLabelName: "crook"},
+		},
+		Data: map[string]string{scaleHistoryDataKey: string(data)},
+	})
+}
+
+// loadScaleHistory loads the scale history for a phase run. Best-effort: a
+// history that cannot be read starts empty.
+func loadScaleHistory(ctx context.Context, client *k8s.Client, cfg config.Config) *ScaleHistory {
+	history, err := LoadScaleHistory(ctx, client, cfg)
+	if err != nil {
+		logger.Debug("failed to load scale history", "error", err)
+		return &ScaleHistory{Timings: make(map[string]ScaleTiming)}
+	}
+	return history
+}
+
+// saveScaleHistory stores the timings measured by a phase run. Best-effort.
+func saveScaleHistory(ctx context.Context, client *k8s.Client, cfg config.Config, history *ScaleHistory) {
+	if err := SaveScaleHistory(ctx, client, cfg, history); err != nil {
+		logger.Debug("failed to save scale history", "error", err)
+	}
+}
+
+// ScaleETA estimates the time left to scale a phase's workloads from the
+// durations measured so far in the run, falling back to the cluster's
+// history before the first workload is done
+type ScaleETA struct {
+	phase   string
+	history *ScaleHistory
+	recent  []time.Duration
+}
+
+// NewScaleETA creates an estimator for a phase; history may be nil
+func NewScaleETA(phase string, history *ScaleHistory) *ScaleETA {
+	return &ScaleETA{phase: phase, history: history}
+}
+
+// Observe records the time a workload of this run took to scale
+func (e *ScaleETA) Observe(d time.Duration) {
+	e.recent = append(e.recent, d)
+	if len(e.recent) > etaWindow {
+		e.recent = e.recent[len(e.recent)-etaWindow:]
+	}
+}
+
+// estimate returns the expected time to scale one workload
+func (e *ScaleETA) estimate(workload string) (time.Duration, bool) {
+	if len(e.recent) > 0 {
+		var total time.Duration
+		for _, d := range e.recent {
+			total += d
+		}
+		return total / time.Duration(len(e.recent)), true
+	}
+	return e.history.Estimate(e.phase, workload)
+}
+
+// Remaining returns the time left to scale the pending workloads and the
+// one in progress, which has been scaling for currentElapsed. ok is false
+// when there is nothing to base an estimate on yet.
+func (e *ScaleETA) Remaining(pending []string, current string, currentElapsed time.Duration) (time.Duration, bool) {
+	var remaining time.Duration
+	known := false
+	if current != "" {
+		if d, ok := e.estimate(current); ok {
+			remaining += max(d-currentElapsed, 0)
+			known = true
+		}
+	}
+	for _, workload := range pending {
+		d, ok := e.estimate(workload)
+		if !ok {
+			return 0, false
+		}
+		remaining += d
+		known = true
+	}
+	return remaining, known
+}
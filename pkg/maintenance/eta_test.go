@@ -0,0 +1,93 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestScaleHistory_Estimate(t *testing.T) {
+	history := &ScaleHistory{}
+	history.Record(ScalePhaseDown, "rook-ceph/rook-ceph-osd-0", 10*time.Second)
+	history.Record(ScalePhaseDown, "rook-ceph/rook-ceph-osd-0", 20*time.Second)
+	history.Record(ScalePhaseDown, "rook-ceph/rook-ceph-mon-a", 30*time.Second)
+
+	tests := []struct {
+		name     string
+		phase    string
+		workload string
+		want     time.Duration
+		wantOK   bool
+	}{
+		{"workload average", ScalePhaseDown, "rook-ceph/rook-ceph-osd-0", 15 * time.Second, true},
+		{"unseen workload uses phase average", ScalePhaseDown, "rook-ceph/rook-ceph-osd-1", 22500 * time.Millisecond, true},
+		{"no history for phase", ScalePhaseUp, "rook-ceph/rook-ceph-osd-0", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := history.Estimate(tt.phase, tt.workload)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Estimate() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSaveAndLoadScaleHistory(t *testing.T) {
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	cfg := config.DefaultConfig()
+
+	empty, err := LoadScaleHistory(ctx, client, cfg)
+	if err != nil {
+		t.Fatalf("LoadScaleHistory() error = %v", err)
+	}
+	if len(empty.Timings) != 0 {
+		t.Errorf("LoadScaleHistory() without ConfigMap = %v, want empty", empty.Timings)
+	}
+
+	empty.Record(ScalePhaseUp, "rook-ceph/rook-ceph-osd-0", 12*time.Second)
+	if err := SaveScaleHistory(ctx, client, cfg, empty); err != nil {
+		t.Fatalf("SaveScaleHistory() error = %v", err)
+	}
+
+	loaded, err := LoadScaleHistory(ctx, client, cfg)
+	if err != nil {
+		t.Fatalf("LoadScaleHistory() error = %v", err)
+	}
+	if got, ok := loaded.Estimate(ScalePhaseUp, "rook-ceph/rook-ceph-osd-0"); !ok || got != 12*time.Second {
+		t.Errorf("loaded estimate = %v, %v, want 12s", got, ok)
+	}
+}
+
+func TestScaleETA_Remaining(t *testing.T) {
+	history := &ScaleHistory{}
+	history.Record(ScalePhaseDown, "rook-ceph/rook-ceph-osd-0", 20*time.Second)
+	pending := []string{"rook-ceph/rook-ceph-osd-1", "rook-ceph/rook-ceph-osd-2"}
+
+	if _, ok := NewScaleETA(ScalePhaseDown, nil).Remaining(pending, "", 0); ok {
+		t.Error("Remaining() without history or measurements should be unknown")
+	}
+
+	eta := NewScaleETA(ScalePhaseDown, history)
+	if got, ok := eta.Remaining(pending, "rook-ceph/rook-ceph-osd-0", 5*time.Second); !ok || got != 55*time.Second {
+		t.Errorf("Remaining() from history = %v, %v, want 55s", got, ok)
+	}
+
+	// Measurements of the run replace history
+	eta.Observe(10 * time.Second)
+	eta.Observe(20 * time.Second)
+	if got, ok := eta.Remaining(pending, "", 0); !ok || got != 30*time.Second {
+		t.Errorf("Remaining() from measurements = %v, %v, want 30s", got, ok)
+	}
+
+	// A workload slower than the estimate does not count negative time
+	if got, _ := eta.Remaining(nil, "rook-ceph/rook-ceph-osd-1", time.Minute); got != 0 {
+		t.Errorf("Remaining() for an overdue workload = %v, want 0", got)
+	}
+}
@@ -0,0 +1,45 @@
+package maintenance
+
+import (
+	"context"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// ExternalClusterNote marks plans for an external Ceph cluster, whose daemons
+// run outside Kubernetes and are not scaled by crook
+const ExternalClusterNote = "External Ceph cluster: only the node cordon and the noout flag are managed; no Rook daemons are scaled"
+
+// IsExternalCluster reports whether crook manages an external Ceph cluster
+// consumed through Rook external mode. It is forced by ceph.external and
+// otherwise detected from spec.external.enable of the selected CephClusters.
+// Detection is best-effort: an unreadable CephCluster means a Rook-managed cluster.
+func IsExternalCluster(ctx context.Context, client *k8s.Client, cfg config.Config) bool {
+	if cfg.Ceph.External {
+		return true
+	}
+
+	clusters, err := client.ListCephClusters(ctx, cfg.Namespace)
+	if err != nil {
+		logger.Debug("skipping external cluster detection", "error", err)
+		return false
+	}
+	clusters, err = SelectCephClusters(clusters, cfg.Cluster)
+	if err != nil {
+		logger.Debug("skipping external cluster detection", "error", err)
+		return false
+	}
+	return allExternal(clusters)
+}
+
+// allExternal reports whether there are clusters and all of them are external
+func allExternal(clusters []k8s.CephClusterSettings) bool {
+	for _, cluster := range clusters {
+		if !cluster.External {
+			return false
+		}
+	}
+	return len(clusters) > 0
+}
@@ -0,0 +1,49 @@
+package maintenance
+
+import (
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAllExternal(t *testing.T) {
+	tests := []struct {
+		name     string
+		clusters []k8s.CephClusterSettings
+		want     bool
+	}{
+		{name: "no clusters", want: false},
+		{name: "external", clusters: []k8s.CephClusterSettings{{Name: "ext", External: true}}, want: true},
+		{name: "rook managed", clusters: []k8s.CephClusterSettings{{Name: "rook-ceph"}}, want: false},
+		{
+			name:     "mixed",
+			clusters: []k8s.CephClusterSettings{{Name: "ext", External: true}, {Name: "rook-ceph"}},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allExternal(tt.clusters); got != tt.want {
+				t.Errorf("allExternal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsExternalCluster(t *testing.T) {
+	// Without a dynamic client the CephCluster cannot be read
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+
+	cfg := config.DefaultConfig()
+	if IsExternalCluster(t.Context(), client, cfg) {
+		t.Error("IsExternalCluster() = true for an unreadable CephCluster, want false")
+	}
+
+	cfg.Ceph.External = true
+	if !IsExternalCluster(t.Context(), client, cfg) {
+		t.Error("IsExternalCluster() = false with ceph.external set, want true")
+	}
+}
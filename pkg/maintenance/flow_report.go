@@ -0,0 +1,348 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// FlowReport is the timeline of a single down or up phase run
+type FlowReport struct {
+	Node        string
+	Phase       string
+	SignOff     SignOff
+	Started     time.Time
+	Finished    time.Time
+	Stages      []FlowStage
+	Deployments []string
+	Health      []HealthTransition
+	Error       string
+}
+
+// FlowStage is one progress stage of a phase run
+type FlowStage struct {
+	Stage       string
+	Description string
+	Deployment  string
+	Started     time.Time
+	Duration    time.Duration
+}
+
+// HealthTransition records the Ceph health status observed at a point in time
+type HealthTransition struct {
+	Time   time.Time
+	Status string
+}
+
+// Duration returns the total runtime of the phase
+func (r *FlowReport) Duration() time.Duration {
+	return r.Finished.Sub(r.Started)
+}
+
+// Succeeded reports whether the phase completed without error
+func (r *FlowReport) Succeeded() bool {
+	return r.Error == ""
+}
+
+// FlowRecorder builds a FlowReport from phase progress callbacks.
+// It is safe for concurrent use.
+type FlowRecorder struct {
+	mu     sync.Mutex
+	report FlowReport
+	now    func() time.Time
+}
+
+// NewFlowRecorder starts recording a phase run for a node
+func NewFlowRecorder(nodeName, phase string) *FlowRecorder {
+	return newFlowRecorder(nodeName, phase, time.Now)
+}
+
+// newFlowRecorder creates a recorder with an injectable clock
+func newFlowRecorder(nodeName, phase string, now func() time.Time) *FlowRecorder {
+	return &FlowRecorder{
+		report: FlowReport{Node: nodeName, Phase: phase, Started: now()},
+		now:    now,
+	}
+}
+
+// SetSignOff records the reason and ticket the phase was run with
+func (r *FlowRecorder) SetSignOff(signOff SignOff) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.SignOff = signOff
+}
+
+// OnDownProgress records a down phase progress update
+func (r *FlowRecorder) OnDownProgress(p DownPhaseProgress) {
+	r.record(p.Stage, p.Description, p.Deployment)
+}
+
+// OnUpProgress records an up phase progress update
+func (r *FlowRecorder) OnUpProgress(p UpPhaseProgress) {
+	r.record(p.Stage, p.Description, p.Deployment)
+}
+
+// record closes the current stage and opens a new one
+func (r *FlowRecorder) record(stage, description, deployment string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.closeStageLocked(now)
+	r.report.Stages = append(r.report.Stages, FlowStage{
+		Stage:       stage,
+		Description: description,
+		Deployment:  deployment,
+		Started:     now,
+	})
+	if deployment != "" && !slices.Contains(r.report.Deployments, deployment) {
+		r.report.Deployments = append(r.report.Deployments, deployment)
+	}
+}
+
+// closeStageLocked sets the duration of the last stage; caller must hold mu
+func (r *FlowRecorder) closeStageLocked(now time.Time) {
+	if n := len(r.report.Stages); n > 0 && r.report.Stages[n-1].Duration == 0 {
+		r.report.Stages[n-1].Duration = now.Sub(r.report.Stages[n-1].Started)
+	}
+}
+
+// RecordHealth adds a health observation when it differs from the previous one
+func (r *FlowRecorder) RecordHealth(status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n := len(r.report.Health); n > 0 && r.report.Health[n-1].Status == status {
+		return
+	}
+	r.report.Health = append(r.report.Health, HealthTransition{Time: r.now(), Status: status})
+}
+
+// SampleHealth reads the current Ceph health and records it (best-effort)
+func (r *FlowRecorder) SampleHealth(ctx context.Context, client *k8s.Client, cfg config.Config) {
+	status, err := client.GetCephStatus(ctx, cfg.Namespace)
+	if err != nil {
+		logger.Debug("skipping health sample for flow report", "error", err)
+		return
+	}
+	r.RecordHealth(status.Health.Status)
+}
+
+// Finish stops recording and returns the report. err is the phase result.
+func (r *FlowRecorder) Finish(err error) *FlowReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.report.Finished = r.now()
+	r.closeStageLocked(r.report.Finished)
+	if err != nil {
+		r.report.Error = err.Error()
+	}
+
+	report := r.report
+	report.Stages = append([]FlowStage(nil), r.report.Stages...)
+	report.Deployments = append([]string(nil), r.report.Deployments...)
+	report.Health = append([]HealthTransition(nil), r.report.Health...)
+	return &report
+}
+
+// WriteFlowReports writes Markdown and HTML renderings of the report to dir
+// and returns the written paths
+func WriteFlowReports(dir string, report *FlowReport) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create report directory %s: %w", dir, err)
+	}
+
+	base := fmt.Sprintf("crook-%s-%s-%s", report.Phase, report.Node, report.Started.UTC().Format("20060102T150405Z"))
+	renderers := []struct {
+		ext    string
+		render func(io.Writer, *FlowReport) error
+	}{
+		{ext: ".md", render: RenderFlowMarkdown},
+		{ext: ".html", render: RenderFlowHTML},
+	}
+
+	var paths []string
+	for _, r := range renderers {
+		path := filepath.Join(dir, base+r.ext)
+		if err := writeFlowReportFile(path, report, r.render); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// writeFlowReportFile renders the report into a new file
+func writeFlowReportFile(path string, report *FlowReport, render func(io.Writer, *FlowReport) error) error {
+	f, err := os.Create(path) //nolint:gosec // path is built from the user-provided report directory
+	if err != nil {
+		return fmt.Errorf("failed to create report %s: %w", path, err)
+	}
+	if renderErr := render(f, report); renderErr != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write report %s: %w", path, renderErr)
+	}
+	if closeErr := f.Close(); closeErr != nil {
+		return fmt.Errorf("failed to write report %s: %w", path, closeErr)
+	}
+	return nil
+}
+
+// RenderFlowMarkdown renders the report as Markdown
+func RenderFlowMarkdown(w io.Writer, report *FlowReport) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# crook %s %s\n\n", report.Phase, report.Node)
+	b.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Node | %s |\n", markdownCell(report.Node))
+	fmt.Fprintf(&b, "| Phase | %s |\n", report.Phase)
+	if report.SignOff.Ticket != "" {
+		fmt.Fprintf(&b, "| Ticket | %s |\n", markdownCell(report.SignOff.Ticket))
+	}
+	if report.SignOff.Reason != "" {
+		fmt.Fprintf(&b, "| Reason | %s |\n", markdownCell(report.SignOff.Reason))
+	}
+	fmt.Fprintf(&b, "| Started | %s |\n", formatReportTime(report.Started))
+	fmt.Fprintf(&b, "| Finished | %s |\n", formatReportTime(report.Finished))
+	fmt.Fprintf(&b, "| Duration | %s |\n", formatReportDuration(report.Duration()))
+	fmt.Fprintf(&b, "| Result | %s |\n", flowResult(report))
+
+	if report.Error != "" {
+		fmt.Fprintf(&b, "\n## Error\n\n```text\n%s\n```\n", report.Error)
+	}
+
+	if len(report.Health) > 0 {
+		b.WriteString("\n## Ceph Health\n\n| Time | Status |\n|---|---|\n")
+		for _, h := range report.Health {
+			fmt.Fprintf(&b, "| %s | %s |\n", formatReportTime(h.Time), markdownCell(h.Status))
+		}
+	}
+
+	b.WriteString("\n## Timeline\n\n| # | Stage | Description | Started | Duration |\n|---|---|---|---|---|\n")
+	for i, s := range report.Stages {
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | %s |\n", i+1,
+			markdownCell(s.Stage), markdownCell(s.Description), formatReportTime(s.Started), formatReportDuration(s.Duration))
+	}
+
+	if len(report.Deployments) > 0 {
+		b.WriteString("\n## Deployments\n\n")
+		for _, d := range report.Deployments {
+			fmt.Fprintf(&b, "- `%s`\n", d)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// flowReportHTML is the HTML rendering of a FlowReport
+var flowReportHTML = template.Must(template.New("flow").Funcs(template.FuncMap{
+	"time":     formatReportTime,
+	"duration": formatReportDuration,
+	"inc":      func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>crook {{.Report.Phase}} {{.Report.Node}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+.failed { color: #b00020; }
+.succeeded { color: #1b7f3b; }
+</style>
+</head>
+<body>
+<h1>crook {{.Report.Phase}} {{.Report.Node}}</h1>
+<table>
+<tr><th>Node</th><td>{{.Report.Node}}</td></tr>
+<tr><th>Phase</th><td>{{.Report.Phase}}</td></tr>
+{{- with .Report.SignOff.Ticket}}
+<tr><th>Ticket</th><td>{{.}}</td></tr>
+{{- end}}
+{{- with .Report.SignOff.Reason}}
+<tr><th>Reason</th><td>{{.}}</td></tr>
+{{- end}}
+<tr><th>Started</th><td>{{time .Report.Started}}</td></tr>
+<tr><th>Finished</th><td>{{time .Report.Finished}}</td></tr>
+<tr><th>Duration</th><td>{{duration .Report.Duration}}</td></tr>
+<tr><th>Result</th><td class="{{.Result}}">{{.Result}}</td></tr>
+</table>
+{{- if .Report.Error}}
+<h2>Error</h2>
+<pre>{{.Report.Error}}</pre>
+{{- end}}
+{{- if .Report.Health}}
+<h2>Ceph Health</h2>
+<table>
+<tr><th>Time</th><th>Status</th></tr>
+{{- range .Report.Health}}
+<tr><td>{{time .Time}}</td><td>{{.Status}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>Timeline</h2>
+<table>
+<tr><th>#</th><th>Stage</th><th>Description</th><th>Started</th><th>Duration</th></tr>
+{{- range $i, $s := .Report.Stages}}
+<tr><td>{{inc $i}}</td><td>{{$s.Stage}}</td><td>{{$s.Description}}</td><td>{{time $s.Started}}</td><td>{{duration $s.Duration}}</td></tr>
+{{- end}}
+</table>
+{{- if .Report.Deployments}}
+<h2>Deployments</h2>
+<ul>
+{{- range .Report.Deployments}}
+<li><code>{{.}}</code></li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+// RenderFlowHTML renders the report as a standalone HTML page
+func RenderFlowHTML(w io.Writer, report *FlowReport) error {
+	return flowReportHTML.Execute(w, struct {
+		Report *FlowReport
+		Result string
+	}{Report: report, Result: flowResult(report)})
+}
+
+// flowResult returns "succeeded" or "failed"
+func flowResult(report *FlowReport) string {
+	if report.Succeeded() {
+		return "succeeded"
+	}
+	return "failed"
+}
+
+// formatReportTime formats a timestamp for reports
+func formatReportTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatReportDuration formats a duration for reports
+func formatReportDuration(d time.Duration) string {
+	return d.Round(10 * time.Millisecond).String()
+}
+
+// markdownCell escapes characters that would break a Markdown table cell
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
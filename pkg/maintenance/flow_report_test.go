@@ -0,0 +1,113 @@
+package maintenance
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a clock advancing by step on every call
+func fakeClock(start time.Time, step time.Duration) func() time.Time {
+	current := start
+	return func() time.Time {
+		t := current
+		current = current.Add(step)
+		return t
+	}
+}
+
+func recordedDownFlow(err error) *FlowReport {
+	r := newFlowRecorder("worker-1", "down", fakeClock(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), time.Second))
+	r.SetSignOff(SignOff{Reason: "replace disk", Ticket: "CHG-1"})
+	r.RecordHealth("HEALTH_OK")
+	r.OnDownProgress(DownPhaseProgress{Stage: "cordon", Description: "Cordoning node worker-1"})
+	r.OnDownProgress(DownPhaseProgress{Stage: "scale-down", Description: "Scaling down rook-ceph/rook-ceph-osd-0 to 0", Deployment: "rook-ceph/rook-ceph-osd-0"})
+	r.OnDownProgress(DownPhaseProgress{Stage: "scale-down", Description: "Waiting | retry", Deployment: "rook-ceph/rook-ceph-osd-0"})
+	r.RecordHealth("HEALTH_OK")
+	r.RecordHealth("HEALTH_WARN")
+	return r.Finish(err)
+}
+
+func TestFlowRecorder(t *testing.T) {
+	report := recordedDownFlow(nil)
+
+	if len(report.Stages) != 3 {
+		t.Fatalf("got %d stages, want 3", len(report.Stages))
+	}
+	for i, s := range report.Stages {
+		if s.Duration <= 0 {
+			t.Errorf("stage %d (%s) has no duration", i, s.Stage)
+		}
+	}
+	if len(report.Deployments) != 1 || report.Deployments[0] != "rook-ceph/rook-ceph-osd-0" {
+		t.Errorf("Deployments = %v, want deduplicated osd-0", report.Deployments)
+	}
+	if len(report.Health) != 2 {
+		t.Errorf("Health = %v, want only transitions OK -> WARN", report.Health)
+	}
+	if !report.Succeeded() || report.Duration() <= 0 {
+		t.Errorf("report = %+v, want succeeded with positive duration", report)
+	}
+
+	failed := recordedDownFlow(errors.New("timed out"))
+	if failed.Succeeded() || failed.Error != "timed out" {
+		t.Errorf("failed report = %+v, want error recorded", failed)
+	}
+}
+
+func TestRenderFlowMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderFlowMarkdown(&buf, recordedDownFlow(errors.New("timed out"))); err != nil {
+		t.Fatalf("RenderFlowMarkdown() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"# crook down worker-1", "| Ticket | CHG-1 |", "| Reason | replace disk |", "| Result | failed |", "## Error", "timed out", "HEALTH_WARN", "## Timeline", `Waiting \| retry`, "- `rook-ceph/rook-ceph-osd-0`"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderFlowHTML(t *testing.T) {
+	report := recordedDownFlow(errors.New("<script>alert(1)</script>"))
+
+	var buf bytes.Buffer
+	if err := RenderFlowHTML(&buf, report); err != nil {
+		t.Fatalf("RenderFlowHTML() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"<title>crook down worker-1</title>", "<th>Ticket</th><td>CHG-1</td>", `class="failed"`, "Cordoning node worker-1", "&lt;script&gt;"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("html missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<script>") {
+		t.Error("html must escape report content")
+	}
+}
+
+func TestWriteFlowReports(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+
+	paths, err := WriteFlowReports(dir, recordedDownFlow(nil))
+	if err != nil {
+		t.Fatalf("WriteFlowReports() error = %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("got %d paths, want 2", len(paths))
+	}
+	for _, path := range paths {
+		if !strings.HasPrefix(filepath.Base(path), "crook-down-worker-1-20260301T100000Z") {
+			t.Errorf("unexpected report name %s", path)
+		}
+		if info, statErr := os.Stat(path); statErr != nil || info.Size() == 0 {
+			t.Errorf("report %s not written: %v", path, statErr)
+		}
+	}
+}
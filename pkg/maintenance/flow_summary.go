@@ -0,0 +1,88 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// FlowSummary is the machine-readable result of a headless down or up run,
+// written with --summary-path for pipelines (Terraform, CI) to consume
+type FlowSummary struct {
+	Node  string `json:"node"`
+	Phase string `json:"phase"`
+
+	// Outcome is OutcomeSucceeded, OutcomeFailed or OutcomeUnchanged
+	Outcome string `json:"outcome"`
+
+	// ExitCode is the process exit code of the run
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+
+	Started         time.Time `json:"started"`
+	Finished        time.Time `json:"finished"`
+	DurationSeconds float64   `json:"duration_seconds"`
+
+	Stages      []FlowSummaryStage `json:"stages"`
+	Deployments []string           `json:"deployments"`
+	SignOff     *SignOff           `json:"sign_off,omitempty"`
+
+	// FinalHealth is the last Ceph health status observed, if any
+	FinalHealth string `json:"final_health,omitempty"`
+}
+
+// FlowSummaryStage is the duration of one stage of the run
+type FlowSummaryStage struct {
+	Stage           string  `json:"stage"`
+	Description     string  `json:"description"`
+	Deployment      string  `json:"deployment,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// NewFlowSummary summarizes a finished run that exited with exitCode
+func NewFlowSummary(report *FlowReport, exitCode int) FlowSummary {
+	summary := FlowSummary{
+		Node:            report.Node,
+		Phase:           report.Phase,
+		Outcome:         OutcomeSucceeded,
+		ExitCode:        exitCode,
+		Error:           report.Error,
+		Started:         report.Started,
+		Finished:        report.Finished,
+		DurationSeconds: report.Duration().Seconds(),
+		Stages:          make([]FlowSummaryStage, 0, len(report.Stages)),
+		Deployments:     append([]string{}, report.Deployments...),
+	}
+	if !report.Succeeded() {
+		summary.Outcome = OutcomeFailed
+	}
+	if !report.SignOff.IsZero() {
+		signOff := report.SignOff
+		summary.SignOff = &signOff
+	}
+	for _, stage := range report.Stages {
+		summary.Stages = append(summary.Stages, FlowSummaryStage{
+			Stage:           stage.Stage,
+			Description:     stage.Description,
+			Deployment:      stage.Deployment,
+			DurationSeconds: stage.Duration.Seconds(),
+		})
+	}
+	if n := len(report.Health); n > 0 {
+		summary.FinalHealth = report.Health[n-1].Status
+	}
+	return summary
+}
+
+// WriteFlowSummary writes the summary as JSON to path
+func WriteFlowSummary(path string, summary FlowSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write summary %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewFlowSummary(t *testing.T) {
+	summary := NewFlowSummary(recordedDownFlow(nil), 0)
+
+	if summary.Outcome != OutcomeSucceeded || summary.ExitCode != 0 || summary.Error != "" {
+		t.Errorf("summary = %+v, want succeeded", summary)
+	}
+	if len(summary.Stages) != 3 || summary.Stages[0].Stage != "cordon" || summary.Stages[0].DurationSeconds <= 0 {
+		t.Errorf("Stages = %+v, want 3 timed stages starting with cordon", summary.Stages)
+	}
+	if summary.DurationSeconds <= 0 {
+		t.Errorf("DurationSeconds = %v, want positive", summary.DurationSeconds)
+	}
+	if len(summary.Deployments) != 1 || summary.FinalHealth != "HEALTH_WARN" {
+		t.Errorf("Deployments = %v, FinalHealth = %q", summary.Deployments, summary.FinalHealth)
+	}
+	if summary.SignOff == nil || summary.SignOff.Ticket != "CHG-1" {
+		t.Errorf("SignOff = %+v, want ticket CHG-1", summary.SignOff)
+	}
+
+	failed := NewFlowSummary(recordedDownFlow(errors.New("timed out")), 5)
+	if failed.Outcome != OutcomeFailed || failed.ExitCode != 5 || failed.Error != "timed out" {
+		t.Errorf("failed summary = %+v", failed)
+	}
+}
+
+func TestWriteFlowSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.json")
+	if err := WriteFlowSummary(path, NewFlowSummary(recordedDownFlow(nil), 0)); err != nil {
+		t.Fatalf("WriteFlowSummary() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read summary: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	for _, key := range []string{"node", "phase", "outcome", "exit_code", "duration_seconds", "stages", "deployments", "final_health"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("summary is missing %q:\n%s", key, data)
+		}
+	}
+}
+
+func TestWaitForEndState_NotReached(t *testing.T) {
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := WaitForEndState(ctx, client, config.DefaultConfig(), "worker-1", ScalePhaseDown, nil, false, time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForEndState() error = %v, want deadline exceeded", err)
+	}
+	if !strings.Contains(err.Error(), "did not reach the down state") {
+		t.Errorf("error = %q, want the unreached state", err)
+	}
+}
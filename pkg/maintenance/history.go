@@ -0,0 +1,275 @@
+package maintenance
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"slices"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// History storage: one ConfigMap per cluster archives the reports of
+// completed maintenances, since each node's report ConfigMap only holds the
+// latest one
+const (
+	historyConfigMapName = "crook-maintenance-history"
+	historyDataKey       = "history.json"
+
+	// maxHistoryEntries caps the archive; the oldest entries are dropped
+	maxHistoryEntries = 200
+)
+
+// Maintenance outcomes
+const (
+	OutcomeSucceeded  = "succeeded"
+	OutcomeFailed     = "failed"
+	OutcomeInProgress = "in progress"
+
+	// OutcomeUnchanged is a run that found the node already in the requested state
+	OutcomeUnchanged = "unchanged"
+)
+
+// HistoryEntry summarizes one maintenance of a node, from 'crook down' to
+// 'crook up'
+type HistoryEntry struct {
+	Node     string     `json:"node"`
+	Started  time.Time  `json:"started"`
+	Finished *time.Time `json:"finished,omitempty"`
+
+	DownOperator string   `json:"down_operator,omitempty"`
+	UpOperator   string   `json:"up_operator,omitempty"`
+	DownSignOff  *SignOff `json:"down_sign_off,omitempty"`
+	UpSignOff    *SignOff `json:"up_sign_off,omitempty"`
+
+	// Outcome is OutcomeSucceeded, OutcomeFailed or OutcomeInProgress
+	Outcome string `json:"outcome"`
+
+	// Error is the latest phase failure of a maintenance still open
+	Error string `json:"error,omitempty"`
+
+	// Changes counts the differences between the cluster before and after
+	Changes int `json:"changes"`
+}
+
+// NewHistoryEntry summarizes a maintenance report. The report must have a
+// pre-maintenance snapshot.
+func NewHistoryEntry(report *MaintenanceReport) HistoryEntry {
+	entry := HistoryEntry{
+		Node:         report.Node,
+		Started:      report.Before.Time,
+		DownOperator: report.DownOperator,
+		UpOperator:   report.UpOperator,
+		DownSignOff:  report.DownSignOff,
+		UpSignOff:    report.UpSignOff,
+		Error:        report.Error,
+		Changes:      len(report.Changes),
+	}
+	switch {
+	case report.After != nil:
+		finished := report.After.Time
+		entry.Finished = &finished
+		entry.Outcome = OutcomeSucceeded
+	case report.Error != "":
+		entry.Outcome = OutcomeFailed
+	default:
+		entry.Outcome = OutcomeInProgress
+	}
+	return entry
+}
+
+// Duration returns how long the node was in maintenance; for an open
+// maintenance, until now
+func (e HistoryEntry) Duration() time.Duration {
+	if e.Finished != nil {
+		return e.Finished.Sub(e.Started)
+	}
+	return time.Since(e.Started)
+}
+
+// Operators returns who ran the phases, e.g. "alice" or "alice, bob"
+func (e HistoryEntry) Operators() string {
+	switch {
+	case e.UpOperator == "" || e.UpOperator == e.DownOperator:
+		return e.DownOperator
+	case e.DownOperator == "":
+		return e.UpOperator
+	default:
+		return e.DownOperator + ", " + e.UpOperator
+	}
+}
+
+// Ticket returns the ticket of the down phase, else of the up phase
+func (e HistoryEntry) Ticket() string {
+	for _, signOff := range []*SignOff{e.DownSignOff, e.UpSignOff} {
+		if signOff != nil && signOff.Ticket != "" {
+			return signOff.Ticket
+		}
+	}
+	return ""
+}
+
+// CurrentOperator returns the local user name recorded as the operator of a
+// phase, or "" when it cannot be determined
+func CurrentOperator() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// LoadHistory returns the archived maintenances, oldest first
+func LoadHistory(ctx context.Context, client *k8s.Client, cfg config.Config) ([]HistoryEntry, error) {
+	cm, err := client.GetConfigMap(ctx, cfg.Namespace, historyConfigMapName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := cm.Data[historyDataKey]
+	if !ok {
+		return nil, nil
+	}
+	var entries []HistoryEntry
+	if err := json.Unmarshal([]byte(data), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse maintenance history: %w", err)
+	}
+	return entries, nil
+}
+
+// SaveHistory stores the archived maintenances, keeping the newest
+// maxHistoryEntries
+func SaveHistory(ctx context.Context, client *k8s.Client, cfg config.Config, entries []HistoryEntry) error {
+	if len(entries) > maxHistoryEntries {
+		entries = entries[len(entries)-maxHistoryEntries:]
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode maintenance history: %w", err)
+	}
+
+	return client.ApplyConfigMap(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      historyConfigMapName,
+			Namespace: cfg.Namespace,
+			Labels:    map[string]string{k8s.JobLabelName: "crook"},
+		},
+		Data: map[string]string{historyDataKey: string(data)},
+	})
+}
+
+// archiveReport appends a completed maintenance to the history. Best-effort.
+func archiveReport(ctx context.Context, client *k8s.Client, cfg config.Config, report *MaintenanceReport) {
+	entries, err := LoadHistory(ctx, client, cfg)
+	if err != nil {
+		logger.Warn("failed to load maintenance history", "error", err)
+		return
+	}
+	entries = append(entries, NewHistoryEntry(report))
+	if err := SaveHistory(ctx, client, cfg, entries); err != nil {
+		logger.Warn("failed to save maintenance history", "error", err)
+	}
+}
+
+// ListHistory returns the archived maintenances and those still open, newest
+// first. A non-empty nodeName limits the list to that node.
+func ListHistory(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) ([]HistoryEntry, error) {
+	archived, err := LoadHistory(ctx, client, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := openReports(ctx, client, cfg, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []HistoryEntry
+	for _, entry := range archived {
+		if nodeName == "" || entry.Node == nodeName {
+			entries = append(entries, entry)
+		}
+	}
+	for _, report := range open {
+		entries = append(entries, NewHistoryEntry(report))
+	}
+
+	slices.SortStableFunc(entries, func(a, b HistoryEntry) int {
+		return cmp.Compare(b.Started.UnixNano(), a.Started.UnixNano())
+	})
+	return entries, nil
+}
+
+// openReports returns the maintenance reports without an after snapshot, of
+// nodeName or, when empty, of every node
+func openReports(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) ([]*MaintenanceReport, error) {
+	var reports []*MaintenanceReport
+	if nodeName != "" {
+		report, err := LoadReport(ctx, client, cfg, nodeName)
+		if errors.Is(err, ErrNoReport) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	} else {
+		configMaps, err := client.ListConfigMaps(ctx, cfg.Namespace, reportLabelNode)
+		if err != nil {
+			return nil, err
+		}
+		for i := range configMaps {
+			var report MaintenanceReport
+			if err := json.Unmarshal([]byte(configMaps[i].Data[reportDataKey]), &report); err != nil {
+				logger.Debug("skipping unreadable maintenance report", "configmap", configMaps[i].Name, "error", err)
+				continue
+			}
+			reports = append(reports, &report)
+		}
+	}
+
+	return slices.DeleteFunc(reports, func(r *MaintenanceReport) bool {
+		return r.Before == nil || r.After != nil
+	}), nil
+}
+
+// recordReportResult stores a phase failure in the node's open maintenance
+// report, or clears it when the phase succeeded. A closed report is left
+// alone, so a down phase failing pre-flight does not touch the last
+// maintenance. Best-effort.
+func recordReportResult(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName, phase string, phaseErr error) {
+	report, err := LoadReport(ctx, client, cfg, nodeName)
+	if err != nil {
+		if !errors.Is(err, ErrNoReport) {
+			logger.Warn("failed to load maintenance report", "node", nodeName, "error", err)
+		}
+		return
+	}
+	if report.Before == nil || report.After != nil {
+		return
+	}
+
+	message := ""
+	if phaseErr != nil {
+		message = phase + ": " + phaseErr.Error()
+	}
+	if report.Error == message {
+		return
+	}
+	report.Error = message
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		logger.Warn("failed to save maintenance report", "node", nodeName, "error", err)
+	}
+}
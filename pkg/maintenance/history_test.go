@@ -0,0 +1,161 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestListHistory(t *testing.T) {
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	cfg := config.DefaultConfig()
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// Two completed maintenances of worker-1 and one of worker-2 are archived
+	for i, node := range []string{"worker-1", "worker-2", "worker-1"} {
+		started := start.Add(time.Duration(i) * 24 * time.Hour)
+		archiveReport(ctx, client, cfg, &MaintenanceReport{
+			Node:         node,
+			Before:       &ClusterSnapshot{Time: started},
+			After:        &ClusterSnapshot{Time: started.Add(time.Hour)},
+			DownOperator: "alice",
+		})
+	}
+
+	// worker-3 is still down, worker-2 is down again after a failed up
+	open := []*MaintenanceReport{
+		{Node: "worker-3", Before: &ClusterSnapshot{Time: start.Add(96 * time.Hour)}},
+		{Node: "worker-2", Before: &ClusterSnapshot{Time: start.Add(72 * time.Hour)}, Error: "up: timeout"},
+	}
+	for _, report := range open {
+		if err := SaveReport(ctx, client, cfg, report); err != nil {
+			t.Fatalf("SaveReport() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		node     string
+		wantNode []string
+		wantOut  []string
+	}{
+		{
+			name:     "cluster",
+			wantNode: []string{"worker-3", "worker-2", "worker-1", "worker-2", "worker-1"},
+			wantOut:  []string{OutcomeInProgress, OutcomeFailed, OutcomeSucceeded, OutcomeSucceeded, OutcomeSucceeded},
+		},
+		{
+			name:     "one node",
+			node:     "worker-2",
+			wantNode: []string{"worker-2", "worker-2"},
+			wantOut:  []string{OutcomeFailed, OutcomeSucceeded},
+		},
+		{
+			name: "unknown node",
+			node: "worker-9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ListHistory(ctx, client, cfg, tt.node)
+			if err != nil {
+				t.Fatalf("ListHistory() error = %v", err)
+			}
+			if len(entries) != len(tt.wantNode) {
+				t.Fatalf("ListHistory() returned %d entries, want %d: %+v", len(entries), len(tt.wantNode), entries)
+			}
+			for i, entry := range entries {
+				if entry.Node != tt.wantNode[i] || entry.Outcome != tt.wantOut[i] {
+					t.Errorf("entry %d = %s %s, want %s %s", i, entry.Node, entry.Outcome, tt.wantNode[i], tt.wantOut[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSaveHistory_KeepsNewest(t *testing.T) {
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	cfg := config.DefaultConfig()
+
+	entries := make([]HistoryEntry, maxHistoryEntries+5)
+	for i := range entries {
+		entries[i] = HistoryEntry{Node: "worker-1", Changes: i}
+	}
+	if err := SaveHistory(ctx, client, cfg, entries); err != nil {
+		t.Fatalf("SaveHistory() error = %v", err)
+	}
+
+	loaded, err := LoadHistory(ctx, client, cfg)
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if len(loaded) != maxHistoryEntries {
+		t.Fatalf("LoadHistory() returned %d entries, want %d", len(loaded), maxHistoryEntries)
+	}
+	if loaded[0].Changes != 5 {
+		t.Errorf("oldest kept entry = %d, want 5", loaded[0].Changes)
+	}
+}
+
+func TestRecordReportResult(t *testing.T) {
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	cfg := config.DefaultConfig()
+
+	// Without a report nothing is recorded
+	recordReportResult(ctx, client, cfg, "worker-1", ScalePhaseDown, errors.New("boom"))
+	if _, err := LoadReport(ctx, client, cfg, "worker-1"); !errors.Is(err, ErrNoReport) {
+		t.Fatalf("recordReportResult() should not create a report, LoadReport() error = %v", err)
+	}
+
+	report := &MaintenanceReport{Node: "worker-1", Before: &ClusterSnapshot{Time: time.Now()}}
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+
+	recordReportResult(ctx, client, cfg, "worker-1", ScalePhaseUp, errors.New("timeout"))
+	loaded, err := LoadReport(ctx, client, cfg, "worker-1")
+	if err != nil {
+		t.Fatalf("LoadReport() error = %v", err)
+	}
+	if loaded.Error != "up: timeout" {
+		t.Errorf("Error = %q, want %q", loaded.Error, "up: timeout")
+	}
+
+	recordReportResult(ctx, client, cfg, "worker-1", ScalePhaseUp, nil)
+	loaded, err = LoadReport(ctx, client, cfg, "worker-1")
+	if err != nil {
+		t.Fatalf("LoadReport() error = %v", err)
+	}
+	if loaded.Error != "" {
+		t.Errorf("Error = %q, want it cleared", loaded.Error)
+	}
+}
+
+func TestHistoryEntry_Operators(t *testing.T) {
+	tests := []struct {
+		down, up string
+		want     string
+	}{
+		{down: "alice", want: "alice"},
+		{down: "alice", up: "alice", want: "alice"},
+		{down: "alice", up: "bob", want: "alice, bob"},
+		{up: "bob", want: "bob"},
+		{want: ""},
+	}
+
+	for _, tt := range tests {
+		entry := HistoryEntry{DownOperator: tt.down, UpOperator: tt.up}
+		if got := entry.Operators(); got != tt.want {
+			t.Errorf("Operators() with down %q, up %q = %q, want %q", tt.down, tt.up, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// KuredReason is the sign-off reason 'crook hook kured-pre' records on the
+// node; 'crook hook kured-post' only brings back a node that carries it, so a
+// maintenance started by hand is never ended by an automated reboot
+const KuredReason = "kured reboot"
+
+// KuredStatus is what kured, the reboot daemon for OS updates, reports about
+// a node
+type KuredStatus struct {
+	// DaemonSet is the kured DaemonSet as "namespace/name"
+	DaemonSet string
+
+	// LockHolders are the nodes kured is rebooting
+	LockHolders []string
+
+	// RebootRequired is set when kured found the node's reboot sentinel
+	// (kured --annotate-nodes)
+	RebootRequired bool
+
+	// RebootInProgress is set while kured drains and reboots the node
+	RebootInProgress bool
+}
+
+// Warnings describe what kured is doing that affects a down phase of the
+// node. fromHook is set in 'crook hook kured-pre', which kured runs because it
+// is about to reboot the node.
+func (s KuredStatus) Warnings(nodeName string, fromHook bool) []string {
+	var warnings []string
+	if others := slices.DeleteFunc(slices.Clone(s.LockHolders), func(n string) bool { return n == nodeName }); len(others) > 0 {
+		warnings = append(warnings, fmt.Sprintf("kured is rebooting %s (reboot lock of %s): taking %s down too leaves more OSDs out at once",
+			strings.Join(others, ", "), s.DaemonSet, nodeName))
+	}
+	switch {
+	case fromHook:
+	case s.RebootInProgress || slices.Contains(s.LockHolders, nodeName):
+		warnings = append(warnings, fmt.Sprintf("kured is rebooting %s; have it run 'crook hook kured-pre' so the down phase comes first", nodeName))
+	case s.RebootRequired:
+		warnings = append(warnings, fmt.Sprintf("kured reports that %s needs a reboot; it may reboot the node while it is in maintenance", nodeName))
+	}
+	return warnings
+}
+
+// DetectKured returns what kured reports about the node, nil when kured is
+// not installed as configured in hooks.kured or cannot be read
+func DetectKured(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) *KuredStatus {
+	kured := cfg.Hooks.Kured
+	holders, err := client.GetKuredLockHolders(ctx, kured.Namespace, kured.DaemonSet)
+	if err != nil {
+		logger.Debug("skipping kured detection", "error", err)
+		return nil
+	}
+
+	status := &KuredStatus{DaemonSet: kured.Namespace + "/" + kured.DaemonSet, LockHolders: holders}
+	if node, err := client.GetNode(ctx, nodeName); err == nil {
+		status.RebootInProgress = node.Annotations[k8s.KuredRebootInProgressAnnotation] != ""
+		status.RebootRequired = node.Annotations[k8s.KuredRebootNeededAnnotation] != ""
+	}
+	return status
+}
+
+// KuredSentinelPresent reports whether the reboot sentinel file exists. It
+// only tells about the host crook runs on, so only the hooks kured runs on
+// the node read it.
+func KuredSentinelPresent(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
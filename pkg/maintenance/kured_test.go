@@ -0,0 +1,99 @@
+package maintenance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDetectKured(t *testing.T) {
+	kured := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+		Name:        "kured",
+		Namespace:   "kube-system",
+		Annotations: map[string]string{k8s.KuredLockAnnotation: `{"nodeID":"worker-2"}`},
+	}}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "worker-1",
+		Annotations: map[string]string{k8s.KuredRebootNeededAnnotation: "2026-01-02T03:04:05Z"},
+	}}
+
+	tests := []struct {
+		name     string
+		objects  []runtime.Object
+		fromHook bool
+		want     []string
+	}{
+		{name: "not installed", objects: []runtime.Object{node}},
+		{
+			name:    "other node rebooting, this one needs a reboot",
+			objects: []runtime.Object{kured, node},
+			want: []string{
+				"kured is rebooting worker-2 (reboot lock of kube-system/kured)",
+				"kured reports that worker-1 needs a reboot",
+			},
+		},
+		{
+			name:     "from the kured hook",
+			objects:  []runtime.Object{kured, node},
+			fromHook: true,
+			want:     []string{"kured is rebooting worker-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &k8s.Client{Clientset: fake.NewClientset(tt.objects...)}
+			status := DetectKured(context.Background(), client, config.DefaultConfig(), "worker-1")
+			if status == nil {
+				if tt.want != nil {
+					t.Fatal("DetectKured() = nil, want a status")
+				}
+				return
+			}
+
+			warnings := status.Warnings("worker-1", tt.fromHook)
+			if len(warnings) != len(tt.want) {
+				t.Fatalf("Warnings() = %q, want %d warnings", warnings, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(warnings[i], want) {
+					t.Errorf("Warnings()[%d] = %q, want it to contain %q", i, warnings[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestKuredStatus_WarningsRebootingNode(t *testing.T) {
+	status := KuredStatus{DaemonSet: "kube-system/kured", LockHolders: []string{"worker-1"}, RebootRequired: true}
+	warnings := status.Warnings("worker-1", false)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "crook hook kured-pre") {
+		t.Errorf("Warnings() = %q, want the kured-pre hint only", warnings)
+	}
+}
+
+func TestKuredSentinelPresent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reboot-required")
+	if KuredSentinelPresent(path) {
+		t.Error("KuredSentinelPresent() = true before the sentinel exists")
+	}
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("write sentinel: %v", err)
+	}
+	if !KuredSentinelPresent(path) {
+		t.Error("KuredSentinelPresent() = false with the sentinel present")
+	}
+	if KuredSentinelPresent("") {
+		t.Error("KuredSentinelPresent(\"\") = true")
+	}
+}
@@ -0,0 +1,108 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// nodeGroupLabels map the labels cloud providers set on the nodes of their
+// managed node groups to the provider, in lookup order
+var nodeGroupLabels = []struct {
+	label    string
+	provider string
+}{
+	{"eks.amazonaws.com/nodegroup", "EKS"},
+	{"alpha.eksctl.io/nodegroup-name", "EKS"},
+	{"cloud.google.com/gke-nodepool", "GKE"},
+	{"kubernetes.azure.com/agentpool", "AKS"},
+	{"agentpool", "AKS"},
+}
+
+// NodeGroup is the managed cloud node group a node belongs to
+type NodeGroup struct {
+	// Provider is "EKS", "GKE" or "AKS"
+	Provider string
+
+	// Name is the node group, node pool or agent pool name
+	Name string
+
+	// ScaleDownDisabled is set when the maintenance keeps the cluster
+	// autoscaler from removing the node (cloud.disable-scale-down)
+	ScaleDownDisabled bool
+}
+
+// String returns a warning naming the node group and what it means for the
+// cordoned node
+func (g NodeGroup) String() string {
+	group := fmt.Sprintf("the node is in the %s managed node group %s", g.Provider, g.Name)
+	if g.ScaleDownDisabled {
+		return group + ": the cluster autoscaler may remove a cordoned node, so it is annotated " +
+			k8s.ScaleDownDisabledAnnotation + " until 'crook up'; provider upgrades or repairs may still replace it"
+	}
+	return group + ": the cluster autoscaler or the provider may remove or replace it while it is cordoned; " +
+		"set cloud.disable-scale-down to annotate it " + k8s.ScaleDownDisabledAnnotation
+}
+
+// DetectNodeGroup returns the managed node group of the node from its
+// labels, nil when it is in none or cannot be read. disableScaleDown is
+// cloud.disable-scale-down.
+func DetectNodeGroup(ctx context.Context, client *k8s.Client, nodeName string, disableScaleDown bool) *NodeGroup {
+	node, err := client.GetNode(ctx, nodeName)
+	if err != nil {
+		logger.Debug("skipping node group detection", "node", nodeName, "error", err)
+		return nil
+	}
+	for _, l := range nodeGroupLabels {
+		if name := node.Labels[l.label]; name != "" {
+			return &NodeGroup{Provider: l.provider, Name: name, ScaleDownDisabled: disableScaleDown}
+		}
+	}
+	return nil
+}
+
+// disableScaleDown keeps the cluster autoscaler from removing the cordoned
+// node until the up phase. An annotation someone else set is left to them.
+// Best-effort.
+func disableScaleDown(ctx context.Context, client *k8s.Client, nodeName string) {
+	node, err := client.GetNode(ctx, nodeName)
+	if err != nil {
+		logger.Warn("failed to disable autoscaler scale-down", "node", nodeName, "error", err)
+		return
+	}
+	if node.Annotations[k8s.ScaleDownDisabledAnnotation] == "true" {
+		return
+	}
+
+	enabled := "true"
+	err = client.SetNodeAnnotations(ctx, nodeName, map[string]*string{
+		k8s.ScaleDownDisabledAnnotation:        &enabled,
+		k8s.ScaleDownDisabledByCrookAnnotation: &enabled,
+	})
+	if err != nil {
+		logger.Warn("failed to disable autoscaler scale-down", "node", nodeName, "error", err)
+	}
+}
+
+// restoreScaleDown removes the scale-down annotation the down phase set once
+// the node is back in service. Best-effort.
+func restoreScaleDown(ctx context.Context, client *k8s.Client, nodeName string) {
+	node, err := client.GetNode(ctx, nodeName)
+	if err != nil {
+		logger.Warn("failed to restore autoscaler scale-down", "node", nodeName, "error", err)
+		return
+	}
+	if node.Annotations[k8s.ScaleDownDisabledByCrookAnnotation] == "" {
+		return
+	}
+
+	err = client.SetNodeAnnotations(ctx, nodeName, map[string]*string{
+		k8s.ScaleDownDisabledAnnotation:        nil,
+		k8s.ScaleDownDisabledByCrookAnnotation: nil,
+	})
+	if err != nil {
+		logger.Warn("failed to restore autoscaler scale-down", "node", nodeName, "error", err)
+	}
+}
@@ -0,0 +1,110 @@
+package maintenance
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDetectNodeGroup(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   *NodeGroup
+	}{
+		{name: "unmanaged", labels: map[string]string{"kubernetes.io/hostname": "worker-1"}},
+		{name: "eks", labels: map[string]string{"eks.amazonaws.com/nodegroup": "storage"}, want: &NodeGroup{Provider: "EKS", Name: "storage"}},
+		{name: "eksctl", labels: map[string]string{"alpha.eksctl.io/nodegroup-name": "ng-1"}, want: &NodeGroup{Provider: "EKS", Name: "ng-1"}},
+		{name: "gke", labels: map[string]string{"cloud.google.com/gke-nodepool": "pool-1"}, want: &NodeGroup{Provider: "GKE", Name: "pool-1"}},
+		{name: "aks", labels: map[string]string{"kubernetes.azure.com/agentpool": "nodepool1"}, want: &NodeGroup{Provider: "AKS", Name: "nodepool1"}},
+		{name: "aks legacy label", labels: map[string]string{"agentpool": "nodepool1"}, want: &NodeGroup{Provider: "AKS", Name: "nodepool1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &k8s.Client{Clientset: fake.NewClientset(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: tt.labels},
+			})}
+
+			got := DetectNodeGroup(context.Background(), client, "worker-1", false)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("DetectNodeGroup() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("missing node", func(t *testing.T) {
+		client := &k8s.Client{Clientset: fake.NewClientset()}
+		if got := DetectNodeGroup(context.Background(), client, "worker-1", false); got != nil {
+			t.Errorf("DetectNodeGroup() = %+v, want nil", got)
+		}
+	})
+}
+
+func TestNodeGroup_String(t *testing.T) {
+	group := NodeGroup{Provider: "EKS", Name: "storage"}
+	if got := group.String(); !strings.Contains(got, "EKS managed node group storage") || !strings.Contains(got, "cloud.disable-scale-down") {
+		t.Errorf("String() = %q, want the group and the setting", got)
+	}
+
+	group.ScaleDownDisabled = true
+	if got := group.String(); !strings.Contains(got, "annotated "+k8s.ScaleDownDisabledAnnotation) {
+		t.Errorf("String() = %q, want the annotation", got)
+	}
+}
+
+func TestDisableAndRestoreScaleDown(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantAfterUp map[string]string
+	}{
+		{
+			name:        "set by crook",
+			annotations: map[string]string{"other": "kept"},
+			wantAfterUp: map[string]string{"other": "kept"},
+		},
+		{
+			name:        "set before crook ran",
+			annotations: map[string]string{k8s.ScaleDownDisabledAnnotation: "true"},
+			wantAfterUp: map[string]string{k8s.ScaleDownDisabledAnnotation: "true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := &k8s.Client{Clientset: fake.NewClientset(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Annotations: tt.annotations},
+			})}
+
+			disableScaleDown(ctx, client, "worker-1")
+			node, err := client.GetNode(ctx, "worker-1")
+			if err != nil {
+				t.Fatalf("GetNode() error = %v", err)
+			}
+			if node.Annotations[k8s.ScaleDownDisabledAnnotation] != "true" {
+				t.Errorf("annotations after down = %v, want scale-down disabled", node.Annotations)
+			}
+
+			restoreScaleDown(ctx, client, "worker-1")
+			node, err = client.GetNode(ctx, "worker-1")
+			if err != nil {
+				t.Fatalf("GetNode() error = %v", err)
+			}
+			if len(node.Annotations) != len(tt.wantAfterUp) {
+				t.Errorf("annotations after up = %v, want %v", node.Annotations, tt.wantAfterUp)
+			}
+			for k, v := range tt.wantAfterUp {
+				if node.Annotations[k] != v {
+					t.Errorf("annotations after up = %v, want %v", node.Annotations, tt.wantAfterUp)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// NoOutScope is what a maintenance sets the noout flag on: the whole cluster,
+// or only the node's CRUSH host or OSDs (see config.NoOutScopes)
+type NoOutScope struct {
+	// Scope is the ceph.noout-scope value
+	Scope string `json:"scope"`
+
+	// Groups are the CRUSH host or the OSDs (osd.N) a node scope sets noout
+	// on; empty for the cluster scope and for a node without OSDs
+	Groups []string `json:"groups,omitempty"`
+}
+
+// Cluster reports whether the scope is the cluster-wide noout flag
+func (s NoOutScope) Cluster() bool {
+	return s.Scope == "" || s.Scope == config.NoOutScopeCluster
+}
+
+// String describes the scope, e.g. "cluster-wide" or "CRUSH host worker-1"
+func (s NoOutScope) String() string {
+	switch {
+	case s.Cluster():
+		return "cluster-wide"
+	case len(s.Groups) == 0:
+		return "none, the node has no OSDs"
+	case s.Scope == config.NoOutScopeHost:
+		return "CRUSH host " + strings.Join(s.Groups, ", ")
+	default:
+		return "OSDs " + strings.Join(s.Groups, ", ")
+	}
+}
+
+// IsSet reports whether the flags prevent the scope's OSDs from being marked
+// out. The cluster-wide flag covers every scope.
+func (s NoOutScope) IsSet(flags *k8s.CephFlags) bool {
+	if flags.NoOut {
+		return true
+	}
+	if s.Cluster() {
+		return false
+	}
+	for _, group := range s.Groups {
+		if !slices.Contains(flags.NoOutGroups, group) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsUnset reports whether the flags no longer carry the scope's noout. A node
+// scope leaves the cluster-wide flag, which another maintenance may own, alone.
+func (s NoOutScope) IsUnset(flags *k8s.CephFlags) bool {
+	if s.Cluster() {
+		return !flags.NoOut
+	}
+	for _, group := range s.Groups {
+		if slices.Contains(flags.NoOutGroups, group) {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveNoOutScope resolves ceph.noout-scope for the node: the host scope is
+// the node's CRUSH host bucket, the osd scope the OSDs below it. A node that
+// runs OSDs no CRUSH host could be resolved for falls back to the cluster-wide
+// flag, so the maintenance never runs without noout.
+func ResolveNoOutScope(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) (NoOutScope, error) {
+	scope := NoOutScope{Scope: cfg.Ceph.NoOutScope}
+	if scope.Cluster() {
+		return NoOutScope{Scope: config.NoOutScopeCluster}, nil
+	}
+
+	placement, err := client.GetOSDPlacement(ctx, cfg.Namespace, nodeName)
+	if err != nil {
+		return scope, fmt.Errorf("failed to resolve the %s noout scope of %s: %w", scope.Scope, nodeName, err)
+	}
+	tree, err := client.GetOSDTree(ctx, cfg.Namespace)
+	if err != nil {
+		return scope, fmt.Errorf("failed to resolve the %s noout scope of %s: %w", scope.Scope, nodeName, err)
+	}
+	scope.Groups = nooutGroups(tree, scope.Scope, placement)
+	if len(scope.Groups) == 0 && len(placement.OSDIDs) > 0 {
+		logger.Warn("no CRUSH host found for the node's OSDs, falling back to the cluster-wide noout flag",
+			"node", nodeName, "scope", scope.Scope, "osds", placement.OSDIDs)
+		return NoOutScope{Scope: config.NoOutScopeCluster}, nil
+	}
+	return scope, nil
+}
+
+// nooutGroups returns the node's CRUSH host buckets for the host scope, or the
+// OSDs below them for the osd scope. A host bucket is the node's if it is named
+// like one of its host names or holds one of its OSDs; none when neither matches.
+func nooutGroups(tree *k8s.CephOSDTree, scope string, placement *k8s.OSDPlacement) []string {
+	h := newCrushHierarchy(tree)
+	var hosts []k8s.CephOSDNode
+	addHost := func(host k8s.CephOSDNode) {
+		if !slices.ContainsFunc(hosts, func(n k8s.CephOSDNode) bool { return n.ID == host.ID }) {
+			hosts = append(hosts, host)
+		}
+	}
+	for _, node := range tree.Nodes {
+		if node.Type == "host" && slices.Contains(placement.HostNames, node.Name) {
+			addHost(node)
+		}
+	}
+	for _, id := range placement.OSDIDs {
+		if host, ok := h.bucketOfType(id, "host"); ok {
+			addHost(host)
+		}
+	}
+
+	var groups []string
+	for _, host := range hosts {
+		if scope == config.NoOutScopeHost {
+			groups = append(groups, host.Name)
+			continue
+		}
+		for _, osd := range h.descendants(host.ID, "osd") {
+			groups = append(groups, osd.Name)
+		}
+	}
+	return groups
+}
+
+// UpNoOutScope returns the scope the node's open maintenance set noout on, as
+// recorded in its report or, without one, in the node's
+// k8s.NoOutScopeAnnotation, so the up phase clears that flag even when
+// ceph.noout-scope changed since; else the configured scope
+func UpNoOutScope(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) (NoOutScope, error) {
+	report, err := LoadReport(ctx, client, cfg, nodeName)
+	switch {
+	case err == nil && report.After == nil && report.NoOutScope != nil:
+		return *report.NoOutScope, nil
+	case err != nil && !errors.Is(err, ErrNoReport):
+		logger.Debug("failed to load maintenance report", "node", nodeName, "error", err)
+	}
+
+	if node, nodeErr := client.GetNode(ctx, nodeName); nodeErr == nil {
+		scope, recorded, scopeErr := recordedNoOutScope(node.Annotations)
+		if scopeErr != nil {
+			return NoOutScope{}, fmt.Errorf("failed to read the noout scope of %s: %w", nodeName, scopeErr)
+		}
+		if recorded {
+			return scope, nil
+		}
+	}
+	return ResolveNoOutScope(ctx, client, cfg, nodeName)
+}
+
+// noOutScopeAnnotation returns the k8s.NoOutScopeAnnotation value recording
+// the scope; nil removes the record
+func noOutScopeAnnotation(scope *NoOutScope) (*string, error) {
+	if scope == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode noout scope: %w", err)
+	}
+	value := string(data)
+	return &value, nil
+}
+
+// recordedNoOutScope returns the scope recorded in k8s.NoOutScopeAnnotation;
+// false when there is no record
+func recordedNoOutScope(annotations map[string]string) (NoOutScope, bool, error) {
+	value, ok := annotations[k8s.NoOutScopeAnnotation]
+	if !ok {
+		return NoOutScope{}, false, nil
+	}
+	var scope NoOutScope
+	if err := json.Unmarshal([]byte(value), &scope); err != nil {
+		return NoOutScope{}, false, fmt.Errorf("invalid %s annotation %q: %w", k8s.NoOutScopeAnnotation, value, err)
+	}
+	return scope, true, nil
+}
+
+// setNoOut sets the noout flag on the scope
+func setNoOut(ctx context.Context, client *k8s.Client, cfg config.Config, scope NoOutScope) error {
+	switch {
+	case scope.Cluster():
+		return client.SetNoOut(ctx, cfg.Namespace)
+	case len(scope.Groups) == 0:
+		return nil
+	default:
+		return client.SetNoOutGroup(ctx, cfg.Namespace, scope.Groups)
+	}
+}
+
+// unsetNoOut unsets the noout flag on the scope
+func unsetNoOut(ctx context.Context, client *k8s.Client, cfg config.Config, scope NoOutScope) error {
+	switch {
+	case scope.Cluster():
+		return client.UnsetNoOut(ctx, cfg.Namespace)
+	case len(scope.Groups) == 0:
+		return nil
+	default:
+		return client.UnsetNoOutGroup(ctx, cfg.Namespace, scope.Groups)
+	}
+}
+
+// annotateNoOutScope records the scope noout was set on in the node's
+// k8s.NoOutScopeAnnotation, for an up phase without the report (best-effort)
+func annotateNoOutScope(ctx context.Context, client *k8s.Client, nodeName string, scope NoOutScope) {
+	value, err := noOutScopeAnnotation(&scope)
+	if err == nil {
+		err = client.SetNodeAnnotations(ctx, nodeName, map[string]*string{k8s.NoOutScopeAnnotation: value})
+	}
+	if err != nil {
+		logger.Warn("failed to record noout scope on node", "node", nodeName, "error", err)
+	}
+}
+
+// clearNoOutScope removes the node's k8s.NoOutScopeAnnotation once the up
+// phase unset the flag (best-effort)
+func clearNoOutScope(ctx context.Context, client *k8s.Client, nodeName string) {
+	if err := client.SetNodeAnnotations(ctx, nodeName, map[string]*string{k8s.NoOutScopeAnnotation: nil}); err != nil {
+		logger.Warn("failed to remove noout scope from node", "node", nodeName, "error", err)
+	}
+}
+
+// recordReportNoOutScope records the scope noout was set on in the node's
+// open maintenance report, for the up phase (best-effort)
+func recordReportNoOutScope(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, scope NoOutScope) {
+	report, err := LoadReport(ctx, client, cfg, nodeName)
+	if err != nil {
+		if !errors.Is(err, ErrNoReport) {
+			logger.Warn("failed to load maintenance report", "node", nodeName, "error", err)
+		}
+		return
+	}
+	report.NoOutScope = &scope
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		logger.Warn("failed to save maintenance report", "node", nodeName, "error", err)
+	}
+}
@@ -0,0 +1,155 @@
+package maintenance
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNooutGroups(t *testing.T) {
+	tree := &k8s.CephOSDTree{Nodes: []k8s.CephOSDNode{
+		{ID: -1, Name: "default", Type: "root", Children: []int{-2, -3}},
+		{ID: -2, Name: "worker-1", Type: "host", Children: []int{0, 3}},
+		{ID: -3, Name: "worker-2-example-com", Type: "host", Children: []int{1}},
+		{ID: 0, Name: "osd.0", Type: "osd"},
+		{ID: 1, Name: "osd.1", Type: "osd"},
+		{ID: 3, Name: "osd.3", Type: "osd"},
+	}}
+
+	tests := []struct {
+		name      string
+		scope     string
+		placement k8s.OSDPlacement
+		want      []string
+	}{
+		{name: "host", scope: config.NoOutScopeHost, placement: k8s.OSDPlacement{HostNames: []string{"worker-1"}}, want: []string{"worker-1"}},
+		{name: "osds", scope: config.NoOutScopeOSD, placement: k8s.OSDPlacement{HostNames: []string{"worker-1"}}, want: []string{"osd.0", "osd.3"}},
+		{
+			name:      "hostname label",
+			scope:     config.NoOutScopeHost,
+			placement: k8s.OSDPlacement{HostNames: []string{"worker-1.example.com", "worker-1"}},
+			want:      []string{"worker-1"},
+		},
+		{
+			name:      "host of the node's OSD pods",
+			scope:     config.NoOutScopeHost,
+			placement: k8s.OSDPlacement{HostNames: []string{"worker-2.example.com"}, OSDIDs: []int{1}},
+			want:      []string{"worker-2-example-com"},
+		},
+		{
+			name:      "OSDs of the node's OSD pods",
+			scope:     config.NoOutScopeOSD,
+			placement: k8s.OSDPlacement{HostNames: []string{"worker-2.example.com"}, OSDIDs: []int{1}},
+			want:      []string{"osd.1"},
+		},
+		{name: "node without OSDs", scope: config.NoOutScopeHost, placement: k8s.OSDPlacement{HostNames: []string{"worker-3"}}, want: nil},
+		{name: "OSDs missing from the tree", scope: config.NoOutScopeHost, placement: k8s.OSDPlacement{HostNames: []string{"worker-4"}, OSDIDs: []int{7}}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nooutGroups(tree, tt.scope, &tt.placement); !slices.Equal(got, tt.want) {
+				t.Errorf("nooutGroups() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoOutScope(t *testing.T) {
+	cluster := NoOutScope{Scope: config.NoOutScopeCluster}
+	host := NoOutScope{Scope: config.NoOutScopeHost, Groups: []string{"worker-1"}}
+	osds := NoOutScope{Scope: config.NoOutScopeOSD, Groups: []string{"osd.0", "osd.3"}}
+	none := NoOutScope{Scope: config.NoOutScopeHost}
+
+	tests := []struct {
+		name      string
+		scope     NoOutScope
+		flags     k8s.CephFlags
+		wantSet   bool
+		wantUnset bool
+	}{
+		{name: "cluster set", scope: cluster, flags: k8s.CephFlags{NoOut: true}, wantSet: true},
+		{name: "cluster unset", scope: cluster, flags: k8s.CephFlags{NoOutGroups: []string{"worker-1"}}, wantUnset: true},
+		{name: "host set", scope: host, flags: k8s.CephFlags{NoOutGroups: []string{"worker-1"}}, wantSet: true},
+		{name: "host covered by the cluster flag", scope: host, flags: k8s.CephFlags{NoOut: true}, wantSet: true, wantUnset: true},
+		{name: "host unset", scope: host, flags: k8s.CephFlags{NoOutGroups: []string{"worker-2"}}, wantUnset: true},
+		{name: "osds partly set", scope: osds, flags: k8s.CephFlags{NoOutGroups: []string{"osd.0"}}},
+		{name: "osds set", scope: osds, flags: k8s.CephFlags{NoOutGroups: []string{"osd.0", "osd.3"}}, wantSet: true},
+		{name: "node without OSDs", scope: none, wantSet: true, wantUnset: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scope.IsSet(&tt.flags); got != tt.wantSet {
+				t.Errorf("IsSet() = %v, want %v", got, tt.wantSet)
+			}
+			if got := tt.scope.IsUnset(&tt.flags); got != tt.wantUnset {
+				t.Errorf("IsUnset() = %v, want %v", got, tt.wantUnset)
+			}
+		})
+	}
+
+	for scope, want := range map[*NoOutScope]string{
+		&cluster: "cluster-wide",
+		&host:    "CRUSH host worker-1",
+		&osds:    "OSDs osd.0, osd.3",
+		&none:    "none, the node has no OSDs",
+	} {
+		if got := scope.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestRecordedNoOutScope(t *testing.T) {
+	scope := NoOutScope{Scope: config.NoOutScopeOSD, Groups: []string{"osd.0", "osd.3"}}
+	value, err := noOutScopeAnnotation(&scope)
+	if err != nil {
+		t.Fatalf("noOutScopeAnnotation() error = %v", err)
+	}
+
+	got, ok, err := recordedNoOutScope(map[string]string{k8s.NoOutScopeAnnotation: *value})
+	if err != nil || !ok || !slices.Equal(got.Groups, scope.Groups) || got.Scope != scope.Scope {
+		t.Errorf("recordedNoOutScope() = %+v, %v, %v, want %+v", got, ok, err, scope)
+	}
+	if _, ok, err := recordedNoOutScope(nil); ok || err != nil {
+		t.Errorf("recordedNoOutScope(nil) = %v, %v, want no record", ok, err)
+	}
+	if _, _, err := recordedNoOutScope(map[string]string{k8s.NoOutScopeAnnotation: "host"}); err == nil {
+		t.Error("recordedNoOutScope() of an invalid record should fail")
+	}
+	if value, err := noOutScopeAnnotation(nil); value != nil || err != nil {
+		t.Errorf("noOutScopeAnnotation(nil) = %v, %v, want a removal", value, err)
+	}
+}
+
+func TestUpNoOutScope_NodeRecordWithoutReport(t *testing.T) {
+	// The OSDs are scaled down and the CRUSH host is not named like the node,
+	// so re-resolving the scope could not find it
+	client := &k8s.Client{Clientset: fake.NewClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1.example.com"}})}
+	cfg := config.DefaultConfig()
+	cfg.Ceph.NoOutScope = config.NoOutScopeOSD
+	ctx := context.Background()
+
+	annotateNoOutScope(ctx, client, "worker-1.example.com", NoOutScope{Scope: config.NoOutScopeHost, Groups: []string{"worker-1-example-com"}})
+	scope, err := UpNoOutScope(ctx, client, cfg, "worker-1.example.com")
+	if err != nil {
+		t.Fatalf("UpNoOutScope() error = %v", err)
+	}
+	if scope.Scope != config.NoOutScopeHost || !slices.Equal(scope.Groups, []string{"worker-1-example-com"}) {
+		t.Errorf("UpNoOutScope() = %+v, want the scope recorded on the node", scope)
+	}
+
+	clearNoOutScope(ctx, client, "worker-1.example.com")
+	got, err := client.GetNode(ctx, "worker-1.example.com")
+	if err != nil {
+		t.Fatalf("GetNode() error = %v", err)
+	}
+	if value, ok := got.Annotations[k8s.NoOutScopeAnnotation]; ok {
+		t.Errorf("node still records noout scope %s", value)
+	}
+}
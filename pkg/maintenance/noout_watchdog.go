@@ -0,0 +1,94 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// armNoOutWatchdog creates the node's noout watchdog Job when
+// ceph.noout-watchdog is enabled, replacing one an earlier down phase left.
+// It is best-effort: the maintenance goes on without the safety timer.
+func armNoOutWatchdog(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, scope NoOutScope, callback func(DownPhaseProgress)) {
+	watchdog := cfg.Ceph.NoOutWatchdog
+	if !watchdog.Enabled || (!scope.Cluster() && len(scope.Groups) == 0) {
+		return
+	}
+	if _, err := client.DeleteNoOutWatchdogs(ctx, cfg.Namespace, nodeName); err != nil {
+		logger.Warn("failed to replace noout watchdog", "node", nodeName, "error", err)
+		return
+	}
+
+	now := time.Now()
+	expires := now.Add(time.Duration(watchdog.TTLSeconds) * time.Second)
+	job := k8s.BuildNoOutWatchdogJob(k8s.NoOutWatchdogOptions{
+		Namespace:      cfg.Namespace,
+		Cluster:        cfg.Cluster,
+		NodeName:       nodeName,
+		Image:          watchdog.Image,
+		ServiceAccount: watchdog.ServiceAccount,
+		Expires:        expires,
+		Scope:          scope.Scope,
+		Groups:         scope.Groups,
+	}, now)
+	created, err := client.CreateJob(ctx, job)
+	if err != nil {
+		logger.Warn("failed to create noout watchdog", "node", nodeName, "error", err)
+		updateProgress(callback, "noout", "Warning: no noout watchdog, the flag will not expire: "+err.Error(), "")
+		return
+	}
+	updateProgress(callback, "noout", fmt.Sprintf("Noout watchdog %s clears the flag at %s unless 'crook up' runs first",
+		created.Name, expires.Format(time.RFC3339)), "")
+}
+
+// disarmNoOutWatchdog deletes the node's noout watchdog Jobs once the up
+// phase has cleared the flag itself. Jobs left from an earlier configuration
+// are removed too; failing to list them only matters when the watchdog is on.
+func disarmNoOutWatchdog(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, callback func(UpPhaseProgress)) {
+	deleted, err := client.DeleteNoOutWatchdogs(ctx, cfg.Namespace, nodeName)
+	switch {
+	case err != nil && cfg.Ceph.NoOutWatchdog.Enabled:
+		logger.Warn("failed to delete noout watchdog", "node", nodeName, "error", err)
+	case err != nil:
+		logger.Debug("failed to delete noout watchdog", "node", nodeName, "error", err)
+	}
+	if len(deleted) > 0 {
+		sendUpProgress(callback, "unset-noout", "Deleted noout watchdog "+strings.Join(deleted, ", "), "")
+	}
+}
+
+// RunNoOutWatchdog waits until expires and then clears the noout scope, unless
+// the node's maintenance was closed meanwhile. It runs inside the watchdog Job.
+func RunNoOutWatchdog(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, scope NoOutScope, expires time.Time) error {
+	if wait := time.Until(expires); wait > 0 {
+		logger.Info("noout watchdog armed", "node", nodeName, "scope", scope.String(), "expires", expires)
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("noout watchdog of %s stopped before expiry: %w", nodeName, ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	report, err := LoadReport(ctx, client, cfg, nodeName)
+	switch {
+	case err == nil && report.After != nil:
+		logger.Info("maintenance already closed, leaving noout alone", "node", nodeName)
+		return nil
+	case err != nil && !errors.Is(err, ErrNoReport):
+		logger.Debug("failed to load maintenance report", "node", nodeName, "error", err)
+	}
+
+	logger.Warn("noout watchdog expired, clearing the noout flag left by the maintenance", "node", nodeName, "scope", scope.String())
+	if err := unsetNoOut(ctx, client, cfg, scope); err != nil {
+		return fmt.Errorf("failed to unset noout flag: %w", err)
+	}
+	return nil
+}
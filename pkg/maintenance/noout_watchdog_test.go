@@ -0,0 +1,90 @@
+package maintenance
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNoOutWatchdogLifecycle(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewClientset()
+	client := &k8s.Client{Clientset: clientset}
+	cfg := config.DefaultConfig()
+	host := NoOutScope{Scope: config.NoOutScopeHost, Groups: []string{"worker-1"}}
+
+	jobs := func() []string {
+		list, err := clientset.BatchV1().Jobs(cfg.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, job := range list.Items {
+			names = append(names, strings.Join(job.Spec.Template.Spec.Containers[0].Args[:2], " "))
+		}
+		return names
+	}
+
+	armNoOutWatchdog(ctx, client, cfg, "worker-1", host, nil)
+	if got := jobs(); len(got) != 0 {
+		t.Fatalf("disabled watchdog created jobs: %v", got)
+	}
+
+	cfg.Ceph.NoOutWatchdog.Enabled = true
+	cfg.Ceph.NoOutWatchdog.Image = "example.com/crook:v1"
+	armNoOutWatchdog(ctx, client, cfg, "worker-2", NoOutScope{Scope: config.NoOutScopeHost}, nil)
+	if got := jobs(); len(got) != 0 {
+		t.Fatalf("watchdog created for a node without OSDs: %v", got)
+	}
+
+	var progress []string
+	armNoOutWatchdog(ctx, client, cfg, "worker-1", host, func(p DownPhaseProgress) {
+		progress = append(progress, p.Description)
+	})
+	if got := jobs(); len(got) != 1 || got[0] != "noout-watchdog worker-1" {
+		t.Fatalf("jobs = %v, want one watchdog for worker-1", got)
+	}
+	if len(progress) != 1 || !strings.Contains(progress[0], "unless 'crook up' runs first") {
+		t.Errorf("progress = %v", progress)
+	}
+
+	var upProgress []string
+	disarmNoOutWatchdog(ctx, client, cfg, "worker-1", func(p UpPhaseProgress) {
+		upProgress = append(upProgress, p.Description)
+	})
+	if got := jobs(); len(got) != 0 {
+		t.Errorf("jobs after disarm = %v, want none", got)
+	}
+	if len(upProgress) != 1 || !strings.HasPrefix(upProgress[0], "Deleted noout watchdog") {
+		t.Errorf("up progress = %v", upProgress)
+	}
+}
+
+func TestRunNoOutWatchdogClosedMaintenance(t *testing.T) {
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	cfg := config.DefaultConfig()
+
+	report := &MaintenanceReport{Node: "worker-1", Before: &ClusterSnapshot{}, After: &ClusterSnapshot{}}
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+
+	// The up phase closed the maintenance, so no ceph command is run
+	scope := NoOutScope{Scope: config.NoOutScopeCluster}
+	if err := RunNoOutWatchdog(ctx, client, cfg, "worker-1", scope, time.Now().Add(-time.Minute)); err != nil {
+		t.Errorf("RunNoOutWatchdog() error = %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := RunNoOutWatchdog(cancelled, client, cfg, "worker-1", scope, time.Now().Add(time.Hour)); err == nil {
+		t.Error("expected a cancelled watchdog to fail before expiry")
+	}
+}
@@ -0,0 +1,140 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// PlanDiff compares a down plan with the workloads the node's previous
+// maintenance scaled, so topology changes are reviewed before scaling
+type PlanDiff struct {
+	// Since is when the previous maintenance started
+	Since time.Time
+
+	// Previous and Current count the workloads of both plans
+	Previous int
+	Current  int
+
+	// Added and Removed list the workloads (namespace/name) new to the plan
+	// and no longer in it
+	Added   []string
+	Removed []string
+}
+
+// Changed reports whether the plan differs from the previous maintenance
+func (d *PlanDiff) Changed() bool {
+	return d != nil && (len(d.Added) > 0 || len(d.Removed) > 0)
+}
+
+// Summary compares the workload counts, e.g. "Last maintenance (2026-01-02):
+// 12 workloads, now 13"
+func (d *PlanDiff) Summary() string {
+	return fmt.Sprintf("Last maintenance (%s): %d workloads, now %d", d.Since.Local().Format(time.DateOnly), d.Previous, d.Current)
+}
+
+// AddedNames returns the names of the workloads new to the plan
+func (d *PlanDiff) AddedNames() []string {
+	return shortNames(d.Added)
+}
+
+// RemovedNames returns the names of the workloads no longer in the plan
+func (d *PlanDiff) RemovedNames() []string {
+	return shortNames(d.Removed)
+}
+
+// String summarizes the diff, e.g. "Last maintenance (2026-01-02): 12
+// workloads, now 13 — new: rook-ceph-osd-42"
+func (d *PlanDiff) String() string {
+	var b strings.Builder
+	b.WriteString(d.Summary())
+	if len(d.Added) > 0 {
+		fmt.Fprintf(&b, " — new: %s", strings.Join(d.AddedNames(), ", "))
+	}
+	if len(d.Removed) > 0 {
+		fmt.Fprintf(&b, " — gone: %s", strings.Join(d.RemovedNames(), ", "))
+	}
+	return b.String()
+}
+
+// shortNames drops the namespace of namespace/name entries
+func shortNames(names []string) []string {
+	short := make([]string, 0, len(names))
+	for _, name := range names {
+		short = append(short, path.Base(name))
+	}
+	return short
+}
+
+// workloadNames returns the namespace/name of each workload
+func workloadNames(workloads []k8s.Workload) []string {
+	names := make([]string, 0, len(workloads))
+	for _, w := range workloads {
+		names = append(names, w.String())
+	}
+	return names
+}
+
+// DiffLastMaintenance compares the down plan's workloads with those recorded
+// in the node's maintenance report. Returns nil when no earlier plan was
+// recorded for the node.
+func DiffLastMaintenance(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, workloads []k8s.Workload) *PlanDiff {
+	report, err := LoadReport(ctx, client, cfg, nodeName)
+	if err != nil {
+		if !errors.Is(err, ErrNoReport) {
+			logger.Debug("failed to load maintenance report", "node", nodeName, "error", err)
+		}
+		return nil
+	}
+	if len(report.Workloads) == 0 || report.Before == nil {
+		return nil
+	}
+
+	current := workloadNames(workloads)
+	diff := &PlanDiff{
+		Since:    report.Before.Time,
+		Previous: len(report.Workloads),
+		Current:  len(current),
+	}
+	for _, name := range current {
+		if !slices.Contains(report.Workloads, name) {
+			diff.Added = append(diff.Added, name)
+		}
+	}
+	for _, name := range report.Workloads {
+		if !slices.Contains(current, name) {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	return diff
+}
+
+// RecordPlannedWorkloads stores the down plan's workloads in the node's open
+// maintenance report, for the next maintenance to diff against. A plan
+// already recorded for the open report is kept, so re-running the down phase
+// does not replace it. Best-effort.
+func RecordPlannedWorkloads(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, workloads []k8s.Workload) {
+	report, err := LoadReport(ctx, client, cfg, nodeName)
+	if err != nil {
+		if !errors.Is(err, ErrNoReport) {
+			logger.Warn("failed to load maintenance report", "node", nodeName, "error", err)
+		}
+		return
+	}
+	if report.After != nil || len(report.Workloads) > 0 {
+		return
+	}
+
+	report.Workloads = workloadNames(workloads)
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		logger.Warn("failed to save maintenance report", "node", nodeName, "error", err)
+	}
+}
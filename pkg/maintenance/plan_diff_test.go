@@ -0,0 +1,155 @@
+package maintenance
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testWorkloads(names ...string) []k8s.Workload {
+	workloads := make([]k8s.Workload, 0, len(names))
+	for _, name := range names {
+		workloads = append(workloads, k8s.Workload{Kind: k8s.WorkloadKindDeployment, Namespace: "rook-ceph", Name: name})
+	}
+	return workloads
+}
+
+func TestDiffLastMaintenance(t *testing.T) {
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		report      *MaintenanceReport
+		current     []k8s.Workload
+		wantNil     bool
+		wantChanged bool
+		wantAdded   []string
+		wantRemoved []string
+	}{
+		{
+			name:    "no report",
+			current: testWorkloads("rook-ceph-osd-0"),
+			wantNil: true,
+		},
+		{
+			name:    "report without recorded workloads",
+			report:  &MaintenanceReport{Node: "worker-1", Before: &ClusterSnapshot{Time: since}},
+			current: testWorkloads("rook-ceph-osd-0"),
+			wantNil: true,
+		},
+		{
+			name: "unchanged plan",
+			report: &MaintenanceReport{
+				Node:      "worker-1",
+				Before:    &ClusterSnapshot{Time: since},
+				Workloads: []string{"rook-ceph/rook-ceph-osd-0", "rook-ceph/rook-ceph-mon-a"},
+			},
+			current: testWorkloads("rook-ceph-mon-a", "rook-ceph-osd-0"),
+		},
+		{
+			name: "new and removed workloads",
+			report: &MaintenanceReport{
+				Node:      "worker-1",
+				Before:    &ClusterSnapshot{Time: since},
+				After:     &ClusterSnapshot{Time: since.Add(time.Hour)},
+				Workloads: []string{"rook-ceph/rook-ceph-osd-0", "rook-ceph/rook-ceph-mon-a"},
+			},
+			current:     testWorkloads("rook-ceph-osd-0", "rook-ceph-osd-42"),
+			wantChanged: true,
+			wantAdded:   []string{"rook-ceph/rook-ceph-osd-42"},
+			wantRemoved: []string{"rook-ceph/rook-ceph-mon-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := &k8s.Client{Clientset: fake.NewClientset()}
+			cfg := config.DefaultConfig()
+			if tt.report != nil {
+				if err := SaveReport(ctx, client, cfg, tt.report); err != nil {
+					t.Fatalf("SaveReport() error = %v", err)
+				}
+			}
+
+			diff := DiffLastMaintenance(ctx, client, cfg, "worker-1", tt.current)
+			if tt.wantNil {
+				if diff != nil {
+					t.Errorf("DiffLastMaintenance() = %+v, want nil", diff)
+				}
+				return
+			}
+			if diff == nil {
+				t.Fatal("DiffLastMaintenance() = nil, want a diff")
+			}
+			if diff.Changed() != tt.wantChanged {
+				t.Errorf("Changed() = %v, want %v", diff.Changed(), tt.wantChanged)
+			}
+			if !slices.Equal(diff.Added, tt.wantAdded) || !slices.Equal(diff.Removed, tt.wantRemoved) {
+				t.Errorf("Added = %v, Removed = %v, want %v and %v", diff.Added, diff.Removed, tt.wantAdded, tt.wantRemoved)
+			}
+			if !diff.Since.Equal(since) {
+				t.Errorf("Since = %v, want %v", diff.Since, since)
+			}
+		})
+	}
+}
+
+func TestPlanDiff_String(t *testing.T) {
+	diff := &PlanDiff{
+		Since:    time.Date(2026, 1, 2, 12, 0, 0, 0, time.Local),
+		Previous: 12,
+		Current:  13,
+		Added:    []string{"rook-ceph/rook-ceph-osd-42"},
+	}
+	want := "Last maintenance (2026-01-02): 12 workloads, now 13 — new: rook-ceph-osd-42"
+	if got := diff.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	diff.Removed = []string{"rook-ceph/rook-ceph-osd-7"}
+	want += " — gone: rook-ceph-osd-7"
+	if got := diff.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	var none *PlanDiff
+	if none.Changed() {
+		t.Error("nil diff should not be changed")
+	}
+}
+
+func TestRecordPlannedWorkloads(t *testing.T) {
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	cfg := config.DefaultConfig()
+
+	// Without an open report nothing is recorded
+	RecordPlannedWorkloads(ctx, client, cfg, "worker-1", testWorkloads("rook-ceph-osd-0"))
+	if _, err := LoadReport(ctx, client, cfg, "worker-1"); err == nil {
+		t.Fatal("RecordPlannedWorkloads() should not create a report")
+	}
+
+	report := &MaintenanceReport{Node: "worker-1", Before: &ClusterSnapshot{Time: time.Now()}}
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+	RecordPlannedWorkloads(ctx, client, cfg, "worker-1", testWorkloads("rook-ceph-osd-0", "rook-ceph-osd-1"))
+
+	// A re-run of the down phase keeps the plan recorded first
+	RecordPlannedWorkloads(ctx, client, cfg, "worker-1", testWorkloads("rook-ceph-osd-1"))
+
+	loaded, err := LoadReport(ctx, client, cfg, "worker-1")
+	if err != nil {
+		t.Fatalf("LoadReport() error = %v", err)
+	}
+	want := []string{"rook-ceph/rook-ceph-osd-0", "rook-ceph/rook-ceph-osd-1"}
+	if !slices.Equal(loaded.Workloads, want) {
+		t.Errorf("Workloads = %v, want %v", loaded.Workloads, want)
+	}
+}
@@ -0,0 +1,66 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// healthPolicyCheck is the pre-flight check name for the configured health policy
+const healthPolicyCheck = "Ceph health policy"
+
+// HealthPolicyViolations returns the ways the Ceph status breaks the
+// configured policy. An empty result means the node may go down.
+func HealthPolicyViolations(policy config.PolicyConfig, status *k8s.CephStatus) []string {
+	var violations []string
+
+	if policy.RequireHealthOKBeforeDown && !status.IsHealthy() {
+		violations = append(violations, fmt.Sprintf("Ceph health is %s, policy requires HEALTH_OK", status.Health.Status))
+	}
+
+	if limit := policy.MaxDegradedPGsPercent; limit != nil {
+		if degraded := status.PGMap.DegradedPGsPercent(); degraded > *limit {
+			violations = append(violations, fmt.Sprintf("%.1f%% of PGs are degraded, policy allows %g%%", degraded, *limit))
+		}
+	}
+
+	if !policy.NearFullAllowed() && status.IsNearFull() {
+		violations = append(violations, "OSDs or pools are near full, policy does not allow maintenance")
+	}
+
+	return violations
+}
+
+// validateHealthPolicy checks the Ceph status against the configured policy.
+// Policies that keep the built-in behavior skip the check entirely.
+func validateHealthPolicy(ctx context.Context, client *k8s.Client, cfg config.Config) (ValidationResult, bool) {
+	if !cfg.Policy.GatesHealth() {
+		return ValidationResult{}, false
+	}
+
+	status, err := client.GetCephStatus(ctx, cfg.Namespace)
+	if err != nil {
+		return ValidationResult{
+			Check:   healthPolicyCheck,
+			Error:   err,
+			Message: "Unable to read Ceph status to enforce the policy",
+		}, true
+	}
+
+	if violations := HealthPolicyViolations(cfg.Policy, status); len(violations) > 0 {
+		return ValidationResult{
+			Check:   healthPolicyCheck,
+			Error:   fmt.Errorf("policy violated: %s", strings.Join(violations, "; ")),
+			Message: strings.Join(violations, "; "),
+		}, true
+	}
+
+	return ValidationResult{
+		Check:   healthPolicyCheck,
+		Passed:  true,
+		Message: fmt.Sprintf("Ceph status %s is within policy", status.Health.Status),
+	}, true
+}
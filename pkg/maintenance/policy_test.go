@@ -0,0 +1,79 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+const degradedNearFullStatusJSON = `{
+	"health": {
+		"status": "HEALTH_WARN",
+		"checks": {"OSD_NEARFULL": {"severity": "HEALTH_WARN", "summary": {"message": "1 nearfull osd(s)"}}}
+	},
+	"pgmap": {
+		"num_pgs": 200,
+		"pgs_by_state": [
+			{"state_name": "active+clean", "count": 180},
+			{"state_name": "active+undersized+degraded", "count": 20}
+		]
+	}
+}`
+
+func TestHealthPolicyViolations(t *testing.T) {
+	var status k8s.CephStatus
+	if err := json.Unmarshal([]byte(degradedNearFullStatusJSON), &status); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	limit := func(v float64) *float64 { return &v }
+	deny := false
+
+	tests := []struct {
+		name   string
+		policy config.PolicyConfig
+		want   []string
+	}{
+		{name: "default policy", policy: config.PolicyConfig{}},
+		{
+			name:   "require HEALTH_OK",
+			policy: config.PolicyConfig{RequireHealthOKBeforeDown: true},
+			want:   []string{"HEALTH_WARN"},
+		},
+		{
+			name:   "degraded PGs above limit",
+			policy: config.PolicyConfig{MaxDegradedPGsPercent: limit(5)},
+			want:   []string{"10.0% of PGs are degraded"},
+		},
+		{name: "degraded PGs within limit", policy: config.PolicyConfig{MaxDegradedPGsPercent: limit(10)}},
+		{
+			name:   "near full denied",
+			policy: config.PolicyConfig{AllowWhenNearFull: &deny},
+			want:   []string{"near full"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HealthPolicyViolations(tt.policy, &status)
+			if len(got) != len(tt.want) {
+				t.Fatalf("HealthPolicyViolations() = %v, want %d violation(s)", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("violation %d = %q, want it to mention %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateHealthPolicy_SkippedByDefault(t *testing.T) {
+	client := &k8s.Client{}
+	if _, enforced := validateHealthPolicy(t.Context(), client, config.DefaultConfig()); enforced {
+		t.Error("default policy should not query Ceph status")
+	}
+}
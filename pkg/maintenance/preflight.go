@@ -0,0 +1,109 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Progress stages reported while the pre-flight checks run
+const (
+	preflightStage      = "pre-flight"
+	preflightCheckStage = "pre-flight-check"
+)
+
+// PreflightProgressStages lists the progress stages of the pre-flight checks
+var PreflightProgressStages = []string{preflightStage, preflightCheckStage}
+
+// preflightConcurrency bounds the pre-flight checks running at once, so a
+// large check set does not flood the API server or the toolbox pod
+const preflightConcurrency = 4
+
+// preflightCheck is one pre-flight check. Checks are independent of each
+// other and run concurrently, each adding its results and warnings to its
+// own ValidationResults.
+type preflightCheck struct {
+	name string
+	run  func(ctx context.Context, results *ValidationResults)
+}
+
+// CheckStatus reports a pre-flight check that finished
+type CheckStatus struct {
+	// Name of the check, e.g. "RBAC permissions"
+	Name string
+
+	// Passed is false when a result of the check blocks the phase
+	Passed bool
+
+	// Warnings counts the non-blocking findings of the check, including
+	// failed SeverityWarn results
+	Warnings int
+
+	// Done and Total count the finished checks and all checks
+	Done  int
+	Total int
+}
+
+// String returns the check as "Pre-flight check 3/11: RBAC permissions"
+func (c CheckStatus) String() string {
+	return fmt.Sprintf("Pre-flight check %d/%d: %s", c.Done, c.Total, c.Name)
+}
+
+// runPreflightChecks runs the checks with at most preflightConcurrency at a
+// time and merges their results in check order, so the report reads the
+// same whatever order they finished in. onCheck is called from the calling
+// goroutine as each check finishes; it may be nil.
+func runPreflightChecks(ctx context.Context, checks []preflightCheck, onCheck func(CheckStatus)) *ValidationResults {
+	partial := make([]*ValidationResults, len(checks))
+	finished := make(chan int)
+	slots := make(chan struct{}, preflightConcurrency)
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Go(func() {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			results := &ValidationResults{AllPassed: true}
+			check.run(ctx, results)
+			partial[i] = results
+			finished <- i
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	done := 0
+	for i := range finished {
+		done++
+		if onCheck != nil {
+			warnings := len(partial[i].Warnings)
+			for _, r := range partial[i].Results {
+				if !r.Passed && !r.Blocking() {
+					warnings++
+				}
+			}
+			onCheck(CheckStatus{
+				Name:     checks[i].name,
+				Passed:   !partial[i].Blocked(),
+				Warnings: warnings,
+				Done:     done,
+				Total:    len(checks),
+			})
+		}
+	}
+
+	results := &ValidationResults{
+		Results:   make([]ValidationResult, 0),
+		AllPassed: true,
+	}
+	for _, p := range partial {
+		for _, r := range p.Results {
+			results.add(r)
+		}
+		results.Warnings = append(results.Warnings, p.Warnings...)
+	}
+	return results
+}
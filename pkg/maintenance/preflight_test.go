@@ -0,0 +1,73 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunPreflightChecks(t *testing.T) {
+	var running, peak atomic.Int32
+	checks := make([]preflightCheck, 0, 10)
+	for i := range 10 {
+		checks = append(checks, preflightCheck{
+			name: fmt.Sprintf("check-%d", i),
+			run: func(_ context.Context, results *ValidationResults) {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				// Later checks finish first
+				time.Sleep(time.Duration(10-i) * time.Millisecond)
+				running.Add(-1)
+
+				if i == 3 {
+					results.addResult(fmt.Sprintf("check-%d", i), false, errors.New("denied"), "failed")
+					return
+				}
+				results.addResult(fmt.Sprintf("check-%d", i), true, nil, "ok")
+				if i == 5 {
+					results.Warnings = append(results.Warnings, "check-5 warning")
+				}
+			},
+		})
+	}
+
+	var updates []CheckStatus
+	results := runPreflightChecks(t.Context(), checks, func(c CheckStatus) {
+		updates = append(updates, c)
+	})
+
+	if got := peak.Load(); got > preflightConcurrency {
+		t.Errorf("peak concurrency = %d, want at most %d", got, preflightConcurrency)
+	}
+	if results.AllPassed {
+		t.Error("AllPassed should be false when a check fails")
+	}
+	for i, r := range results.Results {
+		if want := fmt.Sprintf("check-%d", i); r.Check != want {
+			t.Errorf("Results[%d] = %q, want %q in check order", i, r.Check, want)
+		}
+	}
+	if len(results.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want the warning of check-5", results.Warnings)
+	}
+
+	if len(updates) != len(checks) {
+		t.Fatalf("onCheck called %d times, want %d", len(updates), len(checks))
+	}
+	for i, u := range updates {
+		if u.Done != i+1 || u.Total != len(checks) {
+			t.Errorf("update %d = %d/%d, want %d/%d", i, u.Done, u.Total, i+1, len(checks))
+		}
+		if wantPassed := u.Name != "check-3"; u.Passed != wantPassed {
+			t.Errorf("%s Passed = %v, want %v", u.Name, u.Passed, wantPassed)
+		}
+	}
+}
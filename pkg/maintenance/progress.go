@@ -0,0 +1,132 @@
+package maintenance
+
+import "sync"
+
+// stageWeight is the range of a phase's percentage a progress stage covers.
+// A stage told how many updates to expect (see progressSequence.expect)
+// splits its range evenly across them, e.g. one step per deployment.
+type stageWeight struct {
+	start int
+	end   int
+}
+
+// downStageWeights is the share of the down phase each progress stage
+// covers; scaling the workloads is the bulk of it. Stages not listed keep
+// the current percentage.
+var downStageWeights = map[string]stageWeight{
+	"pre-flight":       {0, 10},
+	"cordon":           {10, 20},
+	"noout":            {20, 25},
+	"operator":         {25, 30},
+	"discover":         {30, 30},
+	"skip":             {30, 30},
+	"scale-down":       {30, 90},
+	drainStage:         {90, 100},
+	drainEvictingStage: {90, 100},
+	drainBlockedStage:  {90, 100},
+	drainEvictedStage:  {90, 100},
+	forceDeleteStage:   {90, 100},
+	"complete":         {100, 100},
+}
+
+// upStageWeights is the share of the up phase each progress stage covers.
+// The MON quorum wait falls inside the scale-up range.
+var upStageWeights = map[string]stageWeight{
+	"pre-flight":   {0, 10},
+	"discover":     {10, 20},
+	"uncordon":     {20, 30},
+	"skip":         {30, 30},
+	"scale-up":     {30, 90},
+	"operator":     {90, 95},
+	"unset-noout":  {95, 100},
+	smokeTestStage: {97, 100},
+	"complete":     {100, 100},
+}
+
+// progressSequence stamps the progress updates of one phase run with a
+// monotonically increasing sequence number and the phase's completion
+// percentage. Safe for concurrent use.
+type progressSequence struct {
+	mu       sync.Mutex
+	stages   map[string]stageWeight
+	expected map[string]int
+	seen     map[string]int
+	seq      uint64
+	percent  int
+}
+
+// newProgressSequence creates a sequence using the given stage weights
+func newProgressSequence(stages map[string]stageWeight) *progressSequence {
+	return &progressSequence{
+		stages:   stages,
+		expected: make(map[string]int),
+		seen:     make(map[string]int),
+	}
+}
+
+// expect sets how many updates a stage reports, one per workload scaled,
+// so each of them advances the percentage by an equal share of the stage
+func (s *progressSequence) expect(stage string, updates int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expected[stage] = updates
+}
+
+// next returns the sequence number and percentage of an update in stage.
+// The percentage never decreases, so stages revisited later in the phase
+// do not move a progress bar backwards.
+func (s *progressSequence) next(stage string) (uint64, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	weight, ok := s.stages[stage]
+	if !ok {
+		return s.seq, s.percent
+	}
+
+	percent := weight.start
+	if expected := s.expected[stage]; expected > 0 {
+		// The n-th update starts the n-th share of the stage
+		done := min(s.seen[stage], expected)
+		percent += (weight.end - weight.start) * done / expected
+	}
+	s.seen[stage]++
+
+	s.percent = max(s.percent, percent)
+	return s.seq, s.percent
+}
+
+// downCallback wraps a down phase callback so every update carries its
+// Sequence and Percent. Returns nil for a nil callback.
+func (s *progressSequence) downCallback(callback func(DownPhaseProgress)) func(DownPhaseProgress) {
+	if callback == nil {
+		return nil
+	}
+	return func(p DownPhaseProgress) {
+		p.Sequence, p.Percent = s.next(p.Stage)
+		callback(p)
+	}
+}
+
+// upCallback wraps an up phase callback so every update carries its
+// Sequence and Percent. Returns nil for a nil callback.
+func (s *progressSequence) upCallback(callback func(UpPhaseProgress)) func(UpPhaseProgress) {
+	if callback == nil {
+		return nil
+	}
+	return func(p UpPhaseProgress) {
+		p.Sequence, p.Percent = s.next(p.Stage)
+		callback(p)
+	}
+}
+
+// DroppedUpdates returns how many updates were lost between the sequence
+// numbers of two consecutively received updates. Consumers reading progress
+// from a non-blocking channel use it to detect that they fell behind.
+func DroppedUpdates(last, current uint64) uint64 {
+	if current <= last+1 {
+		return 0
+	}
+	return current - last - 1
+}
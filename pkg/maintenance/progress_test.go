@@ -0,0 +1,95 @@
+package maintenance
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestProgressSequence_Down(t *testing.T) {
+	if newProgressSequence(downStageWeights).downCallback(nil) != nil {
+		t.Error("downCallback(nil) should stay nil")
+	}
+
+	var got []DownPhaseProgress
+	seq := newProgressSequence(downStageWeights)
+	seq.expect("scale-down", 4)
+	callback := seq.downCallback(func(p DownPhaseProgress) { got = append(got, p) })
+	for _, stage := range []string{"pre-flight", "cordon", "noout", "scale-down", "scale-down", "scale-down", "scale-down", "unknown", "drain", "complete"} {
+		callback(DownPhaseProgress{Stage: stage})
+	}
+
+	// The scale-down range 30-90 is split across the 4 expected workloads
+	wantPercent := []int{0, 10, 20, 30, 45, 60, 75, 75, 90, 100}
+	for i, p := range got {
+		if p.Sequence != uint64(i+1) {
+			t.Errorf("update %d Sequence = %d, want %d", i, p.Sequence, i+1)
+		}
+		if p.Percent != wantPercent[i] {
+			t.Errorf("update %d (%s) Percent = %d, want %d", i, p.Stage, p.Percent, wantPercent[i])
+		}
+	}
+}
+
+func TestProgressSequence_UpNeverDecreases(t *testing.T) {
+	var got []UpPhaseProgress
+	seq := newProgressSequence(upStageWeights)
+	seq.expect("scale-up", 2)
+	callback := seq.upCallback(func(p UpPhaseProgress) { got = append(got, p) })
+	for _, stage := range []string{"uncordon", "scale-up", "quorum", "scale-up", "operator", "scale-up"} {
+		callback(UpPhaseProgress{Stage: stage})
+	}
+
+	for i := 1; i < len(got); i++ {
+		if got[i].Percent < got[i-1].Percent {
+			t.Errorf("Percent went from %d to %d at %s", got[i-1].Percent, got[i].Percent, got[i].Stage)
+		}
+	}
+	if last := got[len(got)-1].Percent; last != 90 {
+		t.Errorf("final Percent = %d, want 90", last)
+	}
+}
+
+func TestProgressSequence_Concurrent(t *testing.T) {
+	seq := newProgressSequence(downStageWeights)
+
+	var mu sync.Mutex
+	seen := make(map[uint64]bool)
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			n, _ := seq.next("scale-down")
+			mu.Lock()
+			seen[n] = true
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	for n := uint64(1); n <= 50; n++ {
+		if !seen[n] {
+			t.Errorf("sequence number %d was not handed out", n)
+		}
+	}
+}
+
+func TestDroppedUpdates(t *testing.T) {
+	tests := []struct {
+		name          string
+		last, current uint64
+		want          uint64
+	}{
+		{"first update", 0, 1, 0},
+		{"consecutive", 4, 5, 0},
+		{"gap", 4, 8, 3},
+		{"unsequenced", 0, 0, 0},
+		{"out of order", 5, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DroppedUpdates(tt.last, tt.current); got != tt.want {
+				t.Errorf("DroppedUpdates(%d, %d) = %d, want %d", tt.last, tt.current, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package maintenance
+
+import (
+	"context"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	authv1 "k8s.io/api/authorization/v1"
+)
+
+// Permission is a permission crook may need, with the outcome of its
+// SelfSubjectAccessReview
+type Permission struct {
+	Verb        string `json:"verb"`
+	Group       string `json:"group,omitempty"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+
+	// Namespace is empty for cluster-wide permissions
+	Namespace string `json:"namespace,omitempty"`
+
+	// Purpose names what crook needs the permission for
+	Purpose string `json:"purpose"`
+
+	// Required is set when 'crook down' and 'crook up' need the permission
+	// with the current configuration; the others serve optional features
+	Required bool `json:"required"`
+
+	Allowed bool `json:"allowed"`
+
+	// Error is why the permission could not be verified
+	Error string `json:"error,omitempty"`
+}
+
+// String returns the permission as "update apps/deployments/scale [rook-ceph]"
+func (p Permission) String() string {
+	return formatPermissionCheck(p.attributes())
+}
+
+// Denied reports whether the review answered that the permission is missing
+func (p Permission) Denied() bool {
+	return !p.Allowed && p.Error == ""
+}
+
+func (p Permission) attributes() *authv1.ResourceAttributes {
+	return &authv1.ResourceAttributes{
+		Verb:        p.Verb,
+		Group:       p.Group,
+		Resource:    p.Resource,
+		Subresource: p.Subresource,
+		Namespace:   p.Namespace,
+	}
+}
+
+// PermissionReport is the outcome of 'crook check rbac'
+type PermissionReport struct {
+	Namespace   string       `json:"namespace"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// MissingRequired returns the required permissions the review denied
+func (r *PermissionReport) MissingRequired() []Permission {
+	var missing []Permission
+	for _, p := range r.Permissions {
+		if p.Required && p.Denied() {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+// permissionRequirement is an entry of the permission catalog
+type permissionRequirement struct {
+	verb, group, resource, subresource string
+
+	// namespaced permissions are checked in the Rook namespace
+	namespaced bool
+
+	// namespace overrides the Rook namespace of a namespaced permission
+	namespace func(cfg config.Config) string
+
+	purpose string
+
+	// required reports whether the configured phases need the permission;
+	// nil means it only serves optional commands
+	required func(cfg config.Config) bool
+
+	// preflight marks the permissions the down pre-flight verifies when
+	// they are required
+	preflight bool
+}
+
+func always(config.Config) bool { return true }
+
+func drainEnabled(cfg config.Config) bool { return cfg.Drain.Enabled }
+
+func statefulSetsEnabled(cfg config.Config) bool { return cfg.Discovery.StatefulSets }
+
+func approvalRequired(cfg config.Config) bool { return cfg.Policy.RequireApprovalBeforeDown }
+
+func smokeTestEnabled(cfg config.Config) bool { return cfg.SmokeTest.Enabled }
+
+func nooutWatchdogEnabled(cfg config.Config) bool { return cfg.Ceph.NoOutWatchdog.Enabled }
+
+// smokeTestNamespace is the namespace of the smoke test volumes and pods
+func smokeTestNamespace(cfg config.Config) string {
+	if cfg.SmokeTest.Namespace != "" {
+		return cfg.SmokeTest.Namespace
+	}
+	return cfg.Namespace
+}
+
+// permissionCatalog lists every permission crook may need, the ones the
+// down pre-flight verifies first
+var permissionCatalog = []permissionRequirement{
+	// Cordon and uncordon, node lookups
+	{verb: "patch", resource: "nodes", purpose: "cordon and uncordon", required: always, preflight: true},
+	{verb: "get", resource: "nodes", purpose: "node lookup", required: always, preflight: true},
+	// Node-pinned deployments and their scale subresource (least-privilege)
+	{verb: "get", group: "apps", resource: "deployments", namespaced: true, purpose: "discovery", required: always, preflight: true},
+	{verb: "list", group: "apps", resource: "deployments", namespaced: true, purpose: "discovery", required: always, preflight: true},
+	{verb: "get", group: "apps", resource: "deployments", subresource: "scale", namespaced: true, purpose: "scaling", required: always, preflight: true},
+	{verb: "update", group: "apps", resource: "deployments", subresource: "scale", namespaced: true, purpose: "scaling", required: always, preflight: true},
+	// The rook-ceph-tools pod runs the ceph commands
+	{verb: "list", resource: "pods", namespaced: true, purpose: "ceph commands", required: always, preflight: true},
+	{verb: "create", resource: "pods", subresource: "exec", namespaced: true, purpose: "ceph commands", required: always, preflight: true},
+	// Drain stage: pods on the node and the eviction subresource
+	{verb: "list", resource: "pods", purpose: "drain", required: drainEnabled, preflight: true},
+	{verb: "create", resource: "pods", subresource: "eviction", purpose: "drain", required: drainEnabled, preflight: true},
+	// Node-pinned StatefulSets
+	{verb: "list", group: "apps", resource: "statefulsets", namespaced: true, purpose: "statefulsets", required: statefulSetsEnabled, preflight: true},
+	{verb: "get", group: "apps", resource: "statefulsets", namespaced: true, purpose: "statefulsets", required: statefulSetsEnabled, preflight: true},
+	{verb: "get", group: "apps", resource: "statefulsets", subresource: "scale", namespaced: true, purpose: "statefulsets", required: statefulSetsEnabled, preflight: true},
+	{verb: "update", group: "apps", resource: "statefulsets", subresource: "scale", namespaced: true, purpose: "statefulsets", required: statefulSetsEnabled, preflight: true},
+
+	// Checked by the phases without a pre-flight check of their own
+	{verb: "list", resource: "nodes", purpose: "other nodes in maintenance", required: always},
+	{verb: "get", resource: "pods", purpose: "drain", required: drainEnabled},
+	{verb: "get", resource: "namespaces", purpose: "pre-flight", required: always},
+	{verb: "patch", group: "apps", resource: "deployments", namespaced: true, purpose: "scaling annotations", required: always},
+	{verb: "get", group: "apps", resource: "replicasets", namespaced: true, purpose: "discovery", required: always},
+	{verb: "list", group: k8s.CephClusterGVR.Group, resource: k8s.CephClusterGVR.Resource, namespaced: true, purpose: "CephCluster settings", required: always},
+	{verb: "get", resource: "configmaps", namespaced: true, purpose: "approvals", required: approvalRequired},
+	{verb: "list", resource: "configmaps", namespaced: true, purpose: "approvals", required: approvalRequired},
+	{verb: "create", resource: "configmaps", namespaced: true, purpose: "approvals", required: approvalRequired},
+	{verb: "update", resource: "configmaps", namespaced: true, purpose: "approvals", required: approvalRequired},
+	{verb: "create", group: "authentication.k8s.io", resource: "selfsubjectreviews", purpose: "approvals", required: approvalRequired},
+	{verb: "create", resource: "persistentvolumeclaims", namespaced: true, namespace: smokeTestNamespace, purpose: "smoke test", required: smokeTestEnabled},
+	{verb: "delete", resource: "persistentvolumeclaims", namespaced: true, namespace: smokeTestNamespace, purpose: "smoke test", required: smokeTestEnabled},
+	{verb: "create", resource: "pods", namespaced: true, namespace: smokeTestNamespace, purpose: "smoke test", required: smokeTestEnabled},
+	{verb: "get", resource: "pods", namespaced: true, namespace: smokeTestNamespace, purpose: "smoke test", required: smokeTestEnabled},
+	{verb: "delete", resource: "pods", namespaced: true, namespace: smokeTestNamespace, purpose: "smoke test", required: smokeTestEnabled},
+	{verb: "create", group: "batch", resource: "jobs", namespaced: true, purpose: "noout watchdog", required: nooutWatchdogEnabled},
+	{verb: "list", group: "batch", resource: "jobs", namespaced: true, purpose: "noout watchdog", required: nooutWatchdogEnabled},
+	{verb: "delete", group: "batch", resource: "jobs", namespaced: true, purpose: "noout watchdog", required: nooutWatchdogEnabled},
+
+	// Optional commands and best-effort records
+	{verb: "get", resource: "pods", namespaced: true, purpose: "crook logcat"},
+	{verb: "get", resource: "pods", subresource: "log", namespaced: true, purpose: "crook logcat, run-in-cluster"},
+	{verb: "create", group: "batch", resource: "jobs", namespaced: true, purpose: "run-in-cluster"},
+	{verb: "delete", resource: "pods", purpose: "force-delete-pods, dead-node mode"},
+	{verb: "patch", group: k8s.BareMetalHostGVR.Group, resource: k8s.BareMetalHostGVR.Resource, purpose: "Metal3 reboot hook"},
+	{verb: "get", resource: "configmaps", namespaced: true, purpose: "maintenance windows"},
+	{verb: "create", resource: "configmaps", namespaced: true, purpose: "maintenance windows"},
+	{verb: "update", resource: "configmaps", namespaced: true, purpose: "maintenance windows"},
+	{verb: "delete", resource: "configmaps", namespaced: true, purpose: "maintenance windows"},
+	{verb: "get", group: "apps", resource: "daemonsets", purpose: "kured reboot lock"},
+	{verb: "list", resource: "persistentvolumes", purpose: "volume check"},
+	{verb: "list", group: "storage.k8s.io", resource: "volumeattachments", purpose: "volume check"},
+	{verb: "list", resource: "pods", purpose: "volume check"},
+	{verb: "list", group: "storage.k8s.io", resource: "storageclasses", purpose: "crook pvc ls"},
+	{verb: "list", resource: "persistentvolumeclaims", purpose: "crook pvc ls"},
+}
+
+// permissions returns the catalog entries for the configuration
+func permissions(cfg config.Config) []Permission {
+	perms := make([]Permission, 0, len(permissionCatalog))
+	for _, req := range permissionCatalog {
+		p := Permission{
+			Verb:        req.verb,
+			Group:       req.group,
+			Resource:    req.resource,
+			Subresource: req.subresource,
+			Purpose:     req.purpose,
+			Required:    req.required != nil && req.required(cfg),
+		}
+		if req.namespaced {
+			p.Namespace = cfg.Namespace
+			if req.namespace != nil {
+				p.Namespace = req.namespace(cfg)
+			}
+		}
+		perms = append(perms, p)
+	}
+	return perms
+}
+
+// preflightPermissions returns the permissions the down pre-flight verifies
+func preflightPermissions(cfg config.Config) []Permission {
+	var perms []Permission
+	for i, p := range permissions(cfg) {
+		if permissionCatalog[i].preflight && p.Required {
+			perms = append(perms, p)
+		}
+	}
+	return perms
+}
+
+// CheckPermissions reviews every permission crook may need with
+// SelfSubjectAccessReviews, for 'crook check rbac'. Reviews that fail are
+// recorded in the permission's Error rather than failing the report.
+func CheckPermissions(ctx context.Context, client *k8s.Client, cfg config.Config) *PermissionReport {
+	report := &PermissionReport{Namespace: cfg.Namespace, Permissions: permissions(cfg)}
+	for i := range report.Permissions {
+		p := &report.Permissions[i]
+		allowed, err := checkPermission(ctx, client, p.attributes())
+		if err != nil {
+			p.Error = err.Error()
+			continue
+		}
+		p.Allowed = allowed
+	}
+	return report
+}
@@ -0,0 +1,74 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestPreflightPermissions(t *testing.T) {
+	cfg := config.DefaultConfig()
+	base := len(preflightPermissions(cfg))
+	if base != 8 {
+		t.Fatalf("preflightPermissions() = %d permissions, want 8 by default", base)
+	}
+
+	cfg.Drain.Enabled = true
+	cfg.Discovery.StatefulSets = true
+	if got := len(preflightPermissions(cfg)); got != base+6 {
+		t.Errorf("preflightPermissions() = %d permissions, want %d with drain and statefulsets", got, base+6)
+	}
+}
+
+func TestCheckPermissions(t *testing.T) {
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		// The role lacks the scale subresource and optional jobs
+		review.Status.Allowed = attrs.Subresource != "scale" && attrs.Resource != "jobs"
+		return true, review, nil
+	})
+	client := &k8s.Client{Clientset: clientset}
+	cfg := config.DefaultConfig()
+
+	report := CheckPermissions(context.Background(), client, cfg)
+	if len(report.Permissions) != len(permissionCatalog) {
+		t.Fatalf("report has %d permissions, want the whole catalog (%d)", len(report.Permissions), len(permissionCatalog))
+	}
+
+	missing := report.MissingRequired()
+	if len(missing) != 2 {
+		t.Fatalf("MissingRequired() = %v, want the two deployments/scale permissions", missing)
+	}
+	for _, p := range missing {
+		if p.String() != "get apps/deployments/scale [rook-ceph]" && p.String() != "update apps/deployments/scale [rook-ceph]" {
+			t.Errorf("unexpected missing permission %s", p)
+		}
+	}
+}
+
+func TestSmokeTestPermissions(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SmokeTest.Enabled = true
+	cfg.SmokeTest.Namespace = "crook-smoke"
+
+	var smokeTest []string
+	for _, p := range permissions(cfg) {
+		if p.Purpose == "smoke test" {
+			if !p.Required {
+				t.Errorf("%s is not required with smoke-test.enabled", p)
+			}
+			smokeTest = append(smokeTest, p.String())
+		}
+	}
+	if len(smokeTest) != 5 || smokeTest[0] != "create persistentvolumeclaims [crook-smoke]" {
+		t.Errorf("smoke test permissions = %v, want five in the smoke test namespace", smokeTest)
+	}
+}
@@ -0,0 +1,147 @@
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// ErrNoRebootHook is returned when a reboot is requested without a
+// configured hooks.reboot mechanism
+var ErrNoRebootHook = errors.New("no reboot hook configured: set hooks.reboot.command, url or bare-metal-host-namespace")
+
+// ErrNodeNotCordoned is returned when a reboot targets a node the down phase
+// has not prepared
+var ErrNodeNotCordoned = errors.New("node is not cordoned")
+
+// rebootHTTPClient calls the URL of a reboot hook; the hook's timeout bounds
+// the request through the context
+var rebootHTTPClient = &http.Client{}
+
+// RebootOptions holds options for RebootNode
+type RebootOptions struct {
+	// PollInterval is how often the node's readiness is checked (default: 5 seconds)
+	PollInterval time.Duration
+
+	// ProgressCallback is called with a description of each step
+	// Optional - if nil, no progress updates are sent
+	ProgressCallback func(description string)
+}
+
+// RebootNode reboots a node the down phase prepared through hooks.reboot,
+// then waits until the node is back Ready, bounded by
+// hooks.reboot.ready-timeout-seconds. A node counts as back once it reports
+// a new boot ID, or is Ready again after it was seen NotReady. Nodes that are
+// not cordoned are refused.
+func RebootNode(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, opts RebootOptions) error {
+	hook := cfg.Hooks.Reboot
+	if !hook.Configured() {
+		return ErrNoRebootHook
+	}
+
+	before, err := client.GetNodeStatus(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+	if !before.Unschedulable {
+		return fmt.Errorf("%w: %s; run 'crook down' before rebooting it", ErrNodeNotCordoned, nodeName)
+	}
+
+	reportReboot(opts, fmt.Sprintf("Rebooting %s through the %s reboot hook", nodeName, hook.Mechanism()))
+	triggerCtx, cancel := context.WithTimeout(ctx, time.Duration(hook.TimeoutSeconds)*time.Second)
+	err = triggerReboot(triggerCtx, client, hook, nodeName)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to trigger the reboot of %s: %w", nodeName, err)
+	}
+
+	readyCtx, cancel := context.WithTimeout(ctx, time.Duration(hook.ReadyTimeoutSeconds)*time.Second)
+	defer cancel()
+	reportReboot(opts, fmt.Sprintf("Waiting for %s to come back Ready", nodeName))
+	return waitForReboot(readyCtx, client, nodeName, before.BootID, opts)
+}
+
+// triggerReboot asks the configured mechanism to reboot the node
+func triggerReboot(ctx context.Context, client *k8s.Client, hook config.RebootHook, nodeName string) error {
+	switch hook.Mechanism() {
+	case "command":
+		_, _, err := runCustomCheckCommand(ctx, config.CustomCheck{Command: hook.Command}, nodeName, "reboot")
+		return err
+	case "url":
+		return postRebootHook(ctx, hook.URL, nodeName)
+	default:
+		return client.RebootBareMetalHost(ctx, hook.BareMetalHostNamespace, nodeName)
+	}
+}
+
+// postRebootHook asks an HTTP endpoint to reboot the node
+func postRebootHook(ctx context.Context, url, nodeName string) error {
+	body, err := json.Marshal(map[string]string{"node": nodeName, "action": "reboot"})
+	if err != nil {
+		return fmt.Errorf("failed to encode reboot request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := rebootHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("reboot request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned HTTP %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// waitForReboot polls the node until it is back Ready from a new boot
+func waitForReboot(ctx context.Context, client *k8s.Client, nodeName, bootID string, opts RebootOptions) error {
+	interval := opts.PollInterval
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	wentDown := false
+	for {
+		status, err := client.GetNodeStatus(ctx, nodeName)
+		switch {
+		case err != nil:
+			// The API server may be unreachable for a moment when it runs on the node
+			logger.Debug("failed to read node readiness", "node", nodeName, "error", err)
+		case !status.Ready:
+			if !wentDown {
+				wentDown = true
+				reportReboot(opts, fmt.Sprintf("%s is down", nodeName))
+			}
+		case wentDown || (bootID != "" && status.BootID != "" && status.BootID != bootID):
+			reportReboot(opts, fmt.Sprintf("%s is back Ready", nodeName))
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("node %s did not come back Ready after the reboot: %w", nodeName, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// reportReboot sends a reboot progress update when a callback is set
+func reportReboot(opts RebootOptions, description string) {
+	if opts.ProgressCallback != nil {
+		opts.ProgressCallback(description)
+	}
+}
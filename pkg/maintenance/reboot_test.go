@@ -0,0 +1,145 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func rebootTestNode(cordoned bool) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+		Spec:       corev1.NodeSpec{Unschedulable: cordoned},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			NodeInfo:   corev1.NodeSystemInfo{BootID: "boot-1"},
+		},
+	}
+}
+
+func TestRebootNode(t *testing.T) {
+	tests := []struct {
+		name     string
+		cordoned bool
+		hook     config.RebootHook
+		reboots  bool
+		wantErr  error
+	}{
+		{
+			name:     "no hook",
+			cordoned: true,
+			wantErr:  ErrNoRebootHook,
+		},
+		{
+			name:    "not cordoned",
+			hook:    config.RebootHook{Command: "true"},
+			wantErr: ErrNodeNotCordoned,
+		},
+		{
+			name:     "url hook",
+			cordoned: true,
+			reboots:  true,
+		},
+		{
+			name:     "failing command",
+			cordoned: true,
+			hook:     config.RebootHook{Command: "echo no route to host; exit 255"},
+			wantErr:  errors.New("no route to host"),
+		},
+		{
+			name:     "node never comes back",
+			cordoned: true,
+			hook:     config.RebootHook{Command: "true"},
+			wantErr:  context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewClientset(rebootTestNode(tt.cordoned))
+			client := &k8s.Client{Clientset: clientset}
+
+			var requested string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				requested = body["node"]
+				// The host comes back from a new boot
+				node := rebootTestNode(true)
+				node.Status.NodeInfo.BootID = "boot-2"
+				_, _ = clientset.CoreV1().Nodes().Update(r.Context(), node, metav1.UpdateOptions{})
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer server.Close()
+
+			cfg := config.DefaultConfig()
+			cfg.Hooks.Reboot = tt.hook
+			if tt.reboots {
+				cfg.Hooks.Reboot.URL = server.URL
+			}
+			cfg.Hooks.Reboot.TimeoutSeconds = 5
+			cfg.Hooks.Reboot.ReadyTimeoutSeconds = 1
+
+			var steps []string
+			err := RebootNode(context.Background(), client, cfg, "worker-1", RebootOptions{
+				PollInterval:     10 * time.Millisecond,
+				ProgressCallback: func(description string) { steps = append(steps, description) },
+			})
+
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("RebootNode() error = %v", err)
+			case tt.wantErr == nil:
+				if requested != "worker-1" {
+					t.Errorf("reboot requested for %q, want worker-1", requested)
+				}
+				if last := steps[len(steps)-1]; last != "worker-1 is back Ready" {
+					t.Errorf("last step = %q, want the node back Ready", last)
+				}
+			case err == nil:
+				t.Fatalf("RebootNode() error = nil, want %v", tt.wantErr)
+			case !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()):
+				t.Errorf("RebootNode() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWaitForReboot_SeenDown(t *testing.T) {
+	// Without a boot ID the node counts as rebooted once it was seen NotReady
+	node := rebootTestNode(true)
+	node.Status.NodeInfo.BootID = ""
+	node.Status.Conditions[0].Status = corev1.ConditionUnknown
+	clientset := fake.NewClientset(node)
+	client := &k8s.Client{Clientset: clientset}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var steps []string
+	opts := RebootOptions{
+		PollInterval: 10 * time.Millisecond,
+		ProgressCallback: func(description string) {
+			steps = append(steps, description)
+			if description == "worker-1 is down" {
+				_, _ = clientset.CoreV1().Nodes().Update(ctx, rebootTestNode(true), metav1.UpdateOptions{})
+			}
+		},
+	}
+	if err := waitForReboot(ctx, client, "worker-1", "", opts); err != nil {
+		t.Fatalf("waitForReboot() error = %v", err)
+	}
+	if len(steps) != 2 || steps[1] != "worker-1 is back Ready" {
+		t.Errorf("steps = %v", steps)
+	}
+}
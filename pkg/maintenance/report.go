@@ -0,0 +1,248 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Report storage: one ConfigMap per node holds the latest maintenance report
+const (
+	reportConfigMapPrefix = "crook-report-"
+	reportDataKey         = "report.json"
+	reportLabelNode       = "crook.io/report-node"
+)
+
+// ErrNoReport is returned when no maintenance report has been recorded for a node
+var ErrNoReport = errors.New("no maintenance report found")
+
+// MaintenanceReport compares the cluster before 'crook down' with the cluster
+// after 'crook up' for one node
+type MaintenanceReport struct {
+	Node    string           `json:"node"`
+	Before  *ClusterSnapshot `json:"before,omitempty"`
+	After   *ClusterSnapshot `json:"after,omitempty"`
+	Changes []SnapshotChange `json:"changes,omitempty"`
+
+	// DownSignOff and UpSignOff are the reason and ticket given to each phase
+	DownSignOff *SignOff `json:"down_sign_off,omitempty"`
+	UpSignOff   *SignOff `json:"up_sign_off,omitempty"`
+
+	// DownOperator and UpOperator are who ran each phase
+	DownOperator string `json:"down_operator,omitempty"`
+	UpOperator   string `json:"up_operator,omitempty"`
+
+	// Error is the latest phase failure while the maintenance is open
+	Error string `json:"error,omitempty"`
+
+	// Workloads lists the namespace/name of the workloads the down phase
+	// planned for the node, diffed by the next maintenance (see DiffLastMaintenance)
+	Workloads []string `json:"workloads,omitempty"`
+
+	// Applied lists the changes crook itself made during the maintenance,
+	// in order, e.g. every replica count it scaled
+	Applied []k8s.Change `json:"applied,omitempty"`
+
+	// DeadNode marks a down phase run in dead-node mode, the emergency
+	// handling of a node that was already NotReady
+	DeadNode bool `json:"dead_node,omitempty"`
+
+	// NoOutScope is what the down phase set the noout flag on, cleared by
+	// the up phase
+	NoOutScope *NoOutScope `json:"noout_scope,omitempty"`
+}
+
+// Complete reports whether both snapshots have been recorded
+func (r *MaintenanceReport) Complete() bool {
+	return r.Before != nil && r.After != nil
+}
+
+// ReportConfigMapName returns the name of the ConfigMap holding a node's report
+func ReportConfigMapName(nodeName string) string {
+	return reportConfigMapPrefix + nodeName
+}
+
+// LoadReport returns the latest maintenance report for a node, or ErrNoReport
+func LoadReport(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) (*MaintenanceReport, error) {
+	cm, err := client.GetConfigMap(ctx, cfg.Namespace, ReportConfigMapName(nodeName))
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w for node %s", ErrNoReport, nodeName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := cm.Data[reportDataKey]
+	if !ok {
+		return nil, fmt.Errorf("%w for node %s", ErrNoReport, nodeName)
+	}
+	var report MaintenanceReport
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse maintenance report for node %s: %w", nodeName, err)
+	}
+	return &report, nil
+}
+
+// SaveReport stores a maintenance report in the node's report ConfigMap
+func SaveReport(ctx context.Context, client *k8s.Client, cfg config.Config, report *MaintenanceReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode maintenance report: %w", err)
+	}
+
+	return client.ApplyConfigMap(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ReportConfigMapName(report.Node),
+			Namespace: cfg.Namespace,
+			Labels: map[string]string{
+				k8s.JobLabelName: "crook",
+				reportLabelNode:  report.Node,
+			},
+		},
+		Data: map[string]string{reportDataKey: string(data)},
+	})
+}
+
+// RecordBeforeSnapshot starts a new maintenance report for the node. If the
+// previous report is still open (down without a matching up), its snapshot is
+// kept so the report covers the whole maintenance window. Best-effort: failures
+// are logged and never abort the phase.
+func RecordBeforeSnapshot(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) {
+	if existing, err := LoadReport(ctx, client, cfg, nodeName); err == nil && existing.Before != nil && existing.After == nil {
+		logger.Debug("keeping snapshot of open maintenance report", "node", nodeName, "taken", existing.Before.Time)
+		return
+	}
+
+	snapshot, err := CaptureSnapshot(ctx, client, cfg)
+	if err != nil {
+		logger.Warn("failed to capture pre-maintenance snapshot", "node", nodeName, "error", err)
+		return
+	}
+	if err := SaveReport(ctx, client, cfg, &MaintenanceReport{Node: nodeName, Before: snapshot}); err != nil {
+		logger.Warn("failed to save maintenance report", "node", nodeName, "error", err)
+	}
+}
+
+// RecordAfterSnapshot completes the node's maintenance report with the current
+// state and the diff against the pre-maintenance snapshot, and archives it in
+// the maintenance history. Best-effort.
+func RecordAfterSnapshot(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) {
+	report, err := LoadReport(ctx, client, cfg, nodeName)
+	if err != nil {
+		if !errors.Is(err, ErrNoReport) {
+			logger.Warn("failed to load maintenance report", "node", nodeName, "error", err)
+		}
+		report = &MaintenanceReport{Node: nodeName}
+	}
+
+	snapshot, err := CaptureSnapshot(ctx, client, cfg)
+	if err != nil {
+		logger.Warn("failed to capture post-maintenance snapshot", "node", nodeName, "error", err)
+		return
+	}
+	wasOpen := report.Before != nil && report.After == nil
+	report.After = snapshot
+	report.Error = ""
+	report.Changes = nil
+	if report.Before != nil {
+		report.Changes = DiffSnapshots(report.Before, report.After)
+	}
+
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		logger.Warn("failed to save maintenance report", "node", nodeName, "error", err)
+	}
+
+	// Re-running the up phase on a closed report must not archive it twice
+	if wasOpen {
+		archiveReport(ctx, client, cfg, report)
+	}
+}
+
+// recordAppliedChanges appends the changes a phase made to the node's open
+// maintenance report. Best-effort.
+func recordAppliedChanges(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, changes []k8s.Change) {
+	if len(changes) == 0 {
+		return
+	}
+	report, err := LoadReport(ctx, client, cfg, nodeName)
+	if err != nil {
+		if !errors.Is(err, ErrNoReport) {
+			logger.Warn("failed to load maintenance report", "node", nodeName, "error", err)
+		}
+		return
+	}
+	if report.Before == nil || report.After != nil {
+		return
+	}
+
+	report.Applied = append(report.Applied, changes...)
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		logger.Warn("failed to save maintenance report", "node", nodeName, "error", err)
+	}
+}
+
+// WriteReport renders a maintenance report as human-readable text
+func WriteReport(w io.Writer, report *MaintenanceReport) error {
+	timestamp := func(s *ClusterSnapshot) string {
+		if s == nil {
+			return "not recorded"
+		}
+		return s.Time.Local().Format(time.RFC3339)
+	}
+
+	_, _ = fmt.Fprintf(w, "Maintenance report for %s\n", report.Node)
+	_, _ = fmt.Fprintf(w, "Before (crook down): %s\n", timestamp(report.Before))
+	_, _ = fmt.Fprintf(w, "After (crook up):    %s\n", timestamp(report.After))
+	if report.DownSignOff != nil {
+		_, _ = fmt.Fprintf(w, "Down sign-off:       %s\n", report.DownSignOff)
+	}
+	if report.UpSignOff != nil {
+		_, _ = fmt.Fprintf(w, "Up sign-off:         %s\n", report.UpSignOff)
+	}
+	if report.DeadNode {
+		_, _ = fmt.Fprintln(w, "EMERGENCY: the down phase ran in dead-node mode; node-local checks were skipped")
+	}
+	_, _ = fmt.Fprintln(w)
+
+	if len(report.Applied) > 0 {
+		_, _ = fmt.Fprintf(w, "Applied by crook, %d change(s):\n", len(report.Applied))
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "TIME\tOBJECT\tFIELD\tBEFORE\tAFTER")
+		for _, c := range report.Applied {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", c.Time.Local().Format(time.TimeOnly), c.Object(), c.Field, c.Before, c.After)
+		}
+		_ = tw.Flush()
+		_, _ = fmt.Fprintln(w)
+	}
+
+	switch {
+	case report.Before == nil:
+		_, _ = fmt.Fprintln(w, "No pre-maintenance snapshot was recorded; nothing to compare.")
+		return nil
+	case report.After == nil:
+		_, _ = fmt.Fprintln(w, "Maintenance is still in progress; run 'crook up' to complete the report.")
+		return nil
+	case len(report.Changes) == 0:
+		_, _ = fmt.Fprintln(w, "No differences - the cluster returned to its pre-maintenance state.")
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "%d change(s):\n", len(report.Changes))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "KIND\tNAME\tBEFORE\tAFTER")
+	for _, c := range report.Changes {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Kind, c.Name, c.Before, c.After)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,135 @@
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSaveAndLoadReport(t *testing.T) {
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	cfg := config.DefaultConfig()
+
+	if _, err := LoadReport(ctx, client, cfg, "worker-1"); !errors.Is(err, ErrNoReport) {
+		t.Fatalf("LoadReport() error = %v, want ErrNoReport", err)
+	}
+
+	report := &MaintenanceReport{
+		Node:   "worker-1",
+		Before: &ClusterSnapshot{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Health: "HEALTH_OK"},
+	}
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+
+	loaded, err := LoadReport(ctx, client, cfg, "worker-1")
+	if err != nil {
+		t.Fatalf("LoadReport() error = %v", err)
+	}
+	if loaded.Node != "worker-1" || loaded.Before == nil || loaded.Before.Health != "HEALTH_OK" {
+		t.Errorf("loaded report = %+v, want saved report", loaded)
+	}
+	if loaded.Complete() {
+		t.Error("report without after snapshot should not be complete")
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	snapshot := &ClusterSnapshot{Time: time.Now()}
+
+	tests := []struct {
+		name   string
+		report *MaintenanceReport
+		want   []string
+	}{
+		{
+			name:   "in progress",
+			report: &MaintenanceReport{Node: "worker-1", Before: snapshot},
+			want:   []string{"Maintenance report for worker-1", "still in progress"},
+		},
+		{
+			name:   "no before snapshot",
+			report: &MaintenanceReport{Node: "worker-1", After: snapshot},
+			want:   []string{"not recorded", "nothing to compare"},
+		},
+		{
+			name:   "no differences",
+			report: &MaintenanceReport{Node: "worker-1", Before: snapshot, After: snapshot},
+			want:   []string{"No differences"},
+		},
+		{
+			name: "changes",
+			report: &MaintenanceReport{
+				Node:    "worker-1",
+				Before:  snapshot,
+				After:   snapshot,
+				Changes: []SnapshotChange{{Kind: "flag", Name: "noout", Before: "unset", After: "set"}},
+			},
+			want: []string{"1 change(s)", "KIND", "noout", "unset", "set"},
+		},
+		{
+			name: "applied by crook",
+			report: &MaintenanceReport{
+				Node:   "worker-1",
+				Before: snapshot,
+				Applied: []k8s.Change{{
+					Time: snapshot.Time, Kind: k8s.WorkloadKindDeployment, Namespace: "rook-ceph", Name: "rook-ceph-osd-3",
+					Field: "spec.replicas", Before: "1", After: "0",
+				}},
+			},
+			want: []string{"Applied by crook, 1 change(s)", "Deployment rook-ceph/rook-ceph-osd-3", "spec.replicas", "still in progress"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteReport(&buf, tt.report); err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRecordAppliedChanges(t *testing.T) {
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	cfg := config.DefaultConfig()
+	change := k8s.Change{Kind: k8s.WorkloadKindDeployment, Namespace: "rook-ceph", Name: "rook-ceph-osd-3", Field: "spec.replicas", Before: "1", After: "0"}
+
+	// Without an open report nothing is recorded
+	recordAppliedChanges(ctx, client, cfg, "worker-1", []k8s.Change{change})
+	if _, err := LoadReport(ctx, client, cfg, "worker-1"); !errors.Is(err, ErrNoReport) {
+		t.Fatalf("recordAppliedChanges() should not create a report, LoadReport() error = %v", err)
+	}
+
+	report := &MaintenanceReport{Node: "worker-1", Before: &ClusterSnapshot{Time: time.Now()}}
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+	recordAppliedChanges(ctx, client, cfg, "worker-1", []k8s.Change{change})
+	back := change
+	back.Before, back.After = "0", "1"
+	recordAppliedChanges(ctx, client, cfg, "worker-1", []k8s.Change{back})
+
+	loaded, err := LoadReport(ctx, client, cfg, "worker-1")
+	if err != nil {
+		t.Fatalf("LoadReport() error = %v", err)
+	}
+	if len(loaded.Applied) != 2 || loaded.Applied[0] != change || loaded.Applied[1] != back {
+		t.Errorf("Applied = %+v, want both changes in order", loaded.Applied)
+	}
+}
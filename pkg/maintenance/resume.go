@@ -0,0 +1,56 @@
+package maintenance
+
+import "slices"
+
+// downSteps are the steps of the down phase a failed run can resume from,
+// in order, named after the progress stage each reports
+var downSteps = []string{"pre-flight", "cordon", "noout", "operator", "discover", "scale-down", drainStage}
+
+// downStepAliases maps the other progress stages of the down phase to the
+// step reporting them
+var downStepAliases = map[string]string{
+	preflightCheckStage: preflightStage,
+	"skip":              "scale-down",
+	drainEvictingStage:  drainStage,
+	drainBlockedStage:   drainStage,
+	drainEvictedStage:   drainStage,
+	forceDeleteStage:    drainStage,
+}
+
+// upSteps are the steps of the up phase a failed run can resume from
+var upSteps = []string{"pre-flight", "discover", "uncordon", "scale-up", "operator", "unset-noout", smokeTestStage}
+
+// upStepAliases maps the other progress stages of the up phase to the step
+// reporting them
+var upStepAliases = map[string]string{
+	preflightCheckStage: preflightStage,
+	"skip":              "scale-up",
+	"quorum":            "scale-up",
+}
+
+// resumePlan decides which steps a resumed run repeats: the step that
+// failed and those after it. The steps before it already changed the
+// cluster and are not redone.
+type resumePlan struct {
+	steps []string
+	from  int
+}
+
+// newResumePlan resumes from the step reporting the progress stage from; an
+// empty or unknown stage runs every step
+func newResumePlan(steps []string, aliases map[string]string, from string) resumePlan {
+	if step, ok := aliases[from]; ok {
+		from = step
+	}
+	return resumePlan{steps: steps, from: max(slices.Index(steps, from), 0)}
+}
+
+// runs reports whether the run executes step
+func (r resumePlan) runs(step string) bool {
+	return slices.Index(r.steps, step) >= r.from
+}
+
+// resumed reports whether the run skips any step
+func (r resumePlan) resumed() bool {
+	return r.from > 0
+}
@@ -0,0 +1,59 @@
+package maintenance
+
+import "testing"
+
+func TestResumePlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    []string
+		aliases  map[string]string
+		from     string
+		wantRuns map[string]bool
+	}{
+		{
+			name:     "full run",
+			steps:    downSteps,
+			aliases:  downStepAliases,
+			wantRuns: map[string]bool{"pre-flight": true, "cordon": true, "scale-down": true},
+		},
+		{
+			name:     "down resumes at scale-down",
+			steps:    downSteps,
+			aliases:  downStepAliases,
+			from:     "scale-down",
+			wantRuns: map[string]bool{"pre-flight": false, "cordon": false, "operator": false, "scale-down": true, drainStage: true},
+		},
+		{
+			name:     "drain progress resumes the drain",
+			steps:    downSteps,
+			aliases:  downStepAliases,
+			from:     drainBlockedStage,
+			wantRuns: map[string]bool{"scale-down": false, drainStage: true},
+		},
+		{
+			name:     "quorum wait resumes the scale-up",
+			steps:    upSteps,
+			aliases:  upStepAliases,
+			from:     "quorum",
+			wantRuns: map[string]bool{"pre-flight": false, "uncordon": false, "scale-up": true, "operator": true},
+		},
+		{
+			name:     "unknown stage runs everything",
+			steps:    upSteps,
+			aliases:  upStepAliases,
+			from:     "bogus",
+			wantRuns: map[string]bool{"pre-flight": true, "unset-noout": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := newResumePlan(tt.steps, tt.aliases, tt.from)
+			for step, want := range tt.wantRuns {
+				if got := plan.runs(step); got != want {
+					t.Errorf("runs(%q) = %v, want %v", step, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// RookSettingsConflict describes a CephCluster setting that interacts badly with
+// crook's manual maintenance approach (operator scaled down, cluster-wide noout).
+type RookSettingsConflict struct {
+	// Cluster is the CephCluster in namespace/name form
+	Cluster string
+
+	// Setting is the CR field path (e.g. spec.disruptionManagement.managePodBudgets)
+	Setting string
+
+	// Value is the configured value
+	Value string
+
+	// Reason explains why the setting conflicts with crook
+	Reason string
+}
+
+// String returns a single-line description pointing at the conflicting config
+func (c RookSettingsConflict) String() string {
+	return fmt.Sprintf("CephCluster %s: %s=%s - %s", c.Cluster, c.Setting, c.Value, c.Reason)
+}
+
+// CheckRookSettings fetches CephCluster resources and reports settings that conflict
+// with crook's maintenance flow. An error means the check could not be performed
+// (e.g. CRD missing or RBAC denied); callers should treat it as best-effort.
+func CheckRookSettings(ctx context.Context, client *k8s.Client, cfg config.Config) ([]RookSettingsConflict, error) {
+	clusters, err := client.ListCephClusters(ctx, cfg.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CephCluster settings: %w", err)
+	}
+	clusters, err = SelectCephClusters(clusters, cfg.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	return DetectRookSettingsConflicts(clusters), nil
+}
+
+// SelectCephClusters returns the CephClusters crook operates on: the one named
+// selected, or all of them when selected is empty
+func SelectCephClusters(clusters []k8s.CephClusterSettings, selected string) ([]k8s.CephClusterSettings, error) {
+	if selected == "" {
+		return clusters, nil
+	}
+	names := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		if cluster.Name == selected {
+			return []k8s.CephClusterSettings{cluster}, nil
+		}
+		names = append(names, cluster.Name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("CephCluster %q not found: no CephClusters in the namespace", selected)
+	}
+	return nil, fmt.Errorf("CephCluster %q not found (available: %s)", selected, strings.Join(names, ", "))
+}
+
+// validateCephClusterSelection checks that the configured CephCluster exists.
+// Without a selection it only warns when several CephClusters share the
+// namespace, since their daemons are then all treated as one cluster. It is
+// skipped when the CephCluster CRD cannot be read.
+func validateCephClusterSelection(ctx context.Context, client *k8s.Client, cfg config.Config) (*ValidationResult, string) {
+	clusters, err := client.ListCephClusters(ctx, cfg.Namespace)
+	if err != nil {
+		logger.Debug("skipping CephCluster selection check", "error", err)
+		return nil, ""
+	}
+
+	if cfg.Cluster == "" {
+		if len(clusters) > 1 {
+			return nil, fmt.Sprintf(
+				"%d CephClusters in namespace %s - set cluster (or --cluster) to operate on one of them",
+				len(clusters), cfg.Namespace)
+		}
+		return nil, ""
+	}
+
+	if _, err := SelectCephClusters(clusters, cfg.Cluster); err != nil {
+		return &ValidationResult{Check: "CephCluster", Error: err, Message: fmt.Sprintf("CephCluster %s not found", cfg.Cluster)}, ""
+	}
+	return &ValidationResult{Check: "CephCluster", Passed: true, Message: fmt.Sprintf("CephCluster %s exists", cfg.Cluster)}, ""
+}
+
+// DetectRookSettingsConflicts inspects CephCluster settings and returns all conflicts found.
+//
+// Conflicts detected:
+//   - managePodBudgets: Rook's own drain handling sets noout per failure domain and
+//     manages OSD PDBs, which races with crook's cluster-wide noout handling
+//   - skipUpgradeChecks / continueUpgradeAfterChecksEvenIfNotHealthy: when crook scales
+//     the operator back up, it may restart daemons without waiting for a healthy cluster
+//   - osd_memory_target override: restored OSDs pick up the override, so node memory
+//     should be verified before bringing the node back
+func DetectRookSettingsConflicts(clusters []k8s.CephClusterSettings) []RookSettingsConflict {
+	var conflicts []RookSettingsConflict
+
+	for _, cluster := range clusters {
+		name := fmt.Sprintf("%s/%s", cluster.Namespace, cluster.Name)
+
+		if cluster.ManagePodBudgets {
+			reason := "Rook manages OSD PDBs and sets noout on drained failure domains, which can race with crook's noout handling"
+			if cluster.OSDMaintenanceTimeout > 0 {
+				reason = fmt.Sprintf("%s (Rook clears it after osdMaintenanceTimeout=%dm)", reason, cluster.OSDMaintenanceTimeout)
+			}
+			conflicts = append(conflicts, RookSettingsConflict{
+				Cluster: name,
+				Setting: "spec.disruptionManagement.managePodBudgets",
+				Value:   "true",
+				Reason:  reason,
+			})
+		}
+
+		if cluster.SkipUpgradeChecks {
+			conflicts = append(conflicts, RookSettingsConflict{
+				Cluster: name,
+				Setting: "spec.skipUpgradeChecks",
+				Value:   "true",
+				Reason:  "the operator may restart daemons without health checks once crook scales it back up",
+			})
+		}
+
+		if cluster.ContinueUpgradeAfterChecksEvenIfNotHealthy {
+			conflicts = append(conflicts, RookSettingsConflict{
+				Cluster: name,
+				Setting: "spec.continueUpgradeAfterChecksEvenIfNotHealthy",
+				Value:   "true",
+				Reason:  "the operator may continue reconciling while the cluster is degraded by maintenance",
+			})
+		}
+
+		if target, ok := cluster.OSDConfig["osd_memory_target"]; ok {
+			conflicts = append(conflicts, RookSettingsConflict{
+				Cluster: name,
+				Setting: "spec.cephConfig.osd.osd_memory_target",
+				Value:   target,
+				Reason:  "restored OSDs apply this override; verify node memory before bringing the node up",
+			})
+		}
+	}
+
+	return conflicts
+}
@@ -0,0 +1,131 @@
+package maintenance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func TestDetectRookSettingsConflicts(t *testing.T) {
+	tests := []struct {
+		name         string
+		clusters     []k8s.CephClusterSettings
+		wantSettings []string
+	}{
+		{
+			name:         "no clusters",
+			clusters:     nil,
+			wantSettings: nil,
+		},
+		{
+			name: "default settings - no conflicts",
+			clusters: []k8s.CephClusterSettings{
+				{Name: "rook-ceph", Namespace: "rook-ceph"},
+			},
+			wantSettings: nil,
+		},
+		{
+			name: "managed pod budgets",
+			clusters: []k8s.CephClusterSettings{
+				{Name: "rook-ceph", Namespace: "rook-ceph", ManagePodBudgets: true, OSDMaintenanceTimeout: 30},
+			},
+			wantSettings: []string{"spec.disruptionManagement.managePodBudgets"},
+		},
+		{
+			name: "all conflicts",
+			clusters: []k8s.CephClusterSettings{
+				{
+					Name:              "rook-ceph",
+					Namespace:         "rook-ceph",
+					ManagePodBudgets:  true,
+					SkipUpgradeChecks: true,
+					ContinueUpgradeAfterChecksEvenIfNotHealthy: true,
+					OSDConfig: map[string]string{"osd_memory_target": "4294967296"},
+				},
+			},
+			wantSettings: []string{
+				"spec.disruptionManagement.managePodBudgets",
+				"spec.skipUpgradeChecks",
+				"spec.continueUpgradeAfterChecksEvenIfNotHealthy",
+				"spec.cephConfig.osd.osd_memory_target",
+			},
+		},
+		{
+			name: "unrelated osd config is ignored",
+			clusters: []k8s.CephClusterSettings{
+				{Name: "rook-ceph", Namespace: "rook-ceph", OSDConfig: map[string]string{"osd_max_backfills": "1"}},
+			},
+			wantSettings: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts := DetectRookSettingsConflicts(tt.clusters)
+			if len(conflicts) != len(tt.wantSettings) {
+				t.Fatalf("got %d conflicts, want %d: %v", len(conflicts), len(tt.wantSettings), conflicts)
+			}
+			for i, want := range tt.wantSettings {
+				if conflicts[i].Setting != want {
+					t.Errorf("conflict[%d].Setting = %q, want %q", i, conflicts[i].Setting, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRookSettingsConflictString(t *testing.T) {
+	conflict := RookSettingsConflict{
+		Cluster: "rook-ceph/my-cluster",
+		Setting: "spec.skipUpgradeChecks",
+		Value:   "true",
+		Reason:  "reason",
+	}
+
+	got := conflict.String()
+	for _, want := range []string{"rook-ceph/my-cluster", "spec.skipUpgradeChecks=true", "reason"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestSelectCephClusters(t *testing.T) {
+	clusters := []k8s.CephClusterSettings{{Name: "east"}, {Name: "west"}}
+
+	tests := []struct {
+		name     string
+		clusters []k8s.CephClusterSettings
+		selected string
+		want     []string
+		wantErr  string
+	}{
+		{name: "no selection keeps all", clusters: clusters, want: []string{"east", "west"}},
+		{name: "selected by name", clusters: clusters, selected: "west", want: []string{"west"}},
+		{name: "unknown name", clusters: clusters, selected: "north", wantErr: "available: east, west"},
+		{name: "no clusters", selected: "east", wantErr: "no CephClusters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectCephClusters(tt.clusters, tt.selected)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SelectCephClusters() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectCephClusters() error = %v", err)
+			}
+			names := make([]string, 0, len(got))
+			for _, cluster := range got {
+				names = append(names, cluster.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("SelectCephClusters() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
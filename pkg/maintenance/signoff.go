@@ -0,0 +1,108 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// SignOff links a maintenance to its change request for auditing
+// (crook down/up --reason/--ticket)
+type SignOff struct {
+	// Reason explains why the maintenance is done
+	Reason string `json:"reason,omitempty"`
+
+	// Ticket is the change request or ticket ID, e.g. CHG-1234
+	Ticket string `json:"ticket,omitempty"`
+}
+
+// IsZero reports whether neither a reason nor a ticket was given
+func (s SignOff) IsZero() bool {
+	return s.Reason == "" && s.Ticket == ""
+}
+
+// String returns e.g. "CHG-1234: replace failed disk", or whichever of the
+// ticket and reason is set
+func (s SignOff) String() string {
+	switch {
+	case s.Ticket != "" && s.Reason != "":
+		return s.Ticket + ": " + s.Reason
+	case s.Ticket != "":
+		return s.Ticket
+	default:
+		return s.Reason
+	}
+}
+
+// annotateSignOff records the sign-off on the node so anyone looking at the
+// cordoned node sees why. Best-effort.
+func annotateSignOff(ctx context.Context, client *k8s.Client, nodeName string, signOff SignOff) {
+	if signOff.IsZero() {
+		return
+	}
+
+	annotations := make(map[string]*string)
+	if signOff.Reason != "" {
+		annotations[k8s.ReasonAnnotation] = &signOff.Reason
+	}
+	if signOff.Ticket != "" {
+		annotations[k8s.TicketAnnotation] = &signOff.Ticket
+	}
+	if err := client.SetNodeAnnotations(ctx, nodeName, annotations); err != nil {
+		logger.Warn("failed to record sign-off on node", "node", nodeName, "error", err)
+	}
+}
+
+// clearSignOff removes the sign-off annotations once the node is back in
+// service; the maintenance report keeps the record. Best-effort.
+func clearSignOff(ctx context.Context, client *k8s.Client, nodeName string) {
+	err := client.SetNodeAnnotations(ctx, nodeName, map[string]*string{
+		k8s.ReasonAnnotation: nil,
+		k8s.TicketAnnotation: nil,
+	})
+	if err != nil {
+		logger.Warn("failed to remove sign-off from node", "node", nodeName, "error", err)
+	}
+}
+
+// recordReportRun stores who ran a phase and its sign-off in the node's
+// maintenance report; an empty operator defaults to CurrentOperator.
+// Best-effort.
+func recordReportRun(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName, phase, operator string, signOff SignOff) {
+	if operator == "" {
+		operator = CurrentOperator()
+	}
+
+	report, err := LoadReport(ctx, client, cfg, nodeName)
+	if err != nil {
+		if !errors.Is(err, ErrNoReport) {
+			logger.Warn("failed to load maintenance report", "node", nodeName, "error", err)
+		}
+		return
+	}
+
+	var signOffPtr *SignOff
+	if !signOff.IsZero() {
+		signOffPtr = &signOff
+	}
+	switch phase {
+	case ScalePhaseDown:
+		report.DownOperator = operator
+		if signOffPtr != nil {
+			report.DownSignOff = signOffPtr
+		}
+	case ScalePhaseUp:
+		report.UpOperator = operator
+		if signOffPtr != nil {
+			report.UpSignOff = signOffPtr
+		}
+	default:
+		return
+	}
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		logger.Warn("failed to save maintenance report", "node", nodeName, "error", err)
+	}
+}
@@ -0,0 +1,106 @@
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSignOff_String(t *testing.T) {
+	tests := []struct {
+		name    string
+		signOff SignOff
+		want    string
+	}{
+		{name: "empty", want: ""},
+		{name: "reason only", signOff: SignOff{Reason: "replace failed disk"}, want: "replace failed disk"},
+		{name: "ticket only", signOff: SignOff{Ticket: "CHG-1234"}, want: "CHG-1234"},
+		{name: "both", signOff: SignOff{Reason: "replace failed disk", Ticket: "CHG-1234"}, want: "CHG-1234: replace failed disk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.signOff.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			if tt.signOff.IsZero() != (tt.want == "") {
+				t.Errorf("IsZero() = %v for %+v", tt.signOff.IsZero(), tt.signOff)
+			}
+		})
+	}
+}
+
+func TestAnnotateAndClearSignOff(t *testing.T) {
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Annotations: map[string]string{"other": "kept"}},
+	})}
+
+	annotateSignOff(ctx, client, "worker-1", SignOff{Reason: "replace failed disk", Ticket: "CHG-1234"})
+	node, err := client.GetNode(ctx, "worker-1")
+	if err != nil {
+		t.Fatalf("GetNode() error = %v", err)
+	}
+	if node.Annotations[k8s.ReasonAnnotation] != "replace failed disk" || node.Annotations[k8s.TicketAnnotation] != "CHG-1234" {
+		t.Errorf("annotations = %v, want reason and ticket", node.Annotations)
+	}
+
+	clearSignOff(ctx, client, "worker-1")
+	node, err = client.GetNode(ctx, "worker-1")
+	if err != nil {
+		t.Fatalf("GetNode() error = %v", err)
+	}
+	if _, ok := node.Annotations[k8s.ReasonAnnotation]; ok {
+		t.Errorf("reason annotation should be removed, got %v", node.Annotations)
+	}
+	if _, ok := node.Annotations[k8s.TicketAnnotation]; ok {
+		t.Errorf("ticket annotation should be removed, got %v", node.Annotations)
+	}
+	if node.Annotations["other"] != "kept" {
+		t.Errorf("unrelated annotations should be kept, got %v", node.Annotations)
+	}
+}
+
+func TestRecordReportRun(t *testing.T) {
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	cfg := config.DefaultConfig()
+
+	report := &MaintenanceReport{Node: "worker-1", Before: &ClusterSnapshot{Time: time.Now()}}
+	if err := SaveReport(ctx, client, cfg, report); err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+
+	recordReportRun(ctx, client, cfg, "worker-1", ScalePhaseDown, "alice", SignOff{Ticket: "CHG-1234", Reason: "replace failed disk"})
+	recordReportRun(ctx, client, cfg, "worker-1", ScalePhaseUp, "bob", SignOff{})
+
+	loaded, err := LoadReport(ctx, client, cfg, "worker-1")
+	if err != nil {
+		t.Fatalf("LoadReport() error = %v", err)
+	}
+	if loaded.DownSignOff == nil || loaded.DownSignOff.Ticket != "CHG-1234" {
+		t.Errorf("DownSignOff = %+v, want ticket CHG-1234", loaded.DownSignOff)
+	}
+	if loaded.UpSignOff != nil {
+		t.Errorf("UpSignOff = %+v, want nil for an empty sign-off", loaded.UpSignOff)
+	}
+	if loaded.DownOperator != "alice" || loaded.UpOperator != "bob" {
+		t.Errorf("operators = %q/%q, want alice/bob", loaded.DownOperator, loaded.UpOperator)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, loaded); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Down sign-off:       CHG-1234: replace failed disk") {
+		t.Errorf("report should show the sign-off, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,103 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// smokeTestStage is the progress stage of the optional storage smoke test at
+// the end of the up phase
+const smokeTestStage = "smoke-test"
+
+// SmokeTestProgressStages lists the progress stages of the smoke test stage
+var SmokeTestProgressStages = []string{smokeTestStage}
+
+// smokeTestPollInterval is how often a smoke test pod is polled when the
+// phase sets no poll interval
+const smokeTestPollInterval = 2 * time.Second
+
+// ErrSmokeTestFailed is returned when a storage class fails the smoke test;
+// the node is back in service, but its maintenance is left open
+var ErrSmokeTestFailed = errors.New("storage smoke test failed")
+
+// smokeTestIfEnabled runs the smoke test stage when smoke-test.enabled is set
+func smokeTestIfEnabled(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, resume resumePlan, opts UpPhaseOptions) error {
+	if !cfg.SmokeTest.Enabled || !resume.runs(smokeTestStage) {
+		return nil
+	}
+	return runSmokeTests(ctx, client, cfg, nodeName, opts)
+}
+
+// runSmokeTests checks each configured storage class end-to-end from the
+// node: a small PVC is provisioned, mounted by a pod on the node that writes
+// and reads back a file, and deleted again. Every class is tested, and the
+// failures are reported together.
+func runSmokeTests(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, opts UpPhaseOptions) error {
+	test := cfg.SmokeTest
+	namespace := test.Namespace
+	if namespace == "" {
+		namespace = cfg.Namespace
+	}
+	size, err := resource.ParseQuantity(test.Size)
+	if err != nil {
+		return fmt.Errorf("invalid smoke-test.size %q: %w", test.Size, err)
+	}
+	pollInterval := opts.WaitOptions.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = smokeTestPollInterval
+	}
+
+	var failures []string
+	for _, class := range test.StorageClasses {
+		sendUpProgress(opts.ProgressCallback, smokeTestStage, fmt.Sprintf("Smoke testing storage class %s on %s", class, nodeName), "")
+
+		started := time.Now()
+		err := runSmokeTest(ctx, client, k8s.SmokeTestOptions{
+			Namespace:    namespace,
+			Name:         "crook-smoke-test-" + rand.String(5),
+			StorageClass: class,
+			NodeName:     nodeName,
+			Image:        test.Image,
+			Size:         size,
+		}, time.Duration(test.TimeoutSeconds)*time.Second, pollInterval)
+		if err != nil {
+			logger.Warn("storage smoke test failed", "node", nodeName, "storageClass", class, "error", err)
+			failures = append(failures, fmt.Sprintf("%s: %v", class, err))
+			continue
+		}
+		sendUpProgress(opts.ProgressCallback, smokeTestStage,
+			fmt.Sprintf("Storage class %s: wrote and read back data in %s", class, time.Since(started).Round(time.Second)), "")
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%w: %s", ErrSmokeTestFailed, strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// runSmokeTest runs the smoke test of one storage class within timeout and
+// always cleans up after it
+func runSmokeTest(ctx context.Context, client *k8s.Client, test k8s.SmokeTestOptions, timeout, pollInterval time.Duration) error {
+	defer func() {
+		// Clean up even when the phase context was cancelled
+		if err := client.DeleteSmokeTest(context.WithoutCancel(ctx), test.Namespace, test.Name); err != nil {
+			logger.Warn("failed to clean up smoke test", "namespace", test.Namespace, "name", test.Name, "error", err)
+		}
+	}()
+
+	testCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := client.CreateSmokeTest(testCtx, test); err != nil {
+		return err
+	}
+	return client.WaitForSmokeTest(testCtx, test.Namespace, test.Name, pollInterval)
+}
@@ -0,0 +1,112 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRunSmokeTests(t *testing.T) {
+	clientset := fake.NewClientset()
+	// Pods of the "broken" class fail, the others succeed
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+		pvc, err := clientset.Tracker().Get(corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims"), pod.Namespace, pod.Name)
+		if err != nil {
+			return true, nil, err
+		}
+		pod.Status.Phase = corev1.PodSucceeded
+		if *pvc.(*corev1.PersistentVolumeClaim).Spec.StorageClassName == "broken" {
+			pod.Status.Phase = corev1.PodFailed
+			pod.Status.Reason = "Evicted"
+		}
+		return false, nil, nil
+	})
+	client := &k8s.Client{Clientset: clientset}
+
+	cfg := config.DefaultConfig()
+	cfg.SmokeTest.Enabled = true
+	cfg.SmokeTest.StorageClasses = []string{"ceph-block", "broken", "cephfs"}
+	cfg.SmokeTest.Namespace = "smoke"
+
+	var descriptions []string
+	opts := UpPhaseOptions{
+		WaitOptions:      WaitOptions{PollInterval: time.Millisecond},
+		ProgressCallback: func(p UpPhaseProgress) { descriptions = append(descriptions, p.Description) },
+	}
+	err := runSmokeTests(context.Background(), client, cfg, "worker-1", opts)
+	if !errors.Is(err, ErrSmokeTestFailed) || !strings.Contains(err.Error(), "broken: ") {
+		t.Fatalf("runSmokeTests() error = %v, want the broken class to fail", err)
+	}
+	if strings.Contains(err.Error(), "ceph-block") || strings.Contains(err.Error(), "cephfs") {
+		t.Errorf("runSmokeTests() error = %v, want only the broken class", err)
+	}
+
+	passed := 0
+	for _, d := range descriptions {
+		if strings.Contains(d, "wrote and read back data") {
+			passed++
+		}
+	}
+	if passed != 2 {
+		t.Errorf("progress = %q, want 2 passed classes", descriptions)
+	}
+
+	// Every class is cleaned up, whatever its outcome
+	pvcs, _ := clientset.CoreV1().PersistentVolumeClaims("smoke").List(context.Background(), metav1.ListOptions{})
+	pods, _ := clientset.CoreV1().Pods("smoke").List(context.Background(), metav1.ListOptions{})
+	if len(pvcs.Items) != 0 || len(pods.Items) != 0 {
+		t.Errorf("left %d PVC(s) and %d pod(s) behind", len(pvcs.Items), len(pods.Items))
+	}
+}
+
+func TestSmokeTestIfEnabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		resumeFrom string
+		wantRun    bool
+	}{
+		{name: "disabled"},
+		{name: "enabled", enabled: true, wantRun: true},
+		{name: "resumed at the smoke test", enabled: true, resumeFrom: smokeTestStage, wantRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewClientset()
+			clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				action.(k8stesting.CreateAction).GetObject().(*corev1.Pod).Status.Phase = corev1.PodSucceeded
+				return false, nil, nil
+			})
+			client := &k8s.Client{Clientset: clientset}
+			cfg := config.DefaultConfig()
+			cfg.SmokeTest.Enabled = tt.enabled
+			cfg.SmokeTest.StorageClasses = []string{"ceph-block"}
+
+			resume := newResumePlan(upSteps, upStepAliases, tt.resumeFrom)
+			opts := UpPhaseOptions{WaitOptions: WaitOptions{PollInterval: time.Millisecond}}
+			if err := smokeTestIfEnabled(context.Background(), client, cfg, "worker-1", resume, opts); err != nil {
+				t.Fatalf("smokeTestIfEnabled() error = %v", err)
+			}
+
+			ran := false
+			for _, action := range clientset.Actions() {
+				ran = ran || action.Matches("create", "persistentvolumeclaims")
+			}
+			if ran != tt.wantRun {
+				t.Errorf("smoke test ran = %v, want %v", ran, tt.wantRun)
+			}
+		})
+	}
+}
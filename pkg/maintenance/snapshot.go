@@ -0,0 +1,215 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// ClusterSnapshot is the cluster state relevant to node maintenance at one point in time
+type ClusterSnapshot struct {
+	Time        time.Time            `json:"time"`
+	Health      string               `json:"health,omitempty"`
+	Flags       *k8s.CephFlags       `json:"flags,omitempty"`
+	Nodes       []SnapshotNode       `json:"nodes"`
+	Deployments []SnapshotDeployment `json:"deployments"`
+	OSDs        []SnapshotOSD        `json:"osds,omitempty"`
+}
+
+// SnapshotNode is the state of a node hosting Ceph pods
+type SnapshotNode struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Schedulable bool   `json:"schedulable"`
+}
+
+// SnapshotDeployment is the replica state of a Ceph deployment
+type SnapshotDeployment struct {
+	Namespace     string `json:"namespace"`
+	Name          string `json:"name"`
+	Replicas      int32  `json:"replicas"`
+	ReadyReplicas int32  `json:"ready_replicas"`
+}
+
+// SnapshotOSD is the state of an OSD in the CRUSH tree
+type SnapshotOSD struct {
+	ID     int    `json:"id"`
+	Host   string `json:"host"`
+	Status string `json:"status"`
+	InOut  string `json:"in_out"`
+}
+
+// SnapshotChange is a single difference between two snapshots
+type SnapshotChange struct {
+	// Kind is the kind of object that changed (health, flag, node, deployment, osd)
+	Kind string `json:"kind"`
+
+	// Name identifies the object within its kind
+	Name string `json:"name"`
+
+	// Before and After describe the state; "absent" when the object did not exist
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// absent describes an object missing from one side of a diff
+const absent = "absent"
+
+// CaptureSnapshot records the current state of Ceph nodes, deployments, OSDs,
+// flags and health. Kubernetes state is required; Ceph state is best-effort
+// and left empty when the toolbox cannot be reached.
+func CaptureSnapshot(ctx context.Context, client *k8s.Client, cfg config.Config) (*ClusterSnapshot, error) {
+	snapshot := &ClusterSnapshot{Time: time.Now()}
+
+	nodes, err := client.ListNodesWithCephPods(ctx, cfg.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot nodes: %w", err)
+	}
+	for _, n := range nodes {
+		snapshot.Nodes = append(snapshot.Nodes, SnapshotNode{Name: n.Name, Status: n.Status, Schedulable: n.Schedulable})
+	}
+
+	deployments, err := client.ListCephDeployments(ctx, cfg.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot deployments: %w", err)
+	}
+	for _, d := range deployments {
+		snapshot.Deployments = append(snapshot.Deployments, SnapshotDeployment{
+			Namespace:     d.Namespace,
+			Name:          d.Name,
+			Replicas:      d.DesiredReplicas,
+			ReadyReplicas: d.ReadyReplicas,
+		})
+	}
+
+	if status, statusErr := client.GetCephStatus(ctx, cfg.Namespace); statusErr == nil {
+		snapshot.Health = status.Health.Status
+	} else {
+		logger.Debug("snapshot without ceph health", "error", statusErr)
+	}
+	if flags, flagsErr := client.GetCephFlags(ctx, cfg.Namespace); flagsErr == nil {
+		snapshot.Flags = flags
+	} else {
+		logger.Debug("snapshot without ceph flags", "error", flagsErr)
+	}
+	if osds, osdErr := client.GetOSDInfoList(ctx, cfg.Namespace); osdErr == nil {
+		for _, o := range osds {
+			snapshot.OSDs = append(snapshot.OSDs, SnapshotOSD{ID: o.ID, Host: o.Hostname, Status: o.Status, InOut: o.InOut})
+		}
+	} else {
+		logger.Debug("snapshot without ceph osds", "error", osdErr)
+	}
+
+	return snapshot, nil
+}
+
+// DiffSnapshots lists what changed between two snapshots, ordered by kind and name.
+// Ceph sections missing from either snapshot are not compared.
+func DiffSnapshots(before, after *ClusterSnapshot) []SnapshotChange {
+	var changes []SnapshotChange
+
+	if before.Health != "" && after.Health != "" && before.Health != after.Health {
+		changes = append(changes, SnapshotChange{Kind: "health", Name: "ceph", Before: before.Health, After: after.Health})
+	}
+
+	if before.Flags != nil && after.Flags != nil {
+		beforeFlags, afterFlags := flagStates(before.Flags), flagStates(after.Flags)
+		changes = append(changes, diffStates("flag", beforeFlags, afterFlags)...)
+	}
+
+	nodeStates := func(nodes []SnapshotNode) map[string]string {
+		states := make(map[string]string, len(nodes))
+		for _, n := range nodes {
+			scheduling := "schedulable"
+			if !n.Schedulable {
+				scheduling = "cordoned"
+			}
+			states[n.Name] = n.Status + ", " + scheduling
+		}
+		return states
+	}
+	changes = append(changes, diffStates("node", nodeStates(before.Nodes), nodeStates(after.Nodes))...)
+
+	deploymentStates := func(deployments []SnapshotDeployment) map[string]string {
+		states := make(map[string]string, len(deployments))
+		for _, d := range deployments {
+			states[d.Namespace+"/"+d.Name] = fmt.Sprintf("%d/%d ready", d.ReadyReplicas, d.Replicas)
+		}
+		return states
+	}
+	changes = append(changes, diffStates("deployment", deploymentStates(before.Deployments), deploymentStates(after.Deployments))...)
+
+	if len(before.OSDs) > 0 && len(after.OSDs) > 0 {
+		osdStates := func(osds []SnapshotOSD) map[string]string {
+			states := make(map[string]string, len(osds))
+			for _, o := range osds {
+				states["osd."+strconv.Itoa(o.ID)] = fmt.Sprintf("%s/%s on %s", o.Status, o.InOut, o.Host)
+			}
+			return states
+		}
+		changes = append(changes, diffStates("osd", osdStates(before.OSDs), osdStates(after.OSDs))...)
+	}
+
+	return changes
+}
+
+// diffStates compares two name→state maps and returns the changes sorted by name
+func diffStates(kind string, before, after map[string]string) []SnapshotChange {
+	names := make(map[string]bool, len(before)+len(after))
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var changes []SnapshotChange
+	for _, name := range sorted {
+		b, inBefore := before[name]
+		a, inAfter := after[name]
+		if !inBefore {
+			b = absent
+		}
+		if !inAfter {
+			a = absent
+		}
+		if b != a {
+			changes = append(changes, SnapshotChange{Kind: kind, Name: name, Before: b, After: a})
+		}
+	}
+	return changes
+}
+
+// flagStates returns the set/unset state of each maintenance-relevant Ceph flag
+func flagStates(f *k8s.CephFlags) map[string]string {
+	state := func(set bool) string {
+		if set {
+			return "set"
+		}
+		return "unset"
+	}
+	return map[string]string{
+		"noout":        state(f.NoOut),
+		"noin":         state(f.NoIn),
+		"nodown":       state(f.NoDown),
+		"noup":         state(f.NoUp),
+		"norebalance":  state(f.NoRebalance),
+		"norecover":    state(f.NoRecover),
+		"noscrub":      state(f.NoScrub),
+		"nodeep-scrub": state(f.NoDeepScrub),
+		"nobackfill":   state(f.NoBackfill),
+		"pause":        state(f.Pause),
+	}
+}
@@ -0,0 +1,84 @@
+package maintenance
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	before := &ClusterSnapshot{
+		Health: "HEALTH_OK",
+		Flags:  &k8s.CephFlags{},
+		Nodes: []SnapshotNode{
+			{Name: "worker-1", Status: "Ready", Schedulable: true},
+			{Name: "worker-2", Status: "Ready", Schedulable: true},
+		},
+		Deployments: []SnapshotDeployment{
+			{Namespace: "rook-ceph", Name: "rook-ceph-osd-0", Replicas: 1, ReadyReplicas: 1},
+			{Namespace: "rook-ceph", Name: "rook-ceph-osd-1", Replicas: 1, ReadyReplicas: 1},
+		},
+		OSDs: []SnapshotOSD{
+			{ID: 0, Host: "worker-1", Status: "up", InOut: "in"},
+			{ID: 1, Host: "worker-2", Status: "up", InOut: "in"},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		after *ClusterSnapshot
+		want  []SnapshotChange
+	}{
+		{
+			name:  "identical",
+			after: before,
+			want:  nil,
+		},
+		{
+			name: "node left cordoned with osd down",
+			after: &ClusterSnapshot{
+				Health: "HEALTH_WARN",
+				Flags:  &k8s.CephFlags{NoOut: true},
+				Nodes: []SnapshotNode{
+					{Name: "worker-1", Status: "Ready", Schedulable: false},
+					{Name: "worker-2", Status: "Ready", Schedulable: true},
+				},
+				Deployments: []SnapshotDeployment{
+					{Namespace: "rook-ceph", Name: "rook-ceph-osd-0", Replicas: 0, ReadyReplicas: 0},
+					{Namespace: "rook-ceph", Name: "rook-ceph-osd-1", Replicas: 1, ReadyReplicas: 1},
+					{Namespace: "rook-ceph", Name: "rook-ceph-crashcollector-worker-1", Replicas: 1, ReadyReplicas: 1},
+				},
+				OSDs: []SnapshotOSD{
+					{ID: 0, Host: "worker-1", Status: "down", InOut: "in"},
+					{ID: 1, Host: "worker-2", Status: "up", InOut: "in"},
+				},
+			},
+			want: []SnapshotChange{
+				{Kind: "health", Name: "ceph", Before: "HEALTH_OK", After: "HEALTH_WARN"},
+				{Kind: "flag", Name: "noout", Before: "unset", After: "set"},
+				{Kind: "node", Name: "worker-1", Before: "Ready, schedulable", After: "Ready, cordoned"},
+				{Kind: "deployment", Name: "rook-ceph/rook-ceph-crashcollector-worker-1", Before: "absent", After: "1/1 ready"},
+				{Kind: "deployment", Name: "rook-ceph/rook-ceph-osd-0", Before: "1/1 ready", After: "0/0 ready"},
+				{Kind: "osd", Name: "osd.0", Before: "up/in on worker-1", After: "down/in on worker-1"},
+			},
+		},
+		{
+			name: "ceph state missing afterwards is not compared",
+			after: &ClusterSnapshot{
+				Nodes:       before.Nodes,
+				Deployments: before.Deployments,
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffSnapshots(before, tt.after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffSnapshots() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -13,10 +13,12 @@ const operatorDeploymentName = "rook-ceph-operator"
 // IsInDownState checks if the node is fully in the "down" maintenance state.
 // This includes:
 //   - Node is cordoned (unschedulable)
-//   - Ceph noout flag is set
+//   - Ceph noout flag is set on the ceph.noout-scope of the node
 //   - rook-ceph-operator is scaled to 0 and has no ready replicas
 //   - All provided deployments are scaled to 0 and have no ready replicas
+//   - With discovery.statefulsets, all node-pinned StatefulSets are scaled to 0
 //
+// The operator is not checked for an external Ceph cluster.
 // Returns true only if ALL conditions are met. On any error, returns false
 // so the caller proceeds with the maintenance operation (fail-safe behavior).
 func IsInDownState(
@@ -31,6 +33,11 @@ func IsInDownState(
 		return false
 	}
 
+	statefulSets, _, err := DiscoverStatefulSets(ctx, client, cfg, nodeName, nil)
+	if err != nil || !AllStatefulSetsScaledDown(statefulSets) {
+		return false
+	}
+
 	// Check node is cordoned
 	nodeStatus, err := client.GetNodeStatus(ctx, nodeName)
 	if err != nil || !nodeStatus.Unschedulable {
@@ -38,11 +45,20 @@ func IsInDownState(
 	}
 
 	// Check noout flag is set
+	scope, err := ResolveNoOutScope(ctx, client, cfg, nodeName)
+	if err != nil {
+		return false
+	}
 	flags, err := client.GetCephFlags(ctx, cfg.Namespace)
-	if err != nil || !flags.NoOut {
+	if err != nil || !scope.IsSet(flags) {
 		return false
 	}
 
+	// External clusters keep the operator running
+	if IsExternalCluster(ctx, client, cfg) {
+		return true
+	}
+
 	// Check operator is scaled down
 	opStatus, err := client.GetDeploymentStatus(ctx, cfg.Namespace, operatorDeploymentName)
 	if err != nil || opStatus.Replicas != 0 || opStatus.ReadyReplicas != 0 {
@@ -55,10 +71,12 @@ func IsInDownState(
 // IsInUpState checks if the node is fully in the "up" operational state.
 // This includes:
 //   - Node is schedulable (not cordoned)
-//   - Ceph noout flag is unset
+//   - Ceph noout flag is unset on the scope the down phase set it on
 //   - rook-ceph-operator is scaled to 1 and has 1 ready replica
 //   - No deployments need to be restored (empty list means all are up)
+//   - With discovery.statefulsets, no node-pinned StatefulSet is scaled down
 //
+// The operator is not checked for an external Ceph cluster.
 // Returns true only if ALL conditions are met. On any error, returns false
 // so the caller proceeds with the maintenance operation (fail-safe behavior).
 func IsInUpState(
@@ -75,6 +93,11 @@ func IsInUpState(
 		return false
 	}
 
+	statefulSets, _, err := DiscoverScaledDownStatefulSets(ctx, client, cfg, nodeName, nil)
+	if err != nil || len(statefulSets) > 0 {
+		return false
+	}
+
 	// Check node is schedulable
 	nodeStatus, err := client.GetNodeStatus(ctx, nodeName)
 	if err != nil || nodeStatus.Unschedulable {
@@ -82,11 +105,20 @@ func IsInUpState(
 	}
 
 	// Check noout flag is unset
+	scope, err := UpNoOutScope(ctx, client, cfg, nodeName)
+	if err != nil {
+		return false
+	}
 	flags, err := client.GetCephFlags(ctx, cfg.Namespace)
-	if err != nil || flags.NoOut {
+	if err != nil || !scope.IsUnset(flags) {
 		return false
 	}
 
+	// External clusters keep the operator running
+	if IsExternalCluster(ctx, client, cfg) {
+		return true
+	}
+
 	// Check operator is running
 	opStatus, err := client.GetDeploymentStatus(ctx, cfg.Namespace, operatorDeploymentName)
 	if err != nil || opStatus.Replicas != 1 || opStatus.ReadyReplicas != 1 {
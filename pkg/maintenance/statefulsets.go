@@ -0,0 +1,101 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// DiscoverStatefulSets returns the StatefulSets pinned to the node that the
+// down phase scales, and those discovery.exclude keeps running. Both are empty
+// unless discovery.statefulsets is set.
+func DiscoverStatefulSets(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	nodeName string,
+	prefixes []string,
+) (kept, excluded []appsv1.StatefulSet, err error) {
+	if !cfg.Discovery.StatefulSets {
+		return nil, nil, nil
+	}
+	pinned, err := client.ListNodePinnedStatefulSets(ctx, cfg.Namespace, nodeName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to discover node-pinned statefulsets: %w", err)
+	}
+	kept, excluded = ExcludeStatefulSets(filterStatefulSetsByPrefixes(pinned, prefixes), cfg.Discovery)
+	return kept, excluded, nil
+}
+
+// DiscoverScaledDownStatefulSets returns the scaled-down StatefulSets pinned
+// to the node that the up phase restores, and those discovery.exclude leaves
+// at 0. Both are empty unless discovery.statefulsets is set.
+func DiscoverScaledDownStatefulSets(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	nodeName string,
+	prefixes []string,
+) (kept, excluded []appsv1.StatefulSet, err error) {
+	if !cfg.Discovery.StatefulSets {
+		return nil, nil, nil
+	}
+	scaledDown, err := client.ListScaledDownStatefulSetsForNode(ctx, cfg.Namespace, nodeName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to discover scaled-down statefulsets: %w", err)
+	}
+	kept, excluded = ExcludeStatefulSets(filterStatefulSetsByPrefixes(scaledDown, prefixes), cfg.Discovery)
+	return kept, excluded, nil
+}
+
+// ExcludeStatefulSets splits discovered StatefulSets into those the phases
+// act on and those discovery.exclude or discovery.filters.exclude keeps
+// running, preserving their order
+func ExcludeStatefulSets(statefulSets []appsv1.StatefulSet, discovery config.DiscoveryConfig) (kept, excluded []appsv1.StatefulSet) {
+	excludes := discoveryExcludes(discovery)
+	for i, sts := range statefulSets {
+		if excludes(&statefulSets[i]) {
+			excluded = append(excluded, sts)
+		} else {
+			kept = append(kept, sts)
+		}
+	}
+	return kept, excluded
+}
+
+// filterStatefulSetsByPrefixes narrows StatefulSets to those whose names
+// start with one of the given prefixes. An empty prefix list keeps all.
+func filterStatefulSetsByPrefixes(statefulSets []appsv1.StatefulSet, prefixes []string) []appsv1.StatefulSet {
+	if len(prefixes) == 0 {
+		return statefulSets
+	}
+	var filtered []appsv1.StatefulSet
+	for _, sts := range statefulSets {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(sts.Name, prefix) {
+				filtered = append(filtered, sts)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// AllStatefulSetsScaledDown checks if all StatefulSets are scaled to 0 with
+// no ready replicas, like AllDeploymentsScaledDown
+func AllStatefulSetsScaledDown(statefulSets []appsv1.StatefulSet) bool {
+	for _, sts := range statefulSets {
+		specReplicas := int32(1) // default if nil
+		if sts.Spec.Replicas != nil {
+			specReplicas = *sts.Spec.Replicas
+		}
+		if specReplicas > 0 || sts.Status.ReadyReplicas > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package maintenance
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func pinnedStatefulSet(name, node string, replicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{NodeSelector: map[string]string{"kubernetes.io/hostname": node}},
+			},
+		},
+	}
+}
+
+func statefulSetNames(statefulSets []appsv1.StatefulSet) []string {
+	var names []string
+	for _, sts := range statefulSets {
+		names = append(names, sts.Name)
+	}
+	return names
+}
+
+func TestDiscoverStatefulSets(t *testing.T) {
+	client := &k8s.Client{Clientset: fake.NewClientset(
+		pinnedStatefulSet("rook-ceph-rgw-store-a", "worker-1", 1),
+		pinnedStatefulSet("metrics-agent", "worker-1", 0),
+		pinnedStatefulSet("log-shipper", "worker-1", 1),
+		pinnedStatefulSet("rook-ceph-rgw-store-b", "worker-2", 1),
+	)}
+
+	tests := []struct {
+		name         string
+		enabled      bool
+		prefixes     []string
+		scaledDown   bool
+		wantKept     []string
+		wantExcluded []string
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:         "down discovery",
+			enabled:      true,
+			wantKept:     []string{"metrics-agent", "rook-ceph-rgw-store-a"},
+			wantExcluded: []string{"log-shipper"},
+		},
+		{
+			name:     "limited to prefixes",
+			enabled:  true,
+			prefixes: []string{"rook-ceph-"},
+			wantKept: []string{"rook-ceph-rgw-store-a"},
+		},
+		{
+			name:       "up discovery only returns scaled-down statefulsets",
+			enabled:    true,
+			scaledDown: true,
+			wantKept:   []string{"metrics-agent"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Namespace = "rook-ceph"
+			cfg.Discovery.StatefulSets = tt.enabled
+			cfg.Discovery.Exclude = []string{"log-.*"}
+
+			discover := DiscoverStatefulSets
+			if tt.scaledDown {
+				discover = DiscoverScaledDownStatefulSets
+			}
+			kept, excluded, err := discover(context.Background(), client, cfg, "worker-1", tt.prefixes)
+			if err != nil {
+				t.Fatalf("discover error = %v", err)
+			}
+			if got := statefulSetNames(kept); !slices.Equal(got, tt.wantKept) {
+				t.Errorf("kept = %v, want %v", got, tt.wantKept)
+			}
+			if got := statefulSetNames(excluded); !slices.Equal(got, tt.wantExcluded) {
+				t.Errorf("excluded = %v, want %v", got, tt.wantExcluded)
+			}
+		})
+	}
+}
+
+func TestAllStatefulSetsScaledDown(t *testing.T) {
+	scaled := *pinnedStatefulSet("a", "worker-1", 0)
+	running := *pinnedStatefulSet("b", "worker-1", 1)
+	terminating := *pinnedStatefulSet("c", "worker-1", 0)
+	terminating.Status.ReadyReplicas = 1
+
+	tests := []struct {
+		name         string
+		statefulSets []appsv1.StatefulSet
+		want         bool
+	}{
+		{"empty", nil, true},
+		{"all at 0", []appsv1.StatefulSet{scaled}, true},
+		{"one running", []appsv1.StatefulSet{scaled, running}, false},
+		{"pods still ready", []appsv1.StatefulSet{terminating}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AllStatefulSetsScaledDown(tt.statefulSets); got != tt.want {
+				t.Errorf("AllStatefulSetsScaledDown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// StretchInfo describes a stretch cluster as seen from the node going down.
+// In stretch mode data and monitors are split across two zones plus a
+// tiebreaker monitor, so quorum and placement depend on zones, not hosts.
+type StretchInfo struct {
+	// ZoneType is the CRUSH bucket type the monitors are located by (e.g. zone)
+	ZoneType string
+
+	// Zone is the bucket holding the node, empty if the CRUSH tree does not place it
+	Zone string
+
+	// TiebreakerMon is the monitor outside the data zones
+	TiebreakerMon string
+
+	// OutOfQuorum lists the monitors currently out of quorum
+	OutOfQuorum []string
+
+	// Degraded is set while a zone is down and pools run with reduced min_size
+	Degraded bool
+
+	// Recovering is set while a returning zone catches up
+	Recovering bool
+}
+
+// Summary returns a one-line description of the node's place in the cluster
+func (s *StretchInfo) Summary() string {
+	zone := "an unknown " + s.ZoneType
+	if s.Zone != "" {
+		zone = fmt.Sprintf("%s %s", s.ZoneType, s.Zone)
+	}
+	state := "active"
+	switch {
+	case s.Degraded:
+		state = "degraded"
+	case s.Recovering:
+		state = "recovering"
+	}
+	return fmt.Sprintf("Stretch mode %s: node is in %s, tiebreaker mon %s", state, zone, s.TiebreakerMon)
+}
+
+// Blocking returns why the node must not go down, or "" when it may. A
+// degraded stretch cluster already runs on a single zone; losing a node there
+// risks data unavailability.
+func (s *StretchInfo) Blocking() string {
+	if s.Degraded {
+		return "stretch mode is degraded - a zone is already down and the remaining zone holds the only copies"
+	}
+	return ""
+}
+
+// Warnings returns the non-blocking stretch mode risks
+func (s *StretchInfo) Warnings() []string {
+	var warnings []string
+	if s.Recovering {
+		warnings = append(warnings, "stretch mode is recovering - wait until both zones are back in sync before taking more nodes down")
+	}
+	if slices.Contains(s.OutOfQuorum, s.TiebreakerMon) {
+		warnings = append(warnings, fmt.Sprintf(
+			"tiebreaker mon %s is out of quorum - losing a monitor in %s %s may lose quorum", s.TiebreakerMon, s.ZoneType, s.Zone))
+	}
+	if others := slices.DeleteFunc(slices.Clone(s.OutOfQuorum), func(name string) bool { return name == s.TiebreakerMon }); len(others) > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"mon(s) %s out of quorum - stretch quorum needs monitors from both zones or the tiebreaker", strings.Join(others, ", ")))
+	}
+	if s.Zone == "" {
+		warnings = append(warnings, fmt.Sprintf("node not found under any %s in the CRUSH tree", s.ZoneType))
+	}
+	return warnings
+}
+
+// CheckStretchMode returns the stretch cluster view of nodeName, or nil when
+// the cluster does not run in stretch mode
+func CheckStretchMode(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) (*StretchInfo, error) {
+	mons, err := client.GetMonitorStatus(ctx, cfg.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !mons.StretchMode {
+		return nil, nil
+	}
+
+	osdMap, err := client.GetStretchModeStatus(ctx, cfg.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := client.GetOSDTree(ctx, cfg.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return DescribeStretchMode(mons, osdMap, tree, nodeName), nil
+}
+
+// DescribeStretchMode combines monitor, OSD map and CRUSH data into the
+// stretch cluster view of nodeName
+func DescribeStretchMode(mons *k8s.MonitorStatus, osdMap *k8s.StretchModeStatus, tree *k8s.CephOSDTree, nodeName string) *StretchInfo {
+	info := &StretchInfo{
+		ZoneType:      stretchZoneType(mons),
+		TiebreakerMon: mons.TiebreakerMon,
+		OutOfQuorum:   mons.OutOfQuorum,
+		Degraded:      osdMap.Degraded,
+		Recovering:    osdMap.Recovering,
+	}
+
+	h := newCrushHierarchy(tree)
+	for _, node := range tree.Nodes {
+		if node.Type == "host" && node.Name == nodeName {
+			if zone, ok := h.bucketOfType(node.ID, info.ZoneType); ok {
+				info.Zone = zone.Name
+			}
+			break
+		}
+	}
+
+	return info
+}
+
+// stretchZoneType returns the CRUSH bucket type of the monitor locations
+// (e.g. "zone" from "{zone=a}"), defaulting to zone
+func stretchZoneType(mons *k8s.MonitorStatus) string {
+	for _, location := range mons.Locations {
+		location = strings.Trim(location, "{}")
+		if bucketType, _, ok := strings.Cut(location, "="); ok && bucketType != "" {
+			return bucketType
+		}
+	}
+	return "zone"
+}
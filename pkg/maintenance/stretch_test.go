@@ -0,0 +1,146 @@
+package maintenance
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// zoneTree has node-a in zone a, node-b in zone b and node-c outside any zone
+func zoneTree() *k8s.CephOSDTree {
+	return &k8s.CephOSDTree{Nodes: []k8s.CephOSDNode{
+		{ID: -1, Name: "default", Type: "root", Children: []int{-2, -3, -6}},
+		{ID: -2, Name: "a", Type: "zone", Children: []int{-4}},
+		{ID: -3, Name: "b", Type: "zone", Children: []int{-5}},
+		{ID: -4, Name: "node-a", Type: "host", Children: []int{0}},
+		{ID: -5, Name: "node-b", Type: "host", Children: []int{1}},
+		{ID: -6, Name: "node-c", Type: "host", Children: []int{2}},
+		{ID: 0, Name: "osd.0", Type: "osd", Status: "up"},
+		{ID: 1, Name: "osd.1", Type: "osd", Status: "up"},
+		{ID: 2, Name: "osd.2", Type: "osd", Status: "up"},
+	}}
+}
+
+func TestDescribeStretchMode(t *testing.T) {
+	locations := map[string]string{"a": "{zone=a}", "b": "{zone=b}", "e": "{zone=arbiter}"}
+
+	tests := []struct {
+		name         string
+		outOfQuorum  []string
+		osdMap       k8s.StretchModeStatus
+		nodeName     string
+		wantZone     string
+		wantBlocking bool
+		wantWarnings []string
+	}{
+		{
+			name:     "healthy stretch cluster",
+			osdMap:   k8s.StretchModeStatus{Enabled: true, BucketCount: 2},
+			nodeName: "node-a",
+			wantZone: "a",
+		},
+		{
+			name:         "degraded blocks",
+			osdMap:       k8s.StretchModeStatus{Enabled: true, BucketCount: 2, Degraded: true},
+			nodeName:     "node-b",
+			wantZone:     "b",
+			wantBlocking: true,
+		},
+		{
+			name:         "recovering warns",
+			osdMap:       k8s.StretchModeStatus{Enabled: true, BucketCount: 2, Recovering: true},
+			nodeName:     "node-a",
+			wantZone:     "a",
+			wantWarnings: []string{"stretch mode is recovering"},
+		},
+		{
+			name:         "tiebreaker and data mon out of quorum",
+			outOfQuorum:  []string{"b", "e"},
+			osdMap:       k8s.StretchModeStatus{Enabled: true, BucketCount: 2},
+			nodeName:     "node-a",
+			wantZone:     "a",
+			wantWarnings: []string{"tiebreaker mon e is out of quorum", "mon(s) b out of quorum"},
+		},
+		{
+			name:         "node outside any zone",
+			osdMap:       k8s.StretchModeStatus{Enabled: true, BucketCount: 2},
+			nodeName:     "node-c",
+			wantWarnings: []string{"node not found under any zone"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mons := &k8s.MonitorStatus{
+				StretchMode:   true,
+				TiebreakerMon: "e",
+				Locations:     locations,
+				OutOfQuorum:   tt.outOfQuorum,
+			}
+
+			info := DescribeStretchMode(mons, &tt.osdMap, zoneTree(), tt.nodeName)
+
+			if info.ZoneType != "zone" {
+				t.Errorf("ZoneType = %q, want zone", info.ZoneType)
+			}
+			if info.Zone != tt.wantZone {
+				t.Errorf("Zone = %q, want %q", info.Zone, tt.wantZone)
+			}
+			if got := info.Blocking() != ""; got != tt.wantBlocking {
+				t.Errorf("Blocking() = %q, want blocking %v", info.Blocking(), tt.wantBlocking)
+			}
+
+			warnings := info.Warnings()
+			if len(warnings) != len(tt.wantWarnings) {
+				t.Fatalf("Warnings() = %v, want %d warning(s)", warnings, len(tt.wantWarnings))
+			}
+			for i, want := range tt.wantWarnings {
+				if !strings.Contains(warnings[i], want) {
+					t.Errorf("Warnings()[%d] = %q, want it to contain %q", i, warnings[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestStretchInfo_Summary(t *testing.T) {
+	info := &StretchInfo{ZoneType: "zone", Zone: "a", TiebreakerMon: "e", Degraded: true}
+
+	want := "Stretch mode degraded: node is in zone a, tiebreaker mon e"
+	if got := info.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestStretchZoneType(t *testing.T) {
+	tests := []struct {
+		name      string
+		locations map[string]string
+		want      string
+	}{
+		{name: "datacenter", locations: map[string]string{"a": "{datacenter=dc1}"}, want: "datacenter"},
+		{name: "no locations defaults to zone", want: "zone"},
+		{name: "malformed location", locations: map[string]string{"a": "{dc1}"}, want: "zone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stretchZoneType(&k8s.MonitorStatus{Locations: tt.locations}); got != tt.want {
+				t.Errorf("stretchZoneType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStretchInfo_WarningsKeepsOutOfQuorum(t *testing.T) {
+	outOfQuorum := []string{"e", "b"}
+	info := &StretchInfo{ZoneType: "zone", Zone: "a", TiebreakerMon: "e", OutOfQuorum: outOfQuorum}
+
+	info.Warnings()
+
+	if !slices.Equal(outOfQuorum, []string{"e", "b"}) {
+		t.Errorf("Warnings() modified OutOfQuorum: %v", outOfQuorum)
+	}
+}
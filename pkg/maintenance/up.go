@@ -2,10 +2,14 @@ package maintenance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/andri/crook/internal/logger"
 	"github.com/andri/crook/pkg/config"
+	crookerrors "github.com/andri/crook/pkg/errors"
 	"github.com/andri/crook/pkg/k8s"
 	appsv1 "k8s.io/api/apps/v1"
 )
@@ -14,7 +18,14 @@ import (
 type UpPhaseProgress struct {
 	Stage       string
 	Description string
-	Deployment  string // Optional: current deployment being processed
+	Deployment  string       // Optional: current deployment being processed
+	Check       *CheckStatus // Optional: the pre-flight check that just finished
+
+	// Sequence increases by one with every update of a run, starting at 1,
+	// so consumers can detect dropped updates (see DroppedUpdates)
+	Sequence uint64
+	// Percent is the share of the phase done, 0-100; it never decreases
+	Percent int
 }
 
 // UpPhaseOptions holds options for the up phase operation
@@ -31,10 +42,40 @@ type UpPhaseOptions struct {
 	// This ensures the confirmed plan matches the executed plan (avoiding TUI plan drift).
 	// If nil, ExecuteUpPhase will discover deployments via ListScaledDownDeploymentsForNode.
 	Deployments []appsv1.Deployment
+
+	// StatefulSets provides pre-discovered StatefulSets to restore alongside
+	// Deployments. Only used when discovery.statefulsets is set.
+	StatefulSets []appsv1.StatefulSet
+
+	// Prefixes limits discovery to deployments whose names start with one of
+	// these prefixes. Optional - if empty, every scaled-down deployment is restored.
+	Prefixes []string
+
+	// SignOff is recorded in the maintenance report. Optional.
+	SignOff SignOff
+
+	// Operator is who runs the phase, recorded in the maintenance report.
+	// Optional - defaults to CurrentOperator.
+	Operator string
+
+	// Only restricts the restore to these workloads (namespace/name), e.g.
+	// the RetryKeys of a failed run's *crookerrors.MultiError. Optional - if
+	// empty, every discovered workload is restored.
+	Only []string
+
+	// ResumeFrom is the progress stage a failed run stopped at. The steps
+	// before it are not repeated: resuming from "scale-up" skips pre-flight
+	// and the uncordon. Optional - if empty, every step runs.
+	ResumeFrom string
 }
 
 // ExecuteUpPhase orchestrates the complete node up phase workflow
-// Steps: pre-flight → discover scaled-down deployments → uncordon → restore deployments → scale operator → unset noout
+// Steps: pre-flight → discover scaled-down deployments → uncordon → restore deployments → restore statefulsets → scale operator → unset noout → smoke test
+// StatefulSets are only restored when discovery.statefulsets is set, the
+// storage smoke test only when smoke-test.enabled is set. With
+// opts.ResumeFrom a failed run continues at the step that failed. A
+// failure is recorded in the node's open maintenance report for 'crook history';
+// on success the node's PagerDuty/Opsgenie maintenance windows are closed.
 func ExecuteUpPhase(
 	ctx context.Context,
 	client *k8s.Client,
@@ -42,23 +83,71 @@ func ExecuteUpPhase(
 	nodeName string,
 	opts UpPhaseOptions,
 ) error {
+	mark := client.ChangeCount()
+	err := runUpPhase(ctx, client, cfg, nodeName, opts)
+	// The phase context may have timed out; the result should still be recorded.
+	// A successful run has closed the report already, with its changes.
+	recordAppliedChanges(context.WithoutCancel(ctx), client, cfg, nodeName, client.ChangesSince(mark))
+	recordReportResult(context.WithoutCancel(ctx), client, cfg, nodeName, ScalePhaseUp, err)
+	if err == nil {
+		closeMaintenanceWindows(ctx, client, cfg, nodeName)
+	}
+	return err
+}
+
+// runUpPhase runs the steps of ExecuteUpPhase
+func runUpPhase(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	nodeName string,
+	opts UpPhaseOptions,
+) error {
+	mark := client.ChangeCount()
+	progress := newProgressSequence(upStageWeights)
+	opts.ProgressCallback = progress.upCallback(opts.ProgressCallback)
+
+	// A resumed run only repeats the step that failed and those after it
+	resume := newResumePlan(upSteps, upStepAliases, opts.ResumeFrom)
+	if resume.resumed() {
+		logger.Info("resuming up phase", "node", nodeName, "from", opts.ResumeFrom, "only", opts.Only)
+	}
+
 	// Step 1: Pre-flight validation
-	sendUpProgress(opts.ProgressCallback, "pre-flight", "Running pre-flight validation checks", "")
+	if resume.runs("pre-flight") {
+		sendUpProgress(opts.ProgressCallback, "pre-flight", "Running pre-flight validation checks", "")
 
-	validationResults, err := ValidateUpPhase(ctx, client, cfg, nodeName)
-	if err != nil {
-		return fmt.Errorf("pre-flight validation failed: %w", err)
+		validationResults, err := ValidateUpPhaseWithProgress(ctx, client, cfg, nodeName, func(check CheckStatus) {
+			if opts.ProgressCallback != nil {
+				opts.ProgressCallback(UpPhaseProgress{Stage: preflightCheckStage, Description: check.String(), Check: &check})
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrValidationFailed, err)
+		}
+		// Warnings never hold back bringing a node into service; they are logged
+		if err := validationResults.Check(true); err != nil {
+			return err
+		}
+
+		// Keep alerts silenced while the workloads come back, in case the down
+		// phase opened no window (best-effort)
+		openMaintenanceWindows(ctx, client, cfg, nodeName)
 	}
-	if !validationResults.AllPassed {
-		return fmt.Errorf("pre-flight validation failed:\n%s", validationResults.String())
+
+	// External clusters have no Rook daemons or operator to restore
+	if IsExternalCluster(ctx, client, cfg) {
+		return executeExternalUpPhase(ctx, client, cfg, nodeName, resume, opts)
 	}
 
 	// Step 2: Use pre-discovered deployments or discover via nodeSelector
 	var deployments []appsv1.Deployment
-	if len(opts.Deployments) > 0 {
+	var statefulSets []appsv1.StatefulSet
+	if len(opts.Deployments) > 0 || len(opts.StatefulSets) > 0 {
 		// Use pre-discovered deployments (TUI confirmed plan)
 		sendUpProgress(opts.ProgressCallback, "discover", fmt.Sprintf("Using %d pre-discovered deployments on %s", len(opts.Deployments), nodeName), "")
 		deployments = opts.Deployments
+		statefulSets = opts.StatefulSets
 	} else {
 		// Discover deployments (CLI non-TUI mode)
 		sendUpProgress(opts.ProgressCallback, "discover", fmt.Sprintf("Discovering scaled-down deployments on %s", nodeName), "")
@@ -66,34 +155,109 @@ func ExecuteUpPhase(
 		if discoverErr != nil {
 			return fmt.Errorf("failed to discover scaled-down deployments: %w", discoverErr)
 		}
-		deployments = discovered
+		deployments, _ = ExcludeDeployments(FilterByPrefixes(discovered, opts.Prefixes), cfg.Discovery)
+		statefulSets, _, discoverErr = DiscoverScaledDownStatefulSets(ctx, client, cfg, nodeName, opts.Prefixes)
+		if discoverErr != nil {
+			return discoverErr
+		}
+	}
+
+	if len(opts.Only) > 0 {
+		deployments = slices.DeleteFunc(slices.Clone(deployments), func(d appsv1.Deployment) bool {
+			return !slices.Contains(opts.Only, d.Namespace+"/"+d.Name)
+		})
+		statefulSets = slices.DeleteFunc(slices.Clone(statefulSets), func(s appsv1.StatefulSet) bool {
+			return !slices.Contains(opts.Only, s.Namespace+"/"+s.Name)
+		})
 	}
 
 	// Step 3: Uncordon node FIRST so pods can schedule when deployments scale up
-	sendUpProgress(opts.ProgressCallback, "uncordon", fmt.Sprintf("Uncordoning node %s", nodeName), "")
-	if uncordonErr := client.UncordonNode(ctx, nodeName); uncordonErr != nil {
-		return fmt.Errorf("failed to uncordon node %s: %w", nodeName, uncordonErr)
+	if resume.runs("uncordon") {
+		if uncordonErr := uncordonForUp(ctx, client, nodeName, opts); uncordonErr != nil {
+			return uncordonErr
+		}
 	}
 
-	// Step 4: Restore deployments in order
-	if restoreErr := restoreDeployments(ctx, client, cfg, deployments, opts); restoreErr != nil {
-		return restoreErr
+	// Step 4: Restore deployments in order, to the replica counts the down
+	// phase persisted in the maintenance report or annotated on each workload
+	if resume.runs("scale-up") {
+		persisted := LoadPersistedReplicas(ctx, client, cfg, nodeName)
+		progress.expect("scale-up", len(deployments)+len(statefulSets))
+		history := loadScaleHistory(ctx, client, cfg)
+		restoreErr := restoreDeployments(ctx, client, cfg, deployments, persisted, history, opts)
+		if restoreErr == nil {
+			restoreErr = scaleUpWorkloads(ctx, client, k8s.StatefulSetWorkloads(statefulSets), "", persisted, history, opts)
+		} else {
+			restoreErr = skipRemaining(restoreErr, k8s.StatefulSetWorkloads(statefulSets))
+		}
+		saveScaleHistory(ctx, client, cfg, history)
+		if restoreErr != nil {
+			return restoreErr
+		}
 	}
 
 	// Step 5: Scale up rook-ceph-operator to 1
-	if scaleErr := scaleOperator(ctx, client, cfg, opts); scaleErr != nil {
-		return scaleErr
+	if resume.runs("operator") {
+		if scaleErr := scaleOperator(ctx, client, cfg, opts); scaleErr != nil {
+			return scaleErr
+		}
 	}
 
 	// Step 6: Finalize - unset noout flag to allow normal Ceph rebalancing
-	if finalizeErr := finalizeUpPhase(ctx, client, cfg, opts); finalizeErr != nil {
+	if finalizeErr := finalizeUpPhase(ctx, client, cfg, nodeName, opts); finalizeErr != nil {
 		return finalizeErr
 	}
 
+	// Step 7 (optional): Prove the storage path works before closing the maintenance
+	progress.expect(smokeTestStage, 2*len(cfg.SmokeTest.StorageClasses))
+	if smokeErr := smokeTestIfEnabled(ctx, client, cfg, nodeName, resume, opts); smokeErr != nil {
+		return smokeErr
+	}
+
+	// Complete the maintenance report started by the down phase (best-effort)
+	recordReportRun(ctx, client, cfg, nodeName, ScalePhaseUp, opts.Operator, opts.SignOff)
+	recordAppliedChanges(ctx, client, cfg, nodeName, client.ChangesSince(mark))
+	RecordAfterSnapshot(ctx, client, cfg, nodeName)
+
 	sendUpProgress(opts.ProgressCallback, "complete", fmt.Sprintf("Up phase completed successfully - node %s is operational", nodeName), "")
 	return nil
 }
 
+// executeExternalUpPhase restores a node of an external Ceph cluster
+// Steps: uncordon → unset noout → smoke test
+func executeExternalUpPhase(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, resume resumePlan, opts UpPhaseOptions) error {
+	if resume.runs("uncordon") {
+		if err := uncordonForUp(ctx, client, nodeName, opts); err != nil {
+			return err
+		}
+	}
+
+	if err := finalizeUpPhase(ctx, client, cfg, nodeName, opts); err != nil {
+		return err
+	}
+
+	if err := smokeTestIfEnabled(ctx, client, cfg, nodeName, resume, opts); err != nil {
+		return err
+	}
+
+	recordReportRun(ctx, client, cfg, nodeName, ScalePhaseUp, opts.Operator, opts.SignOff)
+	RecordAfterSnapshot(ctx, client, cfg, nodeName)
+
+	sendUpProgress(opts.ProgressCallback, "complete", fmt.Sprintf("Up phase completed successfully - node %s is operational", nodeName), "")
+	return nil
+}
+
+// uncordonForUp uncordons the node and clears its sign-off
+func uncordonForUp(ctx context.Context, client *k8s.Client, nodeName string, opts UpPhaseOptions) error {
+	sendUpProgress(opts.ProgressCallback, "uncordon", fmt.Sprintf("Uncordoning node %s", nodeName), "")
+	if err := client.UncordonNode(ctx, nodeName); err != nil {
+		return fmt.Errorf("failed to uncordon node %s: %w", nodeName, err)
+	}
+	clearSignOff(ctx, client, nodeName)
+	restoreScaleDown(ctx, client, nodeName)
+	return nil
+}
+
 // restoreDeployments scales up deployments in the correct order.
 //
 // MON handling: Unlike the DOWN phase, the UP phase requires explicit MON
@@ -112,8 +276,17 @@ func ExecuteUpPhase(
 //  3. Wait for Ceph monitor quorum to establish
 //  4. Scale up remaining deployments (OSDs, exporters, etc.) in order
 //
-// All deployments are scaled to 1 replica (Rook-Ceph node-pinned deployments always use 1).
-func restoreDeployments(ctx context.Context, client *k8s.Client, cfg config.Config, deployments []appsv1.Deployment, opts UpPhaseOptions) error {
+// Deployments are scaled back to their original replica count (see
+// PersistedReplicas.RestoreReplicas), which is 1 for Rook-Ceph node-pinned deployments.
+func restoreDeployments(
+	ctx context.Context,
+	client *k8s.Client,
+	cfg config.Config,
+	deployments []appsv1.Deployment,
+	persisted PersistedReplicas,
+	history *ScaleHistory,
+	opts UpPhaseOptions,
+) error {
 	if len(deployments) == 0 {
 		sendUpProgress(opts.ProgressCallback, "skip", "No scaled-down deployments to restore", "")
 		return nil
@@ -124,18 +297,8 @@ func restoreDeployments(ctx context.Context, client *k8s.Client, cfg config.Conf
 
 	// First scale up MON deployments
 	if len(monDeployments) > 0 {
-		for _, deployment := range monDeployments {
-			deploymentName := fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name)
-
-			sendUpProgress(opts.ProgressCallback, "scale-up", fmt.Sprintf("Scaling up MON %s to 1 replica", deploymentName), deploymentName)
-
-			if err := client.ScaleDeployment(ctx, deployment.Namespace, deployment.Name, 1); err != nil {
-				return fmt.Errorf("failed to scale MON deployment %s to 1: %w", deploymentName, err)
-			}
-
-			if err := WaitForDeploymentScaleUp(ctx, client, deployment.Namespace, deployment.Name, 1, opts.WaitOptions); err != nil {
-				return fmt.Errorf("failed waiting for MON deployment %s to scale up: %w", deploymentName, err)
-			}
+		if err := scaleUpWorkloads(ctx, client, k8s.DeploymentWorkloads(monDeployments), "MON ", persisted, history, opts); err != nil {
+			return skipRemaining(err, k8s.DeploymentWorkloads(otherDeployments))
 		}
 
 		// Wait for MON quorum before proceeding to OSDs
@@ -147,22 +310,21 @@ func restoreDeployments(ctx context.Context, client *k8s.Client, cfg config.Conf
 	}
 
 	// Now scale up remaining deployments (OSDs and others) in order
-	orderedOther := OrderDeploymentsForUp(otherDeployments)
-	for _, deployment := range orderedOther {
-		deploymentName := fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name)
-
-		sendUpProgress(opts.ProgressCallback, "scale-up", fmt.Sprintf("Scaling up %s to 1 replica", deploymentName), deploymentName)
-
-		if err := client.ScaleDeployment(ctx, deployment.Namespace, deployment.Name, 1); err != nil {
-			return fmt.Errorf("failed to scale deployment %s to 1: %w", deploymentName, err)
-		}
+	return scaleUpWorkloads(ctx, client, k8s.DeploymentWorkloads(OrderDeploymentsForUp(otherDeployments)), "", persisted, history, opts)
+}
 
-		if err := WaitForDeploymentScaleUp(ctx, client, deployment.Namespace, deployment.Name, 1, opts.WaitOptions); err != nil {
-			return fmt.Errorf("failed waiting for deployment %s to scale up: %w", deploymentName, err)
-		}
+// skipRemaining adds the workloads a failed scale batch kept from being
+// attempted to its *crookerrors.MultiError, so the error lists them and a
+// retry of the failed subset includes them. Other errors are returned as is.
+func skipRemaining(err error, remaining []k8s.Workload) error {
+	var multi *crookerrors.MultiError
+	if !errors.As(err, &multi) {
+		return err
 	}
-
-	return nil
+	for _, w := range remaining {
+		multi.Skip(workloadName(w), w.String(), errors.New("an earlier workload failed"))
+	}
+	return multi
 }
 
 // separateMonDeploymentsFromList separates MON deployments from other deployments.
@@ -197,13 +359,21 @@ func scaleOperator(ctx context.Context, client *k8s.Client, cfg config.Config, o
 	return nil
 }
 
-// finalizeUpPhase unsets the noout flag to allow normal Ceph rebalancing
-func finalizeUpPhase(ctx context.Context, client *k8s.Client, cfg config.Config, opts UpPhaseOptions) error {
-	sendUpProgress(opts.ProgressCallback, "unset-noout", "Unsetting Ceph noout flag", "")
+// finalizeUpPhase unsets the noout flag, on the scope the down phase set it
+// on, to allow normal Ceph rebalancing, and deletes the noout watchdog and the
+// node's record of the scope
+func finalizeUpPhase(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, opts UpPhaseOptions) error {
+	scope, err := UpNoOutScope(ctx, client, cfg, nodeName)
+	if err != nil {
+		return err
+	}
+	sendUpProgress(opts.ProgressCallback, "unset-noout", fmt.Sprintf("Unsetting Ceph noout flag (%s)", scope), "")
 
-	if err := client.UnsetNoOut(ctx, cfg.Namespace); err != nil {
+	if err := unsetNoOut(ctx, client, cfg, scope); err != nil {
 		return fmt.Errorf("failed to unset noout flag: %w", err)
 	}
+	clearNoOutScope(ctx, client, nodeName)
+	disarmNoOutWatchdog(ctx, client, cfg, nodeName, opts.ProgressCallback)
 
 	return nil
 }
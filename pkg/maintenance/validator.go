@@ -2,95 +2,277 @@ package maintenance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/andri/crook/internal/logger"
 	"github.com/andri/crook/pkg/config"
+	crookerrors "github.com/andri/crook/pkg/errors"
 	"github.com/andri/crook/pkg/k8s"
 	authv1 "k8s.io/api/authorization/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ErrValidationFailed is returned when pre-flight validation does not pass.
+// No cluster changes have been made when this error is returned.
+var ErrValidationFailed = errors.New("pre-flight validation failed")
+
+// ErrAcknowledgmentRequired is returned, wrapped in ErrValidationFailed,
+// when only SeverityWarn checks failed and the phase was not told to
+// proceed anyway (DownPhaseOptions.AcknowledgeWarnings)
+var ErrAcknowledgmentRequired = errors.New("pre-flight warnings need acknowledgment")
+
+// ErrControlPlaneNode is returned, wrapped in ErrValidationFailed, when the
+// down phase targets a control-plane node without
+// DownPhaseOptions.AllowControlPlane
+var ErrControlPlaneNode = errors.New("node is a control-plane node")
+
+// Severity decides whether a failed pre-flight check stops the phase
+type Severity string
+
+const (
+	// SeverityBlock failures stop the phase; results without a severity block
+	SeverityBlock Severity = "block"
+
+	// SeverityWarn failures let the phase proceed once the operator
+	// acknowledges them, e.g. a HEALTH_WARN cluster
+	SeverityWarn Severity = "warn"
+)
+
 // ValidationResult holds the result of a single pre-flight check
 type ValidationResult struct {
-	Check   string
-	Passed  bool
-	Error   error
-	Message string
+	Check    string
+	Passed   bool
+	Error    error
+	Message  string
+	Severity Severity
+}
+
+// Blocking reports whether the result failed and stops the phase
+func (r ValidationResult) Blocking() bool {
+	return !r.Passed && r.Severity != SeverityWarn
 }
 
 // ValidationResults holds all validation results
 type ValidationResults struct {
 	Results   []ValidationResult
 	AllPassed bool
+
+	// Warnings are non-blocking findings (e.g. conflicting CephCluster settings)
+	Warnings []string
 }
 
+// Pre-flight check names of the checks that only warn
+const (
+	cephHealthCheck = "Ceph health"
+	otherNodesCheck = "Other nodes in maintenance"
+)
+
 // ValidateDownPhase performs comprehensive pre-flight checks before down phase
 func ValidateDownPhase(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) (*ValidationResults, error) {
-	results := &ValidationResults{
-		Results:   make([]ValidationResult, 0),
-		AllPassed: true,
-	}
+	return ValidateDownPhaseWithProgress(ctx, client, cfg, nodeName, nil)
+}
 
-	// Check 1: Cluster connectivity (implicit - client creation validates this)
-	results.addResult("Cluster connectivity", true, nil, "Successfully connected to Kubernetes API")
+// ValidateDownPhaseWithProgress runs the down phase pre-flight checks
+// concurrently, calling onCheck as each one finishes. onCheck may be nil.
+func ValidateDownPhaseWithProgress(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, onCheck func(CheckStatus)) (*ValidationResults, error) {
+	return validateDownPhase(ctx, client, cfg, nodeName, false, onCheck)
+}
 
-	// Check 2: Node exists
-	if err := validateNodeExists(ctx, client, nodeName); err != nil {
-		results.addResult("Node existence", false, err, fmt.Sprintf("Node %s not found", nodeName))
-	} else {
-		results.addResult("Node existence", true, nil, fmt.Sprintf("Node %s exists", nodeName))
-	}
+// validateDownPhase runs the down phase pre-flight checks. For a dead node
+// the checks that need the node itself are skipped: whether its OSDs may
+// stop, and the user-defined checks, which usually probe the node.
+func validateDownPhase(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, deadNode bool, onCheck func(CheckStatus)) (*ValidationResults, error) {
+	checks := append(basePreflightChecks(client, cfg, nodeName),
+		// Check 4: rook-ceph-tools deployment exists and is ready
+		preflightCheck{name: "rook-ceph-tools deployment", run: func(ctx context.Context, results *ValidationResults) {
+			if err := validateRookToolsDeployment(ctx, client, cfg.Namespace); err != nil {
+				results.addResult("rook-ceph-tools deployment", false, err, "rook-ceph-tools deployment not ready")
+			} else {
+				results.addResult("rook-ceph-tools deployment", true, nil, "rook-ceph-tools deployment is ready")
+			}
+		}},
 
-	// Check 3: Namespace exists
-	if err := validateNamespaceExists(ctx, client, cfg.Namespace); err != nil {
-		results.addResult("Namespace", false, err, fmt.Sprintf("Namespace %s not found", cfg.Namespace))
-	} else {
-		results.addResult("Namespace", true, nil, fmt.Sprintf("Namespace %s exists", cfg.Namespace))
-	}
+		// Check 5: Ceph health against the configured policy
+		preflightCheck{name: cephHealthCheck, run: func(ctx context.Context, results *ValidationResults) {
+			if r, enforced := validateHealthPolicy(ctx, client, cfg); enforced {
+				results.addResult(r.Check, r.Passed, r.Error, r.Message)
+			}
+		}},
 
-	// Check 4: rook-ceph-tools deployment exists and is ready
-	if err := validateRookToolsDeployment(ctx, client, cfg.Namespace); err != nil {
-		results.addResult("rook-ceph-tools deployment", false, err, "rook-ceph-tools deployment not ready")
-	} else {
-		results.addResult("rook-ceph-tools deployment", true, nil, "rook-ceph-tools deployment is ready")
-	}
+		// Check 6: RBAC permissions (best-effort)
+		preflightCheck{name: "RBAC permissions", run: func(ctx context.Context, results *ValidationResults) {
+			for _, r := range validateRBACPermissions(ctx, client, cfg) {
+				results.addResult(r.Check, r.Passed, r.Error, r.Message)
+			}
+		}},
 
-	// Check 6: RBAC permissions (best-effort)
-	rbacResults := validateRBACPermissions(ctx, client, cfg)
-	for _, r := range rbacResults {
-		results.addResult(r.Check, r.Passed, r.Error, r.Message)
+		// Check 7: CephCluster settings that conflict with manual maintenance (best-effort, warning only)
+		preflightCheck{name: "CephCluster settings", run: func(ctx context.Context, results *ValidationResults) {
+			conflicts, err := CheckRookSettings(ctx, client, cfg)
+			if err != nil {
+				logger.Debug("skipping CephCluster settings check", "error", err)
+			}
+			for _, conflict := range conflicts {
+				results.Warnings = append(results.Warnings, conflict.String())
+			}
+		}},
+
+		// Check 8: Ceph modules that move data while the node is down (best-effort, warning only)
+		preflightCheck{name: "Data movement", run: func(ctx context.Context, results *ValidationResults) {
+			for _, risk := range CheckDataMovementRisks(ctx, client, cfg) {
+				results.Warnings = append(results.Warnings, risk.String())
+			}
+		}},
+
+		// Check 9: Stretch mode quorum and zone state (best-effort, only in stretch clusters)
+		preflightCheck{name: "Stretch mode", run: func(ctx context.Context, results *ValidationResults) {
+			stretch, err := CheckStretchMode(ctx, client, cfg, nodeName)
+			if err != nil {
+				logger.Debug("skipping stretch mode check", "error", err)
+			}
+			if stretch == nil {
+				return
+			}
+			if reason := stretch.Blocking(); reason != "" {
+				results.addResult("Stretch mode", false, errors.New(reason), "Stretch mode is degraded")
+			} else {
+				results.addResult("Stretch mode", true, nil, stretch.Summary())
+			}
+			results.Warnings = append(results.Warnings, stretch.Warnings()...)
+		}},
+
+		// Check 10: The selected CephCluster exists (best-effort)
+		preflightCheck{name: "CephCluster selection", run: func(ctx context.Context, results *ValidationResults) {
+			if r, warning := validateCephClusterSelection(ctx, client, cfg); r != nil {
+				results.addResult(r.Check, r.Passed, r.Error, r.Message)
+			} else if warning != "" {
+				results.Warnings = append(results.Warnings, warning)
+			}
+		}},
+
+		// Check 11: Ceph release and whether the node's OSDs may stop (best-effort, warning only)
+		preflightCheck{name: "Ceph release", run: func(ctx context.Context, results *ValidationResults) {
+			results.Warnings = append(results.Warnings, CheckCephRelease(ctx, client, cfg)...)
+			if deadNode {
+				return
+			}
+			if warning := CheckOSDsOkToStop(ctx, client, cfg, nodeName); warning != "" {
+				results.Warnings = append(results.Warnings, warning)
+			}
+		}},
+
+		// Check 12: Ceph health outside a health policy (best-effort, needs acknowledgment)
+		preflightCheck{name: cephHealthCheck, run: func(ctx context.Context, results *ValidationResults) {
+			if cfg.Policy.GatesHealth() {
+				return
+			}
+			status, err := client.GetCephStatus(ctx, cfg.Namespace)
+			if err != nil {
+				logger.Debug("skipping ceph health check", "error", err)
+				return
+			}
+			if status.IsHealthy() {
+				results.addResult(cephHealthCheck, true, nil, "Ceph reports HEALTH_OK")
+				return
+			}
+			codes := slices.Sorted(maps.Keys(status.Health.Checks))
+			message := fmt.Sprintf("Ceph health is %s", status.Health.Status)
+			if len(codes) > 0 {
+				message += " (" + strings.Join(codes, ", ") + ")"
+			}
+			results.addWarning(cephHealthCheck, errors.New(message), message)
+		}},
+
+		// Check 13: Other nodes in maintenance (best-effort, needs acknowledgment)
+		preflightCheck{name: otherNodesCheck, run: func(ctx context.Context, results *ValidationResults) {
+			info, err := CheckOtherNodesInMaintenance(ctx, client, cfg, nodeName)
+			if err != nil {
+				logger.Debug("skipping other nodes in maintenance check", "error", err)
+				return
+			}
+			if !info.HasWarning() {
+				results.addResult(otherNodesCheck, true, nil, "No other node is in maintenance")
+				return
+			}
+			message := info.Summary()
+			results.addWarning(otherNodesCheck, errors.New(message), message)
+		}},
+	)
+
+	// User-defined checks from preflight.checks
+	custom := customPreflightChecks(cfg, nodeName, config.PhaseDown)
+	if deadNode && len(custom) > 0 {
+		names := make([]string, len(custom))
+		for i, check := range custom {
+			names[i] = check.name
+		}
+		checks = append(checks, preflightCheck{name: "Custom checks", run: func(_ context.Context, results *ValidationResults) {
+			results.Warnings = append(results.Warnings, "Skipped on a dead node: "+strings.Join(names, ", "))
+		}})
+		custom = nil
 	}
+	checks = append(checks, custom...)
 
-	return results, nil
+	return runPreflightChecks(ctx, checks, onCheck), nil
 }
 
 // ValidateUpPhase performs pre-flight checks before up phase
 func ValidateUpPhase(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) (*ValidationResults, error) {
-	results := &ValidationResults{
-		Results:   make([]ValidationResult, 0),
-		AllPassed: true,
-	}
+	return ValidateUpPhaseWithProgress(ctx, client, cfg, nodeName, nil)
+}
 
-	// Check 1: Cluster connectivity
-	results.addResult("Cluster connectivity", true, nil, "Successfully connected to Kubernetes API")
+// ValidateUpPhaseWithProgress runs the up phase pre-flight checks
+// concurrently, calling onCheck as each one finishes. onCheck may be nil.
+func ValidateUpPhaseWithProgress(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, onCheck func(CheckStatus)) (*ValidationResults, error) {
+	checks := append(basePreflightChecks(client, cfg, nodeName), customPreflightChecks(cfg, nodeName, config.PhaseUp)...)
+	return runPreflightChecks(ctx, checks, onCheck), nil
+}
 
-	// Check 2: Node exists
-	if err := validateNodeExists(ctx, client, nodeName); err != nil {
-		results.addResult("Node existence", false, err, fmt.Sprintf("Node %s not found", nodeName))
-	} else {
-		results.addResult("Node existence", true, nil, fmt.Sprintf("Node %s exists", nodeName))
+// basePreflightChecks are the checks both phases run
+func basePreflightChecks(client *k8s.Client, cfg config.Config, nodeName string) []preflightCheck {
+	return []preflightCheck{
+		// Check 1: Cluster connectivity (implicit - client creation validates this)
+		{name: "Cluster connectivity", run: func(_ context.Context, results *ValidationResults) {
+			results.addResult("Cluster connectivity", true, nil, "Successfully connected to Kubernetes API")
+		}},
+
+		// Check 2: Node exists
+		{name: "Node existence", run: func(ctx context.Context, results *ValidationResults) {
+			if err := validateNodeExists(ctx, client, nodeName); err != nil {
+				results.addResult("Node existence", false, err, fmt.Sprintf("Node %s not found", nodeName))
+			} else {
+				results.addResult("Node existence", true, nil, fmt.Sprintf("Node %s exists", nodeName))
+			}
+		}},
+
+		// Check 3: Namespace exists
+		{name: "Namespace", run: func(ctx context.Context, results *ValidationResults) {
+			if err := validateNamespaceExists(ctx, client, cfg.Namespace); err != nil {
+				results.addResult("Namespace", false, err, fmt.Sprintf("Namespace %s not found", cfg.Namespace))
+			} else {
+				results.addResult("Namespace", true, nil, fmt.Sprintf("Namespace %s exists", cfg.Namespace))
+			}
+		}},
 	}
+}
 
-	// Check 3: Namespace exists
-	if err := validateNamespaceExists(ctx, client, cfg.Namespace); err != nil {
-		results.addResult("Namespace", false, err, fmt.Sprintf("Namespace %s not found", cfg.Namespace))
-	} else {
-		results.addResult("Namespace", true, nil, fmt.Sprintf("Namespace %s exists", cfg.Namespace))
+// RefuseControlPlane returns an ErrControlPlaneNode error for a control-plane
+// node: cordoning one has a broader blast radius than a storage worker and is
+// usually a mistake. A node that cannot be read is left to the node
+// existence check.
+func RefuseControlPlane(ctx context.Context, client *k8s.Client, nodeName string) error {
+	node, err := client.GetNode(ctx, nodeName)
+	if err != nil || !k8s.IsControlPlaneNode(node) {
+		return nil
 	}
-
-	return results, nil
+	return crookerrors.Wrap(crookerrors.KindControlPlaneNode,
+		fmt.Errorf("%w: %s", ErrControlPlaneNode, nodeName),
+		"check the node name; if the control-plane node really needs maintenance, rerun 'crook down' with --allow-control-plane")
 }
 
 // validateNodeExists checks if the specified node exists in the cluster
@@ -98,7 +280,7 @@ func validateNodeExists(ctx context.Context, client *k8s.Client, nodeName string
 	_, err := client.GetNode(ctx, nodeName)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
-			return fmt.Errorf("node not found - verify node name is correct")
+			return crookerrors.NodeNotFound(nodeName)
 		}
 		return fmt.Errorf("failed to get node: %w", err)
 	}
@@ -122,14 +304,14 @@ func validateRookToolsDeployment(ctx context.Context, client *k8s.Client, namesp
 	deployment, err := client.GetDeployment(ctx, namespace, "rook-ceph-tools")
 	if err != nil {
 		if kerrors.IsNotFound(err) {
-			return fmt.Errorf("deployment not found - deploy rook-ceph-tools to continue")
+			return crookerrors.ToolboxMissing(namespace, fmt.Errorf("deployment not found - deploy rook-ceph-tools to continue"))
 		}
 		return fmt.Errorf("failed to get deployment: %w", err)
 	}
 
 	// Check if deployment has at least one ready replica
 	if deployment.Status.ReadyReplicas == 0 {
-		return fmt.Errorf("deployment has no ready replicas - wait for rook-ceph-tools to become ready")
+		return crookerrors.ToolboxMissing(namespace, fmt.Errorf("deployment has no ready replicas - wait for rook-ceph-tools to become ready"))
 	}
 
 	return nil
@@ -140,28 +322,9 @@ func validateRBACPermissions(ctx context.Context, client *k8s.Client, cfg config
 	results := make([]ValidationResult, 0)
 
 	// Required permissions for maintenance operations
-	permissions := []authv1.ResourceAttributes{
-		// Cluster-scoped: nodes (patch for cordon/uncordon, get for validation)
-		{Resource: "nodes", Verb: "patch"},
-		{Resource: "nodes", Verb: "get"},
-		// Namespaced: deployments in cluster namespace (for node-pinned deployments)
-		{Group: "apps", Resource: "deployments", Verb: "get", Namespace: cfg.Namespace},
-		{Group: "apps", Resource: "deployments", Verb: "list", Namespace: cfg.Namespace},
-		// Scale subresource for scaling deployments (least-privilege)
-		{Group: "apps", Resource: "deployments", Subresource: "scale", Verb: "get", Namespace: cfg.Namespace},
-		{Group: "apps", Resource: "deployments", Subresource: "scale", Verb: "update", Namespace: cfg.Namespace},
-		// Namespaced: deployments in operator namespace (for rook-ceph-operator)
-		{Group: "apps", Resource: "deployments", Verb: "get", Namespace: cfg.Namespace},
-		{Group: "apps", Resource: "deployments", Subresource: "scale", Verb: "get", Namespace: cfg.Namespace},
-		{Group: "apps", Resource: "deployments", Subresource: "scale", Verb: "update", Namespace: cfg.Namespace},
-		// Namespaced: pods (for exec to rook-ceph-tools)
-		{Resource: "pods", Verb: "list", Namespace: cfg.Namespace},
-		{Resource: "pods", Subresource: "exec", Verb: "create", Namespace: cfg.Namespace},
-	}
-
-	for _, perm := range permissions {
-		checkName := formatPermissionCheck(&perm)
-		allowed, err := checkPermission(ctx, client, &perm)
+	for _, perm := range preflightPermissions(cfg) {
+		checkName := perm.String()
+		allowed, err := checkPermission(ctx, client, perm.attributes())
 		if err != nil {
 			// Best-effort check - don't fail on errors
 			results = append(results, ValidationResult{
@@ -174,7 +337,7 @@ func validateRBACPermissions(ctx context.Context, client *k8s.Client, cfg config
 			results = append(results, ValidationResult{
 				Check:   checkName,
 				Passed:  false,
-				Error:   fmt.Errorf("missing permission: %s", checkName),
+				Error:   crookerrors.RBACDenied(checkName),
 				Message: "Permission denied - contact cluster admin",
 			})
 		} else {
@@ -224,33 +387,88 @@ func checkPermission(ctx context.Context, client *k8s.Client, ra *authv1.Resourc
 
 // addResult adds a validation result and updates AllPassed flag
 func (vr *ValidationResults) addResult(check string, passed bool, err error, message string) {
-	vr.Results = append(vr.Results, ValidationResult{
+	vr.add(ValidationResult{
 		Check:   check,
 		Passed:  passed,
 		Error:   err,
 		Message: message,
 	})
-	if !passed {
+}
+
+// addWarning adds a failed SeverityWarn result, which the operator can
+// acknowledge to proceed
+func (vr *ValidationResults) addWarning(check string, err error, message string) {
+	vr.add(ValidationResult{
+		Check:    check,
+		Error:    err,
+		Message:  message,
+		Severity: SeverityWarn,
+	})
+}
+
+func (vr *ValidationResults) add(r ValidationResult) {
+	vr.Results = append(vr.Results, r)
+	if !r.Passed {
 		vr.AllPassed = false
 	}
 }
 
+// Blocked reports whether any failed check stops the phase
+func (vr *ValidationResults) Blocked() bool {
+	return slices.ContainsFunc(vr.Results, ValidationResult.Blocking)
+}
+
+// Check returns nil when the phase may proceed: every check passed, or only
+// SeverityWarn checks failed and acknowledged is set
+func (vr *ValidationResults) Check(acknowledged bool) error {
+	switch {
+	case vr.Blocked():
+		return fmt.Errorf("%w:\n%s", ErrValidationFailed, vr.String())
+	case !vr.AllPassed && !acknowledged:
+		return fmt.Errorf("%w: %w:\n%s", ErrValidationFailed, ErrAcknowledgmentRequired, vr.String())
+	}
+	for _, r := range vr.Results {
+		if !r.Passed {
+			logger.Warn("pre-flight warning acknowledged", "check", r.Check, "message", r.Message)
+		}
+	}
+	return nil
+}
+
 // String returns a formatted string representation of validation results
 func (vr *ValidationResults) String() string {
 	var sb strings.Builder
+	var hints []string
 	sb.WriteString("Pre-flight validation results:\n")
 	for _, r := range vr.Results {
 		status := "✓"
-		if !r.Passed {
+		switch {
+		case r.Blocking():
 			status = "✗"
+		case !r.Passed:
+			status = "⚠"
 		}
 		fmt.Fprintf(&sb, "  %s %s: %s\n", status, r.Check, r.Message)
+		if hint := crookerrors.Hint(r.Error); !r.Passed && hint != "" && !slices.Contains(hints, hint) {
+			hints = append(hints, hint)
+		}
+	}
+	for _, w := range vr.Warnings {
+		fmt.Fprintf(&sb, "  ⚠ %s\n", w)
 	}
-	if vr.AllPassed {
+	switch {
+	case vr.AllPassed:
 		sb.WriteString("\nAll checks passed - ready to proceed\n")
-	} else {
+	case !vr.Blocked():
+		sb.WriteString("\nSome checks raised warnings - acknowledge them to proceed\n")
+	default:
 		sb.WriteString("\nSome checks failed - resolve issues before proceeding\n")
 	}
+	if !vr.AllPassed {
+		for _, hint := range hints {
+			fmt.Fprintf(&sb, "  hint: %s\n", hint)
+		}
+	}
 	return sb.String()
 }
 
@@ -277,6 +495,26 @@ func (info *OtherNodesMaintenanceInfo) HasWarning() bool {
 	return len(info.NodesInMaintenance) > 0 || info.NoOutFlagSet
 }
 
+// Summary returns the warning on one line, e.g.
+// "worker-2 (cordoned); noout flag already set"
+func (info *OtherNodesMaintenanceInfo) Summary() string {
+	var parts []string
+	for _, node := range info.NodesInMaintenance {
+		var reasons []string
+		if node.Cordoned {
+			reasons = append(reasons, "cordoned")
+		}
+		if node.HasScaledDownDeployments {
+			reasons = append(reasons, "scaled-down deployments")
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", node.NodeName, strings.Join(reasons, ", ")))
+	}
+	if info.NoOutFlagSet {
+		parts = append(parts, "noout flag already set")
+	}
+	return strings.Join(parts, "; ")
+}
+
 // WarningMessage returns a formatted warning message about nodes in maintenance
 func (info *OtherNodesMaintenanceInfo) WarningMessage() string {
 	if !info.HasWarning() {
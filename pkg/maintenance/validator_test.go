@@ -1,8 +1,16 @@
 package maintenance
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
+
+	crookerrors "github.com/andri/crook/pkg/errors"
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestOtherNodesMaintenanceInfo_HasWarning(t *testing.T) {
@@ -182,3 +190,117 @@ func TestMaintenanceStatus_Fields(t *testing.T) {
 		t.Error("HasScaledDownDeployments = true, want false")
 	}
 }
+
+func TestValidationResults_StringHints(t *testing.T) {
+	results := &ValidationResults{AllPassed: true}
+	results.addResult("Node existence", false, crookerrors.NodeNotFound("worker-9"), "Node worker-9 not found")
+	results.addResult("patch nodes [cluster]", false, crookerrors.RBACDenied("patch nodes [cluster]"), "Permission denied - contact cluster admin")
+	results.addResult("get nodes [cluster]", false, crookerrors.RBACDenied("get nodes [cluster]"), "Permission denied - contact cluster admin")
+	results.addResult("Namespace", true, nil, "Namespace rook-ceph exists")
+
+	out := results.String()
+	if got := strings.Count(out, "hint: "); got != 2 {
+		t.Errorf("String() has %d hints, want one per distinct hint (2):\n%s", got, out)
+	}
+	if !strings.Contains(out, "hint: list the node names") {
+		t.Errorf("String() should carry the node hint:\n%s", out)
+	}
+
+	passed := &ValidationResults{AllPassed: true}
+	passed.addResult("Namespace", true, nil, "Namespace rook-ceph exists")
+	if strings.Contains(passed.String(), "hint:") {
+		t.Error("passing results should not print hints")
+	}
+}
+
+func TestValidationResults_Check(t *testing.T) {
+	warn := func(results *ValidationResults) {
+		results.addWarning(cephHealthCheck, errors.New("Ceph health is HEALTH_WARN"), "Ceph health is HEALTH_WARN")
+	}
+	block := func(results *ValidationResults) {
+		results.addResult("Node existence", false, crookerrors.NodeNotFound("worker-9"), "Node worker-9 not found")
+	}
+
+	tests := []struct {
+		name         string
+		add          []func(*ValidationResults)
+		acknowledged bool
+		wantErr      bool
+		wantAck      bool
+	}{
+		{name: "all passed"},
+		{name: "blocking failure", add: []func(*ValidationResults){block}, wantErr: true},
+		{name: "blocking failure ignores acknowledgment", add: []func(*ValidationResults){block, warn}, acknowledged: true, wantErr: true},
+		{name: "unacknowledged warning", add: []func(*ValidationResults){warn}, wantErr: true, wantAck: true},
+		{name: "acknowledged warning", add: []func(*ValidationResults){warn}, acknowledged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := &ValidationResults{AllPassed: true}
+			results.addResult("Namespace", true, nil, "Namespace rook-ceph exists")
+			for _, add := range tt.add {
+				add(results)
+			}
+
+			err := results.Check(tt.acknowledged)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if !errors.Is(err, ErrValidationFailed) {
+				t.Errorf("Check() error = %v, want ErrValidationFailed", err)
+			}
+			if got := errors.Is(err, ErrAcknowledgmentRequired); got != tt.wantAck {
+				t.Errorf("errors.Is(ErrAcknowledgmentRequired) = %v, want %v", got, tt.wantAck)
+			}
+		})
+	}
+}
+
+func TestValidationResults_StringWarnings(t *testing.T) {
+	results := &ValidationResults{AllPassed: true}
+	results.addWarning(otherNodesCheck, errors.New("worker-2 (cordoned)"), "worker-2 (cordoned)")
+
+	if results.Blocked() {
+		t.Error("Blocked() = true, want false for a warning")
+	}
+	out := results.String()
+	if !strings.Contains(out, "⚠") || !strings.Contains(out, "acknowledge") {
+		t.Errorf("String() should mark the warning and ask for acknowledgment:\n%s", out)
+	}
+}
+
+func TestOtherNodesMaintenanceInfo_Summary(t *testing.T) {
+	info := &OtherNodesMaintenanceInfo{
+		NodesInMaintenance: []MaintenanceStatus{
+			{NodeName: "worker-2", Cordoned: true, HasScaledDownDeployments: true},
+		},
+		NoOutFlagSet: true,
+	}
+	want := "worker-2 (cordoned, scaled-down deployments); noout flag already set"
+	if got := info.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestRefuseControlPlane(t *testing.T) {
+	controlPlane := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "cp-1",
+		Labels: map[string]string{"node-role.kubernetes.io/control-plane": ""},
+	}}
+	worker := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}}
+	client := &k8s.Client{Clientset: fake.NewClientset(controlPlane, worker)}
+
+	err := RefuseControlPlane(context.Background(), client, "cp-1")
+	if !errors.Is(err, ErrControlPlaneNode) || crookerrors.Hint(err) == "" {
+		t.Errorf("RefuseControlPlane(cp-1) = %v, want ErrControlPlaneNode with a hint", err)
+	}
+	for _, name := range []string{"worker-1", "missing"} {
+		if err := RefuseControlPlane(context.Background(), client, name); err != nil {
+			t.Errorf("RefuseControlPlane(%s) = %v, want nil", name, err)
+		}
+	}
+}
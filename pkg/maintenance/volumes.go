@@ -0,0 +1,174 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// VolumeSettleTimeout is how long CheckNodeVolumes waits for attachments no
+// pod uses to detach: the attach/detach controller releases them a few
+// seconds after their pods are gone
+const VolumeSettleTimeout = 30 * time.Second
+
+// volumeSettlePollInterval is how often the volumes are listed while settling
+const volumeSettlePollInterval = 2 * time.Second
+
+// NodeVolumes are the volumes tied to a node under maintenance
+type NodeVolumes struct {
+	Node    string           `json:"node"`
+	Volumes []k8s.VolumeInfo `json:"volumes"`
+}
+
+// Attached returns the volumes still attached to the node
+func (v NodeVolumes) Attached() []k8s.VolumeInfo {
+	var attached []k8s.VolumeInfo
+	for _, volume := range v.Volumes {
+		if volume.Attached {
+			attached = append(attached, volume)
+		}
+	}
+	return attached
+}
+
+// Pinned returns the volumes the node affinity of their PV pins to the node;
+// their pods cannot run elsewhere while the node is down
+func (v NodeVolumes) Pinned() []k8s.VolumeInfo {
+	var pinned []k8s.VolumeInfo
+	for _, volume := range v.Volumes {
+		if volume.NodeAffinity && volume.Attachment == "" {
+			pinned = append(pinned, volume)
+		}
+	}
+	return pinned
+}
+
+// Stuck returns the attachments that look stuck, see StuckReason
+func (v NodeVolumes) Stuck() []k8s.VolumeInfo {
+	var stuck []k8s.VolumeInfo
+	for _, volume := range v.Volumes {
+		if StuckReason(volume) != "" {
+			stuck = append(stuck, volume)
+		}
+	}
+	return stuck
+}
+
+// StuckReason explains why an attachment looks stuck: its driver reported an
+// attach or detach error, its detach never completed, or it is attached while
+// no pod on the node uses it, as a mapping left over from before a reboot
+// is. It is empty for a healthy attachment and for unattached volumes.
+func StuckReason(volume k8s.VolumeInfo) string {
+	switch {
+	case volume.Attachment == "":
+		return ""
+	case volume.Error != "":
+		return volume.Error
+	case volume.Detaching:
+		return "detach pending"
+	case volume.Attached && !volume.InUse:
+		return "attached, but no pod on the node uses it"
+	default:
+		return ""
+	}
+}
+
+// VolumeStatus describes a volume's attachment, e.g. "attached, in use" or
+// "stuck: detach pending"
+func VolumeStatus(volume k8s.VolumeInfo) string {
+	switch {
+	case StuckReason(volume) != "":
+		return "stuck: " + StuckReason(volume)
+	case volume.Attached:
+		return "attached, in use"
+	case volume.NodeAffinity:
+		return "pinned to node"
+	default:
+		return "not attached"
+	}
+}
+
+// Warnings describe the volumes that need attention in a phase: before
+// shutdown (ScalePhaseDown) every volume still attached and every volume
+// pinned to the node, after recovery (ScalePhaseUp) the stuck attachments
+func (v NodeVolumes) Warnings(phase string) []string {
+	var warnings []string
+	switch phase {
+	case ScalePhaseDown:
+		if attached := v.Attached(); len(attached) > 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"%d volume(s) still attached to %s (%s): stop or drain the pods using them before shutting the node down, or their rbd mappings can be left stale",
+				len(attached), v.Node, volumeNames(attached)))
+		}
+		if pinned := v.Pinned(); len(pinned) > 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"%d volume(s) pinned to %s by node affinity (%s): their pods cannot run elsewhere while the node is down",
+				len(pinned), v.Node, volumeNames(pinned)))
+		}
+	case ScalePhaseUp:
+		if stuck := v.Stuck(); len(stuck) > 0 {
+			details := make([]string, len(stuck))
+			for i, volume := range stuck {
+				details[i] = fmt.Sprintf("%s: %s", volumeName(volume), StuckReason(volume))
+			}
+			warnings = append(warnings, fmt.Sprintf(
+				"%d volume attachment(s) on %s look stuck (%s): check the CSI node plugin and the node's rbd mappings, as pods using them elsewhere fail to attach",
+				len(stuck), v.Node, strings.Join(details, "; ")))
+		}
+	}
+	return warnings
+}
+
+// CheckNodeVolumes lists the volumes tied to the node. While an attached
+// volume is no longer used by a pod on the node it lists them again, for up
+// to settle, so a detach still under way is not reported as stuck.
+func CheckNodeVolumes(ctx context.Context, client *k8s.Client, nodeName string, settle time.Duration) (*NodeVolumes, error) {
+	deadline := time.Now().Add(settle)
+	for {
+		volumes, err := client.ListNodeVolumes(ctx, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		result := &NodeVolumes{Node: nodeName, Volumes: volumes}
+		if !detachPending(volumes) || !time.Now().Before(deadline) {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, nil
+		case <-time.After(volumeSettlePollInterval):
+		}
+	}
+}
+
+// detachPending reports whether an attachment without an error is detaching
+// or no longer used by a pod on the node
+func detachPending(volumes []k8s.VolumeInfo) bool {
+	for _, volume := range volumes {
+		if volume.Attachment != "" && volume.Error == "" && (volume.Detaching || (volume.Attached && !volume.InUse)) {
+			return true
+		}
+	}
+	return false
+}
+
+// volumeName names a volume by its claim, or its PV when unbound
+func volumeName(volume k8s.VolumeInfo) string {
+	if volume.Claim != "" {
+		return volume.Claim
+	}
+	return volume.PersistentVolume
+}
+
+// volumeNames lists the volumes by name
+func volumeNames(volumes []k8s.VolumeInfo) string {
+	names := make([]string, len(volumes))
+	for i, volume := range volumes {
+		names[i] = volumeName(volume)
+	}
+	return strings.Join(names, ", ")
+}
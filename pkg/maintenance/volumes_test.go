@@ -0,0 +1,73 @@
+package maintenance
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNodeVolumesWarnings(t *testing.T) {
+	inUse := k8s.VolumeInfo{PersistentVolume: "pv-1", Claim: "apps/db", Attachment: "csi-1", Attached: true, InUse: true}
+	unused := k8s.VolumeInfo{PersistentVolume: "pv-2", Claim: "apps/old", Attachment: "csi-2", Attached: true}
+	failed := k8s.VolumeInfo{PersistentVolume: "pv-3", Attachment: "csi-3", Attached: true, InUse: true, Error: "rbd: unmap failed"}
+	pinned := k8s.VolumeInfo{PersistentVolume: "pv-4", Claim: "apps/cache", NodeAffinity: true, InUse: true}
+
+	tests := []struct {
+		name    string
+		volumes []k8s.VolumeInfo
+		phase   string
+		want    []string
+	}{
+		{name: "no volumes before shutdown", phase: ScalePhaseDown},
+		{
+			name:    "attached and pinned before shutdown",
+			volumes: []k8s.VolumeInfo{inUse, unused, pinned},
+			phase:   ScalePhaseDown,
+			want:    []string{"2 volume(s) still attached to worker-1 (apps/db, apps/old)", "1 volume(s) pinned to worker-1 by node affinity (apps/cache)"},
+		},
+		{name: "healthy after recovery", volumes: []k8s.VolumeInfo{inUse, pinned}, phase: ScalePhaseUp},
+		{
+			name:    "stuck after recovery",
+			volumes: []k8s.VolumeInfo{inUse, unused, failed},
+			phase:   ScalePhaseUp,
+			want:    []string{"2 volume attachment(s) on worker-1 look stuck (apps/old: attached, but no pod on the node uses it; pv-3: rbd: unmap failed)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := NodeVolumes{Node: "worker-1", Volumes: tt.volumes}.Warnings(tt.phase)
+			if len(warnings) != len(tt.want) {
+				t.Fatalf("Warnings() = %q, want %d warning(s)", warnings, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(warnings[i], want) {
+					t.Errorf("warning %d = %q, want prefix %q", i, warnings[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckNodeVolumes(t *testing.T) {
+	client := &k8s.Client{Clientset: fake.NewClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+	)}
+
+	volumes, err := CheckNodeVolumes(context.Background(), client, "worker-1", 0)
+	if err != nil {
+		t.Fatalf("CheckNodeVolumes() error = %v", err)
+	}
+	if volumes.Node != "worker-1" || len(volumes.Volumes) != 0 {
+		t.Errorf("CheckNodeVolumes() = %+v, want no volumes on worker-1", volumes)
+	}
+
+	if _, err := CheckNodeVolumes(context.Background(), client, "missing", 0); err == nil {
+		t.Error("CheckNodeVolumes() of a missing node succeeded")
+	}
+}
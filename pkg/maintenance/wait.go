@@ -35,7 +35,20 @@ type WaitOptions struct {
 // WaitForDeploymentScaleDown polls until readyReplicas becomes 0
 // Returns error if timeout is exceeded or context is cancelled
 func WaitForDeploymentScaleDown(ctx context.Context, client *k8s.Client, namespace, name string, opts WaitOptions) error {
-	return waitForCondition(ctx, client, namespace, name, opts,
+	return WaitForWorkloadScaleDown(ctx, client, k8s.WorkloadKindDeployment, namespace, name, opts)
+}
+
+// WaitForDeploymentScaleUp polls until replicas equals targetReplicas
+// Returns error if timeout is exceeded or context is cancelled
+func WaitForDeploymentScaleUp(ctx context.Context, client *k8s.Client, namespace, name string, targetReplicas int32, opts WaitOptions) error {
+	return WaitForWorkloadScaleUp(ctx, client, k8s.WorkloadKindDeployment, namespace, name, targetReplicas, opts)
+}
+
+// WaitForWorkloadScaleDown polls a workload of any kind k8s.ScaleWorkload
+// supports until readyReplicas becomes 0
+// Returns error if timeout is exceeded or context is cancelled
+func WaitForWorkloadScaleDown(ctx context.Context, client *k8s.Client, kind, namespace, name string, opts WaitOptions) error {
+	return waitForCondition(ctx, client, kind, namespace, name, opts,
 		func(status *k8s.DeploymentStatus) bool {
 			return status.ReadyReplicas == 0
 		},
@@ -43,10 +56,11 @@ func WaitForDeploymentScaleDown(ctx context.Context, client *k8s.Client, namespa
 	)
 }
 
-// WaitForDeploymentScaleUp polls until replicas equals targetReplicas
+// WaitForWorkloadScaleUp polls a workload of any kind k8s.ScaleWorkload
+// supports until replicas equals targetReplicas
 // Returns error if timeout is exceeded or context is cancelled
-func WaitForDeploymentScaleUp(ctx context.Context, client *k8s.Client, namespace, name string, targetReplicas int32, opts WaitOptions) error {
-	return waitForCondition(ctx, client, namespace, name, opts,
+func WaitForWorkloadScaleUp(ctx context.Context, client *k8s.Client, kind, namespace, name string, targetReplicas int32, opts WaitOptions) error {
+	return waitForCondition(ctx, client, kind, namespace, name, opts,
 		func(status *k8s.DeploymentStatus) bool {
 			return status.Replicas == targetReplicas && status.ReadyReplicas == targetReplicas
 		},
@@ -54,15 +68,17 @@ func WaitForDeploymentScaleUp(ctx context.Context, client *k8s.Client, namespace
 	)
 }
 
-// waitForCondition is a helper that polls deployment status until condition is met
+// waitForCondition is a helper that polls workload status until condition is met
 func waitForCondition(
 	ctx context.Context,
 	client *k8s.Client,
-	namespace, name string,
+	kind, namespace, name string,
 	opts WaitOptions,
 	condition func(*k8s.DeploymentStatus) bool,
 	conditionDesc string,
 ) error {
+	resource := k8s.WorkloadResource(kind)
+
 	// Apply defaults if not set
 	if opts.PollInterval == 0 {
 		opts.PollInterval = DefaultPollInterval
@@ -83,10 +99,10 @@ func waitForCondition(
 
 	// Check immediately before first poll
 	callCtx, callCancel := context.WithTimeout(timeoutCtx, opts.APITimeout)
-	status, err := client.GetDeploymentStatus(callCtx, namespace, name)
+	status, err := client.GetWorkloadStatus(callCtx, kind, namespace, name)
 	callCancel()
 	if err != nil {
-		return fmt.Errorf("failed to get deployment %s/%s status: %w", namespace, name, err)
+		return fmt.Errorf("failed to get %s %s/%s status: %w", resource, namespace, name, err)
 	}
 
 	// Send initial progress callback
@@ -110,26 +126,26 @@ func waitForCondition(
 			// Attempt a final status fetch with a bounded timeout
 			// This is best-effort and won't block the timeout/cancellation return
 			finalFetchCtx, finalFetchCancel := context.WithTimeout(context.Background(), opts.APITimeout)
-			if fetchedStatus, fetchErr := client.GetDeploymentStatus(finalFetchCtx, namespace, name); fetchErr == nil {
+			if fetchedStatus, fetchErr := client.GetWorkloadStatus(finalFetchCtx, kind, namespace, name); fetchErr == nil {
 				finalStatus = fetchedStatus
 			}
 			finalFetchCancel()
 
 			if timeoutCtx.Err() == context.DeadlineExceeded {
 				return fmt.Errorf(
-					"timeout waiting for deployment %s/%s to %s after %v - current state: replicas=%d, ready=%d, available=%d, updated=%d",
-					namespace, name, conditionDesc, opts.Timeout,
+					"timeout waiting for %s %s/%s to %s after %v - current state: replicas=%d, ready=%d, available=%d, updated=%d",
+					resource, namespace, name, conditionDesc, opts.Timeout,
 					finalStatus.Replicas, finalStatus.ReadyReplicas, finalStatus.AvailableReplicas, finalStatus.UpdatedReplicas,
 				)
 			}
-			return fmt.Errorf("context cancelled while waiting for deployment %s/%s to %s", namespace, name, conditionDesc)
+			return fmt.Errorf("context cancelled while waiting for %s %s/%s to %s", resource, namespace, name, conditionDesc)
 
 		case <-ticker.C:
 			pollCtx, pollCancel := context.WithTimeout(timeoutCtx, opts.APITimeout)
-			status, err = client.GetDeploymentStatus(pollCtx, namespace, name)
+			status, err = client.GetWorkloadStatus(pollCtx, kind, namespace, name)
 			pollCancel()
 			if err != nil {
-				return fmt.Errorf("failed to get deployment %s/%s status: %w", namespace, name, err)
+				return fmt.Errorf("failed to get %s %s/%s status: %w", resource, namespace, name, err)
 			}
 
 			// Send progress callback
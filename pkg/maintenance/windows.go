@@ -0,0 +1,148 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/notify"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// windowsConfigMapPrefix names the ConfigMap holding a node's open
+// maintenance windows, keyed by sink name, so 'crook up' can close the
+// windows 'crook down' opened from another workstation
+const windowsConfigMapPrefix = "crook-windows-"
+
+// openWindow is a maintenance window opened in one tool
+type openWindow struct {
+	// ID is the window's ID in the tool
+	ID string `json:"id"`
+
+	// End is when the tool ends the window on its own
+	End time.Time `json:"end"`
+}
+
+// expired reports whether the tool has already ended the window
+func (w openWindow) expired(now time.Time) bool {
+	return !now.Before(w.End)
+}
+
+// newWindowSinks returns the sinks of the configured tools; replaced in tests
+var newWindowSinks = func(cfg config.NotificationsConfig) []notify.Sink {
+	return notify.NewSinks(cfg, nil)
+}
+
+// openMaintenanceWindows opens a window in every configured tool that has
+// none open for the node, or whose window has expired while the node is
+// still in maintenance. Best-effort: a failing tool is logged and retried by
+// the next phase.
+func openMaintenanceWindows(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) {
+	sinks := newWindowSinks(cfg.Notifications)
+	if len(sinks) == 0 {
+		return
+	}
+
+	windows := loadWindows(ctx, client, cfg, nodeName)
+	now := time.Now().UTC()
+	window := notify.NewWindow(nodeName, cfg.Notifications, now)
+	opened := false
+	for _, sink := range sinks {
+		existing, ok := windows[sink.Name()]
+		if ok && !existing.expired(now) {
+			continue
+		}
+		id, err := sink.OpenWindow(ctx, window)
+		if err != nil {
+			logger.Warn("failed to open maintenance window", "sink", sink.Name(), "node", nodeName, "error", err)
+			continue
+		}
+		if ok {
+			logger.Info("reopened expired maintenance window", "sink", sink.Name(), "node", nodeName, "id", id, "expired", existing.ID)
+		} else {
+			logger.Info("opened maintenance window", "sink", sink.Name(), "node", nodeName, "id", id)
+		}
+		windows[sink.Name()] = openWindow{ID: id, End: window.End}
+		opened = true
+	}
+	if opened {
+		saveWindows(ctx, client, cfg, nodeName, windows)
+	}
+}
+
+// closeMaintenanceWindows closes the node's open windows once it is back in
+// service and deletes their record. Best-effort: windows that fail to close
+// expire on their own.
+func closeMaintenanceWindows(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) {
+	windows := loadWindows(ctx, client, cfg, nodeName)
+	if len(windows) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, sink := range newWindowSinks(cfg.Notifications) {
+		window, ok := windows[sink.Name()]
+		if !ok || window.expired(now) {
+			continue
+		}
+		if err := sink.CloseWindow(ctx, window.ID); err != nil {
+			logger.Warn("failed to close maintenance window", "sink", sink.Name(), "node", nodeName, "id", window.ID, "error", err)
+			continue
+		}
+		logger.Info("closed maintenance window", "sink", sink.Name(), "node", nodeName, "id", window.ID)
+	}
+	// Windows of tools no longer configured, or failing to close, are dropped too
+	if err := client.DeleteConfigMap(ctx, cfg.Namespace, windowsConfigMapPrefix+nodeName); err != nil {
+		logger.Warn("failed to delete maintenance windows", "node", nodeName, "error", err)
+	}
+}
+
+// loadWindows returns the node's open windows by sink name
+func loadWindows(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) map[string]openWindow {
+	windows := map[string]openWindow{}
+	cm, err := client.GetConfigMap(ctx, cfg.Namespace, windowsConfigMapPrefix+nodeName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Warn("failed to load maintenance windows", "node", nodeName, "error", err)
+		}
+		return windows
+	}
+	for name, data := range cm.Data {
+		var window openWindow
+		if err := json.Unmarshal([]byte(data), &window); err != nil {
+			logger.Warn("ignoring unreadable maintenance window", "sink", name, "node", nodeName, "error", err)
+			continue
+		}
+		windows[name] = window
+	}
+	return windows
+}
+
+// saveWindows stores the node's open windows
+func saveWindows(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, windows map[string]openWindow) {
+	data := make(map[string]string, len(windows))
+	for name, window := range windows {
+		encoded, err := json.Marshal(window)
+		if err != nil {
+			logger.Warn("failed to encode maintenance window", "sink", name, "node", nodeName, "error", err)
+			continue
+		}
+		data[name] = string(encoded)
+	}
+	err := client.ApplyConfigMap(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      windowsConfigMapPrefix + nodeName,
+			Namespace: cfg.Namespace,
+			Labels:    map[string]string{k8s.JobLabelName: "crook"},
+		},
+		Data: data,
+	})
+	if err != nil {
+		logger.Warn("failed to save maintenance windows", "node", nodeName, "error", err)
+	}
+}
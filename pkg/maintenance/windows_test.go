@@ -0,0 +1,122 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/notify"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeSink records the windows opened and closed
+type fakeSink struct {
+	name    string
+	openErr error
+	opened  []notify.Window
+	closed  []string
+}
+
+func (s *fakeSink) Name() string { return s.name }
+
+func (s *fakeSink) OpenWindow(_ context.Context, window notify.Window) (string, error) {
+	if s.openErr != nil {
+		return "", s.openErr
+	}
+	s.opened = append(s.opened, window)
+	return s.name + "-window", nil
+}
+
+func (s *fakeSink) CloseWindow(_ context.Context, id string) error {
+	s.closed = append(s.closed, id)
+	return nil
+}
+
+func TestMaintenanceWindows(t *testing.T) {
+	pagerDuty := &fakeSink{name: "pagerduty"}
+	opsgenie := &fakeSink{name: "opsgenie", openErr: errors.New("unauthorized")}
+	original := newWindowSinks
+	newWindowSinks = func(config.NotificationsConfig) []notify.Sink {
+		return []notify.Sink{pagerDuty, opsgenie}
+	}
+	t.Cleanup(func() { newWindowSinks = original })
+
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	cfg := config.DefaultConfig()
+
+	openMaintenanceWindows(ctx, client, cfg, "worker-1")
+	if len(pagerDuty.opened) != 1 || pagerDuty.opened[0].Node != "worker-1" {
+		t.Fatalf("pagerduty windows = %+v, want one for worker-1", pagerDuty.opened)
+	}
+
+	// The up phase opens only the windows still missing
+	opsgenie.openErr = nil
+	openMaintenanceWindows(ctx, client, cfg, "worker-1")
+	if len(pagerDuty.opened) != 1 || len(opsgenie.opened) != 1 {
+		t.Fatalf("opened %d pagerduty and %d opsgenie windows, want 1 each", len(pagerDuty.opened), len(opsgenie.opened))
+	}
+
+	closeMaintenanceWindows(ctx, client, cfg, "worker-1")
+	if len(pagerDuty.closed) != 1 || pagerDuty.closed[0] != "pagerduty-window" || len(opsgenie.closed) != 1 {
+		t.Fatalf("closed pagerduty %v, opsgenie %v", pagerDuty.closed, opsgenie.closed)
+	}
+	if _, err := client.GetConfigMap(ctx, cfg.Namespace, windowsConfigMapPrefix+"worker-1"); !apierrors.IsNotFound(err) {
+		t.Errorf("windows ConfigMap after close: error = %v, want not found", err)
+	}
+
+	// Nothing is left to close
+	closeMaintenanceWindows(ctx, client, cfg, "worker-1")
+	if len(pagerDuty.closed) != 1 {
+		t.Errorf("closed pagerduty windows = %v, want one", pagerDuty.closed)
+	}
+}
+
+func TestMaintenanceWindows_ReopensExpired(t *testing.T) {
+	pagerDuty := &fakeSink{name: "pagerduty"}
+	opsgenie := &fakeSink{name: "opsgenie"}
+	original := newWindowSinks
+	newWindowSinks = func(config.NotificationsConfig) []notify.Sink {
+		return []notify.Sink{pagerDuty, opsgenie}
+	}
+	t.Cleanup(func() { newWindowSinks = original })
+
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	cfg := config.DefaultConfig()
+
+	// A maintenance outlasting window-minutes: the pagerduty window has ended
+	saveWindows(ctx, client, cfg, "worker-1", map[string]openWindow{
+		"pagerduty": {ID: "expired-window", End: time.Now().Add(-time.Minute)},
+		"opsgenie":  {ID: "open-window", End: time.Now().Add(time.Hour)},
+	})
+
+	openMaintenanceWindows(ctx, client, cfg, "worker-1")
+	if len(pagerDuty.opened) != 1 {
+		t.Fatalf("pagerduty windows = %+v, want the expired one reopened", pagerDuty.opened)
+	}
+	if len(opsgenie.opened) != 0 {
+		t.Errorf("opsgenie windows = %+v, want the open one kept", opsgenie.opened)
+	}
+	windows := loadWindows(ctx, client, cfg, "worker-1")
+	if got := windows["pagerduty"]; got.ID != "pagerduty-window" || got.expired(time.Now()) {
+		t.Errorf("pagerduty window = %+v, want the reopened window", got)
+	}
+
+	// Expired windows are not closed again
+	saveWindows(ctx, client, cfg, "worker-1", map[string]openWindow{
+		"pagerduty": {ID: "expired-window", End: time.Now().Add(-time.Minute)},
+		"opsgenie":  {ID: "open-window", End: time.Now().Add(time.Hour)},
+	})
+	closeMaintenanceWindows(ctx, client, cfg, "worker-1")
+	if len(pagerDuty.closed) != 0 {
+		t.Errorf("closed pagerduty windows = %v, want none", pagerDuty.closed)
+	}
+	if len(opsgenie.closed) != 1 || opsgenie.closed[0] != "open-window" {
+		t.Errorf("closed opsgenie windows = %v, want open-window", opsgenie.closed)
+	}
+}
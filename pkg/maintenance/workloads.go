@@ -0,0 +1,210 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	crookerrors "github.com/andri/crook/pkg/errors"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// workloadName formats a workload for error messages: "<resource> namespace/name"
+func workloadName(w k8s.Workload) string {
+	return k8s.WorkloadResource(w.Kind) + " " + w.String()
+}
+
+// replicaCount formats a replica count: "1 replica", "3 replicas"
+func replicaCount(n int32) string {
+	if n == 1 {
+		return "1 replica"
+	}
+	return fmt.Sprintf("%d replicas", n)
+}
+
+// PartialPlan counts the workloads of a down plan a re-run leaves alone
+// because they are already at 0 replicas, and those it still scales down
+type PartialPlan struct {
+	AlreadyDown int
+	ToScale     int
+}
+
+// NewPartialPlan splits the down plan's workloads by their replica count
+func NewPartialPlan(workloads []k8s.Workload) PartialPlan {
+	var plan PartialPlan
+	for _, w := range workloads {
+		if w.Replicas == 0 {
+			plan.AlreadyDown++
+		} else {
+			plan.ToScale++
+		}
+	}
+	return plan
+}
+
+// Partial reports whether the down phase is re-entered with some workloads
+// already scaled down and others still to scale
+func (p PartialPlan) Partial() bool {
+	return p.AlreadyDown > 0 && p.ToScale > 0
+}
+
+// String returns the plan summary: "3 already down, 5 to scale"
+func (p PartialPlan) String() string {
+	return fmt.Sprintf("%d already down, %d to scale", p.AlreadyDown, p.ToScale)
+}
+
+// selectWorkloads returns the workloads named in only (namespace/name), or
+// all of them when only is empty
+func selectWorkloads(workloads []k8s.Workload, only []string) []k8s.Workload {
+	if len(only) == 0 {
+		return workloads
+	}
+	var selected []k8s.Workload
+	for _, w := range workloads {
+		if slices.Contains(only, w.String()) {
+			selected = append(selected, w)
+		}
+	}
+	return selected
+}
+
+// scaleDownWorkloads scales each workload to 0 in the given order and waits
+// for its pods to go. Workloads already at 0 replicas are skipped. Any kind
+// k8s.ScaleWorkload supports can be passed.
+//
+// The replica count is recorded in k8s.OriginalReplicasAnnotation before
+// scaling so the up phase can restore it even if crook stops in between, and
+// the time each workload took is added to history.
+//
+// A workload that fails does not stop the others; the failures are returned
+// as a *crookerrors.MultiError listing every workload's outcome.
+func scaleDownWorkloads(ctx context.Context, client *k8s.Client, workloads []k8s.Workload, history *ScaleHistory, opts DownPhaseOptions) error {
+	result := &crookerrors.MultiError{Op: "scale down"}
+	for _, w := range workloads {
+		if w.Replicas == 0 {
+			logger.Debug("skipping workload already at 0 replicas", "workload", w.DisplayName())
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			result.Skip(workloadName(w), w.String(), err)
+			continue
+		}
+
+		updateProgress(opts.ProgressCallback, "scale-down", fmt.Sprintf("Scaling down %s to 0", w.DisplayName()), w.String())
+
+		recordOriginalReplicas(ctx, client, w)
+
+		started := time.Now()
+		if err := client.ScaleWorkload(ctx, w.Kind, w.Namespace, w.Name, 0); err != nil {
+			result.Fail(workloadName(w), w.String(), fmt.Errorf("failed to scale %s to 0: %w", workloadName(w), err))
+			continue
+		}
+
+		// A dead node's kubelet never reports its pods stopped
+		if opts.DeadNode {
+			result.Succeed(workloadName(w), w.String())
+			continue
+		}
+		if err := WaitForWorkloadScaleDown(ctx, client, w.Kind, w.Namespace, w.Name, opts.WaitOptions); err != nil {
+			result.Fail(workloadName(w), w.String(), fmt.Errorf("failed waiting for %s to scale down: %w", workloadName(w), err))
+			continue
+		}
+		history.Record(ScalePhaseDown, w.String(), time.Since(started))
+		result.Succeed(workloadName(w), w.String())
+	}
+	return result.Err()
+}
+
+// scaleUpWorkloads restores each workload in the given order to its
+// RestoreReplicas count and waits for it to become ready, adding the time it
+// took to history. label prefixes the workload in progress messages (e.g. "MON ").
+//
+// Like scaleDownWorkloads, a failing workload does not stop the others, and
+// the failures are returned as a *crookerrors.MultiError.
+func scaleUpWorkloads(ctx context.Context, client *k8s.Client, workloads []k8s.Workload, label string, persisted PersistedReplicas, history *ScaleHistory, opts UpPhaseOptions) error {
+	result := &crookerrors.MultiError{Op: "scale up"}
+	for _, w := range workloads {
+		if err := ctx.Err(); err != nil {
+			result.Skip(label+workloadName(w), w.String(), err)
+			continue
+		}
+
+		target := persisted.RestoreReplicas(w)
+
+		sendUpProgress(opts.ProgressCallback, "scale-up", fmt.Sprintf("Scaling up %s%s to %s", label, w.DisplayName(), replicaCount(target)), w.String())
+
+		started := time.Now()
+		if err := client.ScaleWorkload(ctx, w.Kind, w.Namespace, w.Name, target); err != nil {
+			result.Fail(label+workloadName(w), w.String(), fmt.Errorf("failed to scale %s%s to %d: %w", label, workloadName(w), target, err))
+			continue
+		}
+
+		if err := WaitForWorkloadScaleUp(ctx, client, w.Kind, w.Namespace, w.Name, target, opts.WaitOptions); err != nil {
+			result.Fail(label+workloadName(w), w.String(), fmt.Errorf("failed waiting for %s%s to scale up: %w", label, workloadName(w), err))
+			continue
+		}
+		history.Record(ScalePhaseUp, w.String(), time.Since(started))
+		result.Succeed(label+workloadName(w), w.String())
+
+		clearOriginalReplicas(ctx, client, w)
+	}
+	return result.Err()
+}
+
+// recordOriginalReplicas annotates the workload with its current replica
+// count. Best-effort: without patch permission the up phase falls back to
+// the maintenance report or 1 replica.
+func recordOriginalReplicas(ctx context.Context, client *k8s.Client, w k8s.Workload) {
+	value := strconv.Itoa(int(w.Replicas))
+	if err := client.SetWorkloadAnnotation(ctx, w.Kind, w.Namespace, w.Name, k8s.OriginalReplicasAnnotation, &value); err != nil {
+		logger.Warn("failed to record original replicas", "workload", w.DisplayName(), "error", err)
+	}
+}
+
+// clearOriginalReplicas removes the annotation once the workload is restored
+func clearOriginalReplicas(ctx context.Context, client *k8s.Client, w k8s.Workload) {
+	if w.OriginalReplicas == 0 {
+		return
+	}
+	if err := client.SetWorkloadAnnotation(ctx, w.Kind, w.Namespace, w.Name, k8s.OriginalReplicasAnnotation, nil); err != nil {
+		logger.Debug("failed to clear original replicas", "workload", w.DisplayName(), "error", err)
+	}
+}
+
+// PersistedReplicas maps namespace/name to the replica count of each
+// deployment in the open maintenance report's pre-maintenance snapshot
+type PersistedReplicas map[string]int32
+
+// LoadPersistedReplicas reads the replica counts recorded by the down phase
+// in the node's maintenance report. Empty when no report is open.
+func LoadPersistedReplicas(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) PersistedReplicas {
+	report, err := LoadReport(ctx, client, cfg, nodeName)
+	if err != nil || report.Before == nil || report.After != nil {
+		return nil
+	}
+	persisted := make(PersistedReplicas, len(report.Before.Deployments))
+	for _, d := range report.Before.Deployments {
+		persisted[d.Namespace+"/"+d.Name] = d.Replicas
+	}
+	return persisted
+}
+
+// RestoreReplicas returns the replica count the up phase restores a workload
+// to, preferring the persisted maintenance report, then the workload's
+// k8s.OriginalReplicasAnnotation, then 1 (Rook-Ceph node-pinned deployments
+// always use 1)
+func (p PersistedReplicas) RestoreReplicas(w k8s.Workload) int32 {
+	if w.Kind == k8s.WorkloadKindDeployment {
+		if replicas, ok := p[w.String()]; ok && replicas > 0 {
+			return replicas
+		}
+	}
+	if w.OriginalReplicas > 0 {
+		return w.OriginalReplicas
+	}
+	return 1
+}
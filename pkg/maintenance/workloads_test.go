@@ -0,0 +1,181 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	crookerrors "github.com/andri/crook/pkg/errors"
+	"github.com/andri/crook/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestPersistedReplicas_RestoreReplicas(t *testing.T) {
+	persisted := PersistedReplicas{"rook-ceph/rook-ceph-mgr-a": 2, "rook-ceph/rook-ceph-osd-0": 0}
+
+	tests := []struct {
+		name     string
+		workload k8s.Workload
+		want     int32
+	}{
+		{
+			name:     "report wins over annotation",
+			workload: k8s.Workload{Kind: k8s.WorkloadKindDeployment, Namespace: "rook-ceph", Name: "rook-ceph-mgr-a", OriginalReplicas: 3},
+			want:     2,
+		},
+		{
+			name:     "annotation when report recorded 0",
+			workload: k8s.Workload{Kind: k8s.WorkloadKindDeployment, Namespace: "rook-ceph", Name: "rook-ceph-osd-0", OriginalReplicas: 3},
+			want:     3,
+		},
+		{
+			name:     "report ignored for statefulsets",
+			workload: k8s.Workload{Kind: k8s.WorkloadKindStatefulSet, Namespace: "rook-ceph", Name: "rook-ceph-mgr-a", OriginalReplicas: 4},
+			want:     4,
+		},
+		{
+			name:     "defaults to 1",
+			workload: k8s.Workload{Kind: k8s.WorkloadKindDeployment, Namespace: "rook-ceph", Name: "rook-ceph-osd-1"},
+			want:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := persisted.RestoreReplicas(tt.workload); got != tt.want {
+				t.Errorf("RestoreReplicas() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPersistedReplicas(t *testing.T) {
+	ctx := context.Background()
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	cfg := config.DefaultConfig()
+
+	if got := LoadPersistedReplicas(ctx, client, cfg, "worker-1"); len(got) != 0 {
+		t.Errorf("LoadPersistedReplicas() without report = %v, want empty", got)
+	}
+
+	before := &ClusterSnapshot{Deployments: []SnapshotDeployment{{Namespace: "rook-ceph", Name: "rook-ceph-mgr-a", Replicas: 2}}}
+	if err := SaveReport(ctx, client, cfg, &MaintenanceReport{Node: "worker-1", Before: before}); err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+	if got := LoadPersistedReplicas(ctx, client, cfg, "worker-1"); got["rook-ceph/rook-ceph-mgr-a"] != 2 {
+		t.Errorf("LoadPersistedReplicas() = %v, want rook-ceph-mgr-a: 2", got)
+	}
+
+	// A completed report belongs to an earlier maintenance window
+	if err := SaveReport(ctx, client, cfg, &MaintenanceReport{Node: "worker-1", Before: before, After: &ClusterSnapshot{}}); err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+	if got := LoadPersistedReplicas(ctx, client, cfg, "worker-1"); len(got) != 0 {
+		t.Errorf("LoadPersistedReplicas() with completed report = %v, want empty", got)
+	}
+}
+
+func TestNewPartialPlan(t *testing.T) {
+	tests := []struct {
+		name        string
+		replicas    []int32
+		wantPartial bool
+		wantString  string
+	}{
+		{"fresh run", []int32{1, 1}, false, "0 already down, 2 to scale"},
+		{"re-entry", []int32{0, 0, 0, 1, 1, 1, 1, 1}, true, "3 already down, 5 to scale"},
+		{"all down", []int32{0, 0}, false, "2 already down, 0 to scale"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workloads := make([]k8s.Workload, 0, len(tt.replicas))
+			for _, replicas := range tt.replicas {
+				workloads = append(workloads, k8s.Workload{Kind: k8s.WorkloadKindDeployment, Replicas: replicas})
+			}
+
+			plan := NewPartialPlan(workloads)
+			if plan.Partial() != tt.wantPartial {
+				t.Errorf("Partial() = %v, want %v", plan.Partial(), tt.wantPartial)
+			}
+			if plan.String() != tt.wantString {
+				t.Errorf("String() = %q, want %q", plan.String(), tt.wantString)
+			}
+		})
+	}
+}
+
+func TestSelectWorkloads(t *testing.T) {
+	workloads := []k8s.Workload{
+		{Namespace: "rook-ceph", Name: "rook-ceph-osd-0"},
+		{Namespace: "rook-ceph", Name: "rook-ceph-osd-1"},
+		{Namespace: "rook-ceph", Name: "rook-ceph-mon-a"},
+	}
+
+	if got := selectWorkloads(workloads, nil); len(got) != 3 {
+		t.Errorf("selectWorkloads() without a selection = %v, want all workloads", got)
+	}
+	got := selectWorkloads(workloads, []string{"rook-ceph/rook-ceph-mon-a", "rook-ceph/rook-ceph-osd-1"})
+	if len(got) != 2 || got[0].Name != "rook-ceph-osd-1" || got[1].Name != "rook-ceph-mon-a" {
+		t.Errorf("selectWorkloads() = %v, want osd-1 and mon-a in workload order", got)
+	}
+}
+
+func TestScaleDownWorkloads_ContinuesPastFailures(t *testing.T) {
+	ctx := context.Background()
+
+	var objects []runtime.Object
+	var workloads []k8s.Workload
+	for _, name := range []string{"rook-ceph-osd-0", "rook-ceph-osd-1", "rook-ceph-osd-2"} {
+		replicas := int32(1)
+		objects = append(objects, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		})
+		workloads = append(workloads, k8s.Workload{Kind: k8s.WorkloadKindDeployment, Namespace: "rook-ceph", Name: name, Replicas: 1})
+	}
+	clientset := fake.NewClientset(objects...)
+	clientset.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		name := action.(k8stesting.GetAction).GetName()
+		return true, &autoscalingv1.Scale{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"}}, nil
+	})
+	clientset.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		scale := action.(k8stesting.UpdateAction).GetObject().(*autoscalingv1.Scale)
+		if scale.Name == "rook-ceph-osd-1" {
+			return true, nil, errors.New("conflict")
+		}
+		return true, scale, nil
+	})
+	client := &k8s.Client{Clientset: clientset}
+
+	opts := DownPhaseOptions{WaitOptions: WaitOptions{PollInterval: time.Millisecond, Timeout: time.Second}}
+	err := scaleDownWorkloads(ctx, client, workloads, &ScaleHistory{}, opts)
+
+	var multi *crookerrors.MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("scaleDownWorkloads() error = %v, want a MultiError", err)
+	}
+	if len(multi.Items) != 3 {
+		t.Fatalf("Items = %+v, want every workload attempted", multi.Items)
+	}
+	if keys := multi.RetryKeys(); len(keys) != 1 || keys[0] != "rook-ceph/rook-ceph-osd-1" {
+		t.Errorf("RetryKeys() = %v, want only osd-1", keys)
+	}
+	if !strings.Contains(err.Error(), "conflict") {
+		t.Errorf("error = %q, want the failure reason", err)
+	}
+}
@@ -0,0 +1,126 @@
+package monitoring
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/tui/components"
+)
+
+// ResourceDelta lists the identities of resources that changed between two
+// refreshes of a source
+type ResourceDelta struct {
+	// Added holds identities that were not present before
+	Added []string
+
+	// Updated holds identities whose data changed
+	Updated []string
+
+	// Removed holds identities that are no longer present
+	Removed []string
+}
+
+// Empty reports whether nothing changed
+func (d ResourceDelta) Empty() bool {
+	return len(d.Added) == 0 && len(d.Updated) == 0 && len(d.Removed) == 0
+}
+
+// Changed returns the identities of added and updated resources
+func (d ResourceDelta) Changed() []string {
+	changed := make([]string, 0, len(d.Added)+len(d.Updated))
+	changed = append(changed, d.Added...)
+	return append(changed, d.Updated...)
+}
+
+// LsChanges holds per-resource deltas carried by an LsMonitorUpdate. Only the
+// source that triggered the update has a non-empty delta.
+type LsChanges struct {
+	Nodes       ResourceDelta
+	Deployments ResourceDelta
+	Pods        ResourceDelta
+	OSDs        ResourceDelta
+}
+
+// NodeKey returns the identity of a node
+func NodeKey(n k8s.NodeInfo) string {
+	return n.Name
+}
+
+// DeploymentKey returns the identity of a deployment
+func DeploymentKey(d k8s.DeploymentInfo) string {
+	return d.Namespace + "/" + d.Name
+}
+
+// PodKey returns the identity of a pod
+func PodKey(p k8s.PodInfo) string {
+	return p.Namespace + "/" + p.Name
+}
+
+// OSDKey returns the identity of an OSD
+func OSDKey(o k8s.OSDInfo) string {
+	return strconv.Itoa(o.ID)
+}
+
+// diffResources compares two snapshots of a source by identity. Items are
+// compared with their Age cleared so the passage of time alone is not a change.
+func diffResources[T any](prev, next []T, key func(T) string, withoutAge func(T) T) ResourceDelta {
+	var delta ResourceDelta
+
+	previous := make(map[string]T, len(prev))
+	for _, item := range prev {
+		previous[key(item)] = item
+	}
+
+	seen := make(map[string]bool, len(next))
+	for _, item := range next {
+		k := key(item)
+		seen[k] = true
+		old, ok := previous[k]
+		switch {
+		case !ok:
+			delta.Added = append(delta.Added, k)
+		case !reflect.DeepEqual(withoutAge(old), withoutAge(item)):
+			delta.Updated = append(delta.Updated, k)
+		}
+	}
+
+	for _, item := range prev {
+		if k := key(item); !seen[k] {
+			delta.Removed = append(delta.Removed, k)
+		}
+	}
+
+	return delta
+}
+
+func nodeWithoutAge(n k8s.NodeInfo) k8s.NodeInfo {
+	n.Age = ""
+	return n
+}
+
+func deploymentWithoutAge(d k8s.DeploymentInfo) k8s.DeploymentInfo {
+	d.Age = ""
+	return d
+}
+
+func podWithoutAge(p k8s.PodInfo) k8s.PodInfo {
+	p.Age = ""
+	return p
+}
+
+func osdUnchanged(o k8s.OSDInfo) k8s.OSDInfo {
+	return o
+}
+
+// headerChanged reports whether the header content differs, ignoring the
+// refresh time carried by every fetch
+func headerChanged(prev, next *components.ClusterHeaderData) bool {
+	if prev == nil || next == nil {
+		return prev != next
+	}
+	a, b := *prev, *next
+	a.LastUpdate, b.LastUpdate = time.Time{}, time.Time{}
+	return !reflect.DeepEqual(a, b)
+}
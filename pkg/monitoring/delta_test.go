@@ -0,0 +1,56 @@
+package monitoring
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func TestDiffResources(t *testing.T) {
+	prev := []k8s.NodeInfo{
+		{Name: "node-a", Status: "Ready", Age: "5d"},
+		{Name: "node-b", Status: "Ready", Age: "5d"},
+		{Name: "node-c", Status: "Ready", Age: "5d"},
+	}
+	next := []k8s.NodeInfo{
+		{Name: "node-a", Status: "Ready", Age: "6d"},
+		{Name: "node-b", Status: "Ready", Cordoned: true, Age: "5d"},
+		{Name: "node-d", Status: "NotReady", Age: "1m"},
+	}
+
+	delta := diffResources(prev, next, NodeKey, nodeWithoutAge)
+
+	if !slices.Equal(delta.Added, []string{"node-d"}) {
+		t.Errorf("Added = %v, want [node-d]", delta.Added)
+	}
+	if !slices.Equal(delta.Updated, []string{"node-b"}) {
+		t.Errorf("Updated = %v, want [node-b] (age changes are ignored)", delta.Updated)
+	}
+	if !slices.Equal(delta.Removed, []string{"node-c"}) {
+		t.Errorf("Removed = %v, want [node-c]", delta.Removed)
+	}
+	if !slices.Equal(delta.Changed(), []string{"node-d", "node-b"}) {
+		t.Errorf("Changed() = %v, want [node-d node-b]", delta.Changed())
+	}
+}
+
+func TestDiffResources_Unchanged(t *testing.T) {
+	osds := []k8s.OSDInfo{{ID: 0, Status: "up"}, {ID: 1, Status: "up"}}
+	delta := diffResources(osds, slices.Clone(osds), OSDKey, osdUnchanged)
+	if !delta.Empty() {
+		t.Errorf("expected empty delta, got %+v", delta)
+	}
+}
+
+func TestResourceKeys(t *testing.T) {
+	if got := DeploymentKey(k8s.DeploymentInfo{Namespace: "rook-ceph", Name: "rook-ceph-osd-1"}); got != "rook-ceph/rook-ceph-osd-1" {
+		t.Errorf("DeploymentKey() = %q", got)
+	}
+	if got := PodKey(k8s.PodInfo{Namespace: "rook-ceph", Name: "rook-ceph-mon-a-1"}); got != "rook-ceph/rook-ceph-mon-a-1" {
+		t.Errorf("PodKey() = %q", got)
+	}
+	if got := OSDKey(k8s.OSDInfo{ID: 12}); got != "12" {
+		t.Errorf("OSDKey() = %q", got)
+	}
+}
@@ -0,0 +1,81 @@
+package monitoring
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/test/util/fakeclock"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newFakeLsMonitor(t *testing.T, ctx context.Context) *LsMonitor {
+	t.Helper()
+	m, err := NewLsMonitor(&LsMonitorConfig{
+		Context:             ctx,
+		Client:              &k8s.Client{Clientset: fake.NewClientset()},
+		Namespace:           "rook-ceph",
+		K8sRefreshInterval:  10 * time.Millisecond,
+		CephRefreshInterval: 10 * time.Millisecond,
+		Clock:               fakeclock.New(testStart),
+	})
+	if err != nil {
+		t.Fatalf("NewLsMonitor() error = %v", err)
+	}
+	return m
+}
+
+// drain reads a subscription until it is closed, failing after a timeout
+func drain(t *testing.T, ch <-chan *LsMonitorUpdate) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("subscription was not closed")
+		}
+	}
+}
+
+func TestLsMonitor_StopClosesSubscriptions(t *testing.T) {
+	m := newFakeLsMonitor(t, context.Background())
+	updates := m.Subscribe(SubscribeOptions{Name: "ls", Policy: KeepLatest})
+	m.Start()
+	extra := m.Subscribe(SubscribeOptions{Name: "flow", Policy: Lossless})
+
+	m.Stop()
+	m.Stop() // idempotent
+
+	drain(t, updates)
+	drain(t, extra)
+}
+
+func TestLsMonitor_ParentCancelShutsDown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := newFakeLsMonitor(t, ctx)
+	updates := m.Subscribe(SubscribeOptions{})
+	m.Start()
+
+	cancel()
+
+	select {
+	case <-m.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("monitor did not shut down after the parent context was cancelled")
+	}
+	drain(t, updates)
+}
+
+func TestLsMonitor_StopBeforeStart(t *testing.T) {
+	m := newFakeLsMonitor(t, context.Background())
+	sub := m.Subscribe(SubscribeOptions{})
+
+	m.Stop()
+
+	drain(t, sub)
+}
@@ -7,12 +7,28 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/andri/crook/internal/clock"
+	"github.com/andri/crook/internal/metrics"
 	"github.com/andri/crook/pkg/k8s"
 	"github.com/andri/crook/pkg/tui/components"
 )
 
+const (
+	// reauthRetryDelay is how soon a poller retries after an authentication
+	// failure; client-go refreshes exec plugin credentials after a 401.
+	reauthRetryDelay = 2 * time.Second
+
+	// maxReauthAttempts is how many consecutive authentication failures a source
+	// may have before it is reported as a regular error.
+	maxReauthAttempts = 5
+
+	// maxPollBackoff caps the delay between fetches of a failing source
+	maxPollBackoff = time.Minute
+)
+
 // LsMonitorConfig holds configuration for the ls monitor
 type LsMonitorConfig struct {
 	// Context is the parent context for all polling operations.
@@ -28,11 +44,18 @@ type LsMonitorConfig struct {
 	// NodeFilter optionally filters resources to a specific node
 	NodeFilter string
 
+	// Selector optionally narrows the nodes, deployments and pods listed by
+	// label and field; the API server applies it
+	Selector k8s.ListSelector
+
 	// K8sRefreshInterval is the refresh interval for Kubernetes API resources (nodes, deployments, pods)
 	K8sRefreshInterval time.Duration
 
 	// CephRefreshInterval is the refresh interval for Ceph CLI operations (OSDs, header)
 	CephRefreshInterval time.Duration
+
+	// Clock drives polling and update timestamps. If nil, the real clock is used.
+	Clock clock.Clock
 }
 
 // LsMonitorUpdate contains the latest monitoring data for the ls TUI
@@ -57,19 +80,52 @@ type LsMonitorUpdate struct {
 
 	// Error holds any error that occurred during fetching
 	Error error
+
+	// Reauthenticating is true while credentials are being refreshed after
+	// an authentication failure
+	Reauthenticating bool
+
+	// NextRetry is set while a source keeps failing and polling backs off;
+	// it holds the earliest time a failing source is fetched again
+	NextRetry time.Time
+
+	// Changes describes what changed in the source that triggered this update.
+	// Slices above always hold the full data; Changes lets views preserve
+	// state and highlight rows instead of treating every refresh as new.
+	Changes LsChanges
 }
 
-// LsMonitor manages background polling of all ls resources
+// LsMonitor manages background polling of all ls resources.
+//
+// Every monitor must be shut down by calling Stop or by cancelling the parent
+// context. Shutdown waits for all polling goroutines to return and then closes
+// every subscription, so consumers blocked on a subscription are released.
 type LsMonitor struct {
-	config   *LsMonitorConfig
-	ctx      context.Context
-	cancel   context.CancelFunc
-	updates  chan *LsMonitorUpdate
-	stopOnce sync.Once
-	wg       sync.WaitGroup
-	mu       sync.RWMutex
-	latest   *LsMonitorUpdate
-	errors   map[string]error
+	config       *LsMonitorConfig
+	clock        clock.Clock
+	ctx          context.Context
+	cancel       context.CancelFunc
+	started      atomic.Bool
+	shutdownOnce sync.Once
+	done         chan struct{}
+	wg           sync.WaitGroup
+	mu           sync.RWMutex
+	latest       *LsMonitorUpdate
+	errors       map[string]error
+
+	// authFailures counts consecutive authentication failures per source
+	authFailures map[string]int
+
+	// retryAt holds the next fetch time of sources that are backing off
+	retryAt map[string]time.Time
+
+	// received records sources that delivered data at least once
+	received map[string]bool
+
+	// subMu guards subscribers and stopped
+	subMu       sync.Mutex
+	subscribers map[<-chan *LsMonitorUpdate]*subscriber
+	stopped     bool
 }
 
 // NewLsMonitor creates a new ls monitoring instance
@@ -82,16 +138,21 @@ func NewLsMonitor(config *LsMonitorConfig) (*LsMonitor, error) {
 		parentCtx = config.Context
 	}
 	ctx, cancel := context.WithCancel(parentCtx)
+	clk := clock.OrReal(config.Clock)
 
 	return &LsMonitor{
-		config:  config,
-		ctx:     ctx,
-		cancel:  cancel,
-		updates: make(chan *LsMonitorUpdate, 10),
+		config: config,
+		clock:  clk,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
 		latest: &LsMonitorUpdate{
-			UpdateTime: time.Now(),
+			UpdateTime: clk.Now(),
 		},
-		errors: make(map[string]error),
+		errors:       make(map[string]error),
+		authFailures: make(map[string]int),
+		retryAt:      make(map[string]time.Time),
+		received:     make(map[string]bool),
 	}, nil
 }
 
@@ -114,8 +175,12 @@ func validateLsMonitorConfig(config *LsMonitorConfig) error {
 	return nil
 }
 
-// Start begins background monitoring of all ls resources
-func (m *LsMonitor) Start() <-chan *LsMonitorUpdate {
+// Start begins background monitoring of all ls resources. Consumers receive
+// the updates through Subscribe, before or after Start, and share the same
+// polling.
+func (m *LsMonitor) Start() {
+	m.started.Store(true)
+
 	// Start individual resource pollers
 	nodesCh := m.startNodesPoller()
 	deploymentsCh := m.startDeploymentsPoller()
@@ -127,15 +192,35 @@ func (m *LsMonitor) Start() <-chan *LsMonitorUpdate {
 	m.wg.Add(1)
 	go m.aggregator(nodesCh, deploymentsCh, podsCh, osdsCh, headerCh)
 
-	return m.updates
+	// Shut down on parent context cancellation as well as on Stop
+	go func() {
+		<-m.ctx.Done()
+		m.shutdown()
+	}()
 }
 
-// Stop gracefully stops all monitoring goroutines
+// Stop cancels polling and blocks until all monitoring goroutines have
+// returned and every subscription is closed. It is safe to call more than
+// once and before Start.
 func (m *LsMonitor) Stop() {
-	m.stopOnce.Do(func() {
-		m.cancel()
+	m.cancel()
+	if !m.started.Load() {
+		m.shutdown()
+	}
+	<-m.done
+}
+
+// Done returns a channel that is closed once the monitor has shut down
+func (m *LsMonitor) Done() <-chan struct{} {
+	return m.done
+}
+
+// shutdown waits for the pollers and closes all subscriptions, once
+func (m *LsMonitor) shutdown() {
+	m.shutdownOnce.Do(func() {
 		m.wg.Wait()
-		close(m.updates)
+		m.closeSubscribers()
+		close(m.done)
 	})
 }
 
@@ -144,31 +229,38 @@ func (m *LsMonitor) GetLatest() *LsMonitorUpdate {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// Return a snapshot; slices and header are shared and must be treated as immutable.
-	return &LsMonitorUpdate{
-		Nodes:       m.latest.Nodes,
-		Deployments: m.latest.Deployments,
-		Pods:        m.latest.Pods,
-		OSDs:        m.latest.OSDs,
-		Header:      m.latest.Header,
-		UpdateTime:  m.latest.UpdateTime,
-		Error:       m.latest.Error,
+	return m.snapshot(LsChanges{})
+}
+
+// LastRefresh returns when the cluster header was last fetched successfully,
+// or the zero time before the first fetch. Header refreshes without changes
+// are not sent on the updates channel, so clocks read the time here.
+func (m *LsMonitor) LastRefresh() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.latest.Header == nil {
+		return time.Time{}
 	}
+	return m.latest.Header.LastUpdate
 }
 
 // runPoller runs a polling loop with the given interval and fetch function.
 // It handles initial fetch, tick-based updates, context cancellation, and error wrapping.
+// Consecutive failures back off exponentially up to maxPollBackoff, except that
+// early authentication failures are retried after reauthRetryDelay so refreshed
+// credentials are picked up quickly. onError receives the delay until the next
+// fetch when the source is backing off, and zero otherwise.
 // This generic helper reduces code duplication across the 5 resource pollers.
 func runPoller[T any](
 	ctx context.Context,
+	clk clock.Clock,
 	updates chan<- T,
 	interval time.Duration,
 	source string,
 	fetch func() (T, error),
-	onError func(string, error),
+	onError func(source string, err error, backoff time.Duration),
 ) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	failures := 0
 
 	// send attempts to send data to the updates channel (non-blocking)
 	send := func(data T) {
@@ -176,37 +268,65 @@ func runPoller[T any](
 		case updates <- data:
 		case <-ctx.Done():
 		default:
+			metrics.DroppedMessage("ls-" + source)
 		}
 	}
 
-	// handleFetch fetches data and either sends it or reports an error
-	handleFetch := func() {
+	// handleFetch fetches data, sends it or reports an error, and returns the
+	// delay until the next fetch
+	handleFetch := func() time.Duration {
+		start := time.Now()
 		data, err := fetch()
 		if err != nil {
 			// Suppress context cancellation errors during shutdown
 			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-				return
+				return interval
+			}
+			metrics.ObserveRefresh(source, time.Since(start), err)
+			failures++
+			delay := pollBackoff(interval, failures)
+			if k8s.IsAuthError(err) && failures < maxReauthAttempts {
+				delay = min(interval, reauthRetryDelay)
+			}
+			backoff := time.Duration(0)
+			if delay > interval {
+				backoff = delay
 			}
-			onError(source, fmt.Errorf("%s: %w", source, err))
-		} else {
-			send(data)
+			onError(source, fmt.Errorf("%s: %w", source, err), backoff)
+			return delay
 		}
+		metrics.ObserveRefresh(source, time.Since(start), nil)
+		failures = 0
+		send(data)
+		return interval
 	}
 
 	// Initial fetch
-	handleFetch()
+	timer := clk.NewTimer(handleFetch())
+	defer timer.Stop()
 
 	// Poll loop
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			handleFetch()
+		case <-timer.C():
+			timer.Reset(handleFetch())
 		}
 	}
 }
 
+// pollBackoff returns the delay before the next fetch after the given number of
+// consecutive failures: the interval doubles per failure after the first, capped
+// at maxPollBackoff (or the interval itself when that is longer).
+func pollBackoff(interval time.Duration, failures int) time.Duration {
+	delay := interval
+	for i := 1; i < failures && delay < maxPollBackoff; i++ {
+		delay *= 2
+	}
+	return max(interval, min(delay, maxPollBackoff))
+}
+
 // startNodesPoller starts background node polling
 func (m *LsMonitor) startNodesPoller() <-chan []k8s.NodeInfo {
 	updates := make(chan []k8s.NodeInfo, 1)
@@ -214,14 +334,30 @@ func (m *LsMonitor) startNodesPoller() <-chan []k8s.NodeInfo {
 	go func() {
 		defer m.wg.Done()
 		defer close(updates)
-		runPoller(m.ctx, updates, m.config.K8sRefreshInterval, "nodes", m.fetchNodes, m.handleError)
+		runPoller(m.ctx, m.clock, updates, m.config.K8sRefreshInterval, "nodes", m.fetchNodes, m.handleError)
 	}()
 	return updates
 }
 
 // fetchNodes fetches all nodes with Ceph pods
 func (m *LsMonitor) fetchNodes() ([]k8s.NodeInfo, error) {
-	return m.config.Client.ListNodesWithCephPods(m.ctx, m.config.Namespace)
+	nodes, err := m.config.Client.ListNodesWithCephPodsMatching(m.ctx, m.config.Namespace, m.config.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply node filter if specified
+	if m.config.NodeFilter == "" {
+		return nodes, nil
+	}
+
+	result := make([]k8s.NodeInfo, 0, 1)
+	for _, n := range nodes {
+		if n.Name == m.config.NodeFilter {
+			result = append(result, n)
+		}
+	}
+	return result, nil
 }
 
 // startDeploymentsPoller starts background deployment polling
@@ -231,14 +367,14 @@ func (m *LsMonitor) startDeploymentsPoller() <-chan []k8s.DeploymentInfo {
 	go func() {
 		defer m.wg.Done()
 		defer close(updates)
-		runPoller(m.ctx, updates, m.config.K8sRefreshInterval, "deployments", m.fetchDeployments, m.handleError)
+		runPoller(m.ctx, m.clock, updates, m.config.K8sRefreshInterval, "deployments", m.fetchDeployments, m.handleError)
 	}()
 	return updates
 }
 
 // fetchDeployments fetches all Ceph deployments
 func (m *LsMonitor) fetchDeployments() ([]k8s.DeploymentInfo, error) {
-	deployments, err := m.config.Client.ListCephDeployments(m.ctx, m.config.Namespace)
+	deployments, err := m.config.Client.ListCephDeploymentsMatching(m.ctx, m.config.Namespace, nil, m.config.Selector)
 	if err != nil {
 		return nil, err
 	}
@@ -264,14 +400,14 @@ func (m *LsMonitor) startPodsPoller() <-chan []k8s.PodInfo {
 	go func() {
 		defer m.wg.Done()
 		defer close(updates)
-		runPoller(m.ctx, updates, m.config.K8sRefreshInterval, "pods", m.fetchPods, m.handleError)
+		runPoller(m.ctx, m.clock, updates, m.config.K8sRefreshInterval, "pods", m.fetchPods, m.handleError)
 	}()
 	return updates
 }
 
 // fetchPods fetches all Ceph pods
 func (m *LsMonitor) fetchPods() ([]k8s.PodInfo, error) {
-	return m.config.Client.ListCephPods(m.ctx, m.config.Namespace, m.config.NodeFilter)
+	return m.config.Client.ListCephPodsMatching(m.ctx, m.config.Namespace, m.config.NodeFilter, m.config.Selector)
 }
 
 // startOSDsPoller starts background OSD polling
@@ -281,7 +417,7 @@ func (m *LsMonitor) startOSDsPoller() <-chan []k8s.OSDInfo {
 	go func() {
 		defer m.wg.Done()
 		defer close(updates)
-		runPoller(m.ctx, updates, m.config.CephRefreshInterval, "osds", m.fetchOSDs, m.handleError)
+		runPoller(m.ctx, m.clock, updates, m.config.CephRefreshInterval, "osds", m.fetchOSDs, m.handleError)
 	}()
 	return updates
 }
@@ -314,7 +450,7 @@ func (m *LsMonitor) startHeaderPoller() <-chan *components.ClusterHeaderData {
 	go func() {
 		defer m.wg.Done()
 		defer close(updates)
-		runPoller(m.ctx, updates, m.config.CephRefreshInterval, "header", m.fetchHeader, m.handleError)
+		runPoller(m.ctx, m.clock, updates, m.config.CephRefreshInterval, "header", m.fetchHeader, m.handleError)
 	}()
 	return updates
 }
@@ -331,7 +467,7 @@ func (m *LsMonitor) fetchHeader() (*components.ClusterHeaderData, error) {
 		OSDs:       status.OSDMap.NumOSDs,
 		OSDsUp:     status.OSDMap.NumUpOSDs,
 		OSDsIn:     status.OSDMap.NumInOSDs,
-		LastUpdate: time.Now(),
+		LastUpdate: m.clock.Now(),
 	}
 
 	// Fetch monitor status
@@ -339,12 +475,28 @@ func (m *LsMonitor) fetchHeader() (*components.ClusterHeaderData, error) {
 	if monErr == nil {
 		headerData.MonsTotal = monStatus.TotalCount
 		headerData.MonsInQuorum = monStatus.InQuorum
+		headerData.TiebreakerMon = monStatus.TiebreakerMon
+	}
+
+	// Fetch stretch mode state, only present in stretch clusters
+	if monErr == nil && monStatus.StretchMode {
+		headerData.StretchMode = "active"
+		if stretch, stretchErr := m.config.Client.GetStretchModeStatus(m.ctx, m.config.Namespace); stretchErr == nil {
+			switch {
+			case stretch.Degraded:
+				headerData.StretchMode = "degraded"
+			case stretch.Recovering:
+				headerData.StretchMode = "recovering"
+			}
+		}
 	}
 
 	// Fetch flags
 	flags, flagsErr := m.config.Client.GetCephFlags(m.ctx, m.config.Namespace)
 	if flagsErr == nil {
 		headerData.NooutSet = flags.NoOut
+		headerData.NooutGroups = flags.NoOutGroups
+		headerData.Flags = flags.ActiveFlags()
 	}
 
 	// Fetch storage usage
@@ -354,6 +506,18 @@ func (m *LsMonitor) fetchHeader() (*components.ClusterHeaderData, error) {
 		headerData.TotalBytes = storage.TotalBytes
 	}
 
+	// Fetch balancer and autoscaler state
+	balancer, balancerErr := m.config.Client.GetBalancerStatus(m.ctx, m.config.Namespace)
+	if balancerErr == nil {
+		headerData.BalancerActive = balancer.Active
+		headerData.BalancerMode = balancer.Mode
+	}
+	autoscale, autoscaleErr := m.config.Client.GetAutoscaleStatus(m.ctx, m.config.Namespace)
+	if autoscaleErr == nil {
+		headerData.AutoscaleOn = len(autoscale.EnabledPools())
+		headerData.AutoscalePools = len(autoscale.Pools)
+	}
+
 	return headerData, nil
 }
 
@@ -377,40 +541,45 @@ func (m *LsMonitor) aggregator(
 				nodesCh = nil
 				continue
 			}
-			m.updateNodes(nodes)
-			m.sendUpdate()
+			if delta, changed := m.updateNodes(nodes); changed {
+				m.sendUpdate(LsChanges{Nodes: delta})
+			}
 
 		case deployments, ok := <-deploymentsCh:
 			if !ok {
 				deploymentsCh = nil
 				continue
 			}
-			m.updateDeployments(deployments)
-			m.sendUpdate()
+			if delta, changed := m.updateDeployments(deployments); changed {
+				m.sendUpdate(LsChanges{Deployments: delta})
+			}
 
 		case pods, ok := <-podsCh:
 			if !ok {
 				podsCh = nil
 				continue
 			}
-			m.updatePods(pods)
-			m.sendUpdate()
+			if delta, changed := m.updatePods(pods); changed {
+				m.sendUpdate(LsChanges{Pods: delta})
+			}
 
 		case osds, ok := <-osdsCh:
 			if !ok {
 				osdsCh = nil
 				continue
 			}
-			m.updateOSDs(osds)
-			m.sendUpdate()
+			if delta, changed := m.updateOSDs(osds); changed {
+				m.sendUpdate(LsChanges{OSDs: delta})
+			}
 
 		case header, ok := <-headerCh:
 			if !ok {
 				headerCh = nil
 				continue
 			}
-			m.updateHeader(header)
-			m.sendUpdate()
+			if m.updateHeader(header) {
+				m.sendUpdate(LsChanges{})
+			}
 		}
 
 		// Exit if all channels are closed
@@ -420,69 +589,160 @@ func (m *LsMonitor) aggregator(
 	}
 }
 
-// updateNodes updates the nodes in the latest cache
-func (m *LsMonitor) updateNodes(nodes []k8s.NodeInfo) {
+// updateNodes updates the nodes in the latest cache and reports what changed.
+// changed is false when the refresh brought nothing new to display.
+func (m *LsMonitor) updateNodes(nodes []k8s.NodeInfo) (delta ResourceDelta, changed bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	delta = diffResources(m.latest.Nodes, nodes, NodeKey, nodeWithoutAge)
 	m.latest.Nodes = nodes
-	m.clearErrorLocked("nodes")
-	m.latest.UpdateTime = time.Now()
+	cleared := m.clearErrorLocked("nodes")
+	m.latest.UpdateTime = m.clock.Now()
+	if m.markReceivedLocked("nodes") {
+		// The first delivery is initial data, not a change
+		return ResourceDelta{}, true
+	}
+	return delta, cleared || !delta.Empty()
 }
 
-// updateDeployments updates the deployments in the latest cache
-func (m *LsMonitor) updateDeployments(deployments []k8s.DeploymentInfo) {
+// updateDeployments updates the deployments in the latest cache and reports what changed.
+// changed is false when the refresh brought nothing new to display.
+func (m *LsMonitor) updateDeployments(deployments []k8s.DeploymentInfo) (delta ResourceDelta, changed bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	delta = diffResources(m.latest.Deployments, deployments, DeploymentKey, deploymentWithoutAge)
 	m.latest.Deployments = deployments
-	m.clearErrorLocked("deployments")
-	m.latest.UpdateTime = time.Now()
+	cleared := m.clearErrorLocked("deployments")
+	m.latest.UpdateTime = m.clock.Now()
+	if m.markReceivedLocked("deployments") {
+		// The first delivery is initial data, not a change
+		return ResourceDelta{}, true
+	}
+	return delta, cleared || !delta.Empty()
 }
 
-// updatePods updates the pods in the latest cache
-func (m *LsMonitor) updatePods(pods []k8s.PodInfo) {
+// updatePods updates the pods in the latest cache and reports what changed.
+// changed is false when the refresh brought nothing new to display.
+func (m *LsMonitor) updatePods(pods []k8s.PodInfo) (delta ResourceDelta, changed bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	delta = diffResources(m.latest.Pods, pods, PodKey, podWithoutAge)
 	m.latest.Pods = pods
-	m.clearErrorLocked("pods")
-	m.latest.UpdateTime = time.Now()
+	cleared := m.clearErrorLocked("pods")
+	m.latest.UpdateTime = m.clock.Now()
+	if m.markReceivedLocked("pods") {
+		// The first delivery is initial data, not a change
+		return ResourceDelta{}, true
+	}
+	return delta, cleared || !delta.Empty()
 }
 
-// updateOSDs updates the OSDs in the latest cache
-func (m *LsMonitor) updateOSDs(osds []k8s.OSDInfo) {
+// updateOSDs updates the OSDs in the latest cache and reports what changed.
+// changed is false when the refresh brought nothing new to display.
+func (m *LsMonitor) updateOSDs(osds []k8s.OSDInfo) (delta ResourceDelta, changed bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	delta = diffResources(m.latest.OSDs, osds, OSDKey, osdUnchanged)
 	m.latest.OSDs = osds
-	m.clearErrorLocked("osds")
-	m.latest.UpdateTime = time.Now()
+	cleared := m.clearErrorLocked("osds")
+	m.latest.UpdateTime = m.clock.Now()
+	if m.markReceivedLocked("osds") {
+		// The first delivery is initial data, not a change
+		return ResourceDelta{}, true
+	}
+	return delta, cleared || !delta.Empty()
 }
 
-// updateHeader updates the header in the latest cache
-func (m *LsMonitor) updateHeader(header *components.ClusterHeaderData) {
+// updateHeader updates the header in the latest cache and reports whether it
+// changed. A refresh that only advances LastUpdate is not a change; consumers
+// read the refresh time through LastRefresh instead.
+func (m *LsMonitor) updateHeader(header *components.ClusterHeaderData) bool {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	changed := headerChanged(m.latest.Header, header)
 	m.latest.Header = header
-	m.clearErrorLocked("header")
-	m.latest.UpdateTime = time.Now()
+	cleared := m.clearErrorLocked("header")
+	m.latest.UpdateTime = m.clock.Now()
+	return changed || cleared
 }
 
-func (m *LsMonitor) clearErrorLocked(source string) {
+// clearErrorLocked clears the error state of a source after a successful fetch
+// and reports whether there was any to clear.
+func (m *LsMonitor) clearErrorLocked(source string) bool {
+	_, hadError := m.errors[source]
+	hadFailures := m.authFailures[source] > 0 || !m.retryAt[source].IsZero()
+
+	delete(m.authFailures, source)
+	delete(m.retryAt, source)
+	m.latest.Reauthenticating = m.reauthenticatingLocked()
+	m.latest.NextRetry = m.nextRetryLocked()
 	if m.errors == nil {
-		return
+		return hadFailures
 	}
 	delete(m.errors, source)
 	m.latest.Error = combineErrors(m.errors)
+	return hadError || hadFailures
+}
+
+// markReceivedLocked records that a source delivered data and reports whether
+// it was the first delivery.
+func (m *LsMonitor) markReceivedLocked(source string) bool {
+	if m.received == nil {
+		m.received = make(map[string]bool)
+	}
+	first := !m.received[source]
+	m.received[source] = true
+	return first
 }
 
-// updateError updates the error in the latest cache
-func (m *LsMonitor) updateError(source string, err error) {
+// updateError updates the error in the latest cache. Authentication failures
+// are reported as re-authenticating rather than as errors until they persist
+// for maxReauthAttempts consecutive fetches. A non-zero backoff marks the
+// source as degraded until its next fetch.
+func (m *LsMonitor) updateError(source string, err error, backoff time.Duration) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if m.errors == nil {
 		m.errors = make(map[string]error)
 	}
-	m.errors[source] = err
+	if m.authFailures == nil {
+		m.authFailures = make(map[string]int)
+	}
+	if m.retryAt == nil {
+		m.retryAt = make(map[string]time.Time)
+	}
+
+	if backoff > 0 {
+		m.retryAt[source] = m.clock.Now().Add(backoff)
+	} else {
+		delete(m.retryAt, source)
+	}
+
+	if k8s.IsAuthError(err) {
+		m.authFailures[source]++
+	} else {
+		delete(m.authFailures, source)
+	}
+
+	if failures := m.authFailures[source]; failures > 0 && failures < maxReauthAttempts {
+		delete(m.errors, source)
+	} else {
+		m.errors[source] = err
+	}
 	m.latest.Error = combineErrors(m.errors)
-	m.latest.UpdateTime = time.Now()
+	m.latest.Reauthenticating = m.reauthenticatingLocked()
+	m.latest.NextRetry = m.nextRetryLocked()
+	m.latest.UpdateTime = m.clock.Now()
+}
+
+// reauthenticatingLocked reports whether any source is waiting for refreshed credentials
+func (m *LsMonitor) reauthenticatingLocked() bool {
+	for _, failures := range m.authFailures {
+		if failures < maxReauthAttempts {
+			return true
+		}
+	}
+	return false
 }
 
 func combineErrors(errs map[string]error) error {
@@ -507,32 +767,59 @@ func combineErrors(errs map[string]error) error {
 	return errors.Join(list...)
 }
 
+// nextRetryLocked returns the earliest retry time of backing-off sources
+func (m *LsMonitor) nextRetryLocked() time.Time {
+	var next time.Time
+	for _, at := range m.retryAt {
+		if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	return next
+}
+
 // handleError updates the error state and sends an update directly.
 // This simplifies error handling by avoiding an intermediate channel.
-func (m *LsMonitor) handleError(source string, err error) {
-	m.updateError(source, err)
-	m.sendUpdate()
+func (m *LsMonitor) handleError(source string, err error, backoff time.Duration) {
+	m.updateError(source, err, backoff)
+	m.sendUpdate(LsChanges{})
 }
 
-// sendUpdate sends the current state to the updates channel
-func (m *LsMonitor) sendUpdate() {
+// sendUpdate publishes the current state and the triggering changes to all subscribers
+func (m *LsMonitor) sendUpdate(changes LsChanges) {
+	// Holding subMu across the snapshot keeps a concurrent Subscribe from
+	// receiving a newer state before this one
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+
 	m.mu.RLock()
-	update := &LsMonitorUpdate{
-		Nodes:       m.latest.Nodes,
-		Deployments: m.latest.Deployments,
-		Pods:        m.latest.Pods,
-		OSDs:        m.latest.OSDs,
-		Header:      m.latest.Header,
-		UpdateTime:  m.latest.UpdateTime,
-		Error:       m.latest.Error,
-	}
+	update := m.snapshot(changes)
 	m.mu.RUnlock()
 
-	// Non-blocking send
-	select {
-	case m.updates <- update:
-	case <-m.ctx.Done():
-	default:
-		// Channel full, skip this update
+	for _, sub := range m.subscribers {
+		sub.deliver(update)
+	}
+}
+
+// snapshot copies the latest state; callers must hold mu for reading.
+// Slices and header are shared and must be treated as immutable.
+func (m *LsMonitor) snapshot(changes LsChanges) *LsMonitorUpdate {
+	return &LsMonitorUpdate{
+		Nodes:            m.latest.Nodes,
+		Deployments:      m.latest.Deployments,
+		Pods:             m.latest.Pods,
+		OSDs:             m.latest.OSDs,
+		Header:           m.latest.Header,
+		UpdateTime:       m.latest.UpdateTime,
+		Error:            m.latest.Error,
+		Reauthenticating: m.latest.Reauthenticating,
+		NextRetry:        m.latest.NextRetry,
+		Changes:          changes,
 	}
 }
+
+// hasData reports whether any source delivered data or failed; callers must
+// hold mu for reading
+func (m *LsMonitor) hasData() bool {
+	return len(m.received) > 0 || m.latest.Error != nil
+}
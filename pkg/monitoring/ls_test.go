@@ -0,0 +1,222 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/tui/components"
+	"github.com/andri/crook/test/util/fakeclock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// testStart is the time of the fake clock of test monitors
+var testStart = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestLsMonitor() *LsMonitor {
+	return &LsMonitor{
+		clock:        fakeclock.New(testStart),
+		latest:       &LsMonitorUpdate{},
+		errors:       make(map[string]error),
+		authFailures: make(map[string]int),
+		retryAt:      make(map[string]time.Time),
+		received:     make(map[string]bool),
+	}
+}
+
+func TestLsMonitor_AuthErrorsReportReauthenticating(t *testing.T) {
+	m := newTestLsMonitor()
+	authErr := apierrors.NewUnauthorized("token expired")
+
+	m.updateError("nodes", authErr, 0)
+	latest := m.GetLatest()
+	if !latest.Reauthenticating {
+		t.Error("expected Reauthenticating after an auth error")
+	}
+	if latest.Error != nil {
+		t.Errorf("expected auth error to be hidden while re-authenticating, got %v", latest.Error)
+	}
+
+	m.updateNodes(nil)
+	latest = m.GetLatest()
+	if latest.Reauthenticating || latest.Error != nil {
+		t.Errorf("expected recovery to clear state, got reauth=%v err=%v", latest.Reauthenticating, latest.Error)
+	}
+}
+
+func TestLsMonitor_PersistentAuthErrorsSurface(t *testing.T) {
+	m := newTestLsMonitor()
+	authErr := apierrors.NewUnauthorized("token expired")
+
+	for range maxReauthAttempts {
+		m.updateError("nodes", authErr, 0)
+	}
+	latest := m.GetLatest()
+	if latest.Reauthenticating {
+		t.Error("expected Reauthenticating to stop after repeated failures")
+	}
+	if latest.Error == nil {
+		t.Error("expected persistent auth failure to be reported as an error")
+	}
+}
+
+func TestLsMonitor_OtherErrorsAreReported(t *testing.T) {
+	m := newTestLsMonitor()
+
+	m.updateError("osds", errors.New("connection refused"), 0)
+	latest := m.GetLatest()
+	if latest.Reauthenticating {
+		t.Error("expected non-auth error not to trigger re-authentication")
+	}
+	if latest.Error == nil {
+		t.Error("expected error to be reported")
+	}
+}
+
+func TestPollBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		failures int
+		want     time.Duration
+	}{
+		{name: "first failure keeps interval", interval: 2 * time.Second, failures: 1, want: 2 * time.Second},
+		{name: "second failure doubles", interval: 2 * time.Second, failures: 2, want: 4 * time.Second},
+		{name: "fourth failure", interval: 2 * time.Second, failures: 4, want: 16 * time.Second},
+		{name: "capped", interval: 2 * time.Second, failures: 20, want: maxPollBackoff},
+		{name: "long interval is not shortened", interval: 2 * time.Minute, failures: 3, want: 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pollBackoff(tt.interval, tt.failures); got != tt.want {
+				t.Errorf("pollBackoff(%v, %d) = %v, want %v", tt.interval, tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLsMonitor_BackoffReportsNextRetry(t *testing.T) {
+	m := newTestLsMonitor()
+
+	m.updateError("osds", errors.New("timeout"), 8*time.Second)
+	m.updateError("header", errors.New("timeout"), 30*time.Second)
+	latest := m.GetLatest()
+	if latest.NextRetry.IsZero() {
+		t.Fatal("expected NextRetry while sources back off")
+	}
+	if want := testStart.Add(8 * time.Second); !latest.NextRetry.Equal(want) {
+		t.Errorf("NextRetry = %v, want the earliest retry %v", latest.NextRetry, want)
+	}
+
+	m.updateOSDs(nil)
+	m.updateHeader(nil)
+	if latest = m.GetLatest(); !latest.NextRetry.IsZero() {
+		t.Errorf("expected NextRetry to clear after recovery, got %v", latest.NextRetry)
+	}
+}
+
+func TestLsMonitor_HeaderRefreshWithoutChanges(t *testing.T) {
+	m := newTestLsMonitor()
+	first := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	header := &components.ClusterHeaderData{Health: "HEALTH_OK", OSDs: 3, HealthMessages: []string{}, LastUpdate: first}
+
+	if !m.updateHeader(header) {
+		t.Error("first header should be reported as changed")
+	}
+
+	refreshed := *header
+	refreshed.LastUpdate = first.Add(time.Minute)
+	if m.updateHeader(&refreshed) {
+		t.Error("header refresh with identical content should not be reported as changed")
+	}
+	if got := m.LastRefresh(); !got.Equal(refreshed.LastUpdate) {
+		t.Errorf("LastRefresh() = %v, want %v", got, refreshed.LastUpdate)
+	}
+
+	warn := refreshed
+	warn.Health = "HEALTH_WARN"
+	if !m.updateHeader(&warn) {
+		t.Error("health change should be reported as changed")
+	}
+}
+
+func TestLsMonitor_UpdateReportsChanges(t *testing.T) {
+	m := newTestLsMonitor()
+	nodes := []k8s.NodeInfo{{Name: "node-a", Status: "Ready"}}
+
+	delta, changed := m.updateNodes(nodes)
+	if !changed || !delta.Empty() {
+		t.Errorf("first delivery: changed=%v delta=%+v, want changed with empty delta", changed, delta)
+	}
+
+	if _, changed = m.updateNodes([]k8s.NodeInfo{{Name: "node-a", Status: "Ready"}}); changed {
+		t.Error("identical refresh should not be reported as changed")
+	}
+
+	delta, changed = m.updateNodes([]k8s.NodeInfo{{Name: "node-a", Status: "NotReady"}})
+	if !changed || len(delta.Updated) != 1 {
+		t.Errorf("status change: changed=%v delta=%+v", changed, delta)
+	}
+
+	m.updateError("nodes", errors.New("timeout"), 0)
+	if _, changed = m.updateNodes([]k8s.NodeInfo{{Name: "node-a", Status: "NotReady"}}); !changed {
+		t.Error("recovery from an error should be reported as changed")
+	}
+}
+
+func TestRunPoller_BacksOffOnFakeClock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clk := fakeclock.New(testStart)
+	interval := 2 * time.Second
+
+	fetched := make(chan struct{})
+	fetch := func() (int, error) {
+		fetched <- struct{}{}
+		return 0, errors.New("timeout")
+	}
+	backoffs := make(chan time.Duration, 10)
+	onError := func(_ string, _ error, backoff time.Duration) {
+		backoffs <- backoff
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runPoller(ctx, clk, make(chan int, 1), interval, "osds", fetch, onError)
+	}()
+
+	// waitFetch waits for the next fetch and the poller to arm its timer
+	waitFetch := func() time.Duration {
+		t.Helper()
+		<-fetched
+		backoff := <-backoffs
+		clk.BlockUntil(1)
+		return backoff
+	}
+
+	// The first failure retries at the interval
+	if backoff := waitFetch(); backoff != 0 {
+		t.Errorf("first failure backoff = %v, want 0", backoff)
+	}
+	clk.Advance(interval)
+
+	// The second failure doubles the delay
+	if backoff := waitFetch(); backoff != 2*interval {
+		t.Errorf("second failure backoff = %v, want %v", backoff, 2*interval)
+	}
+	clk.Advance(interval)
+	select {
+	case <-fetched:
+		t.Fatal("poller fetched before its backoff elapsed")
+	default:
+	}
+	clk.Advance(interval)
+	waitFetch()
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,12 @@
+package monitoring
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+// TestMain fails the package when pollers or subscriptions outlive the tests
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
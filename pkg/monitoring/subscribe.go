@@ -0,0 +1,202 @@
+package monitoring
+
+import (
+	"sync"
+
+	"github.com/andri/crook/internal/metrics"
+)
+
+// defaultSubscriberBuffer is the channel capacity of a subscription when none is given
+const defaultSubscriberBuffer = 10
+
+// BackpressurePolicy decides what happens to updates for a subscriber whose
+// channel is full
+type BackpressurePolicy int
+
+const (
+	// DropNewest discards the update that does not fit; queued updates are
+	// delivered in order
+	DropNewest BackpressurePolicy = iota
+
+	// KeepLatest discards the oldest queued update to make room for the
+	// newest. Every update carries the full data, so a slow consumer only
+	// loses the Changes of the discarded updates.
+	KeepLatest
+
+	// Lossless queues updates that do not fit the buffer without bound and
+	// delivers every one of them in order. The consumer must keep reading or
+	// Unsubscribe, or the queue grows for as long as the monitor runs.
+	Lossless
+)
+
+// SubscribeOptions configures a subscription to monitor updates
+type SubscribeOptions struct {
+	// Name identifies the subscriber in the dropped message metrics
+	Name string
+
+	// Buffer is the channel capacity (default: 10)
+	Buffer int
+
+	// Policy handles updates that do not fit the buffer
+	Policy BackpressurePolicy
+}
+
+// subscriber is a registered consumer of monitor updates
+type subscriber struct {
+	ch     chan *LsMonitorUpdate
+	name   string
+	policy BackpressurePolicy
+
+	// Lossless subscribers queue their updates; forward moves them to ch
+	// and closes ch once closing is closed
+	mu      sync.Mutex
+	queue   []*LsMonitorUpdate
+	wake    chan struct{}
+	closing chan struct{}
+}
+
+// Subscribe registers a consumer and returns the channel its updates are
+// delivered on. When data has already been received, the current state is
+// queued immediately so late subscribers do not wait for the next change.
+// The channel is closed by Unsubscribe or Stop.
+func (m *LsMonitor) Subscribe(opts SubscribeOptions) <-chan *LsMonitorUpdate {
+	buffer := opts.Buffer
+	if buffer <= 0 {
+		buffer = defaultSubscriberBuffer
+	}
+	name := opts.Name
+	if name == "" {
+		name = "subscriber"
+	}
+	sub := &subscriber{
+		ch:     make(chan *LsMonitorUpdate, buffer),
+		name:   name,
+		policy: opts.Policy,
+	}
+
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	if m.stopped {
+		close(sub.ch)
+		return sub.ch
+	}
+	if sub.policy == Lossless {
+		sub.wake = make(chan struct{}, 1)
+		sub.closing = make(chan struct{})
+		go sub.forward()
+	}
+	if m.subscribers == nil {
+		m.subscribers = make(map[<-chan *LsMonitorUpdate]*subscriber)
+	}
+	m.subscribers[sub.ch] = sub
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.hasData() {
+		sub.deliver(m.snapshot(LsChanges{}))
+	}
+	return sub.ch
+}
+
+// Unsubscribe stops delivery to a channel returned by Subscribe and closes it.
+// Unknown or already closed channels are ignored.
+func (m *LsMonitor) Unsubscribe(ch <-chan *LsMonitorUpdate) {
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	sub, ok := m.subscribers[ch]
+	if !ok {
+		return
+	}
+	delete(m.subscribers, ch)
+	sub.close()
+}
+
+// SubscriberCount returns the number of active subscriptions
+func (m *LsMonitor) SubscriberCount() int {
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	return len(m.subscribers)
+}
+
+// closeSubscribers closes all subscriber channels; later subscriptions are
+// returned closed
+func (m *LsMonitor) closeSubscribers() {
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	m.stopped = true
+	for ch, sub := range m.subscribers {
+		delete(m.subscribers, ch)
+		sub.close()
+	}
+}
+
+// close ends delivery and closes the subscriber's channel; a Lossless
+// subscriber's channel is closed by forward, discarding what is still queued
+func (s *subscriber) close() {
+	if s.policy == Lossless {
+		close(s.closing)
+		return
+	}
+	close(s.ch)
+}
+
+// forward delivers a Lossless subscriber's queue in order, blocking on the
+// consumer instead of on the monitor
+func (s *subscriber) forward() {
+	defer close(s.ch)
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.wake:
+				continue
+			case <-s.closing:
+				return
+			}
+		}
+		next := s.queue[0]
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- next:
+		case <-s.closing:
+			return
+		}
+	}
+}
+
+// deliver sends an update according to the subscriber's backpressure policy
+func (s *subscriber) deliver(update *LsMonitorUpdate) {
+	if s.policy == Lossless {
+		s.mu.Lock()
+		s.queue = append(s.queue, update)
+		s.mu.Unlock()
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+		return
+	}
+
+	select {
+	case s.ch <- update:
+		return
+	default:
+	}
+
+	if s.policy == KeepLatest {
+		// Make room by discarding the oldest queued update
+		select {
+		case <-s.ch:
+		default:
+		}
+		select {
+		case s.ch <- update:
+		default:
+		}
+	}
+	metrics.DroppedMessage("ls-" + s.name)
+}
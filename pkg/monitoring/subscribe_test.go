@@ -0,0 +1,143 @@
+package monitoring
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func changesFor(name string) LsChanges {
+	return LsChanges{Nodes: ResourceDelta{Added: []string{name}}}
+}
+
+func TestLsMonitor_SubscribersShareUpdates(t *testing.T) {
+	m := newTestLsMonitor()
+	first := m.Subscribe(SubscribeOptions{Name: "ls"})
+	second := m.Subscribe(SubscribeOptions{Name: "flow"})
+
+	if len(first) != 0 {
+		t.Fatal("subscription before any data should start empty")
+	}
+
+	m.sendUpdate(changesFor("node-a"))
+
+	for i, ch := range []<-chan *LsMonitorUpdate{first, second} {
+		select {
+		case update := <-ch:
+			if got := update.Changes.Nodes.Added; len(got) != 1 || got[0] != "node-a" {
+				t.Errorf("subscriber %d: changes = %+v", i, update.Changes)
+			}
+		default:
+			t.Errorf("subscriber %d received no update", i)
+		}
+	}
+}
+
+func TestLsMonitor_LateSubscriberGetsSnapshot(t *testing.T) {
+	m := newTestLsMonitor()
+	m.updateNodes([]k8s.NodeInfo{{Name: "node-a"}})
+
+	ch := m.Subscribe(SubscribeOptions{})
+	select {
+	case update := <-ch:
+		if len(update.Nodes) != 1 {
+			t.Errorf("snapshot nodes = %v, want node-a", update.Nodes)
+		}
+	default:
+		t.Fatal("late subscriber should receive the current state")
+	}
+}
+
+func TestLsMonitor_BackpressurePolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy BackpressurePolicy
+		want   string
+	}{
+		{name: "drop newest keeps queued update", policy: DropNewest, want: "first"},
+		{name: "keep latest replaces queued update", policy: KeepLatest, want: "second"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestLsMonitor()
+			ch := m.Subscribe(SubscribeOptions{Buffer: 1, Policy: tt.policy})
+
+			m.sendUpdate(changesFor("first"))
+			m.sendUpdate(changesFor("second"))
+
+			if len(ch) != 1 {
+				t.Fatalf("queued updates = %d, want 1", len(ch))
+			}
+			if got := (<-ch).Changes.Nodes.Added[0]; got != tt.want {
+				t.Errorf("delivered %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLsMonitor_Unsubscribe(t *testing.T) {
+	m := newTestLsMonitor()
+	kept := m.Subscribe(SubscribeOptions{})
+	removed := m.Subscribe(SubscribeOptions{})
+
+	m.Unsubscribe(removed)
+	m.Unsubscribe(removed) // already removed: ignored
+
+	if _, ok := <-removed; ok {
+		t.Error("unsubscribed channel should be closed")
+	}
+	if got := m.SubscriberCount(); got != 1 {
+		t.Errorf("SubscriberCount() = %d, want 1", got)
+	}
+
+	m.sendUpdate(LsChanges{})
+	if len(kept) != 1 {
+		t.Error("remaining subscriber should still receive updates")
+	}
+
+	m.closeSubscribers()
+	if _, ok := <-m.Subscribe(SubscribeOptions{}); ok {
+		t.Error("subscription after stop should be closed")
+	}
+}
+
+func TestLsMonitor_LosslessSubscribersReceiveEveryChange(t *testing.T) {
+	m := newTestLsMonitor()
+	first := m.Subscribe(SubscribeOptions{Name: "flow-a", Policy: Lossless})
+	second := m.Subscribe(SubscribeOptions{Name: "flow-b", Policy: Lossless, Buffer: 1})
+
+	// More changes than either buffer holds, sent before anyone reads
+	const changes = 3 * defaultSubscriberBuffer
+	for i := range changes {
+		m.sendUpdate(changesFor(fmt.Sprintf("node-%d", i)))
+	}
+
+	for name, ch := range map[string]<-chan *LsMonitorUpdate{"flow-a": first, "flow-b": second} {
+		for i := range changes {
+			select {
+			case update := <-ch:
+				want := fmt.Sprintf("node-%d", i)
+				if got := update.Changes.Nodes.Added; len(got) != 1 || got[0] != want {
+					t.Fatalf("%s: update %d changes = %+v, want %s added", name, i, update.Changes, want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("%s: received %d of %d changes", name, i, changes)
+			}
+		}
+	}
+
+	m.closeSubscribers()
+	for name, ch := range map[string]<-chan *LsMonitorUpdate{"flow-a": first, "flow-b": second} {
+		select {
+		case _, ok := <-ch:
+			if ok {
+				t.Errorf("%s: unexpected update after all changes were read", name)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("%s: channel was not closed", name)
+		}
+	}
+}
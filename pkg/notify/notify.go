@@ -0,0 +1,111 @@
+// Package notify opens maintenance windows in incident management tools
+// (PagerDuty, Opsgenie) while a node is in maintenance, and closes them when
+// it is back in service, so expected alerts do not page anyone.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+)
+
+// requestTimeout bounds each call to an incident management API
+const requestTimeout = 15 * time.Second
+
+// Window describes a maintenance window to open
+type Window struct {
+	// Node is the node in maintenance
+	Node string
+
+	// Start and End bound the window; 'crook up' closes it before End
+	Start time.Time
+	End   time.Time
+
+	// Description is shown in the incident management tool
+	Description string
+}
+
+// NewWindow returns the window for a maintenance of the node starting now
+func NewWindow(nodeName string, cfg config.NotificationsConfig, now time.Time) Window {
+	return Window{
+		Node:        nodeName,
+		Start:       now,
+		End:         now.Add(time.Duration(cfg.WindowMinutes) * time.Minute),
+		Description: fmt.Sprintf("crook: Rook-Ceph maintenance of node %s", nodeName),
+	}
+}
+
+// Sink opens and closes maintenance windows in one tool
+type Sink interface {
+	// Name identifies the sink, e.g. "pagerduty"
+	Name() string
+
+	// OpenWindow creates the window and returns its ID in the tool
+	OpenWindow(ctx context.Context, window Window) (string, error)
+
+	// CloseWindow ends the window with the given ID now
+	CloseWindow(ctx context.Context, id string) error
+}
+
+// NewSinks returns a sink for every tool enabled in the configuration
+func NewSinks(cfg config.NotificationsConfig, httpClient *http.Client) []Sink {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: requestTimeout}
+	}
+
+	var sinks []Sink
+	if cfg.PagerDuty.Enabled() {
+		sinks = append(sinks, NewPagerDuty(cfg.PagerDuty, httpClient))
+	}
+	if cfg.Opsgenie.Enabled() {
+		sinks = append(sinks, NewOpsgenie(cfg.Opsgenie, httpClient))
+	}
+	return sinks
+}
+
+// doJSON sends a request with an optional JSON body and decodes a JSON
+// response into out when it is non-nil
+func doJSON(ctx context.Context, client *http.Client, method, url string, header http.Header, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header = header.Clone()
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, url, resp.Status, bytes.TrimSpace(detail))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", method, url, err)
+	}
+	return nil
+}
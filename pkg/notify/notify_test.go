@@ -0,0 +1,153 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+)
+
+// recordedRequest is a request received by the test server
+type recordedRequest struct {
+	method string
+	path   string
+	auth   string
+	from   string
+	body   map[string]any
+}
+
+// newTestServer records requests and answers them with the response
+func newTestServer(t *testing.T, status int, response string) (*httptest.Server, *[]recordedRequest) {
+	t.Helper()
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := recordedRequest{method: r.Method, path: r.URL.Path, auth: r.Header.Get("Authorization"), from: r.Header.Get("From")}
+		_ = json.NewDecoder(r.Body).Decode(&req.body)
+		requests = append(requests, req)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func testWindow() Window {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	cfg := config.NotificationsConfig{WindowMinutes: 60}
+	return NewWindow("worker-1", cfg, start)
+}
+
+func TestPagerDuty(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusCreated, `{"maintenance_window":{"id":"PW98YIO"}}`)
+	sink := NewPagerDuty(config.PagerDutyConfig{Token: "secret", From: "ops@example.com", Services: []string{"PIJ90N7"}}, server.Client())
+	sink.baseURL = server.URL
+
+	id, err := sink.OpenWindow(context.Background(), testWindow())
+	if err != nil {
+		t.Fatalf("OpenWindow() error = %v", err)
+	}
+	if id != "PW98YIO" {
+		t.Errorf("OpenWindow() = %q, want PW98YIO", id)
+	}
+	if err := sink.CloseWindow(context.Background(), id); err != nil {
+		t.Fatalf("CloseWindow() error = %v", err)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(*requests))
+	}
+	open := (*requests)[0]
+	if open.method != http.MethodPost || open.path != "/maintenance_windows" {
+		t.Errorf("open request = %s %s", open.method, open.path)
+	}
+	if open.auth != "Token token=secret" || open.from != "ops@example.com" {
+		t.Errorf("open headers: Authorization %q, From %q", open.auth, open.from)
+	}
+	window, _ := open.body["maintenance_window"].(map[string]any)
+	if window["end_time"] != "2026-03-01T11:00:00Z" {
+		t.Errorf("end_time = %v, want 2026-03-01T11:00:00Z", window["end_time"])
+	}
+	services, _ := window["services"].([]any)
+	if len(services) != 1 {
+		t.Errorf("services = %v, want one", window["services"])
+	}
+
+	closeReq := (*requests)[1]
+	if closeReq.method != http.MethodDelete || closeReq.path != "/maintenance_windows/PW98YIO" {
+		t.Errorf("close request = %s %s", closeReq.method, closeReq.path)
+	}
+}
+
+func TestOpsgenie(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusCreated, `{"data":{"id":"8418d193"}}`)
+	sink := NewOpsgenie(config.OpsgenieConfig{APIKey: "key", URL: server.URL + "/", Integrations: []string{"int-1", "int-2"}}, server.Client())
+
+	id, err := sink.OpenWindow(context.Background(), testWindow())
+	if err != nil {
+		t.Fatalf("OpenWindow() error = %v", err)
+	}
+	if id != "8418d193" {
+		t.Errorf("OpenWindow() = %q, want 8418d193", id)
+	}
+	if err := sink.CloseWindow(context.Background(), id); err != nil {
+		t.Fatalf("CloseWindow() error = %v", err)
+	}
+
+	open := (*requests)[0]
+	if open.path != "/v1/maintenance" || open.auth != "GenieKey key" {
+		t.Errorf("open request = %s, Authorization %q", open.path, open.auth)
+	}
+	rules, _ := open.body["rules"].([]any)
+	if len(rules) != 2 {
+		t.Errorf("rules = %v, want two", open.body["rules"])
+	}
+	if closeReq := (*requests)[1]; closeReq.method != http.MethodPost || closeReq.path != "/v1/maintenance/8418d193/cancel" {
+		t.Errorf("close request = %s %s", closeReq.method, closeReq.path)
+	}
+}
+
+func TestOpenWindow_APIError(t *testing.T) {
+	server, _ := newTestServer(t, http.StatusUnauthorized, `{"error":"invalid token"}`)
+	sink := NewPagerDuty(config.PagerDutyConfig{Token: "bad", From: "ops@example.com", Services: []string{"P1"}}, server.Client())
+	sink.baseURL = server.URL
+
+	if _, err := sink.OpenWindow(context.Background(), testWindow()); err == nil {
+		t.Fatal("OpenWindow() should fail on an API error")
+	}
+}
+
+func TestNewSinks(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.NotificationsConfig
+		want []string
+	}{
+		{name: "none", want: nil},
+		{
+			name: "both",
+			cfg: config.NotificationsConfig{
+				PagerDuty: config.PagerDutyConfig{Services: []string{"P1"}},
+				Opsgenie:  config.OpsgenieConfig{Integrations: []string{"i1"}},
+			},
+			want: []string{"pagerduty", "opsgenie"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sinks := NewSinks(tt.cfg, nil)
+			if len(sinks) != len(tt.want) {
+				t.Fatalf("NewSinks() returned %d sinks, want %d", len(sinks), len(tt.want))
+			}
+			for i, sink := range sinks {
+				if sink.Name() != tt.want[i] {
+					t.Errorf("sink %d = %s, want %s", i, sink.Name(), tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+)
+
+// Opsgenie opens maintenance windows on Opsgenie integrations
+type Opsgenie struct {
+	cfg    config.OpsgenieConfig
+	client *http.Client
+}
+
+// NewOpsgenie creates an Opsgenie sink
+func NewOpsgenie(cfg config.OpsgenieConfig, client *http.Client) *Opsgenie {
+	return &Opsgenie{cfg: cfg, client: client}
+}
+
+// Name implements Sink
+func (o *Opsgenie) Name() string {
+	return "opsgenie"
+}
+
+// opsgenieRule puts one entity in maintenance
+type opsgenieRule struct {
+	State  string         `json:"state"`
+	Entity opsgenieEntity `json:"entity"`
+}
+
+// opsgenieEntity references an integration or policy
+type opsgenieEntity struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// opsgenieTime is the schedule of a maintenance
+type opsgenieTime struct {
+	Type      string    `json:"type"`
+	StartDate time.Time `json:"startDate"`
+	EndDate   time.Time `json:"endDate"`
+}
+
+// opsgenieMaintenance is a maintenance of the Opsgenie API
+type opsgenieMaintenance struct {
+	Description string         `json:"description"`
+	Time        opsgenieTime   `json:"time"`
+	Rules       []opsgenieRule `json:"rules"`
+}
+
+// OpenWindow implements Sink
+func (o *Opsgenie) OpenWindow(ctx context.Context, window Window) (string, error) {
+	rules := make([]opsgenieRule, 0, len(o.cfg.Integrations))
+	for _, id := range o.cfg.Integrations {
+		rules = append(rules, opsgenieRule{State: "enabled", Entity: opsgenieEntity{ID: id, Type: "integration"}})
+	}
+
+	body := opsgenieMaintenance{
+		Description: window.Description,
+		Time:        opsgenieTime{Type: "schedule", StartDate: window.Start, EndDate: window.End},
+		Rules:       rules,
+	}
+	var resp struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := doJSON(ctx, o.client, http.MethodPost, o.url("/v1/maintenance"), o.header(), body, &resp); err != nil {
+		return "", err
+	}
+	return resp.Data.ID, nil
+}
+
+// CloseWindow implements Sink
+func (o *Opsgenie) CloseWindow(ctx context.Context, id string) error {
+	return doJSON(ctx, o.client, http.MethodPost, o.url("/v1/maintenance/"+id+"/cancel"), o.header(), nil, nil)
+}
+
+// url returns the API URL of the path
+func (o *Opsgenie) url(path string) string {
+	return strings.TrimSuffix(o.cfg.URL, "/") + path
+}
+
+// header returns the authentication headers of the API
+func (o *Opsgenie) header() http.Header {
+	return http.Header{"Authorization": {"GenieKey " + o.cfg.APIKey}}
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+)
+
+// pagerDutyAPIURL is the PagerDuty REST API endpoint
+const pagerDutyAPIURL = "https://api.pagerduty.com"
+
+// PagerDuty opens maintenance windows on PagerDuty services
+type PagerDuty struct {
+	cfg    config.PagerDutyConfig
+	client *http.Client
+
+	// baseURL is the API endpoint, replaced in tests
+	baseURL string
+}
+
+// NewPagerDuty creates a PagerDuty sink
+func NewPagerDuty(cfg config.PagerDutyConfig, client *http.Client) *PagerDuty {
+	return &PagerDuty{cfg: cfg, client: client, baseURL: pagerDutyAPIURL}
+}
+
+// Name implements Sink
+func (p *PagerDuty) Name() string {
+	return "pagerduty"
+}
+
+// pagerDutyReference references a PagerDuty object
+type pagerDutyReference struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// pagerDutyWindow is a maintenance window of the PagerDuty REST API
+type pagerDutyWindow struct {
+	ID          string               `json:"id,omitempty"`
+	Type        string               `json:"type"`
+	StartTime   time.Time            `json:"start_time"`
+	EndTime     time.Time            `json:"end_time"`
+	Description string               `json:"description"`
+	Services    []pagerDutyReference `json:"services"`
+}
+
+// OpenWindow implements Sink
+func (p *PagerDuty) OpenWindow(ctx context.Context, window Window) (string, error) {
+	services := make([]pagerDutyReference, 0, len(p.cfg.Services))
+	for _, id := range p.cfg.Services {
+		services = append(services, pagerDutyReference{ID: id, Type: "service_reference"})
+	}
+
+	body := map[string]pagerDutyWindow{
+		"maintenance_window": {
+			Type:        "maintenance_window",
+			StartTime:   window.Start,
+			EndTime:     window.End,
+			Description: window.Description,
+			Services:    services,
+		},
+	}
+	var resp struct {
+		MaintenanceWindow pagerDutyWindow `json:"maintenance_window"`
+	}
+	if err := doJSON(ctx, p.client, http.MethodPost, p.baseURL+"/maintenance_windows", p.header(), body, &resp); err != nil {
+		return "", err
+	}
+	return resp.MaintenanceWindow.ID, nil
+}
+
+// CloseWindow implements Sink. Deleting an ongoing window ends it.
+func (p *PagerDuty) CloseWindow(ctx context.Context, id string) error {
+	return doJSON(ctx, p.client, http.MethodDelete, p.baseURL+"/maintenance_windows/"+id, p.header(), nil, nil)
+}
+
+// header returns the authentication headers of the REST API
+func (p *PagerDuty) header() http.Header {
+	return http.Header{
+		"Authorization": {"Token token=" + p.cfg.Token},
+		"From":          {p.cfg.From},
+	}
+}
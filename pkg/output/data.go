@@ -136,6 +136,10 @@ type FetchOptions struct {
 	ResourceTypes []ResourceType
 	// NodeFilter optionally filters to a specific node
 	NodeFilter string
+	// Prefixes optionally overrides the deployment name prefixes listed
+	Prefixes []string
+	// Selector optionally narrows the nodes, deployments and pods listed
+	Selector k8s.ListSelector
 }
 
 // FetchData fetches all requested data for non-TUI output
@@ -159,14 +163,14 @@ func FetchData(ctx context.Context, opts FetchOptions) (*Data, error) {
 	for _, rt := range opts.ResourceTypes {
 		switch rt {
 		case ResourceNodes:
-			nodes, fetchErr := fetchNodes(ctx, opts.Client, namespace)
+			nodes, fetchErr := fetchNodes(ctx, opts.Client, namespace, opts.Selector)
 			if fetchErr != nil {
 				return nil, fetchErr
 			}
 			data.Nodes = nodes
 
 		case ResourceDeployments:
-			deployments, fetchErr := fetchDeployments(ctx, opts.Client, namespace, opts.NodeFilter)
+			deployments, fetchErr := fetchDeployments(ctx, opts.Client, namespace, opts.NodeFilter, opts.Prefixes, opts.Selector)
 			if fetchErr != nil {
 				return nil, fetchErr
 			}
@@ -183,7 +187,7 @@ func FetchData(ctx context.Context, opts FetchOptions) (*Data, error) {
 			}
 
 		case ResourcePods:
-			pods, fetchErr := fetchPods(ctx, opts.Client, namespace, opts.NodeFilter)
+			pods, fetchErr := fetchPods(ctx, opts.Client, namespace, opts.NodeFilter, opts.Selector)
 			if fetchErr != nil {
 				return nil, fetchErr
 			}
@@ -232,13 +236,13 @@ func fetchClusterHealth(ctx context.Context, client *k8s.Client, namespace strin
 }
 
 // fetchNodes fetches node data
-func fetchNodes(ctx context.Context, client *k8s.Client, namespace string) ([]k8s.NodeInfo, error) {
-	return client.ListNodesWithCephPods(ctx, namespace)
+func fetchNodes(ctx context.Context, client *k8s.Client, namespace string, selector k8s.ListSelector) ([]k8s.NodeInfo, error) {
+	return client.ListNodesWithCephPodsMatching(ctx, namespace, selector)
 }
 
 // fetchDeployments fetches deployment data
-func fetchDeployments(ctx context.Context, client *k8s.Client, namespace string, nodeFilter string) ([]k8s.DeploymentInfo, error) {
-	deployments, err := client.ListCephDeployments(ctx, namespace)
+func fetchDeployments(ctx context.Context, client *k8s.Client, namespace string, nodeFilter string, prefixes []string, selector k8s.ListSelector) ([]k8s.DeploymentInfo, error) {
+	deployments, err := client.ListCephDeploymentsMatching(ctx, namespace, prefixes, selector)
 	if err != nil {
 		return nil, err
 	}
@@ -281,6 +285,6 @@ func fetchOSDs(ctx context.Context, client *k8s.Client, namespace, nodeFilter st
 }
 
 // fetchPods fetches pod data
-func fetchPods(ctx context.Context, client *k8s.Client, namespace string, nodeFilter string) ([]k8s.PodInfo, error) {
-	return client.ListCephPods(ctx, namespace, nodeFilter)
+func fetchPods(ctx context.Context, client *k8s.Client, namespace string, nodeFilter string, selector k8s.ListSelector) ([]k8s.PodInfo, error) {
+	return client.ListCephPodsMatching(ctx, namespace, nodeFilter, selector)
 }
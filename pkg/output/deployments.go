@@ -0,0 +1,152 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+)
+
+// Actions the down phase would take on a pinned deployment
+const (
+	// PinnedActionScaleDown means the down phase scales the deployment to 0
+	PinnedActionScaleDown = "scale down"
+	// PinnedActionSkip means the deployment is already at 0 and is skipped
+	PinnedActionSkip = "skip"
+	// PinnedActionExcluded means discovery.exclude keeps the deployment running
+	PinnedActionExcluded = "excluded"
+)
+
+// PinnedDeployment is a node-pinned deployment as listed by 'crook deployments'
+type PinnedDeployment struct {
+	k8s.DeploymentInfo
+
+	// Order is the 1-based position in the down phase scale-down order
+	Order int `json:"order"`
+
+	// PinnedBy is the part of the pod spec that pins the deployment to the
+	// node: nodeSelector or nodeAffinity
+	PinnedBy string `json:"pinned_by"`
+
+	// Action is what the down phase would do with the deployment
+	Action string `json:"action"`
+}
+
+// PinnedDeploymentList holds the deployments pinned to a node, in down phase order
+type PinnedDeploymentList struct {
+	Node        string             `json:"node"`
+	Deployments []PinnedDeployment `json:"deployments"`
+}
+
+// FetchPinnedDeployments discovers the deployments pinned to a node exactly as
+// the down phase does and returns them in the order it would scale them down
+func FetchPinnedDeployments(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string) (*PinnedDeploymentList, error) {
+	deployments, err := client.ListNodePinnedDeployments(ctx, cfg.Namespace, nodeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover node-pinned deployments: %w", err)
+	}
+
+	now := time.Now()
+	list := &PinnedDeploymentList{Node: nodeName, Deployments: make([]PinnedDeployment, 0, len(deployments))}
+	for i, dep := range maintenance.OrderDeploymentsForDown(deployments) {
+		_, source := k8s.DeploymentPinSource(&dep)
+		action := PinnedActionScaleDown
+		switch {
+		case cfg.Discovery.Excludes(dep.Name):
+			action = PinnedActionExcluded
+		case dep.Spec.Replicas != nil && *dep.Spec.Replicas == 0:
+			action = PinnedActionSkip
+		}
+		list.Deployments = append(list.Deployments, PinnedDeployment{
+			DeploymentInfo: k8s.NewDeploymentInfo(&dep, nodeName, now),
+			Order:          i + 1,
+			PinnedBy:       source,
+			Action:         action,
+		})
+	}
+	return list, nil
+}
+
+// RenderPinnedDeployments renders the pinned deployment list in the given format
+func RenderPinnedDeployments(w io.Writer, list *PinnedDeploymentList, format Format) error {
+	switch format {
+	case FormatTable:
+		NewTableWriter(w).writePinnedDeploymentsTable(list)
+		return nil
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// writePinnedDeploymentsTable writes the 'crook deployments' table
+func (tw *TableWriter) writePinnedDeploymentsTable(list *PinnedDeploymentList) {
+	if len(list.Deployments) == 0 {
+		_, _ = fmt.Fprintf(tw.w, "No node-pinned deployments found on %s\n", list.Node)
+		return
+	}
+
+	cols := []column{
+		{header: "#", width: 4},
+		{header: "NAME", width: 40},
+		{header: "READY", width: 8},
+		{header: "STATUS", width: 12},
+		{header: "PINNED BY", width: 14},
+		{header: "ACTION", width: 12},
+		{header: "VERSION", width: 14},
+	}
+
+	tw.writeTableHeader(cols)
+	tw.writeTableSeparator(cols)
+
+	for _, dep := range list.Deployments {
+		readyStr := fmt.Sprintf("%d/%d", dep.ReadyReplicas, dep.DesiredReplicas)
+		readyColor := colorGreen
+		if dep.ReadyReplicas == 0 && dep.DesiredReplicas > 0 {
+			readyColor = colorRed
+		} else if dep.ReadyReplicas < dep.DesiredReplicas {
+			readyColor = colorYellow
+		}
+
+		statusColor := colorGreen
+		switch dep.Status {
+		case "Scaling":
+			statusColor = colorYellow
+		case "Unavailable":
+			statusColor = colorRed
+		}
+
+		actionColor := ""
+		switch dep.Action {
+		case PinnedActionSkip:
+			actionColor = colorYellow
+		case PinnedActionExcluded:
+			actionColor = colorFaint
+		}
+
+		version := dep.Version()
+		if version == "" {
+			version = "-"
+		}
+
+		row := []cell{
+			{value: strconv.Itoa(dep.Order)},
+			{value: dep.Name},
+			{value: readyStr, color: readyColor},
+			{value: dep.Status, color: statusColor},
+			{value: dep.PinnedBy},
+			{value: dep.Action, color: actionColor},
+			{value: version},
+		}
+		tw.writeTableRow(cols, row)
+	}
+}
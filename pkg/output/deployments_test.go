@@ -0,0 +1,123 @@
+package output_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/output"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestFetchPinnedDeployments(t *testing.T) {
+	affinityPinned := nodesTestDeployment("rook-ceph-mon-a", "", 1)
+	affinityPinned.Spec.Template.Spec.NodeSelector = nil
+	affinityPinned.Spec.Template.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      "kubernetes.io/hostname",
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{"worker-1"},
+				}},
+			}},
+		},
+	}}
+
+	clientset := fake.NewClientset(
+		nodesTestDeployment("rook-ceph-crashcollector-worker-1", "worker-1", 1),
+		affinityPinned,
+		nodesTestDeployment("rook-ceph-osd-0", "worker-1", 0),
+		nodesTestDeployment("rook-ceph-osd-1", "worker-2", 1),
+	)
+	client := &k8s.Client{Clientset: clientset}
+
+	cfg := config.DefaultConfig()
+	cfg.Discovery.Exclude = []string{"rook-ceph-crashcollector-.*"}
+
+	list, err := output.FetchPinnedDeployments(context.Background(), client, cfg, "worker-1")
+	if err != nil {
+		t.Fatalf("FetchPinnedDeployments() error: %v", err)
+	}
+
+	want := []struct {
+		name     string
+		pinnedBy string
+		action   string
+	}{
+		{"rook-ceph-osd-0", k8s.PinSourceNodeSelector, output.PinnedActionSkip},
+		{"rook-ceph-mon-a", k8s.PinSourceNodeAffinity, output.PinnedActionScaleDown},
+		{"rook-ceph-crashcollector-worker-1", k8s.PinSourceNodeSelector, output.PinnedActionExcluded},
+	}
+	if len(list.Deployments) != len(want) {
+		t.Fatalf("FetchPinnedDeployments() returned %d deployments, want %d", len(list.Deployments), len(want))
+	}
+	for i, w := range want {
+		got := list.Deployments[i]
+		if got.Order != i+1 || got.Name != w.name || got.PinnedBy != w.pinnedBy || got.Action != w.action {
+			t.Errorf("deployment %d = {%d %s %s %s}, want {%d %s %s %s}",
+				i, got.Order, got.Name, got.PinnedBy, got.Action, i+1, w.name, w.pinnedBy, w.action)
+		}
+	}
+}
+
+func TestRenderPinnedDeployments(t *testing.T) {
+	list := &output.PinnedDeploymentList{Node: "worker-1", Deployments: []output.PinnedDeployment{
+		{
+			DeploymentInfo: k8s.DeploymentInfo{
+				Name:            "rook-ceph-osd-0",
+				Namespace:       "rook-ceph",
+				ReadyReplicas:   1,
+				DesiredReplicas: 1,
+				Status:          "Ready",
+			},
+			Order:    1,
+			PinnedBy: k8s.PinSourceNodeSelector,
+			Action:   output.PinnedActionScaleDown,
+		},
+	}}
+
+	var table bytes.Buffer
+	if err := output.RenderPinnedDeployments(&table, list, output.FormatTable); err != nil {
+		t.Fatalf("RenderPinnedDeployments(table) error: %v", err)
+	}
+	for _, want := range []string{"PINNED BY", "ACTION", "rook-ceph-osd-0", "1/1", "nodeSelector", "scale down"} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("table output missing %q:\n%s", want, table.String())
+		}
+	}
+
+	var empty bytes.Buffer
+	if err := output.RenderPinnedDeployments(&empty, &output.PinnedDeploymentList{Node: "worker-2"}, output.FormatTable); err != nil {
+		t.Fatalf("RenderPinnedDeployments(empty) error: %v", err)
+	}
+	if !strings.Contains(empty.String(), "No node-pinned deployments found on worker-2") {
+		t.Errorf("unexpected empty table output: %q", empty.String())
+	}
+
+	var raw bytes.Buffer
+	if err := output.RenderPinnedDeployments(&raw, list, output.FormatJSON); err != nil {
+		t.Fatalf("RenderPinnedDeployments(json) error: %v", err)
+	}
+	var decoded struct {
+		Node        string `json:"node"`
+		Deployments []struct {
+			Name     string `json:"name"`
+			Order    int    `json:"order"`
+			PinnedBy string `json:"pinned_by"`
+			Action   string `json:"action"`
+		} `json:"deployments"`
+	}
+	if err := json.Unmarshal(raw.Bytes(), &decoded); err != nil {
+		t.Fatalf("json output is not parseable: %v", err)
+	}
+	if decoded.Node != "worker-1" || len(decoded.Deployments) != 1 || decoded.Deployments[0].Order != 1 ||
+		decoded.Deployments[0].PinnedBy != "nodeSelector" || decoded.Deployments[0].Action != "scale down" {
+		t.Errorf("unexpected json output: %s", raw.String())
+	}
+}
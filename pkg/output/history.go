@@ -0,0 +1,97 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/andri/crook/pkg/maintenance"
+)
+
+// HistoryList holds the past maintenances listed by 'crook history', newest first
+type HistoryList struct {
+	// Node is set when the list is limited to one node
+	Node    string                     `json:"node,omitempty"`
+	Entries []maintenance.HistoryEntry `json:"entries"`
+}
+
+// RenderHistory renders the maintenance history in the given format
+func RenderHistory(w io.Writer, list *HistoryList, format Format) error {
+	switch format {
+	case FormatTable:
+		NewTableWriter(w).writeHistoryTable(list)
+		return nil
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// FormatHistoryDuration formats how long a maintenance took, to the second
+func FormatHistoryDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+// writeHistoryTable writes the 'crook history' table
+func (tw *TableWriter) writeHistoryTable(list *HistoryList) {
+	if len(list.Entries) == 0 {
+		if list.Node != "" {
+			_, _ = fmt.Fprintf(tw.w, "No maintenance recorded for %s\n", list.Node)
+		} else {
+			_, _ = fmt.Fprintln(tw.w, "No maintenance recorded")
+		}
+		return
+	}
+
+	cols := []column{
+		{header: "NODE", width: 20},
+		{header: "STARTED", width: 18},
+		{header: "DURATION", width: 12},
+		{header: "OPERATORS", width: 20},
+		{header: "TICKET", width: 14},
+		{header: "OUTCOME", width: 12},
+		{header: "CHANGES", width: 8},
+	}
+
+	tw.writeTableHeader(cols)
+	tw.writeTableSeparator(cols)
+
+	for _, entry := range list.Entries {
+		outcomeColor := colorGreen
+		switch entry.Outcome {
+		case maintenance.OutcomeFailed:
+			outcomeColor = colorRed
+		case maintenance.OutcomeInProgress:
+			outcomeColor = colorYellow
+		}
+
+		changesColor := ""
+		if entry.Changes > 0 {
+			changesColor = colorYellow
+		}
+
+		row := []cell{
+			{value: entry.Node},
+			{value: entry.Started.Local().Format("2006-01-02 15:04")},
+			{value: FormatHistoryDuration(entry.Duration())},
+			{value: dashIfEmpty(entry.Operators())},
+			{value: dashIfEmpty(entry.Ticket())},
+			{value: entry.Outcome, color: outcomeColor},
+			{value: strconv.Itoa(entry.Changes), color: changesColor},
+		}
+		tw.writeTableRow(cols, row)
+	}
+}
+
+// dashIfEmpty returns "-" for an empty value
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,67 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/output"
+)
+
+func TestRenderHistory(t *testing.T) {
+	started := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	finished := started.Add(90 * time.Minute)
+	list := &output.HistoryList{
+		Entries: []maintenance.HistoryEntry{
+			{
+				Node:         "worker-1",
+				Started:      started,
+				Finished:     &finished,
+				DownOperator: "alice",
+				UpOperator:   "bob",
+				DownSignOff:  &maintenance.SignOff{Ticket: "CHG-42"},
+				Outcome:      maintenance.OutcomeSucceeded,
+				Changes:      2,
+			},
+		},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.RenderHistory(&buf, list, output.FormatTable); err != nil {
+			t.Fatalf("RenderHistory() error: %v", err)
+		}
+		for _, want := range []string{"NODE", "OPERATORS", "worker-1", "1h30m0s", "alice, bob", "CHG-42", "succeeded"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("table output missing %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.RenderHistory(&buf, list, output.FormatJSON); err != nil {
+			t.Fatalf("RenderHistory() error: %v", err)
+		}
+		var decoded output.HistoryList
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if len(decoded.Entries) != 1 || decoded.Entries[0].UpOperator != "bob" {
+			t.Errorf("decoded = %+v", decoded)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.RenderHistory(&buf, &output.HistoryList{Node: "worker-2"}, output.FormatTable); err != nil {
+			t.Fatalf("RenderHistory() error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "No maintenance recorded for worker-2") {
+			t.Errorf("unexpected output: %q", buf.String())
+		}
+	})
+}
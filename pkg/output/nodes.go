@@ -0,0 +1,164 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// Maintenance states of a node listed by 'crook nodes'
+const (
+	// NodeMaintenanceNone means the node is schedulable with all deployments up
+	NodeMaintenanceNone = "-"
+	// NodeMaintenanceCordoned means the node is cordoned but no deployment is scaled down
+	NodeMaintenanceCordoned = "cordoned"
+	// NodeMaintenanceScaledDown means deployments are scaled down on a schedulable node
+	NodeMaintenanceScaledDown = "scaled down"
+	// NodeMaintenanceDown means the node is cordoned with deployments scaled down
+	NodeMaintenanceDown = "down"
+)
+
+// NodeEntry is a node as listed by 'crook nodes'
+type NodeEntry struct {
+	k8s.NodeInfo
+
+	// ScaledDownDeployments is the number of Ceph deployments pinned to the
+	// node that are scaled to 0
+	ScaledDownDeployments int `json:"scaled_down_deployments"`
+
+	// Maintenance summarizes the node's maintenance state
+	Maintenance string `json:"maintenance"`
+}
+
+// NodeList holds the nodes listed by 'crook nodes'
+type NodeList struct {
+	Nodes []NodeEntry `json:"nodes"`
+}
+
+// FetchNodeList fetches the nodes with their Ceph roles and maintenance state
+func FetchNodeList(ctx context.Context, client *k8s.Client, cfg config.Config) (*NodeList, error) {
+	nodes, err := client.ListNodesWithCephPods(ctx, cfg.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := client.ListDeploymentsInNamespace(ctx, cfg.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	scaledDown := make(map[string]int)
+	for _, dep := range client.CephDeploymentFilter(nil).Filter(deployments) {
+		if dep.Spec.Replicas != nil && *dep.Spec.Replicas == 0 {
+			scaledDown[k8s.GetDeploymentTargetNode(&dep)]++
+		}
+	}
+
+	list := &NodeList{Nodes: make([]NodeEntry, 0, len(nodes))}
+	for _, node := range nodes {
+		entry := NodeEntry{NodeInfo: node, ScaledDownDeployments: scaledDown[node.Name]}
+		entry.Maintenance = nodeMaintenance(node.Cordoned, entry.ScaledDownDeployments)
+		list.Nodes = append(list.Nodes, entry)
+	}
+	return list, nil
+}
+
+// nodeMaintenance classifies a node's maintenance state
+func nodeMaintenance(cordoned bool, scaledDown int) string {
+	switch {
+	case cordoned && scaledDown > 0:
+		return NodeMaintenanceDown
+	case scaledDown > 0:
+		return NodeMaintenanceScaledDown
+	case cordoned:
+		return NodeMaintenanceCordoned
+	default:
+		return NodeMaintenanceNone
+	}
+}
+
+// RenderNodeList renders the node list in the given format
+func RenderNodeList(w io.Writer, list *NodeList, format Format) error {
+	switch format {
+	case FormatTable:
+		NewTableWriter(w).writeNodeListTable(list.Nodes)
+		return nil
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// writeNodeListTable writes the 'crook nodes' table
+func (tw *TableWriter) writeNodeListTable(nodes []NodeEntry) {
+	cols := []column{
+		{header: "NAME", width: 30},
+		{header: "STATUS", width: 10},
+		{header: "SCHEDULE", width: 10},
+		{header: "CEPH ROLES", width: 16},
+		{header: "MAINTENANCE", width: 12},
+		{header: "AGE", width: 8},
+		{header: "ANNOTATIONS", width: 40},
+	}
+
+	tw.writeTableHeader(cols)
+	tw.writeTableSeparator(cols)
+
+	for _, node := range nodes {
+		statusColor := colorGreen
+		if node.Status == "NotReady" {
+			statusColor = colorRed
+		} else if node.Status != "Ready" {
+			statusColor = colorYellow
+		}
+
+		scheduleStr := "Ready"
+		scheduleColor := ""
+		if node.Cordoned {
+			scheduleStr = "Cordoned"
+			scheduleColor = colorYellow
+		}
+
+		rolesStr := "-"
+		if len(node.CephRoles) > 0 {
+			rolesStr = strings.Join(node.CephRoles, ",")
+		}
+
+		maintenanceColor := ""
+		if node.Maintenance != NodeMaintenanceNone {
+			maintenanceColor = colorYellow
+		}
+
+		row := []cell{
+			{value: node.Name},
+			{value: node.Status, color: statusColor},
+			{value: scheduleStr, color: scheduleColor},
+			{value: rolesStr},
+			{value: node.Maintenance, color: maintenanceColor},
+			{value: node.Age},
+			{value: formatAnnotations(node.MaintenanceAnnotations)},
+		}
+		tw.writeTableRow(cols, row)
+	}
+}
+
+// formatAnnotations joins annotations as sorted key=value pairs
+func formatAnnotations(annotations map[string]string) string {
+	if len(annotations) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(annotations))
+	for key, value := range annotations {
+		pairs = append(pairs, key+"="+value)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
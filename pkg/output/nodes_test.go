@@ -0,0 +1,110 @@
+package output_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/output"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func nodesTestDeployment(name, node string, replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{NodeSelector: map[string]string{"kubernetes.io/hostname": node}},
+			},
+		},
+	}
+}
+
+func TestFetchNodeList(t *testing.T) {
+	clientset := fake.NewClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}, Spec: corev1.NodeSpec{Unschedulable: true}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-2"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-3"}, Spec: corev1.NodeSpec{Unschedulable: true}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-4"}},
+		nodesTestDeployment("rook-ceph-osd-0", "worker-1", 0),
+		nodesTestDeployment("rook-ceph-osd-1", "worker-2", 0),
+		nodesTestDeployment("rook-ceph-osd-2", "worker-3", 1),
+		nodesTestDeployment("rook-ceph-osd-3", "worker-4", 1),
+	)
+	client := &k8s.Client{Clientset: clientset}
+
+	list, err := output.FetchNodeList(context.Background(), client, config.DefaultConfig())
+	if err != nil {
+		t.Fatalf("FetchNodeList() error: %v", err)
+	}
+
+	want := map[string]string{
+		"worker-1": output.NodeMaintenanceDown,
+		"worker-2": output.NodeMaintenanceScaledDown,
+		"worker-3": output.NodeMaintenanceCordoned,
+		"worker-4": output.NodeMaintenanceNone,
+	}
+	if len(list.Nodes) != len(want) {
+		t.Fatalf("FetchNodeList() returned %d nodes, want %d", len(list.Nodes), len(want))
+	}
+	for _, node := range list.Nodes {
+		if node.Maintenance != want[node.Name] {
+			t.Errorf("%s maintenance = %q, want %q", node.Name, node.Maintenance, want[node.Name])
+		}
+	}
+}
+
+func TestRenderNodeList(t *testing.T) {
+	list := &output.NodeList{Nodes: []output.NodeEntry{
+		{
+			NodeInfo: k8s.NodeInfo{
+				Name:                   "worker-1",
+				Status:                 "Ready",
+				Cordoned:               true,
+				CephRoles:              []string{"mon", "osd"},
+				Age:                    "5d",
+				MaintenanceAnnotations: map[string]string{"crook.io/reason": "disk"},
+			},
+			ScaledDownDeployments: 2,
+			Maintenance:           output.NodeMaintenanceDown,
+		},
+	}}
+
+	var table bytes.Buffer
+	if err := output.RenderNodeList(&table, list, output.FormatTable); err != nil {
+		t.Fatalf("RenderNodeList(table) error: %v", err)
+	}
+	for _, want := range []string{"CEPH ROLES", "MAINTENANCE", "worker-1", "Cordoned", "mon,osd", "down", "crook.io/reason=disk"} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("table output missing %q:\n%s", want, table.String())
+		}
+	}
+
+	var raw bytes.Buffer
+	if err := output.RenderNodeList(&raw, list, output.FormatJSON); err != nil {
+		t.Fatalf("RenderNodeList(json) error: %v", err)
+	}
+	var decoded struct {
+		Nodes []struct {
+			Name        string   `json:"name"`
+			CephRoles   []string `json:"ceph_roles"`
+			ScaledDown  int      `json:"scaled_down_deployments"`
+			Maintenance string   `json:"maintenance"`
+		} `json:"nodes"`
+	}
+	if err := json.Unmarshal(raw.Bytes(), &decoded); err != nil {
+		t.Fatalf("json output is not parseable: %v", err)
+	}
+	if len(decoded.Nodes) != 1 || decoded.Nodes[0].Name != "worker-1" || decoded.Nodes[0].ScaledDown != 2 ||
+		decoded.Nodes[0].Maintenance != "down" || len(decoded.Nodes[0].CephRoles) != 2 {
+		t.Errorf("unexpected json output: %s", raw.String())
+	}
+}
@@ -155,6 +155,8 @@ func createTestData() *output.Data {
 				Status:          "Ready",
 				Type:            "osd",
 				OsdID:           "0",
+				Image:           "quay.io/ceph/ceph:v18.2.2",
+				CephVersion:     "18.2.2-0",
 			},
 		},
 		OSDs: []k8s.OSDInfo{
@@ -289,6 +291,9 @@ func TestRenderTable(t *testing.T) {
 	if !strings.Contains(tableOutput, "osd.0") {
 		t.Error("RenderTable() missing OSD data")
 	}
+	if !strings.Contains(tableOutput, "VERSION") || !strings.Contains(tableOutput, "18.2.2-0") {
+		t.Error("RenderTable() missing deployment Ceph version")
+	}
 }
 
 func TestRenderTableHealthStatus(t *testing.T) {
@@ -0,0 +1,169 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/andri/crook/pkg/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// IO states of a claim listed by 'crook pvc ls'
+const (
+	// PVCIOPending means the claim is not bound to a volume yet
+	PVCIOPending = "pending"
+	// PVCIOIdle means the volume is bound but no node uses it
+	PVCIOIdle = "idle"
+	// PVCIOActive means the volume is in use on Ready, schedulable nodes
+	PVCIOActive = "active"
+	// PVCIOMaintenance means a node using the volume is cordoned
+	PVCIOMaintenance = "maintenance"
+	// PVCIOStalled means a node using the volume is NotReady, so its IO is
+	// likely blocked
+	PVCIOStalled = "stalled"
+	// PVCIOError means an attachment of the volume reported an error
+	PVCIOError = "error"
+)
+
+// PVCEntry is a claim as listed by 'crook pvc ls'
+type PVCEntry struct {
+	k8s.PVCInfo
+
+	// IOStatus summarizes whether the claim's consumers can do IO
+	IOStatus string `json:"io_status"`
+}
+
+// PVCList holds the claims listed by 'crook pvc ls'
+type PVCList struct {
+	PVCs []PVCEntry `json:"pvcs"`
+}
+
+// FetchPVCList fetches the Ceph-backed claims in the namespace, all
+// namespaces when empty, with their IO status. When node is set only the
+// claims used on that node are kept.
+func FetchPVCList(ctx context.Context, client *k8s.Client, namespace, node string) (*PVCList, error) {
+	claims, err := client.ListCephPVCs(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := map[string]*k8s.NodeStatus{}
+	list := &PVCList{PVCs: make([]PVCEntry, 0, len(claims))}
+	for _, claim := range claims {
+		if node != "" && !slices.Contains(claim.Nodes, node) {
+			continue
+		}
+		for _, name := range claim.Nodes {
+			if _, ok := nodes[name]; ok {
+				continue
+			}
+			status, err := client.GetNodeStatus(ctx, name)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			nodes[name] = status
+		}
+		list.PVCs = append(list.PVCs, PVCEntry{PVCInfo: claim, IOStatus: pvcIOStatus(claim, nodes)})
+	}
+	return list, nil
+}
+
+// pvcIOStatus classifies a claim's IO status from its attachments and the
+// state of the nodes using it
+func pvcIOStatus(claim k8s.PVCInfo, nodes map[string]*k8s.NodeStatus) string {
+	switch {
+	case claim.AttachError != "":
+		return PVCIOError
+	case claim.Volume == "":
+		return PVCIOPending
+	case len(claim.Nodes) == 0:
+		return PVCIOIdle
+	}
+	status := PVCIOActive
+	for _, name := range claim.Nodes {
+		node := nodes[name]
+		switch {
+		// A node that no longer exists cannot release the volume either
+		case node == nil || !node.Ready:
+			return PVCIOStalled
+		case node.Unschedulable:
+			status = PVCIOMaintenance
+		}
+	}
+	return status
+}
+
+// RenderPVCList renders the claim list in the given format
+func RenderPVCList(w io.Writer, list *PVCList, format Format) error {
+	switch format {
+	case FormatTable:
+		NewTableWriter(w).writePVCListTable(list.PVCs)
+		return nil
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// pvcIOStatusColor colors a claim's IO status by whether it needs attention
+func pvcIOStatusColor(status string) string {
+	switch status {
+	case PVCIOError, PVCIOStalled:
+		return colorRed
+	case PVCIOMaintenance, PVCIOPending:
+		return colorYellow
+	case PVCIOActive:
+		return colorGreen
+	default:
+		return ""
+	}
+}
+
+// writePVCListTable writes the 'crook pvc ls' table
+func (tw *TableWriter) writePVCListTable(pvcs []PVCEntry) {
+	if len(pvcs) == 0 {
+		_, _ = fmt.Fprintln(tw.w, "No PVCs served by Ceph storage classes")
+		return
+	}
+
+	cols := []column{
+		{header: "NAMESPACE", width: 16},
+		{header: "NAME", width: 32},
+		{header: "STORAGE CLASS", width: 16},
+		{header: "SIZE", width: 8},
+		{header: "POOL", width: 20},
+		{header: "NODE", width: 24},
+		{header: "IO STATUS", width: 12},
+	}
+
+	tw.writeTableHeader(cols)
+	tw.writeTableSeparator(cols)
+
+	for _, pvc := range pvcs {
+		nodes := "-"
+		if len(pvc.Nodes) > 0 {
+			nodes = strings.Join(pvc.Nodes, ",")
+		}
+		pool := pvc.Pool
+		if pool == "" {
+			pool = "-"
+		}
+		row := []cell{
+			{value: pvc.Namespace},
+			{value: pvc.Name},
+			{value: pvc.StorageClass},
+			{value: pvc.Size},
+			{value: pool},
+			{value: nodes},
+			{value: pvc.IOStatus, color: pvcIOStatusColor(pvc.IOStatus)},
+		}
+		tw.writeTableRow(cols, row)
+	}
+}
@@ -0,0 +1,131 @@
+package output_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/output"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestFetchPVCList(t *testing.T) {
+	const rbd = "rook-ceph.rbd.csi.ceph.com"
+	className := "ceph-block"
+
+	node := func(name string, ready corev1.ConditionStatus, cordoned bool) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.NodeSpec{Unschedulable: cordoned},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}}},
+		}
+	}
+	claim := func(name, volume string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &className, VolumeName: volume},
+		}
+	}
+	pod := func(name, nodeName, claimName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec: corev1.PodSpec{NodeName: nodeName, Volumes: []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName}},
+			}}},
+		}
+	}
+
+	clientset := fake.NewClientset(
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: className}, Provisioner: rbd},
+		node("worker-1", corev1.ConditionTrue, false),
+		node("worker-2", corev1.ConditionTrue, true),
+		node("worker-3", corev1.ConditionUnknown, false),
+		claim("active", "pv-1"), claim("cordoned", "pv-2"), claim("down", "pv-3"),
+		claim("gone", "pv-4"), claim("idle", "pv-5"), claim("new", ""),
+		pod("a", "worker-1", "active"), pod("b", "worker-2", "cordoned"), pod("c", "worker-3", "down"),
+		pod("d", "worker-9", "gone"),
+	)
+	client := &k8s.Client{Clientset: clientset}
+
+	list, err := output.FetchPVCList(context.Background(), client, "", "")
+	if err != nil {
+		t.Fatalf("FetchPVCList() error: %v", err)
+	}
+	want := map[string]string{
+		"active":   output.PVCIOActive,
+		"cordoned": output.PVCIOMaintenance,
+		"down":     output.PVCIOStalled,
+		"gone":     output.PVCIOStalled,
+		"idle":     output.PVCIOIdle,
+		"new":      output.PVCIOPending,
+	}
+	if len(list.PVCs) != len(want) {
+		t.Fatalf("FetchPVCList() returned %d claims, want %d", len(list.PVCs), len(want))
+	}
+	for _, pvc := range list.PVCs {
+		if pvc.IOStatus != want[pvc.Name] {
+			t.Errorf("%s IO status = %q, want %q", pvc.Name, pvc.IOStatus, want[pvc.Name])
+		}
+	}
+
+	list, err = output.FetchPVCList(context.Background(), client, "", "worker-2")
+	if err != nil {
+		t.Fatalf("FetchPVCList() error: %v", err)
+	}
+	if len(list.PVCs) != 1 || list.PVCs[0].Name != "cordoned" {
+		t.Errorf("FetchPVCList(node worker-2) = %+v, want only the cordoned claim", list.PVCs)
+	}
+}
+
+func TestRenderPVCList(t *testing.T) {
+	list := &output.PVCList{PVCs: []output.PVCEntry{
+		{PVCInfo: k8s.PVCInfo{Namespace: "apps", Name: "db", StorageClass: "ceph-block", Size: "10Gi", Pool: "replicapool", Nodes: []string{"worker-1"}}, IOStatus: output.PVCIOMaintenance},
+		{PVCInfo: k8s.PVCInfo{Namespace: "apps", Name: "new", StorageClass: "ceph-block", Size: "1Gi"}, IOStatus: output.PVCIOPending},
+	}}
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.RenderPVCList(&buf, list, output.FormatTable); err != nil {
+			t.Fatalf("RenderPVCList() error: %v", err)
+		}
+		for _, want := range []string{"IO STATUS", "replicapool", "worker-1", "maintenance", "pending"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("table output missing %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("empty table", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.RenderPVCList(&buf, &output.PVCList{}, output.FormatTable); err != nil {
+			t.Fatalf("RenderPVCList() error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "No PVCs served by Ceph storage classes") {
+			t.Errorf("table output = %q", buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.RenderPVCList(&buf, list, output.FormatJSON); err != nil {
+			t.Fatalf("RenderPVCList() error: %v", err)
+		}
+		var decoded map[string][]map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if got := decoded["pvcs"][0]["io_status"]; got != output.PVCIOMaintenance {
+			t.Errorf("io_status = %v, want %q", got, output.PVCIOMaintenance)
+		}
+		if got := decoded["pvcs"][0]["storage_class"]; got != "ceph-block" {
+			t.Errorf("storage_class = %v, want ceph-block", got)
+		}
+	})
+}
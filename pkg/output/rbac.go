@@ -0,0 +1,110 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/andri/crook/pkg/maintenance"
+)
+
+// Permission statuses listed by 'crook check rbac'
+const (
+	PermissionAllowed = "allowed"
+	PermissionDenied  = "denied"
+	PermissionUnknown = "unknown"
+)
+
+// RenderPermissionReport renders the 'crook check rbac' report in the given format
+func RenderPermissionReport(w io.Writer, report *maintenance.PermissionReport, format Format) error {
+	switch format {
+	case FormatTable:
+		NewTableWriter(w).writePermissionTable(report)
+		return nil
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// permissionStatus classifies the outcome of a permission's review
+func permissionStatus(p maintenance.Permission) string {
+	switch {
+	case p.Error != "":
+		return PermissionUnknown
+	case p.Allowed:
+		return PermissionAllowed
+	default:
+		return PermissionDenied
+	}
+}
+
+// writePermissionTable writes the 'crook check rbac' matrix
+func (tw *TableWriter) writePermissionTable(report *maintenance.PermissionReport) {
+	cols := []column{
+		{header: "VERB", width: 8},
+		{header: "RESOURCE", width: 28},
+		{header: "NAMESPACE", width: 14},
+		{header: "PURPOSE", width: 28},
+		{header: "REQUIRED", width: 8},
+		{header: "STATUS", width: 8},
+	}
+
+	tw.writeTableHeader(cols)
+	tw.writeTableSeparator(cols)
+
+	var missing, unknown int
+	for _, p := range report.Permissions {
+		resource := p.Resource
+		if p.Subresource != "" {
+			resource += "/" + p.Subresource
+		}
+		if p.Group != "" {
+			resource = p.Group + "/" + resource
+		}
+		namespace := p.Namespace
+		if namespace == "" {
+			namespace = "(cluster)"
+		}
+		required := "no"
+		if p.Required {
+			required = "yes"
+		}
+
+		status := permissionStatus(p)
+		statusColor := colorGreen
+		switch {
+		case status == PermissionUnknown:
+			statusColor = colorYellow
+			unknown++
+		case status == PermissionDenied && p.Required:
+			statusColor = colorRed
+			missing++
+		case status == PermissionDenied:
+			statusColor = colorYellow
+		}
+
+		row := []cell{
+			{value: p.Verb},
+			{value: resource},
+			{value: namespace},
+			{value: p.Purpose},
+			{value: required},
+			{value: status, color: statusColor},
+		}
+		tw.writeTableRow(cols, row)
+	}
+
+	_, _ = fmt.Fprintln(tw.w)
+	switch {
+	case missing > 0:
+		_, _ = fmt.Fprintln(tw.w, tw.colorize(fmt.Sprintf("%d required permission(s) missing", missing), colorRed))
+	case unknown > 0:
+		_, _ = fmt.Fprintln(tw.w, tw.colorize(fmt.Sprintf("All verifiable required permissions granted; %d could not be checked", unknown), colorYellow))
+	default:
+		_, _ = fmt.Fprintln(tw.w, tw.colorize("All required permissions granted", colorGreen))
+	}
+}
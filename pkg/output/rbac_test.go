@@ -0,0 +1,48 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/output"
+)
+
+func TestRenderPermissionReport(t *testing.T) {
+	report := &maintenance.PermissionReport{
+		Namespace: "rook-ceph",
+		Permissions: []maintenance.Permission{
+			{Verb: "patch", Resource: "nodes", Purpose: "cordon and uncordon", Required: true, Allowed: true},
+			{Verb: "update", Group: "apps", Resource: "deployments", Subresource: "scale", Namespace: "rook-ceph", Purpose: "scaling", Required: true},
+			{Verb: "create", Group: "batch", Resource: "jobs", Namespace: "rook-ceph", Purpose: "run-in-cluster", Error: "timeout"},
+		},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.RenderPermissionReport(&buf, report, output.FormatTable); err != nil {
+			t.Fatalf("RenderPermissionReport() error: %v", err)
+		}
+		for _, want := range []string{"VERB", "(cluster)", "apps/deployments/scale", "denied", "unknown", "1 required permission(s) missing"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("table output missing %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.RenderPermissionReport(&buf, report, output.FormatJSON); err != nil {
+			t.Fatalf("RenderPermissionReport() error: %v", err)
+		}
+		var decoded maintenance.PermissionReport
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if len(decoded.Permissions) != 3 || decoded.Permissions[1].Subresource != "scale" {
+			t.Errorf("decoded = %+v, want the three permissions", decoded)
+		}
+	})
+}
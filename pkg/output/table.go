@@ -8,6 +8,7 @@ import (
 
 	"github.com/andri/crook/pkg/k8s"
 	"github.com/andri/crook/pkg/tui/format"
+	"github.com/andri/crook/pkg/tui/styles"
 	"golang.org/x/term"
 )
 
@@ -23,7 +24,7 @@ type TableWriter struct {
 func NewTableWriter(w io.Writer) *TableWriter {
 	tw := &TableWriter{
 		w:      w,
-		color:  isTerminal(w),
+		color:  isTerminal(w) && styles.ColorEnabled(),
 		width:  80,
 		indent: "",
 	}
@@ -93,6 +94,7 @@ const (
 	colorGreen  = "\033[32m"
 	colorYellow = "\033[33m"
 	colorCyan   = "\033[36m"
+	colorFaint  = "\033[2m"
 )
 
 // writeClusterHealth writes the cluster health summary
@@ -221,11 +223,15 @@ func (tw *TableWriter) writeDeploymentsTable(deployments []k8s.DeploymentInfo) {
 		{header: "NODE", width: 20},
 		{header: "AGE", width: 8},
 		{header: "STATUS", width: 12},
+		{header: "VERSION", width: 14},
 	}
 
 	tw.writeTableHeader(cols)
 	tw.writeTableSeparator(cols)
 
+	// Daemons off the predominant Ceph version are mid-upgrade
+	cephVersion := k8s.PredominantCephVersion(deployments)
+
 	for _, dep := range deployments {
 		readyStr := fmt.Sprintf("%d/%d", dep.ReadyReplicas, dep.DesiredReplicas)
 		readyColor := colorGreen
@@ -251,6 +257,15 @@ func (tw *TableWriter) writeDeploymentsTable(deployments []k8s.DeploymentInfo) {
 			nodeName = nodeName[:15] + "..."
 		}
 
+		version := dep.Version()
+		if version == "" {
+			version = "-"
+		}
+		versionColor := ""
+		if dep.CephVersion != "" && dep.CephVersion != cephVersion {
+			versionColor = colorYellow
+		}
+
 		row := []cell{
 			{value: dep.Name},
 			{value: dep.Namespace},
@@ -258,6 +273,7 @@ func (tw *TableWriter) writeDeploymentsTable(deployments []k8s.DeploymentInfo) {
 			{value: nodeName},
 			{value: dep.Age},
 			{value: dep.Status, color: statusColor},
+			{value: version, color: versionColor},
 		}
 		tw.writeTableRow(cols, row)
 	}
@@ -272,6 +288,7 @@ func (tw *TableWriter) writeOSDsTable(osds []k8s.OSDInfo) {
 		{header: "IN/OUT", width: 8},
 		{header: "WEIGHT", width: 10},
 		{header: "CLASS", width: 8},
+		{header: "ISSUES", width: 10},
 		{header: "DEPLOYMENT", width: 30},
 	}
 
@@ -291,6 +308,16 @@ func (tw *TableWriter) writeOSDsTable(osds []k8s.OSDInfo) {
 
 		weightStr := fmt.Sprintf("%.3f", osd.Weight)
 
+		issue := osd.HealthIssue()
+		issueColor := colorYellow
+		if osd.SlowOps {
+			issueColor = colorRed
+		}
+		if issue == "" {
+			issue = "-"
+			issueColor = ""
+		}
+
 		deploymentName := osd.DeploymentName
 		if len(deploymentName) > 28 {
 			deploymentName = deploymentName[:25] + "..."
@@ -303,6 +330,7 @@ func (tw *TableWriter) writeOSDsTable(osds []k8s.OSDInfo) {
 			{value: osd.InOut, color: inOutColor},
 			{value: weightStr},
 			{value: osd.DeviceClass},
+			{value: issue, color: issueColor},
 			{value: deploymentName},
 		}
 		tw.writeTableRow(cols, row)
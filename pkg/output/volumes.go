@@ -0,0 +1,76 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+)
+
+// RenderNodeVolumes renders the 'crook check volumes' report in the given format
+func RenderNodeVolumes(w io.Writer, volumes *maintenance.NodeVolumes, format Format) error {
+	switch format {
+	case FormatTable:
+		NewTableWriter(w).writeNodeVolumesTable(volumes)
+		return nil
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(volumes)
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// volumeStatusColor colors a volume's status by whether it needs attention
+func volumeStatusColor(volume k8s.VolumeInfo) string {
+	switch {
+	case maintenance.StuckReason(volume) != "":
+		return colorRed
+	case volume.Attached:
+		return colorGreen
+	case volume.NodeAffinity:
+		return colorYellow
+	default:
+		return ""
+	}
+}
+
+// writeNodeVolumesTable writes the volumes tied to a node
+func (tw *TableWriter) writeNodeVolumesTable(volumes *maintenance.NodeVolumes) {
+	if len(volumes.Volumes) == 0 {
+		_, _ = fmt.Fprintln(tw.w, tw.colorize(fmt.Sprintf("No volumes attached or pinned to %s", volumes.Node), colorGreen))
+		return
+	}
+
+	cols := []column{
+		{header: "PERSISTENT VOLUME", width: 40},
+		{header: "CLAIM", width: 32},
+		{header: "STORAGE CLASS", width: 16},
+		{header: "DRIVER", width: 28},
+		{header: "STATUS", width: 40},
+	}
+
+	tw.writeTableHeader(cols)
+	tw.writeTableSeparator(cols)
+
+	for _, volume := range volumes.Volumes {
+		row := []cell{
+			{value: volume.PersistentVolume},
+			{value: volume.Claim},
+			{value: volume.StorageClass},
+			{value: volume.Driver},
+			{value: maintenance.VolumeStatus(volume), color: volumeStatusColor(volume)},
+		}
+		tw.writeTableRow(cols, row)
+	}
+
+	_, _ = fmt.Fprintln(tw.w)
+	if stuck := volumes.Stuck(); len(stuck) > 0 {
+		_, _ = fmt.Fprintln(tw.w, tw.colorize(fmt.Sprintf("%d volume attachment(s) look stuck", len(stuck)), colorRed))
+	} else {
+		_, _ = fmt.Fprintln(tw.w, tw.colorize("No stuck volume attachments", colorGreen))
+	}
+}
@@ -0,0 +1,59 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/output"
+)
+
+func TestRenderNodeVolumes(t *testing.T) {
+	volumes := &maintenance.NodeVolumes{
+		Node: "worker-1",
+		Volumes: []k8s.VolumeInfo{
+			{PersistentVolume: "pv-db", Claim: "apps/db", StorageClass: "ceph-block", Driver: "rook-ceph.rbd.csi.ceph.com", Attachment: "csi-1", Attached: true, InUse: true},
+			{PersistentVolume: "pv-old", Claim: "apps/old", StorageClass: "ceph-block", Driver: "rook-ceph.rbd.csi.ceph.com", Attachment: "csi-2", Attached: true},
+			{PersistentVolume: "pv-local", Claim: "apps/cache", StorageClass: "local", NodeAffinity: true},
+		},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.RenderNodeVolumes(&buf, volumes, output.FormatTable); err != nil {
+			t.Fatalf("RenderNodeVolumes() error: %v", err)
+		}
+		for _, want := range []string{"PERSISTENT VOLUME", "apps/db", "attached, in use", "stuck: attached, but no pod", "pinned to node", "1 volume attachment(s) look stuck"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("table output missing %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("empty table", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.RenderNodeVolumes(&buf, &maintenance.NodeVolumes{Node: "worker-1"}, output.FormatTable); err != nil {
+			t.Fatalf("RenderNodeVolumes() error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "No volumes attached or pinned to worker-1") {
+			t.Errorf("table output = %q", buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.RenderNodeVolumes(&buf, volumes, output.FormatJSON); err != nil {
+			t.Fatalf("RenderNodeVolumes() error: %v", err)
+		}
+		var decoded maintenance.NodeVolumes
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if decoded.Node != "worker-1" || len(decoded.Volumes) != 3 || !decoded.Volumes[2].NodeAffinity {
+			t.Errorf("decoded = %+v, want the three volumes", decoded)
+		}
+	})
+}
@@ -0,0 +1,320 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	crookerrors "github.com/andri/crook/pkg/errors"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/output"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// PlanItem is a workload affected by a maintenance phase
+type PlanItem struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Replicas  int32  `json:"replicas"`
+}
+
+// Plan describes what a maintenance phase would do to a node
+type Plan struct {
+	Node  string `json:"node"`
+	Phase string `json:"phase"`
+	// AlreadyInState is true when the node is already in the phase's target state
+	AlreadyInState bool `json:"already_in_state"`
+	// External is true for an external Ceph cluster, where only the cordon
+	// and noout flag are managed
+	External    bool       `json:"external,omitempty"`
+	Deployments []PlanItem `json:"deployments"`
+	// StatefulSets lists the node-pinned StatefulSets scaled after the
+	// deployments when discovery.statefulsets is set
+	StatefulSets []PlanItem `json:"statefulsets,omitempty"`
+	// Excluded lists the workloads discovery.exclude keeps running
+	Excluded []PlanItem `json:"excluded,omitempty"`
+	// AlreadyDown and ToScale split a re-entered down plan into workloads
+	// already at 0 replicas, which are left alone, and those still to scale
+	AlreadyDown int `json:"already_down,omitempty"`
+	ToScale     int `json:"to_scale,omitempty"`
+}
+
+// Plan computes the deployments a phase would scale, in execution order
+func (s *Server) Plan(ctx context.Context, nodeName, phase string) (*Plan, error) {
+	client := s.opts.Client
+	cfg := s.opts.Config
+
+	var deployments, excluded []appsv1.Deployment
+	var statefulSets, excludedSets []appsv1.StatefulSet
+	var alreadyInState bool
+	external := maintenance.IsExternalCluster(ctx, client, cfg)
+
+	switch phase {
+	case PhaseDown:
+		discovered, err := client.ListNodePinnedDeployments(ctx, cfg.Namespace, nodeName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover deployments: %w", err)
+		}
+		deployments, excluded = maintenance.ExcludeDeployments(maintenance.OrderDeploymentsForDown(discovered), cfg.Discovery)
+		statefulSets, excludedSets, err = maintenance.DiscoverStatefulSets(ctx, client, cfg, nodeName, nil)
+		if err != nil {
+			return nil, err
+		}
+		alreadyInState = maintenance.IsInDownState(ctx, client, cfg, nodeName, deployments)
+	case PhaseUp:
+		discovered, err := client.ListScaledDownDeploymentsForNode(ctx, cfg.Namespace, nodeName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover deployments: %w", err)
+		}
+		deployments, excluded = maintenance.ExcludeDeployments(maintenance.OrderDeploymentsForUp(discovered), cfg.Discovery)
+		statefulSets, excludedSets, err = maintenance.DiscoverScaledDownStatefulSets(ctx, client, cfg, nodeName, nil)
+		if err != nil {
+			return nil, err
+		}
+		alreadyInState = (len(deployments) == 0 && len(statefulSets) == 0 && !external) || maintenance.IsInUpState(ctx, client, cfg, nodeName, deployments)
+	default:
+		return nil, fmt.Errorf("invalid phase %q: must be %s or %s", phase, PhaseDown, PhaseUp)
+	}
+
+	plan := &Plan{
+		Node:           nodeName,
+		Phase:          phase,
+		AlreadyInState: alreadyInState,
+		External:       external,
+		Deployments:    planItems(deployments),
+	}
+	if len(statefulSets) > 0 {
+		plan.StatefulSets = statefulSetPlanItems(statefulSets)
+	}
+	if len(excluded) > 0 || len(excludedSets) > 0 {
+		plan.Excluded = append(planItems(excluded), statefulSetPlanItems(excludedSets)...)
+	}
+	if phase == PhaseDown {
+		partial := maintenance.NewPartialPlan(append(k8s.DeploymentWorkloads(deployments), k8s.StatefulSetWorkloads(statefulSets)...))
+		plan.AlreadyDown, plan.ToScale = partial.AlreadyDown, partial.ToScale
+	}
+	return plan, nil
+}
+
+// planItems describes deployments as plan items
+func planItems(deployments []appsv1.Deployment) []PlanItem {
+	items := make([]PlanItem, 0, len(deployments))
+	for _, dep := range deployments {
+		var replicas int32
+		if dep.Spec.Replicas != nil {
+			replicas = *dep.Spec.Replicas
+		}
+		items = append(items, PlanItem{
+			Kind:      k8s.WorkloadKindDeployment,
+			Namespace: dep.Namespace,
+			Name:      dep.Name,
+			Replicas:  replicas,
+		})
+	}
+	return items
+}
+
+// statefulSetPlanItems describes StatefulSets as plan items
+func statefulSetPlanItems(statefulSets []appsv1.StatefulSet) []PlanItem {
+	items := make([]PlanItem, 0, len(statefulSets))
+	for _, sts := range statefulSets {
+		var replicas int32
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+		items = append(items, PlanItem{
+			Kind:      k8s.WorkloadKindStatefulSet,
+			Namespace: sts.Namespace,
+			Name:      sts.Name,
+			Replicas:  replicas,
+		})
+	}
+	return items
+}
+
+// handleStatus returns the same data as `crook ls --output json`
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	resourceTypes, err := output.ParseResourceTypes(r.URL.Query().Get("show"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.writeData(w, r, resourceTypes)
+}
+
+// handleNodes returns nodes hosting Ceph pods
+func (s *Server) handleNodes(w http.ResponseWriter, r *http.Request) {
+	s.writeData(w, r, []output.ResourceType{output.ResourceNodes})
+}
+
+// handleOSDs returns Ceph OSDs
+func (s *Server) handleOSDs(w http.ResponseWriter, r *http.Request) {
+	s.writeData(w, r, []output.ResourceType{output.ResourceOSDs})
+}
+
+// writeData fetches and writes cluster data for the given resource types
+func (s *Server) writeData(w http.ResponseWriter, r *http.Request, resourceTypes []output.ResourceType) {
+	data, err := output.FetchData(r.Context(), output.FetchOptions{
+		Client:        s.opts.Client,
+		Config:        s.opts.Config,
+		ResourceTypes: resourceTypes,
+		NodeFilter:    r.URL.Query().Get("node"),
+	})
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, data)
+}
+
+// handlePlan returns the plan for a phase (?phase=down|up, default down)
+func (s *Server) handlePlan(w http.ResponseWriter, r *http.Request) {
+	nodeName := r.PathValue("node")
+	phase := r.URL.Query().Get("phase")
+	if phase == "" {
+		phase = PhaseDown
+	}
+	if phase != PhaseDown && phase != PhaseUp {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid phase %q: must be %s or %s", phase, PhaseDown, PhaseUp))
+		return
+	}
+	if !s.requireNode(w, r, nodeName) {
+		return
+	}
+
+	plan, err := s.Plan(r.Context(), nodeName, phase)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, plan)
+}
+
+// handleStartDown starts a down phase. The optional acknowledge_warnings query
+// parameter proceeds past pre-flight checks that only warn.
+func (s *Server) handleStartDown(w http.ResponseWriter, r *http.Request) {
+	acknowledgeWarnings := false
+	if value := r.URL.Query().Get("acknowledge_warnings"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid acknowledge_warnings %q: must be true or false", value))
+			return
+		}
+		acknowledgeWarnings = parsed
+	}
+	s.handleStart(w, r, func(nodeName string, signOff maintenance.SignOff) (OperationStatus, error) {
+		return s.StartDown(nodeName, signOff, acknowledgeWarnings)
+	})
+}
+
+// handleStartUp starts an up phase
+func (s *Server) handleStartUp(w http.ResponseWriter, r *http.Request) {
+	s.handleStart(w, r, s.StartUp)
+}
+
+// handleStart validates the node and starts an operation. The optional
+// reason and ticket query parameters are the operation's sign-off.
+func (s *Server) handleStart(
+	w http.ResponseWriter,
+	r *http.Request,
+	start func(string, maintenance.SignOff) (OperationStatus, error),
+) {
+	nodeName := r.PathValue("node")
+	if !s.requireNode(w, r, nodeName) {
+		return
+	}
+
+	status, err := start(nodeName, maintenance.SignOff{
+		Reason: r.URL.Query().Get("reason"),
+		Ticket: r.URL.Query().Get("ticket"),
+	})
+	if err != nil {
+		if errors.Is(err, ErrOperationInProgress) {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, status)
+}
+
+// handleListOperations returns all operations, most recent first
+func (s *Server) handleListOperations(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	statuses := make([]OperationStatus, 0, len(s.operations))
+	for _, op := range s.operations {
+		statuses = append(statuses, op.status())
+	}
+	s.mu.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].StartedAt.After(statuses[j].StartedAt)
+	})
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+// handleGetOperation returns a single operation
+func (s *Server) handleGetOperation(w http.ResponseWriter, r *http.Request) {
+	status, ok := s.Operation(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("operation %q not found", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// handleStreamEvents streams progress events as newline-delimited JSON until
+// the operation finishes. The final line is the operation status.
+func (s *Server) handleStreamEvents(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, ok := s.Operation(id); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("operation %q not found", id))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	flush()
+
+	err := s.Subscribe(r.Context(), id, func(event ProgressEvent) {
+		_ = encoder.Encode(event)
+		flush()
+	})
+	if err != nil {
+		return
+	}
+
+	if status, ok := s.Operation(id); ok {
+		_ = encoder.Encode(status)
+		flush()
+	}
+}
+
+// requireNode writes an error response and returns false if the node does not exist
+func (s *Server) requireNode(w http.ResponseWriter, r *http.Request, nodeName string) bool {
+	exists, err := s.opts.Client.NodeExists(r.Context(), nodeName)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("failed to check if node %q exists: %w", nodeName, err))
+		return false
+	}
+	if !exists {
+		writeError(w, http.StatusNotFound, crookerrors.NodeNotFound(nodeName))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/andri/crook/pkg/maintenance"
+)
+
+// Operation phases
+const (
+	PhaseDown = "down"
+	PhaseUp   = "up"
+)
+
+// Operation states
+const (
+	StateRunning   = "running"
+	StateSucceeded = "succeeded"
+	StateFailed    = "failed"
+)
+
+// ProgressEvent is a single progress update emitted by a running operation
+type ProgressEvent struct {
+	// Seq is the 1-based position of the event within the operation
+	Seq int `json:"seq"`
+	// Stage is the maintenance stage (e.g. cordon, noout, scale-down)
+	Stage string `json:"stage"`
+	// Description is a human-readable description of the step
+	Description string `json:"description"`
+	// Deployment is the deployment being processed, if any
+	Deployment string `json:"deployment,omitempty"`
+	// Percent is the share of the phase done, 0-100
+	Percent int `json:"percent"`
+	// Time is when the event was recorded
+	Time time.Time `json:"time"`
+}
+
+// OperationStatus is a point-in-time snapshot of an operation
+type OperationStatus struct {
+	ID         string          `json:"id"`
+	Node       string          `json:"node"`
+	Phase      string          `json:"phase"`
+	Reason     string          `json:"reason,omitempty"`
+	Ticket     string          `json:"ticket,omitempty"`
+	State      string          `json:"state"`
+	Error      string          `json:"error,omitempty"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+	Events     []ProgressEvent `json:"events"`
+}
+
+// operation tracks a maintenance phase started through the API
+type operation struct {
+	mu         sync.Mutex
+	id         string
+	node       string
+	phase      string
+	signOff    maintenance.SignOff
+	state      string
+	err        error
+	startedAt  time.Time
+	finishedAt time.Time
+	events     []ProgressEvent
+
+	// changed is closed and replaced whenever the operation is updated,
+	// waking up any streaming readers
+	changed chan struct{}
+}
+
+// newOperation creates a running operation with a random ID
+func newOperation(node, phase string, signOff maintenance.SignOff) *operation {
+	return &operation{
+		id:        newOperationID(),
+		node:      node,
+		phase:     phase,
+		signOff:   signOff,
+		state:     StateRunning,
+		startedAt: time.Now(),
+		changed:   make(chan struct{}),
+	}
+}
+
+// newOperationID returns a random hex identifier
+func newOperationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// addEvent records a progress event and notifies readers
+func (o *operation) addEvent(stage, description, deployment string, percent int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.events = append(o.events, ProgressEvent{
+		Seq:         len(o.events) + 1,
+		Stage:       stage,
+		Description: description,
+		Deployment:  deployment,
+		Percent:     percent,
+		Time:        time.Now(),
+	})
+	o.notifyLocked()
+}
+
+// finish marks the operation as complete and notifies readers
+func (o *operation) finish(err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.err = err
+	o.finishedAt = time.Now()
+	if err != nil {
+		o.state = StateFailed
+	} else {
+		o.state = StateSucceeded
+	}
+	o.notifyLocked()
+}
+
+// notifyLocked wakes streaming readers. Caller must hold o.mu.
+func (o *operation) notifyLocked() {
+	close(o.changed)
+	o.changed = make(chan struct{})
+}
+
+// running reports whether the operation is still in progress
+func (o *operation) running() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.state == StateRunning
+}
+
+// eventsSince returns events after the given count, whether the operation is
+// finished, and a channel that is closed on the next update.
+func (o *operation) eventsSince(n int) ([]ProgressEvent, bool, <-chan struct{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var events []ProgressEvent
+	if n < len(o.events) {
+		events = append(events, o.events[n:]...)
+	}
+	return events, o.state != StateRunning, o.changed
+}
+
+// status returns a snapshot of the operation
+func (o *operation) status() OperationStatus {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	status := OperationStatus{
+		ID:        o.id,
+		Node:      o.node,
+		Phase:     o.phase,
+		Reason:    o.signOff.Reason,
+		Ticket:    o.signOff.Ticket,
+		State:     o.state,
+		StartedAt: o.startedAt,
+		Events:    append([]ProgressEvent{}, o.events...),
+	}
+	if o.err != nil {
+		status.Error = o.err.Error()
+	}
+	if !o.finishedAt.IsZero() {
+		finished := o.finishedAt
+		status.FinishedAt = &finished
+	}
+	return status
+}
@@ -0,0 +1,261 @@
+// Package server exposes the maintenance engine over an authenticated HTTP API
+// so that web UIs and chatops bots can drive maintenance alongside the TUI.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/pkg/config"
+	crookerrors "github.com/andri/crook/pkg/errors"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+)
+
+const (
+	// DefaultDownTimeout bounds a down phase started through the API
+	DefaultDownTimeout = 10 * time.Minute
+
+	// DefaultUpTimeout bounds an up phase started through the API
+	DefaultUpTimeout = 15 * time.Minute
+)
+
+// ErrOperationInProgress is returned when a maintenance operation is already running
+var ErrOperationInProgress = errors.New("another maintenance operation is in progress")
+
+// Options configures the API server
+type Options struct {
+	// Client is the Kubernetes client used for all operations
+	Client *k8s.Client
+
+	// Config is the application configuration
+	Config config.Config
+
+	// Token is the bearer token clients must present. Required.
+	Token string
+
+	// DownTimeout bounds down operations (default: DefaultDownTimeout)
+	DownTimeout time.Duration
+
+	// UpTimeout bounds up operations (default: DefaultUpTimeout)
+	UpTimeout time.Duration
+
+	// ExecuteDown runs the down phase (default: maintenance.ExecuteDownPhase)
+	ExecuteDown func(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, opts maintenance.DownPhaseOptions) error
+
+	// ExecuteUp runs the up phase (default: maintenance.ExecuteUpPhase)
+	ExecuteUp func(ctx context.Context, client *k8s.Client, cfg config.Config, nodeName string, opts maintenance.UpPhaseOptions) error
+}
+
+// Server serves the crook HTTP API
+type Server struct {
+	ctx  context.Context
+	opts Options
+
+	mu         sync.Mutex
+	operations map[string]*operation
+	active     *operation
+	wg         sync.WaitGroup
+}
+
+// New creates a server. Operations started through the API are bound to ctx
+// and are cancelled when it is done.
+func New(ctx context.Context, opts Options) (*Server, error) {
+	if opts.Token == "" {
+		return nil, fmt.Errorf("an API token is required")
+	}
+	if opts.Client == nil {
+		return nil, fmt.Errorf("a kubernetes client is required")
+	}
+	if opts.DownTimeout <= 0 {
+		opts.DownTimeout = DefaultDownTimeout
+	}
+	if opts.UpTimeout <= 0 {
+		opts.UpTimeout = DefaultUpTimeout
+	}
+	if opts.ExecuteDown == nil {
+		opts.ExecuteDown = maintenance.ExecuteDownPhase
+	}
+	if opts.ExecuteUp == nil {
+		opts.ExecuteUp = maintenance.ExecuteUpPhase
+	}
+
+	return &Server{
+		ctx:        ctx,
+		opts:       opts,
+		operations: make(map[string]*operation),
+	}, nil
+}
+
+// Handler returns the HTTP handler for the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/v1/status", s.handleStatus)
+	mux.HandleFunc("GET /api/v1/nodes", s.handleNodes)
+	mux.HandleFunc("GET /api/v1/osds", s.handleOSDs)
+	mux.HandleFunc("GET /api/v1/nodes/{node}/plan", s.handlePlan)
+	mux.HandleFunc("POST /api/v1/nodes/{node}/down", s.handleStartDown)
+	mux.HandleFunc("POST /api/v1/nodes/{node}/up", s.handleStartUp)
+	mux.HandleFunc("GET /api/v1/operations", s.handleListOperations)
+	mux.HandleFunc("GET /api/v1/operations/{id}", s.handleGetOperation)
+	mux.HandleFunc("GET /api/v1/operations/{id}/events", s.handleStreamEvents)
+
+	return s.authenticate(mux)
+}
+
+// Wait blocks until all running operations have returned
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+// authenticate rejects requests without the configured bearer token
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) != 1 {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// StartDown starts a down phase for the node in the background. signOff
+// may be zero. acknowledgeWarnings proceeds past pre-flight checks that only
+// warn, like 'crook down --acknowledge-warnings'.
+func (s *Server) StartDown(nodeName string, signOff maintenance.SignOff, acknowledgeWarnings bool) (OperationStatus, error) {
+	return s.start(nodeName, PhaseDown, signOff, func(ctx context.Context, op *operation) error {
+		return s.opts.ExecuteDown(ctx, s.opts.Client, s.opts.Config, nodeName, maintenance.DownPhaseOptions{
+			ProgressCallback: func(p maintenance.DownPhaseProgress) {
+				op.addEvent(p.Stage, p.Description, p.Deployment, p.Percent)
+			},
+			SignOff:             signOff,
+			AcknowledgeWarnings: acknowledgeWarnings,
+		})
+	})
+}
+
+// StartUp starts an up phase for the node in the background. signOff may
+// be zero.
+func (s *Server) StartUp(nodeName string, signOff maintenance.SignOff) (OperationStatus, error) {
+	return s.start(nodeName, PhaseUp, signOff, func(ctx context.Context, op *operation) error {
+		return s.opts.ExecuteUp(ctx, s.opts.Client, s.opts.Config, nodeName, maintenance.UpPhaseOptions{
+			ProgressCallback: func(p maintenance.UpPhaseProgress) {
+				op.addEvent(p.Stage, p.Description, p.Deployment, p.Percent)
+			},
+			SignOff: signOff,
+		})
+	})
+}
+
+// Operation returns the status of an operation by ID
+func (s *Server) Operation(id string) (OperationStatus, bool) {
+	op := s.lookup(id)
+	if op == nil {
+		return OperationStatus{}, false
+	}
+	return op.status(), true
+}
+
+// Subscribe streams progress events of an operation to fn until it finishes
+// or ctx is done. Events already recorded are delivered first.
+func (s *Server) Subscribe(ctx context.Context, id string, fn func(ProgressEvent)) error {
+	op := s.lookup(id)
+	if op == nil {
+		return fmt.Errorf("operation %q not found", id)
+	}
+
+	seen := 0
+	for {
+		events, done, changed := op.eventsSince(seen)
+		for _, event := range events {
+			fn(event)
+		}
+		seen += len(events)
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// start registers an operation and runs it in the background.
+// Only one operation may run at a time since noout and the operator are cluster-wide.
+func (s *Server) start(
+	nodeName, phase string,
+	signOff maintenance.SignOff,
+	run func(ctx context.Context, op *operation) error,
+) (OperationStatus, error) {
+	s.mu.Lock()
+	if s.active != nil && s.active.running() {
+		s.mu.Unlock()
+		return OperationStatus{}, ErrOperationInProgress
+	}
+	op := newOperation(nodeName, phase, signOff)
+	s.operations[op.id] = op
+	s.active = op
+	s.mu.Unlock()
+
+	timeout := s.opts.DownTimeout
+	if phase == PhaseUp {
+		timeout = s.opts.UpTimeout
+	}
+
+	logger.Info("starting maintenance operation", "id", op.id, "phase", phase, "node", nodeName,
+		"reason", signOff.Reason, "ticket", signOff.Ticket)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ctx, cancel := context.WithTimeout(s.ctx, timeout)
+		defer cancel()
+
+		err := run(ctx, op)
+		op.finish(err)
+		if err != nil {
+			logger.Error("maintenance operation failed", "id", op.id, "phase", phase, "node", nodeName, "error", err)
+		} else {
+			logger.Info("maintenance operation completed", "id", op.id, "phase", phase, "node", nodeName)
+		}
+	}()
+
+	return op.status(), nil
+}
+
+// lookup returns an operation by ID, or nil
+func (s *Server) lookup(id string) *operation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.operations[id]
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error as a JSON response
+func writeError(w http.ResponseWriter, code int, err error) {
+	body := map[string]string{"error": err.Error()}
+	if hint := crookerrors.Hint(err); hint != "" {
+		body["hint"] = hint
+	}
+	writeJSON(w, code, body)
+}
@@ -0,0 +1,260 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testToken = "secret-token"
+
+func newTestServer(t *testing.T, opts Options) (*Server, *httptest.Server) {
+	t.Helper()
+
+	clientset := fake.NewClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+	})
+	opts.Client = &k8s.Client{Clientset: clientset}
+	opts.Config = config.DefaultConfig()
+	opts.Token = testToken
+
+	srv, err := New(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(func() {
+		ts.Close()
+		srv.Wait()
+	})
+	return srv, ts
+}
+
+func doRequest(t *testing.T, method, url, token string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), method, url, nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	return resp
+}
+
+func TestNewRequiresToken(t *testing.T) {
+	_, err := New(context.Background(), Options{Client: &k8s.Client{Clientset: fake.NewClientset()}})
+	if err == nil {
+		t.Error("expected error without token")
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	_, ts := newTestServer(t, Options{})
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"wrong token", "nope", http.StatusUnauthorized},
+		{"valid token", testToken, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := doRequest(t, http.MethodGet, ts.URL+"/api/v1/operations", tt.token)
+			defer func() { _ = resp.Body.Close() }()
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestStartDownUnknownNode(t *testing.T) {
+	_, ts := newTestServer(t, Options{})
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/api/v1/nodes/missing/down", testToken)
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestPlanInvalidPhase(t *testing.T) {
+	_, ts := newTestServer(t, Options{})
+
+	resp := doRequest(t, http.MethodGet, ts.URL+"/api/v1/nodes/worker-1/plan?phase=sideways", testToken)
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestStartDownStreamsProgress(t *testing.T) {
+	release := make(chan struct{})
+	_, ts := newTestServer(t, Options{
+		ExecuteDown: func(_ context.Context, _ *k8s.Client, _ config.Config, _ string, opts maintenance.DownPhaseOptions) error {
+			opts.ProgressCallback(maintenance.DownPhaseProgress{Stage: "cordon", Description: "Cordoning node worker-1"})
+			<-release
+			opts.ProgressCallback(maintenance.DownPhaseProgress{Stage: "complete", Description: "Down phase completed"})
+			return nil
+		},
+	})
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/api/v1/nodes/worker-1/down", testToken)
+	var started OperationStatus
+	if err := json.NewDecoder(resp.Body).Decode(&started); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+	if started.Phase != PhaseDown || started.Node != "worker-1" {
+		t.Errorf("unexpected operation %+v", started)
+	}
+
+	// A second operation is rejected while the first is running
+	conflict := doRequest(t, http.MethodPost, ts.URL+"/api/v1/nodes/worker-1/up", testToken)
+	_ = conflict.Body.Close()
+	if conflict.StatusCode != http.StatusConflict {
+		t.Errorf("concurrent start status = %d, want %d", conflict.StatusCode, http.StatusConflict)
+	}
+
+	close(release)
+
+	stream := doRequest(t, http.MethodGet, ts.URL+"/api/v1/operations/"+started.ID+"/events", testToken)
+	defer func() { _ = stream.Body.Close() }()
+
+	var lines []string
+	scanner := bufio.NewScanner(stream.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 3 {
+		t.Fatalf("expected 2 events and a final status, got %d lines: %v", len(lines), lines)
+	}
+
+	var event ProgressEvent
+	if err := json.Unmarshal([]byte(lines[0]), &event); err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+	if event.Seq != 1 || event.Stage != "cordon" {
+		t.Errorf("unexpected first event %+v", event)
+	}
+
+	var final OperationStatus
+	if err := json.Unmarshal([]byte(lines[2]), &final); err != nil {
+		t.Fatalf("failed to decode final status: %v", err)
+	}
+	if final.State != StateSucceeded || final.FinishedAt == nil {
+		t.Errorf("unexpected final status %+v", final)
+	}
+}
+
+func TestStartDownSignOff(t *testing.T) {
+	signOffs := make(chan maintenance.SignOff, 1)
+	_, ts := newTestServer(t, Options{
+		ExecuteDown: func(_ context.Context, _ *k8s.Client, _ config.Config, _ string, opts maintenance.DownPhaseOptions) error {
+			signOffs <- opts.SignOff
+			return nil
+		},
+	})
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/api/v1/nodes/worker-1/down?ticket=CHG-1&reason=replace+disk", testToken)
+	var started OperationStatus
+	if err := json.NewDecoder(resp.Body).Decode(&started); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if started.Ticket != "CHG-1" || started.Reason != "replace disk" {
+		t.Errorf("operation sign-off = %q/%q, want CHG-1/replace disk", started.Ticket, started.Reason)
+	}
+	want := maintenance.SignOff{Reason: "replace disk", Ticket: "CHG-1"}
+	if got := <-signOffs; got != want {
+		t.Errorf("down phase sign-off = %+v, want %+v", got, want)
+	}
+}
+
+func TestStartDownAcknowledgeWarnings(t *testing.T) {
+	acknowledged := make(chan bool, 1)
+	_, ts := newTestServer(t, Options{
+		ExecuteDown: func(_ context.Context, _ *k8s.Client, _ config.Config, _ string, opts maintenance.DownPhaseOptions) error {
+			acknowledged <- opts.AcknowledgeWarnings
+			return nil
+		},
+	})
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/api/v1/nodes/worker-1/down?acknowledge_warnings=maybe", testToken)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid acknowledge_warnings status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	resp = doRequest(t, http.MethodPost, ts.URL+"/api/v1/nodes/worker-1/down?acknowledge_warnings=true", testToken)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+	if !<-acknowledged {
+		t.Error("down phase did not acknowledge the pre-flight warnings")
+	}
+}
+
+func TestOperationFailureIsRecorded(t *testing.T) {
+	srv, _ := newTestServer(t, Options{
+		ExecuteUp: func(_ context.Context, _ *k8s.Client, _ config.Config, _ string, _ maintenance.UpPhaseOptions) error {
+			return errors.New("uncordon failed")
+		},
+	})
+
+	started, err := srv.StartUp("worker-1", maintenance.SignOff{})
+	if err != nil {
+		t.Fatalf("StartUp() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if subErr := srv.Subscribe(ctx, started.ID, func(ProgressEvent) {}); subErr != nil {
+		t.Fatalf("Subscribe() error = %v", subErr)
+	}
+
+	status, ok := srv.Operation(started.ID)
+	if !ok {
+		t.Fatal("operation not found")
+	}
+	if status.State != StateFailed || status.Error != "uncordon failed" {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestGetUnknownOperation(t *testing.T) {
+	_, ts := newTestServer(t, Options{})
+
+	resp := doRequest(t, http.MethodGet, ts.URL+"/api/v1/operations/unknown", testToken)
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/andri/crook/internal/clock"
 	"github.com/andri/crook/pkg/tui/format"
 	"github.com/andri/crook/pkg/tui/styles"
 )
@@ -30,7 +31,21 @@ type ClusterHeaderData struct {
 	MonsInQuorum int // Monitors in quorum
 
 	// Flags
-	NooutSet bool
+	NooutSet    bool
+	NooutGroups []string // CRUSH hosts and OSDs with a noout flag of their own
+	Flags       []string // All active cluster-wide OSD flags, noout included
+
+	// Stretch mode state: empty for flat clusters, otherwise "active",
+	// "degraded" or "recovering"
+	StretchMode   string
+	TiebreakerMon string
+
+	// Data movement modules. BalancerMode is empty when the balancer state is
+	// unknown; AutoscalePools is zero when the autoscaler state is unknown.
+	BalancerActive bool
+	BalancerMode   string
+	AutoscaleOn    int // Pools with pg_autoscale_mode=on
+	AutoscalePools int // Total pools
 
 	// Storage usage
 	UsedBytes  int64
@@ -53,12 +68,16 @@ type ClusterHeader struct {
 
 	// error holds any error from data fetching
 	err error
+
+	// clock tells the age of the data for the staleness display
+	clock clock.Clock
 }
 
 // NewClusterHeader creates a new cluster header component
 func NewClusterHeader() *ClusterHeader {
 	return &ClusterHeader{
 		loading: true,
+		clock:   clock.Real(),
 	}
 }
 
@@ -137,11 +156,23 @@ func (h *ClusterHeader) renderFull() string {
 	b.WriteString(h.renderMonStats())
 	b.WriteString("  ")
 	b.WriteString(h.renderNooutFlag())
+	if h.data.StretchMode != "" {
+		b.WriteString("  ")
+		b.WriteString(h.renderStretchMode())
+	}
 	b.WriteString("\n")
 
-	// Row 2: Storage usage and last updated
+	// Row 2: Storage usage, balancer, autoscaler and last updated
 	b.WriteString(h.renderStorageUsage())
 	b.WriteString("  ")
+	if h.data.BalancerMode != "" {
+		b.WriteString(h.renderBalancer())
+		b.WriteString("  ")
+	}
+	if h.data.AutoscalePools > 0 {
+		b.WriteString(h.renderAutoscale())
+		b.WriteString("  ")
+	}
 	b.WriteString(h.renderLastUpdated())
 
 	return b.String()
@@ -157,9 +188,13 @@ func (h *ClusterHeader) renderCompact() string {
 	b.WriteString(" ")
 	b.WriteString(styles.StyleSubtle.Render(fmt.Sprintf("MONs:%d/%d in quorum", h.data.MonsInQuorum, h.data.MonsTotal)))
 
-	if h.data.NooutSet {
+	switch {
+	case h.data.NooutSet:
 		b.WriteString(" ")
 		b.WriteString(styles.StyleWarning.Render(styles.IconWarning + "noout"))
+	case len(h.data.NooutGroups) > 0:
+		b.WriteString(" ")
+		b.WriteString(styles.StyleWarning.Render(styles.IconWarning + "noout:" + strings.Join(h.data.NooutGroups, ",")))
 	}
 
 	return b.String()
@@ -219,14 +254,65 @@ func (h *ClusterHeader) renderMonStats() string {
 	)
 }
 
-// renderNooutFlag renders the noout flag status
+// renderNooutFlag renders the noout flag status and scope: cluster-wide, or
+// the hosts and OSDs carrying their own flag
 func (h *ClusterHeader) renderNooutFlag() string {
-	if h.data.NooutSet {
-		return styles.StyleWarning.Render(styles.IconWarning + " noout: " + styles.IconCheckmark)
+	switch {
+	case h.data.NooutSet:
+		return styles.StyleWarning.Render(styles.IconWarning + " noout: " + styles.IconCheckmark + " cluster-wide")
+	case len(h.data.NooutGroups) > 0:
+		return styles.StyleWarning.Render(styles.IconWarning + " noout: " + strings.Join(h.data.NooutGroups, ", "))
 	}
 	return styles.StyleSubtle.Render("noout: " + styles.IconCross)
 }
 
+// nooutActive reports whether noout is set cluster-wide or on any host or OSD
+func (h *ClusterHeader) nooutActive() bool {
+	return h.data.NooutSet || len(h.data.NooutGroups) > 0
+}
+
+// renderStretchMode renders the stretch mode state and tiebreaker monitor. A
+// degraded stretch cluster already runs on a single zone.
+func (h *ClusterHeader) renderStretchMode() string {
+	style := styles.StyleSuccess
+	switch h.data.StretchMode {
+	case "degraded":
+		style = styles.StyleError
+	case "recovering":
+		style = styles.StyleWarning
+	}
+	stretch := "Stretch: " + style.Render(h.data.StretchMode)
+	if h.data.TiebreakerMon != "" {
+		stretch += styles.StyleSubtle.Render(" (tiebreaker " + h.data.TiebreakerMon + ")")
+	}
+	return stretch
+}
+
+// renderBalancer renders the balancer state; an active balancer is highlighted
+// while noout is set because it may move data around the down OSDs
+func (h *ClusterHeader) renderBalancer() string {
+	if !h.data.BalancerActive {
+		return styles.StyleSubtle.Render("Balancer: off")
+	}
+	style := styles.StyleNormal
+	if h.nooutActive() {
+		style = styles.StyleWarning
+	}
+	return "Balancer: " + style.Render(h.data.BalancerMode)
+}
+
+// renderAutoscale renders how many pools have the pg_autoscaler enabled
+func (h *ClusterHeader) renderAutoscale() string {
+	style := styles.StyleSubtle
+	if h.data.AutoscaleOn > 0 {
+		style = styles.StyleNormal
+		if h.nooutActive() {
+			style = styles.StyleWarning
+		}
+	}
+	return "Autoscale: " + style.Render(fmt.Sprintf("%d/%d pools", h.data.AutoscaleOn, h.data.AutoscalePools))
+}
+
 // renderStorageUsage renders storage usage information
 func (h *ClusterHeader) renderStorageUsage() string {
 	if h.data.TotalBytes == 0 {
@@ -261,7 +347,7 @@ func (h *ClusterHeader) renderLastUpdated() string {
 		return styles.StyleSubtle.Render("Last updated: never")
 	}
 
-	elapsed := time.Since(h.data.LastUpdate)
+	elapsed := h.clock.Since(h.data.LastUpdate)
 	var timeStr string
 
 	switch {
@@ -276,6 +362,11 @@ func (h *ClusterHeader) renderLastUpdated() string {
 	return styles.StyleSubtle.Render("Last updated: " + timeStr)
 }
 
+// SetClock sets the clock the age of the data is measured with
+func (h *ClusterHeader) SetClock(c clock.Clock) {
+	h.clock = clock.OrReal(c)
+}
+
 // SetData updates the header data
 func (h *ClusterHeader) SetData(data *ClusterHeaderData) {
 	h.data = data
@@ -283,6 +374,17 @@ func (h *ClusterHeader) SetData(data *ClusterHeaderData) {
 	h.err = nil
 }
 
+// SetLastUpdate advances the refresh time of the displayed data without
+// replacing it. The data is shared with the monitor, so it is copied.
+func (h *ClusterHeader) SetLastUpdate(t time.Time) {
+	if h.data == nil || t.IsZero() || !t.After(h.data.LastUpdate) {
+		return
+	}
+	data := *h.data
+	data.LastUpdate = t
+	h.data = &data
+}
+
 // SetError sets an error state
 func (h *ClusterHeader) SetError(err error) {
 	h.err = err
@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/andri/crook/test/util/fakeclock"
 )
 
 func TestNewClusterHeader(t *testing.T) {
@@ -45,6 +47,28 @@ func TestClusterHeader_SetData(t *testing.T) {
 	}
 }
 
+func TestClusterHeader_SetLastUpdate(t *testing.T) {
+	h := NewClusterHeader()
+	earlier := time.Now().Add(-time.Minute)
+	data := &ClusterHeaderData{Health: "HEALTH_OK", LastUpdate: earlier}
+	h.SetData(data)
+
+	later := earlier.Add(30 * time.Second)
+	h.SetLastUpdate(later)
+
+	if got := h.GetData().LastUpdate; !got.Equal(later) {
+		t.Errorf("LastUpdate = %v, want %v", got, later)
+	}
+	if !data.LastUpdate.Equal(earlier) {
+		t.Error("SetLastUpdate must not modify the shared data")
+	}
+
+	h.SetLastUpdate(earlier)
+	if got := h.GetData().LastUpdate; !got.Equal(later) {
+		t.Errorf("older time moved LastUpdate back to %v", got)
+	}
+}
+
 func TestClusterHeader_View_Loading(t *testing.T) {
 	h := NewClusterHeader()
 	view := h.Render()
@@ -142,6 +166,92 @@ func TestClusterHeader_View_NooutSet(t *testing.T) {
 	}
 }
 
+func TestClusterHeader_View_BalancerAndAutoscale(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        ClusterHeaderData
+		wantContain []string
+		wantMissing []string
+	}{
+		{
+			name:        "unknown state is hidden",
+			data:        ClusterHeaderData{Health: "HEALTH_OK"},
+			wantMissing: []string{"Balancer:", "Autoscale:"},
+		},
+		{
+			name:        "balancer active",
+			data:        ClusterHeaderData{Health: "HEALTH_OK", BalancerActive: true, BalancerMode: "upmap", AutoscaleOn: 2, AutoscalePools: 3},
+			wantContain: []string{"Balancer:", "upmap", "Autoscale:", "2/3 pools"},
+		},
+		{
+			name:        "balancer off",
+			data:        ClusterHeaderData{Health: "HEALTH_OK", BalancerMode: "upmap"},
+			wantContain: []string{"Balancer:", "off"},
+			wantMissing: []string{"Autoscale:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewClusterHeader()
+			data := tt.data
+			h.SetData(&data)
+			h.SetWidth(120)
+
+			view := h.Render()
+			for _, want := range tt.wantContain {
+				if !strings.Contains(view, want) {
+					t.Errorf("expected %q in view, got: %s", want, view)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(view, missing) {
+					t.Errorf("did not expect %q in view, got: %s", missing, view)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterHeader_View_StretchMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        ClusterHeaderData
+		wantContain []string
+	}{
+		{name: "flat cluster", data: ClusterHeaderData{Health: "HEALTH_OK"}},
+		{
+			name:        "active",
+			data:        ClusterHeaderData{Health: "HEALTH_OK", StretchMode: "active", TiebreakerMon: "e"},
+			wantContain: []string{"Stretch:", "active", "tiebreaker e"},
+		},
+		{
+			name:        "degraded",
+			data:        ClusterHeaderData{Health: "HEALTH_WARN", StretchMode: "degraded"},
+			wantContain: []string{"Stretch:", "degraded"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewClusterHeader()
+			data := tt.data
+			h.SetData(&data)
+			h.SetWidth(120)
+
+			view := h.Render()
+			if tt.wantContain == nil && strings.Contains(view, "Stretch:") {
+				t.Errorf("did not expect stretch mode in view, got: %s", view)
+			}
+			for _, want := range tt.wantContain {
+				if !strings.Contains(view, want) {
+					t.Errorf("expected %q in view, got: %s", want, view)
+				}
+			}
+		})
+	}
+}
+
 func TestClusterHeader_View_Compact(t *testing.T) {
 	h := NewClusterHeader()
 	h.SetData(&ClusterHeaderData{
@@ -322,3 +432,25 @@ func TestHeaderUpdateMsg_WithError(t *testing.T) {
 		t.Error("expected HasError()=true after error update")
 	}
 }
+
+func TestClusterHeader_LastUpdatedFollowsClock(t *testing.T) {
+	clk := fakeclock.New(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	h := NewClusterHeader()
+	h.SetClock(clk)
+	h.SetData(&ClusterHeaderData{Health: "HEALTH_OK", LastUpdate: clk.Now()})
+
+	tests := []struct {
+		advance time.Duration
+		want    string
+	}{
+		{advance: 42 * time.Second, want: "42s ago"},
+		{advance: 5 * time.Minute, want: "5m ago"},
+		{advance: 2 * time.Hour, want: "2h ago"},
+	}
+	for _, tt := range tests {
+		clk.Advance(tt.advance)
+		if got := h.renderLastUpdated(); !strings.Contains(got, tt.want) {
+			t.Errorf("renderLastUpdated() after %v = %q, want %q", tt.advance, got, tt.want)
+		}
+	}
+}
@@ -65,6 +65,15 @@ func NewIndeterminateProgress(label string) *ProgressBar {
 	}
 }
 
+// NewDeterminateProgress creates a progress bar showing its percentage
+func NewDeterminateProgress(label string) *ProgressBar {
+	return &ProgressBar{
+		Label:          label,
+		State:          ProgressStateInProgress,
+		ShowPercentage: true,
+	}
+}
+
 // Init implements tea.Model
 func (p *ProgressBar) Init() tea.Cmd {
 	if p.Indeterminate {
@@ -17,6 +17,18 @@ func TestNewIndeterminateProgress(t *testing.T) {
 	}
 }
 
+func TestNewDeterminateProgress(t *testing.T) {
+	p := NewDeterminateProgress("")
+	p.SetProgress(0.45)
+
+	if p.Indeterminate {
+		t.Error("Indeterminate should be false")
+	}
+	if view := p.Render(); !strings.Contains(view, " 45%") {
+		t.Errorf("Render() = %q, want the percentage", view)
+	}
+}
+
 func TestProgressBar_SetProgress(t *testing.T) {
 	p := NewIndeterminateProgress("Test")
 	p.Indeterminate = false
@@ -0,0 +1,32 @@
+package components
+
+import "strings"
+
+// sparkBlocks are the levels used to draw sparklines, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the most recent values as a single-line bar graph scaled
+// to the largest value shown. At most width values are drawn.
+func Sparkline(values []float64, width int) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if peak > 0 && v > 0 {
+			level = int(v / peak * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
@@ -0,0 +1,26 @@
+package components
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{name: "empty", values: nil, width: 10, want: ""},
+		{name: "zero width", values: []float64{1}, width: 0, want: ""},
+		{name: "all zero", values: []float64{0, 0, 0}, width: 10, want: "▁▁▁"},
+		{name: "scaled to peak", values: []float64{0, 50, 100}, width: 10, want: "▁▄█"},
+		{name: "keeps most recent", values: []float64{100, 0, 100}, width: 2, want: "▁█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("Sparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
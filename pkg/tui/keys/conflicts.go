@@ -0,0 +1,172 @@
+package keys
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+)
+
+// Conflict is a key bound to two actions that are enabled at the same time,
+// so one of them silently shadows the other
+type Conflict struct {
+	// Key is the shared key, e.g. "d"
+	Key string
+
+	// Actions name the colliding actions, e.g. `ls "down node"`
+	Actions [2]string
+
+	// Context describes when both actions are enabled
+	Context string
+}
+
+// String describes the conflict for a startup error
+func (c Conflict) String() string {
+	return fmt.Sprintf("key %q is bound to both %s and %s (%s)", c.Key, c.Actions[0], c.Actions[1], c.Context)
+}
+
+// namedBinding is a binding with the view it belongs to
+type namedBinding struct {
+	view    string
+	binding key.Binding
+}
+
+// name names the binding's action by its help text, e.g. `flow "retry"`
+func (n namedBinding) name() string {
+	return fmt.Sprintf("%s %q", n.view, n.binding.Help().Desc)
+}
+
+// flowStates enable the flow bindings of each flow state, with every
+// optional binding on
+var flowStates = []struct {
+	name string
+	set  func(*FlowBindings)
+}{
+	{"confirm", func(f *FlowBindings) { f.SetStateConfirm() }},
+	{"running", func(f *FlowBindings) { f.SetStateRunning() }},
+	{"error", func(f *FlowBindings) { f.SetStateError(true, true); f.SetChanges(true) }},
+	{"complete", func(f *FlowBindings) { f.SetStateComplete(); f.SetChanges(true) }},
+}
+
+// FindConflicts returns the keys bound to two actions in the same context of
+// the ls view: each pane, with and without the idle lock, and during each
+// state of an embedded flow. During a flow only the keys the ls view keeps
+// (navigation, queue, cluster log, unlock and abort) compete with the flow's.
+func FindConflicts(ls LsKeyMap, flow FlowBindings) []Conflict {
+	var conflicts []Conflict
+	seen := map[string]bool{}
+	check := func(context string, bindings []namedBinding) {
+		for _, c := range collisions(context, bindings) {
+			if id := c.Key + c.Actions[0] + c.Actions[1]; !seen[id] {
+				seen[id] = true
+				conflicts = append(conflicts, c)
+			}
+		}
+	}
+
+	panes := []struct {
+		pane LsPane
+		name string
+	}{{LsPaneNodes, "Nodes pane"}, {LsPaneDeployments, "Deployments pane"}, {LsPaneOSDs, "OSDs pane"}}
+	for _, p := range panes {
+		for _, showingPods := range []bool{false, true} {
+			for _, locked := range []bool{false, true} {
+				context := p.name
+				if locked {
+					context += ", locked"
+				}
+
+				k := ls
+				k.SetContext(p.pane, showingPods)
+				k.SetLocked(locked)
+				check(context, k.namedBindings())
+
+				k.SetFlowActive(true)
+				k.SetLocked(locked)
+				for _, state := range flowStates {
+					f := flow
+					state.set(&f)
+					check(fmt.Sprintf("%s, %s step of a flow", context, state.name),
+						append(k.flowKeptBindings(), f.namedBindings()...))
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// CheckConflicts returns an error listing every key conflict of the bindings
+func CheckConflicts(ls LsKeyMap, flow FlowBindings) error {
+	conflicts := FindConflicts(ls, flow)
+	if len(conflicts) == 0 {
+		return nil
+	}
+	lines := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		lines[i] = "  " + c.String()
+	}
+	return fmt.Errorf("conflicting key bindings:\n%s", strings.Join(lines, "\n"))
+}
+
+// collisions returns the keys shared by two enabled bindings
+func collisions(context string, bindings []namedBinding) []Conflict {
+	var conflicts []Conflict
+	for i, a := range bindings {
+		if !a.binding.Enabled() {
+			continue
+		}
+		for _, b := range bindings[i+1:] {
+			if !b.binding.Enabled() {
+				continue
+			}
+			for _, k := range a.binding.Keys() {
+				if slices.Contains(b.binding.Keys(), k) {
+					conflicts = append(conflicts, Conflict{Key: k, Actions: [2]string{a.name(), b.name()}, Context: context})
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// namedBindings returns every ls binding. The cluster log is checked closed:
+// while it is open, Esc closes it before quitting by design. Its level key
+// is checked as if the log were open.
+func (k LsKeyMap) namedBindings() []namedBinding {
+	k.SetClusterLogOpen(false)
+	k.LogLevel.SetEnabled(true)
+	bindings := []key.Binding{
+		k.Quit, k.Abort, k.Refresh, k.NodeDown, k.NodeUp, k.DeployDown, k.DeployUp,
+		k.ClusterLog, k.LogLevel, k.Unlock,
+	}
+	bindings = append(bindings, k.navigationBindings()...)
+	bindings = append(bindings, k.queueBindings()...)
+	return named("ls", bindings)
+}
+
+// flowKeptBindings returns the ls bindings that stay with the ls view while
+// an embedded flow has the focus
+func (k LsKeyMap) flowKeptBindings() []namedBinding {
+	k.SetClusterLogOpen(false)
+	k.LogLevel.SetEnabled(true)
+	bindings := []key.Binding{k.Abort, k.ClusterLog, k.LogLevel, k.Unlock}
+	bindings = append(bindings, k.navigationBindings()...)
+	bindings = append(bindings, k.queueBindings()...)
+	return named("ls", bindings)
+}
+
+// namedBindings returns every flow binding
+func (f FlowBindings) namedBindings() []namedBinding {
+	return named("flow", []key.Binding{
+		f.Proceed, f.Cancel, f.Retry, f.RetryFailed, f.Acknowledge, f.Changes, f.Exit, f.Interrupt, f.Quit,
+	})
+}
+
+func named(view string, bindings []key.Binding) []namedBinding {
+	result := make([]namedBinding, len(bindings))
+	for i, b := range bindings {
+		result[i] = namedBinding{view: view, binding: b}
+	}
+	return result
+}
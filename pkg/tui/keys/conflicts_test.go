@@ -0,0 +1,67 @@
+package keys
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindConflicts(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(ls *LsKeyMap, flow *FlowBindings)
+		want  []string
+	}{
+		{
+			name:  "defaults",
+			setup: func(*LsKeyMap, *FlowBindings) {},
+		},
+		{
+			name:  "ls keys on the same pane",
+			setup: func(ls *LsKeyMap, _ *FlowBindings) { ls.Layout.SetKeys("d") },
+			want: []string{
+				`key "d" is bound to both ls "down node" and ls "wide layout" (Nodes pane)`,
+				`key "d" is bound to both ls "wide layout" and flow "changes" (Nodes pane, error step of a flow)`,
+				`key "d" is bound to both ls "scale down" and ls "wide layout" (Deployments pane)`,
+			},
+		},
+		{
+			name:  "flow key shadowed by navigation",
+			setup: func(_ *LsKeyMap, flow *FlowBindings) { flow.Retry.SetKeys("j") },
+			want: []string{
+				`key "j" is bound to both ls "down" and flow "retry" (Nodes pane, error step of a flow)`,
+			},
+		},
+		{
+			name:  "flow keys of one state",
+			setup: func(_ *LsKeyMap, flow *FlowBindings) { flow.Acknowledge.SetKeys("r") },
+			want: []string{
+				`key "r" is bound to both flow "retry" and flow "proceed anyway" (Nodes pane, error step of a flow)`,
+			},
+		},
+		{
+			name: "keys of different states share",
+			setup: func(_ *LsKeyMap, flow *FlowBindings) {
+				flow.Proceed.SetKeys("r")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ls, flow := DefaultLsKeyMap(), DefaultFlowBindings()
+			tt.setup(&ls, &flow)
+			conflicts := FindConflicts(ls, flow)
+
+			got := make([]string, len(conflicts))
+			for i, c := range conflicts {
+				got[i] = c.String()
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("FindConflicts() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			if err := CheckConflicts(ls, flow); (err != nil) != (len(tt.want) > 0) {
+				t.Errorf("CheckConflicts() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package keys
+
+import (
+	"charm.land/bubbles/v2/key"
+)
+
+// ContextPickerKeyMap contains the keybindings of the kubeconfig context picker.
+type ContextPickerKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Select key.Binding
+	Quit   key.Binding
+}
+
+// DefaultContextPickerKeyMap returns the default context picker keybindings.
+func DefaultContextPickerKeyMap() ContextPickerKeyMap {
+	return ContextPickerKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("k", "up"),
+			key.WithHelp("k/up", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("j", "down"),
+			key.WithHelp("j/down", "down"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("Enter", "connect"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "esc", "ctrl+c"),
+			key.WithHelp("q/Esc", "quit"),
+		),
+	}
+}
+
+// ShortHelp implements help.KeyMap.
+func (k ContextPickerKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Select, k.Quit}
+}
+
+// FullHelp implements help.KeyMap.
+func (k ContextPickerKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Up, k.Down, k.Select, k.Quit}}
+}
@@ -6,12 +6,15 @@ import (
 
 // FlowBindings contains state-aware bindings for down/up maintenance flows.
 type FlowBindings struct {
-	Proceed   key.Binding
-	Cancel    key.Binding
-	Retry     key.Binding
-	Exit      key.Binding
-	Interrupt key.Binding
-	Quit      key.Binding
+	Proceed     key.Binding
+	Cancel      key.Binding
+	Retry       key.Binding
+	RetryFailed key.Binding
+	Acknowledge key.Binding
+	Changes     key.Binding
+	Exit        key.Binding
+	Interrupt   key.Binding
+	Quit        key.Binding
 }
 
 // DefaultFlowBindings returns the default flow keybindings.
@@ -33,6 +36,21 @@ func DefaultFlowBindings() FlowBindings {
 			key.WithHelp("r", "retry"),
 			key.WithDisabled(),
 		),
+		RetryFailed: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "retry failed"),
+			key.WithDisabled(),
+		),
+		Acknowledge: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "proceed anyway"),
+			key.WithDisabled(),
+		),
+		Changes: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "changes"),
+			key.WithDisabled(),
+		),
 		Exit: key.NewBinding(
 			key.WithKeys("enter", "q", "esc"),
 			key.WithHelp("Enter/q", "exit"),
@@ -58,10 +76,14 @@ func (f *FlowBindings) SetStateConfirm() {
 	f.Cancel.SetEnabled(true)
 }
 
-// SetStateError enables bindings for error state.
-func (f *FlowBindings) SetStateError() {
+// SetStateError enables bindings for error state. resumable enables
+// RetryFailed for flows that can continue where they failed, and
+// acknowledgeable enables Acknowledge for flows stopped by warnings only.
+func (f *FlowBindings) SetStateError(resumable, acknowledgeable bool) {
 	f.disableAll()
 	f.Retry.SetEnabled(true)
+	f.RetryFailed.SetEnabled(resumable)
+	f.Acknowledge.SetEnabled(acknowledgeable)
 	f.Quit.SetEnabled(true)
 }
 
@@ -71,6 +93,12 @@ func (f *FlowBindings) SetStateComplete() {
 	f.Exit.SetEnabled(true)
 }
 
+// SetChanges enables Changes on a result screen when the flow changed
+// something. Call it after SetStateError or SetStateComplete.
+func (f *FlowBindings) SetChanges(available bool) {
+	f.Changes.SetEnabled(available)
+}
+
 // SetStateRunning enables bindings for running state.
 func (f *FlowBindings) SetStateRunning() {
 	f.disableAll()
@@ -82,6 +110,9 @@ func (f *FlowBindings) disableAll() {
 	f.Proceed.SetEnabled(false)
 	f.Cancel.SetEnabled(false)
 	f.Retry.SetEnabled(false)
+	f.RetryFailed.SetEnabled(false)
+	f.Acknowledge.SetEnabled(false)
+	f.Changes.SetEnabled(false)
 	f.Exit.SetEnabled(false)
 	f.Interrupt.SetEnabled(false)
 	f.Quit.SetEnabled(false)
@@ -90,7 +121,7 @@ func (f *FlowBindings) disableAll() {
 // ShortHelp implements help.KeyMap.
 func (f FlowBindings) ShortHelp() []key.Binding {
 	var bindings []key.Binding
-	for _, b := range []key.Binding{f.Proceed, f.Cancel, f.Retry, f.Exit, f.Quit, f.Interrupt} {
+	for _, b := range []key.Binding{f.Proceed, f.Cancel, f.Retry, f.RetryFailed, f.Acknowledge, f.Changes, f.Exit, f.Quit, f.Interrupt} {
 		if b.Enabled() {
 			bindings = append(bindings, b)
 		}
@@ -0,0 +1,40 @@
+package keys
+
+import (
+	"charm.land/bubbles/v2/key"
+)
+
+// HistoryKeyMap contains the keybindings of the maintenance history browser.
+type HistoryKeyMap struct {
+	Quit key.Binding
+	Up   key.Binding
+	Down key.Binding
+}
+
+// DefaultHistoryKeyMap returns the default history browser keybindings.
+func DefaultHistoryKeyMap() HistoryKeyMap {
+	return HistoryKeyMap{
+		Quit: key.NewBinding(
+			key.WithKeys("q", "esc", "ctrl+c"),
+			key.WithHelp("q/Esc", "quit"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("k", "up"),
+			key.WithHelp("k/up", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("j", "down"),
+			key.WithHelp("j/down", "down"),
+		),
+	}
+}
+
+// ShortHelp implements help.KeyMap.
+func (k HistoryKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Quit}
+}
+
+// FullHelp implements help.KeyMap.
+func (k HistoryKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Up, k.Down, k.Quit}}
+}
@@ -17,7 +17,8 @@ const (
 // LsKeyMap contains all keybindings for the ls view.
 type LsKeyMap struct {
 	// Global bindings
-	Quit key.Binding
+	Quit  key.Binding
+	Abort key.Binding
 
 	// Navigation
 	Up   key.Binding
@@ -34,8 +35,29 @@ type LsKeyMap struct {
 	Refresh    key.Binding
 	NodeDown   key.Binding
 	NodeUp     key.Binding
+	DeployDown key.Binding
+	DeployUp   key.Binding
 	ShowDeploy key.Binding
 	ShowPods   key.Binding
+
+	// Follow filters the Deployments/Pods and OSDs panes to the selected node
+	Follow key.Binding
+
+	// Layout switches between the stacked and the wide layout
+	Layout key.Binding
+
+	// Maintenance queue
+	QueueToggle key.Binding
+	QueueStart  key.Binding
+	QueueUp     key.Binding
+	QueueDown   key.Binding
+
+	// Cluster log
+	ClusterLog key.Binding
+	LogLevel   key.Binding
+
+	// Unlock re-enables the mutating keys after the idle lock
+	Unlock key.Binding
 }
 
 // DefaultLsKeyMap returns the default ls view keybindings.
@@ -45,6 +67,10 @@ func DefaultLsKeyMap() LsKeyMap {
 			key.WithKeys("q", "esc", "ctrl+c"),
 			key.WithHelp("q/Esc", "quit"),
 		),
+		Abort: key.NewBinding(
+			key.WithKeys("ctrl+shift+c"),
+			key.WithHelp("C-S-c/C-c C-c", "abort all"),
+		),
 		Up: key.NewBinding(
 			key.WithKeys("k", "up"),
 			key.WithHelp("k/up", "up"),
@@ -85,6 +111,14 @@ func DefaultLsKeyMap() LsKeyMap {
 			key.WithKeys("u"),
 			key.WithHelp("u", "up node"),
 		),
+		DeployDown: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "scale down"),
+		),
+		DeployUp: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "restore"),
+		),
 		ShowDeploy: key.NewBinding(
 			key.WithKeys("["),
 			key.WithHelp("[", "deployments"),
@@ -93,6 +127,44 @@ func DefaultLsKeyMap() LsKeyMap {
 			key.WithKeys("]"),
 			key.WithHelp("]", "pods"),
 		),
+		Follow: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "follow node"),
+		),
+		Layout: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "wide layout"),
+		),
+		QueueToggle: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "queue node"),
+		),
+		QueueStart: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "start queue"),
+		),
+		QueueUp: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "queue earlier"),
+		),
+		QueueDown: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J", "queue later"),
+		),
+		ClusterLog: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "cluster log"),
+		),
+		LogLevel: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "log level"),
+			key.WithDisabled(),
+		),
+		Unlock: key.NewBinding(
+			key.WithKeys("ctrl+u"),
+			key.WithHelp("C-u", "unlock"),
+			key.WithDisabled(),
+		),
 	}
 }
 
@@ -102,9 +174,17 @@ func (k *LsKeyMap) SetContext(pane LsPane, showingPods bool) {
 	isNodesPane := pane == LsPaneNodes
 	k.NodeDown.SetEnabled(isNodesPane)
 	k.NodeUp.SetEnabled(isNodesPane)
+	k.QueueToggle.SetEnabled(isNodesPane)
+	k.QueueStart.SetEnabled(isNodesPane)
+	k.QueueUp.SetEnabled(isNodesPane)
+	k.QueueDown.SetEnabled(isNodesPane)
 
-	// Toggle only available on Deployments pane
+	// Single deployment maintenance on the Deployments pane's deployment list
 	isDeploymentsPane := pane == LsPaneDeployments
+	k.DeployDown.SetEnabled(isDeploymentsPane && !showingPods)
+	k.DeployUp.SetEnabled(isDeploymentsPane && !showingPods)
+
+	// Toggle only available on Deployments pane
 	k.ShowDeploy.SetEnabled(isDeploymentsPane && showingPods)
 	k.ShowPods.SetEnabled(isDeploymentsPane && !showingPods)
 }
@@ -112,6 +192,9 @@ func (k *LsKeyMap) SetContext(pane LsPane, showingPods bool) {
 // ShortHelp implements help.KeyMap for status bar display.
 func (k LsKeyMap) ShortHelp() []key.Binding {
 	bindings := []key.Binding{k.NextPane, k.Down, k.Up}
+	if k.Unlock.Enabled() {
+		bindings = append(bindings, k.Unlock)
+	}
 
 	// Add contextual bindings
 	if k.NodeDown.Enabled() {
@@ -120,6 +203,15 @@ func (k LsKeyMap) ShortHelp() []key.Binding {
 	if k.NodeUp.Enabled() {
 		bindings = append(bindings, k.NodeUp)
 	}
+	if k.QueueToggle.Enabled() {
+		bindings = append(bindings, k.QueueToggle)
+	}
+	if k.DeployDown.Enabled() {
+		bindings = append(bindings, k.DeployDown)
+	}
+	if k.DeployUp.Enabled() {
+		bindings = append(bindings, k.DeployUp)
+	}
 	if k.ShowDeploy.Enabled() {
 		bindings = append(bindings, k.ShowDeploy)
 	}
@@ -127,7 +219,14 @@ func (k LsKeyMap) ShortHelp() []key.Binding {
 		bindings = append(bindings, k.ShowPods)
 	}
 
-	bindings = append(bindings, k.Refresh, k.Quit)
+	if k.Follow.Enabled() {
+		bindings = append(bindings, k.Follow)
+	}
+	if k.LogLevel.Enabled() {
+		bindings = append(bindings, k.LogLevel)
+	}
+
+	bindings = append(bindings, k.ClusterLog, k.Refresh, k.Quit)
 	return bindings
 }
 
@@ -135,25 +234,109 @@ func (k LsKeyMap) ShortHelp() []key.Binding {
 func (k LsKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.NextPane, k.PrevPane, k.Pane1, k.Pane2, k.Pane3},
-		{k.Up, k.Down},
-		{k.NodeDown, k.NodeUp, k.Refresh, k.ShowDeploy, k.ShowPods},
-		{k.Quit},
+		{k.Up, k.Down, k.Follow, k.Layout},
+		{k.NodeDown, k.NodeUp, k.DeployDown, k.DeployUp, k.Refresh, k.ShowDeploy, k.ShowPods},
+		{k.QueueToggle, k.QueueStart, k.QueueUp, k.QueueDown},
+		{k.ClusterLog, k.LogLevel},
+		{k.Unlock, k.Quit, k.Abort},
 	}
 }
 
 // IsNavigationKey returns true if the key message matches a navigation-only key.
-// Navigation keys are: Tab, Shift-Tab, 1, 2, 3, [, ], j, k, up, down
+// Navigation keys are: Tab, Shift-Tab, 1, 2, 3, [, ], j, k, up, down, F, w
 // These keys should remain active during maintenance flows.
 func (k *LsKeyMap) IsNavigationKey(msg tea.KeyMsg) bool {
-	return key.Matches(msg, k.NextPane, k.PrevPane, k.Pane1, k.Pane2, k.Pane3, k.ShowDeploy, k.ShowPods, k.Up, k.Down)
+	return key.Matches(msg, k.navigationBindings()...)
+}
+
+// IsQueueKey returns true if the key message edits or starts the maintenance
+// queue. Queue keys also remain active during maintenance flows.
+func (k *LsKeyMap) IsQueueKey(msg tea.KeyMsg) bool {
+	return key.Matches(msg, k.queueBindings()...)
+}
+
+// navigationBindings returns the navigation-only bindings
+func (k *LsKeyMap) navigationBindings() []key.Binding {
+	return []key.Binding{k.NextPane, k.PrevPane, k.Pane1, k.Pane2, k.Pane3, k.ShowDeploy, k.ShowPods, k.Up, k.Down, k.Follow, k.Layout}
+}
+
+// queueBindings returns the maintenance queue bindings
+func (k *LsKeyMap) queueBindings() []key.Binding {
+	return []key.Binding{k.QueueToggle, k.QueueStart, k.QueueUp, k.QueueDown}
+}
+
+// SetFollowing switches the follow binding's help between turning follow
+// mode on and off
+func (k *LsKeyMap) SetFollowing(following bool) {
+	if following {
+		k.Follow.SetHelp("F", "unfollow")
+	} else {
+		k.Follow.SetHelp("F", "follow node")
+	}
+}
+
+// SetWide switches the layout binding's help to the layout it switches to
+func (k *LsKeyMap) SetWide(wide bool) {
+	if wide {
+		k.Layout.SetHelp("w", "stacked layout")
+	} else {
+		k.Layout.SetHelp("w", "wide layout")
+	}
 }
 
 // SetFlowActive enables or disables action keys based on maintenance flow state.
 // When a flow is active, action keys (d, u, r, q) should be disabled
-// as they are handled by the flow model.
+// as they are handled by the flow model. Queue keys stay enabled so the
+// queue can be edited while a node is in maintenance. Call it after SetContext, which
+// decides which maintenance actions the active pane offers.
 func (k *LsKeyMap) SetFlowActive(active bool) {
-	k.NodeDown.SetEnabled(!active)
-	k.NodeUp.SetEnabled(!active)
+	if active {
+		k.NodeDown.SetEnabled(false)
+		k.NodeUp.SetEnabled(false)
+		k.DeployDown.SetEnabled(false)
+		k.DeployUp.SetEnabled(false)
+	}
 	k.Refresh.SetEnabled(!active)
 	k.Quit.SetEnabled(!active)
 }
+
+// SetLocked disables the keys that change the cluster or the maintenance
+// queue while the idle lock holds, leaving navigation available, and enables
+// the unlock key instead. Call it after SetContext and SetFlowActive.
+func (k *LsKeyMap) SetLocked(locked bool) {
+	if locked {
+		k.disableMutations()
+	}
+	k.Unlock.SetEnabled(locked)
+}
+
+// SetReadOnly disables the keys that change the cluster or the maintenance
+// queue for good. Call it after SetContext and SetFlowActive.
+func (k *LsKeyMap) SetReadOnly(readOnly bool) {
+	if readOnly {
+		k.disableMutations()
+	}
+}
+
+// disableMutations disables the maintenance and queue bindings
+func (k *LsKeyMap) disableMutations() {
+	for _, b := range []*key.Binding{
+		&k.NodeDown, &k.NodeUp, &k.DeployDown, &k.DeployUp,
+		&k.QueueToggle, &k.QueueStart, &k.QueueUp, &k.QueueDown,
+	} {
+		b.SetEnabled(false)
+	}
+}
+
+// SetClusterLogOpen switches the cluster log bindings between opening the
+// log and filtering or closing it. Esc closes the log instead of quitting.
+func (k *LsKeyMap) SetClusterLogOpen(open bool) {
+	if open {
+		k.ClusterLog.SetKeys("L", "esc")
+		k.ClusterLog.SetHelp("L/Esc", "close log")
+	} else {
+		k.ClusterLog.SetKeys("L")
+		k.ClusterLog.SetHelp("L", "cluster log")
+	}
+	k.LogLevel.SetEnabled(open)
+}
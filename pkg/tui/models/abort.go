@@ -0,0 +1,68 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+)
+
+// abortDoublePressWindow is how soon a second Ctrl+C must follow the first
+// to abort everything
+const abortDoublePressWindow = time.Second
+
+// abortableFlow is implemented by flows that can cancel all of their work
+type abortableFlow interface {
+	Abort()
+}
+
+// abortDetector recognizes the global "abort everything" gesture: the abort
+// binding, or Ctrl+C pressed twice within abortDoublePressWindow. A single
+// Ctrl+C keeps its normal meaning (cancel the running step, or quit).
+type abortDetector struct {
+	abort     key.Binding
+	interrupt key.Binding
+
+	// lastInterrupt is when Ctrl+C was last pressed
+	lastInterrupt time.Time
+
+	// now returns the current time; overridden in tests
+	now func() time.Time
+}
+
+// newAbortDetector creates a detector for the given abort binding
+func newAbortDetector(abort key.Binding) abortDetector {
+	return abortDetector{
+		abort:     abort,
+		interrupt: key.NewBinding(key.WithKeys("ctrl+c")),
+		now:       time.Now,
+	}
+}
+
+// observe records a key press and reports whether it completes the abort gesture
+func (a *abortDetector) observe(msg tea.KeyMsg) bool {
+	if key.Matches(msg, a.abort) {
+		return true
+	}
+	if !key.Matches(msg, a.interrupt) {
+		return false
+	}
+
+	now := a.now()
+	if !a.lastInterrupt.IsZero() && now.Sub(a.lastInterrupt) <= abortDoublePressWindow {
+		a.lastInterrupt = time.Time{}
+		return true
+	}
+	a.lastInterrupt = now
+	return false
+}
+
+// withAbort derives a context that Abort cancels from a model's configured
+// parent context
+func withAbort(parent context.Context) (context.Context, context.CancelFunc) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	return context.WithCancel(parent)
+}
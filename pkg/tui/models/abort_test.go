@@ -0,0 +1,147 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/tui/keys"
+	"go.uber.org/goleak"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	ctrlC      = tea.KeyPressMsg{Code: 'c', Mod: tea.ModCtrl}
+	ctrlShiftC = tea.KeyPressMsg{Code: 'c', Mod: tea.ModCtrl | tea.ModShift}
+)
+
+func TestAbortDetector(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []tea.KeyPressMsg
+		gap  time.Duration
+		want []bool
+	}{
+		{name: "abort binding", keys: []tea.KeyPressMsg{ctrlShiftC}, want: []bool{true}},
+		{name: "single ctrl+c", keys: []tea.KeyPressMsg{ctrlC}, want: []bool{false}},
+		{name: "double ctrl+c", keys: []tea.KeyPressMsg{ctrlC, ctrlC}, gap: 200 * time.Millisecond, want: []bool{false, true}},
+		{name: "slow double ctrl+c", keys: []tea.KeyPressMsg{ctrlC, ctrlC}, gap: 2 * time.Second, want: []bool{false, false}},
+		{name: "other key", keys: []tea.KeyPressMsg{{Code: 'q', Text: "q"}}, want: []bool{false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+			d := newAbortDetector(keys.DefaultLsKeyMap().Abort)
+			d.now = func() time.Time { return now }
+
+			for i, msg := range tt.keys {
+				if got := d.observe(msg); got != tt.want[i] {
+					t.Errorf("press %d (%s): observe() = %v, want %v", i, msg.String(), got, tt.want[i])
+				}
+				now = now.Add(tt.gap)
+			}
+		})
+	}
+}
+
+func TestLsModel_AbortCancelsFlowAndMonitor(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	client := &k8s.Client{Clientset: fake.NewClientset()}
+	model := NewLsModel(LsModelConfig{
+		Context: context.Background(),
+		Client:  client,
+		Config:  config.Config{Namespace: "rook-ceph"},
+	})
+
+	// Start the real monitor; its pollers run until the model shuts down
+	started, ok := model.startMonitorCmd()().(LsMonitorStartedMsg)
+	if !ok {
+		t.Fatal("monitor did not start")
+	}
+	model.Update(started)
+
+	flow := NewDownModel(DownModelConfig{
+		NodeName:     "worker-1",
+		Client:       client,
+		Context:      model.config.Context,
+		ExitBehavior: FlowExitMessage,
+		Embedded:     true,
+	})
+	model.maintenanceFlow = flow
+
+	model.Update(ctrlC)
+	if flow.config.Context.Err() != nil {
+		t.Fatal("a single Ctrl+C must not abort everything")
+	}
+
+	_, cmd := model.Update(ctrlC)
+	if cmd == nil {
+		t.Fatal("double Ctrl+C should quit")
+	}
+	if _, isQuit := cmd().(tea.QuitMsg); !isQuit {
+		t.Error("double Ctrl+C should return tea.Quit")
+	}
+	if flow.config.Context.Err() == nil {
+		t.Error("flow context should be cancelled")
+	}
+	if model.config.Context.Err() == nil {
+		t.Error("model context should be cancelled")
+	}
+	// Stop closes the subscription once all pollers have returned
+	for range started.UpdatesCh {
+	}
+}
+
+func TestProgressListenersReturnOnAbort(t *testing.T) {
+	down := NewDownModel(DownModelConfig{Context: context.Background()})
+	up := NewUpModel(UpModelConfig{Context: context.Background()})
+
+	// Start runs without executing the operation, so nothing closes the
+	// progress channel
+	down.runner.Start(down.config.Context, func(context.Context, func(maintenance.DownPhaseProgress)) tea.Msg { return nil })
+	up.runner.Start(up.config.Context, func(context.Context, func(maintenance.UpPhaseProgress)) tea.Msg { return nil })
+
+	tests := []struct {
+		name   string
+		listen tea.Cmd
+		abort  func()
+		closed func(tea.Msg) bool
+	}{
+		{
+			name:   "down",
+			listen: down.runner.Listen(),
+			abort:  down.Abort,
+			closed: func(msg tea.Msg) bool { _, ok := msg.(DownProgressChannelClosedMsg); return ok },
+		},
+		{
+			name:   "up",
+			listen: up.runner.Listen(),
+			abort:  up.Abort,
+			closed: func(msg tea.Msg) bool { _, ok := msg.(UpProgressChannelClosedMsg); return ok },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := make(chan tea.Msg, 1)
+			go func() { result <- tt.listen() }()
+
+			tt.abort()
+
+			select {
+			case msg := <-result:
+				if !tt.closed(msg) {
+					t.Errorf("listener returned %T, want channel closed message", msg)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("progress listener did not return after Abort")
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/pkg/tui/keys"
+	"github.com/andri/crook/pkg/tui/styles"
+)
+
+// ContextPickerConfig configures the kubeconfig context picker
+type ContextPickerConfig struct {
+	// Contexts are the kubeconfig's context names
+	Contexts []string
+
+	// Current is the kubeconfig's current-context
+	Current string
+
+	// Last is the context picked last time in the working directory; it is
+	// preselected over Current
+	Last string
+}
+
+// ContextPickerModel asks which kubeconfig context to connect to before the
+// TUI starts, so a session never opens against a cluster by accident
+type ContextPickerModel struct {
+	contexts []string
+	current  string
+	last     string
+	keyMap   keys.ContextPickerKeyMap
+
+	cursor int
+	chosen string
+}
+
+// NewContextPickerModel creates a context picker
+func NewContextPickerModel(cfg ContextPickerConfig) *ContextPickerModel {
+	m := &ContextPickerModel{
+		contexts: cfg.Contexts,
+		current:  cfg.Current,
+		last:     cfg.Last,
+		keyMap:   keys.DefaultContextPickerKeyMap(),
+	}
+	for _, preselect := range []string{cfg.Last, cfg.Current} {
+		if i := slices.Index(m.contexts, preselect); preselect != "" && i >= 0 {
+			m.cursor = i
+			break
+		}
+	}
+	return m
+}
+
+// Chosen returns the picked context, or "" when the picker was quit
+func (m *ContextPickerModel) Chosen() string {
+	return m.chosen
+}
+
+// Init implements tea.Model
+func (m *ContextPickerModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *ContextPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keyMap.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keyMap.Select):
+			if len(m.contexts) > 0 {
+				m.chosen = m.contexts[m.cursor]
+			}
+			return m, tea.Quit
+		case key.Matches(msg, m.keyMap.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(msg, m.keyMap.Down):
+			if m.cursor < len(m.contexts)-1 {
+				m.cursor++
+			}
+		}
+	}
+	return m, nil
+}
+
+// View implements tea.Model
+func (m *ContextPickerModel) View() tea.View {
+	return tea.NewView(m.Render())
+}
+
+// Render returns the picker as a string
+func (m *ContextPickerModel) Render() string {
+	var b strings.Builder
+	b.WriteString(styles.StyleHeading.Render("Connect to which Kubernetes context?"))
+	b.WriteString("\n\n")
+
+	for i, name := range m.contexts {
+		var notes []string
+		if name == m.current {
+			notes = append(notes, "current")
+		}
+		if name == m.last {
+			notes = append(notes, "last used here")
+		}
+		line := "  " + name
+		if i == m.cursor {
+			line = styles.StyleHighlight.Render("› " + name)
+		}
+		if len(notes) > 0 {
+			line += " " + styles.StyleSubtle.Render("("+strings.Join(notes, ", ")+")")
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.StyleSubtle.Render("j/k move • Enter connect • q quit"))
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestContextPickerModel(t *testing.T) {
+	contexts := []string{"dev", "production", "staging"}
+
+	tests := []struct {
+		name       string
+		cfg        ContextPickerConfig
+		keys       []tea.KeyPressMsg
+		wantChosen string
+	}{
+		{
+			name:       "current context preselected",
+			cfg:        ContextPickerConfig{Contexts: contexts, Current: "staging"},
+			keys:       []tea.KeyPressMsg{{Code: tea.KeyEnter}},
+			wantChosen: "staging",
+		},
+		{
+			name:       "last pick wins over current",
+			cfg:        ContextPickerConfig{Contexts: contexts, Current: "staging", Last: "dev"},
+			keys:       []tea.KeyPressMsg{{Code: 'j', Text: "j"}, {Code: tea.KeyEnter}},
+			wantChosen: "production",
+		},
+		{
+			name:       "cursor stops at the top",
+			cfg:        ContextPickerConfig{Contexts: contexts},
+			keys:       []tea.KeyPressMsg{{Code: 'k', Text: "k"}, {Code: tea.KeyEnter}},
+			wantChosen: "dev",
+		},
+		{
+			name: "quit picks nothing",
+			cfg:  ContextPickerConfig{Contexts: contexts, Current: "staging"},
+			keys: []tea.KeyPressMsg{{Code: 'q', Text: "q"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewContextPickerModel(tt.cfg)
+			for _, msg := range tt.keys {
+				model.Update(msg)
+			}
+			if got := model.Chosen(); got != tt.wantChosen {
+				t.Errorf("Chosen() = %q, want %q", got, tt.wantChosen)
+			}
+		})
+	}
+}
+
+func TestContextPickerModel_Render(t *testing.T) {
+	model := NewContextPickerModel(ContextPickerConfig{Contexts: []string{"dev", "production"}, Current: "dev", Last: "production"})
+	view := model.Render()
+	for _, want := range []string{"Kubernetes context", "dev", "current", "last used here"} {
+		if !contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
@@ -0,0 +1,414 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/tui/components"
+	"github.com/andri/crook/pkg/tui/styles"
+)
+
+// DeploymentPhaseState represents the current state of a single deployment flow
+type DeploymentPhaseState int
+
+const (
+	// DeploymentStateInit is the initial state while the deployment is fetched
+	DeploymentStateInit DeploymentPhaseState = iota
+	// DeploymentStateConfirm waits for user confirmation
+	DeploymentStateConfirm
+	// DeploymentStateNothingToDo indicates the deployment is already at its target
+	DeploymentStateNothingToDo
+	// DeploymentStatePreFlight runs the pre-flight checks of the deployment's node
+	DeploymentStatePreFlight
+	// DeploymentStateSettingNoOut sets the Ceph noout flag
+	DeploymentStateSettingNoOut
+	// DeploymentStateScalingOperator scales the rook-ceph-operator
+	DeploymentStateScalingOperator
+	// DeploymentStateScaling scales the deployment
+	DeploymentStateScaling
+	// DeploymentStateUnsettingNoOut unsets the Ceph noout flag
+	DeploymentStateUnsettingNoOut
+	// DeploymentStateComplete indicates successful completion
+	DeploymentStateComplete
+	// DeploymentStateError indicates an error occurred
+	DeploymentStateError
+)
+
+// String returns the human-readable name for the state
+func (s DeploymentPhaseState) String() string {
+	switch s {
+	case DeploymentStateInit:
+		return "Initializing"
+	case DeploymentStateConfirm:
+		return "Awaiting Confirmation"
+	case DeploymentStateNothingToDo:
+		return "Nothing To Do"
+	case DeploymentStatePreFlight:
+		return "Pre-flight Checks"
+	case DeploymentStateSettingNoOut:
+		return "Setting NoOut Flag"
+	case DeploymentStateScalingOperator:
+		return "Scaling Operator"
+	case DeploymentStateScaling:
+		return "Scaling Deployment"
+	case DeploymentStateUnsettingNoOut:
+		return "Unsetting NoOut Flag"
+	case DeploymentStateComplete:
+		return "Complete"
+	case DeploymentStateError:
+		return "Error"
+	default:
+		return "Unknown"
+	}
+}
+
+// DeploymentModelConfig holds configuration for the single deployment flow
+type DeploymentModelConfig struct {
+	// Namespace and Name identify the target deployment
+	Namespace string
+	Name      string
+
+	// Restore scales the deployment back up instead of down
+	Restore bool
+
+	// ExitBehavior controls how the flow exits (quit vs message).
+	ExitBehavior FlowExitBehavior
+
+	// Embedded renders the model without an outer frame so it can be hosted inside
+	// another container (for example, the `crook ls` Maintenance pane).
+	Embedded bool
+
+	// Config is the application configuration
+	Config config.Config
+
+	// Client is the Kubernetes client
+	Client *k8s.Client
+
+	// Context for cancellation
+	Context context.Context
+}
+
+// DeploymentModel is the Bubble Tea model for scaling a single deployment
+// down or restoring it, with the noout and operator handling of the node
+// phases. It bounces one OSD without taking its node down.
+type DeploymentModel struct {
+	// Shared engine: states, prompt, stage list, keys and rendering
+	PhaseModel[DeploymentPhaseState, maintenance.DeploymentPhaseProgress]
+
+	// Configuration
+	config DeploymentModelConfig
+
+	// Target deployment as discovered for the confirmation screen
+	nodeName        string
+	currentReplicas int32
+	restoreReplicas int32
+}
+
+// NewDeploymentModel creates a new single deployment flow model
+func NewDeploymentModel(cfg DeploymentModelConfig) *DeploymentModel {
+	m := &DeploymentModel{}
+	m.PhaseModel, cfg.Context = NewPhaseModel(cfg.Context, m.definition(cfg.Client, cfg.Restore), cfg.ExitBehavior, cfg.Embedded)
+	m.config = cfg
+	return m
+}
+
+// definition describes the scale-down or restore flow for the phase engine
+func (m *DeploymentModel) definition(client *k8s.Client, restore bool) PhaseDefinition[DeploymentPhaseState, maintenance.DeploymentPhaseProgress] {
+	def := PhaseDefinition[DeploymentPhaseState, maintenance.DeploymentPhaseProgress]{
+		Name:            "Down",
+		Flow:            "deployment-down",
+		ConfirmQuestion: "Proceed with scale-down?",
+		States: PhaseStates[DeploymentPhaseState]{
+			Init:        DeploymentStateInit,
+			Confirm:     DeploymentStateConfirm,
+			NothingToDo: DeploymentStateNothingToDo,
+			Complete:    DeploymentStateComplete,
+			Error:       DeploymentStateError,
+		},
+		Stages: []PhaseStage[DeploymentPhaseState]{
+			{State: DeploymentStatePreFlight, Label: "Pre-flight checks", Progress: maintenance.PreflightProgressStages},
+			{State: DeploymentStateSettingNoOut, Label: "Set noout flag", Progress: []string{"noout"}},
+			{State: DeploymentStateScalingOperator, Label: "Scale operator", Progress: []string{"operator"}},
+			{State: DeploymentStateScaling, Label: "Scale down deployment", Progress: []string{"scale-down"}},
+		},
+		Runner:  newFlowRunnerDeployment(),
+		Execute: m.runDeploymentPhase,
+		TickMsg: DeploymentPhaseTickMsg{},
+		Client:  client,
+		ExitMsg: func(reason FlowExitReason, err error) tea.Msg {
+			return DeploymentFlowExitMsg{Reason: reason, Err: err}
+		},
+		Screens: PhaseScreens{
+			Loading:      m.renderLoading,
+			Confirmation: m.renderConfirmation,
+			NothingToDo:  m.renderNothingToDo,
+			Complete:     m.renderComplete,
+		},
+	}
+
+	if restore {
+		def.Name = "Up"
+		def.Flow = "deployment-up"
+		def.ConfirmQuestion = "Proceed with restoration?"
+		def.Stages = []PhaseStage[DeploymentPhaseState]{
+			{State: DeploymentStateScaling, Label: "Restore deployment", Progress: []string{"scale-up"}},
+			{State: DeploymentStateScalingOperator, Label: "Scale operator", Progress: []string{"operator"}},
+			{State: DeploymentStateUnsettingNoOut, Label: "Unset noout flag", Progress: []string{"unset-noout"}},
+		}
+	}
+
+	return def
+}
+
+// Messages for single deployment flow state transitions
+
+// DeploymentPhaseProgressMsg reports progress of a single deployment flow
+type DeploymentPhaseProgressMsg struct {
+	Stage       string
+	Description string
+}
+
+// DeploymentPhaseCompleteMsg signals successful completion
+type DeploymentPhaseCompleteMsg struct{}
+
+// DeploymentPhaseErrorMsg signals an error occurred
+type DeploymentPhaseErrorMsg struct {
+	Err error
+}
+
+// DeploymentPhaseTickMsg is sent periodically to update elapsed time
+type DeploymentPhaseTickMsg struct{}
+
+// DeploymentDiscoveredMsg reports the target deployment's current state
+type DeploymentDiscoveredMsg struct {
+	NodeName        string
+	CurrentReplicas int32
+
+	// RestoreReplicas is the count a restore scales the deployment to
+	RestoreReplicas int32
+}
+
+// DeploymentProgressChannelClosedMsg signals that the progress channel was closed
+type DeploymentProgressChannelClosedMsg struct{}
+
+// Init implements tea.Model
+func (m *DeploymentModel) Init() tea.Cmd {
+	return tea.Batch(
+		m.discoverDeploymentCmd(),
+		m.tickCmd(),
+	)
+}
+
+// discoverDeploymentCmd fetches the target deployment for the confirmation screen
+func (m *DeploymentModel) discoverDeploymentCmd() tea.Cmd {
+	client := m.config.Client
+	ctx := m.config.Context
+	namespace, name := m.config.Namespace, m.config.Name
+
+	return func() tea.Msg {
+		deployment, err := client.GetDeployment(ctx, namespace, name)
+		if err != nil {
+			return DeploymentPhaseErrorMsg{Err: err}
+		}
+
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		return DeploymentDiscoveredMsg{
+			NodeName:        k8s.GetDeploymentTargetNode(deployment),
+			CurrentReplicas: replicas,
+			RestoreReplicas: maintenance.DeploymentRestoreReplicas(deployment),
+		}
+	}
+}
+
+// newFlowRunnerDeployment creates the runner delivering single deployment progress
+func newFlowRunnerDeployment() *FlowRunner[maintenance.DeploymentPhaseProgress] {
+	return NewFlowRunner(
+		"deployment-progress",
+		func(progress maintenance.DeploymentPhaseProgress) tea.Msg {
+			return DeploymentPhaseProgressMsg{
+				Stage:       progress.Stage,
+				Description: progress.Description,
+			}
+		},
+		DeploymentProgressChannelClosedMsg{},
+	)
+}
+
+// runDeploymentPhase returns the operation run by the flow runner
+func (m *DeploymentModel) runDeploymentPhase() func(context.Context, func(maintenance.DeploymentPhaseProgress)) tea.Msg {
+	client := m.config.Client
+	cfg := m.config.Config
+	namespace, name := m.config.Namespace, m.config.Name
+	acknowledged := m.acknowledged
+	execute := maintenance.ExecuteDeploymentDown
+	if m.config.Restore {
+		execute = maintenance.ExecuteDeploymentUp
+	}
+
+	return func(ctx context.Context, report func(maintenance.DeploymentPhaseProgress)) tea.Msg {
+		opts := maintenance.DeploymentPhaseOptions{
+			ProgressCallback:    report,
+			AcknowledgeWarnings: acknowledged,
+		}
+
+		if err := execute(ctx, client, cfg, namespace, name, opts); err != nil {
+			return DeploymentPhaseErrorMsg{Err: err}
+		}
+		return DeploymentPhaseCompleteMsg{}
+	}
+}
+
+// Update implements tea.Model
+func (m *DeploymentModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case DeploymentPhaseTickMsg:
+		cmds = append(cmds, m.tick(msg))
+
+	case DeploymentDiscoveredMsg:
+		m.nodeName = msg.NodeName
+		m.currentReplicas = msg.CurrentReplicas
+		m.restoreReplicas = msg.RestoreReplicas
+
+		atTarget := msg.CurrentReplicas == 0
+		if m.config.Restore {
+			atTarget = msg.CurrentReplicas > 0
+		}
+		if atTarget {
+			m.state = DeploymentStateNothingToDo
+		} else {
+			m.state = DeploymentStateConfirm
+			m.confirmPrompt.Details = fmt.Sprintf("%s will be scaled to %d", m.deploymentName(), m.targetReplicas())
+		}
+
+	case DeploymentPhaseProgressMsg:
+		m.advance(msg.Stage)
+		// Re-schedule the progress listener while the channel is open
+		cmds = append(cmds, m.runner.Listen())
+
+	case DeploymentProgressChannelClosedMsg:
+		m.runner.MarkClosed()
+
+	case DeploymentPhaseCompleteMsg:
+		m.complete()
+
+	case DeploymentPhaseErrorMsg:
+		m.fail(msg.Err)
+	}
+
+	// Input, resizing and the confirmation prompt behave alike in all phases
+	cmds = append(cmds, m.update(msg))
+
+	return m, tea.Batch(cmds...)
+}
+
+// deploymentName returns the target as "namespace/name"
+func (m *DeploymentModel) deploymentName() string {
+	return fmt.Sprintf("%s/%s", m.config.Namespace, m.config.Name)
+}
+
+// targetReplicas returns the replica count the flow scales to
+func (m *DeploymentModel) targetReplicas() int32 {
+	if m.config.Restore {
+		return max(m.restoreReplicas, 1)
+	}
+	return 0
+}
+
+// NodeName returns the node the deployment is pinned to, once discovered.
+func (m *DeploymentModel) NodeName() string {
+	return m.nodeName
+}
+
+// FlowTitle returns the maintenance pane title for the flow.
+func (m *DeploymentModel) FlowTitle() string {
+	return fmt.Sprintf("Deployment Maintenance [%s]: %s", strings.ToUpper(m.PhaseName()), m.config.Name)
+}
+
+// renderLoading renders the loading state
+func (m *DeploymentModel) renderLoading() string {
+	return fmt.Sprintf("%s Loading deployment %s...", styles.IconSpinner, m.deploymentName())
+}
+
+// renderConfirmation renders the confirmation screen
+func (m *DeploymentModel) renderConfirmation() string {
+	var b strings.Builder
+
+	b.WriteString(styles.StyleStatus.Render("This will:"))
+	b.WriteString("\n")
+	if m.config.Restore {
+		fmt.Fprintf(&b, "  1. Scale up %s to %d replica(s)\n", m.deploymentName(), m.targetReplicas())
+		b.WriteString("  2. Scale up rook-ceph-operator to 1\n")
+		b.WriteString("  3. Unset Ceph noout flag to allow rebalancing\n")
+		b.WriteString(styles.StyleSubtle.Render("Steps 2 and 3 are skipped while other deployments are scaled down."))
+		b.WriteString("\n")
+	} else {
+		b.WriteString("  1. Set Ceph noout flag\n")
+		b.WriteString("  2. Scale down rook-ceph-operator\n")
+		fmt.Fprintf(&b, "  3. Scale down %s to 0 replicas\n", m.deploymentName())
+		b.WriteString(styles.StyleSubtle.Render("The node stays schedulable."))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	table := components.NewSimpleTable("Deployment", "Node", "Current", "Target")
+	table.AddStyledRow(styles.StyleSubtle,
+		m.deploymentName(),
+		m.nodeName,
+		fmt.Sprintf("%d", m.currentReplicas),
+		fmt.Sprintf("%d", m.targetReplicas()),
+	)
+	b.WriteString(table.Render())
+
+	return b.String()
+}
+
+// renderNothingToDo renders the view when the deployment is already at its target
+func (m *DeploymentModel) renderNothingToDo() string {
+	var b strings.Builder
+
+	state := "scaled down"
+	if m.config.Restore {
+		state = "running"
+	}
+	b.WriteString(styles.StyleSuccess.Render(fmt.Sprintf("%s %s is already %s", styles.IconCheckmark, m.deploymentName(), state)))
+	b.WriteString("\n\n")
+	b.WriteString(styles.StyleSubtle.Render("No scaling action needed."))
+
+	return b.String()
+}
+
+// renderComplete renders the completion view
+func (m *DeploymentModel) renderComplete() string {
+	var b strings.Builder
+
+	title := "Deployment Scaled Down"
+	if m.config.Restore {
+		title = "Deployment Restored"
+	}
+	b.WriteString(styles.StyleSuccess.Render(fmt.Sprintf("%s %s", styles.IconCheckmark, title)))
+	b.WriteString("\n\n")
+
+	kv := components.NewKeyValueTable()
+	kv.Add("Deployment", m.deploymentName())
+	kv.Add("Node", m.nodeName)
+	kv.Add("Duration", m.elapsedTime.Round(time.Second).String())
+	b.WriteString(kv.Render())
+
+	if !m.config.Restore {
+		b.WriteString("\n\n")
+		b.WriteString(styles.StyleSubtle.Render("Press 'u' on the deployment to restore it."))
+	}
+
+	return b.String()
+}
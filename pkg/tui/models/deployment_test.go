@@ -0,0 +1,164 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeploymentModel_Discovery(t *testing.T) {
+	tests := []struct {
+		name        string
+		replicas    int32
+		annotations map[string]string
+		restore     bool
+		wantState   DeploymentPhaseState
+		wantTarget  int32
+	}{
+		{name: "scale down running deployment", replicas: 1, wantState: DeploymentStateConfirm},
+		{name: "scale down already scaled down", replicas: 0, wantState: DeploymentStateNothingToDo},
+		{name: "restore scaled down deployment", replicas: 0, restore: true, wantState: DeploymentStateConfirm, wantTarget: 1},
+		{
+			name:        "restore to original replicas",
+			replicas:    0,
+			annotations: map[string]string{k8s.OriginalReplicasAnnotation: "3"},
+			restore:     true,
+			wantState:   DeploymentStateConfirm,
+			wantTarget:  3,
+		},
+		{name: "restore running deployment", replicas: 1, restore: true, wantState: DeploymentStateNothingToDo, wantTarget: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replicas := tt.replicas
+			clientset := fake.NewClientset(&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "rook-ceph-osd-3",
+					Namespace:   "rook-ceph",
+					Annotations: tt.annotations,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: &replicas,
+					Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+						NodeSelector: map[string]string{corev1.LabelHostname: "node-a"},
+					}},
+				},
+			})
+			model := NewDeploymentModel(DeploymentModelConfig{
+				Namespace: "rook-ceph",
+				Name:      "rook-ceph-osd-3",
+				Restore:   tt.restore,
+				Client:    &k8s.Client{Clientset: clientset},
+				Context:   context.Background(),
+			})
+			defer model.Abort()
+
+			model.Update(model.discoverDeploymentCmd()())
+
+			if model.state != tt.wantState {
+				t.Errorf("state = %v, want %v", model.state, tt.wantState)
+			}
+			if model.NodeName() != "node-a" {
+				t.Errorf("NodeName() = %q, want node-a", model.NodeName())
+			}
+			if got := model.targetReplicas(); got != tt.wantTarget {
+				t.Errorf("targetReplicas() = %d, want %d", got, tt.wantTarget)
+			}
+		})
+	}
+}
+
+func TestDeploymentModel_DiscoveryError(t *testing.T) {
+	model := NewDeploymentModel(DeploymentModelConfig{
+		Namespace: "rook-ceph",
+		Name:      "missing",
+		Client:    &k8s.Client{Clientset: fake.NewClientset()},
+		Context:   context.Background(),
+	})
+	defer model.Abort()
+
+	model.Update(model.discoverDeploymentCmd()())
+
+	if model.state != DeploymentStateError {
+		t.Fatalf("state = %v, want %v", model.state, DeploymentStateError)
+	}
+	if !strings.Contains(model.Render(), "missing") {
+		t.Error("error view should name the missing deployment")
+	}
+}
+
+func TestDeploymentModel_Stages(t *testing.T) {
+	tests := []struct {
+		name      string
+		restore   bool
+		stages    []string
+		wantPhase string
+	}{
+		{name: "scale down", stages: []string{"noout", "operator", "scale-down"}, wantPhase: "Down"},
+		{name: "restore", restore: true, stages: []string{"scale-up", "operator", "unset-noout"}, wantPhase: "Up"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewDeploymentModel(DeploymentModelConfig{
+				Namespace: "rook-ceph",
+				Name:      "rook-ceph-osd-3",
+				Restore:   tt.restore,
+				Context:   context.Background(),
+			})
+			defer model.Abort()
+
+			if model.PhaseName() != tt.wantPhase {
+				t.Errorf("PhaseName() = %q, want %q", model.PhaseName(), tt.wantPhase)
+			}
+			model.startExecution()
+			for _, stage := range tt.stages {
+				model.Update(DeploymentPhaseProgressMsg{Stage: stage})
+			}
+			if model.state != model.def.Stages[len(model.def.Stages)-1].State {
+				t.Errorf("state = %v after last stage", model.state)
+			}
+
+			model.Update(DeploymentPhaseErrorMsg{Err: errors.New("boom")})
+			if model.state != DeploymentStateError || model.operationInProgress {
+				t.Errorf("error should stop the flow, state = %v", model.state)
+			}
+		})
+	}
+}
+
+func TestDeploymentModel_NotResumable(t *testing.T) {
+	model := NewDeploymentModel(DeploymentModelConfig{
+		Namespace: "rook-ceph",
+		Name:      "rook-ceph-osd-3",
+		Context:   context.Background(),
+	})
+	defer model.Abort()
+
+	// The deployment phases start over on every run, so a failure past the
+	// first stage offers no retry of the failed part
+	model.startExecution()
+	for _, stage := range []string{"noout", "operator", "scale-down"} {
+		model.Update(DeploymentPhaseProgressMsg{Stage: stage})
+	}
+	model.Update(DeploymentPhaseErrorMsg{Err: errors.New("boom")})
+
+	if model.resumable() {
+		t.Error("deployment flow should not be resumable")
+	}
+	if cmd := model.handleKeyPress(tea.KeyPressMsg{Code: 'f', Text: "f"}); cmd != nil {
+		t.Error("retry failed key should be disabled")
+	}
+	if strings.Contains(model.Render(), "without repeating") {
+		t.Error("error view should not promise to continue at the failed stage")
+	}
+}
@@ -4,17 +4,16 @@ package models
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
-	"charm.land/bubbles/v2/help"
-	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"github.com/andri/crook/pkg/config"
 	"github.com/andri/crook/pkg/k8s"
 	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/monitoring"
 	"github.com/andri/crook/pkg/tui/components"
-	"github.com/andri/crook/pkg/tui/keys"
 	"github.com/andri/crook/pkg/tui/styles"
 	appsv1 "k8s.io/api/apps/v1"
 )
@@ -41,6 +40,10 @@ const (
 	DownStateDiscoveringDeployments
 	// DownStateScalingDeployments scales down node deployments
 	DownStateScalingDeployments
+	// DownStateDraining evicts the pods left on the node (optional)
+	DownStateDraining
+	// DownStateForceDeleting force-deletes the pods stuck on a dead node (optional)
+	DownStateForceDeleting
 	// DownStateComplete indicates successful completion
 	DownStateComplete
 	// DownStateError indicates an error occurred
@@ -68,6 +71,10 @@ func (s DownPhaseState) String() string {
 		return "Discovering Deployments"
 	case DownStateScalingDeployments:
 		return "Scaling Deployments"
+	case DownStateDraining:
+		return "Draining Node"
+	case DownStateForceDeleting:
+		return "Force-Deleting Pods"
 	case DownStateComplete:
 		return "Complete"
 	case DownStateError:
@@ -98,6 +105,10 @@ func (s DownPhaseState) Description() string {
 		return "Finding Rook-Ceph deployments running on this node"
 	case DownStateScalingDeployments:
 		return "Scaling down deployments to 0 replicas"
+	case DownStateDraining:
+		return "Evicting remaining pods while honoring PodDisruptionBudgets"
+	case DownStateForceDeleting:
+		return "Force-deleting the pods stuck in Terminating on the dead node"
 	case DownStateComplete:
 		return "All operations completed successfully"
 	case DownStateError:
@@ -127,45 +138,67 @@ type DownModelConfig struct {
 
 	// Context for cancellation
 	Context context.Context
+
+	// Snapshot is the latest state of a hosting view's monitor (for example
+	// `crook ls`). When set, discovery skips cluster queries whose outcome the
+	// snapshot already shows.
+	Snapshot *monitoring.LsMonitorUpdate
+
+	// Updates is the flow's own lossless subscription to the hosting view's
+	// monitor. The host unsubscribes when the flow closes. Optional.
+	Updates <-chan *monitoring.LsMonitorUpdate
 }
 
-// DownPlanItem represents a deployment to be scaled down
+// DownPlanItem represents a workload to be scaled down
 type DownPlanItem struct {
+	// Kind is k8s.WorkloadKindDeployment (assumed when empty) or k8s.WorkloadKindStatefulSet
+	Kind            string
 	Namespace       string
 	Name            string
 	CurrentReplicas int
-	Status          string // "pending", "scaling", "success", "error"
+	Status          string // "pending", "already-down", "scaling", "success", "error", "excluded"
+}
+
+// planStatusAlreadyDown marks down plan items already at 0 replicas, which a
+// re-run of the down phase leaves alone
+const planStatusAlreadyDown = "already-down"
+
+// DrainPodItem is a pod evicted by the optional drain stage
+type DrainPodItem struct {
+	Name   string // "namespace/name"
+	Status k8s.EvictionStatus
 }
 
 // DownModel is the Bubble Tea model for the down phase workflow
 type DownModel struct {
+	// Shared engine: states, prompt, stage list, keys and rendering
+	PhaseModel[DownPhaseState, maintenance.DownPhaseProgress]
+
 	// Configuration
 	config DownModelConfig
 
-	// Current state machine state
-	state DownPhaseState
+	// feed keeps the hosting view's monitor state current while embedded
+	feed monitorFeed
 
-	// Terminal dimensions
-	width  int
-	height int
+	// Operation state
+	deploymentCount   int
+	statefulSetCount  int
+	alreadyDownCount  int
+	currentDeployment string
+	deploymentsScaled int
 
-	// UI components
-	confirmPrompt *components.ConfirmPrompt
-	statusList    *components.StatusList
-	progress      *components.ProgressBar
+	// drainPods lists the pods evicted by the drain stage in the order reported
+	drainPods []DrainPodItem
 
-	// Operation state
-	deploymentCount     int
-	currentDeployment   string
-	deploymentsScaled   int
-	startTime           time.Time
-	elapsedTime         time.Duration
-	lastError           error
-	operationInProgress bool
+	// forceDeletedPods lists the pods force-deleted from a dead node, as "namespace/name"
+	forceDeletedPods []string
 
 	// Down plan (discovered deployments to scale down)
 	downPlan []DownPlanItem
 
+	// excludedPlan lists the discovered deployments discovery.exclude keeps running
+	excludedPlan []DownPlanItem
+
 	// discoveredDeployments holds the actual Deployment objects discovered during
 	// the confirmation phase. These are passed to ExecuteDownPhase to avoid plan drift
 	// (where the confirmed plan differs from what actually gets executed).
@@ -174,34 +207,115 @@ type DownModel struct {
 	// maintenanceWarning contains info about other nodes in maintenance
 	maintenanceWarning *maintenance.OtherNodesMaintenanceInfo
 
-	// Cancellation and progress
-	cancelFunc     context.CancelFunc // Cancel function for ongoing operation
-	progressChan   chan maintenance.DownPhaseProgress
-	progressClosed bool // Track if progress channel is closed
+	// rookConflicts contains CephCluster settings that conflict with manual maintenance
+	rookConflicts []maintenance.RookSettingsConflict
+
+	// dataMovementRisks lists Ceph modules that would move data while the node is down
+	dataMovementRisks []maintenance.DataMovementRisk
 
-	// Keybindings and help
-	keyBindings keys.FlowBindings
-	helpModel   help.Model
+	// capacityImpact estimates the capacity and redundancy lost while the node is down
+	capacityImpact *maintenance.CapacityImpact
+
+	// crushRisks lists failure domains the node is alone in or that already have down OSDs
+	crushRisks []maintenance.CrushRisk
+
+	// stretch describes the node's zone and quorum in a stretch cluster, nil otherwise
+	stretch *maintenance.StretchInfo
+
+	// lastMaintenance compares the plan with the node's previous maintenance, nil when none was recorded
+	lastMaintenance *maintenance.PlanDiff
+
+	// nodeGroup is the managed cloud node group the node belongs to, nil otherwise
+	nodeGroup *maintenance.NodeGroup
+
+	// volumes checks the node's volume attachments once the phase completed
+	volumes volumeCheck
+
+	// external is set for an external Ceph cluster, where no deployments are scaled
+	external bool
+
+	// nooutScope is what noout will be set on, nil when unknown
+	nooutScope *maintenance.NoOutScope
+
+	// deadNode is set when the node is NotReady; the phase then runs in
+	// dead-node mode, labelled as emergency handling
+	deadNode bool
 }
 
 // NewDownModel creates a new down phase model
 func NewDownModel(cfg DownModelConfig) *DownModel {
-	h := help.New()
-	h.Styles.ShortKey = h.Styles.ShortKey.Foreground(styles.ColorInfo)
-	h.Styles.ShortDesc = h.Styles.ShortDesc.Foreground(styles.ColorSubtle)
+	m := &DownModel{
+		downPlan: make([]DownPlanItem, 0),
+	}
+	m.PhaseModel, cfg.Context = NewPhaseModel(cfg.Context, m.definition(cfg.Client, cfg.Config.Drain.Enabled), cfg.ExitBehavior, cfg.Embedded)
+	m.config = cfg
+	m.feed = newMonitorFeed(cfg.Updates, cfg.Snapshot)
+	return m
+}
 
-	return &DownModel{
-		config:        cfg,
-		state:         DownStateInit,
-		confirmPrompt: components.NewConfirmPrompt("Proceed with down phase?"),
-		statusList:    components.NewStatusList(),
-		progress:      components.NewIndeterminateProgress(""),
-		downPlan:      make([]DownPlanItem, 0),
-		keyBindings:   keys.DefaultFlowBindings(),
-		helpModel:     h,
+// definition describes the down phase for the phase engine
+func (m *DownModel) definition(client *k8s.Client, drain bool) PhaseDefinition[DownPhaseState, maintenance.DownPhaseProgress] {
+	return PhaseDefinition[DownPhaseState, maintenance.DownPhaseProgress]{
+		Name:            "Down",
+		ConfirmQuestion: "Proceed with down phase?",
+		States: PhaseStates[DownPhaseState]{
+			Init:        DownStateInit,
+			Confirm:     DownStateConfirm,
+			NothingToDo: DownStateNothingToDo,
+			Complete:    DownStateComplete,
+			Error:       DownStateError,
+		},
+		Stages:           downStages(drain, false, false),
+		Runner:           newFlowRunnerDown(),
+		Execute:          m.runDownPhase,
+		Resumable:        true,
+		TickMsg:          DownPhaseTickMsg{},
+		PendingWorkloads: m.pendingWorkloads,
+		Client:           client,
+		ExitMsg: func(reason FlowExitReason, err error) tea.Msg {
+			return DownFlowExitMsg{Reason: reason, Err: err}
+		},
+		Screens: PhaseScreens{
+			Loading:      m.renderLoading,
+			Confirmation: m.renderConfirmation,
+			NothingToDo:  m.renderNothingToDo,
+			Complete:     m.renderComplete,
+			Banner:       m.renderDeadNodeBanner,
+		},
 	}
 }
 
+// downStages lists the stages of the down phase. The drain stage is only
+// listed when it runs: a dead node is not drained, its stuck pods are
+// force-deleted in its place when forceDelete is set.
+func downStages(drain, deadNode, forceDelete bool) []PhaseStage[DownPhaseState] {
+	stages := []PhaseStage[DownPhaseState]{
+		{State: DownStatePreFlight, Label: "Pre-flight checks", Progress: maintenance.PreflightProgressStages},
+		{State: DownStateCordoning, Label: "Cordon node", Progress: []string{"cordon"}},
+		{State: DownStateSettingNoOut, Label: "Set noout flag", Progress: []string{"noout"}},
+		{State: DownStateScalingOperator, Label: "Scale operator", Progress: []string{"operator"}},
+		{State: DownStateDiscoveringDeployments, Label: "Discover deployments", Progress: []string{"discover"}},
+		{State: DownStateScalingDeployments, Label: "Scale deployments", Progress: []string{"scale-down"}},
+	}
+	switch {
+	case deadNode && forceDelete:
+		stages = append(stages, PhaseStage[DownPhaseState]{
+			State: DownStateForceDeleting, Label: "Force-delete stuck pods", Progress: maintenance.ForceDeleteProgressStages,
+		})
+	case drain && !deadNode:
+		stages = append(stages, PhaseStage[DownPhaseState]{
+			State: DownStateDraining, Label: "Drain node", Progress: maintenance.DrainProgressStages,
+		})
+	}
+	return stages
+}
+
+// forceDeletesPods reports whether the phase force-deletes the stuck pods of
+// a dead node, set by drain.force-delete-on-dead-node
+func (m *DownModel) forceDeletesPods() bool {
+	return m.deadNode && m.config.Config.Drain.ForceDeleteOnDeadNode
+}
+
 // Messages for down phase state transitions
 
 // DownPhaseStartMsg signals to start the down phase
@@ -212,6 +326,10 @@ type DownPhaseProgressMsg struct {
 	Stage       string
 	Description string
 	Deployment  string
+	Pod         string
+	Check       *maintenance.CheckStatus
+	Sequence    uint64
+	Percent     int
 }
 
 // DownPhaseCompleteMsg signals successful completion
@@ -229,6 +347,8 @@ type DownPhaseTickMsg struct{}
 // DeploymentsDiscoveredMsg reports discovered deployments for confirmation
 type DeploymentsDiscoveredMsg struct {
 	DownPlan []DownPlanItem
+	// Excluded lists the deployments discovery.exclude keeps running, shown greyed out
+	Excluded []DownPlanItem
 	// Deployments contains the actual Deployment objects for execution.
 	// This avoids plan drift between confirmation and execution.
 	Deployments []appsv1.Deployment
@@ -237,6 +357,28 @@ type DeploymentsDiscoveredMsg struct {
 	AlreadyInDesiredState bool
 	// MaintenanceWarning contains info about other nodes in maintenance, if any
 	MaintenanceWarning *maintenance.OtherNodesMaintenanceInfo
+	// RookConflicts lists CephCluster settings that conflict with manual maintenance
+	RookConflicts []maintenance.RookSettingsConflict
+	// DataMovementRisks lists balancer/autoscaler activity that would move data during maintenance
+	DataMovementRisks []maintenance.DataMovementRisk
+	// CapacityImpact estimates the capacity and redundancy lost while the node is down, if known
+	CapacityImpact *maintenance.CapacityImpact
+	// CrushRisks lists failure domains the node is alone in or that already have down OSDs
+	CrushRisks []maintenance.CrushRisk
+	// Stretch describes the node's zone and quorum in a stretch cluster, nil otherwise
+	Stretch *maintenance.StretchInfo
+	// External is set for an external Ceph cluster, where no deployments are scaled
+	External bool
+	// ScaleHistory holds the cluster's earlier scale timings for the ETA, if readable
+	ScaleHistory *maintenance.ScaleHistory
+	// LastMaintenance compares the plan with the node's previous maintenance, nil when none was recorded
+	LastMaintenance *maintenance.PlanDiff
+	// DeadNode is set when the node is NotReady, so the phase runs in dead-node mode
+	DeadNode bool
+	// NodeGroup is the managed cloud node group the node belongs to, nil otherwise
+	NodeGroup *maintenance.NodeGroup
+	// NoOutScope is what noout will be set on, nil when it could not be resolved
+	NoOutScope *maintenance.NoOutScope
 }
 
 // DownProgressChannelClosedMsg signals that the progress channel was closed
@@ -248,6 +390,7 @@ func (m *DownModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.discoverDeploymentsCmd(),
 		m.tickCmd(),
+		m.feed.listen(),
 	)
 }
 
@@ -265,26 +408,29 @@ func (m *DownModel) discoverDeploymentsCmd() tea.Cmd {
 		}
 
 		// Order deployments for down phase (same order as actual scaling)
-		orderedDeployments := maintenance.OrderDeploymentsForDown(deployments)
+		orderedDeployments, excluded := maintenance.ExcludeDeployments(
+			maintenance.OrderDeploymentsForDown(deployments), m.config.Config.Discovery)
 
-		// Build down plan for display
-		downPlan := make([]DownPlanItem, 0, len(orderedDeployments))
-		for _, dep := range orderedDeployments {
-			currentReplicas := int32(0)
-			if dep.Spec.Replicas != nil {
-				currentReplicas = *dep.Spec.Replicas
-			}
-			item := DownPlanItem{
-				Namespace:       dep.Namespace,
-				Name:            dep.Name,
-				CurrentReplicas: int(currentReplicas),
-				Status:          "pending",
-			}
-			downPlan = append(downPlan, item)
+		// StatefulSets are scaled after the deployments (discovery.statefulsets)
+		statefulSets, excludedSets, err := maintenance.DiscoverStatefulSets(
+			m.config.Context,
+			m.config.Client,
+			m.config.Config,
+			m.config.NodeName,
+			nil,
+		)
+		if err != nil {
+			return DownPhaseErrorMsg{Err: err, Stage: "discover"}
 		}
 
+		// Build down plan for display
+		downPlan := append(newDownPlan(orderedDeployments, "pending"), newStatefulSetDownPlan(statefulSets, "pending")...)
+		excludedPlan := append(newDownPlan(excluded, "excluded"), newStatefulSetDownPlan(excludedSets, "excluded")...)
+
+		observed := m.feed.observed()
+
 		// Check if already in desired down state (complete check including node/operator/noout)
-		alreadyInState := maintenance.IsInDownState(
+		alreadyInState := !observed.notInDownState(m.config.NodeName) && maintenance.IsInDownState(
 			m.config.Context,
 			m.config.Client,
 			m.config.Config,
@@ -293,139 +439,194 @@ func (m *DownModel) discoverDeploymentsCmd() tea.Cmd {
 		)
 
 		// Check for other nodes in maintenance
-		maintenanceWarning, _ := maintenance.CheckOtherNodesInMaintenance(
+		maintenanceWarning, known := observed.otherNodesInMaintenance(m.config.NodeName)
+		if !known {
+			maintenanceWarning, _ = maintenance.CheckOtherNodesInMaintenance(
+				m.config.Context,
+				m.config.Client,
+				m.config.Config,
+				m.config.NodeName,
+			)
+		}
+
+		// Check for conflicting CephCluster settings (best-effort)
+		rookConflicts, _ := maintenance.CheckRookSettings(
+			m.config.Context,
+			m.config.Client,
+			m.config.Config,
+		)
+
+		// Check balancer and pg_autoscaler state (best-effort)
+		var dataMovementRisks []maintenance.DataMovementRisk
+		if !observed.noDataMovementRisks() {
+			dataMovementRisks = maintenance.CheckDataMovementRisks(
+				m.config.Context,
+				m.config.Client,
+				m.config.Config,
+			)
+		}
+
+		// Estimate capacity, redundancy and failure-domain impact (best-effort)
+		var capacityImpact *maintenance.CapacityImpact
+		var crushRisks []maintenance.CrushRisk
+		if placement, err := maintenance.LoadPlacement(
+			m.config.Context,
+			m.config.Client,
+			m.config.Config,
+		); err == nil {
+			capacityImpact = placement.CapacityImpact(m.config.NodeName)
+			crushRisks = placement.CrushRisks(m.config.NodeName)
+		}
+
+		// Check stretch mode zone and quorum state (best-effort)
+		stretch, _ := maintenance.CheckStretchMode(
 			m.config.Context,
 			m.config.Client,
 			m.config.Config,
 			m.config.NodeName,
 		)
 
+		// Earlier scale timings seed the ETA (best-effort)
+		scaleHistory, _ := maintenance.LoadScaleHistory(
+			m.config.Context,
+			m.config.Client,
+			m.config.Config,
+		)
+
+		// Compare with the workloads of the node's previous maintenance (best-effort)
+		lastMaintenance := maintenance.DiffLastMaintenance(
+			m.config.Context,
+			m.config.Client,
+			m.config.Config,
+			m.config.NodeName,
+			slices.Concat(k8s.DeploymentWorkloads(orderedDeployments), k8s.StatefulSetWorkloads(statefulSets)),
+		)
+
+		// Resolve what noout will be set on (best-effort)
+		var nooutScope *maintenance.NoOutScope
+		if scope, err := maintenance.ResolveNoOutScope(
+			m.config.Context,
+			m.config.Client,
+			m.config.Config,
+			m.config.NodeName,
+		); err == nil {
+			nooutScope = &scope
+		}
+
 		return DeploymentsDiscoveredMsg{
 			DownPlan:              downPlan,
+			Excluded:              excludedPlan,
 			Deployments:           orderedDeployments, // Include ordered deployments for execution
 			AlreadyInDesiredState: alreadyInState,
 			MaintenanceWarning:    maintenanceWarning,
+			RookConflicts:         rookConflicts,
+			DataMovementRisks:     dataMovementRisks,
+			CapacityImpact:        capacityImpact,
+			CrushRisks:            crushRisks,
+			Stretch:               stretch,
+			External:              maintenance.IsExternalCluster(m.config.Context, m.config.Client, m.config.Config),
+			ScaleHistory:          scaleHistory,
+			LastMaintenance:       lastMaintenance,
+			DeadNode:              maintenance.IsNodeDead(m.config.Context, m.config.Client, m.config.NodeName),
+			NodeGroup: maintenance.DetectNodeGroup(
+				m.config.Context,
+				m.config.Client,
+				m.config.NodeName,
+				m.config.Config.Cloud.DisableScaleDown,
+			),
+			NoOutScope: nooutScope,
 		}
 	}
 }
 
-// tickCmd returns a command that ticks every 100ms
-func (m *DownModel) tickCmd() tea.Cmd {
-	return tea.Tick(100*time.Millisecond, func(_ time.Time) tea.Msg {
-		return DownPhaseTickMsg{}
-	})
-}
-
-// executeDownPhaseCmd runs the actual down phase operation.
-// It sets up a progress channel and returns a batch of commands:
-// one to execute the operation and one to listen for progress.
-func (m *DownModel) executeDownPhaseCmd() tea.Cmd {
-	// Create cancellable context
-	ctx, cancel := context.WithCancel(m.config.Context)
-	m.cancelFunc = cancel
-
-	// Create progress channel (buffered to avoid blocking)
-	m.progressChan = make(chan maintenance.DownPhaseProgress, 10)
-	m.progressClosed = false
-
-	// Return batch of operation + progress listener
-	return tea.Batch(
-		m.runDownPhase(ctx),
-		m.listenForProgress(),
+// newFlowRunnerDown creates the runner delivering down phase progress
+func newFlowRunnerDown() *FlowRunner[maintenance.DownPhaseProgress] {
+	return NewFlowRunner(
+		"down-progress",
+		func(progress maintenance.DownPhaseProgress) tea.Msg {
+			return DownPhaseProgressMsg{
+				Stage:       progress.Stage,
+				Description: progress.Description,
+				Deployment:  progress.Deployment,
+				Pod:         progress.Pod,
+				Check:       progress.Check,
+				Sequence:    progress.Sequence,
+				Percent:     progress.Percent,
+			}
+		},
+		DownProgressChannelClosedMsg{},
 	)
 }
 
-// runDownPhase executes the maintenance operation in a goroutine
-func (m *DownModel) runDownPhase(ctx context.Context) tea.Cmd {
-	progressChan := m.progressChan
+// runDownPhase returns the down phase operation run by the flow runner
+func (m *DownModel) runDownPhase() func(context.Context, func(maintenance.DownPhaseProgress)) tea.Msg {
 	client := m.config.Client
 	cfg := m.config.Config
 	nodeName := m.config.NodeName
+	resume := m.resume
+	acknowledged := m.acknowledged
+	deadNode := m.deadNode
 
-	return func() tea.Msg {
+	return func(ctx context.Context, report func(maintenance.DownPhaseProgress)) tea.Msg {
 		opts := maintenance.DownPhaseOptions{
-			ProgressCallback: func(progress maintenance.DownPhaseProgress) {
-				// Non-blocking send to channel
-				select {
-				case progressChan <- progress:
-				default:
-					// Channel full, skip this update
-				}
-			},
-		}
-
-		err := maintenance.ExecuteDownPhase(
-			ctx,
-			client,
-			cfg,
-			nodeName,
-			opts,
-		)
-
-		// Close progress channel when done
-		close(progressChan)
+			ProgressCallback:    report,
+			Only:                resume.only,
+			ResumeFrom:          resume.from,
+			AcknowledgeWarnings: acknowledged,
+			DeadNode:            deadNode,
+		}
 
-		if err != nil {
+		if err := maintenance.ExecuteDownPhase(ctx, client, cfg, nodeName, opts); err != nil {
 			return DownPhaseErrorMsg{Err: err, Stage: "execute"}
 		}
-
 		return DownPhaseCompleteMsg{}
 	}
 }
 
-// listenForProgress creates a command that listens for progress updates
-// and returns them as messages. It reschedules itself until the channel closes.
-func (m *DownModel) listenForProgress() tea.Cmd {
-	progressChan := m.progressChan
-
-	return func() tea.Msg {
-		progress, ok := <-progressChan
-		if !ok {
-			// Channel closed
-			return DownProgressChannelClosedMsg{}
-		}
-		return DownPhaseProgressMsg{
-			Stage:       progress.Stage,
-			Description: progress.Description,
-			Deployment:  progress.Deployment,
-		}
-	}
-}
-
 // Update implements tea.Model
 func (m *DownModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		cmd := m.handleKeyPress(msg)
-		if cmd != nil {
-			cmds = append(cmds, cmd)
-		}
-
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-
 	case DownPhaseTickMsg:
-		if m.operationInProgress {
-			m.elapsedTime = time.Since(m.startTime)
-		}
-		// Update spinner
-		newProgress, cmd := m.progress.Update(msg)
-		if p, ok := newProgress.(*components.ProgressBar); ok {
-			m.progress = p
+		cmds = append(cmds, m.tick(msg))
+
+	case FlowMonitorUpdateMsg:
+		ok, touched := m.feed.receive(msg, m.config.NodeName, m.discoveredDeployments)
+		if !ok {
+			break
 		}
-		if cmd != nil {
-			cmds = append(cmds, cmd)
+		cmds = append(cmds, m.feed.listen())
+		// The plan awaiting confirmation is rediscovered when its node or
+		// workloads change, so the confirmed plan is the current one
+		if touched && m.state == DownStateConfirm {
+			cmds = append(cmds, m.discoverDeploymentsCmd())
 		}
-		cmds = append(cmds, m.tickCmd())
 
 	case DeploymentsDiscoveredMsg:
+		if m.state != DownStateInit && m.state != DownStateConfirm {
+			// A rediscovery that finished after the plan was confirmed
+			break
+		}
 		m.downPlan = msg.DownPlan
+		m.excludedPlan = msg.Excluded
 		m.discoveredDeployments = msg.Deployments // Store for execution
-		m.deploymentCount = len(msg.DownPlan)
+		m.alreadyDownCount = countAlreadyDown(msg.DownPlan)
+		m.deploymentCount = len(msg.DownPlan) - m.alreadyDownCount
+		m.statefulSetCount = countStatefulSets(msg.DownPlan)
 		m.maintenanceWarning = msg.MaintenanceWarning // Store for display
+		m.rookConflicts = msg.RookConflicts
+		m.dataMovementRisks = msg.DataMovementRisks
+		m.capacityImpact = msg.CapacityImpact
+		m.crushRisks = msg.CrushRisks
+		m.stretch = msg.Stretch
+		m.external = msg.External
+		m.lastMaintenance = msg.LastMaintenance
+		m.deadNode = msg.DeadNode
+		m.nodeGroup = msg.NodeGroup
+		m.nooutScope = msg.NoOutScope
+		m.def.Stages = downStages(m.config.Config.Drain.Enabled, m.deadNode, m.forceDeletesPods())
+		m.startScaleETA(maintenance.ScalePhaseDown, msg.ScaleHistory)
 
 		// Check if already in desired down state (node cordoned, noout set, operator down, deployments scaled)
 		if msg.AlreadyInDesiredState {
@@ -433,200 +634,261 @@ func (m *DownModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.state = DownStateConfirm
 			m.confirmPrompt.Details = fmt.Sprintf("%d deployment(s) will be scaled to 0", m.deploymentCount)
+			if m.alreadyDownCount > 0 {
+				m.confirmPrompt.Details = "Partial plan: " + m.partialPlan().String()
+			}
+			if m.external {
+				m.confirmPrompt.Details = "External cluster: only cordon and noout"
+			}
+			if m.deadNode {
+				m.confirmPrompt.Details = "Dead node: scale down without waiting for its pods"
+			}
+			if m.forceDeletesPods() {
+				m.confirmPrompt.Details = "Dead node: scale down without waiting, then force-delete its stuck pods"
+			}
 		}
 
 	case DownPhaseProgressMsg:
+		m.trackProgress(msg.Sequence, msg.Percent)
 		m.updateStateFromProgress(msg)
-		// Re-schedule the progress listener if channel is still open
-		if !m.progressClosed {
-			cmds = append(cmds, m.listenForProgress())
-		}
+		// Re-schedule the progress listener while the channel is open
+		cmds = append(cmds, m.runner.Listen())
 
 	case DownProgressChannelClosedMsg:
-		m.progressClosed = true
-		// No need to reschedule listener
+		m.runner.MarkClosed()
 
 	case DownPhaseCompleteMsg:
-		m.state = DownStateComplete
-		m.operationInProgress = false
-		m.cancelFunc = nil // Clear cancel func
-		m.progress.Complete()
+		m.complete()
+		cmds = append(cmds, m.notifyFinished(m.config.Config.UI, m.config.NodeName))
+		// A dead node's volumes cannot detach; only a live one can still be
+		// shut down cleanly
+		if !m.deadNode {
+			cmds = append(cmds, m.volumes.start(m.config.Context, m.config.Client, m.config.NodeName, maintenance.ScalePhaseDown))
+		}
+
+	case NodeVolumesMsg:
+		m.volumes.record(msg)
 
 	case DownPhaseErrorMsg:
-		m.state = DownStateError
-		m.lastError = msg.Err
-		m.operationInProgress = false
-		m.cancelFunc = nil // Clear cancel func
-		m.progress.Error()
-
-	case components.ConfirmResultMsg:
-		if msg.Result == components.ConfirmYes {
-			m.startExecution()
-			cmds = append(cmds, m.executeDownPhaseCmd())
-		} else {
-			// User cancelled or declined
-			reason := FlowExitDeclined
-			if msg.Result == components.ConfirmCancelled {
-				reason = FlowExitCancelled
-			}
-			return m, m.exitCmd(reason, nil)
-		}
+		m.fail(msg.Err)
+		cmds = append(cmds, m.notifyFinished(m.config.Config.UI, m.config.NodeName))
 	}
 
-	// Update confirm prompt if in confirm state
-	if m.state == DownStateConfirm {
-		newPrompt, cmd := m.confirmPrompt.Update(msg)
-		if p, ok := newPrompt.(*components.ConfirmPrompt); ok {
-			m.confirmPrompt = p
-		}
-		if cmd != nil {
-			cmds = append(cmds, cmd)
-		}
-	}
+	// Input, resizing and the confirmation prompt behave alike in all phases
+	cmds = append(cmds, m.update(msg))
 
 	return m, tea.Batch(cmds...)
 }
 
-// handleKeyPress processes keyboard input based on current state
-func (m *DownModel) handleKeyPress(msg tea.KeyMsg) tea.Cmd {
-	// Update keybinding state based on current flow state
-	m.updateKeyBindings()
+// updateStateFromProgress updates the model state based on progress messages
+func (m *DownModel) updateStateFromProgress(msg DownPhaseProgressMsg) {
+	m.advance(msg.Stage)
+	if msg.Check != nil {
+		m.recordCheck(*msg.Check)
+	}
 
-	switch m.state { //nolint:exhaustive // default handles all operation states uniformly
-	case DownStateError:
-		switch {
-		case key.Matches(msg, m.keyBindings.Retry):
-			m.startExecution()
-			return m.executeDownPhaseCmd()
-		case key.Matches(msg, m.keyBindings.Quit):
-			return m.exitCmd(FlowExitError, m.lastError)
+	switch msg.Stage {
+	case "scale-down":
+		// If there was a previous deployment being scaled, mark it as complete
+		if m.currentDeployment != "" {
+			m.updateDeploymentStatus(m.currentDeployment, "success")
+			m.deploymentsScaled++
 		}
-
-	case DownStateComplete:
-		if key.Matches(msg, m.keyBindings.Exit) {
-			return m.exitCmd(FlowExitCompleted, nil)
+		// Mark the new deployment as in-progress
+		m.currentDeployment = msg.Deployment
+		m.updateDeploymentStatus(msg.Deployment, "scaling")
+		m.scalingWorkload(msg.Deployment)
+		// Update status item to show progress counter and deployment list
+		if item := m.statusList.Get(5); item != nil {
+			item.SetLabel(fmt.Sprintf("Scale deployments (%d/%d)", m.deploymentsScaled, m.deploymentCount))
+			item.SetDetails(m.buildDeploymentListDetails())
+			item.DetailsOnNewLine = true
 		}
-
-	case DownStateNothingToDo:
-		if key.Matches(msg, m.keyBindings.Exit) {
-			return m.exitCmd(FlowExitNothingToDo, nil)
+	case "drain", "drain-evicting", "drain-blocked", "drain-evicted":
+		// The first evicted pod also means the last deployment is done
+		m.finishDeployments()
+		if msg.Pod != "" {
+			m.updateDrainPod(msg)
+		}
+		// The drain stage follows the six fixed stages
+		if item := m.statusList.Get(6); item != nil {
+			item.SetLabel(fmt.Sprintf("Drain node (%d/%d evicted)", m.podsEvicted(), len(m.drainPods)))
+			item.SetDetails(m.buildDrainPodDetails())
+			item.DetailsOnNewLine = true
+		}
+	case "force-delete":
+		m.finishDeployments()
+		if msg.Pod != "" {
+			m.forceDeletedPods = append(m.forceDeletedPods, msg.Pod)
+		}
+		// Like the drain stage it replaces, it follows the six fixed stages
+		if item := m.statusList.Get(6); item != nil {
+			item.SetLabel(fmt.Sprintf("Force-delete stuck pods (%d deleted)", len(m.forceDeletedPods)))
+			item.SetDetails(strings.Join(m.forceDeletedPods, "\n    "))
+			item.DetailsOnNewLine = true
 		}
+	case phaseCompleteStage:
+		m.finishDeployments()
+	}
+}
 
-	case DownStateConfirm:
-		// Let the confirm prompt handle it
-		return nil
+// finishDeployments marks the last deployment as scaled and keeps the
+// deployment list visible with the final count
+func (m *DownModel) finishDeployments() {
+	if m.currentDeployment == "" {
+		return
+	}
+	m.updateDeploymentStatus(m.currentDeployment, "success")
+	m.deploymentsScaled++
+	m.currentDeployment = ""
+	m.scalingDone()
+	if item := m.statusList.Get(5); item != nil {
+		item.SetLabel(fmt.Sprintf("Scale deployments (%d/%d)", m.deploymentsScaled, m.deploymentCount))
+		item.SetDetails(m.buildDeploymentListDetails())
+	}
+}
 
+// updateDrainPod records the eviction state reported for a pod
+func (m *DownModel) updateDrainPod(msg DownPhaseProgressMsg) {
+	var status k8s.EvictionStatus
+	switch msg.Stage {
+	case "drain-blocked":
+		status = k8s.EvictionBlocked
+	case "drain-evicted":
+		status = k8s.EvictionEvicted
 	default:
-		// During operations, allow cancel
-		if key.Matches(msg, m.keyBindings.Interrupt) {
-			if m.cancelFunc != nil {
-				m.cancelFunc()
-			}
-			return m.exitCmd(FlowExitCancelled, nil)
+		status = k8s.EvictionEvicting
+	}
+
+	for i := range m.drainPods {
+		if m.drainPods[i].Name == msg.Pod {
+			m.drainPods[i].Status = status
+			return
 		}
 	}
+	m.drainPods = append(m.drainPods, DrainPodItem{Name: msg.Pod, Status: status})
+}
 
-	return nil
+// podsEvicted returns the number of pods the drain stage has evicted
+func (m *DownModel) podsEvicted() int {
+	evicted := 0
+	for _, pod := range m.drainPods {
+		if pod.Status == k8s.EvictionEvicted {
+			evicted++
+		}
+	}
+	return evicted
 }
 
-// updateKeyBindings updates the keybinding state based on current flow state
-func (m *DownModel) updateKeyBindings() {
-	switch m.state { //nolint:exhaustive // default handles all operation states
-	case DownStateConfirm:
-		m.keyBindings.SetStateConfirm()
-	case DownStateError:
-		m.keyBindings.SetStateError()
-	case DownStateComplete, DownStateNothingToDo:
-		m.keyBindings.SetStateComplete()
-	default:
-		m.keyBindings.SetStateRunning()
+// buildDrainPodDetails builds a multi-line string showing the evicted pods
+// with status icons
+func (m *DownModel) buildDrainPodDetails() string {
+	var lines []string
+	for _, pod := range m.drainPods {
+		switch pod.Status {
+		case k8s.EvictionEvicted:
+			lines = append(lines, styles.StyleSuccess.Render(styles.IconCheckmark)+" "+pod.Name)
+		case k8s.EvictionEvicting:
+			lines = append(lines, styles.StyleStatus.Render(styles.IconSpinner)+" "+pod.Name)
+		case k8s.EvictionBlocked:
+			lines = append(lines, styles.StyleWarning.Render(styles.IconWarning+" "+pod.Name)+" "+
+				styles.StyleSubtle.Render("blocked by PodDisruptionBudget, retrying"))
+		}
 	}
+	return strings.Join(lines, "\n    ")
 }
 
-func (m *DownModel) exitCmd(reason FlowExitReason, err error) tea.Cmd {
-	return flowExitCmd(m.config.ExitBehavior, DownFlowExitMsg{Reason: reason, Err: err})
+// newDownPlan builds down plan items for deployments in the given status.
+// Pending deployments already at 0 replicas are marked planStatusAlreadyDown.
+func newDownPlan(deployments []appsv1.Deployment, status string) []DownPlanItem {
+	plan := make([]DownPlanItem, 0, len(deployments))
+	for _, dep := range deployments {
+		currentReplicas := int32(0)
+		if dep.Spec.Replicas != nil {
+			currentReplicas = *dep.Spec.Replicas
+		}
+		plan = append(plan, DownPlanItem{
+			Kind:            k8s.WorkloadKindDeployment,
+			Namespace:       dep.Namespace,
+			Name:            dep.Name,
+			CurrentReplicas: int(currentReplicas),
+			Status:          downPlanStatus(status, currentReplicas),
+		})
+	}
+	return plan
 }
 
-// startExecution initializes state for operation execution
-func (m *DownModel) startExecution() {
-	m.operationInProgress = true
-	m.startTime = time.Now()
-	m.state = DownStatePreFlight // First stage is pre-flight checks
-	m.progress = components.NewIndeterminateProgress("Processing...")
-	m.initStatusList()
+// newStatefulSetDownPlan builds down plan items for StatefulSets in the given status
+func newStatefulSetDownPlan(statefulSets []appsv1.StatefulSet, status string) []DownPlanItem {
+	plan := make([]DownPlanItem, 0, len(statefulSets))
+	for _, sts := range statefulSets {
+		currentReplicas := int32(0)
+		if sts.Spec.Replicas != nil {
+			currentReplicas = *sts.Spec.Replicas
+		}
+		plan = append(plan, DownPlanItem{
+			Kind:            k8s.WorkloadKindStatefulSet,
+			Namespace:       sts.Namespace,
+			Name:            sts.Name,
+			CurrentReplicas: int(currentReplicas),
+			Status:          downPlanStatus(status, currentReplicas),
+		})
+	}
+	return plan
 }
 
-// initStatusList creates the status list for tracking progress
-func (m *DownModel) initStatusList() {
-	m.statusList = components.NewStatusList()
-	m.statusList.AddStatus("Pre-flight checks", components.StatusTypePending)
-	m.statusList.AddStatus("Cordon node", components.StatusTypePending)
-	m.statusList.AddStatus("Set noout flag", components.StatusTypePending)
-	m.statusList.AddStatus("Scale operator", components.StatusTypePending)
-	m.statusList.AddStatus("Discover deployments", components.StatusTypePending)
-	m.statusList.AddStatus("Scale deployments", components.StatusTypePending)
+// downPlanStatus marks pending workloads already at 0 replicas as already down
+func downPlanStatus(status string, replicas int32) string {
+	if status == "pending" && replicas == 0 {
+		return planStatusAlreadyDown
+	}
+	return status
 }
 
-// updateStateFromProgress updates the model state based on progress messages
-func (m *DownModel) updateStateFromProgress(msg DownPhaseProgressMsg) {
-	switch msg.Stage {
-	case "pre-flight":
-		m.state = DownStatePreFlight
-		m.updateStatusItem(0, components.StatusTypeRunning)
-	case "cordon":
-		m.state = DownStateCordoning
-		m.updateStatusItem(0, components.StatusTypeSuccess)
-		m.updateStatusItem(1, components.StatusTypeRunning)
-	case "noout":
-		m.state = DownStateSettingNoOut
-		m.updateStatusItem(1, components.StatusTypeSuccess)
-		m.updateStatusItem(2, components.StatusTypeRunning)
-	case "operator":
-		m.state = DownStateScalingOperator
-		m.updateStatusItem(2, components.StatusTypeSuccess)
-		m.updateStatusItem(3, components.StatusTypeRunning)
-	case "discover":
-		m.state = DownStateDiscoveringDeployments
-		m.updateStatusItem(3, components.StatusTypeSuccess)
-		m.updateStatusItem(4, components.StatusTypeRunning)
-	case "scale-down":
-		m.state = DownStateScalingDeployments
-		m.updateStatusItem(4, components.StatusTypeSuccess)
-		m.updateStatusItem(5, components.StatusTypeRunning)
-		// If there was a previous deployment being scaled, mark it as complete
-		if m.currentDeployment != "" {
-			m.updateDeploymentStatus(m.currentDeployment, "success")
-			m.deploymentsScaled++
+// countStatefulSets counts the StatefulSet items of a down plan still to scale
+func countStatefulSets(plan []DownPlanItem) int {
+	count := 0
+	for _, item := range plan {
+		if item.Kind == k8s.WorkloadKindStatefulSet && item.Status != planStatusAlreadyDown {
+			count++
 		}
-		// Mark the new deployment as in-progress
-		m.currentDeployment = msg.Deployment
-		m.updateDeploymentStatus(msg.Deployment, "scaling")
-		// Update status item to show progress counter and deployment list
-		if item := m.statusList.Get(5); item != nil {
-			item.SetLabel(fmt.Sprintf("Scale deployments (%d/%d)", m.deploymentsScaled, m.deploymentCount))
-			item.SetDetails(m.buildDeploymentListDetails())
-			item.DetailsOnNewLine = true
-		}
-	case "complete":
-		// Mark the last deployment as complete
-		if m.currentDeployment != "" {
-			m.updateDeploymentStatus(m.currentDeployment, "success")
-			m.deploymentsScaled++
+	}
+	return count
+}
+
+// pendingWorkloads lists the down plan workloads not yet scaled
+func (m *DownModel) pendingWorkloads() []string {
+	var pending []string
+	for _, item := range m.downPlan {
+		if item.Status == "pending" {
+			pending = append(pending, item.Namespace+"/"+item.Name)
 		}
-		m.updateStatusItem(5, components.StatusTypeSuccess)
-		// Keep deployment list visible with final count
-		if item := m.statusList.Get(5); item != nil {
-			item.SetLabel(fmt.Sprintf("Scale deployments (%d/%d)", m.deploymentsScaled, m.deploymentCount))
-			item.SetDetails(m.buildDeploymentListDetails())
+	}
+	return pending
+}
+
+// countAlreadyDown counts the down plan items already at 0 replicas
+func countAlreadyDown(plan []DownPlanItem) int {
+	count := 0
+	for _, item := range plan {
+		if item.Status == planStatusAlreadyDown {
+			count++
 		}
 	}
+	return count
+}
+
+// partialPlan summarizes the workloads left alone and still to scale
+func (m *DownModel) partialPlan() maintenance.PartialPlan {
+	return maintenance.PartialPlan{AlreadyDown: m.alreadyDownCount, ToScale: m.deploymentCount}
 }
 
-// updateStatusItem safely updates a status item
-func (m *DownModel) updateStatusItem(index int, status components.StatusType) {
-	if item := m.statusList.Get(index); item != nil {
-		item.SetType(status)
+// planKind returns the workload kind shown for a plan item
+func planKind(kind string) string {
+	if kind == "" {
+		return k8s.WorkloadKindDeployment
 	}
+	return kind
 }
 
 // updateDeploymentStatus updates the status of a deployment in the down plan
@@ -651,6 +913,8 @@ func (m *DownModel) buildDeploymentListDetails() string {
 			styledIcon = styles.StyleSuccess.Render(styles.IconCheckmark)
 		case "scaling":
 			styledIcon = styles.StyleStatus.Render(styles.IconSpinner)
+		case planStatusAlreadyDown:
+			styledIcon = styles.StyleSubtle.Render(styles.IconCheckmark)
 		default: // pending
 			styledIcon = styles.StyleSubtle.Render("○")
 		}
@@ -659,52 +923,11 @@ func (m *DownModel) buildDeploymentListDetails() string {
 	return strings.Join(lines, "\n    ")
 }
 
-// View implements tea.Model
-func (m *DownModel) View() tea.View {
-	return tea.NewView(m.Render())
-}
-
 // NodeName returns the target node name.
 func (m *DownModel) NodeName() string {
 	return m.config.NodeName
 }
 
-// PhaseName returns "Down" for the down phase.
-func (m *DownModel) PhaseName() string {
-	return "Down"
-}
-
-// Render returns the string representation for composition
-func (m *DownModel) Render() string {
-	var b strings.Builder
-
-	// Main content based on state
-	switch m.state { //nolint:exhaustive // default handles all operation states uniformly
-	case DownStateInit:
-		b.WriteString(m.renderLoading())
-	case DownStateConfirm:
-		b.WriteString(m.renderConfirmation())
-	case DownStateNothingToDo:
-		b.WriteString(m.renderNothingToDo())
-	case DownStateError:
-		b.WriteString(m.renderError())
-	case DownStateComplete:
-		b.WriteString(m.renderComplete())
-	default:
-		b.WriteString(m.renderProgress())
-	}
-
-	// Footer with help
-	b.WriteString("\n\n")
-	b.WriteString(m.renderFooter())
-
-	if m.config.Embedded {
-		return b.String()
-	}
-
-	return styles.StyleBox.Width(min(m.width-4, 80)).Render(b.String())
-}
-
 // renderLoading renders the loading state
 func (m *DownModel) renderLoading() string {
 	return fmt.Sprintf("%s Discovering deployments on node %s...",
@@ -712,6 +935,15 @@ func (m *DownModel) renderLoading() string {
 		m.config.NodeName)
 }
 
+// nooutScopeSuffix names what the noout flag covers in a plan step, e.g.
+// " (CRUSH host worker-1)"; empty when the scope is unknown
+func nooutScopeSuffix(scope *maintenance.NoOutScope) string {
+	if scope == nil {
+		return ""
+	}
+	return " (" + scope.String() + ")"
+}
+
 // renderConfirmation renders the confirmation screen with down plan
 func (m *DownModel) renderConfirmation() string {
 	var b strings.Builder
@@ -720,30 +952,85 @@ func (m *DownModel) renderConfirmation() string {
 	b.WriteString(styles.StyleStatus.Render("This will:"))
 	b.WriteString("\n")
 	b.WriteString("  1. Cordon the node (mark unschedulable)\n")
-	b.WriteString("  2. Set Ceph noout flag\n")
-	b.WriteString("  3. Scale down rook-ceph-operator\n")
-	fmt.Fprintf(&b, "  4. Scale down %d deployment(s) to 0 replicas\n", m.deploymentCount)
+	b.WriteString("  2. Set Ceph noout flag" + nooutScopeSuffix(m.nooutScope) + "\n")
+	step := 3
+	if !m.external {
+		b.WriteString("  3. Scale down rook-ceph-operator\n")
+		alreadyDown := ""
+		if m.alreadyDownCount > 0 {
+			alreadyDown = fmt.Sprintf(" (%d already down)", m.alreadyDownCount)
+		}
+		if m.statefulSetCount > 0 {
+			fmt.Fprintf(&b, "  4. Scale down %d deployment(s) and %d statefulset(s) to 0 replicas%s\n",
+				m.deploymentCount-m.statefulSetCount, m.statefulSetCount, alreadyDown)
+		} else {
+			fmt.Fprintf(&b, "  4. Scale down %d deployment(s) to 0 replicas%s\n", m.deploymentCount, alreadyDown)
+		}
+		step = 5
+	}
+	if m.config.Config.Drain.Enabled && !m.deadNode {
+		fmt.Fprintf(&b, "  %d. Drain the node: evict remaining pods, honoring PodDisruptionBudgets\n", step)
+	}
+	if m.forceDeletesPods() {
+		fmt.Fprintf(&b, "  %d. Force-delete the pods stuck in Terminating (zero grace period)\n", step)
+		b.WriteString("\n")
+		b.WriteString(styles.StyleError.Render(maintenance.ForceDeleteWarning))
+	}
+	if m.external {
+		b.WriteString("\n")
+		b.WriteString(styles.StyleWarning.Render(maintenance.ExternalClusterNote))
+	}
 
-	// Down plan table
-	if len(m.downPlan) > 0 {
+	// Down plan table; excluded deployments are listed greyed out
+	if len(m.downPlan) > 0 || len(m.excludedPlan) > 0 {
 		b.WriteString("\n")
 
 		// Create table
-		table := components.NewSimpleTable("Deployment", "Current", "Target")
+		table := components.NewSimpleTable("Kind", "Workload", "Current", "Target")
 		for _, item := range m.downPlan {
 			deployName := fmt.Sprintf("%s/%s", item.Namespace, item.Name)
 			currentStr := fmt.Sprintf("%d", item.CurrentReplicas)
-			targetStr := "0" // All deployments will be scaled to 0
+			targetStr := "0" // All workloads will be scaled to 0
+			if item.Status == planStatusAlreadyDown {
+				targetStr = "already down"
+			}
 
-			table.AddStyledRow(styles.StyleSubtle, deployName, currentStr, targetStr)
+			table.AddStyledRow(styles.StyleSubtle, planKind(item.Kind), deployName, currentStr, targetStr)
+		}
+		for _, item := range m.excludedPlan {
+			deployName := fmt.Sprintf("%s/%s", item.Namespace, item.Name)
+			table.AddStyledRow(styles.StyleExcluded, planKind(item.Kind), deployName, fmt.Sprintf("%d", item.CurrentReplicas), "excluded")
 		}
 		table.SetMaxRows(10)
 		b.WriteString(table.Render())
-	} else {
+	} else if !m.external {
 		b.WriteString("\n")
 		b.WriteString(styles.StyleWarning.Render("No deployments found on this node."))
 	}
 
+	// Topology changes since the node's previous maintenance
+	if m.lastMaintenance.Changed() {
+		var warning strings.Builder
+		warning.WriteString(styles.StyleWarning.Render("⚠ " + m.lastMaintenance.Summary()))
+		for _, name := range m.lastMaintenance.AddedNames() {
+			warning.WriteString("\n")
+			warning.WriteString(styles.StyleWarning.Render("• new: " + name))
+		}
+		for _, name := range m.lastMaintenance.RemovedNames() {
+			warning.WriteString("\n")
+			warning.WriteString(styles.StyleWarning.Render("• gone: " + name))
+		}
+
+		b.WriteString("\n")
+		b.WriteString(styles.StyleBoxWarning.Padding(0, 1).Render(warning.String()))
+	}
+
+	// Capacity and redundancy lost while the node's OSDs are offline
+	if m.capacityImpact != nil {
+		b.WriteString("\n")
+		b.WriteString(m.renderCapacityImpact())
+	}
+
 	// Show maintenance warning if other nodes are in maintenance
 	if m.maintenanceWarning != nil && m.maintenanceWarning.HasWarning() {
 		var warning strings.Builder
@@ -770,54 +1057,133 @@ func (m *DownModel) renderConfirmation() string {
 		b.WriteString(styles.StyleBoxWarning.Padding(0, 1).Render(warning.String()))
 	}
 
-	return b.String()
-}
+	// Show failure domains that make losing this node riskier
+	if len(m.crushRisks) > 0 {
+		var warning strings.Builder
+		warning.WriteString(styles.StyleWarning.Render("⚠ CRUSH failure domains at risk:"))
+		for _, risk := range m.crushRisks {
+			warning.WriteString("\n")
+			warning.WriteString(styles.StyleWarning.Render("• " + risk.String()))
+		}
 
-// renderNothingToDo renders the view when all deployments are already scaled down
-func (m *DownModel) renderNothingToDo() string {
-	var b strings.Builder
+		b.WriteString("\n")
+		b.WriteString(styles.StyleBoxWarning.Padding(0, 1).Render(warning.String()))
+	}
 
-	b.WriteString(styles.StyleSuccess.Render(fmt.Sprintf("%s All deployments are already scaled down", styles.IconCheckmark)))
-	b.WriteString("\n\n")
+	// Autoscalers of managed node groups may remove or replace a cordoned node
+	if m.nodeGroup != nil {
+		b.WriteString("\n")
+		b.WriteString(styles.StyleBoxWarning.Padding(0, 1).Render(
+			styles.StyleWarning.Render("⚠ Managed node group: " + m.nodeGroup.String())))
+	}
 
-	b.WriteString(styles.StyleSubtle.Render("No scaling action needed - the node is already prepared for maintenance."))
+	// Show the node's zone and quorum risks in a stretch cluster
+	if m.stretch != nil {
+		b.WriteString("\n")
+		b.WriteString(m.renderStretch())
+	}
+
+	// Show conflicting CephCluster settings
+	if len(m.rookConflicts) > 0 {
+		var warning strings.Builder
+		warning.WriteString(styles.StyleWarning.Render("⚠ CephCluster settings may conflict with manual maintenance:"))
+		for _, conflict := range m.rookConflicts {
+			warning.WriteString("\n")
+			warning.WriteString(styles.StyleWarning.Render(fmt.Sprintf("• %s: %s=%s", conflict.Cluster, conflict.Setting, conflict.Value)))
+			warning.WriteString("\n  ")
+			warning.WriteString(styles.StyleSubtle.Render(conflict.Reason))
+		}
+
+		b.WriteString("\n")
+		b.WriteString(styles.StyleBoxWarning.Padding(0, 1).Render(warning.String()))
+	}
+
+	// Show balancer/autoscaler activity that would move data while the node is down
+	if len(m.dataMovementRisks) > 0 {
+		var warning strings.Builder
+		warning.WriteString(styles.StyleWarning.Render("⚠ Ceph may move data while the node is down:"))
+		for _, risk := range m.dataMovementRisks {
+			warning.WriteString("\n")
+			warning.WriteString(styles.StyleWarning.Render(fmt.Sprintf("• %s is %s", risk.Module, risk.State)))
+			warning.WriteString("\n  ")
+			warning.WriteString(styles.StyleSubtle.Render(fmt.Sprintf("%s; pause with '%s'", risk.Reason, risk.Suggestion)))
+		}
+
+		b.WriteString("\n")
+		b.WriteString(styles.StyleBoxWarning.Padding(0, 1).Render(warning.String()))
+	}
 
 	return b.String()
 }
 
-// renderProgress renders the progress view during operations
-func (m *DownModel) renderProgress() string {
+// renderStretch renders the stretch mode box; a degraded stretch cluster is
+// shown as an error because pre-flight validation will refuse the down phase
+func (m DownModel) renderStretch() string {
 	var b strings.Builder
 
-	// Elapsed time
-	b.WriteString(styles.StyleSubtle.Render(fmt.Sprintf("Elapsed: %s", m.elapsedTime.Round(time.Second))))
-	b.WriteString("\n\n")
+	blocking := m.stretch.Blocking()
+	box := styles.StyleBoxWarning
+	style := styles.StyleWarning
+	if blocking != "" {
+		box = styles.StyleBoxError
+		style = styles.StyleError
+	}
 
-	// Status list (includes deployment progress inline)
-	b.WriteString(m.statusList.Render())
+	b.WriteString(style.Render("⚠ " + m.stretch.Summary()))
+	if blocking != "" {
+		b.WriteString("\n")
+		b.WriteString(style.Render("• " + blocking))
+	}
+	for _, warning := range m.stretch.Warnings() {
+		b.WriteString("\n")
+		b.WriteString(style.Render("• " + warning))
+	}
 
-	return b.String()
+	return box.Padding(0, 1).Render(b.String())
 }
 
-// renderError renders the error state
-func (m *DownModel) renderError() string {
-	var b strings.Builder
+// renderCapacityImpact renders the capacity going offline and the pools left
+// without redundancy margin
+func (m *DownModel) renderCapacityImpact() string {
+	impact := m.capacityImpact
+	if len(impact.Pools) == 0 {
+		return styles.StyleSubtle.Render(impact.Summary())
+	}
 
-	b.WriteString(styles.StyleError.Render(fmt.Sprintf("%s Error", styles.IconCross)))
-	b.WriteString("\n\n")
+	box, style := styles.StyleBoxWarning, styles.StyleWarning
+	if impact.HasUnavailablePools() {
+		box, style = styles.StyleBoxError, styles.StyleError
+	}
 
-	if m.lastError != nil {
-		b.WriteString(styles.StyleError.Render(m.lastError.Error()))
+	var b strings.Builder
+	b.WriteString(style.Render("⚠ " + impact.Summary()))
+	for _, pool := range impact.Pools {
+		b.WriteString("\n")
+		b.WriteString(style.Render("• " + pool.String()))
 	}
+	return box.Padding(0, 1).Render(b.String())
+}
 
+// renderNothingToDo renders the view when all deployments are already scaled down
+func (m *DownModel) renderNothingToDo() string {
+	var b strings.Builder
+
+	b.WriteString(styles.StyleSuccess.Render(fmt.Sprintf("%s All deployments are already scaled down", styles.IconCheckmark)))
 	b.WriteString("\n\n")
-	b.WriteString(styles.StyleSubtle.Render("The cluster may be in a partial state."))
-	b.WriteString("\n")
-	b.WriteString(styles.StyleSubtle.Render("Review the error and decide how to proceed."))
+
+	b.WriteString(styles.StyleSubtle.Render("No scaling action needed - the node is already prepared for maintenance."))
 
 	return b.String()
 }
 
+// renderDeadNodeBanner labels a dead-node phase as emergency handling
+func (m *DownModel) renderDeadNodeBanner() string {
+	if !m.deadNode {
+		return ""
+	}
+	return styles.StyleError.Render(styles.IconWarning + " " + maintenance.DeadNodeNote)
+}
+
 // renderComplete renders the completion view
 func (m *DownModel) renderComplete() string {
 	var b strings.Builder
@@ -829,6 +1195,12 @@ func (m *DownModel) renderComplete() string {
 	kv := components.NewKeyValueTable()
 	kv.Add("Node", m.config.NodeName)
 	kv.Add("Deployments Scaled", fmt.Sprintf("%d", m.deploymentCount))
+	if m.config.Config.Drain.Enabled && !m.deadNode {
+		kv.Add("Pods Evicted", fmt.Sprintf("%d", m.podsEvicted()))
+	}
+	if m.forceDeletesPods() {
+		kv.Add("Pods Force-Deleted", fmt.Sprintf("%d", len(m.forceDeletedPods)))
+	}
 	kv.Add("Duration", m.elapsedTime.Round(time.Second).String())
 	b.WriteString(kv.Render())
 
@@ -836,25 +1208,10 @@ func (m *DownModel) renderComplete() string {
 	b.WriteString(styles.StyleSubtle.Render("The node is now safe for maintenance."))
 	b.WriteString("\n")
 	b.WriteString(styles.StyleSubtle.Render("Run 'crook up' when maintenance is complete."))
+	if volumes := m.volumes.render(); volumes != "" {
+		b.WriteString("\n\n")
+		b.WriteString(volumes)
+	}
 
 	return b.String()
 }
-
-// renderFooter renders context-sensitive help
-func (m *DownModel) renderFooter() string {
-	m.updateKeyBindings()
-	m.helpModel.SetWidth(m.width)
-	return m.helpModel.View(&m.keyBindings)
-}
-
-// SetSize implements SubModel
-func (m *DownModel) SetSize(width, height int) {
-	m.width = width
-	m.height = height
-}
-
-// FlowKeyMap returns the flow keybindings for status bar help model
-func (m *DownModel) FlowKeyMap() help.KeyMap {
-	m.updateKeyBindings()
-	return &m.keyBindings
-}
@@ -8,7 +8,10 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
 	"github.com/andri/crook/pkg/tui/components"
+	"github.com/andri/crook/test/util/fakeclock"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -27,6 +30,8 @@ func TestDownPhaseState_String(t *testing.T) {
 		{DownStateScalingOperator, "Scaling Operator"},
 		{DownStateDiscoveringDeployments, "Discovering Deployments"},
 		{DownStateScalingDeployments, "Scaling Deployments"},
+		{DownStateDraining, "Draining Node"},
+		{DownStateForceDeleting, "Force-Deleting Pods"},
 		{DownStateComplete, "Complete"},
 		{DownStateError, "Error"},
 		{DownPhaseState(99), "Unknown"},
@@ -55,6 +60,8 @@ func TestDownPhaseState_Description(t *testing.T) {
 		{DownStateScalingOperator, true},
 		{DownStateDiscoveringDeployments, true},
 		{DownStateScalingDeployments, true},
+		{DownStateDraining, true},
+		{DownStateForceDeleting, true},
 		{DownStateComplete, true},
 		{DownStateError, true},
 		{DownPhaseState(99), false},
@@ -376,8 +383,11 @@ func TestDownModel_Update_Tick(t *testing.T) {
 		NodeName: "test-node",
 		Context:  context.Background(),
 	})
+	clk := fakeclock.New(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	model.clock = clk
 	model.operationInProgress = true
-	model.startTime = time.Now().Add(-5 * time.Second)
+	model.startTime = clk.Now()
+	clk.Advance(5 * time.Second)
 
 	msg := DownPhaseTickMsg{}
 	updatedModel, cmd := model.Update(msg)
@@ -387,8 +397,8 @@ func TestDownModel_Update_Tick(t *testing.T) {
 	}
 
 	// Should update elapsed time
-	if m.elapsedTime < 4*time.Second {
-		t.Errorf("elapsedTime should be at least 4s, got %v", m.elapsedTime)
+	if m.elapsedTime != 5*time.Second {
+		t.Errorf("elapsedTime = %v, want 5s", m.elapsedTime)
 	}
 
 	// Should return another tick command
@@ -618,6 +628,284 @@ func TestDownModel_updateStateFromProgress(t *testing.T) {
 	}
 }
 
+func TestDownModel_ProgressSequence(t *testing.T) {
+	model := NewDownModel(DownModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+	model.startExecution()
+
+	model.Update(DownPhaseProgressMsg{Stage: "pre-flight", Sequence: 1, Percent: 0})
+	model.Update(DownPhaseProgressMsg{Stage: "noout", Sequence: 4, Percent: 20})
+
+	if model.droppedUpdates != 2 {
+		t.Errorf("droppedUpdates = %d, want 2", model.droppedUpdates)
+	}
+	if model.percent != 20 {
+		t.Errorf("percent = %d, want 20", model.percent)
+	}
+	if view := model.renderProgress(); !contains(view, " 20%") {
+		t.Errorf("progress view should show the phase percentage, got %q", view)
+	}
+}
+
+func TestDownModel_ScaleETA(t *testing.T) {
+	model := NewDownModel(DownModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+	history := &maintenance.ScaleHistory{}
+	history.Record(maintenance.ScalePhaseDown, "rook-ceph/rook-ceph-osd-0", 40*time.Second)
+	model.Update(DeploymentsDiscoveredMsg{
+		DownPlan: []DownPlanItem{
+			{Namespace: "rook-ceph", Name: "rook-ceph-osd-0", CurrentReplicas: 1, Status: "pending"},
+			{Namespace: "rook-ceph", Name: "rook-ceph-osd-1", CurrentReplicas: 1, Status: "pending"},
+		},
+		ScaleHistory: history,
+	})
+	model.startExecution()
+
+	model.updateStateFromProgress(DownPhaseProgressMsg{Stage: "scale-down", Deployment: "rook-ceph/rook-ceph-osd-0"})
+
+	if !model.etaKnown {
+		t.Fatal("ETA should be known from the scale history")
+	}
+	if model.eta <= 40*time.Second || model.eta > 80*time.Second {
+		t.Errorf("eta = %v, want between 40s and 80s", model.eta)
+	}
+	if view := model.renderProgress(); !contains(view, "ETA: ~") {
+		t.Errorf("progress view should show the ETA, got %q", view)
+	}
+
+	model.updateStateFromProgress(DownPhaseProgressMsg{Stage: "scale-down", Deployment: "rook-ceph/rook-ceph-osd-1"})
+	model.finishDeployments()
+	if model.etaKnown {
+		t.Error("ETA should be hidden once every workload is scaled")
+	}
+}
+
+func TestDownModel_Drain(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Drain.Enabled = true
+	model := NewDownModel(DownModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+		Config:   cfg,
+	})
+	model.downPlan = []DownPlanItem{{Namespace: "rook-ceph", Name: "rook-ceph-osd-0", Status: "pending"}}
+	model.deploymentCount = 1
+	model.startExecution()
+
+	if model.statusList.Count() != 7 {
+		t.Fatalf("statusList should have 7 items with drain enabled, got %d", model.statusList.Count())
+	}
+
+	for _, msg := range []DownPhaseProgressMsg{
+		{Stage: "scale-down", Deployment: "rook-ceph/rook-ceph-osd-0"},
+		{Stage: "drain"},
+		{Stage: "drain-evicting", Pod: "apps/web"},
+		{Stage: "drain-blocked", Pod: "apps/db"},
+		{Stage: "drain-evicted", Pod: "apps/web"},
+	} {
+		model.updateStateFromProgress(msg)
+	}
+
+	if model.state != DownStateDraining {
+		t.Errorf("state = %v, want %v", model.state, DownStateDraining)
+	}
+	if model.downPlan[0].Status != "success" {
+		t.Errorf("deployment status = %q, want success once the drain starts", model.downPlan[0].Status)
+	}
+	want := []DrainPodItem{
+		{Name: "apps/web", Status: k8s.EvictionEvicted},
+		{Name: "apps/db", Status: k8s.EvictionBlocked},
+	}
+	if len(model.drainPods) != len(want) {
+		t.Fatalf("drainPods = %v, want %v", model.drainPods, want)
+	}
+	for i := range want {
+		if model.drainPods[i] != want[i] {
+			t.Errorf("drainPods[%d] = %v, want %v", i, model.drainPods[i], want[i])
+		}
+	}
+
+	item := model.statusList.Get(6)
+	if item.Label != "Drain node (1/2 evicted)" {
+		t.Errorf("drain label = %q", item.Label)
+	}
+	if !contains(item.Details, "apps/db") || !contains(item.Details, "PodDisruptionBudget") {
+		t.Errorf("drain details should show the blocked pod, got %q", item.Details)
+	}
+}
+
+func TestDownModel_View_ConfirmDrain(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Drain.Enabled = true
+	model := NewDownModel(DownModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+		Config:   cfg,
+	})
+	model.state = DownStateConfirm
+
+	view := model.Render()
+
+	if !contains(view, "5. Drain the node") {
+		t.Errorf("View should plan the drain stage, got %q", view)
+	}
+}
+
+func TestDownModel_DeadNodeForceDelete(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Drain.Enabled = true
+	cfg.Drain.ForceDeleteOnDeadNode = true
+	model := NewDownModel(DownModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+		Config:   cfg,
+	})
+	model.Update(DeploymentsDiscoveredMsg{
+		DownPlan: []DownPlanItem{{Namespace: "rook-ceph", Name: "rook-ceph-osd-0", Status: "pending"}},
+		DeadNode: true,
+	})
+
+	view := model.Render()
+	if !contains(view, "5. Force-delete the pods stuck in Terminating") || contains(view, "Drain the node") {
+		t.Errorf("View should plan force-deleting in place of the drain, got %q", view)
+	}
+	if !contains(view, "DANGER") {
+		t.Errorf("View should warn about force-deleting, got %q", view)
+	}
+
+	model.startExecution()
+	if model.statusList.Count() != 7 {
+		t.Fatalf("statusList should have 7 items with force-delete, got %d", model.statusList.Count())
+	}
+	for _, msg := range []DownPhaseProgressMsg{
+		{Stage: "scale-down", Deployment: "rook-ceph/rook-ceph-osd-0"},
+		{Stage: "force-delete"},
+		{Stage: "force-delete", Pod: "apps/web"},
+	} {
+		model.updateStateFromProgress(msg)
+	}
+
+	if model.state != DownStateForceDeleting {
+		t.Errorf("state = %v, want %v", model.state, DownStateForceDeleting)
+	}
+	item := model.statusList.Get(6)
+	if item.Label != "Force-delete stuck pods (1 deleted)" || !contains(item.Details, "apps/web") {
+		t.Errorf("force-delete item = %q, details %q", item.Label, item.Details)
+	}
+}
+
+func TestDownModel_View_ConfirmExcluded(t *testing.T) {
+	model := NewDownModel(DownModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+	model.width = 120
+	model.height = 40
+
+	model.Update(DeploymentsDiscoveredMsg{
+		DownPlan: []DownPlanItem{{Namespace: "rook-ceph", Name: "rook-ceph-osd-0", CurrentReplicas: 1, Status: "pending"}},
+		Excluded: []DownPlanItem{{Namespace: "rook-ceph", Name: "rook-ceph-tools", CurrentReplicas: 1, Status: "excluded"}},
+	})
+
+	view := model.Render()
+
+	if !contains(view, "Scale down 1 deployment(s)") {
+		t.Errorf("excluded deployments should not be counted, got %q", view)
+	}
+	if !contains(view, "rook-ceph/rook-ceph-tools") || !contains(view, "excluded") {
+		t.Errorf("View should list the excluded deployment, got %q", view)
+	}
+}
+
+func TestDownModel_View_ConfirmStatefulSets(t *testing.T) {
+	model := NewDownModel(DownModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+	model.width = 120
+	model.height = 40
+
+	model.Update(DeploymentsDiscoveredMsg{
+		DownPlan: []DownPlanItem{
+			{Kind: k8s.WorkloadKindDeployment, Namespace: "rook-ceph", Name: "rook-ceph-osd-0", CurrentReplicas: 1, Status: "pending"},
+			{Kind: k8s.WorkloadKindStatefulSet, Namespace: "rook-ceph", Name: "metrics-agent", CurrentReplicas: 1, Status: "pending"},
+		},
+	})
+
+	view := model.Render()
+
+	if !contains(view, "Scale down 1 deployment(s) and 1 statefulset(s)") {
+		t.Errorf("View should count deployments and statefulsets separately, got %q", view)
+	}
+	if !contains(view, "Kind") || !contains(view, "StatefulSet") {
+		t.Errorf("View should show the workload kind, got %q", view)
+	}
+}
+
+func TestDownModel_View_ConfirmPartialPlan(t *testing.T) {
+	model := NewDownModel(DownModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+	model.width = 120
+	model.height = 40
+
+	replicas := func(n int32) *int32 { return &n }
+	model.Update(DeploymentsDiscoveredMsg{
+		DownPlan: newDownPlan([]appsv1.Deployment{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "rook-ceph", Name: "rook-ceph-osd-0"}, Spec: appsv1.DeploymentSpec{Replicas: replicas(0)}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "rook-ceph", Name: "rook-ceph-osd-1"}, Spec: appsv1.DeploymentSpec{Replicas: replicas(1)}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "rook-ceph", Name: "rook-ceph-osd-2"}, Spec: appsv1.DeploymentSpec{Replicas: replicas(1)}},
+		}, "pending"),
+	})
+
+	if model.deploymentCount != 2 || model.alreadyDownCount != 1 {
+		t.Errorf("deploymentCount = %d, alreadyDownCount = %d, want 2 and 1", model.deploymentCount, model.alreadyDownCount)
+	}
+	if model.confirmPrompt.Details != "Partial plan: 1 already down, 2 to scale" {
+		t.Errorf("confirm details = %q, want partial plan summary", model.confirmPrompt.Details)
+	}
+
+	view := model.Render()
+	if !contains(view, "Scale down 2 deployment(s) to 0 replicas (1 already down)") {
+		t.Errorf("View should count only the deployments still to scale, got %q", view)
+	}
+	if !contains(view, "already down") {
+		t.Errorf("View should mark the deployment already at 0 replicas, got %q", view)
+	}
+}
+
+func TestDownModel_View_ConfirmLastMaintenanceDiff(t *testing.T) {
+	model := NewDownModel(DownModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+	model.width = 120
+	model.height = 40
+
+	replicas := func(n int32) *int32 { return &n }
+	model.Update(DeploymentsDiscoveredMsg{
+		DownPlan: newDownPlan([]appsv1.Deployment{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "rook-ceph", Name: "rook-ceph-osd-42"}, Spec: appsv1.DeploymentSpec{Replicas: replicas(1)}},
+		}, "pending"),
+		LastMaintenance: &maintenance.PlanDiff{
+			Since:    time.Date(2026, 1, 2, 12, 0, 0, 0, time.Local),
+			Previous: 12,
+			Current:  13,
+			Added:    []string{"rook-ceph/rook-ceph-osd-42"},
+		},
+	})
+
+	view := model.Render()
+	if !contains(view, "Last maintenance (2026-01-02): 12 workloads, now 13") || !contains(view, "• new: rook-ceph-osd-42") {
+		t.Errorf("View should show the changes since the last maintenance, got %q", view)
+	}
+}
+
 func TestDownModel_View_Init(t *testing.T) {
 	model := NewDownModel(DownModelConfig{
 		NodeName: "test-node",
@@ -663,6 +951,99 @@ func TestDownModel_View_Confirm(t *testing.T) {
 	}
 }
 
+func TestDownModel_View_ConfirmExternal(t *testing.T) {
+	model := NewDownModel(DownModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+	model.state = DownStateConfirm
+	model.external = true
+
+	view := model.Render()
+
+	if !contains(view, "External Ceph cluster") {
+		t.Errorf("View should mark the external cluster, got %q", view)
+	}
+	if contains(view, "rook-ceph-operator") || contains(view, "No deployments found") {
+		t.Errorf("View should not plan deployment scaling, got %q", view)
+	}
+}
+
+func TestDownModel_View_ConfirmCapacityImpact(t *testing.T) {
+	model := NewDownModel(DownModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+	model.state = DownStateConfirm
+	model.capacityImpact = &maintenance.CapacityImpact{
+		OSDs:          []string{"osd.0", "osd.1"},
+		RemovedWeight: 2,
+		TotalWeight:   8,
+		Pools:         []maintenance.PoolImpact{{Pool: "replicapool", Size: 2, MinSize: 2, FailureDomain: "host", Remaining: 1}},
+	}
+
+	view := model.Render()
+
+	for _, want := range []string{"2 OSD(s) go offline", "25.0%", "pool replicapool", "below min_size 2"} {
+		if !contains(view, want) {
+			t.Errorf("View should contain %q, got %q", want, view)
+		}
+	}
+}
+
+func TestDownModel_View_ConfirmCrushRisks(t *testing.T) {
+	model := NewDownModel(DownModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+	model.state = DownStateConfirm
+	model.crushRisks = []maintenance.CrushRisk{{DomainType: "rack", Bucket: "rack-1"}}
+
+	view := model.Render()
+
+	if !contains(view, "CRUSH failure domains at risk") || !contains(view, "only host in rack rack-1") {
+		t.Errorf("View should contain the CRUSH risk, got %q", view)
+	}
+}
+
+func TestDownModel_View_ConfirmStretch(t *testing.T) {
+	tests := []struct {
+		name    string
+		stretch *maintenance.StretchInfo
+		want    []string
+	}{
+		{
+			name:    "active",
+			stretch: &maintenance.StretchInfo{ZoneType: "zone", Zone: "a", TiebreakerMon: "e"},
+			want:    []string{"Stretch mode active: node is in zone a, tiebreaker mon e"},
+		},
+		{
+			name:    "degraded",
+			stretch: &maintenance.StretchInfo{ZoneType: "zone", Zone: "a", TiebreakerMon: "e", Degraded: true},
+			want:    []string{"Stretch mode degraded", "a zone is already down"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewDownModel(DownModelConfig{
+				NodeName: "test-node",
+				Context:  context.Background(),
+			})
+			model.state = DownStateConfirm
+			model.stretch = tt.stretch
+
+			view := model.Render()
+
+			for _, want := range tt.want {
+				if !contains(view, want) {
+					t.Errorf("View should contain %q, got %q", want, view)
+				}
+			}
+		})
+	}
+}
+
 func TestDownModel_View_NothingToDo(t *testing.T) {
 	model := NewDownModel(DownModelConfig{
 		NodeName: "test-node",
@@ -29,6 +29,11 @@ const (
 	FlowExitNothingToDo
 )
 
+// Succeeded reports whether the flow left the cluster in its target state.
+func (r FlowExitReason) Succeeded() bool {
+	return r == FlowExitCompleted || r == FlowExitNothingToDo
+}
+
 // DownFlowExitMsg is emitted when a down flow exits in embedded mode.
 type DownFlowExitMsg struct {
 	Reason FlowExitReason
@@ -41,6 +46,12 @@ type UpFlowExitMsg struct {
 	Err    error
 }
 
+// DeploymentFlowExitMsg is emitted when a single deployment flow exits in embedded mode.
+type DeploymentFlowExitMsg struct {
+	Reason FlowExitReason
+	Err    error
+}
+
 func flowExitCmd(exitBehavior FlowExitBehavior, msg tea.Msg) tea.Cmd {
 	if exitBehavior == FlowExitMessage {
 		return func() tea.Msg {
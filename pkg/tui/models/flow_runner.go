@@ -0,0 +1,118 @@
+package models
+
+import (
+	"context"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/internal/metrics"
+)
+
+// flowProgressBuffer is the capacity of a flow's progress channel
+const flowProgressBuffer = 10
+
+// FlowRunner runs a long operation of a guided flow in the background and
+// delivers its progress updates of type T as Bubble Tea messages. It owns the
+// progress channel, the listener command, and the operation's cancel
+// function, so flows only map progress to their own messages.
+//
+// All methods must be called from the model's Update goroutine.
+type FlowRunner[T any] struct {
+	// name identifies the flow in the dropped message metrics
+	name string
+
+	// toMsg converts a progress update into the flow's message
+	toMsg func(T) tea.Msg
+
+	// closedMsg is delivered once the progress channel is closed
+	closedMsg tea.Msg
+
+	// State of the current run
+	ctx      context.Context
+	progress chan T
+	closed   bool
+	cancel   context.CancelFunc
+}
+
+// NewFlowRunner creates a runner whose progress updates are converted with
+// toMsg. closedMsg is delivered when the operation has finished reporting.
+func NewFlowRunner[T any](name string, toMsg func(T) tea.Msg, closedMsg tea.Msg) *FlowRunner[T] {
+	return &FlowRunner[T]{
+		name:      name,
+		toMsg:     toMsg,
+		closedMsg: closedMsg,
+	}
+}
+
+// Start runs op with a cancellable child of ctx and returns the operation
+// batched with the progress listener. op reports progress through report,
+// which never blocks: updates are dropped when the flow falls behind. The
+// message op returns is delivered as the operation's result.
+func (r *FlowRunner[T]) Start(ctx context.Context, op func(ctx context.Context, report func(T)) tea.Msg) tea.Cmd {
+	r.Finish()
+
+	opCtx, cancel := context.WithCancel(ctx)
+	progress := make(chan T, flowProgressBuffer)
+	r.ctx = ctx
+	r.progress = progress
+	r.closed = false
+	r.cancel = cancel
+
+	name := r.name
+	run := func() tea.Msg {
+		report := func(update T) {
+			select {
+			case progress <- update:
+			default:
+				metrics.DroppedMessage(name)
+			}
+		}
+		result := op(opCtx, report)
+		close(progress)
+		return result
+	}
+
+	return tea.Batch(run, r.Listen())
+}
+
+// Listen returns a command that waits for the next progress update. Flows
+// call it again after each update; it returns nil once the channel is closed.
+// The listener also returns when the context given to Start is cancelled, as
+// an aborted operation may never close the channel.
+func (r *FlowRunner[T]) Listen() tea.Cmd {
+	if r.progress == nil || r.closed {
+		return nil
+	}
+	progress := r.progress
+	ctx := r.ctx
+	toMsg := r.toMsg
+	closedMsg := r.closedMsg
+
+	return func() tea.Msg {
+		select {
+		case update, ok := <-progress:
+			if ok {
+				return toMsg(update)
+			}
+		case <-ctx.Done():
+		}
+		return closedMsg
+	}
+}
+
+// MarkClosed records that the closed message was received
+func (r *FlowRunner[T]) MarkClosed() {
+	r.closed = true
+}
+
+// Cancel cancels the running operation, if any
+func (r *FlowRunner[T]) Cancel() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
+// Finish releases the operation's context once its result has arrived
+func (r *FlowRunner[T]) Finish() {
+	r.Cancel()
+	r.cancel = nil
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+type testProgressMsg struct{ step int }
+
+type testClosedMsg struct{}
+
+type testResultMsg struct{ err error }
+
+func newTestRunner() *FlowRunner[int] {
+	return NewFlowRunner("test-progress", func(step int) tea.Msg { return testProgressMsg{step: step} }, testClosedMsg{})
+}
+
+// runBatch returns the commands of the batch returned by Start
+func runBatch(t *testing.T, cmd tea.Cmd) []tea.Cmd {
+	t.Helper()
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		t.Fatalf("Start() should return a batch, got %T", msg)
+	}
+	return batch
+}
+
+func TestFlowRunner_DeliversProgressAndResult(t *testing.T) {
+	runner := newTestRunner()
+	cmds := runBatch(t, runner.Start(context.Background(), func(_ context.Context, report func(int)) tea.Msg {
+		report(1)
+		report(2)
+		return testResultMsg{}
+	}))
+	if len(cmds) != 2 {
+		t.Fatalf("batch has %d commands, want operation and listener", len(cmds))
+	}
+
+	if _, ok := cmds[0]().(testResultMsg); !ok {
+		t.Fatal("operation should return its result message")
+	}
+
+	var steps []int
+	listen := cmds[1]
+	for listen != nil {
+		switch msg := listen().(type) {
+		case testProgressMsg:
+			steps = append(steps, msg.step)
+			listen = runner.Listen()
+		case testClosedMsg:
+			runner.MarkClosed()
+			listen = runner.Listen()
+		default:
+			t.Fatalf("unexpected message %T", msg)
+		}
+	}
+
+	if len(steps) != 2 || steps[0] != 1 || steps[1] != 2 {
+		t.Errorf("progress steps = %v, want [1 2]", steps)
+	}
+	runner.Finish()
+}
+
+func TestFlowRunner_CancelStopsOperation(t *testing.T) {
+	runner := newTestRunner()
+	cmds := runBatch(t, runner.Start(context.Background(), func(ctx context.Context, _ func(int)) tea.Msg {
+		<-ctx.Done()
+		return testResultMsg{err: ctx.Err()}
+	}))
+
+	runner.Cancel()
+
+	msg, ok := cmds[0]().(testResultMsg)
+	if !ok || msg.err == nil {
+		t.Errorf("cancelled operation returned %+v, want context error", msg)
+	}
+	if _, closed := cmds[1]().(testClosedMsg); !closed {
+		t.Error("listener should report the closed channel")
+	}
+}
+
+func TestFlowRunner_DropsProgressWhenFull(t *testing.T) {
+	runner := newTestRunner()
+	cmds := runBatch(t, runner.Start(context.Background(), func(_ context.Context, report func(int)) tea.Msg {
+		for i := range flowProgressBuffer * 2 {
+			report(i) // must not block with no listener running
+		}
+		return testResultMsg{}
+	}))
+
+	if _, ok := cmds[0]().(testResultMsg); !ok {
+		t.Fatal("operation should complete without a listener")
+	}
+	runner.Finish()
+}
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/output"
+	"github.com/andri/crook/pkg/tui/components"
+	"github.com/andri/crook/pkg/tui/keys"
+	"github.com/andri/crook/pkg/tui/styles"
+)
+
+// historyReservedLines is the height taken by everything but the table rows:
+// heading, table header, details and help
+const historyReservedLines = 20
+
+// HistoryModelConfig configures the maintenance history browser
+type HistoryModelConfig struct {
+	// Node is set when the history is limited to one node
+	Node string
+
+	// Entries are the maintenances to browse, newest first
+	Entries []maintenance.HistoryEntry
+}
+
+// HistoryModel browses past maintenances ('crook history --tui'): a table of
+// maintenances with the details of the selected one below
+type HistoryModel struct {
+	node    string
+	entries []maintenance.HistoryEntry
+	keyMap  keys.HistoryKeyMap
+
+	// cursor is the selected entry, offset the first entry shown
+	cursor int
+	offset int
+
+	width  int
+	height int
+}
+
+// NewHistoryModel creates a history browser
+func NewHistoryModel(cfg HistoryModelConfig) *HistoryModel {
+	return &HistoryModel{
+		node:    cfg.Node,
+		entries: cfg.Entries,
+		keyMap:  keys.DefaultHistoryKeyMap(),
+	}
+}
+
+// Init implements tea.Model
+func (m *HistoryModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scrollToCursor()
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keyMap.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keyMap.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			m.scrollToCursor()
+		case key.Matches(msg, m.keyMap.Down):
+			if m.cursor < len(m.entries)-1 {
+				m.cursor++
+			}
+			m.scrollToCursor()
+		}
+	}
+	return m, nil
+}
+
+// visibleRows returns how many entries fit in the table
+func (m *HistoryModel) visibleRows() int {
+	if m.height == 0 {
+		return len(m.entries)
+	}
+	return max(m.height-historyReservedLines, 3)
+}
+
+// scrollToCursor moves the table window so the selected entry is shown
+func (m *HistoryModel) scrollToCursor() {
+	rows := m.visibleRows()
+	switch {
+	case m.cursor < m.offset:
+		m.offset = m.cursor
+	case m.cursor >= m.offset+rows:
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+// View implements tea.Model
+func (m *HistoryModel) View() tea.View {
+	v := tea.NewView(m.Render())
+	v.AltScreen = true
+	return v
+}
+
+// Render returns the browser as a string
+func (m *HistoryModel) Render() string {
+	var b strings.Builder
+
+	title := "Maintenance history"
+	if m.node != "" {
+		title += " of " + m.node
+	}
+	b.WriteString(styles.StyleHeading.Render(title))
+	b.WriteString("\n\n")
+
+	if len(m.entries) == 0 {
+		b.WriteString(styles.StyleSubtle.Render("No maintenance recorded"))
+		b.WriteString("\n\n")
+		b.WriteString(styles.StyleSubtle.Render("q quit"))
+		return b.String()
+	}
+
+	table := components.NewSimpleTable("Node", "Started", "Duration", "Operators", "Ticket", "Outcome")
+	end := min(m.offset+m.visibleRows(), len(m.entries))
+	for i := m.offset; i < end; i++ {
+		entry := m.entries[i]
+		table.Rows = append(table.Rows, components.TableRow{
+			Cells: []string{
+				entry.Node,
+				entry.Started.Local().Format("2006-01-02 15:04"),
+				output.FormatHistoryDuration(entry.Duration()),
+				orDash(entry.Operators()),
+				orDash(entry.Ticket()),
+				entry.Outcome,
+			},
+			Style:       historyOutcomeStyle(entry.Outcome),
+			Highlighted: i == m.cursor,
+		})
+	}
+	b.WriteString(table.Render())
+	if len(m.entries) > end-m.offset {
+		fmt.Fprintf(&b, "\n%s", styles.StyleSubtle.Render(fmt.Sprintf("%d/%d", m.cursor+1, len(m.entries))))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(m.renderDetails(m.entries[m.cursor]))
+	b.WriteString("\n\n")
+	b.WriteString(styles.StyleSubtle.Render("j/k move • q quit"))
+	return b.String()
+}
+
+// renderDetails renders everything recorded about a maintenance
+func (m *HistoryModel) renderDetails(entry maintenance.HistoryEntry) string {
+	details := components.NewKeyValueTable()
+	details.Add("Node", entry.Node)
+	details.Add("Started", entry.Started.Local().Format(time.RFC3339))
+	finished := "-"
+	if entry.Finished != nil {
+		finished = entry.Finished.Local().Format(time.RFC3339)
+	}
+	details.Add("Finished", finished)
+	details.Add("Duration", output.FormatHistoryDuration(entry.Duration()))
+	details.Add("Down by", orDash(entry.DownOperator))
+	details.Add("Up by", orDash(entry.UpOperator))
+	if entry.DownSignOff != nil {
+		details.Add("Down sign-off", entry.DownSignOff.String())
+	}
+	if entry.UpSignOff != nil {
+		details.Add("Up sign-off", entry.UpSignOff.String())
+	}
+	details.Add("Outcome", entry.Outcome)
+	if entry.Error != "" {
+		details.Add("Error", entry.Error)
+	}
+	details.Add("Changes", strconv.Itoa(entry.Changes))
+	return details.Render()
+}
+
+// historyOutcomeStyle colors a history row by its outcome
+func historyOutcomeStyle(outcome string) lipgloss.Style {
+	switch outcome {
+	case maintenance.OutcomeFailed:
+		return styles.StyleError
+	case maintenance.OutcomeInProgress:
+		return styles.StyleWarning
+	default:
+		return styles.StyleNormal
+	}
+}
+
+// orDash returns "-" for an empty value
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/pkg/maintenance"
+)
+
+func testHistoryModel() *HistoryModel {
+	started := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	finished := started.Add(time.Hour)
+	return NewHistoryModel(HistoryModelConfig{
+		Entries: []maintenance.HistoryEntry{
+			{Node: "worker-2", Started: started.Add(24 * time.Hour), DownOperator: "alice", Outcome: maintenance.OutcomeInProgress},
+			{
+				Node:         "worker-1",
+				Started:      started,
+				Finished:     &finished,
+				DownOperator: "alice",
+				UpOperator:   "bob",
+				UpSignOff:    &maintenance.SignOff{Ticket: "CHG-7", Reason: "kernel update"},
+				Outcome:      maintenance.OutcomeSucceeded,
+			},
+		},
+	})
+}
+
+func TestHistoryModel_Render(t *testing.T) {
+	model := testHistoryModel()
+	view := model.Render()
+
+	for _, want := range []string{"Maintenance history", "worker-1", "worker-2", "in progress", "Down by"} {
+		if !contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if contains(view, "kernel update") {
+		t.Error("details should show the selected maintenance only")
+	}
+}
+
+func TestHistoryModel_MoveCursor(t *testing.T) {
+	model := testHistoryModel()
+
+	model.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	if model.cursor != 1 {
+		t.Fatalf("cursor = %d, want 1", model.cursor)
+	}
+	if view := model.Render(); !contains(view, "CHG-7: kernel update") {
+		t.Errorf("details should show the selected maintenance:\n%s", view)
+	}
+
+	// The cursor stops at the last entry
+	model.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	if model.cursor != 1 {
+		t.Errorf("cursor = %d, want 1", model.cursor)
+	}
+
+	model.Update(tea.KeyPressMsg{Code: 'k', Text: "k"})
+	if model.cursor != 0 {
+		t.Errorf("cursor = %d, want 0", model.cursor)
+	}
+}
+
+func TestHistoryModel_RenderEmpty(t *testing.T) {
+	model := NewHistoryModel(HistoryModelConfig{Node: "worker-1"})
+	view := model.Render()
+	if !contains(view, "Maintenance history of worker-1") || !contains(view, "No maintenance recorded") {
+		t.Errorf("unexpected empty view:\n%s", view)
+	}
+}
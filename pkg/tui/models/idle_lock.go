@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/andri/crook/internal/clock"
+)
+
+// idleLock locks the mutating keys once no input arrived for a while, so a
+// keypress on a forgotten terminal cannot start maintenance by accident
+type idleLock struct {
+	// after is the idle period; zero never locks
+	after time.Duration
+	clock clock.Clock
+
+	// lastInput is when the last key was pressed
+	lastInput time.Time
+	locked    bool
+}
+
+// newIdleLock creates a lock that engages after the given idle period
+func newIdleLock(after time.Duration, clk clock.Clock) idleLock {
+	return idleLock{after: after, clock: clk, lastInput: clk.Now()}
+}
+
+// refresh engages the lock once the idle period has passed and reports
+// whether it holds
+func (l *idleLock) refresh() bool {
+	if l.after > 0 && !l.locked && l.clock.Since(l.lastInput) >= l.after {
+		l.locked = true
+	}
+	return l.locked
+}
+
+// input records a key press. A press after the idle period engages the lock
+// before the key is handled, so it cannot mutate anything.
+func (l *idleLock) input() {
+	l.refresh()
+	l.lastInput = l.clock.Now()
+}
+
+// unlock releases the lock and restarts the idle period
+func (l *idleLock) unlock() {
+	l.locked = false
+	l.lastInput = l.clock.Now()
+}
@@ -11,6 +11,8 @@ import (
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/andri/crook/internal/clock"
+	"github.com/andri/crook/internal/telemetry"
 	"github.com/andri/crook/pkg/config"
 	"github.com/andri/crook/pkg/k8s"
 	"github.com/andri/crook/pkg/monitoring"
@@ -80,18 +82,38 @@ func (t LsTab) String() string {
 
 // LsModelConfig holds configuration for the ls model
 type LsModelConfig struct {
-	// NodeFilter optionally filters resources to a specific node
+	// NodeFilter optionally scopes the view to a specific node: the Nodes
+	// pane shows its details, the other panes only its resources, and the
+	// maintenance pane is focused on it
 	NodeFilter string
 
+	// ReadOnly hides the maintenance pane and disables the keys that start
+	// maintenance, so the view cannot change the cluster
+	ReadOnly bool
+
+	// Selector narrows the nodes, deployments and pods listed. Optional.
+	Selector k8s.ListSelector
+
 	// Config is the application configuration
 	Config config.Config
 
+	// KubeContext names the kubeconfig context for the terminal title; empty
+	// with in-cluster credentials
+	KubeContext string
+
+	// State restores the setup of a previous session. Optional.
+	State *UIState
+
 	// Client is the Kubernetes client
 	Client *k8s.Client
 
 	// Context for cancellation
 	Context context.Context
 
+	// Clock drives the monitor and the time-based display. If nil, the real
+	// clock is used.
+	Clock clock.Clock
+
 	// ShowTabs specifies which tabs to display (nil = all)
 	// Deprecated: the new multi-pane layout always shows all 3 panes
 	ShowTabs []LsTab
@@ -116,6 +138,7 @@ type LsModel struct {
 	// Views
 	header              *components.ClusterHeader
 	nodesView           *views.NodesView
+	nodeDetail          *views.NodeDetailView // set when scoped to one node
 	deploymentsPodsView *views.DeploymentsPodsView
 	osdsView            *views.OSDsView
 
@@ -128,17 +151,56 @@ type LsModel struct {
 	// Cluster state (for OSD view noout flag)
 	nooutSet bool
 
+	// summary holds the aggregate counts of the footer under the panes
+	summary views.ClusterSummary
+
+	// relations links the rows of the panes for the cross-pane highlight
+	relations relationIndex
+
+	// followSelection filters the Deployments/Pods and OSDs panes to the
+	// node selected in the Nodes pane
+	followSelection bool
+
 	// Error state
 	lastError error
 
+	// reauthenticating is set while the monitor waits for refreshed credentials
+	reauthenticating bool
+
+	// nextRetry is set while failing monitor sources back off
+	nextRetry time.Time
+
 	// Maintenance pane state
 	maintenanceFlow     sizedModel
+	flowPane            LsPane // Pane the flow was started from; its keys drive the flow
 	pendingReselectNode string
 	maintenancePane     *components.Pane
+	queue               *MaintenanceQueue
+
+	// Cluster log state; it replaces the Deployments and OSDs panes while open.
+	// clusterLogGeneration drops polls started before the log was last toggled.
+	clusterLog           *views.ClusterLogView
+	clusterLogPane       *components.Pane
+	clusterLogOpen       bool
+	clusterLogGeneration int
+
+	// Monitor for background updates: the view's subscription, and the
+	// embedded maintenance flow's own
+	monitor     *monitoring.LsMonitor
+	updatesCh   <-chan *monitoring.LsMonitorUpdate
+	flowUpdates <-chan *monitoring.LsMonitorUpdate
+
+	// cancel cancels the model context shared by the monitor and flows
+	cancel context.CancelFunc
 
-	// Monitor for background updates
-	monitor   *monitoring.LsMonitor
-	updatesCh <-chan *monitoring.LsMonitorUpdate
+	// abort recognizes the "abort everything" key gesture
+	abort abortDetector
+
+	// idle locks the mutating keys after a period without input
+	idle idleLock
+
+	// layout is the chosen pane layout, one of the config.Layout values
+	layout string
 
 	// Legacy fields for backwards compatibility
 	tabBar          *components.TabBar
@@ -152,6 +214,9 @@ type LsModel struct {
 	flowHelpModel help.Model // Separate help model with maintenance styling for flow keys
 }
 
+// sizedModel is the contract of a maintenance flow embedded in the ls
+// maintenance pane. Flows use the same Bubble Tea v2 types as the ls model;
+// the extra methods let the pane size, render and describe them.
 type sizedModel interface {
 	tea.Model
 	SetSize(width, height int)
@@ -165,6 +230,12 @@ type flowInfoModel interface {
 	PhaseName() string
 }
 
+// flowTitleModel is implemented by flows that are not about a whole node and
+// title the maintenance pane themselves.
+type flowTitleModel interface {
+	FlowTitle() string
+}
+
 const (
 	paneContentHorizontalPadding = 4 // 2 borders + 2 padding (see components.Pane)
 	paneContentVerticalPadding   = 3 // pane borders plus typical view chrome
@@ -176,12 +247,24 @@ func innerViewSize(paneWidth, paneHeight int) (int, int) {
 	return width, height
 }
 
+// wideLayoutMinWidth is the terminal width from which the auto layout
+// places the Deployments/Pods pane beside Nodes and OSDs
+const wideLayoutMinWidth = 180
+
 type lsLayout struct {
+	// wide places Nodes and OSDs in the left column and Deployments/Pods
+	// in the right one
+	wide bool
+
 	nodesWidth        int
 	maintenanceWidth  int
+	deploymentsWidth  int
+	osdsWidth         int
 	nodesHeight       int
 	deploymentsHeight int
 	osdsHeight        int
+	clusterLogWidth   int
+	clusterLogHeight  int
 
 	nodesInnerWidth        int
 	nodesInnerHeight       int
@@ -225,6 +308,28 @@ type LsMonitorUpdateMsg struct {
 // LsMonitorClosedMsg signals the monitor channel was closed
 type LsMonitorClosedMsg struct{}
 
+// lsClockInterval is how often time-based display (last updated, row
+// highlights) is refreshed; data changes arrive on the monitor channel
+const lsClockInterval = time.Second
+
+// LsClockTickMsg advances time-based display between monitor updates
+type LsClockTickMsg struct{}
+
+// clusterLogLines is the number of cluster log entries fetched per poll
+const clusterLogLines = 100
+
+// ClusterLogMsg carries the result of a cluster log poll
+type ClusterLogMsg struct {
+	Generation int
+	Entries    []k8s.ClusterLogEntry
+	Err        error
+}
+
+// ClusterLogTickMsg triggers the next cluster log poll
+type ClusterLogTickMsg struct {
+	Generation int
+}
+
 // NewLsModel creates a new ls model
 func NewLsModel(cfg LsModelConfig) *LsModel {
 	// Create panes
@@ -234,6 +339,8 @@ func NewLsModel(cfg LsModelConfig) *LsModel {
 		components.NewPane(components.PaneConfig{Title: "OSDs", ShortcutKey: "3"}),
 	}
 	maintenancePane := components.NewPane(components.PaneConfig{Title: "Node Maintenance", ShortcutKey: ""})
+	clusterLogPane := components.NewPane(components.PaneConfig{Title: "Cluster Log", ShortcutKey: "L"})
+	clusterLogPane.SetActive(true)
 
 	// Set first pane as active
 	panes[0].SetActive(true)
@@ -243,9 +350,13 @@ func NewLsModel(cfg LsModelConfig) *LsModel {
 	deploymentsPodsView := views.NewDeploymentsPodsView()
 	osdsView := views.NewOSDsView()
 
-	// Apply node filter to pods view if specified
+	// Scope the view to one node if specified
+	var nodeDetail *views.NodeDetailView
 	if cfg.NodeFilter != "" {
 		deploymentsPodsView.SetNodeFilter(cfg.NodeFilter)
+		nodeDetail = views.NewNodeDetailView(cfg.NodeFilter)
+		panes[LsPaneNodes].SetTitle("Node")
+		maintenancePane.SetActive(true)
 	}
 
 	// Create legacy tab bar for backwards compatibility with tests
@@ -275,14 +386,33 @@ func NewLsModel(cfg LsModelConfig) *LsModel {
 	fh.Styles.FullKey = fh.Styles.FullKey.Foreground(styles.ColorMaintenance).Bold(true)
 	fh.Styles.FullDesc = fh.Styles.FullDesc.Foreground(styles.ColorMaintenance)
 
-	return &LsModel{
+	// The monitor and embedded flows derive from this context so quitting
+	// cancels everything they started
+	ctx, cancel := withAbort(cfg.Context)
+	cfg.Context = ctx
+	cfg.Clock = clock.OrReal(cfg.Clock)
+	keyMap := keys.DefaultLsKeyMap()
+	// A view scoped to one node already follows it
+	keyMap.Follow.SetEnabled(cfg.NodeFilter == "")
+	header := components.NewClusterHeader()
+	header.SetClock(cfg.Clock)
+
+	m := &LsModel{
 		config:              cfg,
+		cancel:              cancel,
+		abort:               newAbortDetector(keyMap.Abort),
+		idle:                newIdleLock(time.Duration(cfg.Config.UI.IdleLockSeconds)*time.Second, cfg.Clock),
+		layout:              cfg.Config.UI.Layout,
 		activePane:          LsPaneNodes,
 		panes:               panes,
 		cursor:              0,
-		header:              components.NewClusterHeader(),
+		header:              header,
 		maintenancePane:     maintenancePane,
+		queue:               NewMaintenanceQueue(),
+		clusterLog:          views.NewClusterLogView(),
+		clusterLogPane:      clusterLogPane,
 		nodesView:           nodesView,
+		nodeDetail:          nodeDetail,
 		deploymentsPodsView: deploymentsPodsView,
 		osdsView:            osdsView,
 		// Legacy fields
@@ -291,45 +421,61 @@ func NewLsModel(cfg LsModelConfig) *LsModel {
 		deploymentsView: deploymentsPodsView.GetDeploymentsView(),
 		podsView:        deploymentsPodsView.GetPodsView(),
 		// Keybindings and help
-		keyMap:        keys.DefaultLsKeyMap(),
+		keyMap:        keyMap,
 		helpModel:     h,
 		flowHelpModel: fh,
 	}
+	if cfg.State != nil {
+		m.restoreUIState(*cfg.State)
+	}
+	return m
 }
 
 // Init implements tea.Model
 func (m *LsModel) Init() tea.Cmd {
-	return m.startMonitorCmd()
+	return tea.Batch(m.startMonitorCmd(), clockTickCmd())
+}
+
+// clockTickCmd returns a command that sends the next LsClockTickMsg
+func clockTickCmd() tea.Cmd {
+	return tea.Tick(lsClockInterval, func(time.Time) tea.Msg {
+		return LsClockTickMsg{}
+	})
 }
 
 // startMonitorCmd starts the LsMonitor in a goroutine and returns when ready
 func (m *LsModel) startMonitorCmd() tea.Cmd {
 	return func() tea.Msg {
-		// Helper to ensure non-zero duration with default fallback
-		getInterval := func(ms int, defaultMS int) time.Duration {
-			if ms <= 0 {
-				ms = defaultMS
-			}
-			return time.Duration(ms) * time.Millisecond
-		}
-
 		cfg := &monitoring.LsMonitorConfig{
 			Context:             m.config.Context,
 			Client:              m.config.Client,
 			Namespace:           m.config.Config.Namespace,
 			NodeFilter:          m.config.NodeFilter,
+			Selector:            m.config.Selector,
 			K8sRefreshInterval:  getInterval(m.config.Config.UI.K8sRefreshMS, config.DefaultK8sRefreshMS),
 			CephRefreshInterval: getInterval(m.config.Config.UI.CephRefreshMS, config.DefaultCephRefreshMS),
+			Clock:               m.config.Clock,
 		}
 		monitor, err := monitoring.NewLsMonitor(cfg)
 		if err != nil {
 			return LsMonitorStartFailedMsg{Err: err}
 		}
-		updatesCh := monitor.Start()
+		// The view only renders the latest state, so it may skip updates
+		updatesCh := monitor.Subscribe(monitoring.SubscribeOptions{Name: "ls", Policy: monitoring.KeepLatest})
+		monitor.Start()
 		return LsMonitorStartedMsg{Monitor: monitor, UpdatesCh: updatesCh}
 	}
 }
 
+// getInterval converts a configured interval in milliseconds to a duration,
+// falling back to defaultMS when it is not set
+func getInterval(ms int, defaultMS int) time.Duration {
+	if ms <= 0 {
+		ms = defaultMS
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
 // waitForMonitorUpdateCmd returns a command that waits for the next monitor update
 func (m *LsModel) waitForMonitorUpdateCmd() tea.Cmd {
 	ch := m.updatesCh
@@ -354,9 +500,17 @@ func (m *LsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Err != nil {
 			m.lastError = msg.Err
 		}
-		cmds = append(cmds, m.closeMaintenanceFlow())
+		m.queue.End(m.flowNodeName(), false, msg.Reason.Succeeded())
+		cmds = append(cmds, m.closeMaintenanceFlow(), m.continueQueue())
 		return m, tea.Batch(cmds...)
 	case UpFlowExitMsg:
+		if msg.Err != nil {
+			m.lastError = msg.Err
+		}
+		m.queue.End(m.flowNodeName(), true, msg.Reason.Succeeded())
+		cmds = append(cmds, m.closeMaintenanceFlow(), m.continueQueue())
+		return m, tea.Batch(cmds...)
+	case DeploymentFlowExitMsg:
 		if msg.Err != nil {
 			m.lastError = msg.Err
 		}
@@ -364,24 +518,19 @@ func (m *LsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(cmds...)
 	}
 
-	if m.maintenanceFlow != nil {
-		if cmd, handled := m.handleFlowMessage(msg); handled {
-			return m, cmd
-		} else if cmd != nil {
-			cmds = append(cmds, cmd)
-		}
+	// Every other message has exactly one owner; see routing.go
+	if keyMsg, isKey := msg.(tea.KeyMsg); isKey {
+		return m, m.routeKey(keyMsg)
+	}
+	if m.messageOwner(msg) == focusFlow {
+		return m, m.updateFlow(msg)
 	}
 
 	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		cmd := m.handleKeyPress(msg)
-		if cmd != nil {
-			cmds = append(cmds, cmd)
-		}
-
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		telemetry.RecordTerminal(msg.Width, msg.Height)
 		m.header.SetWidth(msg.Width)
 		m.tabBar.SetWidth(msg.Width)
 		m.updateViewSizes()
@@ -431,12 +580,41 @@ func (m *LsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case LsMonitorClosedMsg:
 		// Monitor channel was closed, nothing more to do
 		m.updatesCh = nil
+
+	case LsClockTickMsg:
+		// Unchanged header refreshes are not sent; keep "last updated" current
+		if m.monitor != nil {
+			m.header.SetLastUpdate(m.monitor.LastRefresh())
+		}
+		// Lock without waiting for the next key so the status bar shows it
+		m.idle.refresh()
+		cmds = append(cmds, clockTickCmd())
+
+	case ClusterLogMsg:
+		if m.clusterLogOpen && msg.Generation == m.clusterLogGeneration {
+			if msg.Err != nil {
+				m.clusterLog.SetError(msg.Err)
+			} else {
+				m.clusterLog.SetEntries(msg.Entries)
+			}
+			m.updateClusterLogBadge()
+			cmds = append(cmds, m.clusterLogTickCmd())
+		}
+
+	case ClusterLogTickMsg:
+		if m.clusterLogOpen && msg.Generation == m.clusterLogGeneration {
+			cmds = append(cmds, m.fetchClusterLogCmd())
+		}
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
 func (m *LsModel) computeLayout() lsLayout {
+	if m.isWide() {
+		return m.computeWideLayout()
+	}
+
 	activeHeight, inactiveHeight := m.paneHeights()
 
 	nodesHeight := inactiveHeight
@@ -462,9 +640,65 @@ func (m *LsModel) computeLayout() lsLayout {
 	return lsLayout{
 		nodesWidth:        nodesWidth,
 		maintenanceWidth:  maintenanceWidth,
+		deploymentsWidth:  m.width,
+		osdsWidth:         m.width,
 		nodesHeight:       nodesHeight,
 		deploymentsHeight: deploymentsHeight,
 		osdsHeight:        osdsHeight,
+		// The cluster log takes the space of the Deployments and OSDs panes
+		clusterLogWidth:  m.width,
+		clusterLogHeight: deploymentsHeight + osdsHeight,
+
+		nodesInnerWidth:        nodesInnerWidth,
+		nodesInnerHeight:       nodesInnerHeight,
+		deploymentsInnerWidth:  deploymentsInnerWidth,
+		deploymentsInnerHeight: deploymentsInnerHeight,
+		osdsInnerWidth:         osdsInnerWidth,
+		osdsInnerHeight:        osdsInnerHeight,
+		maintenanceInnerWidth:  maintenanceInnerWidth,
+		maintenanceInnerHeight: maintenanceInnerHeight,
+
+		maintenanceActive: !m.config.ReadOnly && (m.maintenanceFlow != nil || m.HasNodeFilter()),
+	}
+}
+
+// computeWideLayout places the Nodes and Maintenance row above the OSDs pane
+// in the left column and the Deployments/Pods pane at full height on the right
+func (m *LsModel) computeWideLayout() lsLayout {
+	available := max(m.height-m.chromeHeight(), 8)
+
+	// The active pane of the left column gets two thirds of its height
+	nodesHeight := available / 2
+	switch m.activePane {
+	case LsPaneNodes:
+		nodesHeight = available * 2 / 3
+	case LsPaneOSDs:
+		nodesHeight = available / 3
+	case LsPaneDeployments:
+	}
+	nodesHeight = max(nodesHeight, 4)
+	osdsHeight := max(available-nodesHeight, 4)
+
+	leftWidth, rightWidth := m.wideColumnWidths()
+	nodesWidth, maintenanceWidth := m.topRowWidths()
+
+	nodesInnerWidth, nodesInnerHeight := innerViewSize(nodesWidth, nodesHeight)
+	deploymentsInnerWidth, deploymentsInnerHeight := innerViewSize(rightWidth, available)
+	osdsInnerWidth, osdsInnerHeight := innerViewSize(leftWidth, osdsHeight)
+	maintenanceInnerWidth, maintenanceInnerHeight := innerViewSize(maintenanceWidth, nodesHeight)
+
+	return lsLayout{
+		wide:              true,
+		nodesWidth:        nodesWidth,
+		maintenanceWidth:  maintenanceWidth,
+		deploymentsWidth:  rightWidth,
+		osdsWidth:         leftWidth,
+		nodesHeight:       nodesHeight,
+		deploymentsHeight: available,
+		osdsHeight:        osdsHeight,
+		// The cluster log takes the place of the Deployments/Pods pane
+		clusterLogWidth:  rightWidth,
+		clusterLogHeight: available,
 
 		nodesInnerWidth:        nodesInnerWidth,
 		nodesInnerHeight:       nodesInnerHeight,
@@ -475,24 +709,55 @@ func (m *LsModel) computeLayout() lsLayout {
 		maintenanceInnerWidth:  maintenanceInnerWidth,
 		maintenanceInnerHeight: maintenanceInnerHeight,
 
-		maintenanceActive: m.maintenanceFlow != nil,
+		maintenanceActive: !m.config.ReadOnly && (m.maintenanceFlow != nil || m.HasNodeFilter()),
 	}
 }
 
+// isWide reports whether the panes use the wide layout: chosen with the
+// layout key or ui.layout, or picked by the auto layout on wide terminals
+func (m *LsModel) isWide() bool {
+	switch m.layout {
+	case config.LayoutWide:
+		return true
+	case config.LayoutStacked:
+		return false
+	default:
+		return m.width >= wideLayoutMinWidth
+	}
+}
+
+// wideColumnWidths splits the width between the left column (Nodes,
+// Maintenance and OSDs) and the right one (Deployments/Pods)
+func (m *LsModel) wideColumnWidths() (int, int) {
+	const gap = 1
+	left := max((m.width-gap)*11/20, 1)
+	return left, max(m.width-gap-left, 1)
+}
+
 func (m *LsModel) applyLayout(layout lsLayout) {
 	m.panes[LsPaneNodes].SetSize(layout.nodesWidth, layout.nodesHeight)
 	m.maintenancePane.SetSize(layout.maintenanceWidth, layout.nodesHeight)
 	m.maintenancePane.SetActive(layout.maintenanceActive)
 
-	m.panes[LsPaneDeployments].SetSize(m.width, layout.deploymentsHeight)
-	m.panes[LsPaneOSDs].SetSize(m.width, layout.osdsHeight)
+	m.panes[LsPaneDeployments].SetSize(layout.deploymentsWidth, layout.deploymentsHeight)
+	m.panes[LsPaneOSDs].SetSize(layout.osdsWidth, layout.osdsHeight)
+
+	m.clusterLogPane.SetSize(layout.clusterLogWidth, layout.clusterLogHeight)
+	m.clusterLog.SetSize(innerViewSize(layout.clusterLogWidth, layout.clusterLogHeight))
 
 	m.nodesView.SetSize(layout.nodesInnerWidth, layout.nodesInnerHeight)
+	if m.nodeDetail != nil {
+		m.nodeDetail.SetSize(layout.nodesInnerWidth, layout.nodesInnerHeight)
+	}
 	m.deploymentsPodsView.SetSize(layout.deploymentsInnerWidth, layout.deploymentsInnerHeight)
 	m.osdsView.SetSize(layout.osdsInnerWidth, layout.osdsInnerHeight)
 
 	if m.maintenanceFlow != nil {
-		m.maintenanceFlow.SetSize(layout.maintenanceInnerWidth, layout.maintenanceInnerHeight)
+		height := layout.maintenanceInnerHeight
+		if m.queue.Len() > 0 {
+			height = max(height-1, 1) // queue summary line
+		}
+		m.maintenanceFlow.SetSize(layout.maintenanceInnerWidth, height)
 	}
 }
 
@@ -502,11 +767,18 @@ func (m *LsModel) updateViewSizes() {
 	m.applyLayout(layout)
 }
 
-// paneHeights calculates the active/inactive pane heights based on layout chrome.
-func (m *LsModel) paneHeights() (int, int) {
+// chromeHeight is the height of everything but the panes: the header, the
+// summary footer and the status bar
+func (m *LsModel) chromeHeight() int {
 	headerHeight := 4
+	summaryHeight := 1
 	statusBarHeight := 2
-	availableHeight := m.height - headerHeight - statusBarHeight
+	return headerHeight + summaryHeight + statusBarHeight
+}
+
+// paneHeights calculates the active/inactive pane heights based on layout chrome.
+func (m *LsModel) paneHeights() (int, int) {
+	availableHeight := m.height - m.chromeHeight()
 
 	// Height distribution: active pane gets 50%, inactive get 25% each.
 	activeHeight := availableHeight / 2
@@ -530,29 +802,50 @@ func (m *LsModel) updateFromMonitor(update *monitoring.LsMonitorUpdate) {
 		m.header.SetData(update.Header)
 		m.nooutSet = update.Header.NooutSet
 		m.osdsView.SetNooutFlag(update.Header.NooutSet)
+		m.summary.Flags = update.Header.Flags
 	}
 
 	// Update views with data
 	if update.Nodes != nil {
+		m.summary.SetNodes(update.Nodes)
 		m.nodesView.SetNodes(update.Nodes)
+		if m.nodeDetail != nil {
+			m.nodeDetail.SetNode(m.nodesView.GetSelectedNode())
+		}
 	}
 	if update.Deployments != nil {
+		m.summary.SetDeployments(update.Deployments)
 		m.deploymentsPodsView.SetDeployments(update.Deployments)
 	}
 	if update.OSDs != nil {
+		m.summary.SetOSDs(update.OSDs)
 		m.osdsView.SetOSDs(update.OSDs)
 	}
 	if update.Pods != nil {
+		m.summary.SetPods(update.Pods)
 		m.deploymentsPodsView.SetPods(update.Pods)
 	}
 
+	if update.Deployments != nil || update.OSDs != nil {
+		m.relations.update(update.Deployments, update.OSDs)
+	}
+
+	// Highlight rows that changed since the previous refresh
+	m.nodesView.MarkChanged(update.Changes.Nodes.Changed())
+	m.deploymentsPodsView.MarkDeploymentsChanged(update.Changes.Deployments.Changed())
+	m.deploymentsPodsView.MarkPodsChanged(update.Changes.Pods.Changed())
+	m.osdsView.MarkChanged(update.Changes.OSDs.Changed())
+
 	// Update counts and badges
 	m.updateAllCounts()
 
 	// Store any error
 	m.lastError = update.Error
+	m.reauthenticating = update.Reauthenticating
+	m.nextRetry = update.NextRetry
 
 	m.reselectNodeIfNeeded()
+	m.syncFollowFilter()
 }
 
 // updateAllCounts updates all pane counts and badges
@@ -583,9 +876,16 @@ func (m *LsModel) updateAllCounts() {
 	m.updateBadge(2, m.osdCount)
 }
 
-// updatePaneBadge updates a pane badge with count information
+// updatePaneBadge updates a pane badge with count information, naming the
+// followed node on the panes filtered to it
 func (m *LsModel) updatePaneBadge(pane LsPane, count int) {
-	m.panes[pane].SetBadge(fmt.Sprintf("%d", count))
+	badge := fmt.Sprintf("%d", count)
+	if pane != LsPaneNodes && m.followSelection {
+		if node := m.osdsView.GetNodeFilter(); node != "" {
+			badge += " · " + node
+		}
+	}
+	m.panes[pane].SetBadge(badge)
 }
 
 // updateBadge updates a tab badge with count information (legacy)
@@ -610,9 +910,18 @@ func (m *LsModel) handleKeyPress(msg tea.KeyMsg) tea.Cmd {
 	if m.handleDeploymentsToggleKey(msg) {
 		return nil
 	}
+	if m.handleFollowKey(msg) {
+		return nil
+	}
+	if m.handleLayoutKey(msg) {
+		return nil
+	}
 	if m.handleCursorKey(msg) {
 		return nil
 	}
+	if cmd, ok := m.handleQueueKey(msg); ok {
+		return cmd
+	}
 	if cmd, ok := m.handleActionKey(msg); ok {
 		return cmd
 	}
@@ -626,64 +935,32 @@ func (m *LsModel) updateKeyBindings() {
 	m.keyMap.SetContext(keys.LsPane(m.activePane), showingPods)
 	// Disable action keys when maintenance flow is active
 	m.keyMap.SetFlowActive(m.maintenanceFlow != nil)
+	m.keyMap.SetLocked(m.idle.locked)
+	m.keyMap.SetReadOnly(m.config.ReadOnly)
+	m.keyMap.SetWide(m.isWide())
 }
 
-// handleFlowMessage handles messages when a maintenance flow is active.
-// Returns (cmd, true) if the message was handled and Update should return early.
-// Returns (nil, false) if the message should continue to normal processing.
-func (m *LsModel) handleFlowMessage(msg tea.Msg) (tea.Cmd, bool) {
-	// Handle navigation keys in LS even when flow is active
-	if keyMsg, isKey := msg.(tea.KeyMsg); isKey {
-		if m.keyMap.IsNavigationKey(keyMsg) {
-			// Navigation keys (Tab, 1-3, [ ], j/k/up/down) handled by LS
-			m.updateKeyBindings()
-			if m.handlePaneNavKey(keyMsg) {
-				return nil, true
-			}
-			if _, handled := m.handlePaneSwitchKey(keyMsg); handled {
-				return nil, true
-			}
-			if m.handleDeploymentsToggleKey(keyMsg) {
-				return nil, true
-			}
-			if m.handleCursorKey(keyMsg) {
-				return nil, true
-			}
-			return nil, true
-		}
-
-		// Non-navigation keys go to flow only when Nodes pane is selected
-		// This ensures flow keys (y/n, Ctrl+C, r, q) only work when focused on nodes
-		if m.activePane == LsPaneNodes {
-			updatedFlow, cmd := m.maintenanceFlow.Update(msg)
-			if flow, isFlow := updatedFlow.(sizedModel); isFlow {
-				m.maintenanceFlow = flow
-			}
-			return cmd, true
-		}
-		// Flow keys ignored when not on Nodes pane - just consume the key
-		return nil, true
-	}
-
-	// Non-key messages go to flow but don't cause early return
-	updatedFlow, cmd := m.maintenanceFlow.Update(msg)
-	if flow, isFlow := updatedFlow.(sizedModel); isFlow {
-		m.maintenanceFlow = flow
-	}
-	if cmd != nil {
-		return cmd, false // Don't return early, let other handlers process too
+func (m *LsModel) handleQuitKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if key.Matches(msg, m.keyMap.Quit) {
+		return m.shutdown(), true
 	}
 	return nil, false
 }
 
-func (m *LsModel) handleQuitKey(msg tea.KeyMsg) (tea.Cmd, bool) {
-	if key.Matches(msg, m.keyMap.Quit) {
-		if m.monitor != nil {
-			m.monitor.Stop()
-		}
-		return tea.Quit, true
+// shutdown cancels all work started by the model (the maintenance flow,
+// monitor polling and anything else derived from the model context), waits
+// for the monitor to stop, and quits the program.
+func (m *LsModel) shutdown() tea.Cmd {
+	if flow, ok := m.maintenanceFlow.(abortableFlow); ok {
+		flow.Abort()
 	}
-	return nil, false
+	if m.cancel != nil {
+		m.cancel()
+	}
+	if m.monitor != nil {
+		m.monitor.Stop()
+	}
+	return tea.Quit
 }
 
 func (m *LsModel) handlePaneNavKey(msg tea.KeyMsg) bool {
@@ -745,6 +1022,52 @@ func (m *LsModel) handleDeploymentsToggleKey(msg tea.KeyMsg) bool {
 	}
 }
 
+// handleLayoutKey switches between the stacked and the wide layout
+func (m *LsModel) handleLayoutKey(msg tea.KeyMsg) bool {
+	if !key.Matches(msg, m.keyMap.Layout) {
+		return false
+	}
+	if m.isWide() {
+		m.layout = config.LayoutStacked
+	} else {
+		m.layout = config.LayoutWide
+	}
+	m.updateViewSizes()
+	return true
+}
+
+// handleFollowKey turns follow mode on and off
+func (m *LsModel) handleFollowKey(msg tea.KeyMsg) bool {
+	if !key.Matches(msg, m.keyMap.Follow) {
+		return false
+	}
+	m.followSelection = !m.followSelection
+	m.keyMap.SetFollowing(m.followSelection)
+	m.syncFollowFilter()
+	return true
+}
+
+// syncFollowFilter filters the Deployments/Pods and OSDs panes to the
+// selected node while following it, and clears the filter otherwise. A view
+// scoped to one node keeps its filter.
+func (m *LsModel) syncFollowFilter() {
+	if m.HasNodeFilter() {
+		return
+	}
+	filter := ""
+	if m.followSelection {
+		if node := m.nodesView.GetSelectedNode(); node != nil {
+			filter = node.Name
+		}
+	}
+	if filter == m.osdsView.GetNodeFilter() && filter == m.deploymentsPodsView.GetNodeFilter() {
+		return
+	}
+	m.deploymentsPodsView.SetNodeFilter(filter)
+	m.osdsView.SetNodeFilter(filter)
+	m.updateAllCounts()
+}
+
 func (m *LsModel) handleCursorKey(msg tea.KeyMsg) bool {
 	switch {
 	case key.Matches(msg, m.keyMap.Down):
@@ -763,6 +1086,10 @@ func (m *LsModel) handleActionKey(msg tea.KeyMsg) (tea.Cmd, bool) {
 	case key.Matches(msg, m.keyMap.Refresh):
 		tab := m.activeTab
 		return func() tea.Msg { return LsRefreshMsg{Tab: tab} }, true
+	case key.Matches(msg, m.keyMap.DeployDown):
+		return m.openDeploymentFlow(false), true
+	case key.Matches(msg, m.keyMap.DeployUp):
+		return m.openDeploymentFlow(true), true
 	case key.Matches(msg, m.keyMap.NodeDown):
 		if m.activePane != LsPaneNodes {
 			return nil, true
@@ -786,6 +1113,114 @@ func (m *LsModel) handleActionKey(msg tea.KeyMsg) (tea.Cmd, bool) {
 	}
 }
 
+// handleQueueKey adds, removes and reorders the selected node in the
+// maintenance queue, or starts the queue
+func (m *LsModel) handleQueueKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if key.Matches(msg, m.keyMap.QueueStart) {
+		return m.startQueue(), true
+	}
+
+	delta := 0
+	switch {
+	case key.Matches(msg, m.keyMap.QueueToggle):
+	case key.Matches(msg, m.keyMap.QueueUp):
+		delta = -1
+	case key.Matches(msg, m.keyMap.QueueDown):
+		delta = 1
+	default:
+		return nil, false
+	}
+
+	node := m.nodesView.GetSelectedNode()
+	if node == nil {
+		return nil, true
+	}
+	if delta == 0 {
+		m.queue.Toggle(node.Name)
+	} else {
+		m.queue.Move(node.Name, delta)
+	}
+	// The queue summary takes a line from an active flow
+	m.updateViewSizes()
+	return nil, true
+}
+
+// startQueue runs the queue and takes its next node down unless another
+// flow occupies the maintenance pane; the queue continues when it exits
+func (m *LsModel) startQueue() tea.Cmd {
+	node, ok := m.queue.Start()
+	if !ok || m.maintenanceFlow != nil {
+		return nil
+	}
+	return m.openMaintenanceFlow(node, false)
+}
+
+// continueQueue takes the next queued node down after a flow exits, as long
+// as the queue is running
+func (m *LsModel) continueQueue() tea.Cmd {
+	if !m.queue.Running() {
+		return nil
+	}
+	return m.startQueue()
+}
+
+// handleClusterLogKey opens and closes the cluster log and cycles its
+// severity filter
+func (m *LsModel) handleClusterLogKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keyMap.ClusterLog):
+		if m.clusterLogOpen {
+			m.setClusterLogOpen(false)
+			return nil, true
+		}
+		m.setClusterLogOpen(true)
+		return m.fetchClusterLogCmd(), true
+	case key.Matches(msg, m.keyMap.LogLevel):
+		m.clusterLog.CycleSeverity()
+		m.updateClusterLogBadge()
+		return nil, true
+	default:
+		return nil, false
+	}
+}
+
+// setClusterLogOpen shows or hides the cluster log. Polls in flight when it
+// is toggled are discarded.
+func (m *LsModel) setClusterLogOpen(open bool) {
+	m.clusterLogOpen = open
+	m.clusterLogGeneration++
+	m.keyMap.SetClusterLogOpen(open)
+}
+
+// updateClusterLogBadge shows the number of entries passing the filter
+func (m *LsModel) updateClusterLogBadge() {
+	m.clusterLogPane.SetBadge(fmt.Sprintf("%d", m.clusterLog.Count()))
+}
+
+// fetchClusterLogCmd polls the cluster log once
+func (m *LsModel) fetchClusterLogCmd() tea.Cmd {
+	client := m.config.Client
+	if client == nil {
+		return nil
+	}
+	ctx := m.config.Context
+	namespace := m.config.Config.Namespace
+	generation := m.clusterLogGeneration
+	return func() tea.Msg {
+		entries, err := client.GetClusterLog(ctx, namespace, clusterLogLines)
+		return ClusterLogMsg{Generation: generation, Entries: entries, Err: err}
+	}
+}
+
+// clusterLogTickCmd schedules the next cluster log poll at the Ceph refresh interval
+func (m *LsModel) clusterLogTickCmd() tea.Cmd {
+	generation := m.clusterLogGeneration
+	interval := getInterval(m.config.Config.UI.CephRefreshMS, config.DefaultCephRefreshMS)
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return ClusterLogTickMsg{Generation: generation}
+	})
+}
+
 // nextPane cycles to the next pane
 func (m *LsModel) nextPane() {
 	newPane := (m.activePane + 1) % 3
@@ -877,6 +1312,7 @@ func (m *LsModel) updateActiveViewCursor(delta int) {
 		newCursor := m.nodesView.GetCursor() + delta
 		if newCursor >= 0 && newCursor < m.nodesView.Count() {
 			m.nodesView.SetCursor(newCursor)
+			m.syncFollowFilter()
 		}
 	case LsPaneDeployments:
 		newCursor := m.deploymentsPodsView.GetCursor() + delta
@@ -895,6 +1331,9 @@ func (m *LsModel) updateActiveViewCursor(delta int) {
 func (m *LsModel) View() tea.View {
 	v := tea.NewView(m.Render())
 	v.AltScreen = true
+	if m.config.Config.UI.TerminalTitle {
+		v.WindowTitle = m.terminalTitle()
+	}
 	return v
 }
 
@@ -910,6 +1349,10 @@ func (m *LsModel) Render() string {
 	b.WriteString(m.renderAllPanes())
 	b.WriteString("\n")
 
+	// Aggregate counts, unaffected by scrolling and filters
+	b.WriteString(m.summary.Render(m.width))
+	b.WriteString("\n")
+
 	// Status bar
 	b.WriteString(m.renderStatusBar())
 
@@ -933,11 +1376,36 @@ func (m *LsModel) renderAllPanes() string {
 	layout := m.computeLayout()
 	m.applyLayout(layout)
 
-	nodes := m.panes[LsPaneNodes].View(m.nodesView.Render())
-	maintenance := m.maintenancePane.View(m.maintenanceContent())
-	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, nodes, " ", maintenance))
+	m.highlightRelated()
+
+	nodesContent := m.nodesView.Render()
+	if m.nodeDetail != nil {
+		nodesContent = m.nodeDetail.Render()
+	}
+	topRow := m.panes[LsPaneNodes].View(nodesContent)
+	if !m.config.ReadOnly {
+		maintenance := m.maintenancePane.View(m.maintenanceContent())
+		topRow = lipgloss.JoinHorizontal(lipgloss.Top, topRow, " ", maintenance)
+	}
+
+	if layout.wide {
+		left := lipgloss.JoinVertical(lipgloss.Left, topRow, m.panes[LsPaneOSDs].View(m.osdsView.Render()))
+		right := m.panes[LsPaneDeployments].View(m.deploymentsPodsView.Render())
+		if m.clusterLogOpen {
+			right = m.clusterLogPane.View(m.clusterLog.Render())
+		}
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, left, " ", right))
+		return b.String()
+	}
+
+	b.WriteString(topRow)
 	b.WriteString("\n")
 
+	if m.clusterLogOpen {
+		b.WriteString(m.clusterLogPane.View(m.clusterLog.Render()))
+		return b.String()
+	}
+
 	b.WriteString(m.panes[LsPaneDeployments].View(m.deploymentsPodsView.Render()))
 	b.WriteString("\n")
 
@@ -946,12 +1414,49 @@ func (m *LsModel) renderAllPanes() string {
 	return b.String()
 }
 
+// highlightRelated highlights the rows of the other panes related to the
+// selection in the active pane
+func (m *LsModel) highlightRelated() {
+	var related relatedKeys
+	switch m.activePane {
+	case LsPaneNodes:
+		if node := m.nodesView.GetSelectedNode(); node != nil {
+			related = m.relations.forNode(monitoring.NodeKey(*node))
+		}
+	case LsPaneDeployments:
+		if m.deploymentsPodsView.IsShowingPods() {
+			if pod := m.deploymentsPodsView.GetSelectedPod(); pod != nil {
+				related.nodes = nonEmpty(pod.NodeName)
+			}
+		} else if dep := m.deploymentsPodsView.GetSelectedDeployment(); dep != nil {
+			related = m.relations.forDeployment(monitoring.DeploymentKey(*dep))
+		}
+	case LsPaneOSDs:
+		if osd := m.osdsView.GetSelectedOSD(); osd != nil {
+			related = m.relations.forOSD(monitoring.OSDKey(*osd))
+		}
+	}
+
+	m.nodesView.SetRelated(related.nodes)
+	m.deploymentsPodsView.SetRelatedDeployments(related.deployments)
+	m.osdsView.SetRelated(related.osds)
+}
+
 func (m *LsModel) openMaintenanceFlow(nodeName string, isUp bool) tea.Cmd {
-	if m.maintenanceFlow != nil {
+	if m.maintenanceFlow != nil || m.config.ReadOnly {
 		return nil
 	}
 
 	m.pendingReselectNode = nodeName
+	m.flowPane = LsPaneNodes
+
+	// Share what the monitor already knows so the flow skips duplicate
+	// queries, and let the flow follow every later change to its node
+	var snapshot *monitoring.LsMonitorUpdate
+	if m.monitor != nil {
+		snapshot = m.monitor.GetLatest()
+		m.flowUpdates = m.monitor.Subscribe(monitoring.SubscribeOptions{Name: "flow", Policy: monitoring.Lossless})
+	}
 
 	var flow sizedModel
 	if isUp {
@@ -962,6 +1467,8 @@ func (m *LsModel) openMaintenanceFlow(nodeName string, isUp bool) tea.Cmd {
 			Context:      m.config.Context,
 			ExitBehavior: FlowExitMessage,
 			Embedded:     true,
+			Snapshot:     snapshot,
+			Updates:      m.flowUpdates,
 		})
 	} else {
 		flow = NewDownModel(DownModelConfig{
@@ -971,9 +1478,41 @@ func (m *LsModel) openMaintenanceFlow(nodeName string, isUp bool) tea.Cmd {
 			Context:      m.config.Context,
 			ExitBehavior: FlowExitMessage,
 			Embedded:     true,
+			Snapshot:     snapshot,
+			Updates:      m.flowUpdates,
 		})
 	}
 
+	m.maintenanceFlow = flow
+	m.queue.Begin(nodeName, isUp)
+	m.updateViewSizes()
+
+	return flow.Init()
+}
+
+// openDeploymentFlow scales the deployment selected in the Deployments pane
+// down, or restores it, without taking its node down
+func (m *LsModel) openDeploymentFlow(restore bool) tea.Cmd {
+	if m.maintenanceFlow != nil || m.config.ReadOnly || m.activePane != LsPaneDeployments || m.deploymentsPodsView.IsShowingPods() {
+		return nil
+	}
+	deployment := m.deploymentsPodsView.GetSelectedDeployment()
+	if deployment == nil {
+		return nil
+	}
+
+	flow := NewDeploymentModel(DeploymentModelConfig{
+		Namespace:    deployment.Namespace,
+		Name:         deployment.Name,
+		Restore:      restore,
+		Config:       m.config.Config,
+		Client:       m.config.Client,
+		Context:      m.config.Context,
+		ExitBehavior: FlowExitMessage,
+		Embedded:     true,
+	})
+
+	m.flowPane = LsPaneDeployments
 	m.maintenanceFlow = flow
 	m.updateViewSizes()
 
@@ -981,6 +1520,14 @@ func (m *LsModel) openMaintenanceFlow(nodeName string, isUp bool) tea.Cmd {
 }
 
 func (m *LsModel) closeMaintenanceFlow() tea.Cmd {
+	// Release the exited flow's context
+	if flow, ok := m.maintenanceFlow.(abortableFlow); ok {
+		flow.Abort()
+	}
+	if m.flowUpdates != nil {
+		m.monitor.Unsubscribe(m.flowUpdates)
+		m.flowUpdates = nil
+	}
 	m.maintenanceFlow = nil
 	m.updateViewSizes()
 	return func() tea.Msg {
@@ -988,6 +1535,14 @@ func (m *LsModel) closeMaintenanceFlow() tea.Cmd {
 	}
 }
 
+// flowNodeName returns the node the active flow works on, if any
+func (m *LsModel) flowNodeName() string {
+	if flow, ok := m.maintenanceFlow.(flowInfoModel); ok {
+		return flow.NodeName()
+	}
+	return ""
+}
+
 func (m *LsModel) reselectNodeIfNeeded() {
 	if m.pendingReselectNode == "" {
 		return
@@ -1001,10 +1556,15 @@ func (m *LsModel) reselectNodeIfNeeded() {
 func (m *LsModel) maintenanceContent() string {
 	if m.maintenanceFlow != nil {
 		// Update pane title with node name and phase
-		if flow, ok := m.maintenanceFlow.(flowInfoModel); ok {
+		if flow, ok := m.maintenanceFlow.(flowTitleModel); ok {
+			m.maintenancePane.SetTitle(flow.FlowTitle())
+		} else if flow, ok := m.maintenanceFlow.(flowInfoModel); ok {
 			title := fmt.Sprintf("Node Maintenance [%s]: %s", strings.ToUpper(flow.PhaseName()), flow.NodeName())
 			m.maintenancePane.SetTitle(title)
 		}
+		if m.queue.Len() > 0 {
+			return styles.StyleSubtle.Render(m.queue.Summary()) + "\n" + m.maintenanceFlow.Render()
+		}
 		return m.maintenanceFlow.Render()
 	}
 
@@ -1012,20 +1572,34 @@ func (m *LsModel) maintenanceContent() string {
 	m.maintenancePane.SetTitle("Node Maintenance")
 
 	var b strings.Builder
-	b.WriteString(styles.StyleSubtle.Render("Select a node and start maintenance:"))
+	if m.HasNodeFilter() {
+		b.WriteString(styles.StyleSubtle.Render(fmt.Sprintf("Start maintenance on %s:", m.config.NodeFilter)))
+	} else {
+		b.WriteString(styles.StyleSubtle.Render("Select a node and start maintenance:"))
+	}
 	b.WriteString("\n\n")
 	b.WriteString(styles.StyleStatus.Render("d"))
 	b.WriteString(styles.StyleSubtle.Render(" → down"))
 	b.WriteString("\n")
 	b.WriteString(styles.StyleStatus.Render("u"))
 	b.WriteString(styles.StyleSubtle.Render(" → up"))
+	b.WriteString("\n")
+	b.WriteString(styles.StyleSubtle.Render("On a deployment, d/u bounces just that deployment."))
+	b.WriteString("\n")
+	b.WriteString(styles.StyleStatus.Render("m"))
+	b.WriteString(styles.StyleSubtle.Render(" → queue, K/J reorder, s starts"))
 
-	if node := m.nodesView.GetSelectedNode(); node != nil {
+	if node := m.nodesView.GetSelectedNode(); node != nil && !m.HasNodeFilter() {
 		b.WriteString("\n\n")
 		b.WriteString(styles.StyleStatus.Render("Selected: "))
 		b.WriteString(node.Name)
 	}
 
+	if m.queue.Len() > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(m.queue.Render())
+	}
+
 	return b.String()
 }
 
@@ -1036,15 +1610,28 @@ func (m *LsModel) topRowWidths() (int, int) {
 	const minMaintenance = 35
 
 	total := m.width
+	if m.isWide() {
+		total, _ = m.wideColumnWidths()
+	}
 	if total <= 0 {
 		return 0, 0
 	}
+	// Read-only views have no maintenance pane; the nodes take the whole row
+	if m.config.ReadOnly {
+		return total, 0
+	}
 	if total <= gap+1 {
 		return total, 1
 	}
 
 	available := total - gap
-	maintenance := max(minMaintenance, total/3)
+	// Scoped to one node, the maintenance pane is the focus and the node's
+	// details need less room than the nodes table
+	share := 3
+	if m.HasNodeFilter() {
+		share = 2
+	}
+	maintenance := max(minMaintenance, total/share)
 	maintenance = min(maintenance, available-1)
 	nodes := available - maintenance
 
@@ -1061,6 +1648,19 @@ func (m *LsModel) topRowWidths() (int, int) {
 	return nodes, maintenance
 }
 
+// selectorLabel names the selectors narrowing the lists, e.g.
+// "selector: app=rook-ceph-osd", or "" when everything is listed
+func (m *LsModel) selectorLabel() string {
+	var parts []string
+	if m.config.Selector.Label != "" {
+		parts = append(parts, "selector: "+m.config.Selector.Label)
+	}
+	if m.config.Selector.Field != "" {
+		parts = append(parts, "field-selector: "+m.config.Selector.Field)
+	}
+	return strings.Join(parts, " ")
+}
+
 // renderStatusBar renders the bottom status bar with help hints
 func (m *LsModel) renderStatusBar() string {
 	m.updateKeyBindings()
@@ -1084,6 +1684,23 @@ func (m *LsModel) renderStatusBar() string {
 
 	status := strings.Join(parts, " ")
 
+	if selector := m.selectorLabel(); selector != "" {
+		status = styles.StyleSubtle.Render(selector) + "  " + status
+	}
+
+	if m.config.ReadOnly {
+		status = styles.StyleSubtle.Render("read-only") + "  " + status
+	} else if m.idle.locked {
+		status = styles.StyleWarning.Render("locked after idle: C-u to unlock") + "  " + status
+	}
+
+	if m.reauthenticating {
+		status = styles.StyleWarning.Render("re-authenticating…") + "  " + status
+	} else if !m.nextRetry.IsZero() {
+		retryIn := max(m.config.Clock.Until(m.nextRetry).Round(time.Second), 0)
+		status = styles.StyleWarning.Render(fmt.Sprintf("degraded: retrying in %s", retryIn)) + "  " + status
+	}
+
 	if m.lastError != nil {
 		errText := styles.StyleError.Render("error: " + format.SanitizeForDisplay(m.lastError.Error()))
 		return errText + "  " + status
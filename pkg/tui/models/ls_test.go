@@ -2,8 +2,12 @@ package models
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"charm.land/bubbles/v2/help"
 	tea "charm.land/bubbletea/v2"
@@ -11,6 +15,8 @@ import (
 	"github.com/andri/crook/pkg/k8s"
 	"github.com/andri/crook/pkg/monitoring"
 	"github.com/andri/crook/pkg/tui/components"
+	"github.com/andri/crook/test/util/fakeclock"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type stubSizedModel struct {
@@ -145,6 +151,103 @@ func TestNewLsModel_WithNodeFilter(t *testing.T) {
 	}
 }
 
+func TestLsModel_NodeDeepDive(t *testing.T) {
+	model := NewLsModel(LsModelConfig{
+		NodeFilter: "worker-1",
+		Context:    context.Background(),
+	})
+	_, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	_, _ = model.Update(LsMonitorUpdateMsg{Update: &monitoring.LsMonitorUpdate{
+		Nodes: []k8s.NodeInfo{{Name: "worker-1", Status: "Ready", KubeletVersion: "v1.31.2"}},
+	}})
+
+	if !model.maintenancePane.IsActive() {
+		t.Error("maintenance pane should be focused when scoped to a node")
+	}
+	if title := model.panes[LsPaneNodes].GetTitle(); title != "Node" {
+		t.Errorf("nodes pane title = %q, want Node", title)
+	}
+	nodesWidth, maintenanceWidth := model.topRowWidths()
+	if maintenanceWidth < nodesWidth-1 {
+		t.Errorf("maintenance pane width = %d, want about half (nodes %d)", maintenanceWidth, nodesWidth)
+	}
+
+	view := model.Render()
+	for _, want := range []string{"Kubelet", "v1.31.2", "Start maintenance on worker-1"} {
+		if !contains(view, want) {
+			t.Errorf("Render() missing %q", want)
+		}
+	}
+
+	// d acts on the scoped node without selecting it first
+	if model.nodesView.GetSelectedNode() == nil {
+		t.Error("the scoped node should be selected")
+	}
+}
+
+func TestLsModel_FollowSelection(t *testing.T) {
+	model := NewLsModel(LsModelConfig{Context: context.Background()})
+	model.updateFromMonitor(&monitoring.LsMonitorUpdate{
+		Nodes: []k8s.NodeInfo{{Name: "worker-1"}, {Name: "worker-2"}},
+		Deployments: []k8s.DeploymentInfo{
+			{Name: "rook-ceph-osd-0", NodeName: "worker-1", Type: "osd"},
+			{Name: "rook-ceph-osd-1", NodeName: "worker-2", Type: "osd"},
+			{Name: "rook-ceph-mon-a", NodeName: "worker-2", Type: "mon"},
+		},
+		OSDs: []k8s.OSDInfo{{ID: 0, Hostname: "worker-1"}, {ID: 1, Hostname: "worker-2"}},
+	})
+	press := func(k string) {
+		_, _ = model.Update(tea.KeyPressMsg{Code: rune(k[0]), Text: k})
+	}
+
+	press("F")
+	if got := model.deploymentsPodsView.DeploymentsCount(); got != 1 {
+		t.Errorf("deployments following worker-1 = %d, want 1", got)
+	}
+	if got := model.osdsView.Count(); got != 1 {
+		t.Errorf("OSDs following worker-1 = %d, want 1", got)
+	}
+	if badge := model.panes[LsPaneOSDs].GetBadge(); badge != "1 · worker-1" {
+		t.Errorf("OSDs badge = %q, want the followed node", badge)
+	}
+
+	// Moving the selection moves the filter
+	press("j")
+	if got := model.deploymentsPodsView.DeploymentsCount(); got != 2 {
+		t.Errorf("deployments following worker-2 = %d, want 2", got)
+	}
+	if osd := model.osdsView.GetSelectedOSD(); osd == nil || osd.Hostname != "worker-2" {
+		t.Errorf("OSDs pane shows %+v, want worker-2's OSD", osd)
+	}
+
+	press("F")
+	if got := model.deploymentsPodsView.DeploymentsCount(); got != 3 {
+		t.Errorf("deployments after unfollow = %d, want 3", got)
+	}
+	if badge := model.panes[LsPaneOSDs].GetBadge(); badge != "2" {
+		t.Errorf("OSDs badge after unfollow = %q, want 2", badge)
+	}
+}
+
+func TestLsModel_SummaryFooter(t *testing.T) {
+	model := NewLsModel(LsModelConfig{Context: context.Background()})
+	_, _ = model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	model.updateFromMonitor(&monitoring.LsMonitorUpdate{
+		Header: &components.ClusterHeaderData{Health: "HEALTH_WARN", NooutSet: true, Flags: []string{"noout"}},
+		Nodes:  []k8s.NodeInfo{{Name: "worker-1", Status: "Ready"}, {Name: "worker-2", Status: "Ready"}},
+		OSDs:   []k8s.OSDInfo{{ID: 0, Hostname: "worker-1", Status: "up", InOut: "in"}, {ID: 1, Hostname: "worker-2", Status: "up", InOut: "in"}},
+	})
+
+	// Following worker-1 filters the OSDs pane, not the footer
+	_, _ = model.Update(tea.KeyPressMsg{Code: 'F', Text: "F"})
+	view := ansi.Strip(model.Render())
+	for _, want := range []string{"Nodes 2", "OSDs 2", "Flags noout"} {
+		if !contains(view, want) {
+			t.Errorf("Render() footer missing %q", want)
+		}
+	}
+}
+
 func TestLsModel_Init(t *testing.T) {
 	model := NewLsModel(LsModelConfig{
 		Context: context.Background(),
@@ -157,6 +260,17 @@ func TestLsModel_Init(t *testing.T) {
 	}
 }
 
+func TestLsModel_ClockTickReschedules(t *testing.T) {
+	model := NewLsModel(LsModelConfig{
+		Context: context.Background(),
+	})
+
+	_, cmd := model.Update(LsClockTickMsg{})
+	if cmd == nil {
+		t.Fatal("clock tick should schedule the next tick")
+	}
+}
+
 func TestLsModel_Update_WindowSize(t *testing.T) {
 	model := NewLsModel(LsModelConfig{
 		Context: context.Background(),
@@ -555,6 +669,157 @@ func TestLsModel_handleKeyPress_MaintenanceFlowOpens(t *testing.T) {
 	}
 }
 
+func TestLsModel_WideLayout(t *testing.T) {
+	model := NewLsModel(LsModelConfig{Context: context.Background()})
+	_, _ = model.Update(tea.WindowSizeMsg{Width: 200, Height: 50})
+	model.nodesView.SetNodes([]k8s.NodeInfo{{Name: "node-a"}})
+
+	// paneRow returns the first line of the pane area, holding the pane titles
+	paneRow := func() string {
+		return strings.Split(ansi.Strip(model.renderAllPanes()), "\n")[0]
+	}
+
+	// The auto layout goes wide on a wide terminal
+	if !model.isWide() {
+		t.Fatal("expected the auto layout to be wide at 200 columns")
+	}
+	row := paneRow()
+	for _, want := range []string{"Nodes", "Deployments"} {
+		if !strings.Contains(row, want) {
+			t.Errorf("wide layout's first row %q is missing %q", row, want)
+		}
+	}
+	lines := strings.Split(model.renderAllPanes(), "\n")
+	if want := 50 - model.chromeHeight(); len(lines) != want {
+		t.Errorf("wide layout has %d lines, want %d", len(lines), want)
+	}
+	for i, line := range lines {
+		if w := ansi.StringWidth(line); w > 200 {
+			t.Fatalf("line %d is %d columns wide, want at most 200", i, w)
+		}
+	}
+
+	// The layout key switches back to stacked panes
+	model.Update(tea.KeyPressMsg{Code: 'w', Text: "w"})
+	if model.isWide() {
+		t.Fatal("expected 'w' to switch to the stacked layout")
+	}
+	if row := paneRow(); strings.Contains(row, "Deployments") {
+		t.Errorf("stacked layout's first row %q should not hold Deployments", row)
+	}
+
+	// An explicit wide layout holds on narrower terminals too
+	model.Update(tea.KeyPressMsg{Code: 'w', Text: "w"})
+	_, _ = model.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+	if !model.isWide() {
+		t.Error("expected the chosen wide layout to hold at 140 columns")
+	}
+}
+
+func TestLsModel_SelectorLabel(t *testing.T) {
+	tests := []struct {
+		selector k8s.ListSelector
+		want     string
+	}{
+		{k8s.ListSelector{}, ""},
+		{k8s.ListSelector{Label: "app=rook-ceph-osd"}, "selector: app=rook-ceph-osd"},
+		{k8s.ListSelector{Label: "zone=a", Field: "spec.unschedulable=false"}, "selector: zone=a field-selector: spec.unschedulable=false"},
+	}
+
+	for _, tt := range tests {
+		model := NewLsModel(LsModelConfig{Context: context.Background(), Selector: tt.selector})
+		if got := model.selectorLabel(); got != tt.want {
+			t.Errorf("selectorLabel() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestLsModel_ReadOnly(t *testing.T) {
+	model := NewLsModel(LsModelConfig{
+		Context:  context.Background(),
+		ReadOnly: true,
+	})
+	_, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	nodes := []k8s.NodeInfo{{Name: "node-a"}, {Name: "node-b"}}
+	model.nodesView.SetNodes(nodes)
+	model.nodeCount = len(nodes)
+
+	if nodesWidth, maintenanceWidth := model.topRowWidths(); nodesWidth != 120 || maintenanceWidth != 0 {
+		t.Errorf("topRowWidths() = (%d,%d), want (120,0)", nodesWidth, maintenanceWidth)
+	}
+
+	for _, k := range []tea.KeyPressMsg{{Code: 'd', Text: "d"}, {Code: 'u', Text: "u"}, {Code: 'm', Text: "m"}} {
+		model.Update(k)
+	}
+	if model.maintenanceFlow != nil {
+		t.Fatal("expected no maintenance flow in a read-only view")
+	}
+	if got := queueNodes(model.queue); len(got) != 0 {
+		t.Errorf("queue = %v, want it empty in a read-only view", got)
+	}
+
+	view := model.Render()
+	if contains(view, "Maintenance") || contains(view, "down node") {
+		t.Error("read-only view should hide the maintenance pane and its keys")
+	}
+	if !contains(view, "read-only") {
+		t.Error("read-only view should say so in the status bar")
+	}
+}
+
+func TestLsModel_IdleLock(t *testing.T) {
+	clk := fakeclock.New(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	cfg := config.DefaultConfig()
+	cfg.UI.IdleLockSeconds = 60
+	model := NewLsModel(LsModelConfig{
+		Context: context.Background(),
+		Config:  cfg,
+		Clock:   clk,
+	})
+	model.width = 120
+	model.height = 40
+	model.setActivePane(LsPaneNodes)
+	nodes := []k8s.NodeInfo{{Name: "node-a"}, {Name: "node-b"}}
+	model.nodesView.SetNodes(nodes)
+	model.nodeCount = len(nodes)
+
+	// The first key after the idle period locks instead of acting
+	clk.Advance(time.Minute)
+	model.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	if model.maintenanceFlow != nil {
+		t.Fatal("expected 'd' to be ignored after the idle period")
+	}
+	if !contains(model.Render(), "locked after idle") {
+		t.Error("View should show the idle lock")
+	}
+
+	// Navigation keeps working while locked
+	model.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	if got := model.nodesView.GetCursor(); got != 1 {
+		t.Errorf("cursor = %d, want 1 after navigating while locked", got)
+	}
+	model.Update(tea.KeyPressMsg{Code: 'm', Text: "m"})
+	if got := queueNodes(model.queue); len(got) != 0 {
+		t.Errorf("queue = %v, want it unchanged while locked", got)
+	}
+
+	model.Update(tea.KeyPressMsg{Code: 'u', Mod: tea.ModCtrl})
+	if contains(model.Render(), "locked after idle") {
+		t.Error("View should clear the idle lock after unlocking")
+	}
+	model.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	if model.maintenanceFlow == nil {
+		t.Fatal("expected 'd' to open the flow after unlocking")
+	}
+
+	// The clock tick locks without waiting for a key
+	clk.Advance(time.Minute)
+	model.Update(LsClockTickMsg{})
+	if !model.idle.locked {
+		t.Error("expected the clock tick to engage the idle lock")
+	}
+}
+
 func TestLsModel_handleKeyPress_MaintenanceFlowIgnoredOutsideNodesPane(t *testing.T) {
 	model := NewLsModel(LsModelConfig{
 		Context: context.Background(),
@@ -574,6 +839,67 @@ func TestLsModel_handleKeyPress_MaintenanceFlowIgnoredOutsideNodesPane(t *testin
 	}
 }
 
+func TestLsModel_handleKeyPress_DeploymentFlowOpens(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         tea.KeyPressMsg
+		wantRestore bool
+	}{
+		{name: "scale down", key: tea.KeyPressMsg{Code: 'd', Text: "d"}},
+		{name: "restore", key: tea.KeyPressMsg{Code: 'u', Text: "u"}, wantRestore: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewLsModel(LsModelConfig{
+				Context: context.Background(),
+			})
+			model.setActivePane(LsPaneDeployments)
+			model.deploymentsPodsView.SetDeployments([]k8s.DeploymentInfo{
+				{Name: "rook-ceph-osd-3", Namespace: "rook-ceph", NodeName: "node-a", Type: "osd"},
+			})
+
+			cmd := model.handleKeyPress(tt.key)
+			flow, ok := model.maintenanceFlow.(*DeploymentModel)
+			if !ok {
+				t.Fatalf("expected deployment flow to open, got %T", model.maintenanceFlow)
+			}
+			if flow.config.Name != "rook-ceph-osd-3" || flow.config.Restore != tt.wantRestore {
+				t.Errorf("flow targets %s (restore=%v), want rook-ceph-osd-3 (restore=%v)",
+					flow.config.Name, flow.config.Restore, tt.wantRestore)
+			}
+			if model.flowPane != LsPaneDeployments {
+				t.Errorf("flowPane = %v, want %v", model.flowPane, LsPaneDeployments)
+			}
+			if cmd == nil {
+				t.Error("expected init command when opening flow")
+			}
+			flow.Abort()
+		})
+	}
+}
+
+func TestLsModel_Update_FlowKeysFollowFlowPane(t *testing.T) {
+	model := NewLsModel(LsModelConfig{
+		Context: context.Background(),
+	})
+	flow := &stubSizedModel{}
+	model.maintenanceFlow = flow
+	model.flowPane = LsPaneDeployments
+
+	model.setActivePane(LsPaneNodes)
+	_, _ = model.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if flow.updated {
+		t.Fatal("flow keys should be ignored outside the pane the flow was started from")
+	}
+
+	model.setActivePane(LsPaneDeployments)
+	_, _ = model.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if !flow.updated {
+		t.Error("flow keys should reach the flow on the pane it was started from")
+	}
+}
+
 type testSizedModel struct{}
 
 func (m *testSizedModel) Init() tea.Cmd { return nil }
@@ -867,3 +1193,153 @@ func TestLsModel_CursorNavigationOnlyAffectsActivePane(t *testing.T) {
 }
 
 // NOTE: contains() helper is defined in app_test.go
+
+func TestLsModel_View_ReauthenticatingBanner(t *testing.T) {
+	model := NewLsModel(LsModelConfig{
+		Context: context.Background(),
+	})
+	model.width = 120
+	model.height = 40
+
+	model.updateFromMonitor(&monitoring.LsMonitorUpdate{Reauthenticating: true})
+	if !contains(model.Render(), "re-authenticating") {
+		t.Error("View should show re-authenticating banner while credentials refresh")
+	}
+
+	model.updateFromMonitor(&monitoring.LsMonitorUpdate{})
+	if contains(model.Render(), "re-authenticating") {
+		t.Error("View should clear re-authenticating banner after a successful update")
+	}
+}
+
+func TestLsModel_View_DegradedBanner(t *testing.T) {
+	clk := fakeclock.New(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	model := NewLsModel(LsModelConfig{
+		Context: context.Background(),
+		Clock:   clk,
+	})
+	model.width = 120
+	model.height = 40
+
+	model.updateFromMonitor(&monitoring.LsMonitorUpdate{
+		Error:     errors.New("osds: timeout"),
+		NextRetry: clk.Now().Add(30 * time.Second),
+	})
+	if !contains(model.Render(), "degraded: retrying in 30s") {
+		t.Error("View should show degraded banner while monitor backs off")
+	}
+
+	// The countdown follows the clock
+	clk.Advance(20 * time.Second)
+	if !contains(model.Render(), "degraded: retrying in 10s") {
+		t.Error("View should count down to the next retry")
+	}
+
+	model.updateFromMonitor(&monitoring.LsMonitorUpdate{})
+	if contains(model.Render(), "degraded") {
+		t.Error("View should clear degraded banner after recovery")
+	}
+}
+
+func TestLsModel_handleKeyPress_Queue(t *testing.T) {
+	model := NewLsModel(LsModelConfig{
+		Context: context.Background(),
+	})
+	model.setActivePane(LsPaneNodes)
+	nodes := []k8s.NodeInfo{{Name: "node-a"}, {Name: "node-b"}}
+	model.nodesView.SetNodes(nodes)
+	model.nodeCount = len(nodes)
+
+	mark := tea.KeyPressMsg{Code: 'm', Text: "m"}
+	model.handleKeyPress(mark)
+	model.nodesView.SetCursor(1)
+	model.handleKeyPress(mark)
+	model.handleKeyPress(tea.KeyPressMsg{Code: 'K', Text: "K"})
+	if got, want := queueNodes(model.queue), []string{"node-b", "node-a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("queue = %v, want %v", got, want)
+	}
+
+	cmd := model.handleKeyPress(tea.KeyPressMsg{Code: 's', Text: "s"})
+	flow, ok := model.maintenanceFlow.(*DownModel)
+	if !ok {
+		t.Fatalf("expected down flow to open, got %T", model.maintenanceFlow)
+	}
+	t.Cleanup(flow.Abort)
+	if flow.NodeName() != "node-b" {
+		t.Errorf("flow node = %s, want node-b", flow.NodeName())
+	}
+	if cmd == nil {
+		t.Error("expected init command when starting the queue")
+	}
+	if current, _ := model.queue.Current(); current.Node != "node-b" || current.Status != QueueEntryGoingDown {
+		t.Errorf("current = %+v, want node-b going down", current)
+	}
+
+	// Declining the down flow pauses the queue and puts the node back
+	_, _ = model.Update(DownFlowExitMsg{Reason: FlowExitDeclined})
+	if model.maintenanceFlow != nil {
+		t.Error("flow should close on exit")
+	}
+	if model.queue.Running() {
+		t.Error("queue should pause when the down flow does not finish")
+	}
+}
+
+func TestLsModel_ClusterLog(t *testing.T) {
+	model := NewLsModel(LsModelConfig{
+		Context: context.Background(),
+	})
+	model.width = 120
+	model.height = 50
+
+	logKey := tea.KeyPressMsg{Code: 'L', Text: "L"}
+	model.Update(logKey)
+	if !model.clusterLogOpen {
+		t.Fatal("L should open the cluster log")
+	}
+
+	entries := []k8s.ClusterLogEntry{
+		{Name: "mon.a", Seq: 1, Severity: k8s.ClusterLogDebug, Message: "pgmap v1"},
+		{Name: "mon.a", Seq: 2, Severity: k8s.ClusterLogWarning, Message: "noout flag(s) set"},
+	}
+
+	// Polls started before the log was toggled are discarded
+	_, cmd := model.Update(ClusterLogMsg{Generation: model.clusterLogGeneration - 1, Entries: entries})
+	if cmd != nil || model.clusterLog.Count() != 0 {
+		t.Error("stale cluster log poll should be ignored")
+	}
+
+	_, cmd = model.Update(ClusterLogMsg{Generation: model.clusterLogGeneration, Entries: entries})
+	if cmd == nil {
+		t.Error("cluster log result should schedule the next poll")
+	}
+
+	view := model.Render()
+	if !contains(view, "Cluster Log") || !contains(view, "noout flag(s) set") {
+		t.Error("view should show the cluster log pane and its entries")
+	}
+	if contains(view, "pgmap v1") {
+		t.Error("debug entries should be hidden at the default level")
+	}
+	if contains(view, "[3] OSDs") {
+		t.Error("cluster log should replace the OSDs pane")
+	}
+
+	// v raises the level from info to warn
+	model.Update(tea.KeyPressMsg{Code: 'v', Text: "v"})
+	if model.clusterLog.MinSeverity() != k8s.ClusterLogWarning {
+		t.Errorf("level = %v, want WRN", model.clusterLog.MinSeverity())
+	}
+
+	// Esc closes the log instead of quitting
+	_, cmd = model.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if model.clusterLogOpen {
+		t.Error("Esc should close the cluster log")
+	}
+	if cmd != nil {
+		t.Error("closing the cluster log should not quit")
+	}
+	if !contains(model.Render(), "[3] OSDs") {
+		t.Error("OSDs pane should return after closing the cluster log")
+	}
+}
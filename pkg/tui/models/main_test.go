@@ -0,0 +1,13 @@
+package models
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+// TestMain fails the package when goroutines started by models (monitors,
+// flows, progress listeners) outlive the tests
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
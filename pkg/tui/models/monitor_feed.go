@@ -0,0 +1,93 @@
+package models
+
+import (
+	"slices"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/pkg/monitoring"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// FlowMonitorUpdateMsg carries an update of an embedded flow's own
+// subscription to the hosting view's monitor
+type FlowMonitorUpdateMsg struct {
+	// Updates is the subscription the update arrived on, so a flow ignores
+	// updates meant for a flow it replaced
+	Updates <-chan *monitoring.LsMonitorUpdate
+
+	Update *monitoring.LsMonitorUpdate
+}
+
+// monitorFeed is an embedded flow's subscription to the hosting view's
+// monitor. The host subscribes with monitoring.Lossless so the flow sees
+// every change to its node, and unsubscribes when the flow closes.
+type monitorFeed struct {
+	updates <-chan *monitoring.LsMonitorUpdate
+
+	// latest is the most recent state; the host's snapshot until the first
+	// update arrives
+	latest *monitoring.LsMonitorUpdate
+}
+
+// newMonitorFeed returns the feed of a flow; updates may be nil for flows
+// that are not embedded
+func newMonitorFeed(updates <-chan *monitoring.LsMonitorUpdate, snapshot *monitoring.LsMonitorUpdate) monitorFeed {
+	return monitorFeed{updates: updates, latest: snapshot}
+}
+
+// observed returns the latest state for discovery
+func (f *monitorFeed) observed() observedState {
+	return observedState{snapshot: f.latest}
+}
+
+// listen returns a command that waits for the next update, nil without a
+// subscription. Nothing is delivered once the subscription is closed.
+func (f *monitorFeed) listen() tea.Cmd {
+	updates := f.updates
+	if updates == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		update, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return FlowMonitorUpdateMsg{Updates: updates, Update: update}
+	}
+}
+
+// receive stores an update of the feed and reports whether it changed the
+// node or one of the given deployments (or a deployment now on the node).
+// ok is false for updates of another feed, which must not be listened to again.
+func (f *monitorFeed) receive(msg FlowMonitorUpdateMsg, nodeName string, deployments []appsv1.Deployment) (ok, touched bool) {
+	if msg.Updates != f.updates || msg.Update == nil {
+		return false, false
+	}
+	f.latest = msg.Update
+
+	changes := msg.Update.Changes
+	if deltaContains(changes.Nodes, func(key string) bool { return key == nodeName }) {
+		return true, true
+	}
+	onNode := func(key string) bool {
+		for i := range deployments {
+			if deployments[i].Namespace+"/"+deployments[i].Name == key {
+				return true
+			}
+		}
+		for _, dep := range msg.Update.Deployments {
+			if dep.NodeName == nodeName && monitoring.DeploymentKey(dep) == key {
+				return true
+			}
+		}
+		return false
+	}
+	return true, deltaContains(changes.Deployments, onNode)
+}
+
+// deltaContains reports whether any added, updated or removed identity matches
+func deltaContains(delta monitoring.ResourceDelta, match func(key string) bool) bool {
+	return slices.ContainsFunc(delta.Added, match) ||
+		slices.ContainsFunc(delta.Updated, match) ||
+		slices.ContainsFunc(delta.Removed, match)
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/monitoring"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMonitorFeed_Receive(t *testing.T) {
+	updates := make(chan *monitoring.LsMonitorUpdate)
+	discovered := []appsv1.Deployment{{ObjectMeta: metav1.ObjectMeta{Namespace: "rook-ceph", Name: "rook-ceph-osd-0"}}}
+
+	tests := []struct {
+		name        string
+		updates     <-chan *monitoring.LsMonitorUpdate
+		update      *monitoring.LsMonitorUpdate
+		wantOK      bool
+		wantTouched bool
+	}{
+		{
+			name:    "update of another feed",
+			updates: make(chan *monitoring.LsMonitorUpdate),
+			update:  &monitoring.LsMonitorUpdate{},
+		},
+		{
+			name:        "node changed",
+			updates:     updates,
+			update:      &monitoring.LsMonitorUpdate{Changes: monitoring.LsChanges{Nodes: monitoring.ResourceDelta{Updated: []string{"worker-1"}}}},
+			wantOK:      true,
+			wantTouched: true,
+		},
+		{
+			name:        "discovered deployment changed",
+			updates:     updates,
+			update:      &monitoring.LsMonitorUpdate{Changes: monitoring.LsChanges{Deployments: monitoring.ResourceDelta{Removed: []string{"rook-ceph/rook-ceph-osd-0"}}}},
+			wantOK:      true,
+			wantTouched: true,
+		},
+		{
+			name:    "deployment on the node added",
+			updates: updates,
+			update: &monitoring.LsMonitorUpdate{
+				Deployments: []k8s.DeploymentInfo{{Namespace: "rook-ceph", Name: "rook-ceph-mon-d", NodeName: "worker-1"}},
+				Changes:     monitoring.LsChanges{Deployments: monitoring.ResourceDelta{Added: []string{"rook-ceph/rook-ceph-mon-d"}}},
+			},
+			wantOK:      true,
+			wantTouched: true,
+		},
+		{
+			name:    "other node changed",
+			updates: updates,
+			update: &monitoring.LsMonitorUpdate{
+				Deployments: []k8s.DeploymentInfo{{Namespace: "rook-ceph", Name: "rook-ceph-osd-5", NodeName: "worker-2"}},
+				Changes: monitoring.LsChanges{
+					Nodes:       monitoring.ResourceDelta{Updated: []string{"worker-2"}},
+					Deployments: monitoring.ResourceDelta{Updated: []string{"rook-ceph/rook-ceph-osd-5"}},
+				},
+			},
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := newMonitorFeed(updates, nil)
+			ok, touched := feed.receive(FlowMonitorUpdateMsg{Updates: tt.updates, Update: tt.update}, "worker-1", discovered)
+			if ok != tt.wantOK || touched != tt.wantTouched {
+				t.Errorf("receive() = %v, %v, want %v, %v", ok, touched, tt.wantOK, tt.wantTouched)
+			}
+			if ok && feed.latest != tt.update {
+				t.Error("receive() did not keep the update as the latest state")
+			}
+		})
+	}
+}
+
+func TestMonitorFeed_ListenClosed(t *testing.T) {
+	unsubscribed := newMonitorFeed(nil, nil)
+	if cmd := unsubscribed.listen(); cmd != nil {
+		t.Error("listen() without a subscription should return nil")
+	}
+
+	updates := make(chan *monitoring.LsMonitorUpdate)
+	close(updates)
+	feed := newMonitorFeed(updates, nil)
+	if msg := feed.listen()(); msg != nil {
+		t.Errorf("listen() on a closed subscription delivered %T, want nothing", msg)
+	}
+}
+
+func TestDownModel_StaleDiscoveryAfterConfirm(t *testing.T) {
+	model := NewDownModel(DownModelConfig{NodeName: "worker-1", Context: context.Background()})
+	defer model.Abort()
+	model.state = DownStateScalingDeployments
+
+	model.Update(DeploymentsDiscoveredMsg{DownPlan: []DownPlanItem{{Namespace: "rook-ceph", Name: "rook-ceph-osd-0", Status: "pending"}}})
+
+	if model.state != DownStateScalingDeployments {
+		t.Errorf("state = %v, want the running state kept", model.state)
+	}
+	if len(model.downPlan) != 0 {
+		t.Errorf("downPlan = %v, want the confirmed plan kept", model.downPlan)
+	}
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/pkg/config"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// notificationTitle is the title of desktop notifications that carry one
+const notificationTitle = "crook"
+
+// finishedNotification returns the escape sequences that alert an operator
+// who tabbed away: the terminal bell and, when configured, a desktop
+// notification with the summary. It returns "" when both are disabled.
+func finishedNotification(ui config.UIConfig, summary string) string {
+	var b strings.Builder
+	if ui.Bell {
+		b.WriteByte(ansi.BEL)
+	}
+
+	summary = sanitizeNotification(summary)
+	switch ui.DesktopNotification {
+	case config.DesktopNotificationOSC9:
+		b.WriteString(ansi.Notify(notificationTitle + ": " + summary))
+	case config.DesktopNotificationOSC777:
+		b.WriteString("\x1b]777;notify;" + notificationTitle + ";" + summary + "\x07")
+	}
+	return b.String()
+}
+
+// sanitizeNotification flattens text to a single line without control
+// characters, which would end or corrupt the escape sequence
+func sanitizeNotification(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// notifyFinished alerts the operator that the flow on node completed or
+// failed, as configured in ui
+func (p *PhaseModel[S, P]) notifyFinished(ui config.UIConfig, node string) tea.Cmd {
+	outcome := fmt.Sprintf("completed in %s", p.clock.Since(p.startTime).Round(time.Second))
+	if p.status() == phaseError {
+		outcome = "failed"
+		if p.lastError != nil {
+			outcome += ": " + p.lastError.Error()
+		}
+	}
+
+	seq := finishedNotification(ui, fmt.Sprintf("%s %s %s", p.def.Name, node, outcome))
+	if seq == "" {
+		return nil
+	}
+	return tea.Raw(seq)
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/pkg/config"
+)
+
+func TestFinishedNotification(t *testing.T) {
+	tests := []struct {
+		name string
+		ui   config.UIConfig
+		want string
+	}{
+		{name: "disabled", ui: config.UIConfig{}, want: ""},
+		{name: "bell", ui: config.UIConfig{Bell: true}, want: "\a"},
+		{
+			name: "osc9",
+			ui:   config.UIConfig{DesktopNotification: config.DesktopNotificationOSC9},
+			want: "\x1b]9;crook: Down worker-1 failed: boom\a",
+		},
+		{
+			name: "bell and osc777",
+			ui:   config.UIConfig{Bell: true, DesktopNotification: config.DesktopNotificationOSC777},
+			want: "\a\x1b]777;notify;crook;Down worker-1 failed: boom\a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Control characters in the summary must not end the sequence early
+			got := finishedNotification(tt.ui, "Down worker-1 failed:\n\x1b\aboom")
+			if got != tt.want {
+				t.Errorf("finishedNotification() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPhaseModel_NotifyFinished(t *testing.T) {
+	p := newTestPhaseModel(t)
+	ui := config.UIConfig{Bell: true, DesktopNotification: config.DesktopNotificationOSC9}
+
+	if cmd := p.notifyFinished(config.UIConfig{}, "worker-1"); cmd != nil {
+		t.Error("notifyFinished() should return nil when notifications are disabled")
+	}
+
+	p.startExecution()
+	p.complete()
+	raw, ok := p.notifyFinished(ui, "worker-1")().(tea.RawMsg)
+	if !ok {
+		t.Fatal("notifyFinished() should print a raw escape sequence")
+	}
+	if want := "\a\x1b]9;crook: Test worker-1 completed in 0s\a"; raw.Msg != want {
+		t.Errorf("complete notification = %q, want %q", raw.Msg, want)
+	}
+
+	p.startExecution()
+	p.fail(errors.New("scale timed out"))
+	raw, _ = p.notifyFinished(ui, "worker-1")().(tea.RawMsg)
+	if want := "\a\x1b]9;crook: Test worker-1 failed: scale timed out\a"; raw.Msg != want {
+		t.Errorf("error notification = %q, want %q", raw.Msg, want)
+	}
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/monitoring"
+)
+
+// observedState answers discovery questions from a hosting view's monitor
+// snapshot so an embedded flow can skip queries whose outcome is already
+// known. Methods return false when the snapshot cannot tell, and callers then
+// query the cluster as usual. The snapshot is at most one refresh old;
+// pre-flight validation always queries the cluster again.
+type observedState struct {
+	snapshot *monitoring.LsMonitorUpdate
+}
+
+// node returns the snapshot entry of a node
+func (o observedState) node(name string) (k8s.NodeInfo, bool) {
+	if o.snapshot == nil {
+		return k8s.NodeInfo{}, false
+	}
+	for _, node := range o.snapshot.Nodes {
+		if node.Name == name {
+			return node, true
+		}
+	}
+	return k8s.NodeInfo{}, false
+}
+
+// noOut returns whether the Ceph noout flag is observed anywhere: cluster-wide
+// or on a host or OSD
+func (o observedState) noOut() (set, known bool) {
+	if o.snapshot == nil || o.snapshot.Header == nil {
+		return false, false
+	}
+	return o.snapshot.Header.NooutSet || len(o.snapshot.Header.NooutGroups) > 0, true
+}
+
+// notInDownState reports that the node is known not to be fully down: it is
+// still schedulable or noout is unset
+func (o observedState) notInDownState(nodeName string) bool {
+	if node, ok := o.node(nodeName); ok && !node.Cordoned {
+		return true
+	}
+	set, known := o.noOut()
+	return known && !set
+}
+
+// notInUpState reports that the node is known not to be fully up: it is
+// cordoned or noout is set
+func (o observedState) notInUpState(nodeName string) bool {
+	if node, ok := o.node(nodeName); ok && node.Cordoned {
+		return true
+	}
+	set, known := o.noOut()
+	return known && set
+}
+
+// noOtherMaintenance reports that no other node is cordoned and noout is
+// unset, which is when CheckOtherNodesInMaintenance finds nothing
+func (o observedState) noOtherMaintenance(target string) bool {
+	if set, known := o.noOut(); !known || set || len(o.snapshot.Nodes) == 0 {
+		return false
+	}
+	for _, node := range o.snapshot.Nodes {
+		if node.Name != target && node.Cordoned {
+			return false
+		}
+	}
+	return true
+}
+
+// noDataMovementRisks reports that the balancer is idle and no pool has the
+// autoscaler on, which is when CheckDataMovementRisks finds nothing
+func (o observedState) noDataMovementRisks() bool {
+	if o.snapshot == nil || o.snapshot.Header == nil {
+		return false
+	}
+	header := o.snapshot.Header
+	if header.BalancerMode == "" || header.AutoscalePools == 0 {
+		// Unknown state
+		return false
+	}
+	balancerIdle := !header.BalancerActive || header.BalancerMode == "none"
+	return balancerIdle && header.AutoscaleOn == 0
+}
+
+// otherNodesInMaintenance returns the observed answer when it is known
+func (o observedState) otherNodesInMaintenance(target string) (*maintenance.OtherNodesMaintenanceInfo, bool) {
+	if !o.noOtherMaintenance(target) {
+		return nil, false
+	}
+	return &maintenance.OtherNodesMaintenanceInfo{NodesInMaintenance: []maintenance.MaintenanceStatus{}}, true
+}
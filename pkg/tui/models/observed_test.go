@@ -0,0 +1,79 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/monitoring"
+	"github.com/andri/crook/pkg/tui/components"
+)
+
+func TestObservedState(t *testing.T) {
+	healthyHeader := &components.ClusterHeaderData{
+		BalancerActive: true,
+		BalancerMode:   "none",
+		AutoscalePools: 3,
+	}
+	cordonedTarget := []k8s.NodeInfo{{Name: "worker-1", Cordoned: true}, {Name: "worker-2"}}
+
+	tests := []struct {
+		name             string
+		snapshot         *monitoring.LsMonitorUpdate
+		wantNotDown      bool
+		wantNotUp        bool
+		wantNoOther      bool
+		wantNoDataMoving bool
+	}{
+		{
+			name: "no snapshot",
+		},
+		{
+			name:     "header unknown",
+			snapshot: &monitoring.LsMonitorUpdate{Nodes: []k8s.NodeInfo{{Name: "worker-1"}}},
+			// A schedulable node is enough to rule out the down state
+			wantNotDown: true,
+		},
+		{
+			name:             "operational node",
+			snapshot:         &monitoring.LsMonitorUpdate{Nodes: []k8s.NodeInfo{{Name: "worker-1"}}, Header: healthyHeader},
+			wantNotDown:      true,
+			wantNoOther:      true,
+			wantNoDataMoving: true,
+		},
+		{
+			name: "target in maintenance",
+			snapshot: &monitoring.LsMonitorUpdate{
+				Nodes:  cordonedTarget,
+				Header: &components.ClusterHeaderData{NooutSet: true, BalancerMode: "upmap", BalancerActive: true, AutoscalePools: 3, AutoscaleOn: 1},
+			},
+			wantNotUp: true,
+		},
+		{
+			name: "another node cordoned",
+			snapshot: &monitoring.LsMonitorUpdate{
+				Nodes:  []k8s.NodeInfo{{Name: "worker-1"}, {Name: "worker-2", Cordoned: true}},
+				Header: healthyHeader,
+			},
+			wantNotDown:      true,
+			wantNoDataMoving: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := observedState{snapshot: tt.snapshot}
+			if got := o.notInDownState("worker-1"); got != tt.wantNotDown {
+				t.Errorf("notInDownState() = %v, want %v", got, tt.wantNotDown)
+			}
+			if got := o.notInUpState("worker-1"); got != tt.wantNotUp {
+				t.Errorf("notInUpState() = %v, want %v", got, tt.wantNotUp)
+			}
+			if _, got := o.otherNodesInMaintenance("worker-1"); got != tt.wantNoOther {
+				t.Errorf("otherNodesInMaintenance() known = %v, want %v", got, tt.wantNoOther)
+			}
+			if got := o.noDataMovementRisks(); got != tt.wantNoDataMoving {
+				t.Errorf("noDataMovementRisks() = %v, want %v", got, tt.wantNoDataMoving)
+			}
+		})
+	}
+}
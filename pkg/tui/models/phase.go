@@ -0,0 +1,788 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/internal/clock"
+	"github.com/andri/crook/internal/logger"
+	"github.com/andri/crook/internal/telemetry"
+	crookerrors "github.com/andri/crook/pkg/errors"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/tui/components"
+	"github.com/andri/crook/pkg/tui/keys"
+	"github.com/andri/crook/pkg/tui/styles"
+)
+
+// phaseTickInterval is how often a running phase refreshes its elapsed time
+const phaseTickInterval = 100 * time.Millisecond
+
+// phaseCompleteStage is the progress stage reported after the last stage
+const phaseCompleteStage = "complete"
+
+// PhaseStage defines one step of a guided phase flow
+type PhaseStage[S comparable] struct {
+	// State is the flow state while the step runs
+	State S
+
+	// Label is the step's entry in the status list
+	Label string
+
+	// Progress lists the progress stages reported by the maintenance
+	// package while the step runs
+	Progress []string
+}
+
+// PhaseStates names the flow states every phase handles alike. All other
+// states are running states.
+type PhaseStates[S comparable] struct {
+	Init        S
+	Confirm     S
+	NothingToDo S
+	Complete    S
+	Error       S
+}
+
+// PhaseScreens render the phase specific parts of a flow
+type PhaseScreens struct {
+	Loading      func() string
+	Confirmation func() string
+	NothingToDo  func() string
+	Complete     func() string
+
+	// Banner is shown above every screen when it returns text, e.g. to
+	// label emergency handling. Optional.
+	Banner func() string
+}
+
+// PhaseDefinition parameterizes a PhaseModel with states S and progress
+// updates P
+type PhaseDefinition[S comparable, P any] struct {
+	// Name is the phase name, e.g. "Down"
+	Name string
+
+	// Flow names the flow in telemetry reports; the lowercase Name when empty
+	Flow string
+
+	// ConfirmQuestion is asked before the phase runs
+	ConfirmQuestion string
+
+	// States and Stages in execution order
+	States PhaseStates[S]
+	Stages []PhaseStage[S]
+
+	// Runner delivers the phase's progress as messages
+	Runner *FlowRunner[P]
+
+	// Execute returns the operation confirmed by the user
+	Execute func() func(ctx context.Context, report func(P)) tea.Msg
+
+	// Resumable is set when Execute continues a retry of the failed part at
+	// the resume point instead of starting over; it enables the retry failed
+	// key
+	Resumable bool
+
+	// TickMsg is delivered by the elapsed time ticker
+	TickMsg tea.Msg
+
+	// ExitMsg wraps the outcome of the flow for embedding callers
+	ExitMsg func(reason FlowExitReason, err error) tea.Msg
+
+	// PendingWorkloads lists the workloads (namespace/name) not yet scaled,
+	// for the ETA. Optional.
+	PendingWorkloads func() []string
+
+	// Client runs the phase; the changes it makes from the start of the flow
+	// are listed by the changes key. Optional.
+	Client *k8s.Client
+
+	// Screens render the phase specific views
+	Screens PhaseScreens
+}
+
+// phaseStatus classifies flow states for the behavior shared by all phases
+type phaseStatus int
+
+const (
+	phaseLoading phaseStatus = iota
+	phaseConfirm
+	phaseNothingToDo
+	phaseRunning
+	phaseComplete
+	phaseError
+)
+
+// phaseResume is where a retry of the failed part of a flow continues: the
+// progress stage it failed at and the items it has to redo (empty for all)
+type phaseResume struct {
+	from string
+	only []string
+}
+
+// PhaseModel is the engine shared by guided maintenance flows. It owns the
+// confirmation prompt, stage status list, elapsed time, key handling, exit
+// behavior and frame rendering; flows embed it and supply a PhaseDefinition
+// with their stages, executor and screens.
+type PhaseModel[S comparable, P any] struct {
+	def PhaseDefinition[S, P]
+
+	// Flow context, cancelled by Abort
+	ctx       context.Context
+	abortFunc context.CancelFunc
+
+	// How the flow is hosted
+	exitBehavior FlowExitBehavior
+	embedded     bool
+
+	// Current state machine state
+	state S
+
+	// Terminal dimensions
+	width  int
+	height int
+
+	// UI components
+	confirmPrompt *components.ConfirmPrompt
+	statusList    *components.StatusList
+	progress      *components.ProgressBar
+
+	// clock drives the elapsed time and scale ETA; replaced in tests
+	clock clock.Clock
+
+	// Operation state
+	startTime           time.Time
+	elapsedTime         time.Duration
+	lastError           error
+	operationInProgress bool
+
+	// Progress sequencing: the last update's sequence number, the updates
+	// lost to the non-blocking progress channel, and the phase percentage
+	lastSequence   uint64
+	droppedUpdates uint64
+	percent        int
+
+	// Scale ETA: the estimator, the workload scaling and since when, and
+	// the estimate refreshed on every tick
+	scaleETA       *maintenance.ScaleETA
+	scaling        string
+	scalingStarted time.Time
+	eta            time.Duration
+	etaKnown       bool
+
+	// Pre-flight checks finished so far, in the order they finished
+	checks []maintenance.CheckStatus
+
+	// Retry of the failed part only: the progress stage the last run
+	// reached, and where the current run resumes (zero for a full run)
+	lastStage string
+	resume    phaseResume
+
+	// acknowledged proceeds past pre-flight warnings; set by the
+	// Acknowledge key after a run stopped at them
+	acknowledged bool
+
+	// changeMark is the client's change count when the flow opened, and
+	// showChanges replaces the result screen with the flow's changes
+	changeMark  int
+	showChanges bool
+
+	// Cancellation and progress
+	runner *FlowRunner[P]
+
+	// Keybindings and help
+	keyBindings keys.FlowBindings
+	helpModel   help.Model
+}
+
+// NewPhaseModel creates the engine for a flow. All work of the flow derives
+// from the returned context so Abort reaches it.
+func NewPhaseModel[S comparable, P any](
+	ctx context.Context,
+	def PhaseDefinition[S, P],
+	exitBehavior FlowExitBehavior,
+	embedded bool,
+) (PhaseModel[S, P], context.Context) {
+	h := help.New()
+	h.Styles.ShortKey = h.Styles.ShortKey.Foreground(styles.ColorInfo)
+	h.Styles.ShortDesc = h.Styles.ShortDesc.Foreground(styles.ColorSubtle)
+
+	flowCtx, abort := withAbort(ctx)
+
+	changeMark := 0
+	if def.Client != nil {
+		changeMark = def.Client.ChangeCount()
+	}
+
+	return PhaseModel[S, P]{
+		def:           def,
+		ctx:           flowCtx,
+		abortFunc:     abort,
+		exitBehavior:  exitBehavior,
+		embedded:      embedded,
+		state:         def.States.Init,
+		confirmPrompt: components.NewConfirmPrompt(def.ConfirmQuestion),
+		statusList:    components.NewStatusList(),
+		progress:      components.NewIndeterminateProgress(""),
+		runner:        def.Runner,
+		keyBindings:   keys.DefaultFlowBindings(),
+		helpModel:     h,
+		clock:         clock.Real(),
+		changeMark:    changeMark,
+	}, flowCtx
+}
+
+// Abort cancels all work of the flow: discovery, the running phase and its
+// progress listener. The flow cannot be used afterwards.
+func (p *PhaseModel[S, P]) Abort() {
+	if p.abortFunc != nil {
+		p.abortFunc()
+	}
+}
+
+// PhaseName returns the name of the phase
+func (p *PhaseModel[S, P]) PhaseName() string {
+	return p.def.Name
+}
+
+// status classifies the current state
+func (p *PhaseModel[S, P]) status() phaseStatus {
+	switch p.state {
+	case p.def.States.Init:
+		return phaseLoading
+	case p.def.States.Confirm:
+		return phaseConfirm
+	case p.def.States.NothingToDo:
+		return phaseNothingToDo
+	case p.def.States.Complete:
+		return phaseComplete
+	case p.def.States.Error:
+		return phaseError
+	default:
+		return phaseRunning
+	}
+}
+
+// tickCmd returns a command that delivers the phase's tick message
+func (p *PhaseModel[S, P]) tickCmd() tea.Cmd {
+	tickMsg := p.def.TickMsg
+	return tea.Tick(phaseTickInterval, func(_ time.Time) tea.Msg {
+		return tickMsg
+	})
+}
+
+// tick refreshes the elapsed time and spinner and schedules the next tick
+func (p *PhaseModel[S, P]) tick(msg tea.Msg) tea.Cmd {
+	if p.operationInProgress {
+		p.elapsedTime = p.clock.Since(p.startTime)
+		p.refreshETA()
+	}
+	newProgress, cmd := p.progress.Update(msg)
+	if bar, ok := newProgress.(*components.ProgressBar); ok {
+		p.progress = bar
+	}
+	return tea.Batch(cmd, p.tickCmd())
+}
+
+// update handles the messages all phases treat alike and forwards input to
+// the confirmation prompt while it is shown
+func (p *PhaseModel[S, P]) update(msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		cmds = append(cmds, p.handleKeyPress(msg))
+
+	case tea.WindowSizeMsg:
+		p.SetSize(msg.Width, msg.Height)
+
+	case components.ConfirmResultMsg:
+		if msg.Result == components.ConfirmYes {
+			p.startExecution()
+			cmds = append(cmds, p.execute())
+		} else {
+			// User cancelled or declined
+			reason := FlowExitDeclined
+			if msg.Result == components.ConfirmCancelled {
+				reason = FlowExitCancelled
+			}
+			return p.exitCmd(reason, nil)
+		}
+	}
+
+	if p.state == p.def.States.Confirm {
+		newPrompt, cmd := p.confirmPrompt.Update(msg)
+		if prompt, ok := newPrompt.(*components.ConfirmPrompt); ok {
+			p.confirmPrompt = prompt
+		}
+		cmds = append(cmds, cmd)
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// handleKeyPress processes keyboard input based on current state
+func (p *PhaseModel[S, P]) handleKeyPress(msg tea.KeyMsg) tea.Cmd {
+	// Update keybinding state based on current flow state
+	p.updateKeyBindings()
+
+	// Only enabled on the result screens of a flow that changed something
+	if key.Matches(msg, p.keyBindings.Changes) {
+		p.showChanges = !p.showChanges
+		return nil
+	}
+
+	switch p.status() { //nolint:exhaustive // default handles all operation states uniformly
+	case phaseError:
+		switch {
+		case key.Matches(msg, p.keyBindings.RetryFailed):
+			p.resume = p.resumePoint()
+			p.startExecution()
+			return p.execute()
+		case key.Matches(msg, p.keyBindings.Acknowledge):
+			p.acknowledged = true
+			p.resume = phaseResume{}
+			p.startExecution()
+			return p.execute()
+		case key.Matches(msg, p.keyBindings.Retry):
+			p.resume = phaseResume{}
+			p.acknowledged = false
+			p.startExecution()
+			return p.execute()
+		case key.Matches(msg, p.keyBindings.Quit):
+			return p.exitCmd(FlowExitError, p.lastError)
+		}
+
+	case phaseComplete:
+		if key.Matches(msg, p.keyBindings.Exit) {
+			return p.exitCmd(FlowExitCompleted, nil)
+		}
+
+	case phaseNothingToDo:
+		if key.Matches(msg, p.keyBindings.Exit) {
+			return p.exitCmd(FlowExitNothingToDo, nil)
+		}
+
+	case phaseConfirm:
+		// Let the confirm prompt handle it
+		return nil
+
+	default:
+		// During operations, allow cancel
+		if key.Matches(msg, p.keyBindings.Interrupt) {
+			p.runner.Cancel()
+			return p.exitCmd(FlowExitCancelled, nil)
+		}
+	}
+
+	return nil
+}
+
+// updateKeyBindings updates the keybinding state based on current flow state
+func (p *PhaseModel[S, P]) updateKeyBindings() {
+	switch p.status() { //nolint:exhaustive // default handles all operation states
+	case phaseConfirm:
+		p.keyBindings.SetStateConfirm()
+	case phaseError:
+		p.keyBindings.SetStateError(p.resumable(), errors.Is(p.lastError, maintenance.ErrAcknowledgmentRequired))
+		p.keyBindings.SetChanges(len(p.flowChanges()) > 0)
+	case phaseComplete, phaseNothingToDo:
+		p.keyBindings.SetStateComplete()
+		p.keyBindings.SetChanges(len(p.flowChanges()) > 0)
+	default:
+		p.keyBindings.SetStateRunning()
+	}
+}
+
+func (p *PhaseModel[S, P]) exitCmd(reason FlowExitReason, err error) tea.Cmd {
+	return flowExitCmd(p.exitBehavior, p.def.ExitMsg(reason, err))
+}
+
+// resumable reports whether the phase can resume and the failed run got past
+// its first stage, so a retry can continue where it failed instead of
+// starting over
+func (p *PhaseModel[S, P]) resumable() bool {
+	if !p.def.Resumable || p.lastStage == "" || len(p.def.Stages) == 0 {
+		return false
+	}
+	return !slices.Contains(p.def.Stages[0].Progress, p.lastStage)
+}
+
+// resumePoint returns where a retry of the failed part continues: the stage
+// the run failed at and, when it failed on some items of a batch, those items
+func (p *PhaseModel[S, P]) resumePoint() phaseResume {
+	resume := phaseResume{from: p.lastStage}
+	var multi *crookerrors.MultiError
+	if errors.As(p.lastError, &multi) {
+		resume.only = multi.RetryKeys()
+	}
+	return resume
+}
+
+// execute runs the confirmed operation in the background
+func (p *PhaseModel[S, P]) execute() tea.Cmd {
+	return p.runner.Start(p.ctx, p.def.Execute())
+}
+
+// startExecution initializes state for operation execution
+func (p *PhaseModel[S, P]) startExecution() {
+	p.operationInProgress = true
+	p.startTime = p.clock.Now()
+	p.lastSequence, p.droppedUpdates, p.percent = 0, 0, 0
+	p.lastStage = ""
+	p.checks = nil
+	p.showChanges = false
+	if len(p.def.Stages) > 0 {
+		p.state = p.def.Stages[0].State
+	}
+	p.progress = components.NewDeterminateProgress("")
+	p.initStatusList()
+
+	// A resumed run starts at the stage that failed; the ones before it are done
+	if p.resume.from != "" {
+		if i := p.advance(p.resume.from); i > 0 {
+			for done := range i {
+				p.updateStatusItem(done, components.StatusTypeSuccess)
+			}
+		}
+	}
+}
+
+// trackProgress records the sequence number and percentage of a progress
+// update, counting the updates dropped since the previous one
+func (p *PhaseModel[S, P]) trackProgress(sequence uint64, percent int) {
+	if dropped := maintenance.DroppedUpdates(p.lastSequence, sequence); dropped > 0 {
+		p.droppedUpdates += dropped
+		logger.Debug("progress updates dropped", "phase", p.def.Name, "count", dropped)
+	}
+	if sequence > p.lastSequence {
+		p.lastSequence = sequence
+	}
+	if percent > p.percent {
+		p.percent = percent
+		p.progress.SetProgress(float64(percent) / 100)
+	}
+}
+
+// startScaleETA estimates the scale time left from history until the run
+// has measured its own workloads
+func (p *PhaseModel[S, P]) startScaleETA(phase string, history *maintenance.ScaleHistory) {
+	p.scaleETA = maintenance.NewScaleETA(phase, history)
+}
+
+// scalingWorkload records that a workload started scaling, which also ends
+// the previous one
+func (p *PhaseModel[S, P]) scalingWorkload(name string) {
+	p.scalingDone()
+	p.scaling = name
+	p.scalingStarted = p.clock.Now()
+	p.refreshETA()
+}
+
+// scalingDone records the time the workload scaling took
+func (p *PhaseModel[S, P]) scalingDone() {
+	if p.scaling != "" && p.scaleETA != nil {
+		p.scaleETA.Observe(p.clock.Since(p.scalingStarted))
+	}
+	p.scaling = ""
+	p.refreshETA()
+}
+
+// refreshETA recomputes the time left to scale the pending workloads
+func (p *PhaseModel[S, P]) refreshETA() {
+	p.eta, p.etaKnown = 0, false
+	if p.scaleETA == nil || p.def.PendingWorkloads == nil {
+		return
+	}
+	pending := p.def.PendingWorkloads()
+	if p.scaling == "" && len(pending) == 0 {
+		return
+	}
+	p.eta, p.etaKnown = p.scaleETA.Remaining(pending, p.scaling, p.clock.Since(p.scalingStarted))
+}
+
+// initStatusList creates the status list for tracking progress
+func (p *PhaseModel[S, P]) initStatusList() {
+	p.statusList = components.NewStatusList()
+	for _, stage := range p.def.Stages {
+		p.statusList.AddStatus(stage.Label, components.StatusTypePending)
+	}
+}
+
+// advance moves the flow to the stage reporting the given progress stage,
+// completing the stage before it. It returns the stage index, or -1 when no
+// stage reports it.
+func (p *PhaseModel[S, P]) advance(progressStage string) int {
+	if progressStage != phaseCompleteStage {
+		p.lastStage = progressStage
+	}
+	if progressStage == phaseCompleteStage {
+		p.updateStatusItem(len(p.def.Stages)-1, components.StatusTypeSuccess)
+		return -1
+	}
+
+	for i, stage := range p.def.Stages {
+		for _, name := range stage.Progress {
+			if name != progressStage {
+				continue
+			}
+			p.state = stage.State
+			p.updateStatusItem(i-1, components.StatusTypeSuccess)
+			p.updateStatusItem(i, components.StatusTypeRunning)
+			return i
+		}
+	}
+	return -1
+}
+
+// recordCheck adds a finished pre-flight check to the first stage's entry,
+// so the checklist fills in while the remaining checks still run
+func (p *PhaseModel[S, P]) recordCheck(check maintenance.CheckStatus) {
+	p.checks = append(p.checks, check)
+	item := p.statusList.Get(0)
+	if item == nil || len(p.def.Stages) == 0 {
+		return
+	}
+
+	item.SetLabel(fmt.Sprintf("%s (%d/%d)", p.def.Stages[0].Label, check.Done, check.Total))
+	item.DetailsOnNewLine = true
+
+	// Once every check passed cleanly the list has nothing left to tell
+	clean := !slices.ContainsFunc(p.checks, func(c maintenance.CheckStatus) bool { return !c.Passed || c.Warnings > 0 })
+	if clean && check.Done == check.Total {
+		item.SetDetails("")
+		return
+	}
+
+	lines := make([]string, 0, len(p.checks))
+	for _, c := range p.checks {
+		switch {
+		case !c.Passed:
+			lines = append(lines, styles.StyleError.Render(styles.IconCross)+" "+c.Name)
+		case c.Warnings > 0:
+			lines = append(lines, styles.StyleWarning.Render(styles.IconWarning)+" "+c.Name)
+		default:
+			lines = append(lines, styles.StyleSuccess.Render(styles.IconCheckmark)+" "+c.Name)
+		}
+	}
+	item.SetDetails(strings.Join(lines, "\n    "))
+}
+
+// updateStatusItem safely updates a status item
+func (p *PhaseModel[S, P]) updateStatusItem(index int, status components.StatusType) {
+	if index < 0 {
+		return
+	}
+	if item := p.statusList.Get(index); item != nil {
+		item.SetType(status)
+	}
+}
+
+// complete records the successful end of the operation
+func (p *PhaseModel[S, P]) complete() {
+	p.state = p.def.States.Complete
+	p.operationInProgress = false
+	p.runner.Finish()
+	p.progress.Complete()
+	p.recordTelemetry(telemetry.OutcomeSucceeded, "")
+}
+
+// fail records the error that ended the operation
+func (p *PhaseModel[S, P]) fail(err error) {
+	p.state = p.def.States.Error
+	p.lastError = err
+	p.operationInProgress = false
+	p.runner.Finish()
+	p.progress.Error()
+	p.recordTelemetry(telemetry.OutcomeFailed, p.lastStage)
+}
+
+// recordTelemetry adds the finished run of the flow to the usage report
+func (p *PhaseModel[S, P]) recordTelemetry(outcome, failureStage string) {
+	flow := p.def.Flow
+	if flow == "" {
+		flow = strings.ToLower(p.def.Name)
+	}
+	telemetry.RecordFlow(telemetry.Flow{
+		Phase:        flow,
+		Outcome:      outcome,
+		DurationMS:   p.clock.Since(p.startTime).Milliseconds(),
+		FailureStage: failureStage,
+	})
+}
+
+// View implements tea.Model
+func (p *PhaseModel[S, P]) View() tea.View {
+	return tea.NewView(p.Render())
+}
+
+// Render returns the string representation for composition
+func (p *PhaseModel[S, P]) Render() string {
+	var b strings.Builder
+
+	if p.def.Screens.Banner != nil {
+		if banner := p.def.Screens.Banner(); banner != "" {
+			b.WriteString(banner)
+			b.WriteString("\n\n")
+		}
+	}
+
+	// Main content based on state; the changes replace a result screen
+	switch status := p.status(); {
+	case p.showChanges:
+		b.WriteString(p.renderChanges())
+	case status == phaseLoading:
+		b.WriteString(p.def.Screens.Loading())
+	case status == phaseConfirm:
+		b.WriteString(p.def.Screens.Confirmation())
+	case status == phaseNothingToDo:
+		b.WriteString(p.def.Screens.NothingToDo())
+	case status == phaseError:
+		b.WriteString(p.renderError())
+	case status == phaseComplete:
+		b.WriteString(p.def.Screens.Complete())
+	case status == phaseRunning:
+		b.WriteString(p.renderProgress())
+	}
+
+	// Footer with help
+	b.WriteString("\n\n")
+	b.WriteString(p.renderFooter())
+
+	if p.embedded {
+		return b.String()
+	}
+
+	return styles.StyleBox.Width(min(p.width-4, 80)).Render(b.String())
+}
+
+// renderProgress renders the progress view during operations
+func (p *PhaseModel[S, P]) renderProgress() string {
+	var b strings.Builder
+
+	// Elapsed time, and the estimated scale time left once it is known
+	elapsed := fmt.Sprintf("Elapsed: %s", p.elapsedTime.Round(time.Second))
+	if p.etaKnown {
+		elapsed += fmt.Sprintf(" · ETA: ~%s", p.eta.Round(time.Second))
+	}
+	b.WriteString(styles.StyleSubtle.Render(elapsed))
+	b.WriteString("\n\n")
+
+	// Overall progress, weighted by stage and split across the workloads scaled
+	p.progress.SetWidth(min(max(p.width-8, 20), 60))
+	b.WriteString(p.progress.Render())
+	b.WriteString("\n\n")
+
+	// Status list (includes deployment progress inline)
+	b.WriteString(p.statusList.Render())
+
+	return b.String()
+}
+
+// renderError renders the error state
+func (p *PhaseModel[S, P]) renderError() string {
+	var b strings.Builder
+
+	b.WriteString(styles.StyleError.Render(fmt.Sprintf("%s Error", styles.IconCross)))
+	b.WriteString("\n\n")
+
+	if p.lastError != nil {
+		var multi *crookerrors.MultiError
+		if errors.As(p.lastError, &multi) {
+			b.WriteString(renderItemResults(multi))
+		} else {
+			b.WriteString(styles.StyleError.Render(p.lastError.Error()))
+		}
+		if hint := crookerrors.Hint(p.lastError); hint != "" {
+			b.WriteString("\n\n")
+			b.WriteString(styles.StyleWarning.Render(fmt.Sprintf("%s %s", styles.IconArrow, hint)))
+		}
+	}
+
+	if p.resumable() {
+		b.WriteString("\n\n")
+		b.WriteString(styles.StyleSubtle.Render(fmt.Sprintf("Retry failed continues at %q without repeating the completed steps.", p.lastStage)))
+	}
+
+	b.WriteString("\n\n")
+	b.WriteString(styles.StyleSubtle.Render("The cluster may be in a partial state."))
+	b.WriteString("\n")
+	b.WriteString(styles.StyleSubtle.Render("Review the error and decide how to proceed."))
+
+	return b.String()
+}
+
+// flowChanges returns the changes the flow's client made since the flow opened
+func (p *PhaseModel[S, P]) flowChanges() []k8s.Change {
+	if p.def.Client == nil {
+		return nil
+	}
+	return p.def.Client.ChangesSince(p.changeMark)
+}
+
+// renderChanges renders the changes the flow made as inline diffs of the
+// changed fields, oldest first
+func (p *PhaseModel[S, P]) renderChanges() string {
+	changes := p.flowChanges()
+	lines := []string{
+		styles.StyleHeading.Render(fmt.Sprintf("Changes made by crook (%d)", len(changes))),
+	}
+	for _, c := range changes {
+		lines = append(lines,
+			"",
+			styles.StyleHighlight.Render(c.Object())+styles.StyleSubtle.Render("  "+c.Time.Local().Format(time.TimeOnly)),
+			styles.StyleError.Render(fmt.Sprintf("- %s: %s", c.Field, c.Before)),
+			styles.StyleSuccess.Render(fmt.Sprintf("+ %s: %s", c.Field, c.After)),
+		)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderItemResults lists the outcome of every item of a failed batch: the
+// done ones, the failed ones with their reason, and those not attempted
+func renderItemResults(multi *crookerrors.MultiError) string {
+	failed := len(multi.Failed())
+	summary := fmt.Sprintf("%d of %d failed to %s", failed, len(multi.Items), multi.Op)
+	if failed == 0 {
+		summary = fmt.Sprintf("%s stopped: %v", multi.Op, multi.Cause)
+	}
+
+	lines := []string{styles.StyleError.Render(summary), ""}
+	for _, r := range multi.Items {
+		switch {
+		case r.Err != nil:
+			lines = append(lines, styles.StyleError.Render(fmt.Sprintf("%s %v", styles.IconCross, r.Err)))
+		case r.Skipped:
+			lines = append(lines, styles.StyleSubtle.Render(fmt.Sprintf("- %s: not attempted", r.Item)))
+		default:
+			lines = append(lines, styles.StyleSuccess.Render(fmt.Sprintf("%s %s", styles.IconCheckmark, r.Item)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderFooter renders context-sensitive help
+func (p *PhaseModel[S, P]) renderFooter() string {
+	p.updateKeyBindings()
+	p.helpModel.SetWidth(p.width)
+	return p.helpModel.View(&p.keyBindings)
+}
+
+// SetSize implements SubModel
+func (p *PhaseModel[S, P]) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+// FlowKeyMap returns the flow keybindings for status bar help model
+func (p *PhaseModel[S, P]) FlowKeyMap() help.KeyMap {
+	p.updateKeyBindings()
+	return &p.keyBindings
+}
@@ -0,0 +1,413 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	crookerrors "github.com/andri/crook/pkg/errors"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/tui/components"
+	"github.com/andri/crook/pkg/tui/styles"
+	"github.com/charmbracelet/x/ansi"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+type testPhaseState int
+
+const (
+	testStateInit testPhaseState = iota
+	testStateConfirm
+	testStateNothingToDo
+	testStateFirst
+	testStateSecond
+	testStateComplete
+	testStateError
+)
+
+type testPhaseTickMsg struct{}
+
+type testPhaseExitMsg struct {
+	reason FlowExitReason
+	err    error
+}
+
+func newTestPhaseModel(t *testing.T) *PhaseModel[testPhaseState, int] {
+	t.Helper()
+	return newTestPhaseModelWithClient(t, nil)
+}
+
+func newTestPhaseModelWithClient(t *testing.T, client *k8s.Client) *PhaseModel[testPhaseState, int] {
+	t.Helper()
+	screen := func() string { return "" }
+	def := PhaseDefinition[testPhaseState, int]{
+		Name:            "Test",
+		ConfirmQuestion: "Proceed?",
+		States: PhaseStates[testPhaseState]{
+			Init:        testStateInit,
+			Confirm:     testStateConfirm,
+			NothingToDo: testStateNothingToDo,
+			Complete:    testStateComplete,
+			Error:       testStateError,
+		},
+		Stages: []PhaseStage[testPhaseState]{
+			{State: testStateFirst, Label: "First", Progress: []string{"first"}},
+			{State: testStateSecond, Label: "Second", Progress: []string{"second", "second-retry"}},
+		},
+		Runner: newTestRunner(),
+		Execute: func() func(context.Context, func(int)) tea.Msg {
+			return func(context.Context, func(int)) tea.Msg { return testResultMsg{} }
+		},
+		Resumable: true,
+		TickMsg:   testPhaseTickMsg{},
+		Client:    client,
+		ExitMsg: func(reason FlowExitReason, err error) tea.Msg {
+			return testPhaseExitMsg{reason: reason, err: err}
+		},
+		Screens: PhaseScreens{Loading: screen, Confirmation: screen, NothingToDo: screen, Complete: screen},
+	}
+	model, ctx := NewPhaseModel(t.Context(), def, FlowExitMessage, true)
+	if ctx == t.Context() {
+		t.Fatal("NewPhaseModel() should derive an abortable context")
+	}
+	t.Cleanup(model.Abort)
+	return &model
+}
+
+func TestPhaseModel_Advance(t *testing.T) {
+	tests := []struct {
+		name      string
+		stages    []string
+		wantState testPhaseState
+		wantTypes []components.StatusType
+	}{
+		{
+			name:      "first stage",
+			stages:    []string{"first"},
+			wantState: testStateFirst,
+			wantTypes: []components.StatusType{components.StatusTypeRunning, components.StatusTypePending},
+		},
+		{
+			name:      "alias of second stage",
+			stages:    []string{"first", "second-retry"},
+			wantState: testStateSecond,
+			wantTypes: []components.StatusType{components.StatusTypeSuccess, components.StatusTypeRunning},
+		},
+		{
+			name:      "complete",
+			stages:    []string{"first", "second", phaseCompleteStage},
+			wantState: testStateSecond,
+			wantTypes: []components.StatusType{components.StatusTypeSuccess, components.StatusTypeSuccess},
+		},
+		{
+			name:      "unknown stage is ignored",
+			stages:    []string{"first", "unknown"},
+			wantState: testStateFirst,
+			wantTypes: []components.StatusType{components.StatusTypeRunning, components.StatusTypePending},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newTestPhaseModel(t)
+			model.startExecution()
+			for _, stage := range tt.stages {
+				model.advance(stage)
+			}
+
+			if model.state != tt.wantState {
+				t.Errorf("state = %v, want %v", model.state, tt.wantState)
+			}
+			for i, want := range tt.wantTypes {
+				if got := model.statusList.Get(i).Type; got != want {
+					t.Errorf("stage %d status = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPhaseModel_ExitKeys(t *testing.T) {
+	enter := tea.KeyPressMsg{Code: tea.KeyEnter}
+	quit := tea.KeyPressMsg{Code: 'q', Text: "q"}
+	interrupt := tea.KeyPressMsg{Code: 'c', Mod: tea.ModCtrl}
+
+	tests := []struct {
+		name       string
+		state      testPhaseState
+		key        tea.KeyPressMsg
+		wantExit   bool
+		wantReason FlowExitReason
+	}{
+		{name: "complete exits", state: testStateComplete, key: enter, wantExit: true, wantReason: FlowExitCompleted},
+		{name: "nothing to do exits", state: testStateNothingToDo, key: enter, wantExit: true, wantReason: FlowExitNothingToDo},
+		{name: "error quits", state: testStateError, key: quit, wantExit: true, wantReason: FlowExitError},
+		{name: "running interrupts", state: testStateSecond, key: interrupt, wantExit: true, wantReason: FlowExitCancelled},
+		{name: "confirm defers to prompt", state: testStateConfirm, key: quit},
+		{name: "loading ignores exit", state: testStateInit, key: enter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newTestPhaseModel(t)
+			model.state = tt.state
+
+			cmd := model.handleKeyPress(tt.key)
+			if !tt.wantExit {
+				if cmd != nil {
+					t.Errorf("handleKeyPress() = %T, want nil", cmd())
+				}
+				return
+			}
+			if cmd == nil {
+				t.Fatal("handleKeyPress() = nil, want exit command")
+			}
+			exit, ok := cmd().(testPhaseExitMsg)
+			if !ok {
+				t.Fatalf("exit command returned %T, want testPhaseExitMsg", cmd())
+			}
+			if exit.reason != tt.wantReason {
+				t.Errorf("exit reason = %v, want %v", exit.reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestPhaseModel_RetryFailed(t *testing.T) {
+	retryFailed := tea.KeyPressMsg{Code: 'f', Text: "f"}
+	retry := tea.KeyPressMsg{Code: 'r', Text: "r"}
+
+	multi := &crookerrors.MultiError{Op: "scale down"}
+	multi.Succeed("deployment ns/a", "ns/a")
+	multi.Fail("deployment ns/b", "ns/b", errors.New("conflict"))
+
+	tests := []struct {
+		name       string
+		stages     []string
+		err        error
+		key        tea.KeyPressMsg
+		wantResume phaseResume
+		wantStart  bool
+	}{
+		{
+			name:       "failed items resume at their stage",
+			stages:     []string{"first", "second"},
+			err:        multi,
+			key:        retryFailed,
+			wantResume: phaseResume{from: "second", only: []string{"ns/b"}},
+			wantStart:  true,
+		},
+		{
+			name:       "failed stage resumes without an item subset",
+			stages:     []string{"first", "second-retry"},
+			err:        errors.New("timeout"),
+			key:        retryFailed,
+			wantResume: phaseResume{from: "second-retry"},
+			wantStart:  true,
+		},
+		{name: "failure in the first stage cannot resume", stages: []string{"first"}, err: multi, key: retryFailed},
+		{name: "full retry starts over", stages: []string{"first", "second"}, err: multi, key: retry, wantStart: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newTestPhaseModel(t)
+			model.startExecution()
+			for _, stage := range tt.stages {
+				model.advance(stage)
+			}
+			model.fail(tt.err)
+
+			cmd := model.handleKeyPress(tt.key)
+			if (cmd != nil) != tt.wantStart {
+				t.Fatalf("handleKeyPress() started = %v, want %v", cmd != nil, tt.wantStart)
+			}
+			if model.resume.from != tt.wantResume.from || !slices.Equal(model.resume.only, tt.wantResume.only) {
+				t.Errorf("resume = %+v, want %+v", model.resume, tt.wantResume)
+			}
+			if !tt.wantStart {
+				return
+			}
+
+			// A resumed run shows the stages before the failed one as done
+			wantFirst := components.StatusTypePending
+			if tt.wantResume.from != "" {
+				wantFirst = components.StatusTypeSuccess
+			}
+			if got := model.statusList.Get(0).Type; got != wantFirst {
+				t.Errorf("first stage status = %v, want %v", got, wantFirst)
+			}
+		})
+	}
+}
+
+func TestPhaseModel_RecordCheck(t *testing.T) {
+	model := newTestPhaseModel(t)
+	model.startExecution()
+
+	model.recordCheck(maintenance.CheckStatus{Name: "Namespace", Passed: true, Done: 1, Total: 3})
+	model.recordCheck(maintenance.CheckStatus{Name: "RBAC permissions", Passed: false, Done: 2, Total: 3})
+
+	item := model.statusList.Get(0)
+	if item.Label != "First (2/3)" {
+		t.Errorf("label = %q, want the checks finished so far", item.Label)
+	}
+	if !strings.Contains(item.Details, "Namespace") || !strings.Contains(item.Details, "RBAC permissions") {
+		t.Errorf("details = %q, want both finished checks", item.Details)
+	}
+
+	// A clean run collapses the list once the last check finished
+	model.startExecution()
+	for i, name := range []string{"Namespace", "Node existence"} {
+		model.recordCheck(maintenance.CheckStatus{Name: name, Passed: true, Done: i + 1, Total: 2})
+	}
+	if item := model.statusList.Get(0); item.Details != "" {
+		t.Errorf("details = %q, want none after a clean run", item.Details)
+	}
+}
+
+func TestPhaseModel_RenderErrorHint(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantHint string
+	}{
+		{name: "plain error", err: errors.New("scale failed")},
+		{name: "hinted error", err: crookerrors.NodeNotFound("worker-9"), wantHint: "crook nodes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newTestPhaseModel(t)
+			model.fail(tt.err)
+
+			view := model.renderError()
+			if !strings.Contains(view, tt.err.Error()) {
+				t.Errorf("renderError() should show the error, got %q", view)
+			}
+			hasHint := strings.Contains(view, styles.IconArrow)
+			if hasHint != (tt.wantHint != "") {
+				t.Errorf("renderError() hint shown = %v, want %v", hasHint, tt.wantHint != "")
+			}
+			if tt.wantHint != "" && !strings.Contains(view, tt.wantHint) {
+				t.Errorf("renderError() = %q, want hint containing %q", view, tt.wantHint)
+			}
+		})
+	}
+}
+
+func TestPhaseModel_RenderErrorItems(t *testing.T) {
+	multi := &crookerrors.MultiError{Op: "scale down"}
+	multi.Succeed("deployment rook-ceph/osd-0", "rook-ceph/osd-0")
+	multi.Fail("deployment rook-ceph/osd-1", "rook-ceph/osd-1", errors.New("failed to scale deployment rook-ceph/osd-1 to 0: conflict"))
+	multi.Skip("deployment rook-ceph/osd-2", "rook-ceph/osd-2", context.Canceled)
+
+	model := newTestPhaseModel(t)
+	model.fail(fmt.Errorf("down phase failed: %w", multi))
+
+	view := model.renderError()
+	for _, want := range []string{
+		"1 of 3 failed to scale down",
+		styles.IconCheckmark + " deployment rook-ceph/osd-0",
+		styles.IconCross + " failed to scale deployment rook-ceph/osd-1 to 0: conflict",
+		"deployment rook-ceph/osd-2: not attempted",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("renderError() = %q, want it to contain %q", view, want)
+		}
+	}
+}
+
+func TestPhaseModel_Acknowledge(t *testing.T) {
+	acknowledge := tea.KeyPressMsg{Code: 'a', Text: "a"}
+	retry := tea.KeyPressMsg{Code: 'r', Text: "r"}
+
+	warned := fmt.Errorf("%w: %w", maintenance.ErrValidationFailed, maintenance.ErrAcknowledgmentRequired)
+
+	model := newTestPhaseModel(t)
+	model.startExecution()
+	model.fail(errors.New("node not found"))
+	if cmd := model.handleKeyPress(acknowledge); cmd != nil || model.acknowledged {
+		t.Fatal("acknowledge should be disabled for blocking failures")
+	}
+
+	model.startExecution()
+	model.fail(warned)
+	if cmd := model.handleKeyPress(acknowledge); cmd == nil || !model.acknowledged {
+		t.Fatal("acknowledge should rerun the phase past the warnings")
+	}
+
+	// A full retry asks again
+	model.fail(warned)
+	if cmd := model.handleKeyPress(retry); cmd == nil || model.acknowledged {
+		t.Error("retry should drop the acknowledgment")
+	}
+}
+
+// newScalingClient returns a client whose deployments all scale from 1 replica
+func newScalingClient() *k8s.Client {
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("get", "deployments/scale", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		get := action.(k8stesting.GetAction)
+		return true, &autoscalingv1.Scale{
+			ObjectMeta: metav1.ObjectMeta{Name: get.GetName(), Namespace: get.GetNamespace()},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: 1},
+		}, nil
+	})
+	clientset.PrependReactor("update", "deployments/scale", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, action.(k8stesting.UpdateAction).GetObject(), nil
+	})
+	return &k8s.Client{Clientset: clientset}
+}
+
+func TestPhaseModel_Changes(t *testing.T) {
+	client := newScalingClient()
+	ctx := context.Background()
+	// Changes made before the flow opened are not the flow's
+	if err := client.ScaleDeployment(ctx, "rook-ceph", "rook-ceph-mgr-a", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	model := newTestPhaseModelWithClient(t, client)
+	changesKey := tea.KeyPressMsg{Code: 'd', Text: "d"}
+	model.startExecution()
+	model.complete()
+	model.handleKeyPress(changesKey)
+	if model.showChanges {
+		t.Fatal("changes key should be disabled when the flow changed nothing")
+	}
+
+	model.startExecution()
+	if err := client.ScaleDeployment(ctx, "rook-ceph", "rook-ceph-osd-3", 0); err != nil {
+		t.Fatal(err)
+	}
+	model.fail(errors.New("boom"))
+	model.handleKeyPress(changesKey)
+	if !model.showChanges {
+		t.Fatal("changes key should show the changes on the error screen")
+	}
+
+	view := ansi.Strip(model.Render())
+	for _, want := range []string{"Changes made by crook (1)", "Deployment rook-ceph/rook-ceph-osd-3", "- spec.replicas: 1", "+ spec.replicas: 0"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "rook-ceph-mgr-a") {
+		t.Errorf("view lists a change made before the flow opened:\n%s", view)
+	}
+
+	model.handleKeyPress(changesKey)
+	if view := ansi.Strip(model.Render()); !strings.Contains(view, "boom") {
+		t.Errorf("changes key should toggle back to the error:\n%s", view)
+	}
+}
@@ -0,0 +1,253 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andri/crook/pkg/tui/styles"
+)
+
+// QueueEntryStatus is the progress of a node through the maintenance queue
+type QueueEntryStatus int
+
+const (
+	// QueueEntryQueued waits for its turn
+	QueueEntryQueued QueueEntryStatus = iota
+	// QueueEntryGoingDown runs the down phase
+	QueueEntryGoingDown
+	// QueueEntryInMaintenance is down and waits for the operator to restore it
+	QueueEntryInMaintenance
+	// QueueEntryComingUp runs the up phase
+	QueueEntryComingUp
+	// QueueEntryDone has been restored
+	QueueEntryDone
+)
+
+// String returns the human-readable name for the status
+func (s QueueEntryStatus) String() string {
+	switch s {
+	case QueueEntryQueued:
+		return "queued"
+	case QueueEntryGoingDown:
+		return "going down"
+	case QueueEntryInMaintenance:
+		return "in maintenance"
+	case QueueEntryComingUp:
+		return "coming up"
+	case QueueEntryDone:
+		return "done"
+	default:
+		return "unknown"
+	}
+}
+
+// QueueEntry is a node in the maintenance queue
+type QueueEntry struct {
+	Node   string
+	Status QueueEntryStatus
+}
+
+// MaintenanceQueue orders nodes for rolling maintenance: one node at a time
+// goes down, is restored, and then the next queued node starts. Queued nodes
+// can be reordered or removed until they start.
+type MaintenanceQueue struct {
+	entries []QueueEntry
+	running bool
+}
+
+// NewMaintenanceQueue creates an empty queue
+func NewMaintenanceQueue() *MaintenanceQueue {
+	return &MaintenanceQueue{}
+}
+
+// Len returns the number of entries, including finished ones
+func (q *MaintenanceQueue) Len() int {
+	return len(q.entries)
+}
+
+// Entries returns a copy of the entries in queue order
+func (q *MaintenanceQueue) Entries() []QueueEntry {
+	return append([]QueueEntry(nil), q.entries...)
+}
+
+// Running reports whether the queue starts the next node on its own
+func (q *MaintenanceQueue) Running() bool {
+	return q.running
+}
+
+// index returns the position of node, or -1
+func (q *MaintenanceQueue) index(node string) int {
+	for i := range q.entries {
+		if q.entries[i].Node == node {
+			return i
+		}
+	}
+	return -1
+}
+
+// Toggle adds node to the end of the queue, or removes it if it has not
+// started yet. It reports whether node is in the queue afterwards.
+func (q *MaintenanceQueue) Toggle(node string) bool {
+	i := q.index(node)
+	if i < 0 {
+		q.entries = append(q.entries, QueueEntry{Node: node})
+		return true
+	}
+	if q.entries[i].Status != QueueEntryQueued {
+		return true
+	}
+	q.entries = append(q.entries[:i], q.entries[i+1:]...)
+	return false
+}
+
+// Move shifts a queued node by delta positions among the queued nodes.
+// Started and finished nodes keep their place.
+func (q *MaintenanceQueue) Move(node string, delta int) bool {
+	i := q.index(node)
+	if i < 0 || q.entries[i].Status != QueueEntryQueued {
+		return false
+	}
+	j := i + delta
+	if j < 0 || j >= len(q.entries) || q.entries[j].Status != QueueEntryQueued {
+		return false
+	}
+	q.entries[i], q.entries[j] = q.entries[j], q.entries[i]
+	return true
+}
+
+// Current returns the node in maintenance, if any
+func (q *MaintenanceQueue) Current() (QueueEntry, bool) {
+	for _, entry := range q.entries {
+		switch entry.Status { //nolint:exhaustive // queued and done nodes are not current
+		case QueueEntryGoingDown, QueueEntryInMaintenance, QueueEntryComingUp:
+			return entry, true
+		}
+	}
+	return QueueEntry{}, false
+}
+
+// next returns the first queued node
+func (q *MaintenanceQueue) next() (string, bool) {
+	for _, entry := range q.entries {
+		if entry.Status == QueueEntryQueued {
+			return entry.Node, true
+		}
+	}
+	return "", false
+}
+
+// Start resumes the queue. It returns the node whose down phase should start
+// now, which is none while another node is in maintenance.
+func (q *MaintenanceQueue) Start() (string, bool) {
+	q.running = true
+	if _, busy := q.Current(); busy {
+		return "", false
+	}
+	node, ok := q.next()
+	if !ok {
+		q.running = false
+	}
+	return node, ok
+}
+
+// Begin records that a phase flow opened for node. It returns false when
+// the flow does not advance the queue.
+func (q *MaintenanceQueue) Begin(node string, up bool) bool {
+	i := q.index(node)
+	if i < 0 {
+		return false
+	}
+	entry := &q.entries[i]
+	switch {
+	case up && entry.Status == QueueEntryInMaintenance:
+		entry.Status = QueueEntryComingUp
+	case !up && q.running && entry.Status == QueueEntryQueued:
+		if next, _ := q.next(); next != node {
+			return false
+		}
+		if _, busy := q.Current(); busy {
+			return false
+		}
+		entry.Status = QueueEntryGoingDown
+	default:
+		return false
+	}
+	return true
+}
+
+// End records the outcome of node's phase flow. A finished down phase leaves
+// the node in maintenance and a finished up phase completes it; call Start
+// afterwards to take the next node down. A down phase that does not finish
+// pauses the queue, and an up phase that does not finish can be retried.
+func (q *MaintenanceQueue) End(node string, up, succeeded bool) {
+	i := q.index(node)
+	if i < 0 {
+		return
+	}
+	entry := &q.entries[i]
+	switch entry.Status { //nolint:exhaustive // other states have no running flow
+	case QueueEntryGoingDown:
+		if !up && succeeded {
+			entry.Status = QueueEntryInMaintenance
+			return
+		}
+		entry.Status = QueueEntryQueued
+		q.running = false
+	case QueueEntryComingUp:
+		if up && succeeded {
+			entry.Status = QueueEntryDone
+		} else {
+			entry.Status = QueueEntryInMaintenance
+		}
+	}
+}
+
+// Summary returns a one-line description of the queue's progress
+func (q *MaintenanceQueue) Summary() string {
+	done := 0
+	for _, entry := range q.entries {
+		if entry.Status == QueueEntryDone {
+			done++
+		}
+	}
+	summary := fmt.Sprintf("Queue %d/%d done", done, len(q.entries))
+	if next, ok := q.next(); ok {
+		summary += " · next " + next
+	}
+	if !q.running {
+		summary += " · paused"
+	}
+	return summary
+}
+
+// Render renders the queue with each node's position and status
+func (q *MaintenanceQueue) Render() string {
+	var b strings.Builder
+
+	state := "paused"
+	if q.running {
+		state = "running"
+	}
+	b.WriteString(styles.StyleStatus.Render("Maintenance queue"))
+	b.WriteString(styles.StyleSubtle.Render(" (" + state + ")"))
+
+	position := 0
+	for _, entry := range q.entries {
+		b.WriteString("\n")
+		switch entry.Status {
+		case QueueEntryDone:
+			b.WriteString(styles.StyleSuccess.Render(styles.IconCheckmark + " " + entry.Node))
+		case QueueEntryGoingDown, QueueEntryComingUp:
+			b.WriteString(styles.StyleWarning.Render(styles.IconSpinner + " " + entry.Node))
+			b.WriteString(styles.StyleSubtle.Render(" " + entry.Status.String()))
+		case QueueEntryInMaintenance:
+			b.WriteString(styles.StyleWarning.Render(styles.IconMaintenance + " " + entry.Node))
+			b.WriteString(styles.StyleSubtle.Render(" in maintenance · u to restore"))
+		case QueueEntryQueued:
+			position++
+			b.WriteString(styles.StyleNormal.Render(fmt.Sprintf("%d %s", position, entry.Node)))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func queueNodes(q *MaintenanceQueue) []string {
+	var nodes []string
+	for _, entry := range q.Entries() {
+		nodes = append(nodes, entry.Node)
+	}
+	return nodes
+}
+
+func TestMaintenanceQueue_ToggleAndMove(t *testing.T) {
+	tests := []struct {
+		name  string
+		edit  func(q *MaintenanceQueue)
+		want  []string
+		moved bool
+	}{
+		{
+			name: "toggle removes queued node",
+			edit: func(q *MaintenanceQueue) { q.Toggle("node-b") },
+			want: []string{"node-a", "node-c"},
+		},
+		{
+			name: "move earlier",
+			edit: func(q *MaintenanceQueue) { q.Move("node-c", -1) },
+			want: []string{"node-a", "node-c", "node-b"},
+		},
+		{
+			name: "move past the end is ignored",
+			edit: func(q *MaintenanceQueue) { q.Move("node-c", 1) },
+			want: []string{"node-a", "node-b", "node-c"},
+		},
+		{
+			name: "started node is not removed",
+			edit: func(q *MaintenanceQueue) {
+				q.Start()
+				q.Begin("node-a", false)
+				q.Toggle("node-a")
+			},
+			want: []string{"node-a", "node-b", "node-c"},
+		},
+		{
+			name: "queued node does not move before a started one",
+			edit: func(q *MaintenanceQueue) {
+				q.Start()
+				q.Begin("node-a", false)
+				q.Move("node-b", -1)
+			},
+			want: []string{"node-a", "node-b", "node-c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewMaintenanceQueue()
+			for _, node := range []string{"node-a", "node-b", "node-c"} {
+				q.Toggle(node)
+			}
+			tt.edit(q)
+			if got := queueNodes(q); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("queue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceQueue_RollingMaintenance(t *testing.T) {
+	q := NewMaintenanceQueue()
+	q.Toggle("node-a")
+	q.Toggle("node-b")
+
+	if q.Begin("node-a", false) {
+		t.Error("Begin() should not track a down flow before the queue starts")
+	}
+	node, ok := q.Start()
+	if !ok || node != "node-a" {
+		t.Fatalf("Start() = %q, %v, want node-a", node, ok)
+	}
+	if !q.Begin("node-a", false) {
+		t.Fatal("Begin() should track the next node's down flow")
+	}
+	if _, ok := q.Start(); ok {
+		t.Error("Start() should not return a node while one is going down")
+	}
+
+	q.End("node-a", false, true)
+	if current, _ := q.Current(); current.Status != QueueEntryInMaintenance {
+		t.Errorf("after down, status = %v, want %v", current.Status, QueueEntryInMaintenance)
+	}
+	if _, ok := q.Start(); ok {
+		t.Error("Start() should wait until the node in maintenance is restored")
+	}
+
+	q.Begin("node-a", true)
+	q.End("node-a", true, false)
+	if current, _ := q.Current(); current.Status != QueueEntryInMaintenance {
+		t.Errorf("after failed up, status = %v, want %v", current.Status, QueueEntryInMaintenance)
+	}
+
+	q.Begin("node-a", true)
+	q.End("node-a", true, true)
+	node, ok = q.Start()
+	if !ok || node != "node-b" {
+		t.Fatalf("Start() after restore = %q, %v, want node-b", node, ok)
+	}
+
+	q.Begin("node-b", false)
+	q.End("node-b", false, false)
+	if q.Running() {
+		t.Error("a down flow that does not finish should pause the queue")
+	}
+	if got := q.Entries()[1].Status; got != QueueEntryQueued {
+		t.Errorf("node-b status = %v, want %v", got, QueueEntryQueued)
+	}
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"strconv"
+
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/monitoring"
+)
+
+// relatedKeys are the identities (see monitoring.NodeKey, DeploymentKey and
+// OSDKey) of the rows to highlight in each pane
+type relatedKeys struct {
+	nodes       []string
+	deployments []string
+	osds        []string
+}
+
+// relationIndex links nodes, deployments and OSDs by identity, so selecting a
+// row in one pane can highlight the related rows of the others: an OSD's host
+// and deployment, a deployment's node and OSD, a node's deployments and OSDs.
+// It is rebuilt from the monitor data whenever deployments or OSDs change.
+type relationIndex struct {
+	deployments []k8s.DeploymentInfo
+	osds        []k8s.OSDInfo
+
+	deploymentNode  map[string]string   // deployment key -> node name
+	deploymentOSD   map[string]string   // deployment key -> OSD key
+	osdNode         map[string]string   // OSD key -> CRUSH host
+	osdDeployment   map[string]string   // OSD key -> deployment key
+	nodeDeployments map[string][]string // node name -> deployment keys
+	nodeOSDs        map[string][]string // node name -> OSD keys
+}
+
+// update replaces the deployments and OSDs that are not nil and rebuilds the
+// index
+func (r *relationIndex) update(deployments []k8s.DeploymentInfo, osds []k8s.OSDInfo) {
+	if deployments != nil {
+		r.deployments = deployments
+	}
+	if osds != nil {
+		r.osds = osds
+	}
+
+	r.deploymentNode = make(map[string]string, len(r.deployments))
+	r.deploymentOSD = make(map[string]string)
+	r.osdNode = make(map[string]string, len(r.osds))
+	r.osdDeployment = make(map[string]string, len(r.osds))
+	r.nodeDeployments = make(map[string][]string)
+	r.nodeOSDs = make(map[string][]string)
+
+	// OSD deployments are found by their ceph-osd-id label, else by the
+	// deployment name Rook gives them
+	osdByID := make(map[string]string, len(r.osds))
+	osdByDeployment := make(map[string]string, len(r.osds))
+	for _, osd := range r.osds {
+		key := monitoring.OSDKey(osd)
+		osdByID[strconv.Itoa(osd.ID)] = key
+		if osd.DeploymentName != "" {
+			osdByDeployment[osd.DeploymentName] = key
+		}
+		if osd.Hostname != "" {
+			r.osdNode[key] = osd.Hostname
+			r.nodeOSDs[osd.Hostname] = append(r.nodeOSDs[osd.Hostname], key)
+		}
+	}
+
+	for _, dep := range r.deployments {
+		key := monitoring.DeploymentKey(dep)
+		if dep.NodeName != "" {
+			r.deploymentNode[key] = dep.NodeName
+			r.nodeDeployments[dep.NodeName] = append(r.nodeDeployments[dep.NodeName], key)
+		}
+		osdKey, ok := osdByID[dep.OsdID]
+		if !ok {
+			osdKey, ok = osdByDeployment[dep.Name]
+		}
+		if ok {
+			r.deploymentOSD[key] = osdKey
+			r.osdDeployment[osdKey] = key
+		}
+	}
+}
+
+// forNode returns the deployments and OSDs on a node
+func (r *relationIndex) forNode(name string) relatedKeys {
+	return relatedKeys{
+		deployments: r.nodeDeployments[name],
+		osds:        r.nodeOSDs[name],
+	}
+}
+
+// forDeployment returns the node a deployment runs on and the OSD it runs
+func (r *relationIndex) forDeployment(key string) relatedKeys {
+	return relatedKeys{
+		nodes: nonEmpty(r.deploymentNode[key]),
+		osds:  nonEmpty(r.deploymentOSD[key]),
+	}
+}
+
+// forOSD returns the host of an OSD and the deployment running it
+func (r *relationIndex) forOSD(key string) relatedKeys {
+	return relatedKeys{
+		nodes:       nonEmpty(r.osdNode[key]),
+		deployments: nonEmpty(r.osdDeployment[key]),
+	}
+}
+
+// nonEmpty returns s as a one-element list, or nil when it is empty
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func TestRelationIndex(t *testing.T) {
+	var r relationIndex
+	r.update(
+		[]k8s.DeploymentInfo{
+			{Namespace: "rook-ceph", Name: "rook-ceph-osd-0", NodeName: "worker-1", OsdID: "0"},
+			{Namespace: "rook-ceph", Name: "rook-ceph-osd-1", NodeName: "worker-2"},
+			{Namespace: "rook-ceph", Name: "rook-ceph-mon-a", NodeName: "worker-1"},
+		},
+		[]k8s.OSDInfo{
+			{ID: 0, Hostname: "worker-1"},
+			{ID: 1, Hostname: "worker-2", DeploymentName: "rook-ceph-osd-1"},
+		},
+	)
+
+	tests := []struct {
+		name string
+		got  relatedKeys
+		want relatedKeys
+	}{
+		{
+			name: "node",
+			got:  r.forNode("worker-1"),
+			want: relatedKeys{deployments: []string{"rook-ceph/rook-ceph-osd-0", "rook-ceph/rook-ceph-mon-a"}, osds: []string{"0"}},
+		},
+		{
+			name: "OSD deployment by label",
+			got:  r.forDeployment("rook-ceph/rook-ceph-osd-0"),
+			want: relatedKeys{nodes: []string{"worker-1"}, osds: []string{"0"}},
+		},
+		{
+			name: "non-OSD deployment",
+			got:  r.forDeployment("rook-ceph/rook-ceph-mon-a"),
+			want: relatedKeys{nodes: []string{"worker-1"}},
+		},
+		{
+			name: "OSD by deployment name",
+			got:  r.forOSD("1"),
+			want: relatedKeys{nodes: []string{"worker-2"}, deployments: []string{"rook-ceph/rook-ceph-osd-1"}},
+		},
+		{
+			name: "unknown node",
+			got:  r.forNode("worker-9"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("related = %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+
+	// A refresh carrying only OSDs keeps the known deployments
+	r.update(nil, []k8s.OSDInfo{{ID: 0, Hostname: "worker-3"}})
+	if got := r.forOSD("0"); !reflect.DeepEqual(got.deployments, []string{"rook-ceph/rook-ceph-osd-0"}) || got.nodes[0] != "worker-3" {
+		t.Errorf("forOSD(0) after OSD refresh = %+v", got)
+	}
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andri/crook/pkg/tui/components"
+)
+
+// focusOwner is the part of the ls view a message is delivered to. Every
+// message has exactly one owner, so a key never drives both the ls view and
+// an embedded flow, and one monitor's ticks never reach another's handler.
+type focusOwner int
+
+const (
+	// focusNone consumes the message without effect, e.g. a flow key pressed
+	// on a pane other than the one the flow was started from
+	focusNone focusOwner = iota
+
+	// focusUnlock lifts the idle lock
+	focusUnlock
+
+	// focusClusterLog opens, closes and filters the cluster log; it narrates
+	// a running flow, so it owns its keys during flows too
+	focusClusterLog
+
+	// focusLs is the ls view: panes, cursor, queue and maintenance actions
+	focusLs
+
+	// focusFlow is the embedded maintenance flow
+	focusFlow
+)
+
+// String returns the owner name for test failures and debugging
+func (o focusOwner) String() string {
+	switch o {
+	case focusUnlock:
+		return "unlock"
+	case focusClusterLog:
+		return "cluster-log"
+	case focusLs:
+		return "ls"
+	case focusFlow:
+		return "flow"
+	default:
+		return "none"
+	}
+}
+
+// keyOwner decides which single owner handles a key press. It has no side
+// effects; call updateKeyBindings first so the bindings reflect the state.
+func (m *LsModel) keyOwner(msg tea.KeyMsg) focusOwner {
+	switch {
+	case key.Matches(msg, m.keyMap.Unlock):
+		return focusUnlock
+	case key.Matches(msg, m.keyMap.ClusterLog, m.keyMap.LogLevel):
+		return focusClusterLog
+	case m.maintenanceFlow == nil:
+		return focusLs
+	case m.keyMap.IsNavigationKey(msg):
+		// Navigation keys (Tab, 1-3, [ ], j/k/up/down, F, w) stay with ls
+		return focusLs
+	case m.keyMap.IsQueueKey(msg):
+		// Queue keys edit the queue while a node is in maintenance
+		return focusLs
+	case m.activePane == m.flowPane && !m.idle.locked:
+		// Other keys drive the flow only from the pane it was started from,
+		// and not while the idle lock holds
+		return focusFlow
+	default:
+		return focusNone
+	}
+}
+
+// messageOwner decides which single owner handles a message other than a
+// key press: the ls view's own messages stay with it and everything else,
+// such as a flow's ticks and progress, belongs to the embedded flow
+func (m *LsModel) messageOwner(msg tea.Msg) focusOwner {
+	switch msg.(type) {
+	case tea.WindowSizeMsg:
+		// The ls layout sizes an embedded flow to its pane
+		return focusLs
+	case components.TabSwitchMsg, LsDataUpdateMsg, LsRefreshMsg,
+		LsMonitorStartFailedMsg, LsMonitorStartedMsg, LsMonitorUpdateMsg,
+		LsMonitorClosedMsg, LsClockTickMsg:
+		return focusLs
+	case ClusterLogMsg, ClusterLogTickMsg:
+		return focusClusterLog
+	}
+	if m.maintenanceFlow == nil {
+		return focusNone
+	}
+	return focusFlow
+}
+
+// routeKey delivers a key press to its owner. The abort gesture and the
+// idle lock observe every key first without taking it from its owner.
+func (m *LsModel) routeKey(msg tea.KeyMsg) tea.Cmd {
+	if m.abort.observe(msg) {
+		return m.shutdown()
+	}
+	m.idle.input()
+	m.updateKeyBindings()
+
+	switch m.keyOwner(msg) {
+	case focusUnlock:
+		m.idle.unlock()
+		return nil
+	case focusClusterLog:
+		cmd, _ := m.handleClusterLogKey(msg)
+		return cmd
+	case focusLs:
+		return m.handleKeyPress(msg)
+	case focusFlow:
+		return m.updateFlow(msg)
+	default:
+		return nil
+	}
+}
+
+// updateFlow delivers a message to the embedded flow
+func (m *LsModel) updateFlow(msg tea.Msg) tea.Cmd {
+	updatedFlow, cmd := m.maintenanceFlow.Update(msg)
+	if flow, isFlow := updatedFlow.(sizedModel); isFlow {
+		m.maintenanceFlow = flow
+	}
+	return cmd
+}
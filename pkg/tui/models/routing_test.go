@@ -0,0 +1,151 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"charm.land/bubbles/v2/help"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// recordingFlow is an embedded flow that records the messages it receives
+type recordingFlow struct {
+	received []tea.Msg
+}
+
+func (f *recordingFlow) Init() tea.Cmd { return nil }
+
+func (f *recordingFlow) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	f.received = append(f.received, msg)
+	return f, nil
+}
+
+func (f *recordingFlow) View() tea.View          { return tea.NewView(f.Render()) }
+func (f *recordingFlow) Render() string          { return "" }
+func (f *recordingFlow) SetSize(int, int)        {}
+func (f *recordingFlow) FlowKeyMap() help.KeyMap { return nil }
+
+// flowTickMsg stands in for the ticks and progress messages of a flow
+type flowTickMsg struct{}
+
+func keyPress(s string) tea.KeyPressMsg {
+	switch s {
+	case "tab":
+		return tea.KeyPressMsg{Code: tea.KeyTab}
+	case "esc":
+		return tea.KeyPressMsg{Code: tea.KeyEscape}
+	case "ctrl+u":
+		return tea.KeyPressMsg{Code: 'u', Mod: tea.ModCtrl}
+	default:
+		return tea.KeyPressMsg{Code: rune(s[0]), Text: s}
+	}
+}
+
+func TestLsModel_keyOwner(t *testing.T) {
+	tests := []struct {
+		name  string
+		flow  bool
+		pane  LsPane
+		setup func(m *LsModel)
+		key   string
+		want  focusOwner
+	}{
+		{name: "no flow", key: "d", want: focusLs},
+		{name: "no flow quit", key: "q", want: focusLs},
+		{name: "cluster log", key: "L", want: focusClusterLog},
+		{name: "cluster log during flow", flow: true, key: "L", want: focusClusterLog},
+		{
+			name:  "esc closes an open cluster log before a flow sees it",
+			flow:  true,
+			setup: func(m *LsModel) { m.setClusterLogOpen(true) },
+			key:   "esc",
+			want:  focusClusterLog,
+		},
+		{name: "navigation during flow", flow: true, key: "tab", want: focusLs},
+		{name: "cursor during flow", flow: true, key: "j", want: focusLs},
+		{name: "queue during flow", flow: true, key: "m", want: focusLs},
+		{name: "flow key on flow pane", flow: true, key: "y", want: focusFlow},
+		{name: "unbound key on flow pane", flow: true, key: "?", want: focusFlow},
+		{name: "esc on flow pane", flow: true, key: "esc", want: focusFlow},
+		{name: "flow key on another pane", flow: true, pane: LsPaneOSDs, key: "y", want: focusNone},
+		{
+			name:  "flow key while locked",
+			flow:  true,
+			setup: func(m *LsModel) { m.idle.locked = true },
+			key:   "y",
+			want:  focusNone,
+		},
+		{
+			name:  "unlock",
+			flow:  true,
+			setup: func(m *LsModel) { m.idle.locked = true },
+			key:   "ctrl+u",
+			want:  focusUnlock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewLsModel(LsModelConfig{Context: context.Background()})
+			if tt.flow {
+				m.maintenanceFlow = &recordingFlow{}
+				m.flowPane = LsPaneNodes
+			}
+			m.setActivePane(tt.pane)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+			m.updateKeyBindings()
+			if got := m.keyOwner(keyPress(tt.key)); got != tt.want {
+				t.Errorf("keyOwner(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLsModel_Update_NoDoubleHandling(t *testing.T) {
+	m := NewLsModel(LsModelConfig{Context: context.Background()})
+	_, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.nodesView.SetNodes([]k8s.NodeInfo{{Name: "node-a"}, {Name: "node-b"}})
+	flow := &recordingFlow{}
+	m.maintenanceFlow = flow
+	m.flowPane = LsPaneNodes
+
+	// Keys and messages the ls view owns never reach the flow
+	for _, msg := range []tea.Msg{
+		keyPress("j"),
+		keyPress("m"),
+		keyPress("L"),
+		keyPress("esc"),
+		tea.WindowSizeMsg{Width: 100, Height: 30},
+		LsClockTickMsg{},
+		LsRefreshMsg{},
+	} {
+		_, _ = m.Update(msg)
+	}
+	if len(flow.received) != 0 {
+		t.Fatalf("flow received %v, want nothing", flow.received)
+	}
+	if got := m.nodesView.GetSelectedNode(); got == nil || got.Name != "node-b" {
+		t.Errorf("selected node = %v, want node-b", got)
+	}
+	if m.queue.index("node-b") < 0 {
+		t.Error("queue key should have queued the selected node")
+	}
+	if m.clusterLogOpen {
+		t.Error("esc should have closed the cluster log, not reached the flow")
+	}
+
+	// Keys and messages the flow owns reach it exactly once and leave the
+	// ls view alone
+	_, _ = m.Update(keyPress("y"))
+	_, _ = m.Update(flowTickMsg{})
+	if len(flow.received) != 2 {
+		t.Fatalf("flow received %d messages, want 2", len(flow.received))
+	}
+	if got := m.nodesView.GetSelectedNode(); got == nil || got.Name != "node-b" {
+		t.Errorf("flow keys moved the selection to %v", got)
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// titledFlow is implemented by flows that summarize their progress for the
+// terminal title
+type titledFlow interface {
+	TerminalTitle() string
+}
+
+// terminalTitle returns the title of a flow acting on target, e.g.
+// "crook: down worker-3 — scale deployments 40%"
+func (p *PhaseModel[S, P]) terminalTitle(target string) string {
+	return fmt.Sprintf("crook: %s %s — %s", strings.ToLower(p.def.Name), target, p.titleStatus())
+}
+
+// titleStatus summarizes the flow state in a few words
+func (p *PhaseModel[S, P]) titleStatus() string {
+	switch p.status() {
+	case phaseLoading:
+		return "discovering"
+	case phaseConfirm:
+		return "confirm"
+	case phaseNothingToDo:
+		return "nothing to do"
+	case phaseComplete:
+		return "complete"
+	case phaseError:
+		return "failed"
+	default:
+		label := "running"
+		for _, stage := range p.def.Stages {
+			if stage.State == p.state {
+				label = strings.ToLower(stage.Label)
+				break
+			}
+		}
+		return fmt.Sprintf("%s %d%%", label, p.percent)
+	}
+}
+
+// TerminalTitle implements titledFlow
+func (m *DownModel) TerminalTitle() string {
+	return m.terminalTitle(m.config.NodeName)
+}
+
+// TerminalTitle implements titledFlow
+func (m *UpModel) TerminalTitle() string {
+	return m.terminalTitle(m.config.NodeName)
+}
+
+// TerminalTitle implements titledFlow
+func (m *DeploymentModel) TerminalTitle() string {
+	return m.terminalTitle(m.config.Name)
+}
+
+// terminalTitle returns the terminal title: the running flow's progress, or
+// the kubeconfig context and node the view is scoped to
+func (m *LsModel) terminalTitle() string {
+	if flow, ok := m.maintenanceFlow.(titledFlow); ok {
+		return flow.TerminalTitle()
+	}
+
+	var scope []string
+	if m.config.KubeContext != "" {
+		scope = append(scope, m.config.KubeContext)
+	}
+	if m.config.NodeFilter != "" {
+		scope = append(scope, m.config.NodeFilter)
+	}
+	if len(scope) == 0 {
+		return "crook"
+	}
+	return "crook: " + strings.Join(scope, " · ")
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andri/crook/pkg/config"
+)
+
+func TestDownModel_TerminalTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(m *DownModel)
+		want  string
+	}{
+		{name: "discovering", setup: func(*DownModel) {}, want: "crook: down worker-3 — discovering"},
+		{name: "confirm", setup: func(m *DownModel) { m.state = DownStateConfirm }, want: "crook: down worker-3 — confirm"},
+		{
+			name: "running",
+			setup: func(m *DownModel) {
+				m.startExecution()
+				m.advance("scale-down")
+				m.trackProgress(1, 40)
+			},
+			want: "crook: down worker-3 — scale deployments 40%",
+		},
+		{
+			name:  "complete",
+			setup: func(m *DownModel) { m.startExecution(); m.complete() },
+			want:  "crook: down worker-3 — complete",
+		},
+		{
+			name:  "failed",
+			setup: func(m *DownModel) { m.startExecution(); m.fail(errors.New("boom")) },
+			want:  "crook: down worker-3 — failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewDownModel(DownModelConfig{NodeName: "worker-3", Context: context.Background()})
+			tt.setup(m)
+			if got := m.TerminalTitle(); got != tt.want {
+				t.Errorf("TerminalTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLsModel_View_WindowTitle(t *testing.T) {
+	cfg := config.DefaultConfig()
+	model := NewLsModel(LsModelConfig{
+		Context:     context.Background(),
+		Config:      cfg,
+		KubeContext: "prod",
+		NodeFilter:  "worker-3",
+	})
+	model.width = 120
+	model.height = 40
+
+	if got, want := model.View().WindowTitle, "crook: prod · worker-3"; got != want {
+		t.Errorf("WindowTitle = %q, want %q", got, want)
+	}
+
+	model.maintenanceFlow = NewUpModel(UpModelConfig{NodeName: "worker-3", Context: context.Background(), Embedded: true})
+	if got, want := model.View().WindowTitle, "crook: up worker-3 — discovering"; got != want {
+		t.Errorf("WindowTitle = %q, want %q during a flow", got, want)
+	}
+
+	model.config.Config.UI.TerminalTitle = false
+	if got := model.View().WindowTitle; got != "" {
+		t.Errorf("WindowTitle = %q, want none when disabled", got)
+	}
+}
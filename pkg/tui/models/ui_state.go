@@ -0,0 +1,156 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+// UIState is the setup of the TUI remembered between sessions
+type UIState struct {
+	// Pane is the active pane: nodes, deployments or osds
+	Pane string `json:"pane,omitempty"`
+
+	// ShowPods lists pods instead of deployments in the Deployments pane
+	ShowPods bool `json:"show-pods,omitempty"`
+
+	// Follow filters the Deployments/Pods and OSDs panes to the selected node
+	Follow bool `json:"follow,omitempty"`
+
+	// Layout is the layout chosen with the layout key; empty keeps ui.layout
+	Layout string `json:"layout,omitempty"`
+
+	// ClusterLogLevel is the cluster log's minimum severity, e.g. warn
+	ClusterLogLevel string `json:"cluster-log-level,omitempty"`
+}
+
+// uiStatePaneNames name the panes in the state file
+var uiStatePaneNames = map[LsPane]string{
+	LsPaneNodes:       "nodes",
+	LsPaneDeployments: "deployments",
+	LsPaneOSDs:        "osds",
+}
+
+// uiStatePath returns the file holding the UI state of every cluster
+var uiStatePath = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "crook", "ui-state.json"), nil
+}
+
+// UIStateKey identifies a cluster in the state file by its kubeconfig
+// context, namespace and CephCluster
+func UIStateKey(kubeContext, namespace, cluster string) string {
+	if kubeContext == "" {
+		kubeContext = "in-cluster"
+	}
+	return strings.Join([]string{kubeContext, namespace, cluster}, "/")
+}
+
+// loadUIStates reads the remembered states, keyed by UIStateKey
+func loadUIStates() (map[string]UIState, error) {
+	path, err := uiStatePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]UIState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	states := map[string]UIState{}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return states, nil
+}
+
+// LoadUIState returns the state remembered for the cluster, or nil when
+// there is none or the state file cannot be read
+func LoadUIState(key string) *UIState {
+	states, err := loadUIStates()
+	if err != nil {
+		return nil
+	}
+	state, ok := states[key]
+	if !ok {
+		return nil
+	}
+	return &state
+}
+
+// SaveUIState remembers the state of the cluster
+func SaveUIState(key string, state UIState) error {
+	states, err := loadUIStates()
+	if err != nil {
+		// A corrupt state file is replaced rather than failing the exit
+		states = map[string]UIState{}
+	}
+	states[key] = state
+
+	path, err := uiStatePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save UI state: %w", err)
+	}
+	return nil
+}
+
+// UIState returns the setup to remember for the next session
+func (m *LsModel) UIState() UIState {
+	state := UIState{
+		Pane:            uiStatePaneNames[m.activePane],
+		ShowPods:        m.deploymentsPodsView.IsShowingPods(),
+		Follow:          m.followSelection,
+		ClusterLogLevel: m.clusterLog.MinSeverity().Level(),
+	}
+	if m.layout != m.config.Config.UI.Layout {
+		state.Layout = m.layout
+	}
+	return state
+}
+
+// restoreUIState applies a remembered setup; unknown values are ignored
+func (m *LsModel) restoreUIState(state UIState) {
+	if state.ShowPods {
+		m.deploymentsPodsView.ShowPods()
+		m.panes[LsPaneDeployments].SetTitle("Pods")
+	}
+	for pane, name := range uiStatePaneNames {
+		if name == state.Pane {
+			m.setActivePane(pane)
+		}
+	}
+	// A view scoped to one node already follows it
+	if state.Follow && !m.HasNodeFilter() {
+		m.followSelection = true
+		m.keyMap.SetFollowing(true)
+	}
+	if slices.Contains(config.Layouts, state.Layout) {
+		m.layout = state.Layout
+	}
+	if severity, err := k8s.ParseClusterLogSeverity(state.ClusterLogLevel); err == nil {
+		m.clusterLog.SetMinSeverity(severity)
+	}
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func TestSaveUIState(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "crook", "ui-state.json")
+	original := uiStatePath
+	uiStatePath = func() (string, error) { return statePath, nil }
+	t.Cleanup(func() { uiStatePath = original })
+
+	prod := UIStateKey("production", "rook-ceph", "")
+	if got := LoadUIState(prod); got != nil {
+		t.Errorf("LoadUIState() = %+v before anything was saved", got)
+	}
+
+	want := UIState{Pane: "osds", Follow: true, Layout: config.LayoutWide}
+	if err := SaveUIState(prod, want); err != nil {
+		t.Fatalf("SaveUIState() error = %v", err)
+	}
+	if err := SaveUIState(UIStateKey("", "rook-ceph", ""), UIState{Pane: "nodes"}); err != nil {
+		t.Fatalf("SaveUIState() error = %v", err)
+	}
+	if got := LoadUIState(prod); got == nil || !reflect.DeepEqual(*got, want) {
+		t.Errorf("LoadUIState() = %+v, want %+v", got, want)
+	}
+
+	// A corrupt state file is replaced on the next save
+	if err := os.WriteFile(statePath, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := LoadUIState(prod); got != nil {
+		t.Errorf("LoadUIState() = %+v from a corrupt file", got)
+	}
+	if err := SaveUIState(prod, want); err != nil || LoadUIState(prod) == nil {
+		t.Errorf("SaveUIState() should replace a corrupt file: %v", err)
+	}
+}
+
+func TestLsModel_UIStateRoundTrip(t *testing.T) {
+	model := NewLsModel(LsModelConfig{Context: context.Background()})
+	_, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model.nodesView.SetNodes([]k8s.NodeInfo{{Name: "node-a"}})
+
+	model.setActivePane(LsPaneDeployments)
+	model.Update(tea.KeyPressMsg{Code: ']', Text: "]"})
+	model.Update(tea.KeyPressMsg{Code: 'F', Text: "F"})
+	model.Update(tea.KeyPressMsg{Code: 'w', Text: "w"})
+	model.clusterLog.CycleSeverity()
+
+	state := model.UIState()
+	want := UIState{Pane: "deployments", ShowPods: true, Follow: true, Layout: config.LayoutWide, ClusterLogLevel: "warn"}
+	if !reflect.DeepEqual(state, want) {
+		t.Fatalf("UIState() = %+v, want %+v", state, want)
+	}
+
+	restored := NewLsModel(LsModelConfig{Context: context.Background(), State: &state})
+	if got := restored.UIState(); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored UIState() = %+v, want %+v", got, want)
+	}
+	if title := restored.panes[LsPaneDeployments].GetTitle(); title != "Pods" {
+		t.Errorf("deployments pane title = %q, want Pods", title)
+	}
+
+	// Unknown values from an older or edited file are ignored
+	odd := NewLsModel(LsModelConfig{Context: context.Background(), State: &UIState{Pane: "logs", Layout: "grid", ClusterLogLevel: "loud"}})
+	if got := odd.UIState(); got.Pane != "nodes" || got.Layout != "" || got.ClusterLogLevel != "info" {
+		t.Errorf("UIState() = %+v, want the defaults", got)
+	}
+}
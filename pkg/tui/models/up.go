@@ -7,14 +7,13 @@ import (
 	"strings"
 	"time"
 
-	"charm.land/bubbles/v2/help"
-	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"github.com/andri/crook/pkg/config"
 	"github.com/andri/crook/pkg/k8s"
 	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/monitoring"
 	"github.com/andri/crook/pkg/tui/components"
-	"github.com/andri/crook/pkg/tui/keys"
+	"github.com/andri/crook/pkg/tui/format"
 	"github.com/andri/crook/pkg/tui/styles"
 	appsv1 "k8s.io/api/apps/v1"
 )
@@ -41,6 +40,8 @@ const (
 	UpStateScalingOperator
 	// UpStateUnsettingNoOut unsets the Ceph noout flag
 	UpStateUnsettingNoOut
+	// UpStateSmokeTesting writes and reads back data through the configured storage classes
+	UpStateSmokeTesting
 	// UpStateComplete indicates successful completion
 	UpStateComplete
 	// UpStateError indicates an error occurred
@@ -68,6 +69,8 @@ func (s UpPhaseState) String() string {
 		return "Scaling Operator"
 	case UpStateUnsettingNoOut:
 		return "Unsetting NoOut Flag"
+	case UpStateSmokeTesting:
+		return "Smoke Testing Storage"
 	case UpStateComplete:
 		return "Complete"
 	case UpStateError:
@@ -98,6 +101,8 @@ func (s UpPhaseState) Description() string {
 		return "Scaling up rook-ceph-operator to resume management"
 	case UpStateUnsettingNoOut:
 		return "Unsetting Ceph noout flag to allow rebalancing"
+	case UpStateSmokeTesting:
+		return "Writing and reading back data through a test volume on the node"
 	case UpStateComplete:
 		return "All operations completed successfully"
 	case UpStateError:
@@ -107,6 +112,18 @@ func (s UpPhaseState) Description() string {
 	}
 }
 
+const (
+	// recoveryPollInterval is how often recovery progress is sampled after the up phase
+	recoveryPollInterval = 2 * time.Second
+
+	// recoveryHistorySize is how many recovery samples are kept for the rate graph
+	recoveryHistorySize = 40
+
+	// recoveryIdleSamples is how many samples are taken before an idle cluster
+	// is considered fully recovered
+	recoveryIdleSamples = 3
+)
+
 // UpModelConfig holds configuration for the up phase model
 type UpModelConfig struct {
 	// NodeName is the target node for the up phase
@@ -127,76 +144,113 @@ type UpModelConfig struct {
 
 	// Context for cancellation
 	Context context.Context
+
+	// Snapshot is the latest state of a hosting view's monitor (for example
+	// `crook ls`). When set, discovery skips cluster queries whose outcome the
+	// snapshot already shows.
+	Snapshot *monitoring.LsMonitorUpdate
+
+	// Updates is the flow's own lossless subscription to the hosting view's
+	// monitor. The host unsubscribes when the flow closes. Optional.
+	Updates <-chan *monitoring.LsMonitorUpdate
 }
 
-// RestorePlanItem represents a deployment to be restored
+// RestorePlanItem represents a workload to be restored
 type RestorePlanItem struct {
+	// Kind is k8s.WorkloadKindDeployment (assumed when empty) or k8s.WorkloadKindStatefulSet
+	Kind            string
 	Namespace       string
 	Name            string
 	CurrentReplicas int
-	Status          string // "pending", "restoring", "success", "error"
+	// TargetReplicas is the k8s.OriginalReplicasAnnotation count, 0 when unknown (restored to 1)
+	TargetReplicas int
+	Status         string // "pending", "restoring", "success", "error", "excluded"
 }
 
 // UpModel is the Bubble Tea model for the up phase workflow
 type UpModel struct {
+	// Shared engine: states, prompt, stage list, keys and rendering
+	PhaseModel[UpPhaseState, maintenance.UpPhaseProgress]
+
 	// Configuration
 	config UpModelConfig
 
-	// Current state machine state
-	state UpPhaseState
-
-	// Terminal dimensions
-	width  int
-	height int
-
-	// UI components
-	confirmPrompt *components.ConfirmPrompt
-	statusList    *components.StatusList
-	progress      *components.ProgressBar
+	// feed keeps the hosting view's monitor state current while embedded
+	feed monitorFeed
 
 	// Restore plan (discovered scaled-down deployments)
 	restorePlan []RestorePlanItem
 
+	// excludedPlan lists the scaled-down deployments discovery.exclude leaves at 0
+	excludedPlan []RestorePlanItem
+
 	// discoveredDeployments holds the actual Deployment objects discovered during
 	// the confirmation phase. These are passed to ExecuteUpPhase to avoid plan drift
 	// (where the confirmed plan differs from what actually gets executed).
 	discoveredDeployments []appsv1.Deployment
 
-	// Operation state
-	startTime           time.Time
-	elapsedTime         time.Duration
-	lastError           error
-	operationInProgress bool
+	// discoveredStatefulSets holds the scaled-down StatefulSets restored after
+	// the deployments (discovery.statefulsets)
+	discoveredStatefulSets []appsv1.StatefulSet
+
+	// external is set for an external Ceph cluster, where no deployments are restored
+	external bool
+
+	// nooutScope is what noout will be unset on, nil when unknown
+	nooutScope *maintenance.NoOutScope
 
 	// Deployment scaling progress (for display)
 	currentDeployment   string
 	deploymentsRestored int
 
-	// Cancellation and progress
-	cancelFunc     context.CancelFunc // Cancel function for ongoing operation
-	progressChan   chan maintenance.UpPhaseProgress
-	progressClosed bool // Track if progress channel is closed
+	// Ceph recovery progress sampled after completion
+	recoveryHistory []k8s.RecoveryStats
+	recoveryErr     error
+	recoveryDone    bool
 
-	// Keybindings and help
-	keyBindings keys.FlowBindings
-	helpModel   help.Model
+	// volumes checks the node's volume attachments once the phase completed
+	volumes volumeCheck
 }
 
 // NewUpModel creates a new up phase model
 func NewUpModel(cfg UpModelConfig) *UpModel {
-	h := help.New()
-	h.Styles.ShortKey = h.Styles.ShortKey.Foreground(styles.ColorInfo)
-	h.Styles.ShortDesc = h.Styles.ShortDesc.Foreground(styles.ColorSubtle)
-
-	return &UpModel{
-		config:        cfg,
-		state:         UpStateInit,
-		confirmPrompt: components.NewConfirmPrompt("Proceed with restoration?"),
-		statusList:    components.NewStatusList(),
-		progress:      components.NewIndeterminateProgress(""),
-		restorePlan:   make([]RestorePlanItem, 0),
-		keyBindings:   keys.DefaultFlowBindings(),
-		helpModel:     h,
+	m := &UpModel{
+		restorePlan: make([]RestorePlanItem, 0),
+	}
+	m.PhaseModel, cfg.Context = NewPhaseModel(cfg.Context, m.definition(cfg.Client, cfg.Config.SmokeTest.Enabled), cfg.ExitBehavior, cfg.Embedded)
+	m.config = cfg
+	m.feed = newMonitorFeed(cfg.Updates, cfg.Snapshot)
+	return m
+}
+
+// definition describes the up phase for the phase engine
+func (m *UpModel) definition(client *k8s.Client, smokeTest bool) PhaseDefinition[UpPhaseState, maintenance.UpPhaseProgress] {
+	return PhaseDefinition[UpPhaseState, maintenance.UpPhaseProgress]{
+		Name:            "Up",
+		ConfirmQuestion: "Proceed with restoration?",
+		States: PhaseStates[UpPhaseState]{
+			Init:        UpStateInit,
+			Confirm:     UpStateConfirm,
+			NothingToDo: UpStateNothingToDo,
+			Complete:    UpStateComplete,
+			Error:       UpStateError,
+		},
+		Stages:           upStages(smokeTest),
+		Runner:           newFlowRunnerUp(),
+		Execute:          m.runUpPhase,
+		Resumable:        true,
+		TickMsg:          UpPhaseTickMsg{},
+		PendingWorkloads: m.pendingWorkloads,
+		Client:           client,
+		ExitMsg: func(reason FlowExitReason, err error) tea.Msg {
+			return UpFlowExitMsg{Reason: reason, Err: err}
+		},
+		Screens: PhaseScreens{
+			Loading:      m.renderLoading,
+			Confirmation: m.renderConfirmation,
+			NothingToDo:  m.renderNothingToDo,
+			Complete:     m.renderComplete,
+		},
 	}
 }
 
@@ -210,6 +264,9 @@ type UpPhaseProgressMsg struct {
 	Stage       string
 	Description string
 	Deployment  string
+	Check       *maintenance.CheckStatus
+	Sequence    uint64
+	Percent     int
 }
 
 // UpPhaseCompleteMsg signals successful completion
@@ -227,12 +284,28 @@ type UpPhaseTickMsg struct{}
 // DeploymentsDiscoveredForUpMsg reports discovered scaled-down deployments
 type DeploymentsDiscoveredForUpMsg struct {
 	RestorePlan []RestorePlanItem
+	// Excluded lists the deployments discovery.exclude leaves at 0, shown greyed out
+	Excluded []RestorePlanItem
 	// Deployments contains the actual Deployment objects for execution.
 	// This avoids plan drift between confirmation and execution.
 	Deployments []appsv1.Deployment
+	// StatefulSets contains the scaled-down StatefulSets for execution
+	StatefulSets []appsv1.StatefulSet
 	// AlreadyInDesiredState indicates the node is fully in up state
 	// (uncordoned, noout unset, operator running, no scaled-down deployments).
 	AlreadyInDesiredState bool
+	// External is set for an external Ceph cluster, where no deployments are restored
+	External bool
+	// ScaleHistory holds the cluster's earlier scale timings for the ETA, if readable
+	ScaleHistory *maintenance.ScaleHistory
+	// NoOutScope is what noout will be unset on, nil when it could not be resolved
+	NoOutScope *maintenance.NoOutScope
+}
+
+// UpRecoveryStatsMsg carries a Ceph recovery sample taken after the up phase
+type UpRecoveryStatsMsg struct {
+	Stats *k8s.RecoveryStats
+	Err   error
 }
 
 // UpProgressChannelClosedMsg signals that the progress channel was closed
@@ -244,9 +317,42 @@ func (m *UpModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.discoverDeploymentsCmd(),
 		m.tickCmd(),
+		m.feed.listen(),
 	)
 }
 
+// newRestorePlan builds restore plan items for scaled-down deployments in the given status
+func newRestorePlan(deployments []appsv1.Deployment, status string) []RestorePlanItem {
+	plan := make([]RestorePlanItem, 0, len(deployments))
+	for _, dep := range deployments {
+		plan = append(plan, RestorePlanItem{
+			Kind:            k8s.WorkloadKindDeployment,
+			Namespace:       dep.Namespace,
+			Name:            dep.Name,
+			CurrentReplicas: 0, // All discovered deployments are at 0
+			TargetReplicas:  int(k8s.OriginalReplicas(dep.Annotations)),
+			Status:          status,
+		})
+	}
+	return plan
+}
+
+// newStatefulSetRestorePlan builds restore plan items for scaled-down StatefulSets in the given status
+func newStatefulSetRestorePlan(statefulSets []appsv1.StatefulSet, status string) []RestorePlanItem {
+	plan := make([]RestorePlanItem, 0, len(statefulSets))
+	for _, sts := range statefulSets {
+		plan = append(plan, RestorePlanItem{
+			Kind:            k8s.WorkloadKindStatefulSet,
+			Namespace:       sts.Namespace,
+			Name:            sts.Name,
+			CurrentReplicas: 0,
+			TargetReplicas:  int(k8s.OriginalReplicas(sts.Annotations)),
+			Status:          status,
+		})
+	}
+	return plan
+}
+
 // discoverDeploymentsCmd discovers scaled-down deployments for the confirmation screen
 func (m *UpModel) discoverDeploymentsCmd() tea.Cmd {
 	return func() tea.Msg {
@@ -261,22 +367,28 @@ func (m *UpModel) discoverDeploymentsCmd() tea.Cmd {
 		}
 
 		// Order deployments for up phase (same order as actual scaling: MONs first, then others)
-		orderedDeployments := maintenance.OrderDeploymentsForUp(deployments)
+		orderedDeployments, excluded := maintenance.ExcludeDeployments(
+			maintenance.OrderDeploymentsForUp(deployments), m.config.Config.Discovery)
 
-		// Build restore plan for display
-		restorePlan := make([]RestorePlanItem, 0, len(orderedDeployments))
-		for _, dep := range orderedDeployments {
-			item := RestorePlanItem{
-				Namespace:       dep.Namespace,
-				Name:            dep.Name,
-				CurrentReplicas: 0, // All discovered deployments are at 0
-				Status:          "pending",
-			}
-			restorePlan = append(restorePlan, item)
+		// StatefulSets are restored after the deployments (discovery.statefulsets)
+		statefulSets, excludedSets, err := maintenance.DiscoverScaledDownStatefulSets(
+			m.config.Context,
+			m.config.Client,
+			m.config.Config,
+			m.config.NodeName,
+			nil,
+		)
+		if err != nil {
+			return UpPhaseErrorMsg{Err: err, Stage: "discover"}
 		}
 
+		// Build restore plan for display
+		restorePlan := append(newRestorePlan(orderedDeployments, "pending"), newStatefulSetRestorePlan(statefulSets, "pending")...)
+		excludedPlan := append(newRestorePlan(excluded, "excluded"), newStatefulSetRestorePlan(excludedSets, "excluded")...)
+
 		// Check if already in desired up state (complete check including node/operator/noout)
-		alreadyInState := maintenance.IsInUpState(
+		observed := m.feed.observed()
+		alreadyInState := !observed.notInUpState(m.config.NodeName) && maintenance.IsInUpState(
 			m.config.Context,
 			m.config.Client,
 			m.config.Config,
@@ -284,292 +396,240 @@ func (m *UpModel) discoverDeploymentsCmd() tea.Cmd {
 			orderedDeployments,
 		)
 
+		// Earlier scale timings seed the ETA (best-effort)
+		scaleHistory, _ := maintenance.LoadScaleHistory(
+			m.config.Context,
+			m.config.Client,
+			m.config.Config,
+		)
+
+		// Resolve what noout will be unset on (best-effort)
+		var nooutScope *maintenance.NoOutScope
+		if scope, err := maintenance.UpNoOutScope(
+			m.config.Context,
+			m.config.Client,
+			m.config.Config,
+			m.config.NodeName,
+		); err == nil {
+			nooutScope = &scope
+		}
+
 		return DeploymentsDiscoveredForUpMsg{
 			RestorePlan:           restorePlan,
+			Excluded:              excludedPlan,
 			Deployments:           orderedDeployments, // Include ordered deployments for execution
+			StatefulSets:          statefulSets,
 			AlreadyInDesiredState: alreadyInState,
+			External:              maintenance.IsExternalCluster(m.config.Context, m.config.Client, m.config.Config),
+			ScaleHistory:          scaleHistory,
+			NoOutScope:            nooutScope,
 		}
 	}
 }
 
-// tickCmd returns a command that ticks every 100ms
-func (m *UpModel) tickCmd() tea.Cmd {
-	return tea.Tick(100*time.Millisecond, func(_ time.Time) tea.Msg {
-		return UpPhaseTickMsg{}
-	})
-}
-
-// executeUpPhaseCmd runs the actual up phase operation.
-// It sets up a progress channel and returns a batch of commands:
-// one to execute the operation and one to listen for progress.
-func (m *UpModel) executeUpPhaseCmd() tea.Cmd {
-	// Create cancellable context
-	ctx, cancel := context.WithCancel(m.config.Context)
-	m.cancelFunc = cancel
-
-	// Create progress channel (buffered to avoid blocking)
-	m.progressChan = make(chan maintenance.UpPhaseProgress, 10)
-	m.progressClosed = false
+// pollRecoveryCmd samples Ceph recovery progress after the given delay
+func (m *UpModel) pollRecoveryCmd(delay time.Duration) tea.Cmd {
+	client := m.config.Client
+	if client == nil {
+		return nil
+	}
+	ctx := m.config.Context
+	namespace := m.config.Config.Namespace
 
-	// Return batch of operation + progress listener
-	return tea.Batch(
-		m.runUpPhase(ctx),
-		m.listenForProgress(),
+	fetch := func() tea.Msg {
+		stats, err := client.GetRecoveryStats(ctx, namespace)
+		return UpRecoveryStatsMsg{Stats: stats, Err: err}
+	}
+	if delay == 0 {
+		return fetch
+	}
+	return tea.Tick(delay, func(time.Time) tea.Msg { return fetch() })
+}
+
+// newFlowRunnerUp creates the runner delivering up phase progress
+func newFlowRunnerUp() *FlowRunner[maintenance.UpPhaseProgress] {
+	return NewFlowRunner(
+		"up-progress",
+		func(progress maintenance.UpPhaseProgress) tea.Msg {
+			return UpPhaseProgressMsg{
+				Stage:       progress.Stage,
+				Description: progress.Description,
+				Deployment:  progress.Deployment,
+				Check:       progress.Check,
+				Sequence:    progress.Sequence,
+				Percent:     progress.Percent,
+			}
+		},
+		UpProgressChannelClosedMsg{},
 	)
 }
 
-// runUpPhase executes the maintenance operation in a goroutine
-func (m *UpModel) runUpPhase(ctx context.Context) tea.Cmd {
-	progressChan := m.progressChan
+// runUpPhase returns the up phase operation run by the flow runner
+func (m *UpModel) runUpPhase() func(context.Context, func(maintenance.UpPhaseProgress)) tea.Msg {
 	client := m.config.Client
 	cfg := m.config.Config
 	nodeName := m.config.NodeName
 	deployments := m.discoveredDeployments // Capture discovered deployments
+	statefulSets := m.discoveredStatefulSets
+	resume := m.resume
 
-	return func() tea.Msg {
+	return func(ctx context.Context, report func(maintenance.UpPhaseProgress)) tea.Msg {
 		opts := maintenance.UpPhaseOptions{
-			ProgressCallback: func(progress maintenance.UpPhaseProgress) {
-				// Non-blocking send to channel
-				select {
-				case progressChan <- progress:
-				default:
-					// Channel full, skip this update
-				}
-			},
+			ProgressCallback: report,
 			// Pass pre-discovered deployments to avoid plan drift between
 			// confirmation and execution (what user confirmed is what executes)
-			Deployments: deployments,
+			Deployments:  deployments,
+			StatefulSets: statefulSets,
+			Only:         resume.only,
+			ResumeFrom:   resume.from,
 		}
 
-		err := maintenance.ExecuteUpPhase(
-			ctx,
-			client,
-			cfg,
-			nodeName,
-			opts,
-		)
-
-		// Close progress channel when done
-		close(progressChan)
-
-		if err != nil {
+		if err := maintenance.ExecuteUpPhase(ctx, client, cfg, nodeName, opts); err != nil {
 			return UpPhaseErrorMsg{Err: err, Stage: "execute"}
 		}
-
 		return UpPhaseCompleteMsg{}
 	}
 }
 
-// listenForProgress creates a command that listens for progress updates
-// and returns them as messages. It reschedules itself until the channel closes.
-func (m *UpModel) listenForProgress() tea.Cmd {
-	progressChan := m.progressChan
-
-	return func() tea.Msg {
-		progress, ok := <-progressChan
-		if !ok {
-			// Channel closed
-			return UpProgressChannelClosedMsg{}
-		}
-		return UpPhaseProgressMsg{
-			Stage:       progress.Stage,
-			Description: progress.Description,
-			Deployment:  progress.Deployment,
-		}
-	}
-}
-
 // Update implements tea.Model
 func (m *UpModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		cmd := m.handleKeyPress(msg)
-		if cmd != nil {
-			cmds = append(cmds, cmd)
-		}
-
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-
 	case UpPhaseTickMsg:
-		if m.operationInProgress {
-			m.elapsedTime = time.Since(m.startTime)
-		}
-		// Update spinner
-		newProgress, cmd := m.progress.Update(msg)
-		if p, ok := newProgress.(*components.ProgressBar); ok {
-			m.progress = p
+		cmds = append(cmds, m.tick(msg))
+
+	case FlowMonitorUpdateMsg:
+		ok, touched := m.feed.receive(msg, m.config.NodeName, m.discoveredDeployments)
+		if !ok {
+			break
 		}
-		if cmd != nil {
-			cmds = append(cmds, cmd)
+		cmds = append(cmds, m.feed.listen())
+		// The plan awaiting confirmation is rediscovered when its node or
+		// workloads change, so the confirmed plan is the current one
+		if touched && m.state == UpStateConfirm {
+			cmds = append(cmds, m.discoverDeploymentsCmd())
 		}
-		cmds = append(cmds, m.tickCmd())
 
 	case DeploymentsDiscoveredForUpMsg:
+		if m.state != UpStateInit && m.state != UpStateConfirm {
+			// A rediscovery that finished after the plan was confirmed
+			break
+		}
 		m.restorePlan = msg.RestorePlan
+		m.excludedPlan = msg.Excluded
 		m.discoveredDeployments = msg.Deployments // Store for execution
+		m.discoveredStatefulSets = msg.StatefulSets
+		m.external = msg.External
+		m.nooutScope = msg.NoOutScope
+		m.startScaleETA(maintenance.ScalePhaseUp, msg.ScaleHistory)
 
-		// Check if already in desired up state (node uncordoned, noout unset, operator running, no scaled-down deployments)
-		if msg.AlreadyInDesiredState || len(m.restorePlan) == 0 {
+		// Check if already in desired up state (node uncordoned, noout unset, operator running, no scaled-down deployments).
+		// External clusters have no deployments to restore, only the cordon and noout.
+		switch {
+		case msg.AlreadyInDesiredState || (len(m.restorePlan) == 0 && !m.external):
 			m.state = UpStateNothingToDo
-		} else {
+		case m.external:
+			m.state = UpStateConfirm
+			m.confirmPrompt.Details = "External cluster: only uncordon and noout"
+		default:
 			m.state = UpStateConfirm
 			m.confirmPrompt.Details = fmt.Sprintf("%d deployment(s) will be restored to 1 replica", len(m.restorePlan))
 		}
 
 	case UpPhaseProgressMsg:
+		m.trackProgress(msg.Sequence, msg.Percent)
 		m.updateStateFromProgress(msg)
-		// Re-schedule the progress listener if channel is still open
-		if !m.progressClosed {
-			cmds = append(cmds, m.listenForProgress())
-		}
+		// Re-schedule the progress listener while the channel is open
+		cmds = append(cmds, m.runner.Listen())
 
 	case UpProgressChannelClosedMsg:
-		m.progressClosed = true
-		// No need to reschedule listener
+		m.runner.MarkClosed()
 
 	case UpPhaseCompleteMsg:
-		m.state = UpStateComplete
-		m.operationInProgress = false
-		m.cancelFunc = nil // Clear cancel func
-		m.progress.Complete()
-
-	case UpPhaseErrorMsg:
-		m.state = UpStateError
-		m.lastError = msg.Err
-		m.operationInProgress = false
-		m.cancelFunc = nil // Clear cancel func
-		m.progress.Error()
-
-	case components.ConfirmResultMsg:
-		if msg.Result == components.ConfirmYes {
-			m.startExecution()
-			cmds = append(cmds, m.executeUpPhaseCmd())
-		} else {
-			// User cancelled or declined
-			reason := FlowExitDeclined
-			if msg.Result == components.ConfirmCancelled {
-				reason = FlowExitCancelled
-			}
-			return m, m.exitCmd(reason, nil)
+		m.complete()
+		cmds = append(cmds, m.notifyFinished(m.config.Config.UI, m.config.NodeName))
+		cmds = append(cmds, m.volumes.start(m.config.Context, m.config.Client, m.config.NodeName, maintenance.ScalePhaseUp))
+		// Watch the recovery that restoring the node's OSDs triggers
+		if cmd := m.pollRecoveryCmd(0); cmd != nil {
+			cmds = append(cmds, cmd)
 		}
-	}
 
-	// Update confirm prompt if in confirm state
-	if m.state == UpStateConfirm {
-		newPrompt, cmd := m.confirmPrompt.Update(msg)
-		if p, ok := newPrompt.(*components.ConfirmPrompt); ok {
-			m.confirmPrompt = p
-		}
-		if cmd != nil {
+	case NodeVolumesMsg:
+		m.volumes.record(msg)
+
+	case UpRecoveryStatsMsg:
+		if cmd := m.handleRecoveryStats(msg); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+
+	case UpPhaseErrorMsg:
+		m.fail(msg.Err)
+		cmds = append(cmds, m.notifyFinished(m.config.Config.UI, m.config.NodeName))
 	}
 
+	// Input, resizing and the confirmation prompt behave alike in all phases
+	cmds = append(cmds, m.update(msg))
+
 	return m, tea.Batch(cmds...)
 }
 
-// handleKeyPress processes keyboard input based on current state
-func (m *UpModel) handleKeyPress(msg tea.KeyMsg) tea.Cmd {
-	// Update keybinding state based on current flow state
-	m.updateKeyBindings()
+// handleRecoveryStats records a recovery sample and schedules the next one
+// until recovery has finished
+func (m *UpModel) handleRecoveryStats(msg UpRecoveryStatsMsg) tea.Cmd {
+	if m.state != UpStateComplete || m.recoveryDone {
+		return nil
+	}
 
-	switch m.state { //nolint:exhaustive // default handles all operation states uniformly
-	case UpStateError:
-		switch {
-		case key.Matches(msg, m.keyBindings.Retry):
-			m.startExecution()
-			return m.executeUpPhaseCmd()
-		case key.Matches(msg, m.keyBindings.Quit):
-			return m.exitCmd(FlowExitError, m.lastError)
-		}
+	if msg.Err != nil {
+		m.recoveryErr = msg.Err
+		return m.pollRecoveryCmd(recoveryPollInterval)
+	}
 
-	case UpStateComplete:
-		if key.Matches(msg, m.keyBindings.Exit) {
-			return m.exitCmd(FlowExitCompleted, nil)
+	m.recoveryErr = nil
+	if msg.Stats != nil {
+		m.recoveryHistory = append(m.recoveryHistory, *msg.Stats)
+		if len(m.recoveryHistory) > recoveryHistorySize {
+			m.recoveryHistory = m.recoveryHistory[len(m.recoveryHistory)-recoveryHistorySize:]
 		}
-
-	case UpStateNothingToDo:
-		if key.Matches(msg, m.keyBindings.Exit) {
-			return m.exitCmd(FlowExitNothingToDo, nil)
-		}
-
-	case UpStateConfirm:
-		// Let the confirm prompt handle it
-		return nil
-
-	default:
-		// During operations, allow cancel
-		if key.Matches(msg, m.keyBindings.Interrupt) {
-			if m.cancelFunc != nil {
-				m.cancelFunc()
-			}
-			return m.exitCmd(FlowExitCancelled, nil)
+		// Peering can take a moment before recovery shows up; only stop after
+		// several idle samples
+		if !msg.Stats.Active() && len(m.recoveryHistory) >= recoveryIdleSamples {
+			m.recoveryDone = true
+			return nil
 		}
 	}
-
-	return nil
-}
-
-// updateKeyBindings updates the keybinding state based on current flow state
-func (m *UpModel) updateKeyBindings() {
-	switch m.state { //nolint:exhaustive // default handles all operation states
-	case UpStateConfirm:
-		m.keyBindings.SetStateConfirm()
-	case UpStateError:
-		m.keyBindings.SetStateError()
-	case UpStateComplete, UpStateNothingToDo:
-		m.keyBindings.SetStateComplete()
-	default:
-		m.keyBindings.SetStateRunning()
+	return m.pollRecoveryCmd(recoveryPollInterval)
+}
+
+// upStages lists the stages of the up phase. The smoke test stage is only
+// listed when smoke-test.enabled is set.
+func upStages(smokeTest bool) []PhaseStage[UpPhaseState] {
+	stages := []PhaseStage[UpPhaseState]{
+		{State: UpStatePreFlight, Label: "Pre-flight checks", Progress: maintenance.PreflightProgressStages},
+		{State: UpStateDiscovering, Label: "Discover deployments", Progress: []string{"discover"}},
+		{State: UpStateUncordoning, Label: "Uncordon node", Progress: []string{"uncordon"}},
+		{State: UpStateRestoringDeployments, Label: "Restore deployments", Progress: []string{"scale-up", "quorum"}},
+		{State: UpStateScalingOperator, Label: "Scale operator", Progress: []string{"operator"}},
+		{State: UpStateUnsettingNoOut, Label: "Unset noout flag", Progress: []string{"unset-noout"}},
 	}
-}
-
-func (m *UpModel) exitCmd(reason FlowExitReason, err error) tea.Cmd {
-	return flowExitCmd(m.config.ExitBehavior, UpFlowExitMsg{Reason: reason, Err: err})
-}
-
-// startExecution initializes state for operation execution
-func (m *UpModel) startExecution() {
-	m.operationInProgress = true
-	m.startTime = time.Now()
-	m.state = UpStatePreFlight // First stage is pre-flight checks
-	m.progress = components.NewIndeterminateProgress("Processing...")
-	m.initStatusList()
-}
-
-// initStatusList creates the status list for tracking progress
-func (m *UpModel) initStatusList() {
-	m.statusList = components.NewStatusList()
-	m.statusList.AddStatus("Pre-flight checks", components.StatusTypePending)
-	m.statusList.AddStatus("Discover deployments", components.StatusTypePending)
-	m.statusList.AddStatus("Uncordon node", components.StatusTypePending)
-	m.statusList.AddStatus("Restore deployments", components.StatusTypePending)
-	m.statusList.AddStatus("Scale operator", components.StatusTypePending)
-	m.statusList.AddStatus("Unset noout flag", components.StatusTypePending)
+	if smokeTest {
+		stages = append(stages, PhaseStage[UpPhaseState]{
+			State: UpStateSmokeTesting, Label: "Smoke test storage", Progress: maintenance.SmokeTestProgressStages,
+		})
+	}
+	return stages
 }
 
 // updateStateFromProgress updates the model state based on progress messages
 func (m *UpModel) updateStateFromProgress(msg UpPhaseProgressMsg) {
+	m.advance(msg.Stage)
+	if msg.Check != nil {
+		m.recordCheck(*msg.Check)
+	}
+
 	switch msg.Stage {
-	case "pre-flight":
-		m.state = UpStatePreFlight
-		m.updateStatusItem(0, components.StatusTypeRunning)
-	case "discover":
-		m.state = UpStateDiscovering
-		m.updateStatusItem(0, components.StatusTypeSuccess)
-		m.updateStatusItem(1, components.StatusTypeRunning)
-	case "uncordon":
-		m.state = UpStateUncordoning
-		m.updateStatusItem(1, components.StatusTypeSuccess)
-		m.updateStatusItem(2, components.StatusTypeRunning)
 	case "scale-up", "quorum":
-		m.state = UpStateRestoringDeployments
-		m.updateStatusItem(2, components.StatusTypeSuccess)
-		m.updateStatusItem(3, components.StatusTypeRunning)
 		// Track deployment progress when a deployment name is provided
 		if msg.Deployment != "" {
 			// If there was a previous deployment being restored, mark it as complete
@@ -580,6 +640,7 @@ func (m *UpModel) updateStateFromProgress(msg UpPhaseProgressMsg) {
 			// Mark the new deployment as in-progress
 			m.currentDeployment = msg.Deployment
 			m.updateDeploymentStatus(msg.Deployment, "restoring")
+			m.scalingWorkload(msg.Deployment)
 			// Update status item to show progress counter and deployment list
 			if item := m.statusList.Get(3); item != nil {
 				item.SetLabel(fmt.Sprintf("Restore deployments (%d/%d)", m.deploymentsRestored, len(m.restorePlan)))
@@ -588,34 +649,35 @@ func (m *UpModel) updateStateFromProgress(msg UpPhaseProgressMsg) {
 			}
 		}
 	case "operator":
-		m.state = UpStateScalingOperator
 		// Mark the last deployment as complete before moving to operator
 		if m.currentDeployment != "" {
 			m.updateDeploymentStatus(m.currentDeployment, "success")
 			m.deploymentsRestored++
 			m.currentDeployment = ""
+			m.scalingDone()
 		}
-		m.updateStatusItem(3, components.StatusTypeSuccess)
-		m.updateStatusItem(4, components.StatusTypeRunning)
 		// Keep deployment list visible with final count
 		if item := m.statusList.Get(3); item != nil {
 			item.SetLabel(fmt.Sprintf("Restore deployments (%d/%d)", m.deploymentsRestored, len(m.restorePlan)))
 			item.SetDetails(m.buildDeploymentListDetails())
 		}
-	case "unset-noout":
-		m.state = UpStateUnsettingNoOut
-		m.updateStatusItem(4, components.StatusTypeSuccess)
-		m.updateStatusItem(5, components.StatusTypeRunning)
-	case "complete":
-		m.updateStatusItem(5, components.StatusTypeSuccess)
+	case "smoke-test":
+		// The smoke test stage follows the six fixed stages
+		if item := m.statusList.Get(6); item != nil {
+			item.SetDetails(msg.Description)
+		}
 	}
 }
 
-// updateStatusItem safely updates a status item
-func (m *UpModel) updateStatusItem(index int, status components.StatusType) {
-	if item := m.statusList.Get(index); item != nil {
-		item.SetType(status)
+// pendingWorkloads lists the restore plan workloads not yet scaled up
+func (m *UpModel) pendingWorkloads() []string {
+	var pending []string
+	for _, item := range m.restorePlan {
+		if item.Status == "pending" {
+			pending = append(pending, item.Namespace+"/"+item.Name)
+		}
 	}
+	return pending
 }
 
 // updateDeploymentStatus updates the status of a deployment in the restore plan
@@ -648,52 +710,11 @@ func (m *UpModel) buildDeploymentListDetails() string {
 	return strings.Join(lines, "\n    ")
 }
 
-// View implements tea.Model
-func (m *UpModel) View() tea.View {
-	return tea.NewView(m.Render())
-}
-
 // NodeName returns the target node name.
 func (m *UpModel) NodeName() string {
 	return m.config.NodeName
 }
 
-// PhaseName returns "Up" for the up phase.
-func (m *UpModel) PhaseName() string {
-	return "Up"
-}
-
-// Render returns the string representation for composition
-func (m *UpModel) Render() string {
-	var b strings.Builder
-
-	// Main content based on state
-	switch m.state { //nolint:exhaustive // default handles all operation states uniformly
-	case UpStateInit, UpStateDiscovering:
-		b.WriteString(m.renderLoading())
-	case UpStateConfirm:
-		b.WriteString(m.renderConfirmation())
-	case UpStateNothingToDo:
-		b.WriteString(m.renderNothingToDo())
-	case UpStateError:
-		b.WriteString(m.renderError())
-	case UpStateComplete:
-		b.WriteString(m.renderComplete())
-	default:
-		b.WriteString(m.renderProgress())
-	}
-
-	// Footer with help
-	b.WriteString("\n\n")
-	b.WriteString(m.renderFooter())
-
-	if m.config.Embedded {
-		return b.String()
-	}
-
-	return styles.StyleBox.Width(min(m.width-4, 80)).Render(b.String())
-}
-
 // renderLoading renders the loading state
 func (m *UpModel) renderLoading() string {
 	return fmt.Sprintf("%s Discovering scaled-down deployments on node %s...",
@@ -708,23 +729,42 @@ func (m *UpModel) renderConfirmation() string {
 	// What will happen
 	b.WriteString(styles.StyleStatus.Render("This will:"))
 	b.WriteString("\n")
+	if m.external {
+		b.WriteString("  1. Uncordon the node to allow pod scheduling\n")
+		b.WriteString("  2. Unset Ceph noout flag" + nooutScopeSuffix(m.nooutScope) + " to allow rebalancing\n")
+		b.WriteString("\n")
+		b.WriteString(styles.StyleWarning.Render(maintenance.ExternalClusterNote))
+		return b.String()
+	}
 	b.WriteString("  1. Uncordon the node to allow pod scheduling\n")
-	b.WriteString(fmt.Sprintf("  2. Scale up %d deployment(s) to 1 replica\n", len(m.restorePlan)))
+	if statefulSets := len(m.discoveredStatefulSets); statefulSets > 0 {
+		b.WriteString(fmt.Sprintf("  2. Scale up %d deployment(s) and %d statefulset(s) to their original replicas\n",
+			len(m.restorePlan)-statefulSets, statefulSets))
+	} else {
+		b.WriteString(fmt.Sprintf("  2. Scale up %d deployment(s) to their original replicas\n", len(m.restorePlan)))
+	}
 	b.WriteString("  3. Scale up rook-ceph-operator to 1\n")
-	b.WriteString("  4. Unset Ceph noout flag to allow rebalancing\n")
+	b.WriteString("  4. Unset Ceph noout flag" + nooutScopeSuffix(m.nooutScope) + " to allow rebalancing\n")
 
 	// Restore plan table
 	if len(m.restorePlan) > 0 {
 		b.WriteString("\n")
 
 		// Create table
-		table := components.NewSimpleTable("Deployment", "Current", "Target")
+		table := components.NewSimpleTable("Kind", "Workload", "Current", "Target")
 		for _, item := range m.restorePlan {
 			deployName := fmt.Sprintf("%s/%s", item.Namespace, item.Name)
 			currentStr := fmt.Sprintf("%d", item.CurrentReplicas)
-			targetStr := "1" // All deployments will be scaled to 1
+			targetStr := "1" // Workloads without a recorded count are scaled to 1
+			if item.TargetReplicas > 0 {
+				targetStr = fmt.Sprintf("%d", item.TargetReplicas)
+			}
 
-			table.AddStyledRow(styles.StyleSubtle, deployName, currentStr, targetStr)
+			table.AddStyledRow(styles.StyleSubtle, planKind(item.Kind), deployName, currentStr, targetStr)
+		}
+		for _, item := range m.excludedPlan {
+			deployName := fmt.Sprintf("%s/%s", item.Namespace, item.Name)
+			table.AddStyledRow(styles.StyleExcluded, planKind(item.Kind), deployName, fmt.Sprintf("%d", item.CurrentReplicas), "excluded")
 		}
 		table.SetMaxRows(10)
 		b.WriteString(table.Render())
@@ -748,39 +788,6 @@ func (m *UpModel) renderNothingToDo() string {
 	return b.String()
 }
 
-// renderProgress renders the progress view during operations
-func (m *UpModel) renderProgress() string {
-	var b strings.Builder
-
-	// Elapsed time
-	b.WriteString(styles.StyleSubtle.Render(fmt.Sprintf("Elapsed: %s", m.elapsedTime.Round(time.Second))))
-	b.WriteString("\n\n")
-
-	// Status list (includes deployment progress inline)
-	b.WriteString(m.statusList.Render())
-
-	return b.String()
-}
-
-// renderError renders the error state
-func (m *UpModel) renderError() string {
-	var b strings.Builder
-
-	b.WriteString(styles.StyleError.Render(fmt.Sprintf("%s Error", styles.IconCross)))
-	b.WriteString("\n\n")
-
-	if m.lastError != nil {
-		b.WriteString(styles.StyleError.Render(m.lastError.Error()))
-	}
-
-	b.WriteString("\n\n")
-	b.WriteString(styles.StyleSubtle.Render("The cluster may be in a partial state."))
-	b.WriteString("\n")
-	b.WriteString(styles.StyleSubtle.Render("Review the error and decide how to proceed."))
-
-	return b.String()
-}
-
 // renderComplete renders the completion view
 func (m *UpModel) renderComplete() string {
 	var b strings.Builder
@@ -797,27 +804,62 @@ func (m *UpModel) renderComplete() string {
 
 	b.WriteString("\n\n")
 	b.WriteString(styles.StyleSuccess.Render("The node is now fully operational."))
-	b.WriteString("\n")
-	b.WriteString(styles.StyleSubtle.Render("Ceph cluster should begin rebalancing if needed."))
+	b.WriteString("\n\n")
+	b.WriteString(m.renderRecovery())
+	if volumes := m.volumes.render(); volumes != "" {
+		b.WriteString("\n\n")
+		b.WriteString(volumes)
+	}
 
 	return b.String()
 }
 
-// renderFooter renders context-sensitive help
-func (m *UpModel) renderFooter() string {
-	m.updateKeyBindings()
-	m.helpModel.SetWidth(m.width)
-	return m.helpModel.View(&m.keyBindings)
-}
+// renderRecovery renders live Ceph recovery throughput after completion
+func (m *UpModel) renderRecovery() string {
+	var b strings.Builder
+	b.WriteString(styles.StyleStatus.Render("Recovery"))
+	b.WriteString("\n")
 
-// SetSize implements SubModel
-func (m *UpModel) SetSize(width, height int) {
-	m.width = width
-	m.height = height
-}
+	if len(m.recoveryHistory) == 0 {
+		if m.recoveryErr != nil {
+			b.WriteString(styles.StyleSubtle.Render("Recovery status unavailable: " + m.recoveryErr.Error()))
+		} else {
+			b.WriteString(styles.StyleSubtle.Render(styles.IconSpinner + " Checking recovery status..."))
+		}
+		return b.String()
+	}
+
+	latest := m.recoveryHistory[len(m.recoveryHistory)-1]
+	if m.recoveryDone || !latest.Active() {
+		if m.recoveryDone {
+			b.WriteString(styles.StyleSuccess.Render(styles.IconCheckmark + " No misplaced or degraded objects"))
+		} else {
+			b.WriteString(styles.StyleSubtle.Render(styles.IconSpinner + " Waiting for recovery to start..."))
+		}
+		return b.String()
+	}
+
+	rates := make([]float64, 0, len(m.recoveryHistory))
+	for _, sample := range m.recoveryHistory {
+		rates = append(rates, sample.ObjectsPerSec)
+	}
+
+	kv := components.NewKeyValueTable()
+	kv.Add("Rate", fmt.Sprintf("%.0f objects/s, %s/s", latest.ObjectsPerSec, format.FormatBytes(int64(latest.BytesPerSec))))
+	kv.Add("Remaining", fmt.Sprintf("%d objects (%d misplaced, %d degraded)",
+		latest.Remaining(), latest.MisplacedObjects, latest.DegradedObjects))
+	if eta, ok := latest.ETA(); ok {
+		kv.Add("ETA", eta.String())
+	} else {
+		kv.Add("ETA", "calculating...")
+	}
+	kv.Add("History", styles.StyleStatus.Render(components.Sparkline(rates, recoveryHistorySize)))
+	b.WriteString(kv.Render())
+
+	if m.recoveryErr != nil {
+		b.WriteString("\n")
+		b.WriteString(styles.StyleWarning.Render("Last refresh failed: " + m.recoveryErr.Error()))
+	}
 
-// FlowKeyMap returns the flow keybindings for status bar help model
-func (m *UpModel) FlowKeyMap() help.KeyMap {
-	m.updateKeyBindings()
-	return &m.keyBindings
+	return b.String()
 }
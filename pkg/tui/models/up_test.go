@@ -8,7 +8,9 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/andri/crook/pkg/config"
+	"github.com/andri/crook/pkg/k8s"
 	"github.com/andri/crook/pkg/tui/components"
+	"github.com/andri/crook/test/util/fakeclock"
 )
 
 func TestUpPhaseState_String(t *testing.T) {
@@ -25,6 +27,7 @@ func TestUpPhaseState_String(t *testing.T) {
 		{UpStateRestoringDeployments, "Restoring Deployments"},
 		{UpStateScalingOperator, "Scaling Operator"},
 		{UpStateUnsettingNoOut, "Unsetting NoOut Flag"},
+		{UpStateSmokeTesting, "Smoke Testing Storage"},
 		{UpStateComplete, "Complete"},
 		{UpStateError, "Error"},
 		{UpPhaseState(99), "Unknown"},
@@ -53,6 +56,7 @@ func TestUpPhaseState_Description(t *testing.T) {
 		{UpStateUncordoning, true},
 		{UpStateScalingOperator, true},
 		{UpStateUnsettingNoOut, true},
+		{UpStateSmokeTesting, true},
 		{UpStateComplete, true},
 		{UpStateError, true},
 		{UpPhaseState(99), false},
@@ -296,8 +300,11 @@ func TestUpModel_Update_Tick(t *testing.T) {
 		NodeName: "test-node",
 		Context:  context.Background(),
 	})
+	clk := fakeclock.New(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	model.clock = clk
 	model.operationInProgress = true
-	model.startTime = time.Now().Add(-5 * time.Second)
+	model.startTime = clk.Now()
+	clk.Advance(5 * time.Second)
 
 	msg := UpPhaseTickMsg{}
 	updatedModel, cmd := model.Update(msg)
@@ -307,8 +314,8 @@ func TestUpModel_Update_Tick(t *testing.T) {
 	}
 
 	// Should update elapsed time
-	if m.elapsedTime < 4*time.Second {
-		t.Errorf("elapsedTime should be at least 4s, got %v", m.elapsedTime)
+	if m.elapsedTime != 5*time.Second {
+		t.Errorf("elapsedTime = %v, want 5s", m.elapsedTime)
 	}
 
 	// Should return another tick command
@@ -493,6 +500,41 @@ func TestUpModel_updateStateFromProgress(t *testing.T) {
 	}
 }
 
+func TestUpModel_SmokeTestStage(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		cfg := config.DefaultConfig()
+		cfg.SmokeTest.Enabled = enabled
+		model := NewUpModel(UpModelConfig{
+			NodeName: "test-node",
+			Context:  context.Background(),
+			Config:   cfg,
+		})
+		model.initStatusList()
+
+		wantStages := 6
+		if enabled {
+			wantStages = 7
+		}
+		if got := model.statusList.Count(); got != wantStages {
+			t.Fatalf("enabled=%v: %d stages, want %d", enabled, got, wantStages)
+		}
+		if !enabled {
+			continue
+		}
+
+		model.updateStateFromProgress(UpPhaseProgressMsg{
+			Stage:       "smoke-test",
+			Description: "Smoke testing storage class ceph-block on test-node",
+		})
+		if model.state != UpStateSmokeTesting {
+			t.Errorf("state = %v, want %v", model.state, UpStateSmokeTesting)
+		}
+		if got := model.statusList.Get(6).Details; got != "Smoke testing storage class ceph-block on test-node" {
+			t.Errorf("smoke test details = %q", got)
+		}
+	}
+}
+
 func TestUpModel_View_Init(t *testing.T) {
 	model := NewUpModel(UpModelConfig{
 		NodeName: "test-node",
@@ -647,6 +689,28 @@ func TestUpModel_Update_DeploymentsDiscovered_EmptyPlan(t *testing.T) {
 	}
 }
 
+func TestUpModel_Update_DeploymentsDiscovered_External(t *testing.T) {
+	model := NewUpModel(UpModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+
+	// External clusters never have deployments to restore, but the node is still cordoned
+	updatedModel, _ := model.Update(DeploymentsDiscoveredForUpMsg{External: true})
+	m, ok := updatedModel.(*UpModel)
+	if !ok {
+		t.Fatal("expected *UpModel type")
+	}
+
+	if m.state != UpStateConfirm {
+		t.Errorf("state = %v, want %v", m.state, UpStateConfirm)
+	}
+	view := m.Render()
+	if !contains(view, "External Ceph cluster") || contains(view, "rook-ceph-operator") {
+		t.Errorf("View should describe the external cluster plan, got %q", view)
+	}
+}
+
 func TestUpModel_handleKeyPress_NothingToDoState(t *testing.T) {
 	model := NewUpModel(UpModelConfig{
 		NodeName: "test-node",
@@ -711,3 +775,68 @@ func TestUpModel_View_NothingToDo(t *testing.T) {
 		t.Errorf("View should contain 'No scaling action needed', got %q", view)
 	}
 }
+
+func TestUpModel_RecoveryPanel(t *testing.T) {
+	model := NewUpModel(UpModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+	model.width = 100
+	model.state = UpStateComplete
+
+	if !contains(model.Render(), "Checking recovery status") {
+		t.Error("View should show recovery check before the first sample")
+	}
+
+	model.Update(UpRecoveryStatsMsg{Stats: &k8s.RecoveryStats{
+		ObjectsPerSec:    40,
+		BytesPerSec:      160 * 1024 * 1024,
+		MisplacedObjects: 900,
+		DegradedObjects:  300,
+	}})
+
+	view := model.Render()
+	for _, want := range []string{"40 objects/s", "160.0 MiB/s", "1200 objects", "ETA", "30s"} {
+		if !contains(view, want) {
+			t.Errorf("View should contain %q, got %q", want, view)
+		}
+	}
+	if model.recoveryDone {
+		t.Error("recovery should not be done while objects are misplaced")
+	}
+}
+
+func TestUpModel_RecoveryStopsWhenIdle(t *testing.T) {
+	model := NewUpModel(UpModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+	model.state = UpStateComplete
+
+	for range recoveryIdleSamples {
+		model.Update(UpRecoveryStatsMsg{Stats: &k8s.RecoveryStats{}})
+	}
+
+	if !model.recoveryDone {
+		t.Fatal("recovery should be done after idle samples")
+	}
+	if !contains(model.Render(), "No misplaced or degraded objects") {
+		t.Error("View should report completed recovery")
+	}
+	if cmd := model.handleRecoveryStats(UpRecoveryStatsMsg{Stats: &k8s.RecoveryStats{}}); cmd != nil {
+		t.Error("no further samples should be scheduled after recovery is done")
+	}
+}
+
+func TestUpModel_RecoveryErrorIsShown(t *testing.T) {
+	model := NewUpModel(UpModelConfig{
+		NodeName: "test-node",
+		Context:  context.Background(),
+	})
+	model.state = UpStateComplete
+
+	model.Update(UpRecoveryStatsMsg{Err: errors.New("tools pod not found")})
+	if !contains(model.Render(), "Recovery status unavailable") {
+		t.Error("View should explain why recovery status is missing")
+	}
+}
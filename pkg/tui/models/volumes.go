@@ -0,0 +1,117 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+	"github.com/andri/crook/pkg/tui/styles"
+)
+
+// maxVolumeLines caps the volumes listed on a completion screen
+const maxVolumeLines = 8
+
+// NodeVolumesMsg carries the volume check that follows a completed phase
+type NodeVolumesMsg struct {
+	Volumes *maintenance.NodeVolumes
+	Err     error
+}
+
+// volumeCheck is the check of the node's volumes shown on the completion
+// screen of a phase: before shutdown after the down phase, after recovery
+// after the up phase
+type volumeCheck struct {
+	// phase is maintenance.ScalePhaseDown or maintenance.ScalePhaseUp; empty
+	// when no check runs
+	phase string
+
+	volumes *maintenance.NodeVolumes
+	err     error
+	done    bool
+}
+
+// start begins the check for the phase and returns the command running it
+func (c *volumeCheck) start(ctx context.Context, client *k8s.Client, nodeName, phase string) tea.Cmd {
+	if client == nil {
+		return nil
+	}
+	*c = volumeCheck{phase: phase}
+	return func() tea.Msg {
+		volumes, err := maintenance.CheckNodeVolumes(ctx, client, nodeName, maintenance.VolumeSettleTimeout)
+		return NodeVolumesMsg{Volumes: volumes, Err: err}
+	}
+}
+
+// record stores the outcome of the check
+func (c *volumeCheck) record(msg NodeVolumesMsg) {
+	if c.phase == "" {
+		return
+	}
+	c.volumes, c.err, c.done = msg.Volumes, msg.Err, true
+}
+
+// needsAttention reports whether a volume is warned about in the phase
+func (c volumeCheck) needsAttention(volume k8s.VolumeInfo) bool {
+	if c.phase == maintenance.ScalePhaseDown {
+		return volume.Attached || volume.NodeAffinity
+	}
+	return maintenance.StuckReason(volume) != ""
+}
+
+// render lists the node's volumes with the phase's warnings
+func (c volumeCheck) render() string {
+	if c.phase == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.StyleStatus.Render("Volumes"))
+	b.WriteString("\n")
+
+	switch {
+	case !c.done:
+		b.WriteString(styles.StyleSubtle.Render(styles.IconSpinner + " Checking the node's volume attachments..."))
+		return b.String()
+	case c.err != nil:
+		b.WriteString(styles.StyleSubtle.Render("Volume check unavailable: " + c.err.Error()))
+		return b.String()
+	case len(c.volumes.Volumes) == 0:
+		b.WriteString(styles.StyleSuccess.Render(styles.IconCheckmark + " No volumes attached or pinned to the node"))
+		return b.String()
+	}
+
+	for i, volume := range c.volumes.Volumes {
+		if i == maxVolumeLines {
+			b.WriteString(styles.StyleSubtle.Render(fmt.Sprintf("… and %d more", len(c.volumes.Volumes)-maxVolumeLines)))
+			b.WriteString("\n")
+			break
+		}
+		name := volume.PersistentVolume
+		if volume.Claim != "" {
+			name = volume.Claim
+		}
+		line := fmt.Sprintf("%s (%s) %s", name, volume.StorageClass, maintenance.VolumeStatus(volume))
+		if c.needsAttention(volume) {
+			b.WriteString(styles.StyleWarning.Render(styles.IconWarning + " " + line))
+		} else {
+			b.WriteString(styles.StyleSubtle.Render(styles.IconCheckmark + " " + line))
+		}
+		b.WriteString("\n")
+	}
+
+	warnings := c.volumes.Warnings(c.phase)
+	if len(warnings) == 0 {
+		b.WriteString(styles.StyleSuccess.Render(styles.IconCheckmark + " No stuck volume attachments"))
+		return b.String()
+	}
+	lines := make([]string, len(warnings))
+	for i, warning := range warnings {
+		lines[i] = styles.StyleWarning.Render("⚠ " + warning)
+	}
+	b.WriteString(styles.StyleBoxWarning.Padding(0, 1).Render(strings.Join(lines, "\n")))
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/maintenance"
+)
+
+func TestVolumeCheckRender(t *testing.T) {
+	attached := k8s.VolumeInfo{PersistentVolume: "pv-db", Claim: "apps/db", StorageClass: "ceph-block", Attachment: "csi-1", Attached: true, InUse: true}
+	stale := k8s.VolumeInfo{PersistentVolume: "pv-old", Claim: "apps/old", StorageClass: "ceph-block", Attachment: "csi-2", Attached: true}
+
+	tests := []struct {
+		name  string
+		check volumeCheck
+		want  []string
+	}{
+		{name: "not started"},
+		{name: "running", check: volumeCheck{phase: maintenance.ScalePhaseDown}, want: []string{"Checking the node's volume attachments"}},
+		{
+			name:  "failed",
+			check: volumeCheck{phase: maintenance.ScalePhaseUp, done: true, err: errors.New("forbidden")},
+			want:  []string{"Volume check unavailable: forbidden"},
+		},
+		{
+			name:  "no volumes",
+			check: volumeCheck{phase: maintenance.ScalePhaseDown, done: true, volumes: &maintenance.NodeVolumes{Node: "worker-1"}},
+			want:  []string{"No volumes attached or pinned to the node"},
+		},
+		{
+			name: "attached before shutdown",
+			check: volumeCheck{phase: maintenance.ScalePhaseDown, done: true, volumes: &maintenance.NodeVolumes{
+				Node: "worker-1", Volumes: []k8s.VolumeInfo{attached},
+			}},
+			want: []string{"apps/db (ceph-block) attached, in use", "1 volume(s) still attached to worker-1"},
+		},
+		{
+			name: "healthy after recovery",
+			check: volumeCheck{phase: maintenance.ScalePhaseUp, done: true, volumes: &maintenance.NodeVolumes{
+				Node: "worker-1", Volumes: []k8s.VolumeInfo{attached},
+			}},
+			want: []string{"No stuck volume attachments"},
+		},
+		{
+			name: "stuck after recovery",
+			check: volumeCheck{phase: maintenance.ScalePhaseUp, done: true, volumes: &maintenance.NodeVolumes{
+				Node: "worker-1", Volumes: []k8s.VolumeInfo{attached, stale},
+			}},
+			want: []string{"apps/old (ceph-block) stuck: attached, but no pod", "1 volume attachment(s) on worker-1 look stuck"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.check.render()
+			if len(tt.want) == 0 && got != "" {
+				t.Errorf("render() = %q, want nothing", got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("render() missing %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package styles
+
+import (
+	"os"
+
+	"github.com/charmbracelet/colorprofile"
+)
+
+// colorDisabled forces colorless output when set via SetColorEnabled
+var colorDisabled bool
+
+// SetColorEnabled globally enables or disables color output.
+// Disabling color also applies to the TUI via ColorProfile.
+func SetColorEnabled(enabled bool) {
+	colorDisabled = !enabled
+}
+
+// ColorEnabled reports whether color output is enabled.
+// Color is disabled by SetColorEnabled(false) or the NO_COLOR environment variable.
+func ColorEnabled() bool {
+	return !colorDisabled && os.Getenv("NO_COLOR") == ""
+}
+
+// ColorProfile returns the color profile to force on the renderer, and false
+// when the terminal's detected profile should be used.
+func ColorProfile() (colorprofile.Profile, bool) {
+	if ColorEnabled() {
+		return colorprofile.Unknown, false
+	}
+	return colorprofile.ASCII, true
+}
@@ -0,0 +1,39 @@
+package styles
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/colorprofile"
+)
+
+func TestSetColorEnabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	defer SetColorEnabled(true)
+
+	SetColorEnabled(false)
+	if ColorEnabled() {
+		t.Error("expected color to be disabled")
+	}
+	profile, forced := ColorProfile()
+	if !forced || profile != colorprofile.ASCII {
+		t.Errorf("ColorProfile() = %v, %v; want ASCII, true", profile, forced)
+	}
+
+	SetColorEnabled(true)
+	if !ColorEnabled() {
+		t.Error("expected color to be enabled")
+	}
+	if _, forced := ColorProfile(); forced {
+		t.Error("expected no forced profile when color is enabled")
+	}
+}
+
+func TestColorEnabledRespectsNoColorEnv(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	defer SetColorEnabled(true)
+
+	SetColorEnabled(true)
+	if ColorEnabled() {
+		t.Error("expected NO_COLOR to disable color")
+	}
+}
@@ -81,6 +81,21 @@ var (
 			Foreground(ColorHighlight).
 			Bold(true)
 
+	// StyleExcluded greys out plan items crook leaves untouched
+	StyleExcluded = lipgloss.NewStyle().
+			Foreground(ColorBorder)
+
+	// StyleChanged highlights table rows whose data changed in the last refresh
+	StyleChanged = lipgloss.NewStyle().
+			Foreground(ColorInfo).
+			Bold(true)
+
+	// StyleRelated marks table rows related to the selection in another pane
+	// (an OSD's node and deployment, a node's OSDs and deployments)
+	StyleRelated = lipgloss.NewStyle().
+			Foreground(ColorPrimary).
+			Underline(true)
+
 	// StyleGroupHeader is used for group headers in tables/lists
 	StyleGroupHeader = lipgloss.NewStyle().
 				Foreground(ColorSubtle).
@@ -47,6 +47,7 @@ func TestTextStyles(t *testing.T) {
 		{"StyleSuccess", StyleSuccess, "Success message"},
 		{"StyleSubtle", StyleSubtle, "Subtle text"},
 		{"StyleHighlight", StyleHighlight, "Highlighted text"},
+		{"StyleChanged", StyleChanged, "Changed row"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,153 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/tui/format"
+	"github.com/andri/crook/pkg/tui/styles"
+)
+
+// clusterLogLevels are the severities the view cycles through as its filter
+var clusterLogLevels = []k8s.ClusterLogSeverity{
+	k8s.ClusterLogDebug,
+	k8s.ClusterLogInfo,
+	k8s.ClusterLogWarning,
+	k8s.ClusterLogError,
+}
+
+// ClusterLogView displays recent Ceph cluster log entries, newest at the
+// bottom, filtered by minimum severity
+type ClusterLogView struct {
+	// entries are all fetched entries, oldest first
+	entries []k8s.ClusterLogEntry
+
+	// minSeverity hides entries below this severity
+	minSeverity k8s.ClusterLogSeverity
+
+	// err is the last fetch error, shown above the entries
+	err error
+
+	// width is the terminal width
+	width int
+
+	// height is the terminal height
+	height int
+}
+
+// NewClusterLogView creates a cluster log view showing info and above
+func NewClusterLogView() *ClusterLogView {
+	return &ClusterLogView{minSeverity: k8s.ClusterLogInfo}
+}
+
+// SetEntries replaces the displayed entries and clears any fetch error
+func (v *ClusterLogView) SetEntries(entries []k8s.ClusterLogEntry) {
+	v.entries = entries
+	v.err = nil
+}
+
+// SetError records a fetch error; previously fetched entries stay visible
+func (v *ClusterLogView) SetError(err error) {
+	v.err = err
+}
+
+// SetSize sets the view dimensions
+func (v *ClusterLogView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
+
+// MinSeverity returns the current severity filter
+func (v *ClusterLogView) MinSeverity() k8s.ClusterLogSeverity {
+	return v.minSeverity
+}
+
+// SetMinSeverity sets the severity filter
+func (v *ClusterLogView) SetMinSeverity(severity k8s.ClusterLogSeverity) {
+	v.minSeverity = severity
+}
+
+// CycleSeverity raises the severity filter, wrapping from error back to debug
+func (v *ClusterLogView) CycleSeverity() {
+	for i, level := range clusterLogLevels {
+		if level == v.minSeverity {
+			v.minSeverity = clusterLogLevels[(i+1)%len(clusterLogLevels)]
+			return
+		}
+	}
+	v.minSeverity = clusterLogLevels[0]
+}
+
+// Count returns the number of entries that pass the filter
+func (v *ClusterLogView) Count() int {
+	return len(k8s.FilterClusterLog(v.entries, v.minSeverity))
+}
+
+// Render returns the filtered entries that fit the view, newest last
+func (v *ClusterLogView) Render() string {
+	var b strings.Builder
+
+	b.WriteString(styles.StyleSubtle.Render(fmt.Sprintf("Level %s and above · v to change", v.minSeverity.Level())))
+	b.WriteString("\n")
+	if v.err != nil {
+		b.WriteString(styles.StyleError.Render(format.TruncateWithEllipsis(
+			styles.IconCross+" "+format.SanitizeForDisplay(v.err.Error()), max(v.width, 1))))
+		b.WriteString("\n")
+	}
+
+	entries := k8s.FilterClusterLog(v.entries, v.minSeverity)
+	if len(entries) == 0 {
+		b.WriteString(styles.StyleSubtle.Render("No cluster log entries"))
+		return b.String()
+	}
+
+	// Tail the log: the newest entries matter most during maintenance
+	visibleRows := v.height - 2
+	if v.err != nil {
+		visibleRows--
+	}
+	if visibleRows > 0 && len(entries) > visibleRows {
+		entries = entries[len(entries)-visibleRows:]
+	}
+
+	for i, entry := range entries {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(v.renderEntry(entry))
+	}
+	return b.String()
+}
+
+// renderEntry renders one log line: time, daemon, severity and message
+func (v *ClusterLogView) renderEntry(entry k8s.ClusterLogEntry) string {
+	stamp := "--:--:--"
+	if !entry.Stamp.IsZero() {
+		stamp = entry.Stamp.Local().Format("15:04:05")
+	}
+
+	prefix := fmt.Sprintf("%s %s [%s] ", stamp, format.PadRight(entry.Name, 8), entry.Severity)
+	message := format.SanitizeForDisplay(entry.Message)
+	if v.width > 0 {
+		message = format.TruncateWithEllipsis(message, max(v.width-format.DisplayWidth(prefix), 1))
+	}
+
+	return styles.StyleSubtle.Render(prefix) + clusterLogStyle(entry.Severity).Render(message)
+}
+
+// clusterLogStyle returns the message style for a severity
+func clusterLogStyle(severity k8s.ClusterLogSeverity) lipgloss.Style {
+	switch severity {
+	case k8s.ClusterLogError:
+		return styles.StyleError
+	case k8s.ClusterLogWarning, k8s.ClusterLogSecurity:
+		return styles.StyleWarning
+	case k8s.ClusterLogDebug:
+		return styles.StyleSubtle
+	case k8s.ClusterLogInfo:
+		return styles.StyleNormal
+	}
+	return styles.StyleNormal
+}
@@ -0,0 +1,80 @@
+package views
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func TestClusterLogView_Render(t *testing.T) {
+	v := NewClusterLogView()
+	v.SetSize(120, 30)
+
+	if !strings.Contains(v.Render(), "No cluster log entries") {
+		t.Error("empty view should say there are no entries")
+	}
+
+	v.SetEntries([]k8s.ClusterLogEntry{
+		{Name: "mgr.a", Seq: 1, Severity: k8s.ClusterLogDebug, Message: "pgmap v5521"},
+		{Name: "mon.a", Seq: 2, Severity: k8s.ClusterLogInfo, Message: "osd.3 marked itself down"},
+		{Name: "mon.a", Seq: 3, Severity: k8s.ClusterLogError, Message: "4 pgs inactive"},
+	})
+
+	output := v.Render()
+	for _, want := range []string{"info and above", "mon.a", "[INF]", "osd.3 marked itself down", "[ERR]"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q", want)
+		}
+	}
+	if strings.Contains(output, "pgmap") {
+		t.Error("debug entries should be hidden at info level")
+	}
+	if v.Count() != 2 {
+		t.Errorf("Count() = %d, want 2", v.Count())
+	}
+}
+
+func TestClusterLogView_TailsToHeight(t *testing.T) {
+	v := NewClusterLogView()
+	v.SetSize(80, 4)
+
+	v.SetEntries([]k8s.ClusterLogEntry{
+		{Name: "mon.a", Seq: 1, Severity: k8s.ClusterLogInfo, Message: "first"},
+		{Name: "mon.a", Seq: 2, Severity: k8s.ClusterLogInfo, Message: "second"},
+		{Name: "mon.a", Seq: 3, Severity: k8s.ClusterLogInfo, Message: "third"},
+	})
+
+	output := v.Render()
+	if strings.Contains(output, "first") {
+		t.Error("oldest entry should scroll out of a short view")
+	}
+	if !strings.Contains(output, "second") || !strings.Contains(output, "third") {
+		t.Error("newest entries should stay visible")
+	}
+}
+
+func TestClusterLogView_Error(t *testing.T) {
+	v := NewClusterLogView()
+	v.SetSize(80, 10)
+	v.SetEntries([]k8s.ClusterLogEntry{{Name: "mon.a", Severity: k8s.ClusterLogWarning, Message: "noout set"}})
+	v.SetError(errors.New("toolbox not ready"))
+
+	output := v.Render()
+	if !strings.Contains(output, "toolbox not ready") || !strings.Contains(output, "noout set") {
+		t.Error("fetch errors should be shown above the previous entries")
+	}
+}
+
+func TestClusterLogView_CycleSeverity(t *testing.T) {
+	v := NewClusterLogView()
+
+	want := []k8s.ClusterLogSeverity{k8s.ClusterLogWarning, k8s.ClusterLogError, k8s.ClusterLogDebug, k8s.ClusterLogInfo}
+	for _, severity := range want {
+		v.CycleSeverity()
+		if v.MinSeverity() != severity {
+			t.Errorf("MinSeverity() = %v, want %v", v.MinSeverity(), severity)
+		}
+	}
+}
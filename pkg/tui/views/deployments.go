@@ -2,12 +2,14 @@ package views
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/monitoring"
 	"github.com/andri/crook/pkg/tui/format"
 	"github.com/andri/crook/pkg/tui/styles"
 )
@@ -21,24 +23,41 @@ const (
 	nodeColWidth      = 20 // Node name
 	ageColWidth       = 8  // Age
 	statusColWidth    = 12 // Status text
+	versionColWidth   = 14 // Ceph version or image tag
 )
 
 // DeploymentsView displays Rook-Ceph deployments with node mapping
 type DeploymentsView struct {
-	// deployments is the list of deployments to display
+	// deployments is the list of deployments to display (may be filtered by node)
 	deployments []k8s.DeploymentInfo
 
+	// allDeployments stores all deployments before node filtering
+	allDeployments []k8s.DeploymentInfo
+
+	// nodeFilter filters deployments to a specific node
+	nodeFilter string
+
 	// cursor is the currently selected row
 	cursor int
 
 	// groupByType controls whether to group deployments by type
 	groupByType bool
 
+	// cephVersion is the Ceph version most deployments run; daemons on
+	// another version are highlighted as partially upgraded
+	cephVersion string
+
 	// width is the terminal width
 	width int
 
 	// height is the terminal height
 	height int
+
+	// flash highlights rows that changed in recent refreshes
+	flash rowFlash
+
+	// related highlights rows related to the selection in another pane
+	related relatedRows
 }
 
 // NewDeploymentsView creates a new deployments view
@@ -160,6 +179,7 @@ func (v *DeploymentsView) renderHeader() string {
 		format.PadRight("NODE", nodeColWidth),
 		format.PadRight("AGE", ageColWidth),
 		format.PadRight("STATUS", statusColWidth),
+		format.PadRight("VERSION", versionColWidth),
 	}
 
 	return headerStyle.Render(strings.Join(cols, " "))
@@ -237,6 +257,10 @@ func (v *DeploymentsView) renderRow(dep k8s.DeploymentInfo, selected bool) strin
 			Bold(true).
 			Foreground(styles.ColorHighlight).
 			Background(styles.ColorPrimary)
+	} else if v.flash.active(monitoring.DeploymentKey(dep)) {
+		nameStyle = styles.StyleChanged
+	} else if v.related[monitoring.DeploymentKey(dep)] {
+		nameStyle = styles.StyleRelated
 	} else {
 		nameStyle = styles.StyleNormal
 	}
@@ -277,6 +301,16 @@ func (v *DeploymentsView) renderRow(dep k8s.DeploymentInfo, selected bool) strin
 	}
 	nodeName = format.TruncateWithEllipsis(nodeName, 18)
 
+	// Daemons off the predominant Ceph version are mid-upgrade
+	version := dep.Version()
+	versionStyle := styles.StyleSubtle
+	if dep.CephVersion != "" && dep.CephVersion != v.cephVersion {
+		versionStyle = styles.StyleWarning
+	}
+	if version == "" {
+		version = "-"
+	}
+
 	cols := []string{
 		styles.StyleWarning.Render(iconPrefix),
 		nameStyle.Render(format.PadRight(dep.Name, nameColWidth)),
@@ -285,6 +319,7 @@ func (v *DeploymentsView) renderRow(dep k8s.DeploymentInfo, selected bool) strin
 		styles.StyleNormal.Render(format.PadRight(nodeName, nodeColWidth)),
 		styles.StyleSubtle.Render(format.PadRight(dep.Age, ageColWidth)),
 		statusStyle.Render(format.PadRight(dep.Status, statusColWidth)),
+		versionStyle.Render(format.PadRight(format.TruncateWithEllipsis(version, versionColWidth), versionColWidth)),
 	}
 
 	return strings.Join(cols, " ")
@@ -292,18 +327,62 @@ func (v *DeploymentsView) renderRow(dep k8s.DeploymentInfo, selected bool) strin
 
 // getTableWidth returns the total table width
 func (v *DeploymentsView) getTableWidth() int {
-	// icon + name + namespace + ready + node + age + status + spacing between columns
-	return iconPrefixWidth + nameColWidth + namespaceColWidth + readyColWidth + nodeColWidth + ageColWidth + statusColWidth + 6
+	// icon + name + namespace + ready + node + age + status + version + spacing between columns
+	return iconPrefixWidth + nameColWidth + namespaceColWidth + readyColWidth + nodeColWidth + ageColWidth + statusColWidth +
+		versionColWidth + 7
 }
 
-// SetDeployments updates the deployments list
+// SetDeployments updates the deployments list, keeping the selection on the same deployment
 func (v *DeploymentsView) SetDeployments(deployments []k8s.DeploymentInfo) {
-	v.deployments = deployments
-	v.sortDeployments()
+	selected := selectedKey(v.deployments, v.cursor, monitoring.DeploymentKey)
+	v.allDeployments = deployments
+	v.cephVersion = k8s.PredominantCephVersion(deployments)
+	v.applyNodeFilter(selected)
+}
+
+// SetRelated highlights the rows with the given identities as related to
+// the selection in another pane; nil clears the highlight
+func (v *DeploymentsView) SetRelated(keys []string) {
+	v.related = newRelatedRows(keys)
+}
+
+// SetNodeFilter sets the node filter for filtering deployments by node
+func (v *DeploymentsView) SetNodeFilter(nodeFilter string) {
+	selected := selectedKey(v.deployments, v.cursor, monitoring.DeploymentKey)
+	v.nodeFilter = nodeFilter
+	v.applyNodeFilter(selected)
+}
+
+// applyNodeFilter filters deployments based on the node filter and keeps the
+// deployment with the selected identity under the cursor
+func (v *DeploymentsView) applyNodeFilter(selected string) {
+	if v.nodeFilter == "" {
+		// Sorting happens in place; the monitor shares its slice with other readers
+		v.deployments = slices.Clone(v.allDeployments)
+	} else {
+		v.deployments = make([]k8s.DeploymentInfo, 0, len(v.allDeployments))
+		for _, dep := range v.allDeployments {
+			if dep.NodeName == v.nodeFilter {
+				v.deployments = append(v.deployments, dep)
+			}
+		}
+	}
+	v.sortDeployments(selected)
+}
+
+// GetNodeFilter returns the current node filter
+func (v *DeploymentsView) GetNodeFilter() string {
+	return v.nodeFilter
+}
+
+// MarkChanged highlights the rows with the given identities (see monitoring.LsChanges)
+func (v *DeploymentsView) MarkChanged(keys []string) {
+	v.flash.mark(keys)
 }
 
 // sortDeployments sorts deployments by type then name for consistent grouping
-func (v *DeploymentsView) sortDeployments() {
+// and moves the cursor to the deployment with the selected identity
+func (v *DeploymentsView) sortDeployments(selected string) {
 	if v.groupByType {
 		sort.Slice(v.deployments, func(i, j int) bool {
 			if v.deployments[i].Type != v.deployments[j].Type {
@@ -313,13 +392,7 @@ func (v *DeploymentsView) sortDeployments() {
 		})
 	}
 
-	// Reset cursor if out of bounds
-	if v.cursor >= len(v.deployments) {
-		v.cursor = len(v.deployments) - 1
-	}
-	if v.cursor < 0 {
-		v.cursor = 0
-	}
+	v.cursor = restoreCursor(v.deployments, v.cursor, selected, monitoring.DeploymentKey)
 }
 
 // typeOrder returns a sort order for deployment types
@@ -372,6 +445,7 @@ func (v *DeploymentsView) GetSelectedDeployment() *k8s.DeploymentInfo {
 
 // SetGroupByType enables or disables grouping by type
 func (v *DeploymentsView) SetGroupByType(group bool) {
+	selected := selectedKey(v.deployments, v.cursor, monitoring.DeploymentKey)
 	v.groupByType = group
-	v.sortDeployments()
+	v.sortDeployments(selected)
 }
@@ -112,12 +112,28 @@ func (v *DeploymentsPodsView) SetPods(pods []k8s.PodInfo) {
 	v.podsView.SetPods(pods)
 }
 
-// SetNodeFilter sets the node filter on the pods sub-view.
+// MarkDeploymentsChanged highlights changed rows in the deployments sub-view.
+func (v *DeploymentsPodsView) MarkDeploymentsChanged(keys []string) {
+	v.deploymentsView.MarkChanged(keys)
+}
+
+// MarkPodsChanged highlights changed rows in the pods sub-view.
+func (v *DeploymentsPodsView) MarkPodsChanged(keys []string) {
+	v.podsView.MarkChanged(keys)
+}
+
+// SetRelatedDeployments highlights related rows in the deployments sub-view.
+func (v *DeploymentsPodsView) SetRelatedDeployments(keys []string) {
+	v.deploymentsView.SetRelated(keys)
+}
+
+// SetNodeFilter sets the node filter on the deployments and pods sub-views.
 func (v *DeploymentsPodsView) SetNodeFilter(nodeFilter string) {
+	v.deploymentsView.SetNodeFilter(nodeFilter)
 	v.podsView.SetNodeFilter(nodeFilter)
 }
 
-// GetNodeFilter returns the node filter from the pods sub-view.
+// GetNodeFilter returns the node filter of the sub-views.
 func (v *DeploymentsPodsView) GetNodeFilter() string {
 	return v.podsView.GetNodeFilter()
 }
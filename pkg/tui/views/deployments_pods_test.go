@@ -200,6 +200,37 @@ func TestDeploymentsPodsView_SetNodeFilter(t *testing.T) {
 	}
 }
 
+func TestDeploymentsPodsView_SetNodeFilterDeployments(t *testing.T) {
+	v := views.NewDeploymentsPodsView()
+	v.SetDeployments([]k8s.DeploymentInfo{
+		{Name: "rook-ceph-osd-0", Namespace: "ns", NodeName: "node1", Type: "osd"},
+		{Name: "rook-ceph-osd-1", Namespace: "ns", NodeName: "node2", Type: "osd"},
+		{Name: "rook-ceph-mon-a", Namespace: "ns", NodeName: "node2", Type: "mon"},
+	})
+
+	v.SetNodeFilter("node2")
+	if v.DeploymentsCount() != 2 {
+		t.Errorf("filtered DeploymentsCount() = %d, want 2", v.DeploymentsCount())
+	}
+	if d := v.GetSelectedDeployment(); d == nil || d.NodeName != "node2" {
+		t.Errorf("selected deployment = %+v, want one on node2", d)
+	}
+
+	// Refreshes keep the filter
+	v.SetDeployments([]k8s.DeploymentInfo{
+		{Name: "rook-ceph-osd-0", Namespace: "ns", NodeName: "node1", Type: "osd"},
+		{Name: "rook-ceph-mon-a", Namespace: "ns", NodeName: "node2", Type: "mon"},
+	})
+	if v.DeploymentsCount() != 1 {
+		t.Errorf("DeploymentsCount() after refresh = %d, want 1", v.DeploymentsCount())
+	}
+
+	v.SetNodeFilter("")
+	if v.DeploymentsCount() != 2 {
+		t.Errorf("unfiltered DeploymentsCount() = %d, want 2", v.DeploymentsCount())
+	}
+}
+
 func TestDeploymentsPodsView_View(t *testing.T) {
 	v := views.NewDeploymentsPodsView()
 	v.SetSize(100, 20)
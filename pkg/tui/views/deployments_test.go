@@ -180,6 +180,29 @@ func TestDeploymentsView_View(t *testing.T) {
 	}
 }
 
+func TestDeploymentsView_Versions(t *testing.T) {
+	v := NewDeploymentsView()
+	v.SetSize(140, 30)
+
+	v.SetDeployments([]k8s.DeploymentInfo{
+		{Name: "rook-ceph-osd-0", Type: "osd", CephVersion: "18.2.2-0"},
+		{Name: "rook-ceph-osd-1", Type: "osd", CephVersion: "18.2.2-0"},
+		{Name: "rook-ceph-osd-2", Type: "osd", CephVersion: "19.2.0-0"},
+		{Name: "rook-ceph-exporter-worker-1", Type: "exporter", Image: "quay.io/ceph/ceph:v18.2.2"},
+	})
+
+	if v.cephVersion != "18.2.2-0" {
+		t.Errorf("cephVersion = %q, want 18.2.2-0", v.cephVersion)
+	}
+
+	output := v.Render()
+	for _, want := range []string{"VERSION", "18.2.2-0", "19.2.0-0", "v18.2.2"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q", want)
+		}
+	}
+}
+
 func TestDeploymentsView_EmptyView(t *testing.T) {
 	v := NewDeploymentsView()
 	v.SetSize(100, 30)
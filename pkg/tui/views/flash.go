@@ -0,0 +1,47 @@
+package views
+
+import "time"
+
+// rowFlashDuration is how long a changed row stays highlighted
+const rowFlashDuration = 3 * time.Second
+
+// rowFlash tracks recently changed rows by resource identity so views can
+// highlight them after a refresh
+type rowFlash struct {
+	until map[string]time.Time
+
+	// now returns the current time; overridden in tests
+	now func() time.Time
+}
+
+// mark highlights the given identities for rowFlashDuration
+func (f *rowFlash) mark(keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	if f.until == nil {
+		f.until = make(map[string]time.Time, len(keys))
+	}
+	now := f.currentTime()
+	for key, until := range f.until {
+		if now.After(until) {
+			delete(f.until, key)
+		}
+	}
+	for _, key := range keys {
+		f.until[key] = now.Add(rowFlashDuration)
+	}
+}
+
+// active reports whether the row with the given identity is highlighted
+func (f *rowFlash) active(key string) bool {
+	until, ok := f.until[key]
+	return ok && f.currentTime().Before(until)
+}
+
+func (f *rowFlash) currentTime() time.Time {
+	if f.now != nil {
+		return f.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,40 @@
+package views
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func TestRowFlash(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := rowFlash{now: func() time.Time { return now }}
+
+	f.mark([]string{"node-a"})
+	if !f.active("node-a") {
+		t.Error("expected marked row to be highlighted")
+	}
+	if f.active("node-b") {
+		t.Error("expected unmarked row not to be highlighted")
+	}
+
+	now = now.Add(rowFlashDuration + time.Second)
+	if f.active("node-a") {
+		t.Error("expected highlight to expire")
+	}
+}
+
+func TestNodesView_MarkChanged(t *testing.T) {
+	v := NewNodesView()
+	v.SetNodes([]k8s.NodeInfo{{Name: "node-a"}, {Name: "node-b"}})
+	v.SetCursor(1)
+
+	v.MarkChanged([]string{"node-a"})
+	if !v.flash.active("node-a") {
+		t.Error("expected node-a to be highlighted")
+	}
+	if v.GetCursor() != 1 {
+		t.Errorf("marking changes must not move the cursor, got %d", v.GetCursor())
+	}
+}
@@ -0,0 +1,119 @@
+package views
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/tui/format"
+	"github.com/andri/crook/pkg/tui/styles"
+)
+
+// NodeDetailView shows a single node as a list of properties. It replaces the
+// nodes table when the ls view is scoped to one node.
+type NodeDetailView struct {
+	// name is the node shown; it is known before the first refresh
+	name string
+
+	// node is the node's latest data, nil until it arrives
+	node *k8s.NodeInfo
+
+	width  int
+	height int
+}
+
+// nodeDetailLabelWidth is the width of the property label column
+const nodeDetailLabelWidth = 13
+
+// NewNodeDetailView creates a detail view for the named node
+func NewNodeDetailView(name string) *NodeDetailView {
+	return &NodeDetailView{name: name}
+}
+
+// SetNode updates the node shown; nil means it is not (yet) known
+func (v *NodeDetailView) SetNode(node *k8s.NodeInfo) {
+	v.node = node
+}
+
+// SetSize sets the view dimensions
+func (v *NodeDetailView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
+
+// Render returns the node's properties, one per line
+func (v *NodeDetailView) Render() string {
+	if v.node == nil {
+		return styles.StyleSubtle.Render(fmt.Sprintf("Waiting for node %s…", v.name))
+	}
+	node := v.node
+
+	statusStyle := styles.StyleWarning
+	switch node.Status {
+	case "Ready":
+		statusStyle = styles.StyleSuccess
+	case "NotReady":
+		statusStyle = styles.StyleError
+	}
+	scheduling := styles.StyleNormal.Render("Schedulable")
+	if node.Cordoned {
+		scheduling = styles.StyleWarning.Render("Cordoned")
+	}
+
+	lines := []string{
+		v.row("Name", styles.StyleStatus.Render(node.Name)),
+		v.row("Status", statusStyle.Render(node.Status)),
+		v.row("Scheduling", scheduling),
+		v.row("Roles", styles.StyleNormal.Render(orNone(strings.Join(node.Roles, ",")))),
+		v.row("IP", styles.StyleNormal.Render(orNone(node.IP))),
+		v.row("Kubelet", styles.StyleNormal.Render(orNone(node.KubeletVersion))),
+		v.row("OS", styles.StyleNormal.Render(v.truncate(orNone(node.OSImage)))),
+		v.row("Kernel", styles.StyleNormal.Render(v.truncate(orNone(node.KernelVersion)))),
+		v.row("Runtime", styles.StyleNormal.Render(v.truncate(orNone(node.ContainerRuntime)))),
+		v.row("Uptime", styles.StyleSubtle.Render(orNone(node.Uptime))),
+		v.row("Age", styles.StyleSubtle.Render(orNone(node.Age))),
+		v.row("Ceph pods", styles.StyleNormal.Render(fmt.Sprintf("%d", node.CephPodCount))),
+		v.row("Ceph roles", styles.StyleNormal.Render(orNone(strings.Join(node.CephRoles, ",")))),
+	}
+
+	keys := make([]string, 0, len(node.MaintenanceAnnotations))
+	for key := range node.MaintenanceAnnotations {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for i, key := range keys {
+		label := ""
+		if i == 0 {
+			label = "Annotations"
+		}
+		value := fmt.Sprintf("%s=%s", key, format.SanitizeForDisplay(node.MaintenanceAnnotations[key]))
+		lines = append(lines, v.row(label, styles.StyleWarning.Render(v.truncate(value))))
+	}
+
+	if v.height > 0 && len(lines) > v.height {
+		lines = lines[:v.height]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// row renders a label and its value
+func (v *NodeDetailView) row(label, value string) string {
+	return styles.StyleSubtle.Render(format.PadRight(label, nodeDetailLabelWidth)) + value
+}
+
+// truncate shortens a value to the space next to the labels
+func (v *NodeDetailView) truncate(value string) string {
+	if v.width <= nodeDetailLabelWidth {
+		return value
+	}
+	return truncateEllipsis(value, v.width-nodeDetailLabelWidth)
+}
+
+// orNone returns s, or a placeholder when it is empty
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,51 @@
+package views
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func TestNodeDetailView_Render(t *testing.T) {
+	v := NewNodeDetailView("worker-1")
+	v.SetSize(60, 20)
+
+	if got := v.Render(); !strings.Contains(got, "Waiting for node worker-1") {
+		t.Errorf("Render() before data = %q, want waiting note", got)
+	}
+
+	v.SetNode(&k8s.NodeInfo{
+		Name:             "worker-1",
+		IP:               "10.0.0.11",
+		Status:           "Ready",
+		Roles:            []string{"worker"},
+		Cordoned:         true,
+		CephPodCount:     4,
+		Age:              "12d",
+		KubeletVersion:   "v1.31.2",
+		OSImage:          "Ubuntu 24.04.1 LTS",
+		KernelVersion:    "6.8.0-51-generic",
+		ContainerRuntime: "containerd://1.7.24",
+		Uptime:           "3h",
+		CephRoles:        []string{"mon", "osd"},
+		MaintenanceAnnotations: map[string]string{
+			"crook.io/ticket": "OPS-12",
+			"crook.io/reason": "kernel upgrade",
+		},
+	})
+	got := v.Render()
+	for _, want := range []string{"worker-1", "10.0.0.11", "Cordoned", "v1.31.2", "Ubuntu 24.04.1 LTS", "6.8.0-51-generic", "containerd://1.7.24", "3h", "mon,osd", "crook.io/reason=kernel upgrade", "crook.io/ticket=OPS-12"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "crook.io/reason") > strings.Index(got, "crook.io/ticket") {
+		t.Error("annotations should be sorted by key")
+	}
+
+	v.SetSize(60, 3)
+	if lines := strings.Count(v.Render(), "\n") + 1; lines != 3 {
+		t.Errorf("Render() at height 3 has %d lines", lines)
+	}
+}
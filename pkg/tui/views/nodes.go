@@ -8,6 +8,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/monitoring"
 	"github.com/andri/crook/pkg/tui/format"
 	"github.com/andri/crook/pkg/tui/styles"
 )
@@ -25,6 +26,12 @@ type NodesView struct {
 
 	// height is the terminal height
 	height int
+
+	// flash highlights rows that changed in recent refreshes
+	flash rowFlash
+
+	// related highlights rows related to the selection in another pane
+	related relatedRows
 }
 
 type nodesColumnLayout struct {
@@ -260,6 +267,10 @@ func (v *NodesView) renderRow(node k8s.NodeInfo, selected bool) string {
 			Bold(true).
 			Foreground(styles.ColorHighlight).
 			Background(styles.ColorPrimary)
+	} else if v.flash.active(monitoring.NodeKey(node)) {
+		nameStyle = styles.StyleChanged
+	} else if v.related[monitoring.NodeKey(node)] {
+		nameStyle = styles.StyleRelated
 	} else {
 		nameStyle = styles.StyleNormal
 	}
@@ -363,16 +374,23 @@ func (v *NodesView) getTableWidth() int {
 	return width + max(0, cols-1)
 }
 
-// SetNodes updates the nodes list
+// SetNodes updates the nodes list, keeping the selection on the same node
 func (v *NodesView) SetNodes(nodes []k8s.NodeInfo) {
+	selected := selectedKey(v.nodes, v.cursor, monitoring.NodeKey)
 	v.nodes = nodes
-	// Reset cursor if out of bounds
-	if v.cursor >= len(v.nodes) {
-		v.cursor = len(v.nodes) - 1
-	}
-	if v.cursor < 0 {
-		v.cursor = 0
-	}
+	// Keep the selected node under the cursor, wherever it moved
+	v.cursor = restoreCursor(v.nodes, v.cursor, selected, monitoring.NodeKey)
+}
+
+// MarkChanged highlights the rows with the given identities (see monitoring.LsChanges)
+func (v *NodesView) MarkChanged(keys []string) {
+	v.flash.mark(keys)
+}
+
+// SetRelated highlights the rows with the given identities as related to
+// the selection in another pane; nil clears the highlight
+func (v *NodesView) SetRelated(keys []string) {
+	v.related = newRelatedRows(keys)
 }
 
 // SetSize sets the view dimensions
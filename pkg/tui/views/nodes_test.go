@@ -278,3 +278,25 @@ func TestNodesView_GetSelectedNode(t *testing.T) {
 		t.Errorf("selected node = %s, want node-2", selected.Name)
 	}
 }
+
+func TestNodesView_SetRelated(t *testing.T) {
+	v := NewNodesView()
+	v.SetSize(100, 20)
+	node := k8s.NodeInfo{Name: "node-2", Status: "Ready"}
+	v.SetNodes([]k8s.NodeInfo{{Name: "node-1", Status: "Ready"}, node})
+
+	plain := v.renderRow(node, false)
+	selected := v.renderRow(node, true)
+	v.SetRelated([]string{"node-2"})
+	if v.renderRow(node, false) == plain {
+		t.Error("related row should be rendered differently")
+	}
+	if v.renderRow(node, true) != selected {
+		t.Error("the selected row style should win over the related highlight")
+	}
+
+	v.SetRelated(nil)
+	if v.renderRow(node, false) != plain {
+		t.Error("SetRelated(nil) should clear the highlight")
+	}
+}
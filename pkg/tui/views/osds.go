@@ -7,15 +7,22 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/monitoring"
 	"github.com/andri/crook/pkg/tui/format"
 	"github.com/andri/crook/pkg/tui/styles"
 )
 
 // OSDsView displays Ceph OSD status from ceph osd tree
 type OSDsView struct {
-	// osds is the list of OSDs to display
+	// osds is the list of OSDs to display (may be filtered by node)
 	osds []k8s.OSDInfo
 
+	// allOSDs stores all OSDs before node filtering
+	allOSDs []k8s.OSDInfo
+
+	// nodeFilter filters OSDs to those on a specific host
+	nodeFilter string
+
 	// cursor is the currently selected row
 	cursor int
 
@@ -27,6 +34,12 @@ type OSDsView struct {
 
 	// height is the terminal height
 	height int
+
+	// flash highlights rows that changed in recent refreshes
+	flash rowFlash
+
+	// related highlights rows related to the selection in another pane
+	related relatedRows
 }
 
 // NewOSDsView creates a new OSDs view
@@ -150,6 +163,7 @@ func (v *OSDsView) renderHeader() string {
 		format.PadRight("IN/OUT", 8),
 		format.PadRight("WEIGHT", 10),
 		format.PadRight("CLASS", 8),
+		format.PadRight("ISSUES", 10),
 		format.PadRight("DEPLOYMENT", 30),
 	}
 
@@ -165,6 +179,10 @@ func (v *OSDsView) renderRow(osd k8s.OSDInfo, selected bool) string {
 			Bold(true).
 			Foreground(styles.ColorHighlight).
 			Background(styles.ColorPrimary)
+	} else if v.flash.active(monitoring.OSDKey(osd)) {
+		nameStyle = styles.StyleChanged
+	} else if v.related[monitoring.OSDKey(osd)] {
+		nameStyle = styles.StyleRelated
 	} else {
 		nameStyle = styles.StyleNormal
 	}
@@ -183,8 +201,19 @@ func (v *OSDsView) renderRow(osd k8s.OSDInfo, selected bool) string {
 		inOutStyle = styles.StyleError
 	}
 
-	// Highlight entire row if OSD is down or out
-	rowWarning := osd.Status == "down" || osd.InOut == "out"
+	// Slow ops often point at the node being maintained; slow heartbeats at its network
+	issue := osd.HealthIssue()
+	issueStyle := styles.StyleWarning
+	if osd.SlowOps {
+		issueStyle = styles.StyleError
+	}
+	if issue == "" {
+		issue = "-"
+		issueStyle = styles.StyleSubtle
+	}
+
+	// Highlight entire row if OSD is down, out, or has slow ops or heartbeats
+	rowWarning := osd.Status == "down" || osd.InOut == "out" || issue != "-"
 
 	// Weight formatting
 	weightStr := fmt.Sprintf("%.3f", osd.Weight)
@@ -204,6 +233,7 @@ func (v *OSDsView) renderRow(osd k8s.OSDInfo, selected bool) string {
 		inOutStyle.Render(format.PadRight(osd.InOut, 8)),
 		styles.StyleSubtle.Render(format.PadRight(weightStr, 10)),
 		styles.StyleSubtle.Render(format.PadRight(osd.DeviceClass, 8)),
+		issueStyle.Render(format.PadRight(issue, 10)),
 		v.renderWithWarning(format.PadRight(deploymentName, 30), rowWarning, selected),
 	}
 
@@ -226,19 +256,53 @@ func (v *OSDsView) renderWithWarning(s string, warning, selected bool) string {
 
 // getTableWidth returns the total table width
 func (v *OSDsView) getTableWidth() int {
-	return 10 + 20 + 8 + 8 + 10 + 8 + 30 + 6 // column widths + spacing
+	return 10 + 20 + 8 + 8 + 10 + 8 + 10 + 30 + 7 // column widths + spacing
 }
 
-// SetOSDs updates the OSDs list
+// SetOSDs updates the OSDs list, keeping the selection on the same OSD
 func (v *OSDsView) SetOSDs(osds []k8s.OSDInfo) {
-	v.osds = osds
-	// Reset cursor if out of bounds
-	if v.cursor >= len(v.osds) {
-		v.cursor = len(v.osds) - 1
-	}
-	if v.cursor < 0 {
-		v.cursor = 0
+	v.allOSDs = osds
+	v.applyNodeFilter()
+}
+
+// SetRelated highlights the rows with the given identities as related to
+// the selection in another pane; nil clears the highlight
+func (v *OSDsView) SetRelated(keys []string) {
+	v.related = newRelatedRows(keys)
+}
+
+// SetNodeFilter sets the node filter for filtering OSDs by host
+func (v *OSDsView) SetNodeFilter(nodeFilter string) {
+	v.nodeFilter = nodeFilter
+	v.applyNodeFilter()
+}
+
+// applyNodeFilter filters OSDs based on the node filter
+func (v *OSDsView) applyNodeFilter() {
+	selected := selectedKey(v.osds, v.cursor, monitoring.OSDKey)
+	if v.nodeFilter == "" {
+		v.osds = v.allOSDs
+	} else {
+		v.osds = make([]k8s.OSDInfo, 0, len(v.allOSDs))
+		for _, osd := range v.allOSDs {
+			if osd.Hostname == v.nodeFilter {
+				v.osds = append(v.osds, osd)
+			}
+		}
 	}
+
+	// Keep the selected OSD under the cursor, wherever it moved
+	v.cursor = restoreCursor(v.osds, v.cursor, selected, monitoring.OSDKey)
+}
+
+// GetNodeFilter returns the current node filter
+func (v *OSDsView) GetNodeFilter() string {
+	return v.nodeFilter
+}
+
+// MarkChanged highlights the rows with the given identities (see monitoring.LsChanges)
+func (v *OSDsView) MarkChanged(keys []string) {
+	v.flash.mark(keys)
 }
 
 // SetNooutFlag sets the noout flag status
@@ -293,6 +357,17 @@ func (v *OSDsView) CountDown() int {
 	return count
 }
 
+// CountSlow returns the number of OSDs with slow ops or slow heartbeats
+func (v *OSDsView) CountSlow() int {
+	count := 0
+	for _, osd := range v.osds {
+		if osd.HealthIssue() != "" {
+			count++
+		}
+	}
+	return count
+}
+
 // CountOut returns the number of OSDs with status "out"
 func (v *OSDsView) CountOut() int {
 	count := 0
@@ -251,3 +251,49 @@ func TestOSDsView_CountDownOut(t *testing.T) {
 		t.Errorf("CountOut() = %d, want 2", v.CountOut())
 	}
 }
+
+func TestOSDsView_HealthIssues(t *testing.T) {
+	v := NewOSDsView()
+	v.SetSize(140, 30)
+
+	osds := []k8s.OSDInfo{
+		{ID: 0, Name: "osd.0", Status: "up", InOut: "in"},
+		{ID: 1, Name: "osd.1", Status: "up", InOut: "in", SlowOps: true, SlowHeartbeatMS: 2143.8},
+		{ID: 2, Name: "osd.2", Status: "up", InOut: "in", SlowHeartbeatMS: 1520.0},
+	}
+	v.SetOSDs(osds)
+
+	if v.CountSlow() != 2 {
+		t.Errorf("CountSlow() = %d, want 2", v.CountSlow())
+	}
+
+	output := v.Render()
+	for _, want := range []string{"ISSUES", "slow ops", "hb 1520ms"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q", want)
+		}
+	}
+}
+
+func TestOSDsView_SetNodeFilter(t *testing.T) {
+	v := NewOSDsView()
+	v.SetOSDs([]k8s.OSDInfo{
+		{ID: 0, Hostname: "node1"},
+		{ID: 1, Hostname: "node2"},
+		{ID: 2, Hostname: "node2"},
+	})
+	v.SetCursor(2)
+
+	v.SetNodeFilter("node2")
+	if v.Count() != 2 {
+		t.Errorf("filtered Count() = %d, want 2", v.Count())
+	}
+	if osd := v.GetSelectedOSD(); osd == nil || osd.ID != 2 {
+		t.Errorf("selected OSD = %+v, want osd.2 to stay selected", osd)
+	}
+
+	v.SetNodeFilter("")
+	if v.Count() != 3 || v.GetNodeFilter() != "" {
+		t.Errorf("unfiltered Count() = %d, filter %q, want all 3 OSDs", v.Count(), v.GetNodeFilter())
+	}
+}
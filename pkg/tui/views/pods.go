@@ -7,6 +7,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/monitoring"
 	"github.com/andri/crook/pkg/tui/format"
 	"github.com/andri/crook/pkg/tui/styles"
 )
@@ -30,6 +31,9 @@ type PodsView struct {
 
 	// height is the terminal height
 	height int
+
+	// flash highlights rows that changed in recent refreshes
+	flash rowFlash
 }
 
 // NewPodsView creates a new pods view
@@ -162,6 +166,8 @@ func (v *PodsView) renderRow(pod k8s.PodInfo, selected bool) string {
 			Bold(true).
 			Foreground(styles.ColorHighlight).
 			Background(styles.ColorPrimary)
+	} else if v.flash.active(monitoring.PodKey(pod)) {
+		nameStyle = styles.StyleChanged
 	} else {
 		nameStyle = styles.StyleNormal
 	}
@@ -255,6 +261,11 @@ func (v *PodsView) SetPods(pods []k8s.PodInfo) {
 	v.applyNodeFilter()
 }
 
+// MarkChanged highlights the rows with the given identities (see monitoring.LsChanges)
+func (v *PodsView) MarkChanged(keys []string) {
+	v.flash.mark(keys)
+}
+
 // SetNodeFilter sets the node filter for filtering pods by node
 func (v *PodsView) SetNodeFilter(nodeFilter string) {
 	v.nodeFilter = nodeFilter
@@ -263,6 +274,7 @@ func (v *PodsView) SetNodeFilter(nodeFilter string) {
 
 // applyNodeFilter filters pods based on the node filter
 func (v *PodsView) applyNodeFilter() {
+	selected := selectedKey(v.pods, v.cursor, monitoring.PodKey)
 	if v.nodeFilter == "" {
 		v.pods = v.allPods
 	} else {
@@ -274,13 +286,8 @@ func (v *PodsView) applyNodeFilter() {
 		}
 	}
 
-	// Reset cursor if out of bounds
-	if v.cursor >= len(v.pods) {
-		v.cursor = len(v.pods) - 1
-	}
-	if v.cursor < 0 {
-		v.cursor = 0
-	}
+	// Keep the selected pod under the cursor, wherever it moved
+	v.cursor = restoreCursor(v.pods, v.cursor, selected, monitoring.PodKey)
 }
 
 // SetSize sets the view dimensions
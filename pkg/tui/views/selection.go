@@ -0,0 +1,40 @@
+package views
+
+// selectedKey returns the identity of the item under the cursor, or "" when
+// the cursor is not on an item
+func selectedKey[T any](items []T, cursor int, key func(T) string) string {
+	if cursor < 0 || cursor >= len(items) {
+		return ""
+	}
+	return key(items[cursor])
+}
+
+// relatedRows holds the identities of rows related to the selection in
+// another pane; they are highlighted with styles.StyleRelated
+type relatedRows map[string]bool
+
+// newRelatedRows returns the set of the given identities
+func newRelatedRows(keys []string) relatedRows {
+	if len(keys) == 0 {
+		return nil
+	}
+	rows := make(relatedRows, len(keys))
+	for _, key := range keys {
+		rows[key] = true
+	}
+	return rows
+}
+
+// restoreCursor returns the cursor position after items were replaced or
+// re-sorted: the row with the previously selected identity when it is still
+// present, otherwise the previous position clamped to the list.
+func restoreCursor[T any](items []T, cursor int, selected string, key func(T) string) int {
+	if selected != "" {
+		for i, item := range items {
+			if key(item) == selected {
+				return i
+			}
+		}
+	}
+	return max(0, min(cursor, len(items)-1))
+}
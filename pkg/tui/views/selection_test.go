@@ -0,0 +1,98 @@
+package views
+
+import (
+	"testing"
+
+	"github.com/andri/crook/pkg/k8s"
+)
+
+func TestRestoreCursor(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	key := func(s string) string { return s }
+
+	tests := []struct {
+		name     string
+		cursor   int
+		selected string
+		items    []string
+		want     int
+	}{
+		{name: "selected item moved", cursor: 0, selected: "c", items: items, want: 2},
+		{name: "selected item removed keeps position", cursor: 1, selected: "x", items: items, want: 1},
+		{name: "position clamped", cursor: 5, selected: "", items: items, want: 2},
+		{name: "empty list", cursor: 3, selected: "a", items: nil, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := restoreCursor(tt.items, tt.cursor, tt.selected, key); got != tt.want {
+				t.Errorf("restoreCursor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestViews_KeepSelectionAcrossRefresh(t *testing.T) {
+	t.Run("nodes", func(t *testing.T) {
+		v := NewNodesView()
+		v.SetNodes([]k8s.NodeInfo{{Name: "node-a"}, {Name: "node-b"}, {Name: "node-c"}})
+		v.SetCursor(1)
+		v.SetNodes([]k8s.NodeInfo{{Name: "node-b"}, {Name: "node-c"}, {Name: "node-a"}})
+		if got := v.GetSelectedNode(); got == nil || got.Name != "node-b" {
+			t.Errorf("selected = %v, want node-b", got)
+		}
+	})
+
+	t.Run("deployments", func(t *testing.T) {
+		v := NewDeploymentsView()
+		v.SetDeployments([]k8s.DeploymentInfo{
+			{Namespace: "rook-ceph", Name: "rook-ceph-mon-a", Type: "mon"},
+			{Namespace: "rook-ceph", Name: "rook-ceph-osd-0", Type: "osd"},
+		})
+		v.SetCursor(1)
+		want := v.GetSelectedDeployment().Name
+		v.SetDeployments([]k8s.DeploymentInfo{
+			{Namespace: "rook-ceph", Name: "rook-ceph-osd-1", Type: "osd"},
+			{Namespace: "rook-ceph", Name: "rook-ceph-mon-a", Type: "mon"},
+			{Namespace: "rook-ceph", Name: "rook-ceph-osd-0", Type: "osd"},
+		})
+		if got := v.GetSelectedDeployment(); got == nil || got.Name != want {
+			t.Errorf("selected = %v, want %s", got, want)
+		}
+	})
+
+	t.Run("osds", func(t *testing.T) {
+		v := NewOSDsView()
+		v.SetOSDs([]k8s.OSDInfo{{ID: 0, Name: "osd.0"}, {ID: 1, Name: "osd.1"}})
+		v.SetCursor(1)
+		v.SetOSDs([]k8s.OSDInfo{{ID: 2, Name: "osd.2"}, {ID: 0, Name: "osd.0"}, {ID: 1, Name: "osd.1"}})
+		if got := v.GetSelectedOSD(); got == nil || got.ID != 1 {
+			t.Errorf("selected = %v, want osd.1", got)
+		}
+	})
+
+	t.Run("pods", func(t *testing.T) {
+		v := NewPodsView()
+		v.SetPods([]k8s.PodInfo{
+			{Namespace: "rook-ceph", Name: "mon-a", NodeName: "node-a"},
+			{Namespace: "rook-ceph", Name: "osd-0", NodeName: "node-b"},
+		})
+		v.SetCursor(1)
+		v.SetNodeFilter("node-b")
+		if got := v.GetSelectedPod(); got == nil || got.Name != "osd-0" {
+			t.Errorf("selected = %v, want osd-0", got)
+		}
+	})
+}
+
+func TestDeploymentsView_SetDeploymentsDoesNotMutateInput(t *testing.T) {
+	v := NewDeploymentsView()
+	input := []k8s.DeploymentInfo{
+		{Namespace: "rook-ceph", Name: "rook-ceph-mon-a", Type: "mon"},
+		{Namespace: "rook-ceph", Name: "rook-ceph-osd-0", Type: "osd"},
+	}
+	v.SetDeployments(input)
+	if input[0].Name != "rook-ceph-mon-a" {
+		t.Error("SetDeployments must not reorder the caller's slice")
+	}
+}
@@ -0,0 +1,141 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/andri/crook/pkg/tui/styles"
+)
+
+// ClusterSummary holds the aggregate counts shown in the one-line footer
+// under the ls panes. It counts everything the monitor reports, so the
+// numbers stay complete while panes are scrolled or filtered.
+type ClusterSummary struct {
+	Nodes         int
+	NodesReady    int
+	NodesCordoned int
+
+	OSDs     int
+	OSDsUp   int
+	OSDsDown int
+	OSDsOut  int
+
+	Deployments      int
+	DeploymentsReady int
+
+	Pods           int
+	PodsNotRunning int
+
+	// Flags are the active cluster-wide OSD flags
+	Flags []string
+
+	// known records which sources reported, so unknown counts are not
+	// shown as zero
+	nodesKnown, osdsKnown, deploymentsKnown, podsKnown bool
+}
+
+// SetNodes counts the nodes
+func (s *ClusterSummary) SetNodes(nodes []k8s.NodeInfo) {
+	s.Nodes, s.NodesReady, s.NodesCordoned = len(nodes), 0, 0
+	for _, node := range nodes {
+		if node.Status == "Ready" {
+			s.NodesReady++
+		}
+		if node.Cordoned {
+			s.NodesCordoned++
+		}
+	}
+	s.nodesKnown = true
+}
+
+// SetOSDs counts the OSDs
+func (s *ClusterSummary) SetOSDs(osds []k8s.OSDInfo) {
+	s.OSDs, s.OSDsUp, s.OSDsDown, s.OSDsOut = len(osds), 0, 0, 0
+	for _, osd := range osds {
+		if osd.Status == "up" {
+			s.OSDsUp++
+		} else {
+			s.OSDsDown++
+		}
+		if osd.InOut != "in" {
+			s.OSDsOut++
+		}
+	}
+	s.osdsKnown = true
+}
+
+// SetDeployments counts the deployments
+func (s *ClusterSummary) SetDeployments(deployments []k8s.DeploymentInfo) {
+	s.Deployments, s.DeploymentsReady = len(deployments), 0
+	for _, dep := range deployments {
+		if dep.Status == "Ready" {
+			s.DeploymentsReady++
+		}
+	}
+	s.deploymentsKnown = true
+}
+
+// SetPods counts the pods
+func (s *ClusterSummary) SetPods(pods []k8s.PodInfo) {
+	s.Pods, s.PodsNotRunning = len(pods), 0
+	for _, pod := range pods {
+		if pod.Status != "Running" && pod.Status != "Succeeded" {
+			s.PodsNotRunning++
+		}
+	}
+	s.podsKnown = true
+}
+
+// Render returns the summary as one line no wider than width. Sections that
+// do not fit are dropped from the end.
+func (s *ClusterSummary) Render(width int) string {
+	var sections []string
+	if s.nodesKnown {
+		sections = append(sections, "Nodes "+styles.StyleNormal.Render(fmt.Sprintf("%d", s.Nodes))+" ("+
+			countStyle(s.NodesReady < s.Nodes, styles.StyleError).Render(fmt.Sprintf("%d ready", s.NodesReady))+", "+
+			countStyle(s.NodesCordoned > 0, styles.StyleWarning).Render(fmt.Sprintf("%d cordoned", s.NodesCordoned))+")")
+	}
+	if s.osdsKnown {
+		sections = append(sections, "OSDs "+styles.StyleNormal.Render(fmt.Sprintf("%d", s.OSDs))+" ("+
+			countStyle(s.OSDsDown == 0, styles.StyleSuccess).Render(fmt.Sprintf("%d up", s.OSDsUp))+", "+
+			countStyle(s.OSDsDown > 0, styles.StyleError).Render(fmt.Sprintf("%d down", s.OSDsDown))+", "+
+			countStyle(s.OSDsOut > 0, styles.StyleWarning).Render(fmt.Sprintf("%d out", s.OSDsOut))+")")
+	}
+	if s.deploymentsKnown {
+		ready := fmt.Sprintf("%d/%d ready", s.DeploymentsReady, s.Deployments)
+		sections = append(sections, "Deployments "+
+			countStyle(s.DeploymentsReady < s.Deployments, styles.StyleWarning).Render(ready))
+	}
+	if s.podsKnown {
+		sections = append(sections, "Pods "+
+			countStyle(s.PodsNotRunning > 0, styles.StyleError).Render(fmt.Sprintf("%d not running", s.PodsNotRunning)))
+	}
+	if len(s.Flags) > 0 {
+		sections = append(sections, "Flags "+styles.StyleWarning.Render(strings.Join(s.Flags, ",")))
+	}
+
+	separator := styles.StyleSubtle.Render(" │ ")
+	line := ""
+	for _, section := range sections {
+		next := section
+		if line != "" {
+			next = line + separator + section
+		}
+		if width > 0 && lipgloss.Width(next) > width {
+			break
+		}
+		line = next
+	}
+	return line
+}
+
+// countStyle returns alert when the count needs attention, else the subtle
+// style
+func countStyle(attention bool, alert lipgloss.Style) lipgloss.Style {
+	if attention {
+		return alert
+	}
+	return styles.StyleSubtle
+}
@@ -0,0 +1,55 @@
+package views
+
+import (
+	"strings"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+	"github.com/andri/crook/pkg/k8s"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestClusterSummary(t *testing.T) {
+	var s ClusterSummary
+	if got := s.Render(200); got != "" {
+		t.Errorf("Render() before any data = %q, want empty", got)
+	}
+
+	s.SetNodes([]k8s.NodeInfo{
+		{Name: "a", Status: "Ready"},
+		{Name: "b", Status: "Ready", Cordoned: true},
+		{Name: "c", Status: "NotReady"},
+	})
+	s.SetOSDs([]k8s.OSDInfo{
+		{ID: 0, Status: "up", InOut: "in"},
+		{ID: 1, Status: "down", InOut: "in"},
+		{ID: 2, Status: "down", InOut: "out"},
+	})
+	s.SetDeployments([]k8s.DeploymentInfo{{Status: "Ready"}, {Status: "Scaling"}})
+	s.SetPods([]k8s.PodInfo{{Status: "Running"}, {Status: "Succeeded"}, {Status: "Pending"}})
+	s.Flags = []string{"noout", "norebalance"}
+
+	got := ansi.Strip(s.Render(200))
+	for _, want := range []string{
+		"Nodes 3", "2 ready", "1 cordoned",
+		"OSDs 3", "1 up", "2 down", "1 out",
+		"Deployments 1/2 ready",
+		"Pods 1 not running",
+		"Flags noout,norebalance",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() missing %q: %s", want, got)
+		}
+	}
+	if strings.Contains(got, "\n") {
+		t.Error("Render() should return a single line")
+	}
+
+	narrow := ansi.Strip(s.Render(40))
+	if w := lipgloss.Width(narrow); w > 40 {
+		t.Errorf("Render(40) width = %d", w)
+	}
+	if !strings.Contains(narrow, "Nodes 3") || strings.Contains(narrow, "Flags") {
+		t.Errorf("Render(40) should keep the first sections and drop the last: %s", narrow)
+	}
+}
@@ -0,0 +1,128 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andri/crook/cmd/crook/commands"
+	"github.com/andri/crook/internal/faults"
+	"github.com/andri/crook/pkg/maintenance"
+)
+
+// runCrook runs a crook command in-process and returns its output
+func runCrook(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := commands.NewRootCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(crookArgs(args...))
+	err := cmd.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+// readSummary reads a --summary-path file
+func readSummary(t *testing.T, path string) maintenance.FlowSummary {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read summary: %v", err)
+	}
+	var summary maintenance.FlowSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("failed to parse summary: %v", err)
+	}
+	return summary
+}
+
+// bringUp runs the up phase and waits for the daemons to be ready again
+func bringUp(t *testing.T) {
+	t.Helper()
+	args := []string{"up", nodeName, "--yes", "--wait"}
+	if out, err := runCrook(t, args...); err != nil {
+		t.Fatalf("crook %s error = %v\n%s", joinArgs(args), err, out)
+	}
+	for _, name := range []string{"rook-ceph-operator", "rook-ceph-mon-a", "rook-ceph-osd-0"} {
+		if err := waitForReplicas(context.Background(), name, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDownUp(t *testing.T) {
+	summaryPath := filepath.Join(t.TempDir(), "down.json")
+
+	args := []string{"down", nodeName, "--yes", "--summary-path", summaryPath}
+	if out, err := runCrook(t, args...); err != nil {
+		t.Fatalf("crook %s error = %v\n%s", joinArgs(args), err, out)
+	}
+
+	if !cordoned(t) {
+		t.Error("node should be cordoned after the down phase")
+	}
+	if !nooutSet(t) {
+		t.Error("noout should be set after the down phase")
+	}
+	for _, name := range []string{"rook-ceph-operator", "rook-ceph-mon-a", "rook-ceph-osd-0"} {
+		if got := replicas(t, name); got != 0 {
+			t.Errorf("%s replicas = %d, want 0", name, got)
+		}
+	}
+	if got := replicas(t, "rook-ceph-tools"); got != 1 {
+		t.Errorf("the toolbox is not pinned to the node and should keep running, replicas = %d", got)
+	}
+	if summary := readSummary(t, summaryPath); summary.Outcome != maintenance.OutcomeSucceeded {
+		t.Errorf("summary outcome = %q, want %q", summary.Outcome, maintenance.OutcomeSucceeded)
+	}
+
+	// A second down phase finds the node prepared and changes nothing
+	_, err := runCrook(t, "down", nodeName, "--yes")
+	if got := commands.ExitCode(err); got != commands.ExitCodeAlreadyInState {
+		t.Errorf("repeated down exit code = %d, want %d (error: %v)", got, commands.ExitCodeAlreadyInState, err)
+	}
+
+	bringUp(t)
+
+	if cordoned(t) {
+		t.Error("node should be uncordoned after the up phase")
+	}
+	if nooutSet(t) {
+		t.Error("noout should be unset after the up phase")
+	}
+	for _, name := range []string{"rook-ceph-operator", "rook-ceph-mon-a", "rook-ceph-osd-0"} {
+		if got := replicas(t, name); got != 1 {
+			t.Errorf("%s replicas = %d, want 1", name, got)
+		}
+	}
+}
+
+func TestDown_InjectedScaleFailure(t *testing.T) {
+	if !faults.Enabled {
+		t.Skip("requires -tags faults")
+	}
+	if err := faults.Activate("k8s:update:deployments/scale=error*1"); err != nil {
+		t.Fatalf("Activate() error = %v", err)
+	}
+	t.Cleanup(func() { _ = faults.Activate("") })
+
+	// Scaling the operator fails once the node is cordoned and noout is set
+	_, err := runCrook(t, "down", nodeName, "--yes")
+	if err == nil {
+		t.Fatal("down phase should fail when scaling fails")
+	}
+	if !cordoned(t) || !nooutSet(t) {
+		t.Error("the steps before the failure should have been applied")
+	}
+
+	// The rule fired once; the up phase restores the node
+	bringUp(t)
+	if cordoned(t) || nooutSet(t) {
+		t.Error("the up phase should recover from a failed down phase")
+	}
+}
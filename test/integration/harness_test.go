@@ -0,0 +1,253 @@
+//go:build integration
+
+// Package integration runs crook's headless down and up flows against a real
+// API server, e.g. a kind cluster created with testdata/kind.yaml (just e2e).
+//
+// The suite installs a minimal fake Rook into a fresh namespace: the operator,
+// one MON and one OSD deployment pinned to the target node, and a toolbox pod
+// whose ceph CLI (testdata/ceph.sh) tracks OSD flags and answers as a healthy
+// cluster. Point KUBECONFIG at the cluster and run
+//
+//	go test -tags integration ./test/integration/...
+//
+// CROOK_E2E_NODE selects the node to take down (default: the first worker),
+// and CROOK_E2E_KEEP=1 keeps the namespace for inspection.
+package integration
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andri/crook/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+)
+
+// Environment variables of the suite
+const (
+	envNode = "CROOK_E2E_NODE"
+	envKeep = "CROOK_E2E_KEEP"
+)
+
+// Images of the fake Rook daemons and toolbox
+const (
+	pauseImage   = "registry.k8s.io/pause:3.10"
+	toolboxImage = "busybox:1.37"
+)
+
+// readyTimeout bounds the wait for the fake Rook daemons to become ready
+const readyTimeout = 3 * time.Minute
+
+//go:embed testdata/ceph.sh
+var fakeCephScript string
+
+// The cluster the suite runs against, set up by TestMain
+var (
+	client    *k8s.Client
+	namespace string
+	nodeName  string
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	ctx := context.Background()
+
+	var err error
+	client, err = k8s.NewClient(ctx, k8s.ClientConfig{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: no cluster to test against: %v\n", err)
+		return 1
+	}
+	if nodeName, err = targetNode(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: %v\n", err)
+		return 1
+	}
+
+	namespace = "crook-e2e-" + rand.String(5)
+	defer func() {
+		if os.Getenv(envKeep) != "" {
+			fmt.Fprintf(os.Stderr, "e2e: keeping namespace %s\n", namespace)
+			return
+		}
+		_ = client.Clientset.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+		// A failed test may leave the node cordoned
+		_ = client.UncordonNode(ctx, nodeName)
+	}()
+
+	if err := installFakeRook(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: failed to install fake Rook: %v\n", err)
+		return 1
+	}
+	return m.Run()
+}
+
+// targetNode returns the node the suite takes down
+func targetNode(ctx context.Context) (string, error) {
+	if name := os.Getenv(envNode); name != "" {
+		return name, nil
+	}
+	nodes, err := client.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for _, node := range nodes.Items {
+		if _, controlPlane := node.Labels["node-role.kubernetes.io/control-plane"]; !controlPlane {
+			return node.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no worker node found; set %s", envNode)
+}
+
+// installFakeRook creates the namespace, the fake Rook daemons and the
+// toolbox, and waits until they are ready
+func installFakeRook(ctx context.Context) error {
+	_, err := client.Clientset.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create namespace: %w", err)
+	}
+
+	_, err = client.Clientset.CoreV1().ConfigMaps(namespace).Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "fake-ceph"},
+		Data:       map[string]string{"ceph": fakeCephScript},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create fake ceph script: %w", err)
+	}
+
+	deployments := []*appsv1.Deployment{
+		daemonDeployment("rook-ceph-operator", "rook-ceph-operator", ""),
+		daemonDeployment("rook-ceph-mon-a", "rook-ceph-mon", nodeName),
+		daemonDeployment("rook-ceph-osd-0", "rook-ceph-osd", nodeName),
+		toolboxDeployment(),
+	}
+	for _, dep := range deployments {
+		if _, err := client.Clientset.AppsV1().Deployments(namespace).Create(ctx, dep, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create deployment %s: %w", dep.Name, err)
+		}
+	}
+	for _, dep := range deployments {
+		if err := waitForReplicas(ctx, dep.Name, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// daemonDeployment returns a Rook daemon stand-in running the pause image,
+// pinned to node by nodeSelector like Rook pins OSDs and MONs
+func daemonDeployment(name, app, node string) *appsv1.Deployment {
+	dep := deployment(name, app, corev1.Container{Name: "daemon", Image: pauseImage})
+	if node != "" {
+		dep.Spec.Template.Spec.NodeSelector = map[string]string{"kubernetes.io/hostname": node}
+	}
+	return dep
+}
+
+// toolboxDeployment returns the rook-ceph-tools stand-in with the fake ceph CLI
+func toolboxDeployment() *appsv1.Deployment {
+	dep := deployment("rook-ceph-tools", "rook-ceph-tools", corev1.Container{
+		Name:    "rook-ceph-tools",
+		Image:   toolboxImage,
+		Command: []string{"sleep", "infinity"},
+		Env:     []corev1.EnvVar{{Name: "NODE_NAME", Value: nodeName}},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "fake-ceph", MountPath: "/usr/local/bin"},
+		},
+	})
+	dep.Spec.Template.Spec.Volumes = []corev1.Volume{{
+		Name: "fake-ceph",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "fake-ceph"},
+			DefaultMode:          ptr.To[int32](0o755),
+		}},
+	}}
+	return dep
+}
+
+func deployment(name, app string, container corev1.Container) *appsv1.Deployment {
+	labels := map[string]string{"app": app}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers:                    []corev1.Container{container},
+					TerminationGracePeriodSeconds: ptr.To[int64](0),
+				},
+			},
+		},
+	}
+}
+
+// waitForReplicas waits until the deployment has the given number of ready
+// replicas
+func waitForReplicas(ctx context.Context, name string, replicas int32) error {
+	err := wait.PollUntilContextTimeout(ctx, time.Second, readyTimeout, true, func(ctx context.Context) (bool, error) {
+		dep, err := client.Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return dep.Status.ReadyReplicas == replicas, nil
+	})
+	if err != nil {
+		return fmt.Errorf("deployment %s did not reach %d ready replicas: %w", name, replicas, err)
+	}
+	return nil
+}
+
+// replicas returns the desired replicas of a deployment
+func replicas(t *testing.T, name string) int32 {
+	t.Helper()
+	dep, err := client.Clientset.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment %s: %v", name, err)
+	}
+	return ptr.Deref(dep.Spec.Replicas, 1)
+}
+
+// cordoned reports whether the target node is unschedulable
+func cordoned(t *testing.T) bool {
+	t.Helper()
+	node, err := client.Clientset.CoreV1().Nodes().Get(context.Background(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get node %s: %v", nodeName, err)
+	}
+	return node.Spec.Unschedulable
+}
+
+// nooutSet reports whether the fake cluster has the noout flag set
+func nooutSet(t *testing.T) bool {
+	t.Helper()
+	flags, err := client.GetCephFlags(context.Background(), namespace)
+	if err != nil {
+		t.Fatalf("failed to get ceph flags: %v", err)
+	}
+	return flags.NoOut
+}
+
+// crookArgs returns the global flags every crook invocation of the suite uses
+func crookArgs(args ...string) []string {
+	return append(args, "--namespace", namespace, "--log-level", "error")
+}
+
+// joinArgs formats arguments for failure messages
+func joinArgs(args []string) string {
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,59 @@
+#!/bin/sh
+# Fake ceph CLI of the e2e toolbox pod. It keeps the OSD flags set with
+# 'ceph osd set|unset' in files and answers the queries crook makes as a
+# healthy one-mon, one-OSD cluster (HEALTH_WARN while a flag is set).
+state=/tmp/ceph-flags
+mkdir -p "$state"
+
+args=""
+for arg in "$@"; do
+	case "$arg" in
+	--format | json) ;;
+	*) args="$args $arg" ;;
+	esac
+done
+# shellcheck disable=SC2086
+set -- $args
+
+flags=$(ls "$state" | paste -sd, -)
+health=HEALTH_OK
+checks='{}'
+if [ -n "$flags" ]; then
+	health=HEALTH_WARN
+	checks='{"OSDMAP_FLAGS":{"severity":"HEALTH_WARN","summary":{"message":"'"$flags"' flag(s) set"}}}'
+fi
+
+case "$*" in
+"osd set "*)
+	touch "$state/$3"
+	echo "$3 is set" >&2
+	;;
+"osd unset "*)
+	rm -f "$state/$3"
+	echo "$3 is unset" >&2
+	;;
+"osd dump")
+	echo '{"epoch":1,"flags":"'"$flags"'","stretch_mode":{"stretch_mode_enabled":false}}'
+	;;
+"status")
+	echo '{"health":{"status":"'"$health"'","checks":'"$checks"'},"osdmap":{"num_osds":1,"num_up_osds":1,"num_in_osds":1},"pgmap":{"num_pgs":1,"pgs_by_state":[{"state_name":"active+clean","count":1}]}}'
+	;;
+"health detail")
+	echo '{"status":"'"$health"'","checks":'"$checks"'}'
+	;;
+"quorum_status")
+	echo '{"election_epoch":1,"quorum_names":["a"],"quorum_leader_name":"a","monmap":{"num_mons":1,"mons":[{"rank":0,"name":"a"}]}}'
+	;;
+"osd tree")
+	echo '{"nodes":[{"id":-1,"name":"default","type":"root","children":[-2]},{"id":-2,"name":"'"$NODE_NAME"'","type":"host","children":[0]},{"id":0,"name":"osd.0","type":"osd","status":"up","reweight":1,"crush_weight":1}]}'
+	;;
+"df")
+	echo '{"stats":{"total_bytes":10737418240,"total_used_raw_bytes":1073741824,"total_avail_bytes":9663676416},"pools":[]}'
+	;;
+"osd pool ls detail" | "osd crush rule dump" | "osd pool autoscale-status" | "log last"*)
+	echo '[]'
+	;;
+*)
+	echo '{}'
+	;;
+esac
@@ -0,0 +1,127 @@
+// Package fakeclock provides a manually advanced clock.Clock for tests
+package fakeclock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/andri/crook/internal/clock"
+)
+
+// Fake is a manually advanced clock for tests. Time only moves on Advance,
+// which fires the timers that became due.
+type Fake struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// New returns a fake clock set to now
+func New(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Now returns the fake time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since returns the fake time elapsed since t
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// Until returns the fake duration until t
+func (f *Fake) Until(t time.Time) time.Duration {
+	return t.Sub(f.Now())
+}
+
+// NewTimer creates a timer that fires when the clock is advanced by d
+func (f *Fake) NewTimer(d time.Duration) clock.Timer {
+	t := &fakeTimer{clock: f, c: make(chan time.Time, 1)}
+	f.mu.Lock()
+	f.timers = append(f.timers, t)
+	f.mu.Unlock()
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the clock forward by d and fires every timer that became due
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	for _, t := range f.timers {
+		if t.armed && !t.deadline.After(f.now) {
+			t.fireLocked()
+		}
+	}
+}
+
+// BlockUntil waits until n timers are armed, so a test can advance the clock
+// only after the code under test started waiting on it
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for f.armedLocked() < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) armedLocked() int {
+	armed := 0
+	for _, t := range f.timers {
+		if t.armed {
+			armed++
+		}
+	}
+	return armed
+}
+
+type fakeTimer struct {
+	clock    *Fake
+	c        chan time.Time
+	deadline time.Time
+	armed    bool
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	wasArmed := t.armed
+	t.deadline = t.clock.now.Add(d)
+	t.armed = true
+	if d <= 0 {
+		t.fireLocked()
+	}
+	t.clock.cond.Broadcast()
+	return wasArmed
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	wasArmed := t.armed
+	t.armed = false
+	t.clock.cond.Broadcast()
+	return wasArmed
+}
+
+// fireLocked disarms the timer and delivers the current time; like a real
+// timer, a pending unread value is kept rather than queueing a second one
+func (t *fakeTimer) fireLocked() {
+	t.armed = false
+	select {
+	case t.c <- t.clock.now:
+	default:
+	}
+}
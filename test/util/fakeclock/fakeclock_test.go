@@ -0,0 +1,60 @@
+package fakeclock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake_Advance(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := New(start)
+
+	f.Advance(90 * time.Second)
+	if got := f.Since(start); got != 90*time.Second {
+		t.Errorf("Since() = %v, want 90s", got)
+	}
+	if got := f.Until(start.Add(2 * time.Minute)); got != 30*time.Second {
+		t.Errorf("Until() = %v, want 30s", got)
+	}
+}
+
+func TestFake_Timer(t *testing.T) {
+	f := New(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	timer := f.NewTimer(time.Second)
+	f.BlockUntil(1)
+
+	f.Advance(999 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired early")
+	default:
+	}
+
+	f.Advance(time.Millisecond)
+	select {
+	case <-timer.C():
+	default:
+		t.Fatal("timer did not fire when due")
+	}
+
+	// A fired timer stays quiet until it is reset
+	f.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Fatal("fired timer fired again")
+	default:
+	}
+
+	if timer.Reset(time.Second) {
+		t.Error("Reset() of a fired timer should report it was not armed")
+	}
+	if !timer.Stop() {
+		t.Error("Stop() of an armed timer should report it was armed")
+	}
+	f.Advance(time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("stopped timer fired")
+	default:
+	}
+}